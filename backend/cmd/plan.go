@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"backend/internal/plandoc"
+	"backend/internal/plantext"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Plan converts and checks plan files without a database. Text plans end in .txt and
+// everything else is read as a JSON plan document.
+//
+//	plan fmt <file>    print the plan in the text format
+//	plan json <file>   print the plan as a JSON plan document
+//	plan check <file>  report the first problem in the plan, if any
+func Plan(args []string) {
+	if len(args) != 2 {
+		fmt.Println("usage: plan fmt|json|check <file>")
+		os.Exit(1)
+	}
+
+	doc, err := readPlanFile(args[1])
+	if err != nil {
+		fmt.Printf("%s: %v\n", args[1], err)
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "fmt":
+		err = plantext.Write(os.Stdout, doc)
+	case "json":
+		err = plandoc.Write(os.Stdout, doc)
+	case "check":
+		fmt.Printf("%s: ok, %d intervals\n", args[1], len(doc.Intervals))
+	default:
+		fmt.Println("usage: plan fmt|json|check <file>")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Println("error writing plan:", err)
+		os.Exit(1)
+	}
+}
+
+func readPlanFile(path string) (*plandoc.Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(strings.ToLower(path), ".txt") {
+		return plantext.Parse(file)
+	}
+
+	doc, err := plandoc.Parse(file)
+	if err != nil {
+		return nil, err
+	}
+	if errs := plandoc.Validate(doc); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return doc, nil
+}
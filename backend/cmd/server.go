@@ -6,7 +6,9 @@ import (
 
 	"backend/db"
 	"backend/internal/api"
+	"backend/internal/auth"
 	"backend/internal/config"
+	"backend/internal/storage"
 
 	"github.com/rs/cors"
 )
@@ -17,6 +19,9 @@ func Server() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if cfg.Environment != "development" && cfg.JWTSecret == config.DefaultJWTSecret {
+		log.Fatalf("JWT_SECRET must be set outside development")
+	}
 
 	db, err := db.Initialize(cfg.DatabaseURL)
 	if err != nil {
@@ -24,8 +29,14 @@ func Server() {
 	}
 	defer db.Close()
 
+	store, err := storage.New(cfg.Storage, cfg.PublicURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
 	// Create router
-	router := api.NewRouter(db)
+	// Bearer tokens name the user each request is made on behalf of
+	router := api.NewRouter(db, store, auth.NewTokens(cfg.JWTSecret))
 
 	// Start server
 	log.Printf("Starting server on port %s", cfg.Port)
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"backend/internal/auth"
+	"backend/internal/config"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Token prints a bearer token that acts on behalf of the user until it expires. The app
+// stores one opened with ?token=<token>; API clients send it as "Authorization: Bearer".
+func Token(userId int64, expiry time.Duration) {
+	if userId <= 0 {
+		log.Fatalf("usage: token -user <id> [-expiry 720h]")
+	}
+	if expiry <= 0 {
+		log.Fatalf("expiry must be positive, got %s", expiry)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Environment != "development" && cfg.JWTSecret == config.DefaultJWTSecret {
+		log.Fatalf("JWT_SECRET must be set outside development")
+	}
+
+	token, err := auth.NewTokens(cfg.JWTSecret).Issue(userId, expiry)
+	if err != nil {
+		log.Fatalf("Failed to issue token: %v", err)
+	}
+	fmt.Println(token)
+}
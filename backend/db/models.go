@@ -8,6 +8,24 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Benchmark struct {
+	ID                  int64
+	UserID              int64
+	ExerciseVariationID pgtype.Int8
+	ParameterTypeID     int64
+	Value               float64
+	RecordedOn          pgtype.Date
+	CreatedAt           pgtype.Timestamp
+	UpdatedAt           pgtype.Timestamp
+}
+
+type CalendarFeed struct {
+	ID        int64
+	UserID    int64
+	Token     string
+	CreatedAt pgtype.Timestamp
+}
+
 type Category struct {
 	ID        int64
 	Name      string
@@ -18,12 +36,32 @@ type Category struct {
 }
 
 type Exercise struct {
-	ID          int64
-	Name        string
-	Description string
-	UserID      pgtype.Int8
-	CreatedAt   pgtype.Timestamp
-	UpdatedAt   pgtype.Timestamp
+	ID                    int64
+	Name                  string
+	Description           string
+	UserID                pgtype.Int8
+	CreatedAt             pgtype.Timestamp
+	UpdatedAt             pgtype.Timestamp
+	PrimaryMuscleGroups   []string
+	SecondaryMuscleGroups []string
+	Equipment             []string
+	Laterality            string
+}
+
+type ExerciseCategory struct {
+	ExerciseID int64
+	CategoryID int64
+}
+
+type ExerciseMedium struct {
+	ID           int64
+	ExerciseID   int64
+	FileName     string
+	ContentType  string
+	SizeBytes    int64
+	StorageKey   string
+	ThumbnailKey pgtype.Text
+	CreatedAt    pgtype.Timestamp
 }
 
 type ExerciseVariation struct {
@@ -61,6 +99,8 @@ type IntervalExercisePrescription struct {
 	SubRepWorkDuration  pgtype.Interval
 	SubRepRestDuration  pgtype.Interval
 	Rest                pgtype.Interval
+	Position            int32
+	BlockID             pgtype.Int8
 }
 
 type IntervalGroupAssignment struct {
@@ -68,6 +108,25 @@ type IntervalGroupAssignment struct {
 	PlanIntervalID int64
 	GroupID        int64
 	Frequency      int32
+	PinnedDays     []int32
+	Order          int32
+}
+
+type LogbookEntry struct {
+	ID                 int64
+	UserID             int64
+	ScheduledSessionID pgtype.Int8
+	Name               string
+	Discipline         string
+	GradeSystem        string
+	Grade              string
+	GradeIndex         int32
+	Style              string
+	Attempts           int32
+	Outdoor            bool
+	ClimbedOn          pgtype.Date
+	CreatedAt          pgtype.Timestamp
+	UpdatedAt          pgtype.Timestamp
 }
 
 type ParameterType struct {
@@ -79,17 +138,75 @@ type ParameterType struct {
 	MaxValue    pgtype.Float8
 }
 
+type PersonalRecord struct {
+	ID                  int64
+	UserID              int64
+	ExerciseVariationID int64
+	ParameterTypeID     int64
+	Kind                string
+	Value               float64
+	PreviousValue       pgtype.Float8
+	Load                pgtype.Float8
+	Reps                pgtype.Int4
+	SessionSetID        pgtype.Int8
+	AchievedOn          pgtype.Date
+	CreatedAt           pgtype.Timestamp
+}
+
 type Plan struct {
-	ID          int64
-	Name        string
-	Description string
+	ID           int64
+	Name         string
+	Description  string
+	UserID       int64
+	IsTemplate   bool
+	IsPublic     bool
+	StartDate    pgtype.Date
+	SourcePlanID pgtype.Int8
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+}
+
+type PlanAssignment struct {
+	ID           int64
+	PlanID       int64
+	UserID       int64
+	StartDate    pgtype.Date
+	TrainingDays []int32
+	MinRestDays  int32
+	CreatedAt    pgtype.Timestamp
+	UpdatedAt    pgtype.Timestamp
+}
+
+type PlanCollaborator struct {
+	PlanID      int64
 	UserID      int64
-	IsTemplate  bool
-	IsPublic    bool
+	Permission  string
+	ShareLinkID pgtype.Int8
 	CreatedAt   pgtype.Timestamp
 	UpdatedAt   pgtype.Timestamp
 }
 
+type PlanComment struct {
+	ID             int64
+	PlanID         int64
+	PlanIntervalID pgtype.Int8
+	GroupID        pgtype.Int8
+	PrescriptionID pgtype.Int8
+	ParentID       pgtype.Int8
+	UserID         int64
+	Body           string
+	ResolvedAt     pgtype.Timestamp
+	ResolvedBy     pgtype.Int8
+	EditedAt       pgtype.Timestamp
+	CreatedAt      pgtype.Timestamp
+	UpdatedAt      pgtype.Timestamp
+}
+
+type PlanCommentMention struct {
+	CommentID int64
+	UserID    int64
+}
+
 type PlanInterval struct {
 	ID          int64
 	PlanID      int64
@@ -97,10 +214,175 @@ type PlanInterval struct {
 	Description pgtype.Text
 	Duration    pgtype.Interval
 	Order       int32
+	IsDeload    bool
 	CreatedAt   pgtype.Timestamp
 	UpdatedAt   pgtype.Timestamp
 }
 
+type PlanRating struct {
+	PlanID    int64
+	UserID    int64
+	Stars     int32
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+type PlanShareLink struct {
+	ID         int64
+	PlanID     int64
+	Token      string
+	Permission string
+	CreatedBy  pgtype.Int8
+	ExpiresAt  pgtype.Timestamp
+	RevokedAt  pgtype.Timestamp
+	CreatedAt  pgtype.Timestamp
+}
+
+type PlanVersion struct {
+	ID           int64
+	PlanID       int64
+	Number       int32
+	Document     []byte
+	CreatedBy    pgtype.Int8
+	RestoredFrom pgtype.Int4
+	CreatedAt    pgtype.Timestamp
+}
+
+type PrescriptionBlock struct {
+	ID             int64
+	GroupID        int64
+	PlanIntervalID int64
+	Kind           string
+	Rest           pgtype.Interval
+	CreatedAt      pgtype.Timestamp
+	UpdatedAt      pgtype.Timestamp
+}
+
+type PrescriptionSet struct {
+	ID             int64
+	PrescriptionID int64
+	SetNumber      int32
+	Reps           pgtype.Int4
+	Duration       pgtype.Interval
+	Rpe            pgtype.Int4
+	Rest           pgtype.Interval
+}
+
+type PrescriptionSetParameter struct {
+	PrescriptionSetID int64
+	ParameterTypeID   int64
+	Value             float64
+	IsPercentage      bool
+}
+
+type ProgressionRule struct {
+	ID             int64
+	PrescriptionID pgtype.Int8
+	GroupID        pgtype.Int8
+	CreatedAt      pgtype.Timestamp
+	UpdatedAt      pgtype.Timestamp
+}
+
+type ProgressionRuleStep struct {
+	ID              int64
+	RuleID          int64
+	Position        int32
+	Target          string
+	ParameterTypeID pgtype.Int8
+	Amount          float64
+	Unit            string
+	UntilValue      pgtype.Float8
+}
+
+type ReadinessCheckin struct {
+	ID         int64
+	UserID     int64
+	CheckedOn  pgtype.Date
+	SleepHours pgtype.Float8
+	FingerPain pgtype.Int4
+	Mood       pgtype.Int4
+	RestingHr  pgtype.Int4
+	CreatedAt  pgtype.Timestamp
+	UpdatedAt  pgtype.Timestamp
+}
+
+type ReadinessSoreness struct {
+	CheckinID int64
+	BodyArea  string
+	Score     int32
+}
+
+type ScheduledSession struct {
+	ID               int64
+	PlanID           int64
+	PlanAssignmentID int64
+	PlanIntervalID   int64
+	GroupID          int64
+	ScheduledDate    pgtype.Date
+	OriginalDate     pgtype.Date
+	Status           string
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+}
+
+type SessionCompletion struct {
+	ScheduledSessionID int64
+	CompletedOn        pgtype.Date
+	Rpe                pgtype.Int4
+	Duration           pgtype.Interval
+	CreatedAt          pgtype.Timestamp
+}
+
+type SessionSet struct {
+	ID                  int64
+	ScheduledSessionID  int64
+	ExerciseVariationID int64
+	SetNumber           int32
+	Reps                pgtype.Int4
+	Rpe                 pgtype.Int4
+	CreatedAt           pgtype.Timestamp
+}
+
+type SessionSetParameter struct {
+	SessionSetID    int64
+	ParameterTypeID int64
+	Value           float64
+}
+
+type Team struct {
+	ID        int64
+	Name      string
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+type TeamInvitation struct {
+	ID        int64
+	TeamID    int64
+	UserID    int64
+	Role      string
+	InvitedBy pgtype.Int8
+	Status    string
+	CreatedAt pgtype.Timestamp
+	UpdatedAt pgtype.Timestamp
+}
+
+type TeamMember struct {
+	TeamID    int64
+	UserID    int64
+	Role      string
+	CreatedAt pgtype.Timestamp
+}
+
+type TrainingLoadThreshold struct {
+	UserID    int64
+	AcwrHigh  float64
+	AcwrLow   float64
+	Monotony  float64
+	Strain    float64
+	UpdatedAt pgtype.Timestamp
+}
+
 type User struct {
 	ID        int64
 	Email     string
@@ -110,6 +392,11 @@ type User struct {
 	UpdatedAt pgtype.Timestamp
 }
 
+type UserEquipment struct {
+	UserID    int64
+	Equipment string
+}
+
 type UserParameterType struct {
 	UserID          int64
 	ParameterTypeID int64
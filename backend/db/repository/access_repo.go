@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"backend/db"
+	"backend/internal/access"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// AccessRepository looks up who owns the records requests name
+type AccessRepository struct {
+	Queries *db.Queries
+}
+
+func NewAccessRepository(queries *db.Queries) *AccessRepository {
+	return &AccessRepository{Queries: queries}
+}
+
+func ownedBy(userId int64) access.Resource {
+	return access.Resource{OwnerID: &userId}
+}
+
+func ownedByNullable(userId pgtype.Int8) access.Resource {
+	if !userId.Valid {
+		return access.Resource{Public: true}
+	}
+	return ownedBy(userId.Int64)
+}
+
+func ownedByPlan(userId int64, isPublic bool, planId int64) access.Resource {
+	return access.Resource{OwnerID: &userId, Public: isPublic, PlanIDs: []int64{planId}}
+}
+
+// User is the data of the user itself, e.g. their equipment or check-ins
+func (r *AccessRepository) User(ctx context.Context, id int64) (access.Resource, error) {
+	return ownedBy(id), nil
+}
+
+func (r *AccessRepository) Plan(ctx context.Context, id int64) (access.Resource, error) {
+	row, err := r.Queries.Access_GetPlanOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByPlan(row.UserID, row.IsPublic, id), nil
+}
+
+func (r *AccessRepository) Interval(ctx context.Context, id int64) (access.Resource, error) {
+	row, err := r.Queries.Access_GetIntervalOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByPlan(row.UserID, row.IsPublic, row.PlanID), nil
+}
+
+func (r *AccessRepository) Prescription(ctx context.Context, id int64) (access.Resource, error) {
+	row, err := r.Queries.Access_GetPrescriptionOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByPlan(row.UserID, row.IsPublic, row.PlanID), nil
+}
+
+func (r *AccessRepository) PrescriptionBlock(ctx context.Context, id int64) (access.Resource, error) {
+	row, err := r.Queries.Access_GetPrescriptionBlockOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByPlan(row.UserID, row.IsPublic, row.PlanID), nil
+}
+
+// Group is part of every plan it is assigned to an interval of
+func (r *AccessRepository) Group(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetGroupOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	planIds, err := r.Queries.Access_ListGroupPlanIds(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	resource := ownedBy(userId)
+	resource.PlanIDs = planIds
+	return resource, nil
+}
+
+// ProgressionRule is accessed like the group it applies to, or the group of its prescription
+func (r *AccessRepository) ProgressionRule(ctx context.Context, id int64) (access.Resource, error) {
+	groupId, err := r.Queries.Access_GetProgressionRuleGroupId(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return r.Group(ctx, groupId)
+}
+
+// Category is public when it is one of the global categories
+func (r *AccessRepository) Category(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetCategoryOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByNullable(userId), nil
+}
+
+// Exercise is public when it belongs to the shared library
+func (r *AccessRepository) Exercise(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetExerciseOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByNullable(userId), nil
+}
+
+func (r *AccessRepository) ExerciseVariation(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetExerciseVariationOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedByNullable(userId), nil
+}
+
+func (r *AccessRepository) PlanAssignment(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetPlanAssignmentOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedBy(userId), nil
+}
+
+// ParameterType is part of the catalog every user reads from
+func (r *AccessRepository) ParameterType(ctx context.Context, id int64) (access.Resource, error) {
+	return access.Resource{Public: true}, nil
+}
+
+// Session belongs to the athlete whose assignment it was scheduled for
+func (r *AccessRepository) Session(ctx context.Context, id int64) (access.Resource, error) {
+	userId, err := r.Queries.Access_GetSessionOwner(ctx, id)
+	if err != nil {
+		return access.Resource{}, err
+	}
+	return ownedBy(userId), nil
+}
+
+// SharedMemberships returns the memberships of both users in the teams they share
+func (r *AccessRepository) SharedMemberships(ctx context.Context, actorId int64, targetId int64) ([]access.Membership, error) {
+	rows, err := r.Queries.TeamMembers_ListShared(ctx, db.TeamMembers_ListSharedParams{
+		ActorID:  actorId,
+		TargetID: targetId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]access.Membership, len(rows))
+	for i, row := range rows {
+		memberships[i] = access.Membership{TeamID: row.TeamID, UserID: row.UserID, Role: row.Role}
+	}
+	return memberships, nil
+}
+
+// Permission returns the strongest permission the user was given as a collaborator of
+// the plans, or an empty string
+func (r *AccessRepository) Permission(ctx context.Context, userId int64, planIds []int64) (string, error) {
+	permissions, err := r.Queries.Access_ListCollaboratorPermissions(ctx, db.Access_ListCollaboratorPermissionsParams{
+		UserID:  userId,
+		PlanIds: planIds,
+	})
+	if err != nil {
+		return "", err
+	}
+	return access.Strongest(permissions), nil
+}
+
+// Allowed tells whether the actor may access the resource at the level. Collaborator
+// permissions and team memberships are only looked up when they could grant access.
+func (r *AccessRepository) Allowed(ctx context.Context, actorId int64, resource access.Resource, level access.Level) (bool, error) {
+	if access.Allowed(actorId, resource, level, nil) {
+		return true, nil
+	}
+	if resource.OwnerID == nil || level == access.Manage {
+		return false, nil
+	}
+	if len(resource.PlanIDs) > 0 {
+		permission, err := r.Permission(ctx, actorId, resource.PlanIDs)
+		if err != nil {
+			return false, err
+		}
+		resource.Permission = permission
+		if access.Allowed(actorId, resource, level, nil) {
+			return true, nil
+		}
+	}
+	// Coaches read and comment on their athletes' data
+	if level != access.Read && level != access.Comment {
+		return false, nil
+	}
+	memberships, err := r.SharedMemberships(ctx, actorId, *resource.OwnerID)
+	if err != nil {
+		return false, err
+	}
+	return access.Allowed(actorId, resource, level, memberships), nil
+}
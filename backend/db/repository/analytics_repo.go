@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+)
+
+type AnalyticsRepository struct {
+	Queries *db.Queries
+}
+
+func NewAnalyticsRepository(queries *db.Queries) *AnalyticsRepository {
+	return &AnalyticsRepository{Queries: queries}
+}
+
+// ListPrescriptionMuscleGroups returns every prescription of a plan with its exercise's muscle groups.
+// An intervalId of 0 includes every interval; excludeDeloads leaves out deload intervals.
+func (r *AnalyticsRepository) ListPrescriptionMuscleGroups(ctx context.Context, planId int64, intervalId int64, excludeDeloads bool) ([]db.Analytics_ListPrescriptionMuscleGroupsRow, error) {
+	return r.Queries.Analytics_ListPrescriptionMuscleGroups(ctx, db.Analytics_ListPrescriptionMuscleGroupsParams{
+		PlanID:         planId,
+		IntervalID:     intervalId,
+		ExcludeDeloads: excludeDeloads,
+	})
+}
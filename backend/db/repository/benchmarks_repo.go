@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type BenchmarksRepository struct {
+	Queries *db.Queries
+}
+
+// BenchmarkFilter narrows a user's benchmarks. Zero values match everything.
+type BenchmarkFilter struct {
+	ExerciseVariationID int64
+	ParameterTypeID     int64
+}
+
+func NewBenchmarksRepository(queries *db.Queries) *BenchmarksRepository {
+	return &BenchmarksRepository{Queries: queries}
+}
+
+// List returns the user's benchmarks, most recent first
+func (r *BenchmarksRepository) List(ctx context.Context, userId int64, filter BenchmarkFilter, offset int32, limit int32) ([]db.Benchmark, error) {
+	return r.Queries.Benchmarks_List(ctx, db.Benchmarks_ListParams{
+		UserID:              userId,
+		ExerciseVariationID: filter.ExerciseVariationID,
+		ParameterTypeID:     filter.ParameterTypeID,
+		Offset:              offset,
+		Limit:               limit,
+	})
+}
+
+// ListLatest returns the user's latest benchmark for each variation and parameter as of
+// the date
+func (r *BenchmarksRepository) ListLatest(ctx context.Context, userId int64, asOf pgtype.Date) ([]db.Benchmark, error) {
+	return r.Queries.Benchmarks_ListLatest(ctx, db.Benchmarks_ListLatestParams{
+		UserID: userId,
+		AsOf:   asOf,
+	})
+}
+
+func (r *BenchmarksRepository) GetById(ctx context.Context, id int64, userId int64) (*db.Benchmark, error) {
+	benchmark, err := r.Queries.Benchmarks_GetById(ctx, db.Benchmarks_GetByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &benchmark, nil
+}
+
+// Create records a benchmark. A variation ID of 0 records one for every variation.
+func (r *BenchmarksRepository) Create(ctx context.Context, userId int64, variationId int64, parameterTypeId int64, value float64, recordedOn pgtype.Date) (*db.Benchmark, error) {
+	benchmark, err := r.Queries.Benchmarks_CreateOne(ctx, db.Benchmarks_CreateOneParams{
+		UserID:              userId,
+		ExerciseVariationID: pgtype.Int8{Int64: variationId, Valid: variationId != 0},
+		ParameterTypeID:     parameterTypeId,
+		Value:               value,
+		RecordedOn:          recordedOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &benchmark, nil
+}
+
+func (r *BenchmarksRepository) Update(ctx context.Context, id int64, userId int64, value float64, recordedOn pgtype.Date) (*db.Benchmark, error) {
+	benchmark, err := r.Queries.Benchmarks_UpdateOne(ctx, db.Benchmarks_UpdateOneParams{
+		ID:         id,
+		UserID:     userId,
+		Value:      value,
+		RecordedOn: recordedOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &benchmark, nil
+}
+
+func (r *BenchmarksRepository) Delete(ctx context.Context, id int64, userId int64) error {
+	_, err := r.Queries.Benchmarks_DeleteOne(ctx, db.Benchmarks_DeleteOneParams{
+		ID:     id,
+		UserID: userId,
+	})
+	return err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// calendarFeedTokenBytes is the amount of randomness in a feed token
+const calendarFeedTokenBytes = 32
+
+type CalendarFeedsRepository struct {
+	Queries *db.Queries
+}
+
+func NewCalendarFeedsRepository(queries *db.Queries) *CalendarFeedsRepository {
+	return &CalendarFeedsRepository{Queries: queries}
+}
+
+func (r *CalendarFeedsRepository) ListByUserId(ctx context.Context, userId int64) ([]db.CalendarFeed, error) {
+	return r.Queries.CalendarFeeds_ListByUserId(ctx, userId)
+}
+
+func (r *CalendarFeedsRepository) GetByToken(ctx context.Context, token string) (*db.CalendarFeed, error) {
+	feed, err := r.Queries.CalendarFeeds_GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Create issues a new feed with a random token for the user
+func (r *CalendarFeedsRepository) Create(ctx context.Context, userId int64) (*db.CalendarFeed, error) {
+	token := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	feed, err := r.Queries.CalendarFeeds_CreateOne(ctx, db.CalendarFeeds_CreateOneParams{
+		UserID: userId,
+		Token:  hex.EncodeToString(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+// Delete revokes one of the user's feeds
+func (r *CalendarFeedsRepository) Delete(ctx context.Context, id int64, userId int64) error {
+	_, err := r.Queries.CalendarFeeds_DeleteOne(ctx, db.CalendarFeeds_DeleteOneParams{
+		ID:     id,
+		UserID: userId,
+	})
+	return err
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CategoriesRepository struct {
+	Queries *db.Queries
+}
+
+type CategoryListParams struct {
+	CategoryId int64
+	UserId     int64
+	ExerciseId int64
+	Offset     int32
+	Limit      int32
+}
+
+func NewCategoriesRepository(queries *db.Queries) *CategoriesRepository {
+	return &CategoriesRepository{Queries: queries}
+}
+
+func (r *CategoriesRepository) List(ctx context.Context, params CategoryListParams) ([]db.Category, error) {
+	return r.Queries.Categories_List(ctx, db.Categories_ListParams{
+		CategoryID: params.CategoryId,
+		UserID:     params.UserId,
+		ExerciseID: params.ExerciseId,
+		Offset:     params.Offset,
+		Limit:      params.Limit,
+	})
+}
+
+func (r *CategoriesRepository) GetById(ctx context.Context, id int64) (*db.Category, error) {
+	category, err := r.Queries.Categories_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoriesRepository) Create(ctx context.Context, name string, icon string, userId int64) (*db.Category, error) {
+	category, err := r.Queries.Categories_CreateOne(ctx, db.Categories_CreateOneParams{
+		Name:   name,
+		Icon:   icon,
+		UserID: pgtype.Int8{Int64: userId, Valid: userId != 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoriesRepository) Update(ctx context.Context, id int64, name string, icon string) (*db.Category, error) {
+	category, err := r.Queries.Categories_UpdateOne(ctx, db.Categories_UpdateOneParams{
+		ID:   id,
+		Name: name,
+		Icon: icon,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoriesRepository) Delete(ctx context.Context, id int64) (*db.Category, error) {
+	category, err := r.Queries.Categories_DeleteById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoriesRepository) AddToExercise(ctx context.Context, exerciseId int64, categoryId int64) error {
+	return r.Queries.ExerciseCategories_Add(ctx, db.ExerciseCategories_AddParams{
+		ExerciseID: exerciseId,
+		CategoryID: categoryId,
+	})
+}
+
+func (r *CategoriesRepository) RemoveFromExercise(ctx context.Context, exerciseId int64, categoryId int64) error {
+	return r.Queries.ExerciseCategories_Remove(ctx, db.ExerciseCategories_RemoveParams{
+		ExerciseID: exerciseId,
+		CategoryID: categoryId,
+	})
+}
+
+// SetExerciseCategories replaces every category tag on an exercise with the given set
+func (r *CategoriesRepository) SetExerciseCategories(ctx context.Context, exerciseId int64, categoryIds []int64) error {
+	if err := r.Queries.ExerciseCategories_DeleteByExerciseId(ctx, exerciseId); err != nil {
+		return err
+	}
+	for _, categoryId := range categoryIds {
+		if err := r.AddToExercise(ctx, exerciseId, categoryId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListByExerciseIds returns the categories for each of the given exercises, keyed by exercise ID
+func (r *CategoriesRepository) ListByExerciseIds(ctx context.Context, exerciseIds []int64) (map[int64][]db.Category, error) {
+	result := make(map[int64][]db.Category)
+	if len(exerciseIds) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.Queries.ExerciseCategories_ListByExerciseIds(ctx, exerciseIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.ExerciseID] = append(result[row.ExerciseID], db.Category{
+			ID:        row.ID,
+			Name:      row.Name,
+			Icon:      row.Icon,
+			UserID:    row.UserID,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ExerciseMediaRepository struct {
+	Queries *db.Queries
+}
+
+type CreateExerciseMediaParams struct {
+	ExerciseId   int64
+	FileName     string
+	ContentType  string
+	SizeBytes    int64
+	StorageKey   string
+	ThumbnailKey string
+}
+
+func NewExerciseMediaRepository(queries *db.Queries) *ExerciseMediaRepository {
+	return &ExerciseMediaRepository{Queries: queries}
+}
+
+func (r *ExerciseMediaRepository) ListByExerciseId(ctx context.Context, exerciseId int64) ([]db.ExerciseMedium, error) {
+	return r.Queries.ExerciseMedia_ListByExerciseId(ctx, exerciseId)
+}
+
+func (r *ExerciseMediaRepository) GetById(ctx context.Context, id int64) (*db.ExerciseMedium, error) {
+	media, err := r.Queries.ExerciseMedia_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *ExerciseMediaRepository) Create(ctx context.Context, params CreateExerciseMediaParams) (*db.ExerciseMedium, error) {
+	media, err := r.Queries.ExerciseMedia_CreateOne(ctx, db.ExerciseMedia_CreateOneParams{
+		ExerciseID:   params.ExerciseId,
+		FileName:     params.FileName,
+		ContentType:  params.ContentType,
+		SizeBytes:    params.SizeBytes,
+		StorageKey:   params.StorageKey,
+		ThumbnailKey: pgtype.Text{String: params.ThumbnailKey, Valid: params.ThumbnailKey != ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *ExerciseMediaRepository) Delete(ctx context.Context, id int64) (*db.ExerciseMedium, error) {
+	media, err := r.Queries.ExerciseMedia_DeleteById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
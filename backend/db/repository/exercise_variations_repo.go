@@ -63,3 +63,16 @@ func (r *ExerciseVariationsRepository) AddParam(ctx context.Context, variationId
 func (r *ExerciseVariationsRepository) DeleteOne(ctx context.Context, id int64) error {
 	return r.Queries.ExerciseVariations_DeleteOne(ctx, id)
 }
+
+// ListByExerciseIds returns every variation of the given exercises with one row per parameter
+func (r *ExerciseVariationsRepository) ListByExerciseIds(ctx context.Context, exerciseIds []int64) ([]db.ExerciseVariations_ListByExerciseIdsRow, error) {
+	if len(exerciseIds) == 0 {
+		return nil, nil
+	}
+	return r.Queries.ExerciseVariations_ListByExerciseIds(ctx, exerciseIds)
+}
+
+// ListParams returns the parameter types a variation is prescribed with
+func (r *ExerciseVariationsRepository) ListParams(ctx context.Context, variationId int64) ([]db.ExerciseVariations_ListParamsRow, error) {
+	return r.Queries.ExerciseVariations_ListParams(ctx, variationId)
+}
@@ -22,18 +22,46 @@ type ExerciseListParams struct {
 	PlanID     int64
 	GroupID    int64
 	IntervalID int64
-	Limit      int32
+	CategoryID int64
+	// Metadata filters; empty strings match every exercise
+	MuscleGroup string
+	Equipment   string
+	Laterality  string
+	// VisibleTo limits the list to the user's own and the shared library's exercises
+	VisibleTo int64
+	Limit     int32
+}
+
+// ExerciseMetadata holds the structured muscle group and equipment metadata of an exercise
+type ExerciseMetadata struct {
+	PrimaryMuscleGroups   []string
+	SecondaryMuscleGroups []string
+	Equipment             []string
+	Laterality            string
+}
+
+// emptyIfNil keeps NOT NULL array columns from receiving a NULL
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
 }
 
 func (r *ExercisesRepository) ListExercises(ctx context.Context, params ExerciseListParams) ([]db.Exercise, error) {
 	return r.Queries.Exercises_List(ctx, db.Exercises_ListParams{
-		ExerciseID: params.ExerciseID,
-		UserID:     params.UserID,
-		PlanID:     params.PlanID,
-		GroupID:    params.GroupID,
-		IntervalID: params.IntervalID,
-		Limit:      params.Limit,
-		Offset:     0,
+		ExerciseID:  params.ExerciseID,
+		UserID:      params.UserID,
+		PlanID:      params.PlanID,
+		GroupID:     params.GroupID,
+		IntervalID:  params.IntervalID,
+		CategoryID:  params.CategoryID,
+		MuscleGroup: params.MuscleGroup,
+		Equipment:   params.Equipment,
+		Laterality:  params.Laterality,
+		VisibleTo:   params.VisibleTo,
+		Limit:       params.Limit,
+		Offset:      0,
 	})
 }
 
@@ -53,11 +81,15 @@ func (r *ExercisesRepository) GetExerciseById(ctx context.Context, id int64) (*d
 	return &exercise, nil
 }
 
-func (r *ExercisesRepository) CreateExercise(ctx context.Context, name string, description string, userId int64) (*db.Exercise, error) {
+func (r *ExercisesRepository) CreateExercise(ctx context.Context, name string, description string, userId int64, metadata ExerciseMetadata) (*db.Exercise, error) {
 	exercise, err := r.Queries.Exercises_CreateOne(ctx, db.Exercises_CreateOneParams{
-		Name:        name,
-		Description: description,
-		UserID:      pgtype.Int8{Int64: userId, Valid: true},
+		Name:                  name,
+		Description:           description,
+		UserID:                pgtype.Int8{Int64: userId, Valid: true},
+		PrimaryMuscleGroups:   emptyIfNil(metadata.PrimaryMuscleGroups),
+		SecondaryMuscleGroups: emptyIfNil(metadata.SecondaryMuscleGroups),
+		Equipment:             emptyIfNil(metadata.Equipment),
+		Laterality:            metadata.Laterality,
 	})
 	if err != nil {
 		return nil, err
@@ -65,11 +97,15 @@ func (r *ExercisesRepository) CreateExercise(ctx context.Context, name string, d
 	return &exercise, nil
 }
 
-func (r *ExercisesRepository) UpdateExercise(ctx context.Context, id int64, name string, description string) (*db.Exercise, error) {
+func (r *ExercisesRepository) UpdateExercise(ctx context.Context, id int64, name string, description string, metadata ExerciseMetadata) (*db.Exercise, error) {
 	exercise, err := r.Queries.Exercises_UpdateOne(ctx, db.Exercises_UpdateOneParams{
-		ID:          id,
-		Name:        name,
-		Description: description,
+		ID:                    id,
+		Name:                  name,
+		Description:           description,
+		PrimaryMuscleGroups:   emptyIfNil(metadata.PrimaryMuscleGroups),
+		SecondaryMuscleGroups: emptyIfNil(metadata.SecondaryMuscleGroups),
+		Equipment:             emptyIfNil(metadata.Equipment),
+		Laterality:            metadata.Laterality,
 	})
 	if err != nil {
 		return nil, err
@@ -96,3 +132,7 @@ func (r *ExercisesRepository) DeleteExercise(ctx context.Context, id int64) erro
 	}
 	return nil
 }
+
+func (r *ExercisesRepository) ListEquipmentByPlanId(ctx context.Context, planId int64) ([]db.Exercises_ListEquipmentByPlanIdRow, error) {
+	return r.Queries.Exercises_ListEquipmentByPlanId(ctx, planId)
+}
@@ -34,6 +34,8 @@ type PrescriptionCreateData struct {
 	SubRepWorkDuration *string
 	SubRepRestDuration *string
 	Rest               *string
+	// Position within the group's prescriptions for the interval, nil appends
+	Position *int32
 }
 
 func NewIntervalExercisePrescriptionsRepository(queries *db.Queries) *IntervalExercisePrescriptionsRepository {
@@ -66,85 +68,140 @@ func (r *IntervalExercisePrescriptionsRepository) ListWithDetails(ctx context.Co
 	})
 }
 
-func (r *IntervalExercisePrescriptionsRepository) CreateOne(ctx context.Context, prescription PrescriptionCreateData) (*db.IntervalExercisePrescription, error) {
-	var duration pgtype.Interval
-	var rest pgtype.Interval
-	var rpe pgtype.Int4
-	var reps pgtype.Int4
-	var subRepWorkDuration pgtype.Interval
-	var subRepRestDuration pgtype.Interval
-	var subReps pgtype.Int4
-
-	if prescription.Duration == nil {
-		duration = pgtype.Interval{Valid: false}
-	} else {
-		durationRef, err := utils.StringToInterval(*prescription.Duration)
+// detailPageSize is how many rows ListAllWithDetails reads at a time
+const detailPageSize = 1000
+
+// ListAllWithDetails returns every row ListWithDetails matches, reading them a page at a
+// time. Rows repeat for every parameter of a variation, so a limit on rows would silently
+// cut prescriptions off; the offset and limit in params are ignored.
+func (r *IntervalExercisePrescriptionsRepository) ListAllWithDetails(ctx context.Context, params IntervalExercisePrescriptionListParams) ([]db.IntervalExercisePrescriptions_ListWithDetailsRow, error) {
+	var rows []db.IntervalExercisePrescriptions_ListWithDetailsRow
+	params.Limit = detailPageSize
+	for params.Offset = 0; ; params.Offset += detailPageSize {
+		page, err := r.ListWithDetails(ctx, params)
 		if err != nil {
 			return nil, err
 		}
-		duration = durationRef
+		rows = append(rows, page...)
+		if len(page) < detailPageSize {
+			return rows, nil
+		}
 	}
+}
 
-	if prescription.SubRepWorkDuration == nil {
-		subRepWorkDuration = pgtype.Interval{Valid: false}
-	} else {
-		subRepWorkDurationRef, err := utils.StringToInterval(*prescription.SubRepWorkDuration)
-		if err != nil {
-			return nil, err
-		}
-		subRepWorkDuration = subRepWorkDurationRef
+// prescriptionValues are the columns of a prescription converted from request values
+type prescriptionValues struct {
+	rpe                pgtype.Int4
+	reps               pgtype.Int4
+	duration           pgtype.Interval
+	subReps            pgtype.Int4
+	subRepWorkDuration pgtype.Interval
+	subRepRestDuration pgtype.Interval
+	rest               pgtype.Interval
+}
+
+func optionalInt4(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{Valid: false}
 	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
 
-	if prescription.SubRepRestDuration == nil {
-		subRepRestDuration = pgtype.Interval{Valid: false}
-	} else {
-		subRepRestDurationRef, err := utils.StringToInterval(*prescription.SubRepRestDuration)
-		if err != nil {
-			return nil, err
-		}
-		subRepRestDuration = subRepRestDurationRef
+func optionalInterval(value *string) (pgtype.Interval, error) {
+	if value == nil {
+		return pgtype.Interval{Valid: false}, nil
 	}
+	return utils.StringToInterval(*value)
+}
 
-	if prescription.SubReps == nil {
-		subReps = pgtype.Int4{Valid: false}
-	} else {
-		subReps = pgtype.Int4{Int32: int32(*prescription.SubReps), Valid: true}
+func toPrescriptionValues(prescription PrescriptionCreateData) (prescriptionValues, error) {
+	values := prescriptionValues{
+		rpe:     optionalInt4(prescription.RPE),
+		reps:    optionalInt4(prescription.Reps),
+		subReps: optionalInt4(prescription.SubReps),
 	}
 
-	if prescription.Rest == nil {
-		rest = pgtype.Interval{Valid: false}
-	} else {
-		restRef, err := utils.StringToInterval(*prescription.Rest)
-		if err != nil {
-			return nil, err
-		}
-		rest = restRef
+	var err error
+	if values.duration, err = optionalInterval(prescription.Duration); err != nil {
+		return values, err
+	}
+	if values.subRepWorkDuration, err = optionalInterval(prescription.SubRepWorkDuration); err != nil {
+		return values, err
+	}
+	if values.subRepRestDuration, err = optionalInterval(prescription.SubRepRestDuration); err != nil {
+		return values, err
+	}
+	if values.rest, err = optionalInterval(prescription.Rest); err != nil {
+		return values, err
 	}
+	return values, nil
+}
 
-	if prescription.RPE == nil {
-		rpe = pgtype.Int4{Valid: false}
-	} else {
-		rpe = pgtype.Int4{Int32: int32(*prescription.RPE), Valid: true}
+func (r *IntervalExercisePrescriptionsRepository) CreateOne(ctx context.Context, prescription PrescriptionCreateData) (*db.IntervalExercisePrescription, error) {
+	values, err := toPrescriptionValues(prescription)
+	if err != nil {
+		return nil, err
 	}
 
-	if prescription.Reps == nil {
-		reps = pgtype.Int4{Valid: false}
+	var position int32
+	if prescription.Position != nil {
+		position = *prescription.Position
 	} else {
-		reps = pgtype.Int4{Int32: int32(*prescription.Reps), Valid: true}
+		next, err := r.NextPosition(ctx, prescription.GroupId, prescription.PlanIntervalId)
+		if err != nil {
+			return nil, err
+		}
+		position = next
 	}
 
 	row, err := r.Queries.IntervalExercisePrescriptions_CreateOne(ctx, db.IntervalExercisePrescriptions_CreateOneParams{
 		GroupID:            prescription.GroupId,
 		VariationID:        prescription.VariationId,
 		IntervalID:         prescription.PlanIntervalId,
-		Rpe:                rpe,
+		Rpe:                values.rpe,
 		Sets:               prescription.Sets,
-		Reps:               reps,
-		Duration:           duration,
-		SubReps:            subReps,
-		SubRepWorkDuration: subRepWorkDuration,
-		SubRepRestDuration: subRepRestDuration,
-		Rest:               rest,
+		Reps:               values.reps,
+		Duration:           values.duration,
+		SubReps:            values.subReps,
+		SubRepWorkDuration: values.subRepWorkDuration,
+		SubRepRestDuration: values.subRepRestDuration,
+		Rest:               values.rest,
+		Position:           position,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (r *IntervalExercisePrescriptionsRepository) GetById(ctx context.Context, id int64) (*db.IntervalExercisePrescription, error) {
+	row, err := r.Queries.IntervalExercisePrescriptions_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// UpdateOne replaces the variation and values of a prescription. Its group, interval and
+// position are kept.
+func (r *IntervalExercisePrescriptionsRepository) UpdateOne(ctx context.Context, id int64, prescription PrescriptionCreateData) (*db.IntervalExercisePrescription, error) {
+	values, err := toPrescriptionValues(prescription)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := r.Queries.IntervalExercisePrescriptions_UpdateOne(ctx, db.IntervalExercisePrescriptions_UpdateOneParams{
+		VariationID:        prescription.VariationId,
+		Rpe:                values.rpe,
+		Sets:               prescription.Sets,
+		Reps:               values.reps,
+		Duration:           values.duration,
+		SubReps:            values.subReps,
+		SubRepWorkDuration: values.subRepWorkDuration,
+		SubRepRestDuration: values.subRepRestDuration,
+		Rest:               values.rest,
+		ID:                 id,
 	})
 	if err != nil {
 		return nil, err
@@ -156,3 +213,31 @@ func (r *IntervalExercisePrescriptionsRepository) CreateOne(ctx context.Context,
 func (r *IntervalExercisePrescriptionsRepository) DeleteOne(ctx context.Context, id int64) error {
 	return r.Queries.IntervalExercisePrescriptions_DeleteOne(ctx, id)
 }
+
+func (r *IntervalExercisePrescriptionsRepository) NextPosition(ctx context.Context, groupId int64, intervalId int64) (int32, error) {
+	return r.Queries.IntervalExercisePrescriptions_NextPosition(ctx, db.IntervalExercisePrescriptions_NextPositionParams{
+		GroupID:    groupId,
+		IntervalID: intervalId,
+	})
+}
+
+// Reorder stores the order of a group's prescriptions, ids listed first come first
+func (r *IntervalExercisePrescriptionsRepository) Reorder(ctx context.Context, ids []int64) error {
+	for position, id := range ids {
+		if err := r.Queries.IntervalExercisePrescriptions_UpdatePosition(ctx, db.IntervalExercisePrescriptions_UpdatePositionParams{
+			Position: int32(position),
+			ID:       id,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetBlock links a prescription into a superset or circuit, a blockId of 0 unlinks it
+func (r *IntervalExercisePrescriptionsRepository) SetBlock(ctx context.Context, id int64, blockId int64) error {
+	return r.Queries.IntervalExercisePrescriptions_SetBlock(ctx, db.IntervalExercisePrescriptions_SetBlockParams{
+		BlockID: pgtype.Int8{Int64: blockId, Valid: blockId != 0},
+		ID:      id,
+	})
+}
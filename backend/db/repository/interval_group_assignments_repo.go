@@ -23,6 +23,8 @@ func (r *IntervalGroupAssignmentsRepository) GetByIntervalId(ctx context.Context
 			PlanIntervalId: row.PlanIntervalID,
 			GroupId:        row.GroupID,
 			Frequency:      row.Frequency,
+			PinnedDays:     pinnedDays(row.PinnedDays),
+			Order:          row.Order,
 			Group: &types.Group{
 				ID:          row.GID,
 				Name:        row.GName,
@@ -51,6 +53,8 @@ func (r *IntervalGroupAssignmentsRepository) GetByGroupId(ctx context.Context, g
 			PlanIntervalId: row.PlanIntervalID,
 			GroupId:        row.GroupID,
 			Frequency:      row.Frequency,
+			PinnedDays:     pinnedDays(row.PinnedDays),
+			Order:          row.Order,
 			PlanInterval: &types.PlanInterval{
 				ID:        row.PiID,
 				PlanID:    row.PiPlanID,
@@ -66,22 +70,50 @@ func (r *IntervalGroupAssignmentsRepository) GetByGroupId(ctx context.Context, g
 	return assignments, nil
 }
 
+func (r *IntervalGroupAssignmentsRepository) GetOne(ctx context.Context, planIntervalId int64, groupId int64) (*types.IntervalGroupAssignment, error) {
+	row, err := r.Queries.IntervalGroupAssignments_GetOne(ctx, db.IntervalGroupAssignments_GetOneParams{
+		PlanIntervalID: planIntervalId,
+		GroupID:        groupId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dbAssignmentToApiAssignment(row), nil
+}
+
+// NextOrder is the order that places a group after those already in the interval
+func (r *IntervalGroupAssignmentsRepository) NextOrder(ctx context.Context, planIntervalId int64) (int32, error) {
+	return r.Queries.IntervalGroupAssignments_NextOrder(ctx, planIntervalId)
+}
+
 func (r *IntervalGroupAssignmentsRepository) CreateOne(ctx context.Context, assignment types.IntervalGroupAssignment) (*types.IntervalGroupAssignment, error) {
 	row, err := r.Queries.IntervalGroupAssignments_Create(ctx, db.IntervalGroupAssignments_CreateParams{
 		PlanIntervalID: assignment.PlanIntervalId,
 		GroupID:        assignment.GroupId,
 		Frequency:      assignment.Frequency,
+		PinnedDays:     pinnedDays(assignment.PinnedDays),
+		Order:          assignment.Order,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &types.IntervalGroupAssignment{
-		ID:             row.ID,
-		PlanIntervalId: row.PlanIntervalID,
-		GroupId:        row.GroupID,
-		Frequency:      row.Frequency,
-	}, nil
+	return dbAssignmentToApiAssignment(row), nil
+}
+
+func (r *IntervalGroupAssignmentsRepository) UpdateOne(ctx context.Context, assignment types.IntervalGroupAssignment) (*types.IntervalGroupAssignment, error) {
+	row, err := r.Queries.IntervalGroupAssignments_Update(ctx, db.IntervalGroupAssignments_UpdateParams{
+		Frequency:      assignment.Frequency,
+		PinnedDays:     pinnedDays(assignment.PinnedDays),
+		Order:          assignment.Order,
+		PlanIntervalID: assignment.PlanIntervalId,
+		GroupID:        assignment.GroupId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dbAssignmentToApiAssignment(row), nil
 }
 
 func (r *IntervalGroupAssignmentsRepository) DeleteOne(ctx context.Context, planIntervalId int64, groupId int64) error {
@@ -90,3 +122,22 @@ func (r *IntervalGroupAssignmentsRepository) DeleteOne(ctx context.Context, plan
 		GroupID:        groupId,
 	})
 }
+
+func dbAssignmentToApiAssignment(row db.IntervalGroupAssignment) *types.IntervalGroupAssignment {
+	return &types.IntervalGroupAssignment{
+		ID:             row.ID,
+		PlanIntervalId: row.PlanIntervalID,
+		GroupId:        row.GroupID,
+		Frequency:      row.Frequency,
+		PinnedDays:     pinnedDays(row.PinnedDays),
+		Order:          row.Order,
+	}
+}
+
+// pinnedDays keeps unpinned assignments as an empty list rather than null
+func pinnedDays(days []int32) []int32 {
+	if days == nil {
+		return []int32{}
+	}
+	return days
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Send styles of a logbook entry
+const (
+	StyleOnsight  = "onsight"
+	StyleFlash    = "flash"
+	StyleRedpoint = "redpoint"
+	StyleRepeat   = "repeat"
+)
+
+type LogbookEntriesRepository struct {
+	Queries *db.Queries
+}
+
+// LogbookEntryFilter narrows a user's logbook. Zero values match everything.
+type LogbookEntryFilter struct {
+	Discipline         string
+	Style              string
+	ScheduledSessionID int64
+	Outdoor            *bool
+	From               pgtype.Date
+	To                 pgtype.Date
+}
+
+// LogbookEntryData is a climb as it is logged. A scheduled session ID of 0 leaves the
+// entry unattached.
+type LogbookEntryData struct {
+	ScheduledSessionID int64
+	Name               string
+	Discipline         string
+	GradeSystem        string
+	Grade              string
+	GradeIndex         int32
+	Style              string
+	Attempts           int32
+	Outdoor            bool
+	ClimbedOn          pgtype.Date
+}
+
+func NewLogbookEntriesRepository(queries *db.Queries) *LogbookEntriesRepository {
+	return &LogbookEntriesRepository{Queries: queries}
+}
+
+// List returns the user's entries, most recent first
+func (r *LogbookEntriesRepository) List(ctx context.Context, userId int64, filter LogbookEntryFilter, offset int32, limit int32) ([]db.LogbookEntry, error) {
+	outdoor := pgtype.Bool{Valid: filter.Outdoor != nil}
+	if filter.Outdoor != nil {
+		outdoor.Bool = *filter.Outdoor
+	}
+	return r.Queries.LogbookEntries_List(ctx, db.LogbookEntries_ListParams{
+		UserID:             userId,
+		Discipline:         filter.Discipline,
+		Style:              filter.Style,
+		ScheduledSessionID: filter.ScheduledSessionID,
+		Outdoor:            outdoor,
+		FromDate:           filter.From,
+		ToDate:             filter.To,
+		Offset:             offset,
+		Limit:              limit,
+	})
+}
+
+func (r *LogbookEntriesRepository) GetById(ctx context.Context, id int64, userId int64) (*db.LogbookEntry, error) {
+	entry, err := r.Queries.LogbookEntries_GetById(ctx, db.LogbookEntries_GetByIdParams{
+		ID:     id,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *LogbookEntriesRepository) Create(ctx context.Context, userId int64, data LogbookEntryData) (*db.LogbookEntry, error) {
+	entry, err := r.Queries.LogbookEntries_CreateOne(ctx, db.LogbookEntries_CreateOneParams{
+		UserID:             userId,
+		ScheduledSessionID: pgtype.Int8{Int64: data.ScheduledSessionID, Valid: data.ScheduledSessionID != 0},
+		Name:               data.Name,
+		Discipline:         data.Discipline,
+		GradeSystem:        data.GradeSystem,
+		Grade:              data.Grade,
+		GradeIndex:         data.GradeIndex,
+		Style:              data.Style,
+		Attempts:           data.Attempts,
+		Outdoor:            data.Outdoor,
+		ClimbedOn:          data.ClimbedOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *LogbookEntriesRepository) Update(ctx context.Context, id int64, userId int64, data LogbookEntryData) (*db.LogbookEntry, error) {
+	entry, err := r.Queries.LogbookEntries_UpdateOne(ctx, db.LogbookEntries_UpdateOneParams{
+		ID:                 id,
+		UserID:             userId,
+		ScheduledSessionID: pgtype.Int8{Int64: data.ScheduledSessionID, Valid: data.ScheduledSessionID != 0},
+		Name:               data.Name,
+		Discipline:         data.Discipline,
+		GradeSystem:        data.GradeSystem,
+		Grade:              data.Grade,
+		GradeIndex:         data.GradeIndex,
+		Style:              data.Style,
+		Attempts:           data.Attempts,
+		Outdoor:            data.Outdoor,
+		ClimbedOn:          data.ClimbedOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *LogbookEntriesRepository) Delete(ctx context.Context, id int64, userId int64) error {
+	_, err := r.Queries.LogbookEntries_DeleteOne(ctx, db.LogbookEntries_DeleteOneParams{
+		ID:     id,
+		UserID: userId,
+	})
+	return err
+}
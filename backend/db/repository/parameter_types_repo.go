@@ -58,3 +58,11 @@ func (r *ParameterTypesRepository) List(ctx context.Context, params ListParamete
 		Limit:           params.Limit,
 	})
 }
+
+// LinkToUser makes a parameter type visible in the user's parameter type list
+func (r *ParameterTypesRepository) LinkToUser(ctx context.Context, userId int64, parameterTypeId int64) error {
+	return r.Queries.UserParameterTypes_Add(ctx, db.UserParameterTypes_AddParams{
+		UserID:          userId,
+		ParameterTypeID: parameterTypeId,
+	})
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PersonalRecordsRepository struct {
+	Queries *db.Queries
+}
+
+// PersonalRecordFilter narrows a record listing. Zero values match everything; without
+// History only current records are listed.
+type PersonalRecordFilter struct {
+	UserID              int64
+	ExerciseVariationID int64
+	ParameterTypeID     int64
+	Kind                string
+	History             bool
+}
+
+// PersonalRecordData is a newly set record
+type PersonalRecordData struct {
+	UserID              int64
+	ExerciseVariationID int64
+	ParameterTypeID     int64
+	Kind                string
+	Value               float64
+	PreviousValue       *float64
+	Load                *float64
+	Reps                *int32
+	SessionSetID        int64
+	AchievedOn          pgtype.Date
+}
+
+func NewPersonalRecordsRepository(queries *db.Queries) *PersonalRecordsRepository {
+	return &PersonalRecordsRepository{Queries: queries}
+}
+
+// List returns records, most recent first
+func (r *PersonalRecordsRepository) List(ctx context.Context, filter PersonalRecordFilter, offset int32, limit int32) ([]db.PersonalRecord, error) {
+	return r.Queries.PersonalRecords_List(ctx, db.PersonalRecords_ListParams{
+		UserID:              filter.UserID,
+		ExerciseVariationID: filter.ExerciseVariationID,
+		ParameterTypeID:     filter.ParameterTypeID,
+		Kind:                filter.Kind,
+		CurrentOnly:         !filter.History,
+		Offset:              offset,
+		Limit:               limit,
+	})
+}
+
+func optionalFloat8(value *float64) pgtype.Float8 {
+	return pgtype.Float8{Float64: derefFloat(value), Valid: value != nil}
+}
+
+func (r *PersonalRecordsRepository) Create(ctx context.Context, data PersonalRecordData) (*db.PersonalRecord, error) {
+	record, err := r.Queries.PersonalRecords_CreateOne(ctx, db.PersonalRecords_CreateOneParams{
+		UserID:              data.UserID,
+		ExerciseVariationID: data.ExerciseVariationID,
+		ParameterTypeID:     data.ParameterTypeID,
+		Kind:                data.Kind,
+		Value:               data.Value,
+		PreviousValue:       optionalFloat8(data.PreviousValue),
+		Load:                optionalFloat8(data.Load),
+		Reps:                optionalInt4(data.Reps),
+		SessionSetID:        pgtype.Int8{Int64: data.SessionSetID, Valid: data.SessionSetID != 0},
+		AchievedOn:          data.AchievedOn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PlanAssignmentsRepository struct {
+	Queries *db.Queries
+}
+
+func NewPlanAssignmentsRepository(queries *db.Queries) *PlanAssignmentsRepository {
+	return &PlanAssignmentsRepository{Queries: queries}
+}
+
+func (r *PlanAssignmentsRepository) List(ctx context.Context, planId int64, userId int64, offset int32, limit int32) ([]db.PlanAssignment, error) {
+	return r.Queries.PlanAssignments_List(ctx, db.PlanAssignments_ListParams{
+		PlanID: planId,
+		UserID: userId,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+func (r *PlanAssignmentsRepository) GetById(ctx context.Context, id int64) (*db.PlanAssignment, error) {
+	assignment, err := r.Queries.PlanAssignments_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *PlanAssignmentsRepository) GetByPlanAndUser(ctx context.Context, planId int64, userId int64) (*db.PlanAssignment, error) {
+	assignment, err := r.Queries.PlanAssignments_GetByPlanAndUser(ctx, db.PlanAssignments_GetByPlanAndUserParams{
+		PlanID: planId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *PlanAssignmentsRepository) Create(ctx context.Context, planId int64, userId int64, startDate pgtype.Date, trainingDays []int32, minRestDays int32) (*db.PlanAssignment, error) {
+	assignment, err := r.Queries.PlanAssignments_CreateOne(ctx, db.PlanAssignments_CreateOneParams{
+		PlanID:       planId,
+		UserID:       userId,
+		StartDate:    startDate,
+		TrainingDays: trainingDays,
+		MinRestDays:  minRestDays,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *PlanAssignmentsRepository) Update(ctx context.Context, id int64, startDate pgtype.Date, trainingDays []int32, minRestDays int32) (*db.PlanAssignment, error) {
+	assignment, err := r.Queries.PlanAssignments_UpdateOne(ctx, db.PlanAssignments_UpdateOneParams{
+		StartDate:    startDate,
+		TrainingDays: trainingDays,
+		MinRestDays:  minRestDays,
+		ID:           id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+func (r *PlanAssignmentsRepository) Delete(ctx context.Context, id int64) error {
+	return r.Queries.PlanAssignments_DeleteOne(ctx, id)
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PlanCommentsRepository stores the threaded comments on plans and the users they mention
+type PlanCommentsRepository struct {
+	Queries *db.Queries
+}
+
+func NewPlanCommentsRepository(queries *db.Queries) *PlanCommentsRepository {
+	return &PlanCommentsRepository{Queries: queries}
+}
+
+// PlanCommentTarget is what a comment is attached to besides its plan; at most one is set
+type PlanCommentTarget struct {
+	IntervalId     *int64
+	GroupId        *int64
+	PrescriptionId *int64
+}
+
+type PlanCommentListParams struct {
+	PlanId int64
+	PlanCommentTarget
+	Resolved *bool
+	Limit    int32
+	Offset   int32
+}
+
+// CommentCounts are the comments on a record and how many of its threads are unresolved
+type CommentCounts struct {
+	Comments          int32
+	UnresolvedThreads int32
+}
+
+func optionalInt8(value *int64) pgtype.Int8 {
+	if value == nil {
+		return pgtype.Int8{}
+	}
+	return pgtype.Int8{Int64: *value, Valid: true}
+}
+
+func (r *PlanCommentsRepository) Create(ctx context.Context, planId int64, target PlanCommentTarget, parentId *int64, userId int64, body string) (*db.PlanComment, error) {
+	comment, err := r.Queries.PlanComments_Create(ctx, db.PlanComments_CreateParams{
+		PlanID:         planId,
+		PlanIntervalID: optionalInt8(target.IntervalId),
+		GroupID:        optionalInt8(target.GroupId),
+		PrescriptionID: optionalInt8(target.PrescriptionId),
+		ParentID:       optionalInt8(parentId),
+		UserID:         userId,
+		Body:           body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Get returns a comment with its author's name
+func (r *PlanCommentsRepository) Get(ctx context.Context, id int64) (*db.PlanComments_GetRow, error) {
+	comment, err := r.Queries.PlanComments_Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListThreads returns the comments that start the plan's threads, oldest first
+func (r *PlanCommentsRepository) ListThreads(ctx context.Context, params PlanCommentListParams) ([]db.PlanComments_ListThreadsRow, error) {
+	resolved := pgtype.Bool{}
+	if params.Resolved != nil {
+		resolved = pgtype.Bool{Bool: *params.Resolved, Valid: true}
+	}
+	return r.Queries.PlanComments_ListThreads(ctx, db.PlanComments_ListThreadsParams{
+		PlanID:         params.PlanId,
+		PlanIntervalID: optionalInt8(params.IntervalId),
+		GroupID:        optionalInt8(params.GroupId),
+		PrescriptionID: optionalInt8(params.PrescriptionId),
+		Resolved:       resolved,
+		Offset:         params.Offset,
+		Limit:          params.Limit,
+	})
+}
+
+// ListReplies returns the replies in the threads, oldest first
+func (r *PlanCommentsRepository) ListReplies(ctx context.Context, threadIds []int64) ([]db.PlanComments_ListRepliesRow, error) {
+	return r.Queries.PlanComments_ListReplies(ctx, threadIds)
+}
+
+func (r *PlanCommentsRepository) Update(ctx context.Context, id int64, body string) (*db.PlanComment, error) {
+	comment, err := r.Queries.PlanComments_Update(ctx, db.PlanComments_UpdateParams{ID: id, Body: body})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// SetResolved resolves a thread, or reopens it. Resolving a resolved thread keeps the
+// time it was first resolved.
+func (r *PlanCommentsRepository) SetResolved(ctx context.Context, id int64, resolved bool, resolvedBy *int64) (*db.PlanComment, error) {
+	comment, err := r.Queries.PlanComments_SetResolved(ctx, db.PlanComments_SetResolvedParams{
+		Resolved:   resolved,
+		ResolvedBy: optionalInt8(resolvedBy),
+		ID:         id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Delete removes a comment with its replies
+func (r *PlanCommentsRepository) Delete(ctx context.Context, id int64) error {
+	return r.Queries.PlanComments_Delete(ctx, id)
+}
+
+// ListMembers returns the users who may be mentioned in the plan's comments: its owner
+// and collaborators
+func (r *PlanCommentsRepository) ListMembers(ctx context.Context, planId int64) ([]db.PlanComments_ListMembersRow, error) {
+	return r.Queries.PlanComments_ListMembers(ctx, planId)
+}
+
+// SetMentions replaces the users a comment mentions
+func (r *PlanCommentsRepository) SetMentions(ctx context.Context, commentId int64, userIds []int64) error {
+	if err := r.Queries.PlanComments_ClearMentions(ctx, commentId); err != nil {
+		return err
+	}
+	for _, userId := range userIds {
+		if err := r.Queries.PlanComments_AddMention(ctx, db.PlanComments_AddMentionParams{CommentID: commentId, UserID: userId}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PlanCommentsRepository) ListMentions(ctx context.Context, commentIds []int64) ([]db.PlanComments_ListMentionsRow, error) {
+	return r.Queries.PlanComments_ListMentions(ctx, commentIds)
+}
+
+// CountByPlan counts the comments on the plans and their intervals, groups and prescriptions
+func (r *PlanCommentsRepository) CountByPlan(ctx context.Context, planIds []int64) (map[int64]CommentCounts, error) {
+	rows, err := r.Queries.PlanComments_CountByPlan(ctx, planIds)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]CommentCounts, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = CommentCounts{Comments: row.CommentCount, UnresolvedThreads: row.UnresolvedThreadCount}
+	}
+	return counts, nil
+}
+
+func (r *PlanCommentsRepository) CountByInterval(ctx context.Context, intervalIds []int64) (map[int64]CommentCounts, error) {
+	rows, err := r.Queries.PlanComments_CountByInterval(ctx, intervalIds)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]CommentCounts, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = CommentCounts{Comments: row.CommentCount, UnresolvedThreads: row.UnresolvedThreadCount}
+	}
+	return counts, nil
+}
+
+// CountByGroup counts the comments on the groups in the plan, or in every plan when
+// planId is zero
+func (r *PlanCommentsRepository) CountByGroup(ctx context.Context, groupIds []int64, planId int64) (map[int64]CommentCounts, error) {
+	rows, err := r.Queries.PlanComments_CountByGroup(ctx, db.PlanComments_CountByGroupParams{
+		Ids:    groupIds,
+		PlanID: pgtype.Int8{Int64: planId, Valid: planId != 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]CommentCounts, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = CommentCounts{Comments: row.CommentCount, UnresolvedThreads: row.UnresolvedThreadCount}
+	}
+	return counts, nil
+}
+
+func (r *PlanCommentsRepository) CountByPrescription(ctx context.Context, prescriptionIds []int64) (map[int64]CommentCounts, error) {
+	rows, err := r.Queries.PlanComments_CountByPrescription(ctx, prescriptionIds)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]CommentCounts, len(rows))
+	for _, row := range rows {
+		counts[row.TargetID] = CommentCounts{Comments: row.CommentCount, UnresolvedThreads: row.UnresolvedThreadCount}
+	}
+	return counts, nil
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"backend/db"
 	"backend/internal/utils"
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -42,20 +44,22 @@ func (r *PlanIntervalsRepository) CreatePlanInterval(ctx context.Context, planId
 		return nil, err
 	}
 
-	if len(plan_intervals) > 0 {
-		plan_interval_updates := db.PlanIntervals_UpdateOrderByValuesParams{
-			IntervalIds: make([]int64, 0),
-			NewOrders:   make([]int32, 0),
+	// Shift the following intervals back one at a time from the last, so no two share
+	// an order on the way
+	following := make([]db.PlanIntervals_ListRow, 0, len(plan_intervals))
+	for _, plan_interval := range plan_intervals {
+		if plan_interval.Order >= order {
+			following = append(following, plan_interval)
 		}
-
-		for _, plan_interval := range plan_intervals {
-			if plan_interval.Order >= order {
-				plan_interval_updates.IntervalIds = append(plan_interval_updates.IntervalIds, plan_interval.ID)
-				plan_interval_updates.NewOrders = append(plan_interval_updates.NewOrders, plan_interval.Order+1)
-			}
-		}
-
-		_, err = r.Queries.PlanIntervals_UpdateOrderByValues(ctx, plan_interval_updates)
+	}
+	slices.SortFunc(following, func(a, b db.PlanIntervals_ListRow) int {
+		return cmp.Compare(b.Order, a.Order)
+	})
+	for _, plan_interval := range following {
+		_, err = r.Queries.PlanIntervals_UpdateOrderByValues(ctx, db.PlanIntervals_UpdateOrderByValuesParams{
+			IntervalIds: []int64{plan_interval.ID},
+			NewOrders:   []int32{plan_interval.Order + 1},
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -97,6 +101,18 @@ func (r *PlanIntervalsRepository) CreatePlanInterval(ctx context.Context, planId
 	return &plan_interval, nil
 }
 
+// SetDeload marks an interval as a deload, or clears the mark
+func (r *PlanIntervalsRepository) SetDeload(ctx context.Context, id int64, isDeload bool) (*db.PlanInterval, error) {
+	plan_interval, err := r.Queries.PlanIntervals_SetDeload(ctx, db.PlanIntervals_SetDeloadParams{
+		ID:       id,
+		IsDeload: isDeload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan_interval, nil
+}
+
 func (r *PlanIntervalsRepository) DeletePlanInterval(ctx context.Context, id int64) (*db.PlanInterval, error) {
 	plan_interval, err := r.Queries.PlanIntervals_DeleteById(ctx, id)
 	if err != nil {
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// shareLinkTokenBytes is the amount of randomness in a share link token
+const shareLinkTokenBytes = 32
+
+// PlanSharingRepository manages the collaborators of plans and the links they are
+// shared through
+type PlanSharingRepository struct {
+	Queries *db.Queries
+}
+
+func NewPlanSharingRepository(queries *db.Queries) *PlanSharingRepository {
+	return &PlanSharingRepository{Queries: queries}
+}
+
+// ShareLinkActive tells whether the link still gives access: it is neither revoked nor expired
+func ShareLinkActive(link *db.PlanShareLink, now time.Time) bool {
+	if link.RevokedAt.Valid {
+		return false
+	}
+	return !link.ExpiresAt.Valid || link.ExpiresAt.Time.After(now)
+}
+
+// ListCollaborators returns the plan's collaborators whose access is active
+func (r *PlanSharingRepository) ListCollaborators(ctx context.Context, planId int64) ([]db.PlanCollaborators_ListRow, error) {
+	return r.Queries.PlanCollaborators_List(ctx, planId)
+}
+
+func (r *PlanSharingRepository) GetCollaborator(ctx context.Context, planId int64, userId int64) (*db.PlanCollaborator, error) {
+	collaborator, err := r.Queries.PlanCollaborators_Get(ctx, db.PlanCollaborators_GetParams{
+		PlanID: planId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// SetCollaborator shares the plan with the user directly, replacing the access they had
+func (r *PlanSharingRepository) SetCollaborator(ctx context.Context, planId int64, userId int64, permission string) (*db.PlanCollaborator, error) {
+	return r.upsertCollaborator(ctx, planId, userId, permission, pgtype.Int8{})
+}
+
+// JoinThroughLink gives the user the link's permission for as long as the link is active
+func (r *PlanSharingRepository) JoinThroughLink(ctx context.Context, link *db.PlanShareLink, userId int64) (*db.PlanCollaborator, error) {
+	return r.upsertCollaborator(ctx, link.PlanID, userId, link.Permission, pgtype.Int8{Int64: link.ID, Valid: true})
+}
+
+func (r *PlanSharingRepository) upsertCollaborator(ctx context.Context, planId int64, userId int64, permission string, shareLinkId pgtype.Int8) (*db.PlanCollaborator, error) {
+	collaborator, err := r.Queries.PlanCollaborators_Upsert(ctx, db.PlanCollaborators_UpsertParams{
+		PlanID:      planId,
+		UserID:      userId,
+		Permission:  permission,
+		ShareLinkID: shareLinkId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+func (r *PlanSharingRepository) RemoveCollaborator(ctx context.Context, planId int64, userId int64) (*db.PlanCollaborator, error) {
+	collaborator, err := r.Queries.PlanCollaborators_Delete(ctx, db.PlanCollaborators_DeleteParams{
+		PlanID: planId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// CreateLink issues a share link with a random token. Without an expiry it lasts until
+// it is revoked.
+func (r *PlanSharingRepository) CreateLink(ctx context.Context, planId int64, permission string, createdBy *int64, expiresAt *time.Time) (*db.PlanShareLink, error) {
+	token := make([]byte, shareLinkTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	params := db.PlanShareLinks_CreateParams{
+		PlanID:     planId,
+		Token:      hex.EncodeToString(token),
+		Permission: permission,
+	}
+	if createdBy != nil {
+		params.CreatedBy = pgtype.Int8{Int64: *createdBy, Valid: true}
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = pgtype.Timestamp{Time: *expiresAt, Valid: true}
+	}
+	link, err := r.Queries.PlanShareLinks_Create(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// ListLinks returns the plan's share links, revoked and expired ones included, newest first
+func (r *PlanSharingRepository) ListLinks(ctx context.Context, planId int64) ([]db.PlanShareLink, error) {
+	return r.Queries.PlanShareLinks_ListByPlanId(ctx, planId)
+}
+
+func (r *PlanSharingRepository) GetLinkByToken(ctx context.Context, token string) (*db.PlanShareLink, error) {
+	link, err := r.Queries.PlanShareLinks_GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// RevokeLink revokes one of the plan's links, ending the access of everyone who joined
+// through it. Revoking a link again keeps the time it was first revoked.
+func (r *PlanSharingRepository) RevokeLink(ctx context.Context, planId int64, id int64) (*db.PlanShareLink, error) {
+	link, err := r.Queries.PlanShareLinks_Revoke(ctx, db.PlanShareLinks_RevokeParams{
+		ID:     id,
+		PlanID: planId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PlanVersionsRepository keeps the history of plans as snapshots of their tree and
+// restores the plan tree to an earlier snapshot
+type PlanVersionsRepository struct {
+	Queries *db.Queries
+}
+
+func NewPlanVersionsRepository(queries *db.Queries) *PlanVersionsRepository {
+	return &PlanVersionsRepository{Queries: queries}
+}
+
+// Create stores the document as the plan's next version. restoredFrom is the number of
+// the version it restored, if any.
+func (r *PlanVersionsRepository) Create(ctx context.Context, planId int64, document []byte, createdBy *int64, restoredFrom *int32) (*db.PlanVersion, error) {
+	params := db.PlanVersions_CreateParams{
+		PlanID:   planId,
+		Document: document,
+	}
+	if createdBy != nil {
+		params.CreatedBy = pgtype.Int8{Int64: *createdBy, Valid: true}
+	}
+	if restoredFrom != nil {
+		params.RestoredFrom = pgtype.Int4{Int32: *restoredFrom, Valid: true}
+	}
+	version, err := r.Queries.PlanVersions_Create(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// List returns the plan's versions without their documents, newest first
+func (r *PlanVersionsRepository) List(ctx context.Context, planId int64, limit int32, offset int32) ([]db.PlanVersions_ListRow, error) {
+	return r.Queries.PlanVersions_List(ctx, db.PlanVersions_ListParams{
+		PlanID: planId,
+		Offset: offset,
+		Limit:  limit,
+	})
+}
+
+func (r *PlanVersionsRepository) Get(ctx context.Context, planId int64, number int32) (*db.PlanVersion, error) {
+	version, err := r.Queries.PlanVersions_Get(ctx, db.PlanVersions_GetParams{
+		PlanID: planId,
+		Number: number,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+func (r *PlanVersionsRepository) GetLatest(ctx context.Context, planId int64) (*db.PlanVersion, error) {
+	version, err := r.Queries.PlanVersions_GetLatest(ctx, planId)
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// RestorePlan sets the plan's name, description and visibility back. Its start date
+// belongs to its schedule rather than its content and is kept.
+func (r *PlanVersionsRepository) RestorePlan(ctx context.Context, id int64, name string, description string, isTemplate bool, isPublic bool) (*db.Plan, error) {
+	plan, err := r.Queries.PlanVersions_RestorePlan(ctx, db.PlanVersions_RestorePlanParams{
+		ID:          id,
+		Name:        name,
+		Description: description,
+		IsTemplate:  isTemplate,
+		IsPublic:    isPublic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// CreateInterval adds an interval at exactly the order, without moving the intervals
+// after it as PlanIntervalsRepository.CreatePlanInterval does
+func (r *PlanVersionsRepository) CreateInterval(ctx context.Context, planId int64, name string, description string, duration pgtype.Interval, order int32, isDeload bool) (*db.PlanInterval, error) {
+	interval, err := r.Queries.PlanVersions_CreateInterval(ctx, db.PlanVersions_CreateIntervalParams{
+		PlanID:      planId,
+		Name:        pgtype.Text{String: name, Valid: true},
+		Description: pgtype.Text{String: description, Valid: true},
+		Duration:    duration,
+		Order:       order,
+		IsDeload:    isDeload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &interval, nil
+}
+
+func (r *PlanVersionsRepository) RestoreInterval(ctx context.Context, id int64, name string, description string, duration pgtype.Interval, isDeload bool) (*db.PlanInterval, error) {
+	interval, err := r.Queries.PlanVersions_RestoreInterval(ctx, db.PlanVersions_RestoreIntervalParams{
+		ID:          id,
+		Name:        pgtype.Text{String: name, Valid: true},
+		Description: pgtype.Text{String: description, Valid: true},
+		Duration:    duration,
+		IsDeload:    isDeload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &interval, nil
+}
+
+// MoveIntervals sets the orders of the intervals. Each interval is first moved out of the
+// way, so intervals may swap orders without breaking their uniqueness within the plan.
+func (r *PlanVersionsRepository) MoveIntervals(ctx context.Context, intervalIds []int64, orders []int32) error {
+	if len(intervalIds) == 0 {
+		return nil
+	}
+	placeholders := make([]int32, len(intervalIds))
+	for i := range placeholders {
+		placeholders[i] = -int32(i) - 1
+	}
+	if _, err := r.Queries.PlanIntervals_UpdateOrderByValues(ctx, db.PlanIntervals_UpdateOrderByValuesParams{
+		IntervalIds: intervalIds,
+		NewOrders:   placeholders,
+	}); err != nil {
+		return err
+	}
+	_, err := r.Queries.PlanIntervals_UpdateOrderByValues(ctx, db.PlanIntervals_UpdateOrderByValuesParams{
+		IntervalIds: intervalIds,
+		NewOrders:   orders,
+	})
+	return err
+}
+
+// ClearInterval removes the groups, prescriptions and blocks of an interval but keeps
+// the interval, so the sessions scheduled from it remain
+func (r *PlanVersionsRepository) ClearInterval(ctx context.Context, intervalId int64) error {
+	if err := r.Queries.PlanVersions_ClearIntervalBlocks(ctx, intervalId); err != nil {
+		return err
+	}
+	if err := r.Queries.PlanVersions_ClearIntervalPrescriptions(ctx, intervalId); err != nil {
+		return err
+	}
+	return r.Queries.PlanVersions_ClearIntervalAssignments(ctx, intervalId)
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"backend/db"
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type PlansRepository struct {
@@ -51,13 +53,14 @@ func (r *PlansRepository) GetPlansByUserId(ctx context.Context, userId int64, li
 	return plans, nil
 }
 
-func (r *PlansRepository) CreatePlan(ctx context.Context, name string, description string, userId int64, isTemplate bool, isPublic bool) (*db.Plan, error) {
+func (r *PlansRepository) CreatePlan(ctx context.Context, name string, description string, userId int64, isTemplate bool, isPublic bool, startDate pgtype.Date) (*db.Plan, error) {
 	plan, err := r.Queries.Plans_CreateOne(ctx, db.Plans_CreateOneParams{
 		Name:          name, 
 		Description:   description, 
 		UserID:        userId,
 		IsTemplate:    isTemplate,
 		IsPublic:      isPublic,
+		StartDate:     startDate,
 	})
 	if err != nil {
 		return nil, err
@@ -65,13 +68,14 @@ func (r *PlansRepository) CreatePlan(ctx context.Context, name string, descripti
 	return &plan, nil
 }
 
-func (r *PlansRepository) UpdatePlan(ctx context.Context, id int64, name string, description string, isTemplate bool, isPublic bool) (*db.Plan, error) {
+func (r *PlansRepository) UpdatePlan(ctx context.Context, id int64, name string, description string, isTemplate bool, isPublic bool, startDate pgtype.Date) (*db.Plan, error) {
 	plan, err := r.Queries.Plans_UpdateOne(ctx, db.Plans_UpdateOneParams{
 		ID:            id,
 		Name:          name,
 		Description:   description,
 		IsTemplate:    isTemplate,
 		IsPublic:      isPublic,
+		StartDate:     startDate,
 	})
 	if err != nil {
 		return nil, err
@@ -93,4 +97,39 @@ func (r *PlansRepository) DeletePlan(ctx context.Context, id int64) error {
 		return err
 	}
 	return nil
-}
\ No newline at end of file
+}
+// SetSourcePlan links a plan to the plan it was copied from
+func (r *PlansRepository) SetSourcePlan(ctx context.Context, id int64, sourcePlanId int64) (*db.Plan, error) {
+	plan, err := r.Queries.Plans_SetSourcePlanId(ctx, db.Plans_SetSourcePlanIdParams{
+		SourcePlanID: pgtype.Int8{Int64: sourcePlanId, Valid: true},
+		ID:           id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// ListCopies returns the plans copied from the plan
+func (r *PlansRepository) ListCopies(ctx context.Context, sourcePlanId int64) ([]db.Plan, error) {
+	return r.Queries.Plans_ListBySourcePlanId(ctx, pgtype.Int8{Int64: sourcePlanId, Valid: true})
+}
+
+// ListAccessible returns the user's own plans and the plans shared with them, with the
+// permission they were given for the shared ones. shared limits the list to either.
+func (r *PlansRepository) ListAccessible(ctx context.Context, userId int64, limit int, offset int, isTemplate *bool, isPublic *bool, shared *bool) ([]db.Plans_ListAccessibleRow, error) {
+	nullableBool := func(value *bool) pgtype.Bool {
+		if value == nil {
+			return pgtype.Bool{}
+		}
+		return pgtype.Bool{Bool: *value, Valid: true}
+	}
+	return r.Queries.Plans_ListAccessible(ctx, db.Plans_ListAccessibleParams{
+		UserID:     userId,
+		IsTemplate: nullableBool(isTemplate),
+		IsPublic:   nullableBool(isPublic),
+		Shared:     nullableBool(shared),
+		Offset:     int32(offset),
+		Limit:      int32(limit),
+	})
+}
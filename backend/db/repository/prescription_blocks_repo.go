@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	BlockKindSuperset = "superset"
+	BlockKindCircuit  = "circuit"
+)
+
+type PrescriptionBlocksRepository struct {
+	Queries *db.Queries
+}
+
+func NewPrescriptionBlocksRepository(queries *db.Queries) *PrescriptionBlocksRepository {
+	return &PrescriptionBlocksRepository{Queries: queries}
+}
+
+func (r *PrescriptionBlocksRepository) List(ctx context.Context, groupId int64, intervalId int64, offset int32, limit int32) ([]db.PrescriptionBlock, error) {
+	return r.Queries.PrescriptionBlocks_List(ctx, db.PrescriptionBlocks_ListParams{
+		GroupID:    groupId,
+		IntervalID: intervalId,
+		Offset:     offset,
+		Limit:      limit,
+	})
+}
+
+func (r *PrescriptionBlocksRepository) GetById(ctx context.Context, id int64) (*db.PrescriptionBlock, error) {
+	block, err := r.Queries.PrescriptionBlocks_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (r *PrescriptionBlocksRepository) Create(ctx context.Context, groupId int64, intervalId int64, kind string, rest pgtype.Interval) (*db.PrescriptionBlock, error) {
+	block, err := r.Queries.PrescriptionBlocks_CreateOne(ctx, db.PrescriptionBlocks_CreateOneParams{
+		GroupID:        groupId,
+		PlanIntervalID: intervalId,
+		Kind:           kind,
+		Rest:           rest,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (r *PrescriptionBlocksRepository) Update(ctx context.Context, id int64, kind string, rest pgtype.Interval) (*db.PrescriptionBlock, error) {
+	block, err := r.Queries.PrescriptionBlocks_UpdateOne(ctx, db.PrescriptionBlocks_UpdateOneParams{
+		Kind: kind,
+		Rest: rest,
+		ID:   id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// Delete removes a block, its prescriptions stay in place and are no longer linked
+func (r *PrescriptionBlocksRepository) Delete(ctx context.Context, id int64) error {
+	return r.Queries.PrescriptionBlocks_DeleteOne(ctx, id)
+}
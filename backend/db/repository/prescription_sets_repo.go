@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+)
+
+type PrescriptionSetsRepository struct {
+	Queries *db.Queries
+}
+
+// PrescriptionSetData is one set of a prescription's breakdown. Empty fields fall back
+// to the prescription's uniform values.
+type PrescriptionSetData struct {
+	Reps       *int32
+	Duration   *string
+	RPE        *int32
+	Rest       *string
+	Parameters []PrescriptionSetParameterData
+}
+
+// PrescriptionSetParameterData is a set's value for a parameter, or a percentage of the
+// athlete's benchmark for it
+type PrescriptionSetParameterData struct {
+	ParameterTypeId int64
+	Value           float64
+	IsPercentage    bool
+}
+
+func NewPrescriptionSetsRepository(queries *db.Queries) *PrescriptionSetsRepository {
+	return &PrescriptionSetsRepository{Queries: queries}
+}
+
+// ListByPrescriptionIds returns the sets of the prescriptions ordered by set number,
+// together with the parameter values of those sets
+func (r *PrescriptionSetsRepository) ListByPrescriptionIds(ctx context.Context, prescriptionIds []int64) ([]db.PrescriptionSet, []db.PrescriptionSetParameter, error) {
+	sets, err := r.Queries.PrescriptionSets_ListByPrescriptionIds(ctx, prescriptionIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sets) == 0 {
+		return sets, nil, nil
+	}
+	parameters, err := r.Queries.PrescriptionSets_ListParametersByPrescriptionIds(ctx, prescriptionIds)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sets, parameters, nil
+}
+
+// Replace stores the breakdown of a prescription, numbering the sets from 1. No sets
+// leaves the prescription uniform.
+func (r *PrescriptionSetsRepository) Replace(ctx context.Context, prescriptionId int64, sets []PrescriptionSetData) error {
+	if err := r.Queries.PrescriptionSets_DeleteByPrescriptionId(ctx, prescriptionId); err != nil {
+		return err
+	}
+
+	for i, set := range sets {
+		duration, err := optionalInterval(set.Duration)
+		if err != nil {
+			return err
+		}
+		rest, err := optionalInterval(set.Rest)
+		if err != nil {
+			return err
+		}
+
+		dbSet, err := r.Queries.PrescriptionSets_CreateOne(ctx, db.PrescriptionSets_CreateOneParams{
+			PrescriptionID: prescriptionId,
+			SetNumber:      int32(i + 1),
+			Reps:           optionalInt4(set.Reps),
+			Duration:       duration,
+			Rpe:            optionalInt4(set.RPE),
+			Rest:           rest,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, parameter := range set.Parameters {
+			if err := r.Queries.PrescriptionSets_CreateParameter(ctx, db.PrescriptionSets_CreateParameterParams{
+				PrescriptionSetID: dbSet.ID,
+				ParameterTypeID:   parameter.ParameterTypeId,
+				Value:             parameter.Value,
+				IsPercentage:      parameter.IsPercentage,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ProgressionRulesRepository struct {
+	Queries *db.Queries
+}
+
+func NewProgressionRulesRepository(queries *db.Queries) *ProgressionRulesRepository {
+	return &ProgressionRulesRepository{Queries: queries}
+}
+
+// ProgressionStepData is a step of a rule. A nil ParameterTypeId is stored as NULL.
+type ProgressionStepData struct {
+	Target          string
+	ParameterTypeId int64
+	Amount          float64
+	Unit            string
+	Until           *float64
+}
+
+func (r *ProgressionRulesRepository) List(ctx context.Context, prescriptionId int64, groupId int64, offset int32, limit int32) ([]db.ProgressionRule, error) {
+	return r.Queries.ProgressionRules_List(ctx, db.ProgressionRules_ListParams{
+		PrescriptionID: prescriptionId,
+		GroupID:        groupId,
+		Offset:         offset,
+		Limit:          limit,
+	})
+}
+
+// ListForPrescriptions returns the rules attached to any of the prescriptions or groups
+func (r *ProgressionRulesRepository) ListForPrescriptions(ctx context.Context, prescriptionIds []int64, groupIds []int64) ([]db.ProgressionRule, error) {
+	return r.Queries.ProgressionRules_ListForPrescriptions(ctx, db.ProgressionRules_ListForPrescriptionsParams{
+		PrescriptionIds: prescriptionIds,
+		GroupIds:        groupIds,
+	})
+}
+
+func (r *ProgressionRulesRepository) ListSteps(ctx context.Context, ruleIds []int64) ([]db.ProgressionRuleStep, error) {
+	return r.Queries.ProgressionRuleSteps_ListByRuleIds(ctx, ruleIds)
+}
+
+func (r *ProgressionRulesRepository) GetById(ctx context.Context, id int64) (*db.ProgressionRule, error) {
+	rule, err := r.Queries.ProgressionRules_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Create attaches a rule to a prescription or, when prescriptionId is 0, to a group
+func (r *ProgressionRulesRepository) Create(ctx context.Context, prescriptionId int64, groupId int64, steps []ProgressionStepData) (*db.ProgressionRule, error) {
+	rule, err := r.Queries.ProgressionRules_CreateOne(ctx, db.ProgressionRules_CreateOneParams{
+		PrescriptionID: pgtype.Int8{Int64: prescriptionId, Valid: prescriptionId != 0},
+		GroupID:        pgtype.Int8{Int64: groupId, Valid: prescriptionId == 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := r.createSteps(ctx, rule.ID, steps); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ReplaceSteps swaps the steps of a rule
+func (r *ProgressionRulesRepository) ReplaceSteps(ctx context.Context, id int64, steps []ProgressionStepData) (*db.ProgressionRule, error) {
+	if err := r.Queries.ProgressionRuleSteps_DeleteByRuleId(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := r.createSteps(ctx, id, steps); err != nil {
+		return nil, err
+	}
+	rule, err := r.Queries.ProgressionRules_Touch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *ProgressionRulesRepository) createSteps(ctx context.Context, ruleId int64, steps []ProgressionStepData) error {
+	for i, step := range steps {
+		if _, err := r.Queries.ProgressionRuleSteps_CreateOne(ctx, db.ProgressionRuleSteps_CreateOneParams{
+			RuleID:          ruleId,
+			Position:        int32(i),
+			Target:          step.Target,
+			ParameterTypeID: pgtype.Int8{Int64: step.ParameterTypeId, Valid: step.ParameterTypeId != 0},
+			Amount:          step.Amount,
+			Unit:            step.Unit,
+			UntilValue:      pgtype.Float8{Float64: derefFloat(step.Until), Valid: step.Until != nil},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func derefFloat(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func (r *ProgressionRulesRepository) Delete(ctx context.Context, id int64) error {
+	return r.Queries.ProgressionRules_DeleteOne(ctx, id)
+}
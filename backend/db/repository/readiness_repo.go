@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ReadinessRepository struct {
+	Queries *db.Queries
+}
+
+// ReadinessCheckinData is a day's check-in. Soreness maps body areas to scores.
+type ReadinessCheckinData struct {
+	SleepHours *float64
+	Soreness   map[string]int32
+	FingerPain *int32
+	Mood       *int32
+	RestingHR  *int32
+}
+
+func NewReadinessRepository(queries *db.Queries) *ReadinessRepository {
+	return &ReadinessRepository{Queries: queries}
+}
+
+// List returns the user's check-ins between the dates, most recent first. Invalid dates
+// leave the range open.
+func (r *ReadinessRepository) List(ctx context.Context, userId int64, from pgtype.Date, to pgtype.Date, offset int32, limit int32) ([]db.ReadinessCheckin, error) {
+	return r.Queries.ReadinessCheckins_List(ctx, db.ReadinessCheckins_ListParams{
+		UserID:   userId,
+		FromDate: from,
+		ToDate:   to,
+		Offset:   offset,
+		Limit:    limit,
+	})
+}
+
+// ListSoreness returns the soreness of each check-in by its ID
+func (r *ReadinessRepository) ListSoreness(ctx context.Context, checkinIds []int64) (map[int64]map[string]int32, error) {
+	rows, err := r.Queries.ReadinessSoreness_ListByCheckinIds(ctx, checkinIds)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]map[string]int32)
+	for _, row := range rows {
+		if result[row.CheckinID] == nil {
+			result[row.CheckinID] = make(map[string]int32)
+		}
+		result[row.CheckinID][row.BodyArea] = row.Score
+	}
+	return result, nil
+}
+
+func (r *ReadinessRepository) GetByDate(ctx context.Context, userId int64, date pgtype.Date) (*db.ReadinessCheckin, error) {
+	checkin, err := r.Queries.ReadinessCheckins_GetByDate(ctx, db.ReadinessCheckins_GetByDateParams{
+		UserID:    userId,
+		CheckedOn: date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &checkin, nil
+}
+
+// Save stores the user's check-in for the date, replacing one already given that day
+func (r *ReadinessRepository) Save(ctx context.Context, userId int64, date pgtype.Date, data ReadinessCheckinData) (*db.ReadinessCheckin, error) {
+	checkin, err := r.Queries.ReadinessCheckins_Upsert(ctx, db.ReadinessCheckins_UpsertParams{
+		UserID:     userId,
+		CheckedOn:  date,
+		SleepHours: optionalFloat8(data.SleepHours),
+		FingerPain: optionalInt4(data.FingerPain),
+		Mood:       optionalInt4(data.Mood),
+		RestingHr:  optionalInt4(data.RestingHR),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.Queries.ReadinessSoreness_DeleteByCheckinId(ctx, checkin.ID); err != nil {
+		return nil, err
+	}
+	for area, score := range data.Soreness {
+		if err := r.Queries.ReadinessSoreness_Create(ctx, db.ReadinessSoreness_CreateParams{
+			CheckinID: checkin.ID,
+			BodyArea:  area,
+			Score:     score,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &checkin, nil
+}
+
+func (r *ReadinessRepository) DeleteByDate(ctx context.Context, userId int64, date pgtype.Date) (*db.ReadinessCheckin, error) {
+	checkin, err := r.Queries.ReadinessCheckins_DeleteByDate(ctx, db.ReadinessCheckins_DeleteByDateParams{
+		UserID:    userId,
+		CheckedOn: date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &checkin, nil
+}
+
+// ListIntervalGroupExercises returns the muscle groups and equipment of each exercise
+// prescribed to the interval's groups, in the groups' order
+func (r *ReadinessRepository) ListIntervalGroupExercises(ctx context.Context, intervalId int64) ([]db.Readiness_ListIntervalGroupExercisesRow, error) {
+	return r.Queries.Readiness_ListIntervalGroupExercises(ctx, intervalId)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	SessionStatusScheduled = "scheduled"
+	SessionStatusCompleted = "completed"
+	SessionStatusSkipped   = "skipped"
+)
+
+type ScheduledSessionsRepository struct {
+	Queries *db.Queries
+}
+
+// ScheduledSessionFilter narrows a session listing. Zero values match everything.
+type ScheduledSessionFilter struct {
+	PlanAssignmentID int64
+	PlanID           int64
+	UserID           int64
+	From             pgtype.Date
+	To               pgtype.Date
+	Status           string
+}
+
+func NewScheduledSessionsRepository(queries *db.Queries) *ScheduledSessionsRepository {
+	return &ScheduledSessionsRepository{Queries: queries}
+}
+
+func (r *ScheduledSessionsRepository) List(ctx context.Context, filter ScheduledSessionFilter, offset int32, limit int32) ([]db.ScheduledSessions_ListRow, error) {
+	return r.Queries.ScheduledSessions_List(ctx, db.ScheduledSessions_ListParams{
+		PlanAssignmentID: filter.PlanAssignmentID,
+		PlanID:           filter.PlanID,
+		UserID:           filter.UserID,
+		FromDate:         filter.From,
+		ToDate:           filter.To,
+		Status:           filter.Status,
+		Limit:            limit,
+		Offset:           offset,
+	})
+}
+
+// ListByUserId lists the sessions of every plan assigned to the user
+func (r *ScheduledSessionsRepository) ListByUserId(ctx context.Context, userId int64) ([]db.ScheduledSessions_ListByUserIdRow, error) {
+	return r.Queries.ScheduledSessions_ListByUserId(ctx, userId)
+}
+
+func (r *ScheduledSessionsRepository) GetById(ctx context.Context, id int64) (*db.ScheduledSession, error) {
+	session, err := r.Queries.ScheduledSessions_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *ScheduledSessionsRepository) Create(ctx context.Context, assignment *db.PlanAssignment, intervalId int64, groupId int64, date pgtype.Date) (*db.ScheduledSession, error) {
+	session, err := r.Queries.ScheduledSessions_CreateOne(ctx, db.ScheduledSessions_CreateOneParams{
+		PlanID:           assignment.PlanID,
+		PlanAssignmentID: assignment.ID,
+		PlanIntervalID:   intervalId,
+		GroupID:          groupId,
+		ScheduledDate:    date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Reschedule moves a session to another date. The generated date is kept.
+func (r *ScheduledSessionsRepository) Reschedule(ctx context.Context, id int64, date pgtype.Date) (*db.ScheduledSession, error) {
+	session, err := r.Queries.ScheduledSessions_UpdateDate(ctx, db.ScheduledSessions_UpdateDateParams{
+		ScheduledDate: date,
+		ID:            id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *ScheduledSessionsRepository) SetStatus(ctx context.Context, id int64, status string) (*db.ScheduledSession, error) {
+	session, err := r.Queries.ScheduledSessions_UpdateStatus(ctx, db.ScheduledSessions_UpdateStatusParams{
+		Status: status,
+		ID:     id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// DeletePending removes an assignment's sessions that were neither completed nor skipped
+func (r *ScheduledSessionsRepository) DeletePending(ctx context.Context, assignmentId int64) error {
+	return r.Queries.ScheduledSessions_DeletePendingByAssignmentId(ctx, assignmentId)
+}
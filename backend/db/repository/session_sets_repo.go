@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+)
+
+type SessionSetsRepository struct {
+	Queries *db.Queries
+}
+
+// SessionSetData is a set as it was performed
+type SessionSetData struct {
+	ExerciseVariationId int64
+	Reps                *int32
+	RPE                 *int32
+	Parameters          []SessionSetParameterData
+}
+
+type SessionSetParameterData struct {
+	ParameterTypeId int64
+	Value           float64
+}
+
+func NewSessionSetsRepository(queries *db.Queries) *SessionSetsRepository {
+	return &SessionSetsRepository{Queries: queries}
+}
+
+// Create logs a set of the session with its parameters
+func (r *SessionSetsRepository) Create(ctx context.Context, sessionId int64, setNumber int32, data SessionSetData) (*db.SessionSet, error) {
+	set, err := r.Queries.SessionSets_CreateOne(ctx, db.SessionSets_CreateOneParams{
+		ScheduledSessionID:  sessionId,
+		ExerciseVariationID: data.ExerciseVariationId,
+		SetNumber:           setNumber,
+		Reps:                optionalInt4(data.Reps),
+		Rpe:                 optionalInt4(data.RPE),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, parameter := range data.Parameters {
+		if err := r.Queries.SessionSets_CreateParameter(ctx, db.SessionSets_CreateParameterParams{
+			SessionSetID:    set.ID,
+			ParameterTypeID: parameter.ParameterTypeId,
+			Value:           parameter.Value,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return &set, nil
+}
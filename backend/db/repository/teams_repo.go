@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"backend/db"
+	"backend/internal/access"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a team invitation
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+type TeamsRepository struct {
+	Queries *db.Queries
+}
+
+func NewTeamsRepository(queries *db.Queries) *TeamsRepository {
+	return &TeamsRepository{Queries: queries}
+}
+
+// Create creates a team owned by the user
+func (r *TeamsRepository) Create(ctx context.Context, name string, ownerId int64) (*db.Team, error) {
+	team, err := r.Queries.Teams_CreateOne(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.AddMember(ctx, team.ID, ownerId, access.RoleOwner); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *TeamsRepository) GetById(ctx context.Context, id int64) (*db.Team, error) {
+	team, err := r.Queries.Teams_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *TeamsRepository) Update(ctx context.Context, id int64, name string) (*db.Team, error) {
+	team, err := r.Queries.Teams_UpdateOne(ctx, db.Teams_UpdateOneParams{
+		Name: name,
+		ID:   id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *TeamsRepository) Delete(ctx context.Context, id int64) (*db.Team, error) {
+	team, err := r.Queries.Teams_DeleteById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// ListByUserId returns the teams the user belongs to with their role in each
+func (r *TeamsRepository) ListByUserId(ctx context.Context, userId int64) ([]db.Teams_ListByUserIdRow, error) {
+	return r.Queries.Teams_ListByUserId(ctx, userId)
+}
+
+func (r *TeamsRepository) ListMembers(ctx context.Context, teamId int64) ([]db.TeamMembers_ListRow, error) {
+	return r.Queries.TeamMembers_List(ctx, teamId)
+}
+
+// Memberships returns the team's roster as memberships
+func (r *TeamsRepository) Memberships(ctx context.Context, teamId int64) ([]access.Membership, error) {
+	rows, err := r.Queries.TeamMembers_List(ctx, teamId)
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]access.Membership, len(rows))
+	for i, row := range rows {
+		memberships[i] = access.Membership{TeamID: row.TeamID, UserID: row.UserID, Role: row.Role}
+	}
+	return memberships, nil
+}
+
+func (r *TeamsRepository) GetMember(ctx context.Context, teamId int64, userId int64) (*db.TeamMember, error) {
+	member, err := r.Queries.TeamMembers_Get(ctx, db.TeamMembers_GetParams{
+		TeamID: teamId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// AddMember adds the user to the team, changing the role of a member already in it
+func (r *TeamsRepository) AddMember(ctx context.Context, teamId int64, userId int64, role string) (*db.TeamMember, error) {
+	member, err := r.Queries.TeamMembers_Upsert(ctx, db.TeamMembers_UpsertParams{
+		TeamID: teamId,
+		UserID: userId,
+		Role:   role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *TeamsRepository) UpdateMemberRole(ctx context.Context, teamId int64, userId int64, role string) (*db.TeamMember, error) {
+	member, err := r.Queries.TeamMembers_UpdateRole(ctx, db.TeamMembers_UpdateRoleParams{
+		Role:   role,
+		TeamID: teamId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *TeamsRepository) RemoveMember(ctx context.Context, teamId int64, userId int64) (*db.TeamMember, error) {
+	member, err := r.Queries.TeamMembers_Delete(ctx, db.TeamMembers_DeleteParams{
+		TeamID: teamId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *TeamsRepository) CreateInvitation(ctx context.Context, teamId int64, userId int64, role string, invitedBy int64) (*db.TeamInvitation, error) {
+	invitation, err := r.Queries.TeamInvitations_Create(ctx, db.TeamInvitations_CreateParams{
+		TeamID:    teamId,
+		UserID:    userId,
+		Role:      role,
+		InvitedBy: pgtype.Int8{Int64: invitedBy, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *TeamsRepository) GetInvitation(ctx context.Context, id int64) (*db.TeamInvitation, error) {
+	invitation, err := r.Queries.TeamInvitations_GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ListInvitations returns the team's invitations, newest first. An empty status lists
+// every invitation.
+func (r *TeamsRepository) ListInvitations(ctx context.Context, teamId int64, status string) ([]db.TeamInvitation, error) {
+	return r.Queries.TeamInvitations_ListByTeamId(ctx, db.TeamInvitations_ListByTeamIdParams{
+		TeamID: teamId,
+		Status: pgtype.Text{String: status, Valid: status != ""},
+	})
+}
+
+// ListUserInvitations returns the invitations sent to the user, newest first
+func (r *TeamsRepository) ListUserInvitations(ctx context.Context, userId int64, status string) ([]db.TeamInvitation, error) {
+	return r.Queries.TeamInvitations_ListByUserId(ctx, db.TeamInvitations_ListByUserIdParams{
+		UserID: userId,
+		Status: pgtype.Text{String: status, Valid: status != ""},
+	})
+}
+
+func (r *TeamsRepository) SetInvitationStatus(ctx context.Context, id int64, status string) (*db.TeamInvitation, error) {
+	invitation, err := r.Queries.TeamInvitations_UpdateStatus(ctx, db.TeamInvitations_UpdateStatusParams{
+		Status: status,
+		ID:     id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
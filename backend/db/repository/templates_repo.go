@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Orders of the template gallery
+const (
+	TemplateSortRecent  = "recent"
+	TemplateSortPopular = "popular" // most forked first, then best rated
+)
+
+// TemplatesRepository browses the public templates of all users and keeps their ratings
+type TemplatesRepository struct {
+	Queries *db.Queries
+}
+
+func NewTemplatesRepository(queries *db.Queries) *TemplatesRepository {
+	return &TemplatesRepository{Queries: queries}
+}
+
+// TemplateListParams filters the gallery. Search matches the name or description and
+// Category the name of a category of the exercises prescribed in the template.
+type TemplateListParams struct {
+	Search   string
+	Category string
+	Sort     string
+	Limit    int32
+	Offset   int32
+}
+
+// List returns the public templates that match the filters with their fork count and rating
+func (r *TemplatesRepository) List(ctx context.Context, params TemplateListParams) ([]db.Templates_ListRow, error) {
+	return r.Queries.Templates_List(ctx, db.Templates_ListParams{
+		Search:   params.Search,
+		Category: params.Category,
+		Sort:     params.Sort,
+		Offset:   params.Offset,
+		Limit:    params.Limit,
+	})
+}
+
+// Get returns a public template with its fork count and rating, or pgx.ErrNoRows when the
+// plan is not a public template
+func (r *TemplatesRepository) Get(ctx context.Context, id int64) (*db.Templates_ListRow, error) {
+	rows, err := r.Queries.Templates_List(ctx, db.Templates_ListParams{
+		ID:    pgtype.Int8{Int64: id, Valid: true},
+		Sort:  TemplateSortRecent,
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return &rows[0], nil
+}
+
+// ListIntervals returns the intervals of the templates in order, with the names of their
+// groups and how many prescriptions they hold
+func (r *TemplatesRepository) ListIntervals(ctx context.Context, planIds []int64) ([]db.Templates_ListIntervalsRow, error) {
+	return r.Queries.Templates_ListIntervals(ctx, planIds)
+}
+
+// Rate sets the user's rating of the template, replacing the one they gave before
+func (r *TemplatesRepository) Rate(ctx context.Context, planId int64, userId int64, stars int32) (*db.PlanRating, error) {
+	rating, err := r.Queries.PlanRatings_Upsert(ctx, db.PlanRatings_UpsertParams{
+		PlanID: planId,
+		UserID: userId,
+		Stars:  stars,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *TemplatesRepository) RemoveRating(ctx context.Context, planId int64, userId int64) (*db.PlanRating, error) {
+	rating, err := r.Queries.PlanRatings_Delete(ctx, db.PlanRatings_DeleteParams{
+		PlanID: planId,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type TrainingLoadRepository struct {
+	Queries *db.Queries
+}
+
+func NewTrainingLoadRepository(queries *db.Queries) *TrainingLoadRepository {
+	return &TrainingLoadRepository{Queries: queries}
+}
+
+// CreateCompletion records when a session was completed and how hard and long it was.
+// The RPE and duration are left out when they are not known.
+func (r *TrainingLoadRepository) CreateCompletion(ctx context.Context, sessionId int64, completedOn pgtype.Date, rpe *int32, duration pgtype.Interval) (*db.SessionCompletion, error) {
+	completion, err := r.Queries.SessionCompletions_CreateOne(ctx, db.SessionCompletions_CreateOneParams{
+		ScheduledSessionID: sessionId,
+		CompletedOn:        completedOn,
+		Rpe:                optionalInt4(rpe),
+		Duration:           duration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+// ListCompletions returns the user's sessions completed between the dates, inclusive,
+// oldest first
+func (r *TrainingLoadRepository) ListCompletions(ctx context.Context, userId int64, from pgtype.Date, to pgtype.Date) ([]db.SessionCompletion, error) {
+	return r.Queries.SessionCompletions_ListByUserId(ctx, db.SessionCompletions_ListByUserIdParams{
+		UserID:   userId,
+		FromDate: from,
+		ToDate:   to,
+	})
+}
+
+func (r *TrainingLoadRepository) GetThresholds(ctx context.Context, userId int64) (*db.TrainingLoadThreshold, error) {
+	thresholds, err := r.Queries.TrainingLoadThresholds_GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &thresholds, nil
+}
+
+func (r *TrainingLoadRepository) SetThresholds(ctx context.Context, params db.TrainingLoadThresholds_UpsertParams) (*db.TrainingLoadThreshold, error) {
+	thresholds, err := r.Queries.TrainingLoadThresholds_Upsert(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &thresholds, nil
+}
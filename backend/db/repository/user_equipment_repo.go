@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"backend/db"
+	"context"
+)
+
+type UserEquipmentRepository struct {
+	Queries *db.Queries
+}
+
+func NewUserEquipmentRepository(queries *db.Queries) *UserEquipmentRepository {
+	return &UserEquipmentRepository{Queries: queries}
+}
+
+func (r *UserEquipmentRepository) ListByUserId(ctx context.Context, userId int64) ([]string, error) {
+	equipment, err := r.Queries.UserEquipment_ListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if equipment == nil {
+		equipment = []string{}
+	}
+	return equipment, nil
+}
+
+// SetEquipment replaces the user's declared equipment inventory with the given set
+func (r *UserEquipmentRepository) SetEquipment(ctx context.Context, userId int64, equipment []string) error {
+	if err := r.Queries.UserEquipment_DeleteByUserId(ctx, userId); err != nil {
+		return err
+	}
+	for _, item := range equipment {
+		if err := r.Queries.UserEquipment_Add(ctx, db.UserEquipment_AddParams{
+			UserID:    userId,
+			Equipment: item,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+-- name: Access_GetPlanOwner :one
+SELECT user_id, is_public FROM plans WHERE id = $1;
+
+-- name: Access_GetIntervalOwner :one
+SELECT plans.user_id, plans.is_public, plans.id AS plan_id
+FROM plan_intervals
+    JOIN plans ON plans.id = plan_intervals.plan_id
+WHERE
+    plan_intervals.id = $1;
+
+-- name: Access_GetPrescriptionOwner :one
+SELECT plans.user_id, plans.is_public, plans.id AS plan_id
+FROM
+    interval_exercise_prescriptions
+    JOIN plan_intervals ON plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+    JOIN plans ON plans.id = plan_intervals.plan_id
+WHERE
+    interval_exercise_prescriptions.id = $1;
+
+-- name: Access_GetPrescriptionBlockOwner :one
+SELECT plans.user_id, plans.is_public, plans.id AS plan_id
+FROM
+    prescription_blocks
+    JOIN plan_intervals ON plan_intervals.id = prescription_blocks.plan_interval_id
+    JOIN plans ON plans.id = plan_intervals.plan_id
+WHERE
+    prescription_blocks.id = $1;
+
+-- name: Access_GetGroupOwner :one
+SELECT user_id FROM groups WHERE id = $1;
+
+-- name: Access_ListGroupPlanIds :many
+SELECT DISTINCT plan_intervals.plan_id
+FROM
+    interval_group_assignments
+    JOIN plan_intervals ON plan_intervals.id = interval_group_assignments.plan_interval_id
+WHERE
+    interval_group_assignments.group_id = $1;
+
+-- name: Access_GetProgressionRuleGroupId :one
+SELECT COALESCE(progression_rules.group_id, interval_exercise_prescriptions.group_id)::BIGINT AS group_id
+FROM
+    progression_rules
+    LEFT JOIN interval_exercise_prescriptions ON interval_exercise_prescriptions.id = progression_rules.prescription_id
+WHERE
+    progression_rules.id = $1;
+
+-- name: Access_GetExerciseOwner :one
+SELECT user_id FROM exercises WHERE id = $1;
+
+-- name: Access_GetExerciseVariationOwner :one
+SELECT exercises.user_id
+FROM exercise_variations
+    JOIN exercises ON exercises.id = exercise_variations.exercise_id
+WHERE
+    exercise_variations.id = $1;
+
+-- name: Access_GetPlanAssignmentOwner :one
+SELECT user_id FROM plan_assignments WHERE id = $1;
+
+-- name: Access_GetSessionOwner :one
+SELECT plan_assignments.user_id
+FROM scheduled_sessions
+    JOIN plan_assignments ON plan_assignments.id = scheduled_sessions.plan_assignment_id
+WHERE
+    scheduled_sessions.id = $1;
+
+-- name: Access_GetCategoryOwner :one
+SELECT user_id FROM categories WHERE id = $1;
+
+-- name: Access_ListCollaboratorPermissions :many
+SELECT plan_collaborators.permission
+FROM plan_collaborators
+WHERE
+    plan_collaborators.user_id = @user_id
+    AND plan_collaborators.plan_id = ANY(@plan_ids::BIGINT[])
+    AND NOT EXISTS (
+        SELECT 1
+        FROM plan_share_links
+        WHERE
+            plan_share_links.id = plan_collaborators.share_link_id
+            AND (
+                plan_share_links.revoked_at IS NOT NULL
+                OR plan_share_links.expires_at <= CURRENT_TIMESTAMP
+            )
+    );
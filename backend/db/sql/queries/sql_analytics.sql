@@ -0,0 +1,27 @@
+-- name: Analytics_ListPrescriptionMuscleGroups :many
+SELECT
+    interval_exercise_prescriptions.id,
+    interval_exercise_prescriptions.plan_interval_id,
+    interval_exercise_prescriptions.sets,
+    interval_exercise_prescriptions.reps,
+    COALESCE(interval_group_assignments.frequency, 1)::INTEGER AS frequency,
+    COALESCE(ARRAY(
+        SELECT COALESCE(prescription_sets.reps, interval_exercise_prescriptions.reps, 0)
+        FROM prescription_sets
+        WHERE prescription_sets.prescription_id = interval_exercise_prescriptions.id
+        ORDER BY prescription_sets.set_number
+    ), '{}')::INTEGER[] AS set_reps,
+    exercises.primary_muscle_groups,
+    exercises.secondary_muscle_groups
+FROM
+    interval_exercise_prescriptions
+    JOIN exercise_variations ON exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
+    JOIN exercises ON exercises.id = exercise_variations.exercise_id
+    JOIN plan_intervals ON plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+    LEFT JOIN interval_group_assignments ON interval_group_assignments.plan_interval_id = interval_exercise_prescriptions.plan_interval_id
+    AND interval_group_assignments.group_id = interval_exercise_prescriptions.group_id
+WHERE
+    plan_intervals.plan_id = @plan_id::BIGINT
+    AND (plan_intervals.id = @interval_id::BIGINT OR @interval_id::BIGINT = 0)
+    AND (NOT plan_intervals.is_deload OR NOT @exclude_deloads::BOOLEAN)
+ORDER BY plan_intervals."order", interval_exercise_prescriptions.id;
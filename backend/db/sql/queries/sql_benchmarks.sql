@@ -0,0 +1,43 @@
+-- name: Benchmarks_List :many
+SELECT * FROM benchmarks
+WHERE
+    user_id = @user_id::BIGINT
+    AND (exercise_variation_id = @exercise_variation_id::BIGINT or @exercise_variation_id::BIGINT = 0)
+    AND (parameter_type_id = @parameter_type_id::BIGINT or @parameter_type_id::BIGINT = 0)
+ORDER BY recorded_on DESC, id DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: Benchmarks_ListLatest :many
+SELECT DISTINCT ON (exercise_variation_id, parameter_type_id) *
+FROM benchmarks
+WHERE
+    user_id = @user_id::BIGINT
+    AND recorded_on <= @as_of::DATE
+ORDER BY exercise_variation_id, parameter_type_id, recorded_on DESC, id DESC;
+
+-- name: Benchmarks_GetById :one
+SELECT * FROM benchmarks WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: Benchmarks_CreateOne :one
+INSERT INTO
+    benchmarks (
+        user_id,
+        exercise_variation_id,
+        parameter_type_id,
+        value,
+        recorded_on
+    )
+VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: Benchmarks_UpdateOne :one
+UPDATE benchmarks
+SET
+    value = $3,
+    recorded_on = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 AND user_id = $2 RETURNING *;
+
+-- name: Benchmarks_DeleteOne :one
+DELETE FROM benchmarks WHERE id = $1 AND user_id = $2 RETURNING *;
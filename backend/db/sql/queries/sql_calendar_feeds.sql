@@ -0,0 +1,11 @@
+-- name: CalendarFeeds_ListByUserId :many
+SELECT * FROM calendar_feeds WHERE user_id = $1 ORDER BY created_at, id;
+
+-- name: CalendarFeeds_GetByToken :one
+SELECT * FROM calendar_feeds WHERE token = $1 LIMIT 1;
+
+-- name: CalendarFeeds_CreateOne :one
+INSERT INTO calendar_feeds (user_id, token) VALUES ($1, $2) RETURNING *;
+
+-- name: CalendarFeeds_DeleteOne :one
+DELETE FROM calendar_feeds WHERE id = $1 AND user_id = $2 RETURNING *;
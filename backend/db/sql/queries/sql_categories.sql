@@ -0,0 +1,60 @@
+-- name: Categories_List :many
+SELECT DISTINCT categories.* FROM categories
+LEFT JOIN exercise_categories on categories.id = exercise_categories.category_id
+WHERE
+    (categories.id = @category_id::BIGINT or @category_id::bigint = 0)
+    AND (categories.user_id = @user_id::BIGINT OR categories.user_id IS NULL OR @user_id::bigint = 0)
+    AND (exercise_categories.exercise_id = @exercise_id::BIGINT or @exercise_id::bigint = 0)
+ORDER BY categories.name
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: Categories_GetById :one
+SELECT * FROM categories WHERE id = $1 LIMIT 1;
+
+-- name: Categories_CreateOne :one
+INSERT INTO
+    categories (name, icon, user_id)
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: Categories_UpdateOne :one
+UPDATE categories
+SET
+    name = $1,
+    icon = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $3 RETURNING *;
+
+-- name: Categories_DeleteById :one
+DELETE FROM categories WHERE id = $1 RETURNING *;
+
+-- name: ExerciseCategories_Add :exec
+INSERT INTO
+    exercise_categories (exercise_id, category_id)
+VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: ExerciseCategories_Remove :exec
+DELETE FROM exercise_categories
+WHERE
+    exercise_id = $1
+    AND category_id = $2;
+
+-- name: ExerciseCategories_DeleteByExerciseId :exec
+DELETE FROM exercise_categories WHERE exercise_id = $1;
+
+-- name: ExerciseCategories_ListByExerciseIds :many
+SELECT
+    ec.exercise_id,
+    c.id,
+    c.name,
+    c.icon,
+    c.user_id,
+    c.created_at,
+    c.updated_at
+FROM
+    exercise_categories ec
+    JOIN categories c ON c.id = ec.category_id
+WHERE
+    ec.exercise_id = ANY(@exercise_ids::BIGINT[])
+ORDER BY c.name;
@@ -0,0 +1,20 @@
+-- name: ExerciseMedia_ListByExerciseId :many
+SELECT * FROM exercise_media WHERE exercise_id = $1 ORDER BY created_at, id;
+
+-- name: ExerciseMedia_GetById :one
+SELECT * FROM exercise_media WHERE id = $1 LIMIT 1;
+
+-- name: ExerciseMedia_CreateOne :one
+INSERT INTO
+    exercise_media (
+        exercise_id,
+        file_name,
+        content_type,
+        size_bytes,
+        storage_key,
+        thumbnail_key
+    )
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: ExerciseMedia_DeleteById :one
+DELETE FROM exercise_media WHERE id = $1 RETURNING *;
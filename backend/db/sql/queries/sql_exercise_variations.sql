@@ -52,3 +52,36 @@ DELETE FROM exercise_variations WHERE exercise_variations.exercise_id = $1;
 
 -- name: ExerciseVariation_DeleteParamsByExerciseId :exec
 DELETE FROM exercise_variation_params WHERE exercise_variation_id IN (SELECT id FROM exercise_variations WHERE exercise_id = $1);
+
+-- name: ExerciseVariations_ListByExerciseIds :many
+SELECT
+    ev.id,
+    ev.exercise_id,
+    ev.name,
+    evp.locked,
+    pt.name as pt_name,
+    pt.data_type as pt_data_type,
+    pt.default_unit as pt_default_unit,
+    pt.min_value as pt_min_value,
+    pt.max_value as pt_max_value
+FROM
+    exercise_variations ev
+    LEFT OUTER JOIN exercise_variation_params evp ON evp.exercise_variation_id = ev.id
+    LEFT OUTER JOIN parameter_types pt ON pt.id = evp.parameter_type_id
+WHERE
+    ev.exercise_id = ANY(@exercise_ids::BIGINT[])
+ORDER BY ev.exercise_id, ev.id, evp.id;
+
+-- name: ExerciseVariations_ListParams :many
+SELECT
+    evp.parameter_type_id,
+    pt.name,
+    pt.data_type,
+    pt.min_value,
+    pt.max_value
+FROM
+    exercise_variation_params evp
+    JOIN parameter_types pt ON pt.id = evp.parameter_type_id
+WHERE
+    evp.exercise_variation_id = $1
+ORDER BY evp.id;
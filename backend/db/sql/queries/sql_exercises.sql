@@ -1,5 +1,5 @@
 -- name: Exercises_GetByPlanId :many
-SELECT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at
+SELECT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at, exercises.primary_muscle_groups, exercises.secondary_muscle_groups, exercises.equipment, exercises.laterality
 FROM
     exercises
     JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
@@ -15,12 +15,26 @@ SELECT DISTINCT exercises.* FROM exercises
 LEFT JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
 LEFT JOIN interval_exercise_prescriptions on exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
 LEFT JOIN plan_intervals on plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+LEFT JOIN exercise_categories on exercise_categories.exercise_id = exercises.id
 WHERE
     (exercises.id = @exercise_id::BIGINT or @exercise_id::BIGINT = 0)
     AND (exercises.user_id = @user_id::BIGINT or @user_id::BIGINT = 0)
     AND (plan_intervals.plan_id = @plan_id::BIGINT or @plan_id::BIGINT = 0)
     AND (interval_exercise_prescriptions.group_id = @group_id::BIGINT or @group_id::BIGINT = 0)
     AND (plan_intervals.id = @interval_id::BIGINT or @interval_id::BIGINT = 0)
+    AND (exercise_categories.category_id = @category_id::BIGINT or @category_id::BIGINT = 0)
+    AND (
+        @muscle_group::TEXT = ''
+        OR @muscle_group::TEXT = ANY(exercises.primary_muscle_groups)
+        OR @muscle_group::TEXT = ANY(exercises.secondary_muscle_groups)
+    )
+    AND (@equipment::TEXT = '' OR @equipment::TEXT = ANY(exercises.equipment))
+    AND (exercises.laterality = @laterality::TEXT or @laterality::TEXT = '')
+    AND (
+        @visible_to::BIGINT = 0
+        OR exercises.user_id IS NULL
+        OR exercises.user_id = @visible_to::BIGINT
+    )
 ORDER BY exercises.created_at DESC
 LIMIT @_limit::int
 OFFSET @_offset::int;
@@ -38,20 +52,44 @@ SELECT * FROM exercises WHERE id = $1 LIMIT 1;
 
 -- name: Exercises_CreateOne :one
 INSERT INTO
-    exercises (name, description, user_id)
-VALUES ($1, $2, $3) RETURNING *;
+    exercises (
+        name,
+        description,
+        user_id,
+        primary_muscle_groups,
+        secondary_muscle_groups,
+        equipment,
+        laterality
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
 
 -- name: Exercises_UpdateOne :one
 UPDATE exercises
 SET
     name = $1,
     description = $2,
+    primary_muscle_groups = $3,
+    secondary_muscle_groups = $4,
+    equipment = $5,
+    laterality = $6,
     updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = $3 RETURNING *;
+    id = $7 RETURNING *;
 
 -- name: Exercises_DeleteOne :exec
 DELETE FROM exercises WHERE id = $1;
 
 -- name: ExerciseVariations_DeleteOne :exec
 DELETE FROM exercise_variations WHERE id = $1;
+
+-- name: Exercises_ListEquipmentByPlanId :many
+SELECT DISTINCT exercises.id, exercises.name, exercises.equipment
+FROM
+    exercises
+    JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
+    JOIN interval_exercise_prescriptions on exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
+    JOIN plan_intervals on plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+WHERE
+    plan_intervals.plan_id = $1
+    AND cardinality(exercises.equipment) > 0
+ORDER BY exercises.id;
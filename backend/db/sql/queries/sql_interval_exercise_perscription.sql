@@ -11,7 +11,8 @@ INSERT INTO
         sub_reps,
         sub_rep_work_duration,
         sub_rep_rest_duration,
-        rest
+        rest,
+        position
     )
 VALUES (
         @group_id::BIGINT,
@@ -24,9 +25,28 @@ VALUES (
         sqlc.narg(sub_reps),
         sqlc.narg(sub_rep_work_duration),
         sqlc.narg(sub_rep_rest_duration),
-        sqlc.narg(rest)
+        sqlc.narg(rest),
+        @position::INT
     ) RETURNING *;
 
+-- name: IntervalExercisePrescriptions_GetById :one
+SELECT * FROM interval_exercise_prescriptions WHERE id = $1 LIMIT 1;
+
+-- name: IntervalExercisePrescriptions_UpdateOne :one
+UPDATE interval_exercise_prescriptions
+SET
+    exercise_variation_id = @variation_id::BIGINT,
+    rpe = sqlc.narg(rpe),
+    sets = @sets::INT,
+    reps = sqlc.narg(reps),
+    duration = sqlc.narg(duration),
+    sub_reps = sqlc.narg(sub_reps),
+    sub_rep_work_duration = sqlc.narg(sub_rep_work_duration),
+    sub_rep_rest_duration = sqlc.narg(sub_rep_rest_duration),
+    rest = sqlc.narg(rest)
+WHERE
+    id = @id::BIGINT RETURNING *;
+
 -- name: IntervalExercisePrescriptions_DeleteOne :exec
 DELETE FROM interval_exercise_prescriptions WHERE id = $1;
 
@@ -43,7 +63,9 @@ SELECT
     iep.sub_reps,
     iep.sub_rep_work_duration,
     iep.sub_rep_rest_duration,
-    iep.rest
+    iep.rest,
+    iep.position,
+    iep.block_id
 FROM
     interval_exercise_prescriptions iep
 WHERE
@@ -51,6 +73,7 @@ WHERE
     AND (iep.id = @prescription_id::BIGINT or @prescription_id::bigint = 0)
     AND (iep.exercise_variation_id = @variation_id::BIGINT or @variation_id::bigint = 0)
     AND (iep.plan_interval_id = @interval_id::BIGINT or @interval_id::bigint = 0)
+ORDER BY iep.plan_interval_id, iep.group_id, iep.position, iep.id
 LIMIT @_limit::int
 OFFSET @_offset::int;
 
@@ -68,6 +91,8 @@ SELECT
     iep.sub_rep_work_duration,
     iep.sub_rep_rest_duration,
     iep.rest,
+    iep.position,
+    iep.block_id,
     -- Exercise Variation details
     ev.id as ev_id,
     ev.exercise_id as ev_exercise_id,
@@ -100,9 +125,22 @@ WHERE
     AND (iep.id = @prescription_id::BIGINT or @prescription_id::bigint = 0)
     AND (iep.exercise_variation_id = @variation_id::BIGINT or @variation_id::bigint = 0)
     AND (iep.plan_interval_id = @interval_id::BIGINT or @interval_id::bigint = 0)
-ORDER BY iep.id, evp.id
+ORDER BY iep.plan_interval_id, iep.group_id, iep.position, iep.id, evp.id
 LIMIT @_limit::int
 OFFSET @_offset::int;
 
+-- name: IntervalExercisePrescriptions_NextPosition :one
+SELECT (COALESCE(MAX(position), -1) + 1)::INTEGER AS next_position
+FROM interval_exercise_prescriptions
+WHERE
+    group_id = @group_id::BIGINT
+    AND plan_interval_id = @interval_id::BIGINT;
+
+-- name: IntervalExercisePrescriptions_UpdatePosition :exec
+UPDATE interval_exercise_prescriptions SET position = @position::INT WHERE id = @id::BIGINT;
+
+-- name: IntervalExercisePrescriptions_SetBlock :exec
+UPDATE interval_exercise_prescriptions SET block_id = sqlc.narg(block_id) WHERE id = @id::BIGINT;
+
 -- name: IntervalExercisePrescription_DeleteByExerciseId :exec
 DELETE FROM interval_exercise_prescriptions WHERE exercise_variation_id IN (SELECT id FROM exercise_variations WHERE exercise_id = @exercise_id::BIGINT);
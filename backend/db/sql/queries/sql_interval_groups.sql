@@ -3,9 +3,21 @@ INSERT INTO
     interval_group_assignments (
         plan_interval_id,
         group_id,
-        frequency
+        frequency,
+        pinned_days,
+        "order"
     )
-VALUES ($1, $2, $3) RETURNING *;
+VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: IntervalGroupAssignments_Update :one
+UPDATE interval_group_assignments
+SET
+    frequency = $1,
+    pinned_days = $2,
+    "order" = $3
+WHERE
+    plan_interval_id = $4
+    AND group_id = $5 RETURNING *;
 
 -- name: IntervalGroupAssignments_Delete :exec
 DELETE FROM interval_group_assignments
@@ -13,6 +25,19 @@ WHERE
     plan_interval_id = $1
     AND group_id = $2;
 
+-- name: IntervalGroupAssignments_GetOne :one
+SELECT * FROM interval_group_assignments
+WHERE
+    plan_interval_id = $1
+    AND group_id = $2
+LIMIT 1;
+
+-- name: IntervalGroupAssignments_NextOrder :one
+SELECT (COALESCE(MAX("order"), -1) + 1)::INTEGER AS next_order
+FROM interval_group_assignments
+WHERE
+    plan_interval_id = $1;
+
 -- name: IntervalGroupAssignments_GetByIntervalId :many
 SELECT
     iga.*,
@@ -26,7 +51,8 @@ FROM
     interval_group_assignments iga
     JOIN groups g ON g.id = iga.group_id
 WHERE
-    iga.plan_interval_id = $1;
+    iga.plan_interval_id = $1
+ORDER BY iga."order", iga.id;
 
 -- name: IntervalGroupAssignments_GetByGroupId :many
 SELECT
@@ -42,4 +68,5 @@ FROM
     interval_group_assignments iga
     JOIN plan_intervals pi ON pi.id = iga.plan_interval_id
 WHERE
-    iga.group_id = $1;
\ No newline at end of file
+    iga.group_id = $1
+ORDER BY pi.plan_id, pi.order, iga.id;
\ No newline at end of file
@@ -41,5 +41,13 @@ INSERT INTO
     )
 VALUES ($1, $2, $3, $4, $5) RETURNING *;
 
+-- name: PlanIntervals_SetDeload :one
+UPDATE plan_intervals
+SET
+    is_deload = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
 -- name: PlanIntervals_DeleteById :one
 DELETE FROM plan_intervals WHERE id = $1 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,53 @@
+-- name: LogbookEntries_List :many
+SELECT * FROM logbook_entries
+WHERE
+    user_id = @user_id::BIGINT
+    AND (discipline = @discipline::TEXT or @discipline::TEXT = '')
+    AND (style = @style::TEXT or @style::TEXT = '')
+    AND (scheduled_session_id = @scheduled_session_id::BIGINT or @scheduled_session_id::BIGINT = 0)
+    AND (outdoor = sqlc.narg(outdoor)::BOOLEAN or sqlc.narg(outdoor)::BOOLEAN IS NULL)
+    AND (climbed_on >= sqlc.narg(from_date)::DATE or sqlc.narg(from_date)::DATE IS NULL)
+    AND (climbed_on <= sqlc.narg(to_date)::DATE or sqlc.narg(to_date)::DATE IS NULL)
+ORDER BY climbed_on DESC, id DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: LogbookEntries_GetById :one
+SELECT * FROM logbook_entries WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: LogbookEntries_CreateOne :one
+INSERT INTO
+    logbook_entries (
+        user_id,
+        scheduled_session_id,
+        name,
+        discipline,
+        grade_system,
+        grade,
+        grade_index,
+        style,
+        attempts,
+        outdoor,
+        climbed_on
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
+
+-- name: LogbookEntries_UpdateOne :one
+UPDATE logbook_entries
+SET
+    scheduled_session_id = $3,
+    name = $4,
+    discipline = $5,
+    grade_system = $6,
+    grade = $7,
+    grade_index = $8,
+    style = $9,
+    attempts = $10,
+    outdoor = $11,
+    climbed_on = $12,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 AND user_id = $2 RETURNING *;
+
+-- name: LogbookEntries_DeleteOne :one
+DELETE FROM logbook_entries WHERE id = $1 AND user_id = $2 RETURNING *;
@@ -20,3 +20,8 @@ INSERT INTO
         max_value
     )
 VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UserParameterTypes_Add :exec
+INSERT INTO
+    user_parameter_types (user_id, parameter_type_id)
+VALUES (@user_id::BIGINT, @parameter_type_id::BIGINT) ON CONFLICT DO NOTHING;
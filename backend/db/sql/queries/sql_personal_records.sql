@@ -0,0 +1,39 @@
+-- name: PersonalRecords_List :many
+SELECT * FROM personal_records
+WHERE
+    (user_id = @user_id::BIGINT or @user_id::BIGINT = 0)
+    AND (exercise_variation_id = @exercise_variation_id::BIGINT or @exercise_variation_id::BIGINT = 0)
+    AND (parameter_type_id = @parameter_type_id::BIGINT or @parameter_type_id::BIGINT = 0)
+    AND (kind = @kind::TEXT or @kind::TEXT = '')
+    AND (
+        NOT @current_only::BOOLEAN
+        OR NOT EXISTS (
+            SELECT 1 FROM personal_records newer
+            WHERE
+                newer.user_id = personal_records.user_id
+                AND newer.exercise_variation_id = personal_records.exercise_variation_id
+                AND newer.parameter_type_id = personal_records.parameter_type_id
+                AND newer.kind = personal_records.kind
+                AND newer.load IS NOT DISTINCT FROM personal_records.load
+                AND newer.id > personal_records.id
+        )
+    )
+ORDER BY achieved_on DESC, id DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: PersonalRecords_CreateOne :one
+INSERT INTO
+    personal_records (
+        user_id,
+        exercise_variation_id,
+        parameter_type_id,
+        kind,
+        value,
+        previous_value,
+        load,
+        reps,
+        session_set_id,
+        achieved_on
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
@@ -0,0 +1,38 @@
+-- name: PlanAssignments_List :many
+SELECT * FROM plan_assignments
+WHERE
+    (plan_id = @plan_id::BIGINT or @plan_id::BIGINT = 0)
+    AND (user_id = @user_id::BIGINT or @user_id::BIGINT = 0)
+ORDER BY start_date, id
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: PlanAssignments_GetById :one
+SELECT * FROM plan_assignments WHERE id = $1 LIMIT 1;
+
+-- name: PlanAssignments_GetByPlanAndUser :one
+SELECT * FROM plan_assignments WHERE plan_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: PlanAssignments_CreateOne :one
+INSERT INTO
+    plan_assignments (
+        plan_id,
+        user_id,
+        start_date,
+        training_days,
+        min_rest_days
+    )
+VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: PlanAssignments_UpdateOne :one
+UPDATE plan_assignments
+SET
+    start_date = $1,
+    training_days = $2,
+    min_rest_days = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4 RETURNING *;
+
+-- name: PlanAssignments_DeleteOne :exec
+DELETE FROM plan_assignments WHERE id = $1;
@@ -0,0 +1,170 @@
+-- name: PlanComments_Create :one
+INSERT INTO
+    plan_comments (
+        plan_id,
+        plan_interval_id,
+        group_id,
+        prescription_id,
+        parent_id,
+        user_id,
+        body
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: PlanComments_Get :one
+SELECT
+    plan_comments.*,
+    users.first_name AS author_first_name,
+    users.last_name AS author_last_name
+FROM plan_comments
+    JOIN users ON users.id = plan_comments.user_id
+WHERE
+    plan_comments.id = $1;
+
+-- name: PlanComments_ListThreads :many
+SELECT
+    plan_comments.*,
+    users.first_name AS author_first_name,
+    users.last_name AS author_last_name
+FROM plan_comments
+    JOIN users ON users.id = plan_comments.user_id
+WHERE
+    plan_comments.plan_id = @plan_id::BIGINT
+    AND plan_comments.parent_id IS NULL
+    AND (
+        sqlc.narg(plan_interval_id)::BIGINT IS NULL
+        OR plan_comments.plan_interval_id = sqlc.narg(plan_interval_id)::BIGINT
+    )
+    AND (
+        sqlc.narg(group_id)::BIGINT IS NULL
+        OR plan_comments.group_id = sqlc.narg(group_id)::BIGINT
+    )
+    AND (
+        sqlc.narg(prescription_id)::BIGINT IS NULL
+        OR plan_comments.prescription_id = sqlc.narg(prescription_id)::BIGINT
+    )
+    AND (
+        sqlc.narg(resolved)::BOOLEAN IS NULL
+        OR (plan_comments.resolved_at IS NOT NULL) = sqlc.narg(resolved)::BOOLEAN
+    )
+ORDER BY plan_comments.created_at, plan_comments.id
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: PlanComments_ListReplies :many
+SELECT
+    plan_comments.*,
+    users.first_name AS author_first_name,
+    users.last_name AS author_last_name
+FROM plan_comments
+    JOIN users ON users.id = plan_comments.user_id
+WHERE
+    plan_comments.parent_id = ANY(@parent_ids::BIGINT[])
+ORDER BY plan_comments.created_at, plan_comments.id;
+
+-- name: PlanComments_Update :one
+UPDATE plan_comments
+SET
+    body = $2,
+    edited_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
+-- name: PlanComments_SetResolved :one
+UPDATE plan_comments
+SET
+    resolved_at = CASE
+        WHEN @resolved::BOOLEAN THEN COALESCE(resolved_at, CURRENT_TIMESTAMP)
+    END,
+    resolved_by = CASE
+        WHEN @resolved::BOOLEAN THEN sqlc.narg(resolved_by)::BIGINT
+    END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = @id::BIGINT RETURNING *;
+
+-- name: PlanComments_Delete :exec
+DELETE FROM plan_comments WHERE id = $1;
+
+-- name: PlanComments_ListMembers :many
+SELECT users.id, users.email, users.first_name, users.last_name
+FROM users
+WHERE
+    users.id = (
+        SELECT plans.user_id
+        FROM plans
+        WHERE
+            plans.id = @plan_id::BIGINT
+    )
+    OR users.id IN (
+        SELECT plan_collaborators.user_id
+        FROM plan_collaborators
+        WHERE
+            plan_collaborators.plan_id = @plan_id::BIGINT
+    )
+ORDER BY users.id;
+
+-- name: PlanComments_ClearMentions :exec
+DELETE FROM plan_comment_mentions WHERE comment_id = $1;
+
+-- name: PlanComments_AddMention :exec
+INSERT INTO
+    plan_comment_mentions (comment_id, user_id)
+VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: PlanComments_ListMentions :many
+SELECT
+    plan_comment_mentions.comment_id,
+    users.id AS user_id,
+    users.first_name,
+    users.last_name
+FROM plan_comment_mentions
+    JOIN users ON users.id = plan_comment_mentions.user_id
+WHERE
+    plan_comment_mentions.comment_id = ANY(@comment_ids::BIGINT[])
+ORDER BY plan_comment_mentions.comment_id, users.id;
+
+-- name: PlanComments_CountByPlan :many
+SELECT
+    plan_id AS target_id,
+    COUNT(*)::INT AS comment_count,
+    (COUNT(*) FILTER (WHERE parent_id IS NULL AND resolved_at IS NULL))::INT AS unresolved_thread_count
+FROM plan_comments
+WHERE
+    plan_id = ANY(@ids::BIGINT[])
+GROUP BY plan_id;
+
+-- name: PlanComments_CountByInterval :many
+SELECT
+    plan_interval_id::BIGINT AS target_id,
+    COUNT(*)::INT AS comment_count,
+    (COUNT(*) FILTER (WHERE parent_id IS NULL AND resolved_at IS NULL))::INT AS unresolved_thread_count
+FROM plan_comments
+WHERE
+    plan_interval_id = ANY(@ids::BIGINT[])
+GROUP BY plan_interval_id;
+
+-- name: PlanComments_CountByGroup :many
+SELECT
+    group_id::BIGINT AS target_id,
+    COUNT(*)::INT AS comment_count,
+    (COUNT(*) FILTER (WHERE parent_id IS NULL AND resolved_at IS NULL))::INT AS unresolved_thread_count
+FROM plan_comments
+WHERE
+    group_id = ANY(@ids::BIGINT[])
+    AND (
+        sqlc.narg(plan_id)::BIGINT IS NULL
+        OR plan_id = sqlc.narg(plan_id)::BIGINT
+    )
+GROUP BY group_id;
+
+-- name: PlanComments_CountByPrescription :many
+SELECT
+    prescription_id::BIGINT AS target_id,
+    COUNT(*)::INT AS comment_count,
+    (COUNT(*) FILTER (WHERE parent_id IS NULL AND resolved_at IS NULL))::INT AS unresolved_thread_count
+FROM plan_comments
+WHERE
+    prescription_id = ANY(@ids::BIGINT[])
+GROUP BY prescription_id;
@@ -0,0 +1,75 @@
+-- name: PlanCollaborators_List :many
+SELECT plan_collaborators.*, users.email, users.first_name, users.last_name
+FROM plan_collaborators
+    JOIN users ON users.id = plan_collaborators.user_id
+WHERE
+    plan_collaborators.plan_id = $1
+    AND NOT EXISTS (
+        SELECT 1
+        FROM plan_share_links
+        WHERE
+            plan_share_links.id = plan_collaborators.share_link_id
+            AND (
+                plan_share_links.revoked_at IS NOT NULL
+                OR plan_share_links.expires_at <= CURRENT_TIMESTAMP
+            )
+    )
+ORDER BY plan_collaborators.created_at, plan_collaborators.user_id;
+
+-- name: PlanCollaborators_Get :one
+SELECT * FROM plan_collaborators WHERE plan_id = $1 AND user_id = $2;
+
+-- name: PlanCollaborators_Upsert :one
+INSERT INTO
+    plan_collaborators (
+        plan_id,
+        user_id,
+        permission,
+        share_link_id
+    )
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (plan_id, user_id) DO
+UPDATE
+SET
+    permission = EXCLUDED.permission,
+    share_link_id = EXCLUDED.share_link_id,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING
+    *;
+
+-- name: PlanCollaborators_Delete :one
+DELETE FROM plan_collaborators
+WHERE
+    plan_id = $1
+    AND user_id = $2
+RETURNING
+    *;
+
+-- name: PlanShareLinks_Create :one
+INSERT INTO
+    plan_share_links (
+        plan_id,
+        token,
+        permission,
+        created_by,
+        expires_at
+    )
+VALUES ($1, $2, $3, $4, $5)
+RETURNING
+    *;
+
+-- name: PlanShareLinks_ListByPlanId :many
+SELECT * FROM plan_share_links WHERE plan_id = $1 ORDER BY created_at DESC, id DESC;
+
+-- name: PlanShareLinks_GetByToken :one
+SELECT * FROM plan_share_links WHERE token = $1 LIMIT 1;
+
+-- name: PlanShareLinks_Revoke :one
+UPDATE plan_share_links
+SET
+    revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+WHERE
+    id = $1
+    AND plan_id = $2
+RETURNING
+    *;
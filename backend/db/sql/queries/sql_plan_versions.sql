@@ -0,0 +1,81 @@
+-- name: PlanVersions_Create :one
+INSERT INTO
+    plan_versions (
+        plan_id,
+        number,
+        document,
+        created_by,
+        restored_from
+    )
+SELECT @plan_id::BIGINT, COALESCE(MAX(number), 0) + 1, @document::JSONB, sqlc.narg(created_by)::BIGINT, sqlc.narg(restored_from)::INT
+FROM plan_versions
+WHERE
+    plan_id = @plan_id::BIGINT
+RETURNING *;
+
+-- name: PlanVersions_List :many
+SELECT
+    plan_versions.id,
+    plan_versions.plan_id,
+    plan_versions.number,
+    plan_versions.created_by,
+    plan_versions.restored_from,
+    plan_versions.created_at,
+    users.first_name AS created_by_first_name,
+    users.last_name AS created_by_last_name
+FROM plan_versions
+    LEFT JOIN users ON users.id = plan_versions.created_by
+WHERE
+    plan_versions.plan_id = @plan_id::BIGINT
+ORDER BY plan_versions.number DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: PlanVersions_Get :one
+SELECT * FROM plan_versions WHERE plan_id = $1 AND number = $2;
+
+-- name: PlanVersions_GetLatest :one
+SELECT * FROM plan_versions WHERE plan_id = $1 ORDER BY number DESC LIMIT 1;
+
+-- name: PlanVersions_RestorePlan :one
+UPDATE plans
+SET
+    name = $2,
+    description = $3,
+    is_template = $4,
+    is_public = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
+-- name: PlanVersions_CreateInterval :one
+INSERT INTO
+    plan_intervals (
+        plan_id,
+        name,
+        description,
+        duration,
+        "order",
+        is_deload
+    )
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: PlanVersions_RestoreInterval :one
+UPDATE plan_intervals
+SET
+    name = $2,
+    description = $3,
+    duration = $4,
+    is_deload = $5,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
+-- name: PlanVersions_ClearIntervalBlocks :exec
+DELETE FROM prescription_blocks WHERE plan_interval_id = $1;
+
+-- name: PlanVersions_ClearIntervalPrescriptions :exec
+DELETE FROM interval_exercise_prescriptions WHERE plan_interval_id = $1;
+
+-- name: PlanVersions_ClearIntervalAssignments :exec
+DELETE FROM interval_group_assignments WHERE plan_interval_id = $1;
@@ -26,9 +26,10 @@ INSERT INTO
         description,
         user_id,
         is_template,
-        is_public
+        is_public,
+        start_date
     )
-VALUES ($1, $2, $3, $4, $5) RETURNING *;
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
 
 -- name: Plans_UpdateOne :one
 UPDATE plans
@@ -37,9 +38,51 @@ SET
     description = $2,
     is_template = $3,
     is_public = $4,
+    start_date = $5,
     updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = $5 RETURNING *;
+    id = $6 RETURNING *;
 
 -- name: Plans_DeleteById :one
-DELETE FROM plans WHERE id = $1 RETURNING *;
\ No newline at end of file
+DELETE FROM plans WHERE id = $1 RETURNING *;
+
+-- name: Plans_SetSourcePlanId :one
+UPDATE plans SET source_plan_id = $1 WHERE id = $2 RETURNING *;
+
+-- name: Plans_ListBySourcePlanId :many
+SELECT * FROM plans WHERE source_plan_id = $1 ORDER BY user_id, id;
+
+-- name: Plans_ListAccessible :many
+SELECT plans.*, plan_collaborators.permission AS shared_permission
+FROM plans
+    LEFT JOIN plan_collaborators ON plan_collaborators.plan_id = plans.id
+    AND plan_collaborators.user_id = @user_id::BIGINT
+    AND NOT EXISTS (
+        SELECT 1
+        FROM plan_share_links
+        WHERE
+            plan_share_links.id = plan_collaborators.share_link_id
+            AND (
+                plan_share_links.revoked_at IS NOT NULL
+                OR plan_share_links.expires_at <= CURRENT_TIMESTAMP
+            )
+    )
+WHERE (
+        plans.user_id = @user_id::BIGINT
+        OR plan_collaborators.user_id IS NOT NULL
+    )
+    AND (
+        plans.is_template = sqlc.narg(is_template)
+        OR sqlc.narg(is_template) IS NULL
+    )
+    AND (
+        plans.is_public = sqlc.narg(is_public)
+        OR sqlc.narg(is_public) IS NULL
+    )
+    AND (
+        (plan_collaborators.user_id IS NOT NULL) = sqlc.narg(shared)::BOOLEAN
+        OR sqlc.narg(shared) IS NULL
+    )
+ORDER BY plans.updated_at DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
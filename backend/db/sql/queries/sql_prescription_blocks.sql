@@ -0,0 +1,33 @@
+-- name: PrescriptionBlocks_List :many
+SELECT * FROM prescription_blocks
+WHERE
+    (group_id = @group_id::BIGINT or @group_id::BIGINT = 0)
+    AND (plan_interval_id = @interval_id::BIGINT or @interval_id::BIGINT = 0)
+ORDER BY plan_interval_id, group_id, id
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: PrescriptionBlocks_GetById :one
+SELECT * FROM prescription_blocks WHERE id = $1 LIMIT 1;
+
+-- name: PrescriptionBlocks_CreateOne :one
+INSERT INTO
+    prescription_blocks (
+        group_id,
+        plan_interval_id,
+        kind,
+        rest
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: PrescriptionBlocks_UpdateOne :one
+UPDATE prescription_blocks
+SET
+    kind = $1,
+    rest = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $3 RETURNING *;
+
+-- name: PrescriptionBlocks_DeleteOne :exec
+DELETE FROM prescription_blocks WHERE id = $1;
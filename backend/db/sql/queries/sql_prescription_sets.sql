@@ -0,0 +1,39 @@
+-- name: PrescriptionSets_ListByPrescriptionIds :many
+SELECT * FROM prescription_sets
+WHERE
+    prescription_id = ANY(@prescription_ids::BIGINT[])
+ORDER BY prescription_id, set_number;
+
+-- name: PrescriptionSets_ListParametersByPrescriptionIds :many
+SELECT psp.*
+FROM
+    prescription_set_parameters psp
+    JOIN prescription_sets ps ON ps.id = psp.prescription_set_id
+WHERE
+    ps.prescription_id = ANY(@prescription_ids::BIGINT[])
+ORDER BY psp.prescription_set_id, psp.parameter_type_id;
+
+-- name: PrescriptionSets_CreateOne :one
+INSERT INTO
+    prescription_sets (
+        prescription_id,
+        set_number,
+        reps,
+        duration,
+        rpe,
+        rest
+    )
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: PrescriptionSets_CreateParameter :exec
+INSERT INTO
+    prescription_set_parameters (
+        prescription_set_id,
+        parameter_type_id,
+        value,
+        is_percentage
+    )
+VALUES ($1, $2, $3, $4);
+
+-- name: PrescriptionSets_DeleteByPrescriptionId :exec
+DELETE FROM prescription_sets WHERE prescription_id = $1;
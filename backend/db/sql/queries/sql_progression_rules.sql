@@ -0,0 +1,57 @@
+-- name: ProgressionRules_List :many
+SELECT * FROM progression_rules
+WHERE
+    (prescription_id = @prescription_id::BIGINT or @prescription_id::BIGINT = 0)
+    AND (group_id = @group_id::BIGINT or @group_id::BIGINT = 0)
+ORDER BY id
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: ProgressionRules_ListForPrescriptions :many
+SELECT * FROM progression_rules
+WHERE
+    prescription_id = ANY(@prescription_ids::BIGINT[])
+    OR group_id = ANY(@group_ids::BIGINT[])
+ORDER BY id;
+
+-- name: ProgressionRules_GetById :one
+SELECT * FROM progression_rules WHERE id = $1 LIMIT 1;
+
+-- name: ProgressionRules_CreateOne :one
+INSERT INTO
+    progression_rules (
+        prescription_id,
+        group_id
+    )
+VALUES ($1, $2) RETURNING *;
+
+-- name: ProgressionRules_Touch :one
+UPDATE progression_rules
+SET
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING *;
+
+-- name: ProgressionRules_DeleteOne :exec
+DELETE FROM progression_rules WHERE id = $1;
+
+-- name: ProgressionRuleSteps_ListByRuleIds :many
+SELECT * FROM progression_rule_steps
+WHERE rule_id = ANY(@rule_ids::BIGINT[])
+ORDER BY rule_id, position;
+
+-- name: ProgressionRuleSteps_CreateOne :one
+INSERT INTO
+    progression_rule_steps (
+        rule_id,
+        position,
+        target,
+        parameter_type_id,
+        amount,
+        unit,
+        until_value
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: ProgressionRuleSteps_DeleteByRuleId :exec
+DELETE FROM progression_rule_steps WHERE rule_id = $1;
@@ -0,0 +1,63 @@
+-- name: ReadinessCheckins_List :many
+SELECT * FROM readiness_checkins
+WHERE
+    user_id = @user_id::BIGINT
+    AND (checked_on >= sqlc.narg(from_date)::DATE or sqlc.narg(from_date)::DATE IS NULL)
+    AND (checked_on <= sqlc.narg(to_date)::DATE or sqlc.narg(to_date)::DATE IS NULL)
+ORDER BY checked_on DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: ReadinessCheckins_GetByDate :one
+SELECT * FROM readiness_checkins WHERE user_id = $1 AND checked_on = $2 LIMIT 1;
+
+-- name: ReadinessCheckins_Upsert :one
+INSERT INTO
+    readiness_checkins (
+        user_id,
+        checked_on,
+        sleep_hours,
+        finger_pain,
+        mood,
+        resting_hr
+    )
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, checked_on) DO UPDATE
+SET
+    sleep_hours = EXCLUDED.sleep_hours,
+    finger_pain = EXCLUDED.finger_pain,
+    mood = EXCLUDED.mood,
+    resting_hr = EXCLUDED.resting_hr,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ReadinessCheckins_DeleteByDate :one
+DELETE FROM readiness_checkins WHERE user_id = $1 AND checked_on = $2 RETURNING *;
+
+-- name: ReadinessSoreness_ListByCheckinIds :many
+SELECT * FROM readiness_soreness
+WHERE checkin_id = ANY(@checkin_ids::BIGINT[])
+ORDER BY checkin_id, body_area;
+
+-- name: ReadinessSoreness_Create :exec
+INSERT INTO readiness_soreness (checkin_id, body_area, score) VALUES ($1, $2, $3);
+
+-- name: ReadinessSoreness_DeleteByCheckinId :exec
+DELETE FROM readiness_soreness WHERE checkin_id = $1;
+
+-- name: Readiness_ListIntervalGroupExercises :many
+SELECT
+    interval_group_assignments.group_id,
+    groups.name,
+    exercises.primary_muscle_groups,
+    exercises.equipment
+FROM
+    interval_group_assignments
+    JOIN groups ON groups.id = interval_group_assignments.group_id
+    JOIN interval_exercise_prescriptions ON interval_exercise_prescriptions.plan_interval_id = interval_group_assignments.plan_interval_id
+    AND interval_exercise_prescriptions.group_id = interval_group_assignments.group_id
+    JOIN exercise_variations ON exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
+    JOIN exercises ON exercises.id = exercise_variations.exercise_id
+WHERE
+    interval_group_assignments.plan_interval_id = $1
+ORDER BY interval_group_assignments."order", interval_group_assignments.group_id, interval_exercise_prescriptions.id;
@@ -0,0 +1,74 @@
+-- name: ScheduledSessions_List :many
+SELECT
+    scheduled_sessions.*,
+    groups.name AS group_name,
+    plan_intervals.name AS interval_name,
+    plans.name AS plan_name,
+    plan_assignments.user_id
+FROM
+    scheduled_sessions
+    JOIN groups ON groups.id = scheduled_sessions.group_id
+    JOIN plan_intervals ON plan_intervals.id = scheduled_sessions.plan_interval_id
+    JOIN plans ON plans.id = scheduled_sessions.plan_id
+    JOIN plan_assignments ON plan_assignments.id = scheduled_sessions.plan_assignment_id
+WHERE
+    (scheduled_sessions.plan_assignment_id = @plan_assignment_id::BIGINT or @plan_assignment_id::BIGINT = 0)
+    AND (scheduled_sessions.plan_id = @plan_id::BIGINT or @plan_id::BIGINT = 0)
+    AND (plan_assignments.user_id = @user_id::BIGINT or @user_id::BIGINT = 0)
+    AND (scheduled_sessions.scheduled_date >= sqlc.narg(from_date)::DATE or sqlc.narg(from_date)::DATE IS NULL)
+    AND (scheduled_sessions.scheduled_date <= sqlc.narg(to_date)::DATE or sqlc.narg(to_date)::DATE IS NULL)
+    AND (scheduled_sessions.status = @status::TEXT or @status::TEXT = '')
+ORDER BY scheduled_sessions.scheduled_date, scheduled_sessions.id
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: ScheduledSessions_ListByUserId :many
+SELECT
+    scheduled_sessions.*,
+    groups.name AS group_name,
+    groups.description AS group_description,
+    plan_intervals.name AS interval_name,
+    plans.name AS plan_name
+FROM
+    scheduled_sessions
+    JOIN groups ON groups.id = scheduled_sessions.group_id
+    JOIN plan_intervals ON plan_intervals.id = scheduled_sessions.plan_interval_id
+    JOIN plans ON plans.id = scheduled_sessions.plan_id
+    JOIN plan_assignments ON plan_assignments.id = scheduled_sessions.plan_assignment_id
+WHERE
+    plan_assignments.user_id = $1
+ORDER BY scheduled_sessions.scheduled_date, scheduled_sessions.id;
+
+-- name: ScheduledSessions_GetById :one
+SELECT * FROM scheduled_sessions WHERE id = $1 LIMIT 1;
+
+-- name: ScheduledSessions_CreateOne :one
+INSERT INTO
+    scheduled_sessions (
+        plan_id,
+        plan_assignment_id,
+        plan_interval_id,
+        group_id,
+        scheduled_date,
+        original_date
+    )
+VALUES ($1, $2, $3, $4, $5, $5) RETURNING *;
+
+-- name: ScheduledSessions_UpdateDate :one
+UPDATE scheduled_sessions
+SET
+    scheduled_date = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2 RETURNING *;
+
+-- name: ScheduledSessions_UpdateStatus :one
+UPDATE scheduled_sessions
+SET
+    status = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2 RETURNING *;
+
+-- name: ScheduledSessions_DeletePendingByAssignmentId :exec
+DELETE FROM scheduled_sessions WHERE plan_assignment_id = $1 AND status = 'scheduled';
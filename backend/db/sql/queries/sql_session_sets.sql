@@ -0,0 +1,41 @@
+-- name: SessionSets_CreateOne :one
+INSERT INTO
+    session_sets (
+        scheduled_session_id,
+        exercise_variation_id,
+        set_number,
+        reps,
+        rpe
+    )
+VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: SessionSets_CreateParameter :exec
+INSERT INTO
+    session_set_parameters (
+        session_set_id,
+        parameter_type_id,
+        value
+    )
+VALUES ($1, $2, $3);
+
+-- name: SessionCompletions_CreateOne :one
+INSERT INTO
+    session_completions (
+        scheduled_session_id,
+        completed_on,
+        rpe,
+        duration
+    )
+VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: SessionCompletions_ListByUserId :many
+SELECT session_completions.*
+FROM
+    session_completions
+    JOIN scheduled_sessions ON scheduled_sessions.id = session_completions.scheduled_session_id
+    JOIN plan_assignments ON plan_assignments.id = scheduled_sessions.plan_assignment_id
+WHERE
+    plan_assignments.user_id = @user_id::BIGINT
+    AND session_completions.completed_on >= @from_date::DATE
+    AND session_completions.completed_on <= @to_date::DATE
+ORDER BY session_completions.completed_on, session_completions.scheduled_session_id;
@@ -0,0 +1,94 @@
+-- name: Teams_CreateOne :one
+INSERT INTO teams (name) VALUES ($1) RETURNING *;
+
+-- name: Teams_GetById :one
+SELECT * FROM teams WHERE id = $1 LIMIT 1;
+
+-- name: Teams_UpdateOne :one
+UPDATE teams SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 RETURNING *;
+
+-- name: Teams_DeleteById :one
+DELETE FROM teams WHERE id = $1 RETURNING *;
+
+-- name: Teams_ListByUserId :many
+SELECT
+    teams.id,
+    teams.name,
+    teams.created_at,
+    teams.updated_at,
+    team_members.role
+FROM teams
+    JOIN team_members ON team_members.team_id = teams.id
+WHERE
+    team_members.user_id = $1
+ORDER BY teams.name, teams.id;
+
+-- name: TeamMembers_List :many
+SELECT
+    team_members.team_id,
+    team_members.user_id,
+    team_members.role,
+    team_members.created_at,
+    users.email,
+    users.first_name,
+    users.last_name
+FROM team_members
+    JOIN users ON users.id = team_members.user_id
+WHERE
+    team_members.team_id = $1
+ORDER BY team_members.created_at, team_members.user_id;
+
+-- name: TeamMembers_Get :one
+SELECT * FROM team_members WHERE team_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: TeamMembers_Upsert :one
+INSERT INTO team_members (team_id, user_id, role)
+VALUES ($1, $2, $3)
+ON CONFLICT (team_id, user_id) DO UPDATE
+SET
+    role = EXCLUDED.role
+RETURNING *;
+
+-- name: TeamMembers_UpdateRole :one
+UPDATE team_members SET role = $1 WHERE team_id = $2 AND user_id = $3 RETURNING *;
+
+-- name: TeamMembers_Delete :one
+DELETE FROM team_members WHERE team_id = $1 AND user_id = $2 RETURNING *;
+
+-- name: TeamMembers_ListShared :many
+SELECT * FROM team_members
+WHERE
+    user_id IN (@actor_id::BIGINT, @target_id::BIGINT)
+    AND team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @actor_id::BIGINT)
+    AND team_id IN (SELECT tm.team_id FROM team_members tm WHERE tm.user_id = @target_id::BIGINT)
+ORDER BY team_id, user_id;
+
+-- name: TeamInvitations_Create :one
+INSERT INTO team_invitations (team_id, user_id, role, invited_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: TeamInvitations_GetById :one
+SELECT * FROM team_invitations WHERE id = $1 LIMIT 1;
+
+-- name: TeamInvitations_ListByTeamId :many
+SELECT * FROM team_invitations
+WHERE
+    team_id = @team_id::BIGINT
+    AND (status = sqlc.narg(status)::TEXT OR sqlc.narg(status)::TEXT IS NULL)
+ORDER BY created_at DESC, id DESC;
+
+-- name: TeamInvitations_ListByUserId :many
+SELECT * FROM team_invitations
+WHERE
+    user_id = @user_id::BIGINT
+    AND (status = sqlc.narg(status)::TEXT OR sqlc.narg(status)::TEXT IS NULL)
+ORDER BY created_at DESC, id DESC;
+
+-- name: TeamInvitations_UpdateStatus :one
+UPDATE team_invitations
+SET
+    status = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2 RETURNING *;
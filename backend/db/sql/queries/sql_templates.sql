@@ -0,0 +1,108 @@
+-- name: Templates_List :many
+SELECT
+    plans.*,
+    users.first_name AS author_first_name,
+    users.last_name AS author_last_name,
+    COALESCE(forks.fork_count, 0)::INT AS fork_count,
+    COALESCE(ratings.rating_count, 0)::INT AS rating_count,
+    COALESCE(ratings.average_rating, 0)::FLOAT8 AS average_rating
+FROM plans
+    JOIN users ON users.id = plans.user_id
+    LEFT JOIN (
+        SELECT source_plan_id, COUNT(*) AS fork_count
+        FROM plans
+        WHERE
+            source_plan_id IS NOT NULL
+        GROUP BY
+            source_plan_id
+    ) forks ON forks.source_plan_id = plans.id
+    LEFT JOIN (
+        SELECT plan_id, COUNT(*) AS rating_count, AVG(stars) AS average_rating
+        FROM plan_ratings
+        GROUP BY
+            plan_id
+    ) ratings ON ratings.plan_id = plans.id
+WHERE
+    plans.is_template
+    AND plans.is_public
+    AND (
+        plans.id = sqlc.narg(id)
+        OR sqlc.narg(id) IS NULL
+    )
+    AND (
+        plans.name ILIKE '%' || @search::TEXT || '%'
+        OR plans.description ILIKE '%' || @search::TEXT || '%'
+        OR @search::TEXT = ''
+    )
+    AND (
+        @category::TEXT = ''
+        OR EXISTS (
+            SELECT 1
+            FROM
+                interval_exercise_prescriptions iep
+                JOIN plan_intervals pi ON pi.id = iep.plan_interval_id
+                JOIN exercise_variations ev ON ev.id = iep.exercise_variation_id
+                JOIN exercise_categories ec ON ec.exercise_id = ev.exercise_id
+                JOIN categories c ON c.id = ec.category_id
+            WHERE
+                pi.plan_id = plans.id
+                AND LOWER(c.name) = LOWER(@category::TEXT)
+        )
+    )
+ORDER BY
+    CASE
+        WHEN @sort::TEXT = 'popular' THEN COALESCE(forks.fork_count, 0)
+    END DESC NULLS LAST,
+    CASE
+        WHEN @sort::TEXT = 'popular' THEN COALESCE(ratings.average_rating, 0)
+    END DESC NULLS LAST,
+    plans.updated_at DESC,
+    plans.id DESC
+LIMIT @_limit::int
+OFFSET @_offset::int;
+
+-- name: Templates_ListIntervals :many
+SELECT
+    pi.plan_id,
+    pi.id,
+    pi.name,
+    pi.duration,
+    pi."order",
+    pi.is_deload,
+    ARRAY(
+        SELECT g.name
+        FROM
+            interval_group_assignments iga
+            JOIN groups g ON g.id = iga.group_id
+        WHERE
+            iga.plan_interval_id = pi.id
+        ORDER BY iga."order", iga.id
+    )::TEXT[] AS group_names,
+    (
+        SELECT COUNT(*)
+        FROM interval_exercise_prescriptions iep
+        WHERE
+            iep.plan_interval_id = pi.id
+    )::INT AS prescription_count
+FROM plan_intervals pi
+WHERE
+    pi.plan_id = ANY(@plan_ids::BIGINT[])
+ORDER BY pi.plan_id, pi."order";
+
+-- name: PlanRatings_Upsert :one
+INSERT INTO
+    plan_ratings (plan_id, user_id, stars)
+VALUES ($1, $2, $3)
+ON CONFLICT (plan_id, user_id) DO
+UPDATE
+SET
+    stars = EXCLUDED.stars,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: PlanRatings_Delete :one
+DELETE FROM plan_ratings
+WHERE
+    plan_id = $1
+    AND user_id = $2
+RETURNING *;
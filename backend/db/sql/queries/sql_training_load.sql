@@ -0,0 +1,21 @@
+-- name: TrainingLoadThresholds_GetByUserId :one
+SELECT * FROM training_load_thresholds WHERE user_id = $1 LIMIT 1;
+
+-- name: TrainingLoadThresholds_Upsert :one
+INSERT INTO
+    training_load_thresholds (
+        user_id,
+        acwr_high,
+        acwr_low,
+        monotony,
+        strain
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET
+    acwr_high = EXCLUDED.acwr_high,
+    acwr_low = EXCLUDED.acwr_low,
+    monotony = EXCLUDED.monotony,
+    strain = EXCLUDED.strain,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
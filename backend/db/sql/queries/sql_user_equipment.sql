@@ -0,0 +1,10 @@
+-- name: UserEquipment_ListByUserId :many
+SELECT equipment FROM user_equipment WHERE user_id = $1 ORDER BY equipment;
+
+-- name: UserEquipment_Add :exec
+INSERT INTO
+    user_equipment (user_id, equipment)
+VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: UserEquipment_DeleteByUserId :exec
+DELETE FROM user_equipment WHERE user_id = $1;
@@ -31,10 +31,15 @@ CREATE TABLE IF NOT EXISTS plans (
     user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
     is_template BOOLEAN NOT NULL DEFAULT FALSE,
     is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    start_date DATE, -- the first day of the first interval; plans without one have no schedule
+    source_plan_id BIGINT REFERENCES plans (id) ON DELETE SET NULL, -- the plan this one was copied from
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Databases created before plans were scheduled gain the start date on migrate
+ALTER TABLE plans ADD COLUMN IF NOT EXISTS start_date DATE;
+
 CREATE TABLE IF NOT EXISTS plan_intervals (
     id BIGSERIAL PRIMARY KEY,
     plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
@@ -46,11 +51,15 @@ CREATE TABLE IF NOT EXISTS plan_intervals (
     ),
     duration INTERVAL NOT NULL DEFAULT '1 week',
     "order" INTEGER NOT NULL,
+    is_deload BOOLEAN NOT NULL DEFAULT FALSE, -- reduced volume and intensity to recover
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE (plan_id, "order")
 );
 
+-- Databases created before deload intervals gain the flag on migrate
+ALTER TABLE plan_intervals ADD COLUMN IF NOT EXISTS is_deload BOOLEAN NOT NULL DEFAULT FALSE;
+
 CREATE TABLE IF NOT EXISTS groups (
     id BIGSERIAL PRIMARY KEY,
     name TEXT NOT NULL CONSTRAINT groups_name_chk CHECK (
@@ -87,12 +96,46 @@ CREATE TABLE IF NOT EXISTS exercises (
     ),
     user_id BIGINT REFERENCES users (id),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    primary_muscle_groups TEXT[] NOT NULL DEFAULT '{}', -- e.g., "fingers", "lats"
+    secondary_muscle_groups TEXT[] NOT NULL DEFAULT '{}',
+    equipment TEXT[] NOT NULL DEFAULT '{}', -- e.g., "hangboard", "campus_board", "barbell", "rings"
+    laterality TEXT NOT NULL DEFAULT 'bilateral' CONSTRAINT exercise_laterality_chk CHECK (
+        laterality IN ('bilateral', 'unilateral')
+    )
+);
+
+-- Databases created before exercises carried metadata gain the columns on migrate
+ALTER TABLE exercises
+    ADD COLUMN IF NOT EXISTS primary_muscle_groups TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS secondary_muscle_groups TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS equipment TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS laterality TEXT NOT NULL DEFAULT 'bilateral' CONSTRAINT exercise_laterality_chk CHECK (
+        laterality IN ('bilateral', 'unilateral')
+    );
+
+CREATE TABLE IF NOT EXISTS exercise_categories (
+    exercise_id BIGINT NOT NULL REFERENCES exercises (id) ON DELETE CASCADE,
+    category_id BIGINT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (exercise_id, category_id)
+);
+
+CREATE TABLE IF NOT EXISTS exercise_media (
+    id BIGSERIAL PRIMARY KEY,
+    exercise_id BIGINT NOT NULL REFERENCES exercises (id) ON DELETE CASCADE,
+    file_name TEXT NOT NULL CONSTRAINT exercise_media_file_name_chk CHECK (
+        validate_length (file_name, 1, 255)
+    ),
+    content_type TEXT NOT NULL, -- sniffed from the upload, e.g. "image/jpeg", "video/mp4"
+    size_bytes BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT, -- only set for images a thumbnail could be generated for
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE TABLE IF NOT EXISTS parameter_types (
     id BIGSERIAL PRIMARY KEY,
-    "name" TEXT NOT NULL CONSTRAINT parameter_types_name_chk CHECK (validate_length ("name", 1, 255)), -- e.g., "Percentage", "Edge Depth"
+    "name" TEXT NOT NULL CONSTRAINT parameter_types_name_chk CHECK (validate_length ("name", 1, 255)), -- e.g., "Weight", "Edge Depth"; percentages of a benchmark are marked on the prescribed value
     data_type TEXT NOT NULL CONSTRAINT parameter_types_data_type_chk CHECK (validate_length (data_type, 1, 255)), -- "percentage", "length", "time", "weight"
     default_unit TEXT NOT NULL CONSTRAINT parameter_types_default_unit_chk CHECK (validate_length (default_unit, 1, 100)), -- "%", "mm", "seconds", "kg"
     min_value FLOAT, -- e.g., 0 for percentages
@@ -118,7 +161,31 @@ CREATE TABLE IF NOT EXISTS interval_group_assignments (
     id BIGSERIAL PRIMARY KEY,
     plan_interval_id BIGINT NOT NULL REFERENCES plan_intervals (id) ON DELETE CASCADE,
     group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
-    frequency INTEGER NOT NULL
+    frequency INTEGER NOT NULL CONSTRAINT interval_group_assignments_frequency_chk CHECK (frequency >= 1), -- sessions per week
+    pinned_days INTEGER[] NOT NULL DEFAULT '{}', -- weekdays the group is held on, 0 = Sunday; empty leaves it to the scheduler
+    "order" INTEGER NOT NULL DEFAULT 0 -- position of the group within its interval
+);
+
+-- Databases created before groups were pinned and ordered gain the columns on migrate;
+-- the frequency check is recreated so it is added exactly once
+ALTER TABLE interval_group_assignments
+    ADD COLUMN IF NOT EXISTS pinned_days INTEGER[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS "order" INTEGER NOT NULL DEFAULT 0,
+    DROP CONSTRAINT IF EXISTS interval_group_assignments_frequency_chk,
+    ADD CONSTRAINT interval_group_assignments_frequency_chk CHECK (frequency >= 1);
+
+-- Prescriptions of a group within an interval that are performed together: a superset
+-- alternates its exercises, a circuit cycles through them. Rest is taken after each round.
+CREATE TABLE IF NOT EXISTS prescription_blocks (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    plan_interval_id BIGINT NOT NULL REFERENCES plan_intervals (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CONSTRAINT prescription_blocks_kind_chk CHECK (
+        kind IN ('superset', 'circuit')
+    ),
+    rest INTERVAL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
 CREATE TABLE IF NOT EXISTS interval_exercise_prescriptions (
@@ -133,12 +200,399 @@ CREATE TABLE IF NOT EXISTS interval_exercise_prescriptions (
     sub_reps INTEGER,
     sub_rep_work_duration INTERVAL,
     sub_rep_rest_duration INTERVAL,
-    rest INTERVAL
+    rest INTERVAL,
+    position INTEGER NOT NULL DEFAULT 0,
+    block_id BIGINT REFERENCES prescription_blocks (id) ON DELETE SET NULL
 );
 
+-- Databases created before prescriptions were ordered and blocked gain the columns on migrate
+ALTER TABLE interval_exercise_prescriptions
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS block_id BIGINT REFERENCES prescription_blocks (id) ON DELETE SET NULL;
+
+-- Optional per-set breakdown of a prescription, e.g. a pyramid or ramping loads. Empty
+-- fields fall back to the prescription's uniform values.
+CREATE TABLE IF NOT EXISTS prescription_sets (
+    id BIGSERIAL PRIMARY KEY,
+    prescription_id BIGINT NOT NULL REFERENCES interval_exercise_prescriptions (id) ON DELETE CASCADE,
+    set_number INTEGER NOT NULL CONSTRAINT prescription_sets_set_number_chk CHECK (set_number >= 1),
+    reps INTEGER,
+    duration INTERVAL,
+    rpe INTEGER,
+    rest INTERVAL,
+    UNIQUE (prescription_id, set_number)
+);
+
+CREATE TABLE IF NOT EXISTS prescription_set_parameters (
+    prescription_set_id BIGINT NOT NULL REFERENCES prescription_sets (id) ON DELETE CASCADE,
+    parameter_type_id BIGINT NOT NULL REFERENCES parameter_types (id) ON DELETE CASCADE,
+    value FLOAT NOT NULL,
+    is_percentage BOOLEAN NOT NULL DEFAULT FALSE, -- value is a percentage of the athlete's benchmark, e.g. 80% of a 1RM
+    PRIMARY KEY (prescription_set_id, parameter_type_id)
+);
+
+-- Progression applied when an interval is copied or generated, attached to a single
+-- prescription or to every prescription of a group. A prescription's own rule wins.
+CREATE TABLE IF NOT EXISTS progression_rules (
+    id BIGSERIAL PRIMARY KEY,
+    prescription_id BIGINT UNIQUE REFERENCES interval_exercise_prescriptions (id) ON DELETE CASCADE,
+    group_id BIGINT UNIQUE REFERENCES groups (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT progression_rules_owner_chk CHECK ((prescription_id IS NULL) <> (group_id IS NULL))
+);
+
+-- Each interval applies the first step whose value has not reached its limit, e.g.
+-- "+1 rep until 8, then +5% weight"
+CREATE TABLE IF NOT EXISTS progression_rule_steps (
+    id BIGSERIAL PRIMARY KEY,
+    rule_id BIGINT NOT NULL REFERENCES progression_rules (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    target TEXT NOT NULL CONSTRAINT progression_rule_steps_target_chk CHECK (
+        target IN ('sets', 'reps', 'rpe', 'duration', 'parameter')
+    ),
+    parameter_type_id BIGINT REFERENCES parameter_types (id) ON DELETE CASCADE,
+    amount FLOAT NOT NULL,
+    unit TEXT NOT NULL DEFAULT 'absolute' CONSTRAINT progression_rule_steps_unit_chk CHECK (
+        unit IN ('absolute', 'percent')
+    ),
+    until_value FLOAT,
+    CONSTRAINT progression_rule_steps_parameter_chk CHECK ((target = 'parameter') = (parameter_type_id IS NOT NULL)),
+    UNIQUE (rule_id, position)
+);
+
+
+CREATE TABLE IF NOT EXISTS user_equipment (
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    equipment TEXT NOT NULL CONSTRAINT user_equipment_equipment_chk CHECK (
+        validate_length (equipment, 1, 100)
+    ),
+    PRIMARY KEY (user_id, equipment)
+);
 
 CREATE TABLE IF NOT EXISTS user_parameter_types (
     user_id BIGINT NOT NULL REFERENCES users (id),
     parameter_type_id BIGINT NOT NULL REFERENCES parameter_types (id),
     PRIMARY KEY (user_id, parameter_type_id)
 );
+
+-- An athlete's measured best for a variation and parameter, e.g. a 1RM deadlift or a max
+-- hang at 20mm. Benchmarks without a variation, such as bodyweight, apply to every
+-- variation. Percentage prescriptions resolve against the latest one as of a date.
+CREATE TABLE IF NOT EXISTS benchmarks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    exercise_variation_id BIGINT REFERENCES exercise_variations (id) ON DELETE CASCADE,
+    parameter_type_id BIGINT NOT NULL REFERENCES parameter_types (id) ON DELETE CASCADE,
+    value FLOAT NOT NULL,
+    recorded_on DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS benchmarks_user_id_idx ON benchmarks (user_id, parameter_type_id, recorded_on);
+
+CREATE TABLE IF NOT EXISTS plan_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    training_days INTEGER[] NOT NULL DEFAULT '{}', -- weekdays sessions may fall on, 0 = Sunday; empty allows every day
+    min_rest_days INTEGER NOT NULL DEFAULT 0 CONSTRAINT plan_assignments_min_rest_days_chk CHECK (
+        min_rest_days BETWEEN 0 AND 6
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plan_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    plan_assignment_id BIGINT NOT NULL REFERENCES plan_assignments (id) ON DELETE CASCADE,
+    plan_interval_id BIGINT NOT NULL REFERENCES plan_intervals (id) ON DELETE CASCADE,
+    group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    scheduled_date DATE NOT NULL,
+    original_date DATE NOT NULL, -- the date the schedule was generated with, kept when a session is moved
+    status TEXT NOT NULL DEFAULT 'scheduled' CONSTRAINT scheduled_sessions_status_chk CHECK (
+        status IN ('scheduled', 'completed', 'skipped')
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_sessions_plan_id_idx ON scheduled_sessions (plan_id, scheduled_date);
+
+CREATE INDEX IF NOT EXISTS scheduled_sessions_plan_assignment_id_idx ON scheduled_sessions (plan_assignment_id, scheduled_date);
+
+-- Sets performed in a session, logged when the session is completed
+CREATE TABLE IF NOT EXISTS session_sets (
+    id BIGSERIAL PRIMARY KEY,
+    scheduled_session_id BIGINT NOT NULL REFERENCES scheduled_sessions (id) ON DELETE CASCADE,
+    exercise_variation_id BIGINT NOT NULL REFERENCES exercise_variations (id) ON DELETE CASCADE,
+    set_number INTEGER NOT NULL CONSTRAINT session_sets_set_number_chk CHECK (set_number >= 1), -- counted per variation within the session
+    reps INTEGER CONSTRAINT session_sets_reps_chk CHECK (reps >= 0),
+    rpe INTEGER CONSTRAINT session_sets_rpe_chk CHECK (rpe BETWEEN 1 AND 10),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (scheduled_session_id, exercise_variation_id, set_number)
+);
+
+CREATE TABLE IF NOT EXISTS session_set_parameters (
+    session_set_id BIGINT NOT NULL REFERENCES session_sets (id) ON DELETE CASCADE,
+    parameter_type_id BIGINT NOT NULL REFERENCES parameter_types (id) ON DELETE CASCADE,
+    value FLOAT NOT NULL,
+    PRIMARY KEY (session_set_id, parameter_type_id)
+);
+
+-- How a completed session went. Its training load is the session RPE times its duration
+-- in minutes.
+CREATE TABLE IF NOT EXISTS session_completions (
+    scheduled_session_id BIGINT PRIMARY KEY REFERENCES scheduled_sessions (id) ON DELETE CASCADE,
+    completed_on DATE NOT NULL,
+    rpe INTEGER CONSTRAINT session_completions_rpe_chk CHECK (rpe BETWEEN 1 AND 10),
+    duration INTERVAL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An athlete's training load risk thresholds, set by their coach. Defaults apply
+-- without a row.
+CREATE TABLE IF NOT EXISTS training_load_thresholds (
+    user_id BIGINT PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    acwr_high FLOAT NOT NULL,
+    acwr_low FLOAT NOT NULL,
+    monotony FLOAT NOT NULL,
+    strain FLOAT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- An athlete's daily readiness check-in, one per day. Every measure is optional.
+CREATE TABLE IF NOT EXISTS readiness_checkins (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    checked_on DATE NOT NULL,
+    sleep_hours FLOAT CONSTRAINT readiness_checkins_sleep_hours_chk CHECK (sleep_hours BETWEEN 0 AND 24),
+    finger_pain INTEGER CONSTRAINT readiness_checkins_finger_pain_chk CHECK (finger_pain BETWEEN 0 AND 10),
+    mood INTEGER CONSTRAINT readiness_checkins_mood_chk CHECK (mood BETWEEN 1 AND 5),
+    resting_hr INTEGER CONSTRAINT readiness_checkins_resting_hr_chk CHECK (resting_hr BETWEEN 20 AND 250),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, checked_on)
+);
+
+-- Soreness of a check-in by body area, from 0 (none) to 10
+CREATE TABLE IF NOT EXISTS readiness_soreness (
+    checkin_id BIGINT NOT NULL REFERENCES readiness_checkins (id) ON DELETE CASCADE,
+    body_area TEXT NOT NULL,
+    score INTEGER NOT NULL CONSTRAINT readiness_soreness_score_chk CHECK (score BETWEEN 0 AND 10),
+    PRIMARY KEY (checkin_id, body_area)
+);
+
+-- Personal records, detected from logged sets. A beaten record is kept as history, so
+-- the current record is the best of its kind for the variation and parameter type.
+CREATE TABLE IF NOT EXISTS personal_records (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    exercise_variation_id BIGINT NOT NULL REFERENCES exercise_variations (id) ON DELETE CASCADE,
+    parameter_type_id BIGINT NOT NULL REFERENCES parameter_types (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CONSTRAINT personal_records_kind_chk CHECK (
+        kind IN ('heaviest_load', 'longest_duration', 'most_reps', 'estimated_max')
+    ),
+    value FLOAT NOT NULL, -- the load, duration, reps or estimated max
+    previous_value FLOAT, -- the record it beat, NULL for the first
+    load FLOAT, -- for most reps, the load they were done at
+    reps INTEGER, -- reps of the set the record was set with
+    session_set_id BIGINT REFERENCES session_sets (id) ON DELETE SET NULL,
+    achieved_on DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS personal_records_user_id_idx ON personal_records (user_id, exercise_variation_id, parameter_type_id, kind);
+
+-- Climbs sent by a user. The grade is kept in the system it was given in; its index
+-- places it on the discipline's ladder so grades of either system compare.
+CREATE TABLE IF NOT EXISTS logbook_entries (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    scheduled_session_id BIGINT REFERENCES scheduled_sessions (id) ON DELETE SET NULL,
+    name TEXT NOT NULL CONSTRAINT logbook_entries_name_chk CHECK (validate_length (name, 0, 255)),
+    discipline TEXT NOT NULL CONSTRAINT logbook_entries_discipline_chk CHECK (
+        discipline IN ('boulder', 'route')
+    ),
+    grade_system TEXT NOT NULL CONSTRAINT logbook_entries_grade_system_chk CHECK (
+        grade_system IN ('v', 'font', 'yds', 'french')
+    ),
+    grade TEXT NOT NULL,
+    grade_index INTEGER NOT NULL,
+    style TEXT NOT NULL CONSTRAINT logbook_entries_style_chk CHECK (
+        style IN ('onsight', 'flash', 'redpoint', 'repeat')
+    ),
+    attempts INTEGER NOT NULL DEFAULT 1 CONSTRAINT logbook_entries_attempts_chk CHECK (attempts >= 1),
+    outdoor BOOLEAN NOT NULL DEFAULT FALSE,
+    climbed_on DATE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS logbook_entries_user_id_idx ON logbook_entries (user_id, climbed_on);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE, -- random and unguessable, the feed URL is the only credential
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Teams of athletes trained by coaches. Owners manage the team and its roster; owners
+-- and coaches may read the data of the team's athletes and assign them plans.
+CREATE TABLE IF NOT EXISTS teams (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL CONSTRAINT teams_name_chk CHECK (
+        validate_length (name, 1, 255)
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id BIGINT NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL CONSTRAINT team_members_role_chk CHECK (
+        role IN ('owner', 'coach', 'athlete')
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+CREATE TABLE IF NOT EXISTS team_invitations (
+    id BIGSERIAL PRIMARY KEY,
+    team_id BIGINT NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL CONSTRAINT team_invitations_role_chk CHECK (
+        role IN ('owner', 'coach', 'athlete')
+    ),
+    invited_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CONSTRAINT team_invitations_status_chk CHECK (
+        status IN ('pending', 'accepted', 'declined')
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A user has at most one open invitation to a team
+CREATE UNIQUE INDEX IF NOT EXISTS team_invitations_pending_idx ON team_invitations (team_id, user_id)
+WHERE
+    status = 'pending';
+
+-- Plans shared with other users. A share link gives the users who accept it its
+-- permission for as long as the link is neither revoked nor expired
+CREATE TABLE IF NOT EXISTS plan_share_links (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    token TEXT NOT NULL UNIQUE, -- random and unguessable, the link is the only credential
+    permission TEXT NOT NULL CONSTRAINT plan_share_links_permission_chk CHECK (
+        permission IN ('view', 'comment', 'edit')
+    ),
+    created_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    expires_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS plan_share_links_plan_id_idx ON plan_share_links (plan_id);
+
+CREATE TABLE IF NOT EXISTS plan_collaborators (
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    permission TEXT NOT NULL CONSTRAINT plan_collaborators_permission_chk CHECK (
+        permission IN ('view', 'comment', 'edit')
+    ),
+    share_link_id BIGINT REFERENCES plan_share_links (id) ON DELETE CASCADE, -- the link the user joined through, NULL when shared directly
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (plan_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS plan_collaborators_user_id_idx ON plan_collaborators (user_id);
+
+-- Star ratings of public templates, one per user
+CREATE TABLE IF NOT EXISTS plan_ratings (
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    stars INTEGER NOT NULL CONSTRAINT plan_ratings_stars_chk CHECK (stars BETWEEN 1 AND 5),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (plan_id, user_id)
+);
+
+-- Databases created before plans were forked gain the source plan on migrate
+ALTER TABLE plans ADD COLUMN IF NOT EXISTS source_plan_id BIGINT REFERENCES plans (id) ON DELETE SET NULL;
+
+-- Forks of a template are the plans copied from it
+CREATE INDEX IF NOT EXISTS plans_source_plan_id_idx ON plans (source_plan_id);
+
+-- Versions of a plan, each a snapshot of its tree as a plan document taken after a
+-- committed change. Version 1 is the plan as it was before its first recorded change.
+CREATE TABLE IF NOT EXISTS plan_versions (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    number INTEGER NOT NULL, -- counts the plan's versions from 1
+    document JSONB NOT NULL,
+    created_by BIGINT REFERENCES users (id) ON DELETE SET NULL, -- the acting user, NULL when unknown
+    restored_from INTEGER, -- the number of the version this one restored
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (plan_id, number)
+);
+
+-- Threaded comments on a plan or one of its intervals, groups or prescriptions. A comment
+-- with a parent is a reply and shares the target of the comment that started its thread;
+-- threads are resolved as a whole.
+CREATE TABLE IF NOT EXISTS plan_comments (
+    id BIGSERIAL PRIMARY KEY,
+    plan_id BIGINT NOT NULL REFERENCES plans (id) ON DELETE CASCADE,
+    plan_interval_id BIGINT REFERENCES plan_intervals (id) ON DELETE CASCADE,
+    group_id BIGINT REFERENCES groups (id) ON DELETE CASCADE,
+    prescription_id BIGINT REFERENCES interval_exercise_prescriptions (id) ON DELETE CASCADE,
+    parent_id BIGINT REFERENCES plan_comments (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    body TEXT NOT NULL CONSTRAINT plan_comments_body_chk CHECK (
+        validate_length (body, 1, 10000)
+    ),
+    resolved_at TIMESTAMP,
+    resolved_by BIGINT REFERENCES users (id) ON DELETE SET NULL,
+    edited_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT plan_comments_target_chk CHECK (
+        num_nonnulls (plan_interval_id, group_id, prescription_id) <= 1
+    )
+);
+
+CREATE INDEX IF NOT EXISTS plan_comments_plan_id_idx ON plan_comments (plan_id);
+CREATE INDEX IF NOT EXISTS plan_comments_parent_id_idx ON plan_comments (parent_id);
+
+-- Users a comment mentions
+CREATE TABLE IF NOT EXISTS plan_comment_mentions (
+    comment_id BIGINT NOT NULL REFERENCES plan_comments (id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+-- Global categories (user_id IS NULL) available to every user
+INSERT INTO categories (name, icon, user_id)
+SELECT seed.name, seed.icon, NULL
+FROM (
+    VALUES
+        ('Finger strength', 'hand'),
+        ('Power endurance', 'zap'),
+        ('Power', 'rocket'),
+        ('Endurance', 'timer'),
+        ('Antagonist', 'repeat'),
+        ('Core', 'circle-dot'),
+        ('Mobility', 'move'),
+        ('Technique', 'target')
+) AS seed (name, icon)
+WHERE NOT EXISTS (
+    SELECT 1 FROM categories c WHERE c.name = seed.name AND c.user_id IS NULL
+);
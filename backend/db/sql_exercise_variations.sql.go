@@ -71,6 +71,117 @@ func (q *Queries) ExerciseVariations_Create(ctx context.Context, arg ExerciseVar
 	return i, err
 }
 
+const exerciseVariations_ListByExerciseIds = `-- name: ExerciseVariations_ListByExerciseIds :many
+SELECT
+    ev.id,
+    ev.exercise_id,
+    ev.name,
+    evp.locked,
+    pt.name as pt_name,
+    pt.data_type as pt_data_type,
+    pt.default_unit as pt_default_unit,
+    pt.min_value as pt_min_value,
+    pt.max_value as pt_max_value
+FROM
+    exercise_variations ev
+    LEFT OUTER JOIN exercise_variation_params evp ON evp.exercise_variation_id = ev.id
+    LEFT OUTER JOIN parameter_types pt ON pt.id = evp.parameter_type_id
+WHERE
+    ev.exercise_id = ANY($1::BIGINT[])
+ORDER BY ev.exercise_id, ev.id, evp.id
+`
+
+type ExerciseVariations_ListByExerciseIdsRow struct {
+	ID            int64
+	ExerciseID    int64
+	Name          string
+	Locked        pgtype.Bool
+	PtName        pgtype.Text
+	PtDataType    pgtype.Text
+	PtDefaultUnit pgtype.Text
+	PtMinValue    pgtype.Float8
+	PtMaxValue    pgtype.Float8
+}
+
+func (q *Queries) ExerciseVariations_ListByExerciseIds(ctx context.Context, exerciseIds []int64) ([]ExerciseVariations_ListByExerciseIdsRow, error) {
+	rows, err := q.db.Query(ctx, exerciseVariations_ListByExerciseIds, exerciseIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExerciseVariations_ListByExerciseIdsRow
+	for rows.Next() {
+		var i ExerciseVariations_ListByExerciseIdsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExerciseID,
+			&i.Name,
+			&i.Locked,
+			&i.PtName,
+			&i.PtDataType,
+			&i.PtDefaultUnit,
+			&i.PtMinValue,
+			&i.PtMaxValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const exerciseVariations_ListParams = `-- name: ExerciseVariations_ListParams :many
+SELECT
+    evp.parameter_type_id,
+    pt.name,
+    pt.data_type,
+    pt.min_value,
+    pt.max_value
+FROM
+    exercise_variation_params evp
+    JOIN parameter_types pt ON pt.id = evp.parameter_type_id
+WHERE
+    evp.exercise_variation_id = $1
+ORDER BY evp.id
+`
+
+type ExerciseVariations_ListParamsRow struct {
+	ParameterTypeID int64
+	Name            string
+	DataType        string
+	MinValue        pgtype.Float8
+	MaxValue        pgtype.Float8
+}
+
+func (q *Queries) ExerciseVariations_ListParams(ctx context.Context, exerciseVariationID int64) ([]ExerciseVariations_ListParamsRow, error) {
+	rows, err := q.db.Query(ctx, exerciseVariations_ListParams, exerciseVariationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExerciseVariations_ListParamsRow
+	for rows.Next() {
+		var i ExerciseVariations_ListParamsRow
+		if err := rows.Scan(
+			&i.ParameterTypeID,
+			&i.Name,
+			&i.DataType,
+			&i.MinValue,
+			&i.MaxValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const exerciseVariations_ListWithDetails = `-- name: ExerciseVariations_ListWithDetails :many
 SELECT
     ev.id,
@@ -22,18 +22,38 @@ func (q *Queries) ExerciseVariations_DeleteOne(ctx context.Context, id int64) er
 
 const exercises_CreateOne = `-- name: Exercises_CreateOne :one
 INSERT INTO
-    exercises (name, description, user_id)
-VALUES ($1, $2, $3) RETURNING id, name, description, user_id, created_at, updated_at
+    exercises (
+        name,
+        description,
+        user_id,
+        primary_muscle_groups,
+        secondary_muscle_groups,
+        equipment,
+        laterality
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, description, user_id, created_at, updated_at, primary_muscle_groups, secondary_muscle_groups, equipment, laterality
 `
 
 type Exercises_CreateOneParams struct {
-	Name        string
-	Description string
-	UserID      pgtype.Int8
+	Name                  string
+	Description           string
+	UserID                pgtype.Int8
+	PrimaryMuscleGroups   []string
+	SecondaryMuscleGroups []string
+	Equipment             []string
+	Laterality            string
 }
 
 func (q *Queries) Exercises_CreateOne(ctx context.Context, arg Exercises_CreateOneParams) (Exercise, error) {
-	row := q.db.QueryRow(ctx, exercises_CreateOne, arg.Name, arg.Description, arg.UserID)
+	row := q.db.QueryRow(ctx, exercises_CreateOne,
+		arg.Name,
+		arg.Description,
+		arg.UserID,
+		arg.PrimaryMuscleGroups,
+		arg.SecondaryMuscleGroups,
+		arg.Equipment,
+		arg.Laterality,
+	)
 	var i Exercise
 	err := row.Scan(
 		&i.ID,
@@ -42,6 +62,10 @@ func (q *Queries) Exercises_CreateOne(ctx context.Context, arg Exercises_CreateO
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PrimaryMuscleGroups,
+		&i.SecondaryMuscleGroups,
+		&i.Equipment,
+		&i.Laterality,
 	)
 	return i, err
 }
@@ -56,7 +80,7 @@ func (q *Queries) Exercises_DeleteOne(ctx context.Context, id int64) error {
 }
 
 const exercises_GetById = `-- name: Exercises_GetById :one
-SELECT id, name, description, user_id, created_at, updated_at FROM exercises WHERE id = $1 LIMIT 1
+SELECT id, name, description, user_id, created_at, updated_at, primary_muscle_groups, secondary_muscle_groups, equipment, laterality FROM exercises WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) Exercises_GetById(ctx context.Context, id int64) (Exercise, error) {
@@ -69,12 +93,16 @@ func (q *Queries) Exercises_GetById(ctx context.Context, id int64) (Exercise, er
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PrimaryMuscleGroups,
+		&i.SecondaryMuscleGroups,
+		&i.Equipment,
+		&i.Laterality,
 	)
 	return i, err
 }
 
 const exercises_GetByPlanId = `-- name: Exercises_GetByPlanId :many
-SELECT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at
+SELECT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at, exercises.primary_muscle_groups, exercises.secondary_muscle_groups, exercises.equipment, exercises.laterality
 FROM
     exercises
     JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
@@ -107,6 +135,10 @@ func (q *Queries) Exercises_GetByPlanId(ctx context.Context, arg Exercises_GetBy
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PrimaryMuscleGroups,
+			&i.SecondaryMuscleGroups,
+			&i.Equipment,
+			&i.Laterality,
 		); err != nil {
 			return nil, err
 		}
@@ -119,7 +151,7 @@ func (q *Queries) Exercises_GetByPlanId(ctx context.Context, arg Exercises_GetBy
 }
 
 const exercises_GetByUserId = `-- name: Exercises_GetByUserId :many
-SELECT id, name, description, user_id, created_at, updated_at
+SELECT id, name, description, user_id, created_at, updated_at, primary_muscle_groups, secondary_muscle_groups, equipment, laterality
 FROM exercises
 WHERE
     user_id = $1
@@ -148,6 +180,10 @@ func (q *Queries) Exercises_GetByUserId(ctx context.Context, arg Exercises_GetBy
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PrimaryMuscleGroups,
+			&i.SecondaryMuscleGroups,
+			&i.Equipment,
+			&i.Laterality,
 		); err != nil {
 			return nil, err
 		}
@@ -160,29 +196,48 @@ func (q *Queries) Exercises_GetByUserId(ctx context.Context, arg Exercises_GetBy
 }
 
 const exercises_List = `-- name: Exercises_List :many
-SELECT DISTINCT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at FROM exercises
+SELECT DISTINCT exercises.id, exercises.name, exercises.description, exercises.user_id, exercises.created_at, exercises.updated_at, exercises.primary_muscle_groups, exercises.secondary_muscle_groups, exercises.equipment, exercises.laterality FROM exercises
 LEFT JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
 LEFT JOIN interval_exercise_prescriptions on exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
 LEFT JOIN plan_intervals on plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+LEFT JOIN exercise_categories on exercise_categories.exercise_id = exercises.id
 WHERE
     (exercises.id = $1::BIGINT or $1::BIGINT = 0)
     AND (exercises.user_id = $2::BIGINT or $2::BIGINT = 0)
     AND (plan_intervals.plan_id = $3::BIGINT or $3::BIGINT = 0)
     AND (interval_exercise_prescriptions.group_id = $4::BIGINT or $4::BIGINT = 0)
     AND (plan_intervals.id = $5::BIGINT or $5::BIGINT = 0)
+    AND (exercise_categories.category_id = $6::BIGINT or $6::BIGINT = 0)
+    AND (
+        $7::TEXT = ''
+        OR $7::TEXT = ANY(exercises.primary_muscle_groups)
+        OR $7::TEXT = ANY(exercises.secondary_muscle_groups)
+    )
+    AND ($8::TEXT = '' OR $8::TEXT = ANY(exercises.equipment))
+    AND (exercises.laterality = $9::TEXT or $9::TEXT = '')
+    AND (
+        $10::BIGINT = 0
+        OR exercises.user_id IS NULL
+        OR exercises.user_id = $10::BIGINT
+    )
 ORDER BY exercises.created_at DESC
-LIMIT $7::int
-OFFSET $6::int
+LIMIT $12::int
+OFFSET $11::int
 `
 
 type Exercises_ListParams struct {
-	ExerciseID int64
-	UserID     int64
-	PlanID     int64
-	GroupID    int64
-	IntervalID int64
-	Offset     int32
-	Limit      int32
+	ExerciseID  int64
+	UserID      int64
+	PlanID      int64
+	GroupID     int64
+	IntervalID  int64
+	CategoryID  int64
+	MuscleGroup string
+	Equipment   string
+	Laterality  string
+	VisibleTo   int64
+	Offset      int32
+	Limit       int32
 }
 
 func (q *Queries) Exercises_List(ctx context.Context, arg Exercises_ListParams) ([]Exercise, error) {
@@ -192,6 +247,11 @@ func (q *Queries) Exercises_List(ctx context.Context, arg Exercises_ListParams)
 		arg.PlanID,
 		arg.GroupID,
 		arg.IntervalID,
+		arg.CategoryID,
+		arg.MuscleGroup,
+		arg.Equipment,
+		arg.Laterality,
+		arg.VisibleTo,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -209,6 +269,10 @@ func (q *Queries) Exercises_List(ctx context.Context, arg Exercises_ListParams)
 			&i.UserID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PrimaryMuscleGroups,
+			&i.SecondaryMuscleGroups,
+			&i.Equipment,
+			&i.Laterality,
 		); err != nil {
 			return nil, err
 		}
@@ -220,24 +284,79 @@ func (q *Queries) Exercises_List(ctx context.Context, arg Exercises_ListParams)
 	return items, nil
 }
 
+const exercises_ListEquipmentByPlanId = `-- name: Exercises_ListEquipmentByPlanId :many
+SELECT DISTINCT exercises.id, exercises.name, exercises.equipment
+FROM
+    exercises
+    JOIN exercise_variations on exercise_variations.exercise_id = exercises.id
+    JOIN interval_exercise_prescriptions on exercise_variations.id = interval_exercise_prescriptions.exercise_variation_id
+    JOIN plan_intervals on plan_intervals.id = interval_exercise_prescriptions.plan_interval_id
+WHERE
+    plan_intervals.plan_id = $1
+    AND cardinality(exercises.equipment) > 0
+ORDER BY exercises.id
+`
+
+type Exercises_ListEquipmentByPlanIdRow struct {
+	ID        int64
+	Name      string
+	Equipment []string
+}
+
+func (q *Queries) Exercises_ListEquipmentByPlanId(ctx context.Context, planID int64) ([]Exercises_ListEquipmentByPlanIdRow, error) {
+	rows, err := q.db.Query(ctx, exercises_ListEquipmentByPlanId, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Exercises_ListEquipmentByPlanIdRow
+	for rows.Next() {
+		var i Exercises_ListEquipmentByPlanIdRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Equipment); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const exercises_UpdateOne = `-- name: Exercises_UpdateOne :one
 UPDATE exercises
 SET
     name = $1,
     description = $2,
+    primary_muscle_groups = $3,
+    secondary_muscle_groups = $4,
+    equipment = $5,
+    laterality = $6,
     updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = $3 RETURNING id, name, description, user_id, created_at, updated_at
+    id = $7 RETURNING id, name, description, user_id, created_at, updated_at, primary_muscle_groups, secondary_muscle_groups, equipment, laterality
 `
 
 type Exercises_UpdateOneParams struct {
-	Name        string
-	Description string
-	ID          int64
+	Name                  string
+	Description           string
+	PrimaryMuscleGroups   []string
+	SecondaryMuscleGroups []string
+	Equipment             []string
+	Laterality            string
+	ID                    int64
 }
 
 func (q *Queries) Exercises_UpdateOne(ctx context.Context, arg Exercises_UpdateOneParams) (Exercise, error) {
-	row := q.db.QueryRow(ctx, exercises_UpdateOne, arg.Name, arg.Description, arg.ID)
+	row := q.db.QueryRow(ctx, exercises_UpdateOne,
+		arg.Name,
+		arg.Description,
+		arg.PrimaryMuscleGroups,
+		arg.SecondaryMuscleGroups,
+		arg.Equipment,
+		arg.Laterality,
+		arg.ID,
+	)
 	var i Exercise
 	err := row.Scan(
 		&i.ID,
@@ -246,6 +365,10 @@ func (q *Queries) Exercises_UpdateOne(ctx context.Context, arg Exercises_UpdateO
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PrimaryMuscleGroups,
+		&i.SecondaryMuscleGroups,
+		&i.Equipment,
+		&i.Laterality,
 	)
 	return i, err
 }
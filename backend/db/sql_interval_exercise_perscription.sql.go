@@ -33,7 +33,8 @@ INSERT INTO
         sub_reps,
         sub_rep_work_duration,
         sub_rep_rest_duration,
-        rest
+        rest,
+        position
     )
 VALUES (
         $1::BIGINT,
@@ -46,8 +47,9 @@ VALUES (
         $8,
         $9,
         $10,
-        $11
-    ) RETURNING id, group_id, exercise_variation_id, plan_interval_id, rpe, sets, reps, duration, sub_reps, sub_rep_work_duration, sub_rep_rest_duration, rest
+        $11,
+        $12::INT
+    ) RETURNING id, group_id, exercise_variation_id, plan_interval_id, rpe, sets, reps, duration, sub_reps, sub_rep_work_duration, sub_rep_rest_duration, rest, position, block_id
 `
 
 type IntervalExercisePrescriptions_CreateOneParams struct {
@@ -62,6 +64,7 @@ type IntervalExercisePrescriptions_CreateOneParams struct {
 	SubRepWorkDuration pgtype.Interval
 	SubRepRestDuration pgtype.Interval
 	Rest               pgtype.Interval
+	Position           int32
 }
 
 func (q *Queries) IntervalExercisePrescriptions_CreateOne(ctx context.Context, arg IntervalExercisePrescriptions_CreateOneParams) (IntervalExercisePrescription, error) {
@@ -77,6 +80,7 @@ func (q *Queries) IntervalExercisePrescriptions_CreateOne(ctx context.Context, a
 		arg.SubRepWorkDuration,
 		arg.SubRepRestDuration,
 		arg.Rest,
+		arg.Position,
 	)
 	var i IntervalExercisePrescription
 	err := row.Scan(
@@ -92,6 +96,8 @@ func (q *Queries) IntervalExercisePrescriptions_CreateOne(ctx context.Context, a
 		&i.SubRepWorkDuration,
 		&i.SubRepRestDuration,
 		&i.Rest,
+		&i.Position,
+		&i.BlockID,
 	)
 	return i, err
 }
@@ -105,6 +111,32 @@ func (q *Queries) IntervalExercisePrescriptions_DeleteOne(ctx context.Context, i
 	return err
 }
 
+const intervalExercisePrescriptions_GetById = `-- name: IntervalExercisePrescriptions_GetById :one
+SELECT id, group_id, exercise_variation_id, plan_interval_id, rpe, sets, reps, duration, sub_reps, sub_rep_work_duration, sub_rep_rest_duration, rest, position, block_id FROM interval_exercise_prescriptions WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) IntervalExercisePrescriptions_GetById(ctx context.Context, id int64) (IntervalExercisePrescription, error) {
+	row := q.db.QueryRow(ctx, intervalExercisePrescriptions_GetById, id)
+	var i IntervalExercisePrescription
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.ExerciseVariationID,
+		&i.PlanIntervalID,
+		&i.Rpe,
+		&i.Sets,
+		&i.Reps,
+		&i.Duration,
+		&i.SubReps,
+		&i.SubRepWorkDuration,
+		&i.SubRepRestDuration,
+		&i.Rest,
+		&i.Position,
+		&i.BlockID,
+	)
+	return i, err
+}
+
 const intervalExercisePrescriptions_List = `-- name: IntervalExercisePrescriptions_List :many
 SELECT
     iep.id,
@@ -118,7 +150,9 @@ SELECT
     iep.sub_reps,
     iep.sub_rep_work_duration,
     iep.sub_rep_rest_duration,
-    iep.rest
+    iep.rest,
+    iep.position,
+    iep.block_id
 FROM
     interval_exercise_prescriptions iep
 WHERE
@@ -126,6 +160,7 @@ WHERE
     AND (iep.id = $2::BIGINT or $2::bigint = 0)
     AND (iep.exercise_variation_id = $3::BIGINT or $3::bigint = 0)
     AND (iep.plan_interval_id = $4::BIGINT or $4::bigint = 0)
+ORDER BY iep.plan_interval_id, iep.group_id, iep.position, iep.id
 LIMIT $6::int
 OFFSET $5::int
 `
@@ -168,6 +203,8 @@ func (q *Queries) IntervalExercisePrescriptions_List(ctx context.Context, arg In
 			&i.SubRepWorkDuration,
 			&i.SubRepRestDuration,
 			&i.Rest,
+			&i.Position,
+			&i.BlockID,
 		); err != nil {
 			return nil, err
 		}
@@ -193,6 +230,8 @@ SELECT
     iep.sub_rep_work_duration,
     iep.sub_rep_rest_duration,
     iep.rest,
+    iep.position,
+    iep.block_id,
     -- Exercise Variation details
     ev.id as ev_id,
     ev.exercise_id as ev_exercise_id,
@@ -225,7 +264,7 @@ WHERE
     AND (iep.id = $2::BIGINT or $2::bigint = 0)
     AND (iep.exercise_variation_id = $3::BIGINT or $3::bigint = 0)
     AND (iep.plan_interval_id = $4::BIGINT or $4::bigint = 0)
-ORDER BY iep.id, evp.id
+ORDER BY iep.plan_interval_id, iep.group_id, iep.position, iep.id, evp.id
 LIMIT $6::int
 OFFSET $5::int
 `
@@ -252,6 +291,8 @@ type IntervalExercisePrescriptions_ListWithDetailsRow struct {
 	SubRepWorkDuration  pgtype.Interval
 	SubRepRestDuration  pgtype.Interval
 	Rest                pgtype.Interval
+	Position            int32
+	BlockID             pgtype.Int8
 	EvID                int64
 	EvExerciseID        int64
 	EvName              string
@@ -300,6 +341,8 @@ func (q *Queries) IntervalExercisePrescriptions_ListWithDetails(ctx context.Cont
 			&i.SubRepWorkDuration,
 			&i.SubRepRestDuration,
 			&i.Rest,
+			&i.Position,
+			&i.BlockID,
 			&i.EvID,
 			&i.EvExerciseID,
 			&i.EvName,
@@ -327,3 +370,113 @@ func (q *Queries) IntervalExercisePrescriptions_ListWithDetails(ctx context.Cont
 	}
 	return items, nil
 }
+
+const intervalExercisePrescriptions_NextPosition = `-- name: IntervalExercisePrescriptions_NextPosition :one
+SELECT (COALESCE(MAX(position), -1) + 1)::INTEGER AS next_position
+FROM interval_exercise_prescriptions
+WHERE
+    group_id = $1::BIGINT
+    AND plan_interval_id = $2::BIGINT
+`
+
+type IntervalExercisePrescriptions_NextPositionParams struct {
+	GroupID    int64
+	IntervalID int64
+}
+
+func (q *Queries) IntervalExercisePrescriptions_NextPosition(ctx context.Context, arg IntervalExercisePrescriptions_NextPositionParams) (int32, error) {
+	row := q.db.QueryRow(ctx, intervalExercisePrescriptions_NextPosition, arg.GroupID, arg.IntervalID)
+	var next_position int32
+	err := row.Scan(&next_position)
+	return next_position, err
+}
+
+const intervalExercisePrescriptions_SetBlock = `-- name: IntervalExercisePrescriptions_SetBlock :exec
+UPDATE interval_exercise_prescriptions SET block_id = $1 WHERE id = $2::BIGINT
+`
+
+type IntervalExercisePrescriptions_SetBlockParams struct {
+	BlockID pgtype.Int8
+	ID      int64
+}
+
+func (q *Queries) IntervalExercisePrescriptions_SetBlock(ctx context.Context, arg IntervalExercisePrescriptions_SetBlockParams) error {
+	_, err := q.db.Exec(ctx, intervalExercisePrescriptions_SetBlock, arg.BlockID, arg.ID)
+	return err
+}
+
+const intervalExercisePrescriptions_UpdateOne = `-- name: IntervalExercisePrescriptions_UpdateOne :one
+UPDATE interval_exercise_prescriptions
+SET
+    exercise_variation_id = $1::BIGINT,
+    rpe = $2,
+    sets = $3::INT,
+    reps = $4,
+    duration = $5,
+    sub_reps = $6,
+    sub_rep_work_duration = $7,
+    sub_rep_rest_duration = $8,
+    rest = $9
+WHERE
+    id = $10::BIGINT RETURNING id, group_id, exercise_variation_id, plan_interval_id, rpe, sets, reps, duration, sub_reps, sub_rep_work_duration, sub_rep_rest_duration, rest, position, block_id
+`
+
+type IntervalExercisePrescriptions_UpdateOneParams struct {
+	VariationID        int64
+	Rpe                pgtype.Int4
+	Sets               int32
+	Reps               pgtype.Int4
+	Duration           pgtype.Interval
+	SubReps            pgtype.Int4
+	SubRepWorkDuration pgtype.Interval
+	SubRepRestDuration pgtype.Interval
+	Rest               pgtype.Interval
+	ID                 int64
+}
+
+func (q *Queries) IntervalExercisePrescriptions_UpdateOne(ctx context.Context, arg IntervalExercisePrescriptions_UpdateOneParams) (IntervalExercisePrescription, error) {
+	row := q.db.QueryRow(ctx, intervalExercisePrescriptions_UpdateOne,
+		arg.VariationID,
+		arg.Rpe,
+		arg.Sets,
+		arg.Reps,
+		arg.Duration,
+		arg.SubReps,
+		arg.SubRepWorkDuration,
+		arg.SubRepRestDuration,
+		arg.Rest,
+		arg.ID,
+	)
+	var i IntervalExercisePrescription
+	err := row.Scan(
+		&i.ID,
+		&i.GroupID,
+		&i.ExerciseVariationID,
+		&i.PlanIntervalID,
+		&i.Rpe,
+		&i.Sets,
+		&i.Reps,
+		&i.Duration,
+		&i.SubReps,
+		&i.SubRepWorkDuration,
+		&i.SubRepRestDuration,
+		&i.Rest,
+		&i.Position,
+		&i.BlockID,
+	)
+	return i, err
+}
+
+const intervalExercisePrescriptions_UpdatePosition = `-- name: IntervalExercisePrescriptions_UpdatePosition :exec
+UPDATE interval_exercise_prescriptions SET position = $1::INT WHERE id = $2::BIGINT
+`
+
+type IntervalExercisePrescriptions_UpdatePositionParams struct {
+	Position int32
+	ID       int64
+}
+
+func (q *Queries) IntervalExercisePrescriptions_UpdatePosition(ctx context.Context, arg IntervalExercisePrescriptions_UpdatePositionParams) error {
+	_, err := q.db.Exec(ctx, intervalExercisePrescriptions_UpdatePosition, arg.Position, arg.ID)
+	return err
+}
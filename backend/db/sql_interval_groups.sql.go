@@ -16,25 +16,37 @@ INSERT INTO
     interval_group_assignments (
         plan_interval_id,
         group_id,
-        frequency
+        frequency,
+        pinned_days,
+        "order"
     )
-VALUES ($1, $2, $3) RETURNING id, plan_interval_id, group_id, frequency
+VALUES ($1, $2, $3, $4, $5) RETURNING id, plan_interval_id, group_id, frequency, pinned_days, "order"
 `
 
 type IntervalGroupAssignments_CreateParams struct {
 	PlanIntervalID int64
 	GroupID        int64
 	Frequency      int32
+	PinnedDays     []int32
+	Order          int32
 }
 
 func (q *Queries) IntervalGroupAssignments_Create(ctx context.Context, arg IntervalGroupAssignments_CreateParams) (IntervalGroupAssignment, error) {
-	row := q.db.QueryRow(ctx, intervalGroupAssignments_Create, arg.PlanIntervalID, arg.GroupID, arg.Frequency)
+	row := q.db.QueryRow(ctx, intervalGroupAssignments_Create,
+		arg.PlanIntervalID,
+		arg.GroupID,
+		arg.Frequency,
+		arg.PinnedDays,
+		arg.Order,
+	)
 	var i IntervalGroupAssignment
 	err := row.Scan(
 		&i.ID,
 		&i.PlanIntervalID,
 		&i.GroupID,
 		&i.Frequency,
+		&i.PinnedDays,
+		&i.Order,
 	)
 	return i, err
 }
@@ -58,7 +70,7 @@ func (q *Queries) IntervalGroupAssignments_Delete(ctx context.Context, arg Inter
 
 const intervalGroupAssignments_GetByGroupId = `-- name: IntervalGroupAssignments_GetByGroupId :many
 SELECT
-    iga.id, iga.plan_interval_id, iga.group_id, iga.frequency,
+    iga.id, iga.plan_interval_id, iga.group_id, iga.frequency, iga.pinned_days, iga."order",
     pi.id as pi_id,
     pi.plan_id as pi_plan_id,
     pi.name as pi_name,
@@ -71,6 +83,7 @@ FROM
     JOIN plan_intervals pi ON pi.id = iga.plan_interval_id
 WHERE
     iga.group_id = $1
+ORDER BY pi.plan_id, pi.order, iga.id
 `
 
 type IntervalGroupAssignments_GetByGroupIdRow struct {
@@ -78,6 +91,8 @@ type IntervalGroupAssignments_GetByGroupIdRow struct {
 	PlanIntervalID int64
 	GroupID        int64
 	Frequency      int32
+	PinnedDays     []int32
+	Order          int32
 	PiID           int64
 	PiPlanID       int64
 	PiName         pgtype.Text
@@ -101,6 +116,8 @@ func (q *Queries) IntervalGroupAssignments_GetByGroupId(ctx context.Context, gro
 			&i.PlanIntervalID,
 			&i.GroupID,
 			&i.Frequency,
+			&i.PinnedDays,
+			&i.Order,
 			&i.PiID,
 			&i.PiPlanID,
 			&i.PiName,
@@ -121,7 +138,7 @@ func (q *Queries) IntervalGroupAssignments_GetByGroupId(ctx context.Context, gro
 
 const intervalGroupAssignments_GetByIntervalId = `-- name: IntervalGroupAssignments_GetByIntervalId :many
 SELECT
-    iga.id, iga.plan_interval_id, iga.group_id, iga.frequency,
+    iga.id, iga.plan_interval_id, iga.group_id, iga.frequency, iga.pinned_days, iga."order",
     g.id as g_id,
     g.name as g_name,
     g.description as g_description,
@@ -133,6 +150,7 @@ FROM
     JOIN groups g ON g.id = iga.group_id
 WHERE
     iga.plan_interval_id = $1
+ORDER BY iga."order", iga.id
 `
 
 type IntervalGroupAssignments_GetByIntervalIdRow struct {
@@ -140,6 +158,8 @@ type IntervalGroupAssignments_GetByIntervalIdRow struct {
 	PlanIntervalID int64
 	GroupID        int64
 	Frequency      int32
+	PinnedDays     []int32
+	Order          int32
 	GID            int64
 	GName          string
 	GDescription   string
@@ -162,6 +182,8 @@ func (q *Queries) IntervalGroupAssignments_GetByIntervalId(ctx context.Context,
 			&i.PlanIntervalID,
 			&i.GroupID,
 			&i.Frequency,
+			&i.PinnedDays,
+			&i.Order,
 			&i.GID,
 			&i.GName,
 			&i.GDescription,
@@ -178,3 +200,83 @@ func (q *Queries) IntervalGroupAssignments_GetByIntervalId(ctx context.Context,
 	}
 	return items, nil
 }
+
+const intervalGroupAssignments_GetOne = `-- name: IntervalGroupAssignments_GetOne :one
+SELECT id, plan_interval_id, group_id, frequency, pinned_days, "order" FROM interval_group_assignments
+WHERE
+    plan_interval_id = $1
+    AND group_id = $2
+LIMIT 1
+`
+
+type IntervalGroupAssignments_GetOneParams struct {
+	PlanIntervalID int64
+	GroupID        int64
+}
+
+func (q *Queries) IntervalGroupAssignments_GetOne(ctx context.Context, arg IntervalGroupAssignments_GetOneParams) (IntervalGroupAssignment, error) {
+	row := q.db.QueryRow(ctx, intervalGroupAssignments_GetOne, arg.PlanIntervalID, arg.GroupID)
+	var i IntervalGroupAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanIntervalID,
+		&i.GroupID,
+		&i.Frequency,
+		&i.PinnedDays,
+		&i.Order,
+	)
+	return i, err
+}
+
+const intervalGroupAssignments_NextOrder = `-- name: IntervalGroupAssignments_NextOrder :one
+SELECT (COALESCE(MAX("order"), -1) + 1)::INTEGER AS next_order
+FROM interval_group_assignments
+WHERE
+    plan_interval_id = $1
+`
+
+func (q *Queries) IntervalGroupAssignments_NextOrder(ctx context.Context, planIntervalID int64) (int32, error) {
+	row := q.db.QueryRow(ctx, intervalGroupAssignments_NextOrder, planIntervalID)
+	var next_order int32
+	err := row.Scan(&next_order)
+	return next_order, err
+}
+
+const intervalGroupAssignments_Update = `-- name: IntervalGroupAssignments_Update :one
+UPDATE interval_group_assignments
+SET
+    frequency = $1,
+    pinned_days = $2,
+    "order" = $3
+WHERE
+    plan_interval_id = $4
+    AND group_id = $5 RETURNING id, plan_interval_id, group_id, frequency, pinned_days, "order"
+`
+
+type IntervalGroupAssignments_UpdateParams struct {
+	Frequency      int32
+	PinnedDays     []int32
+	Order          int32
+	PlanIntervalID int64
+	GroupID        int64
+}
+
+func (q *Queries) IntervalGroupAssignments_Update(ctx context.Context, arg IntervalGroupAssignments_UpdateParams) (IntervalGroupAssignment, error) {
+	row := q.db.QueryRow(ctx, intervalGroupAssignments_Update,
+		arg.Frequency,
+		arg.PinnedDays,
+		arg.Order,
+		arg.PlanIntervalID,
+		arg.GroupID,
+	)
+	var i IntervalGroupAssignment
+	err := row.Scan(
+		&i.ID,
+		&i.PlanIntervalID,
+		&i.GroupID,
+		&i.Frequency,
+		&i.PinnedDays,
+		&i.Order,
+	)
+	return i, err
+}
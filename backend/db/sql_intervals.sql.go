@@ -20,7 +20,7 @@ INSERT INTO
         duration,
         "order"
     )
-VALUES ($1, $2, $3, $4, $5) RETURNING id, plan_id, name, description, duration, "order", created_at, updated_at
+VALUES ($1, $2, $3, $4, $5) RETURNING id, plan_id, name, description, duration, "order", is_deload, created_at, updated_at
 `
 
 type PlanIntervals_CreateOneParams struct {
@@ -47,6 +47,7 @@ func (q *Queries) PlanIntervals_CreateOne(ctx context.Context, arg PlanIntervals
 		&i.Description,
 		&i.Duration,
 		&i.Order,
+		&i.IsDeload,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -54,7 +55,7 @@ func (q *Queries) PlanIntervals_CreateOne(ctx context.Context, arg PlanIntervals
 }
 
 const planIntervals_DeleteById = `-- name: PlanIntervals_DeleteById :one
-DELETE FROM plan_intervals WHERE id = $1 RETURNING id, plan_id, name, description, duration, "order", created_at, updated_at
+DELETE FROM plan_intervals WHERE id = $1 RETURNING id, plan_id, name, description, duration, "order", is_deload, created_at, updated_at
 `
 
 func (q *Queries) PlanIntervals_DeleteById(ctx context.Context, id int64) (PlanInterval, error) {
@@ -67,6 +68,7 @@ func (q *Queries) PlanIntervals_DeleteById(ctx context.Context, id int64) (PlanI
 		&i.Description,
 		&i.Duration,
 		&i.Order,
+		&i.IsDeload,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -75,7 +77,7 @@ func (q *Queries) PlanIntervals_DeleteById(ctx context.Context, id int64) (PlanI
 
 const planIntervals_List = `-- name: PlanIntervals_List :many
 SELECT 
-    pi.id, pi.plan_id, pi.name, pi.description, pi.duration, pi."order", pi.created_at, pi.updated_at,
+    pi.id, pi.plan_id, pi.name, pi.description, pi.duration, pi."order", pi.is_deload, pi.created_at, pi.updated_at,
     COALESCE(group_counts.count, 0) AS group_count
 FROM plan_intervals pi
 LEFT JOIN (
@@ -107,6 +109,7 @@ type PlanIntervals_ListRow struct {
 	Description pgtype.Text
 	Duration    pgtype.Interval
 	Order       int32
+	IsDeload    bool
 	CreatedAt   pgtype.Timestamp
 	UpdatedAt   pgtype.Timestamp
 	GroupCount  int64
@@ -134,6 +137,7 @@ func (q *Queries) PlanIntervals_List(ctx context.Context, arg PlanIntervals_List
 			&i.Description,
 			&i.Duration,
 			&i.Order,
+			&i.IsDeload,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.GroupCount,
@@ -148,6 +152,37 @@ func (q *Queries) PlanIntervals_List(ctx context.Context, arg PlanIntervals_List
 	return items, nil
 }
 
+const planIntervals_SetDeload = `-- name: PlanIntervals_SetDeload :one
+UPDATE plan_intervals
+SET
+    is_deload = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1 RETURNING id, plan_id, name, description, duration, "order", is_deload, created_at, updated_at
+`
+
+type PlanIntervals_SetDeloadParams struct {
+	ID       int64
+	IsDeload bool
+}
+
+func (q *Queries) PlanIntervals_SetDeload(ctx context.Context, arg PlanIntervals_SetDeloadParams) (PlanInterval, error) {
+	row := q.db.QueryRow(ctx, planIntervals_SetDeload, arg.ID, arg.IsDeload)
+	var i PlanInterval
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.Name,
+		&i.Description,
+		&i.Duration,
+		&i.Order,
+		&i.IsDeload,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const planIntervals_UpdateOrderByValues = `-- name: PlanIntervals_UpdateOrderByValues :many
 UPDATE plan_intervals as p_i
 SET
@@ -160,7 +195,7 @@ FROM (
 WHERE
     p_i.id = ANY (v.ids)
 RETURNING
-    id, plan_id, name, description, duration, "order", created_at, updated_at
+    id, plan_id, name, description, duration, "order", is_deload, created_at, updated_at
 `
 
 type PlanIntervals_UpdateOrderByValuesParams struct {
@@ -184,6 +219,7 @@ func (q *Queries) PlanIntervals_UpdateOrderByValues(ctx context.Context, arg Pla
 			&i.Description,
 			&i.Duration,
 			&i.Order,
+			&i.IsDeload,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -117,3 +117,19 @@ func (q *Queries) ParameterTypes_List(ctx context.Context, arg ParameterTypes_Li
 	}
 	return items, nil
 }
+
+const userParameterTypes_Add = `-- name: UserParameterTypes_Add :exec
+INSERT INTO
+    user_parameter_types (user_id, parameter_type_id)
+VALUES ($1::BIGINT, $2::BIGINT) ON CONFLICT DO NOTHING
+`
+
+type UserParameterTypes_AddParams struct {
+	UserID          int64
+	ParameterTypeID int64
+}
+
+func (q *Queries) UserParameterTypes_Add(ctx context.Context, arg UserParameterTypes_AddParams) error {
+	_, err := q.db.Exec(ctx, userParameterTypes_Add, arg.UserID, arg.ParameterTypeID)
+	return err
+}
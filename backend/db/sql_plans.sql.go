@@ -7,6 +7,8 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const plans_CreateOne = `-- name: Plans_CreateOne :one
@@ -16,9 +18,10 @@ INSERT INTO
         description,
         user_id,
         is_template,
-        is_public
+        is_public,
+        start_date
     )
-VALUES ($1, $2, $3, $4, $5) RETURNING id, name, description, user_id, is_template, is_public, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at
 `
 
 type Plans_CreateOneParams struct {
@@ -27,6 +30,7 @@ type Plans_CreateOneParams struct {
 	UserID      int64
 	IsTemplate  bool
 	IsPublic    bool
+	StartDate   pgtype.Date
 }
 
 func (q *Queries) Plans_CreateOne(ctx context.Context, arg Plans_CreateOneParams) (Plan, error) {
@@ -36,6 +40,7 @@ func (q *Queries) Plans_CreateOne(ctx context.Context, arg Plans_CreateOneParams
 		arg.UserID,
 		arg.IsTemplate,
 		arg.IsPublic,
+		arg.StartDate,
 	)
 	var i Plan
 	err := row.Scan(
@@ -45,6 +50,8 @@ func (q *Queries) Plans_CreateOne(ctx context.Context, arg Plans_CreateOneParams
 		&i.UserID,
 		&i.IsTemplate,
 		&i.IsPublic,
+		&i.StartDate,
+		&i.SourcePlanID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -52,7 +59,7 @@ func (q *Queries) Plans_CreateOne(ctx context.Context, arg Plans_CreateOneParams
 }
 
 const plans_DeleteById = `-- name: Plans_DeleteById :one
-DELETE FROM plans WHERE id = $1 RETURNING id, name, description, user_id, is_template, is_public, created_at, updated_at
+DELETE FROM plans WHERE id = $1 RETURNING id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at
 `
 
 func (q *Queries) Plans_DeleteById(ctx context.Context, id int64) (Plan, error) {
@@ -65,6 +72,8 @@ func (q *Queries) Plans_DeleteById(ctx context.Context, id int64) (Plan, error)
 		&i.UserID,
 		&i.IsTemplate,
 		&i.IsPublic,
+		&i.StartDate,
+		&i.SourcePlanID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -72,7 +81,7 @@ func (q *Queries) Plans_DeleteById(ctx context.Context, id int64) (Plan, error)
 }
 
 const plans_GetByPlanId = `-- name: Plans_GetByPlanId :one
-SELECT id, name, description, user_id, is_template, is_public, created_at, updated_at FROM plans WHERE id = $1 LIMIT 1
+SELECT id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at FROM plans WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) Plans_GetByPlanId(ctx context.Context, id int64) (Plan, error) {
@@ -85,6 +94,8 @@ func (q *Queries) Plans_GetByPlanId(ctx context.Context, id int64) (Plan, error)
 		&i.UserID,
 		&i.IsTemplate,
 		&i.IsPublic,
+		&i.StartDate,
+		&i.SourcePlanID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -92,7 +103,7 @@ func (q *Queries) Plans_GetByPlanId(ctx context.Context, id int64) (Plan, error)
 }
 
 const plans_GetByUserId = `-- name: Plans_GetByUserId :many
-SELECT id, name, description, user_id, is_template, is_public, created_at, updated_at
+SELECT id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at
 FROM plans
 WHERE
     user_id = $1
@@ -144,6 +155,8 @@ func (q *Queries) Plans_GetByUserId(ctx context.Context, arg Plans_GetByUserIdPa
 			&i.UserID,
 			&i.IsTemplate,
 			&i.IsPublic,
+			&i.StartDate,
+			&i.SourcePlanID,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -157,6 +170,166 @@ func (q *Queries) Plans_GetByUserId(ctx context.Context, arg Plans_GetByUserIdPa
 	return items, nil
 }
 
+const plans_ListAccessible = `-- name: Plans_ListAccessible :many
+SELECT plans.id, plans.name, plans.description, plans.user_id, plans.is_template, plans.is_public, plans.start_date, plans.source_plan_id, plans.created_at, plans.updated_at, plan_collaborators.permission AS shared_permission
+FROM plans
+    LEFT JOIN plan_collaborators ON plan_collaborators.plan_id = plans.id
+    AND plan_collaborators.user_id = $1::BIGINT
+    AND NOT EXISTS (
+        SELECT 1
+        FROM plan_share_links
+        WHERE
+            plan_share_links.id = plan_collaborators.share_link_id
+            AND (
+                plan_share_links.revoked_at IS NOT NULL
+                OR plan_share_links.expires_at <= CURRENT_TIMESTAMP
+            )
+    )
+WHERE (
+        plans.user_id = $1::BIGINT
+        OR plan_collaborators.user_id IS NOT NULL
+    )
+    AND (
+        plans.is_template = $2
+        OR $2 IS NULL
+    )
+    AND (
+        plans.is_public = $3
+        OR $3 IS NULL
+    )
+    AND (
+        (plan_collaborators.user_id IS NOT NULL) = $4::BOOLEAN
+        OR $4 IS NULL
+    )
+ORDER BY plans.updated_at DESC
+LIMIT $6::int
+OFFSET $5::int
+`
+
+type Plans_ListAccessibleParams struct {
+	UserID     int64
+	IsTemplate pgtype.Bool
+	IsPublic   pgtype.Bool
+	Shared     pgtype.Bool
+	Offset     int32
+	Limit      int32
+}
+
+type Plans_ListAccessibleRow struct {
+	ID               int64
+	Name             string
+	Description      string
+	UserID           int64
+	IsTemplate       bool
+	IsPublic         bool
+	StartDate        pgtype.Date
+	SourcePlanID     pgtype.Int8
+	CreatedAt        pgtype.Timestamp
+	UpdatedAt        pgtype.Timestamp
+	SharedPermission pgtype.Text
+}
+
+func (q *Queries) Plans_ListAccessible(ctx context.Context, arg Plans_ListAccessibleParams) ([]Plans_ListAccessibleRow, error) {
+	rows, err := q.db.Query(ctx, plans_ListAccessible,
+		arg.UserID,
+		arg.IsTemplate,
+		arg.IsPublic,
+		arg.Shared,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Plans_ListAccessibleRow
+	for rows.Next() {
+		var i Plans_ListAccessibleRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.UserID,
+			&i.IsTemplate,
+			&i.IsPublic,
+			&i.StartDate,
+			&i.SourcePlanID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SharedPermission,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const plans_ListBySourcePlanId = `-- name: Plans_ListBySourcePlanId :many
+SELECT id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at FROM plans WHERE source_plan_id = $1 ORDER BY user_id, id
+`
+
+func (q *Queries) Plans_ListBySourcePlanId(ctx context.Context, sourcePlanID pgtype.Int8) ([]Plan, error) {
+	rows, err := q.db.Query(ctx, plans_ListBySourcePlanId, sourcePlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Plan
+	for rows.Next() {
+		var i Plan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.UserID,
+			&i.IsTemplate,
+			&i.IsPublic,
+			&i.StartDate,
+			&i.SourcePlanID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const plans_SetSourcePlanId = `-- name: Plans_SetSourcePlanId :one
+UPDATE plans SET source_plan_id = $1 WHERE id = $2 RETURNING id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at
+`
+
+type Plans_SetSourcePlanIdParams struct {
+	SourcePlanID pgtype.Int8
+	ID           int64
+}
+
+func (q *Queries) Plans_SetSourcePlanId(ctx context.Context, arg Plans_SetSourcePlanIdParams) (Plan, error) {
+	row := q.db.QueryRow(ctx, plans_SetSourcePlanId, arg.SourcePlanID, arg.ID)
+	var i Plan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.UserID,
+		&i.IsTemplate,
+		&i.IsPublic,
+		&i.StartDate,
+		&i.SourcePlanID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const plans_UpdateOne = `-- name: Plans_UpdateOne :one
 UPDATE plans
 SET
@@ -164,9 +337,10 @@ SET
     description = $2,
     is_template = $3,
     is_public = $4,
+    start_date = $5,
     updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = $5 RETURNING id, name, description, user_id, is_template, is_public, created_at, updated_at
+    id = $6 RETURNING id, name, description, user_id, is_template, is_public, start_date, source_plan_id, created_at, updated_at
 `
 
 type Plans_UpdateOneParams struct {
@@ -174,6 +348,7 @@ type Plans_UpdateOneParams struct {
 	Description string
 	IsTemplate  bool
 	IsPublic    bool
+	StartDate   pgtype.Date
 	ID          int64
 }
 
@@ -183,6 +358,7 @@ func (q *Queries) Plans_UpdateOne(ctx context.Context, arg Plans_UpdateOneParams
 		arg.Description,
 		arg.IsTemplate,
 		arg.IsPublic,
+		arg.StartDate,
 		arg.ID,
 	)
 	var i Plan
@@ -193,6 +369,8 @@ func (q *Queries) Plans_UpdateOne(ctx context.Context, arg Plans_UpdateOneParams
 		&i.UserID,
 		&i.IsTemplate,
 		&i.IsPublic,
+		&i.StartDate,
+		&i.SourcePlanID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
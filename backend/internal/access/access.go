@@ -0,0 +1,159 @@
+// Package access decides what a user may do with data that belongs to another user.
+// Everyone may read and change their own data and read public records. The owners and
+// coaches of a team may also read the data of the team's athletes, but athletes never
+// see each other's data. Plans may further be shared with collaborators who view,
+// comment on or edit them.
+package access
+
+import "slices"
+
+// Roles of a team member
+const (
+	RoleOwner   = "owner"
+	RoleCoach   = "coach"
+	RoleAthlete = "athlete"
+)
+
+var Roles = []string{RoleOwner, RoleCoach, RoleAthlete}
+
+// ValidRole tells whether the role is one of Roles
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// Manages tells whether the role coaches the team's athletes: it may read their data,
+// assign them plans and invite athletes
+func Manages(role string) bool {
+	return role == RoleOwner || role == RoleCoach
+}
+
+// CanInvite tells whether a member with the role may invite a user to the role invited.
+// Owners invite anyone; coaches invite athletes.
+func CanInvite(role string, invited string) bool {
+	if role == RoleOwner {
+		return true
+	}
+	return role == RoleCoach && invited == RoleAthlete
+}
+
+// Membership is a user's role in a team
+type Membership struct {
+	TeamID int64
+	UserID int64
+	Role   string
+}
+
+// Coaches tells whether the coach owns or coaches a team the athlete trains with
+func Coaches(coach int64, athlete int64, memberships []Membership) bool {
+	for _, membership := range memberships {
+		if membership.UserID != coach || !Manages(membership.Role) {
+			continue
+		}
+		if slices.ContainsFunc(memberships, func(other Membership) bool {
+			return other.TeamID == membership.TeamID && other.UserID == athlete && other.Role == RoleAthlete
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnerCount returns how many of the memberships are owners
+func OwnerCount(memberships []Membership) int {
+	count := 0
+	for _, membership := range memberships {
+		if membership.Role == RoleOwner {
+			count++
+		}
+	}
+	return count
+}
+
+// Level is what a request does with a record
+type Level int
+
+const (
+	Read Level = iota
+	Comment
+	Write
+	// Manage is reserved to the owner, e.g. deleting or sharing a plan
+	Manage
+)
+
+// Permissions of a plan collaborator
+const (
+	PermissionView    = "view"
+	PermissionComment = "comment"
+	PermissionEdit    = "edit"
+)
+
+var Permissions = []string{PermissionView, PermissionComment, PermissionEdit}
+
+// permissionLevels is the highest level each permission allows
+var permissionLevels = map[string]Level{
+	PermissionView:    Read,
+	PermissionComment: Comment,
+	PermissionEdit:    Write,
+}
+
+// ValidPermission tells whether the permission is one of Permissions
+func ValidPermission(permission string) bool {
+	_, ok := permissionLevels[permission]
+	return ok
+}
+
+// PermissionAllows tells whether a collaborator with the permission may access a plan at
+// the level. An empty permission allows nothing.
+func PermissionAllows(permission string, level Level) bool {
+	allowed, ok := permissionLevels[permission]
+	return ok && level <= allowed
+}
+
+// Strongest returns the permission of the list that allows the most, or an empty string
+func Strongest(permissions []string) string {
+	strongest := ""
+	for _, permission := range permissions {
+		if !ValidPermission(permission) {
+			continue
+		}
+		if strongest == "" || permissionLevels[permission] > permissionLevels[strongest] {
+			strongest = permission
+		}
+	}
+	return strongest
+}
+
+// Resource is the record a request reads or changes. Records without an owner belong to
+// the shared library and are public. PlanIDs are the plans the record is part of, whose
+// collaborators may access it with the Permission they were given.
+type Resource struct {
+	OwnerID    *int64
+	Public     bool
+	PlanIDs    []int64
+	Permission string
+}
+
+// Allowed tells whether the actor may access the resource at the level, given the
+// memberships of the teams the actor shares with its owner
+func Allowed(actor int64, resource Resource, level Level, memberships []Membership) bool {
+	if resource.OwnerID == nil {
+		return level == Read
+	}
+	if *resource.OwnerID == actor {
+		return true
+	}
+	if level == Manage {
+		return false
+	}
+	if PermissionAllows(resource.Permission, level) {
+		return true
+	}
+	// Coaches read and comment on their athletes' data but do not change it
+	if level == Comment {
+		return Coaches(actor, *resource.OwnerID, memberships)
+	}
+	if level != Read {
+		return false
+	}
+	return resource.Public || Coaches(actor, *resource.OwnerID, memberships)
+}
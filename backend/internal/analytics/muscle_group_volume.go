@@ -0,0 +1,76 @@
+package analytics
+
+import (
+	"backend/internal/types"
+	"sort"
+)
+
+// SecondaryMuscleGroupWeight is how much a set counts toward a secondary muscle group
+const SecondaryMuscleGroupWeight = 0.5
+
+// PrescriptionVolume is the slice of a prescription needed to attribute its volume to muscle groups
+type PrescriptionVolume struct {
+	Sets      int32
+	Reps      int32
+	Frequency int32
+	// SetReps holds the reps of each set when the prescription has a set scheme
+	SetReps               []int32
+	PrimaryMuscleGroups   []string
+	SecondaryMuscleGroups []string
+}
+
+// MuscleGroupVolume totals sets and reps per muscle group across prescriptions.
+// Each prescription is multiplied by how often its group is trained in the interval.
+// Results are sorted by weighted sets, highest first.
+func MuscleGroupVolume(prescriptions []PrescriptionVolume) []types.MuscleGroupVolume {
+	volumes := make(map[string]*types.MuscleGroupVolume)
+	get := func(muscleGroup string) *types.MuscleGroupVolume {
+		if volume, ok := volumes[muscleGroup]; ok {
+			return volume
+		}
+		volume := &types.MuscleGroupVolume{MuscleGroup: muscleGroup}
+		volumes[muscleGroup] = volume
+		return volume
+	}
+
+	for _, prescription := range prescriptions {
+		frequency := prescription.Frequency
+		if frequency < 1 {
+			frequency = 1
+		}
+		sets := prescription.Sets * frequency
+		reps := prescription.Sets * prescription.Reps * frequency
+		if len(prescription.SetReps) > 0 {
+			sets = int32(len(prescription.SetReps)) * frequency
+			reps = 0
+			for _, setReps := range prescription.SetReps {
+				reps += setReps * frequency
+			}
+		}
+
+		for _, muscleGroup := range prescription.PrimaryMuscleGroups {
+			volume := get(muscleGroup)
+			volume.PrimarySets += sets
+			volume.WeightedSets += float64(sets)
+			volume.TotalReps += reps
+		}
+		for _, muscleGroup := range prescription.SecondaryMuscleGroups {
+			volume := get(muscleGroup)
+			volume.SecondarySets += sets
+			volume.WeightedSets += float64(sets) * SecondaryMuscleGroupWeight
+			volume.TotalReps += reps
+		}
+	}
+
+	result := make([]types.MuscleGroupVolume, 0, len(volumes))
+	for _, volume := range volumes {
+		result = append(result, *volume)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].WeightedSets != result[j].WeightedSets {
+			return result[i].WeightedSets > result[j].WeightedSets
+		}
+		return result[i].MuscleGroup < result[j].MuscleGroup
+	})
+	return result
+}
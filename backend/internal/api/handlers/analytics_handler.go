@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	"backend/internal/analytics"
+	api_utils "backend/internal/api/utils"
+	"encoding/json"
+	"net/http"
+)
+
+type AnalyticsHandler struct {
+	Db *db.Database
+}
+
+// MuscleGroupVolume breaks a plan's prescribed volume down by muscle group,
+// optionally restricted to a single interval. excludeDeloads=true leaves deload
+// intervals out.
+func (h *AnalyticsHandler) MuscleGroupVolume(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+
+	planId := filterParser.GetIntFilterOrZero("planId")
+	intervalId := filterParser.GetIntFilterOrZero("intervalId")
+	excludeDeloads := filterParser.GetBoolFilterOrFalse("excludeDeloads")
+
+	if planId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: planId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		analyticsRepo := repository.NewAnalyticsRepository(queries)
+
+		rows, err := analyticsRepo.ListPrescriptionMuscleGroups(r.Context(), planId, intervalId, excludeDeloads)
+		if err != nil {
+			return err
+		}
+
+		prescriptions := make([]analytics.PrescriptionVolume, len(rows))
+		for i, row := range rows {
+			prescriptions[i] = analytics.PrescriptionVolume{
+				Sets:                  row.Sets,
+				Reps:                  row.Reps.Int32,
+				Frequency:             row.Frequency,
+				SetReps:               row.SetReps,
+				PrimaryMuscleGroups:   row.PrimaryMuscleGroups,
+				SecondaryMuscleGroups: row.SecondaryMuscleGroups,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(analytics.MuscleGroupVolume(prescriptions))
+	})
+}
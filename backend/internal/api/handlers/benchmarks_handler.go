@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/benchmarks"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type BenchmarksHandler struct {
+	Db *db.Database
+}
+
+// BenchmarkApiArgs records a benchmark. Without an exercise variation it applies to
+// every variation; the date defaults to today.
+type BenchmarkApiArgs struct {
+	ExerciseVariationId int64   `json:"exerciseVariationId"`
+	ParameterTypeId     int64   `json:"parameterTypeId"`
+	Value               float64 `json:"value"`
+	RecordedOn          string  `json:"recordedOn"`
+}
+
+// Helper function to convert a DB benchmark to an API benchmark
+func dbBenchmarkToApiBenchmark(benchmark db.Benchmark) types.Benchmark {
+	return types.Benchmark{
+		ID:                  benchmark.ID,
+		UserID:              benchmark.UserID,
+		ExerciseVariationId: utils.If(benchmark.ExerciseVariationID.Valid, &benchmark.ExerciseVariationID.Int64, nil),
+		ParameterTypeId:     benchmark.ParameterTypeID,
+		Value:               benchmark.Value,
+		RecordedOn:          utils.DateToString(benchmark.RecordedOn),
+		CreatedAt:           benchmark.CreatedAt.Time.String(),
+		UpdatedAt:           benchmark.UpdatedAt.Time.String(),
+	}
+}
+
+// Helper function to read an optional date, today when it is left out
+func dateOrToday(value string) (pgtype.Date, error) {
+	if value == "" {
+		return utils.TimeToDate(time.Now()), nil
+	}
+	return utils.StringToDate(value)
+}
+
+// resolvePercentages fills in the resolved value of every set parameter for the athlete
+// on the date. Percentages resolve against the latest benchmark as of the date and are
+// left unresolved without one.
+func resolvePercentages(ctx context.Context, queries *db.Queries, prescriptions []types.IntervalExercisePrescription, userId int64, date pgtype.Date) error {
+	dbBenchmarks, err := repository.NewBenchmarksRepository(queries).ListLatest(ctx, userId, date)
+	if err != nil {
+		return err
+	}
+	latest := make([]benchmarks.Benchmark, len(dbBenchmarks))
+	for i, dbBenchmark := range dbBenchmarks {
+		latest[i] = benchmarks.Benchmark{
+			ID:              dbBenchmark.ID,
+			VariationID:     dbBenchmark.ExerciseVariationID.Int64,
+			ParameterTypeID: dbBenchmark.ParameterTypeID,
+			Value:           dbBenchmark.Value,
+			Date:            dbBenchmark.RecordedOn.Time,
+		}
+	}
+
+	for i := range prescriptions {
+		prescription := &prescriptions[i]
+		for j := range prescription.SetScheme {
+			for k := range prescription.SetScheme[j].Parameters {
+				parameter := &prescription.SetScheme[j].Parameters[k]
+				value := parameter.Value
+				if parameter.IsPercentage {
+					benchmark, ok := benchmarks.Latest(latest, prescription.ExerciseVariationId, parameter.ParameterTypeId, date.Time)
+					if !ok {
+						continue
+					}
+					value = benchmarks.Resolve(parameter.Value, benchmark)
+				}
+				parameter.ResolvedValue = &value
+			}
+		}
+	}
+	return nil
+}
+
+// List returns the user's benchmarks, most recent first, optionally for one exercise
+// variation or parameter type
+func (h *BenchmarksHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	filterParser := api_utils.NewFilterParser(r, true)
+	filter := repository.BenchmarkFilter{
+		ExerciseVariationID: filterParser.GetIntFilterOrZero("exerciseVariationId"),
+		ParameterTypeID:     filterParser.GetIntFilterOrZero("parameterTypeId"),
+	}
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbBenchmarks, err := repository.NewBenchmarksRepository(queries).List(r.Context(), userId, filter, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		result := make([]types.Benchmark, len(dbBenchmarks))
+		for i, dbBenchmark := range dbBenchmarks {
+			result[i] = dbBenchmarkToApiBenchmark(dbBenchmark)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Create records a benchmark for the user
+func (h *BenchmarksHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var args BenchmarkApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.ParameterTypeId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: parameterTypeId")
+		return
+	}
+	if args.Value <= 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Value must be positive")
+		return
+	}
+	recordedOn, err := dateOrToday(args.RecordedOn)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if args.ExerciseVariationId != 0 {
+			params, err := repository.NewExerciseVariationsRepository(queries).ListParams(r.Context(), args.ExerciseVariationId)
+			if err != nil {
+				return err
+			}
+			found := false
+			for _, param := range params {
+				found = found || param.ParameterTypeID == args.ParameterTypeId
+			}
+			if !found {
+				api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Parameter type %d is not a parameter of exercise variation %d", args.ParameterTypeId, args.ExerciseVariationId))
+				return nil
+			}
+		} else {
+			parameterTypes, err := repository.NewParameterTypesRepository(queries).List(r.Context(), repository.ListParameterTypesParams{
+				ParameterTypeId: args.ParameterTypeId,
+				Limit:           1,
+			})
+			if err != nil {
+				return err
+			}
+			if len(parameterTypes) == 0 {
+				api_utils.WriteError(w, http.StatusNotFound, "Parameter type not found")
+				return nil
+			}
+		}
+
+		dbBenchmark, err := repository.NewBenchmarksRepository(queries).Create(r.Context(), userId, args.ExerciseVariationId, args.ParameterTypeId, args.Value, recordedOn)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Recorded benchmark %d for user %d", dbBenchmark.ID, userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbBenchmarkToApiBenchmark(*dbBenchmark))
+	})
+}
+
+// Update corrects the value or date of a benchmark
+func (h *BenchmarksHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	benchmarkId, err := api_utils.ParseBigInt(chi.URLParam(r, "benchmarkId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid benchmark ID")
+		return
+	}
+
+	var args BenchmarkApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.Value <= 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Value must be positive")
+		return
+	}
+	recordedOn, err := dateOrToday(args.RecordedOn)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbBenchmark, err := repository.NewBenchmarksRepository(queries).Update(r.Context(), benchmarkId, userId, args.Value, recordedOn)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Benchmark not found")
+				return nil
+			}
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbBenchmarkToApiBenchmark(*dbBenchmark))
+	})
+}
+
+// Delete removes a benchmark; percentages fall back to the one before it
+func (h *BenchmarksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	benchmarkId, err := api_utils.ParseBigInt(chi.URLParam(r, "benchmarkId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid benchmark ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if err := repository.NewBenchmarksRepository(queries).Delete(r.Context(), benchmarkId, userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Benchmark not found")
+				return nil
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
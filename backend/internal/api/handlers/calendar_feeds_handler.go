@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/ical"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// calendarFeedPath is where feeds are served; the token completes the path
+const calendarFeedPath = "/api/v1/calendar/"
+
+type CalendarFeedsHandler struct {
+	Db *db.Database
+}
+
+// Helper function to convert a DB feed to an API feed
+func dbCalendarFeedToApiCalendarFeed(feed db.CalendarFeed) types.CalendarFeed {
+	return types.CalendarFeed{
+		ID:        feed.ID,
+		UserID:    feed.UserID,
+		Token:     feed.Token,
+		Path:      calendarFeedPath + feed.Token + ".ics",
+		CreatedAt: feed.CreatedAt.Time.String(),
+	}
+}
+
+// Helper function to describe a session in a calendar
+func sessionToCalendarEvent(row db.ScheduledSessions_ListByUserIdRow) ical.Event {
+	var description []string
+	if row.IntervalName.Valid {
+		description = append(description, row.IntervalName.String)
+	}
+	if row.GroupDescription != "" {
+		description = append(description, row.GroupDescription)
+	}
+	if row.Status == repository.SessionStatusSkipped {
+		description = append(description, "Skipped")
+	}
+	if row.ScheduledDate.Time != row.OriginalDate.Time {
+		description = append(description, "Moved from "+utils.DateToString(row.OriginalDate))
+	}
+
+	return ical.Event{
+		UID:         fmt.Sprintf("session-%d@rocket", row.ID),
+		Date:        row.ScheduledDate.Time,
+		Summary:     row.GroupName + " (" + row.PlanName + ")",
+		Description: strings.Join(description, "\n"),
+		Updated:     row.UpdatedAt.Time,
+		// Sessions only change by being moved or skipped, which bumps updated_at
+		Sequence: int(row.UpdatedAt.Time.Sub(row.CreatedAt.Time).Seconds()),
+	}
+}
+
+// List returns the user's calendar feeds
+func (h *CalendarFeedsHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbFeeds, err := repository.NewCalendarFeedsRepository(queries).ListByUserId(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		feeds := make([]types.CalendarFeed, len(dbFeeds))
+		for i, dbFeed := range dbFeeds {
+			feeds[i] = dbCalendarFeedToApiCalendarFeed(dbFeed)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(feeds)
+	})
+}
+
+// Create issues a new feed URL for the user
+func (h *CalendarFeedsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbFeed, err := repository.NewCalendarFeedsRepository(queries).Create(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Created calendar feed %d for user %d", dbFeed.ID, userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbCalendarFeedToApiCalendarFeed(*dbFeed))
+	})
+}
+
+// Delete revokes a feed, so its URL stops working
+func (h *CalendarFeedsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	feedId, err := api_utils.ParseBigInt(chi.URLParam(r, "feedId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid feed ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if err := repository.NewCalendarFeedsRepository(queries).Delete(r.Context(), feedId, userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Calendar feed not found")
+				return nil
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Feed serves the scheduled sessions of the feed's user as an iCalendar document. The
+// token in the URL is the only credential, so calendar apps can subscribe to it.
+func (h *CalendarFeedsHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbFeed, err := repository.NewCalendarFeedsRepository(queries).GetByToken(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Calendar feed not found")
+				return nil
+			}
+			return err
+		}
+
+		rows, err := repository.NewScheduledSessionsRepository(queries).ListByUserId(r.Context(), dbFeed.UserID)
+		if err != nil {
+			return err
+		}
+
+		calendar := ical.Calendar{Name: "Rocket training", Events: make([]ical.Event, len(rows))}
+		for i, row := range rows {
+			calendar.Events[i] = sessionToCalendarEvent(row)
+		}
+
+		var body bytes.Buffer
+		if err := ical.Write(&body, calendar); err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body.Bytes())
+		return err
+	})
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+type CategoriesHandler struct {
+	Db *db.Database
+}
+
+type CreateCategoryApiArgs struct {
+	Name   string `json:"name"`
+	Icon   string `json:"icon"`
+	UserId int64  `json:"userId"`
+}
+
+type UpdateCategoryApiArgs struct {
+	Name string `json:"name"`
+	Icon string `json:"icon"`
+}
+
+// Helper function to convert DB Category to API Category
+func dbCategoryToApiCategory(dbCategory db.Category) types.Category {
+	return types.Category{
+		ID:        dbCategory.ID,
+		Name:      dbCategory.Name,
+		Icon:      dbCategory.Icon,
+		UserID:    utils.If(dbCategory.UserID.Valid, &dbCategory.UserID.Int64, nil),
+		CreatedAt: dbCategory.CreatedAt.Time.String(),
+		UpdatedAt: dbCategory.UpdatedAt.Time.String(),
+	}
+}
+
+// Helper function to convert slice of DB Categories to API Categories
+func dbCategoriesToApiCategories(dbCategories []db.Category) []types.Category {
+	result := make([]types.Category, len(dbCategories))
+	for i, dbCategory := range dbCategories {
+		result[i] = dbCategoryToApiCategory(dbCategory)
+	}
+	return result
+}
+
+func (h *CategoriesHandler) List(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+
+	categoryId := filterParser.GetIntFilterOrZero("id")
+	userId := filterParser.GetIntFilterOrZero("userId")
+	exerciseId := filterParser.GetIntFilterOrZero("exerciseId")
+
+	if categoryId == 0 && userId == 0 && exerciseId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId, id, or exerciseId")
+		return
+	}
+
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetOffset(0)
+
+	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		categoryRepo := repository.NewCategoriesRepository(queries)
+
+		dbCategories, err := categoryRepo.List(r.Context(), repository.CategoryListParams{
+			CategoryId: categoryId,
+			UserId:     userId,
+			ExerciseId: exerciseId,
+			Limit:      limit,
+			Offset:     int32(offset),
+		})
+		if err != nil {
+			return err
+		}
+
+		apiCategories := dbCategoriesToApiCategories(dbCategories)
+
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(apiCategories)
+	})
+
+	if success {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *CategoriesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var args CreateCategoryApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if args.Name == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+	if args.Icon == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: icon")
+		return
+	}
+	if args.UserId <= 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid or missing userId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		categoryRepo := repository.NewCategoriesRepository(queries)
+
+		dbCategory, err := categoryRepo.Create(r.Context(), args.Name, args.Icon, args.UserId)
+		if err != nil {
+			return err
+		}
+
+		apiCategory := dbCategoryToApiCategory(*dbCategory)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(apiCategory)
+	})
+}
+
+func (h *CategoriesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	var args UpdateCategoryApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if args.Name == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+	if args.Icon == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: icon")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		categoryRepo := repository.NewCategoriesRepository(queries)
+
+		existing, err := categoryRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Category not found")
+				return nil
+			}
+			return err
+		}
+
+		if !existing.UserID.Valid {
+			api_utils.WriteError(w, http.StatusForbidden, "Global categories cannot be modified")
+			return nil
+		}
+
+		dbCategory, err := categoryRepo.Update(r.Context(), id, args.Name, args.Icon)
+		if err != nil {
+			return err
+		}
+
+		apiCategory := dbCategoryToApiCategory(*dbCategory)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiCategory)
+	})
+}
+
+func (h *CategoriesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		categoryRepo := repository.NewCategoriesRepository(queries)
+
+		existing, err := categoryRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Category not found")
+				return nil
+			}
+			return err
+		}
+
+		if !existing.UserID.Valid {
+			api_utils.WriteError(w, http.StatusForbidden, "Global categories cannot be deleted")
+			return nil
+		}
+
+		if _, err := categoryRepo.Delete(r.Context(), id); err != nil {
+			return err
+		}
+
+		log.Printf("Successfully deleted category with ID: %d", id)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
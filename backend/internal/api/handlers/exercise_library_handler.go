@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/library"
+	"backend/internal/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// libraryFetchLimit caps how many exercises, parameter types and categories a single
+// import or export reads
+const libraryFetchLimit = 10000
+
+// maxLibrarySize caps the size of an uploaded library file
+const maxLibrarySize = 10 << 20 // 10 MiB
+
+// defaultImportedCategoryIcon is used for categories an import has to create
+const defaultImportedCategoryIcon = "tag"
+
+type ExerciseLibraryHandler struct {
+	Db *db.Database
+}
+
+// libraryFormat picks the library format from the format query parameter, falling back
+// to the request's content type and then to JSON
+func libraryFormat(r *http.Request, filterParser *api_utils.FilterParser) (string, error) {
+	if format := strings.ToLower(filterParser.GetStringFilter("format")); format != "" {
+		if format != library.FormatJSON && format != library.FormatCSV {
+			return "", fmt.Errorf("unsupported format: %q", format)
+		}
+		return format, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return library.FormatCSV, nil
+	}
+	return library.FormatJSON, nil
+}
+
+// Helper function to build a portable library from the exercises matching the given
+// filters. It also returns how each variation is referenced by name.
+func buildExerciseLibrary(ctx context.Context, queries *db.Queries, params repository.ExerciseListParams) (*library.Library, map[int64]library.VariationRef, error) {
+	exerciseRepo := repository.NewExercisesRepository(queries)
+	variationRepo := repository.NewExerciseVariationsRepository(queries)
+	categoryRepo := repository.NewCategoriesRepository(queries)
+
+	params.Limit = libraryFetchLimit
+	dbExercises, err := exerciseRepo.ListExercises(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exerciseNames := make(map[int64]string)
+	for _, dbExercise := range dbExercises {
+		exerciseNames[dbExercise.ID] = dbExercise.Name
+	}
+
+	exerciseIds := make([]int64, len(dbExercises))
+	for i, dbExercise := range dbExercises {
+		exerciseIds[i] = dbExercise.ID
+	}
+
+	categoriesByExercise, err := categoryRepo.ListByExerciseIds(ctx, exerciseIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variationRows, err := variationRepo.ListByExerciseIds(ctx, exerciseIds)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Rows are ordered by exercise and variation, one row per parameter
+	variationsByExercise := make(map[int64][]library.Variation)
+	parameterTypes := []library.ParameterType{}
+	seenParameterTypes := make(map[string]bool)
+	refs := make(map[int64]library.VariationRef)
+	lastVariationId := int64(0)
+	for _, row := range variationRows {
+		refs[row.ID] = library.VariationRef{Exercise: exerciseNames[row.ExerciseID], Variation: row.Name}
+		variations := variationsByExercise[row.ExerciseID]
+		if row.ID != lastVariationId {
+			variations = append(variations, library.Variation{Name: row.Name, Parameters: []library.Parameter{}})
+			lastVariationId = row.ID
+		}
+
+		if row.PtName.Valid {
+			variation := &variations[len(variations)-1]
+			variation.Parameters = append(variation.Parameters, library.Parameter{
+				ParameterType: row.PtName.String,
+				Locked:        row.Locked.Bool,
+			})
+
+			if !seenParameterTypes[strings.ToLower(row.PtName.String)] {
+				seenParameterTypes[strings.ToLower(row.PtName.String)] = true
+				parameterTypes = append(parameterTypes, library.ParameterType{
+					Name:        row.PtName.String,
+					DataType:    row.PtDataType.String,
+					DefaultUnit: row.PtDefaultUnit.String,
+					MinValue:    utils.If(row.PtMinValue.Valid, &row.PtMinValue.Float64, nil),
+					MaxValue:    utils.If(row.PtMaxValue.Valid, &row.PtMaxValue.Float64, nil),
+				})
+			}
+		}
+		variationsByExercise[row.ExerciseID] = variations
+	}
+
+	lib := &library.Library{
+		Version:        library.FormatVersion,
+		ParameterTypes: parameterTypes,
+		Exercises:      make([]library.Exercise, len(dbExercises)),
+	}
+	for i, dbExercise := range dbExercises {
+		categories := []string{}
+		for _, category := range categoriesByExercise[dbExercise.ID] {
+			categories = append(categories, category.Name)
+		}
+
+		lib.Exercises[i] = library.Exercise{
+			Name:                  dbExercise.Name,
+			Description:           dbExercise.Description,
+			PrimaryMuscleGroups:   utils.If(dbExercise.PrimaryMuscleGroups != nil, dbExercise.PrimaryMuscleGroups, []string{}),
+			SecondaryMuscleGroups: utils.If(dbExercise.SecondaryMuscleGroups != nil, dbExercise.SecondaryMuscleGroups, []string{}),
+			Equipment:             utils.If(dbExercise.Equipment != nil, dbExercise.Equipment, []string{}),
+			Laterality:            dbExercise.Laterality,
+			Categories:            categories,
+			Variations:            utils.If(variationsByExercise[dbExercise.ID] != nil, variationsByExercise[dbExercise.ID], []library.Variation{}),
+		}
+	}
+
+	return lib, refs, nil
+}
+
+// Helper function to load what a user already has, for matching an import by name
+func loadExistingLibrary(ctx context.Context, queries *db.Queries, userId int64) (library.Existing, error) {
+	existing := library.Existing{
+		Exercises:      make(map[string]int64),
+		ParameterTypes: make(map[string]library.ExistingParameterType),
+		Categories:     make(map[string]int64),
+	}
+
+	dbExercises, err := repository.NewExercisesRepository(queries).ListExercises(ctx, repository.ExerciseListParams{
+		UserID: userId,
+		Limit:  libraryFetchLimit,
+	})
+	if err != nil {
+		return existing, err
+	}
+	for _, dbExercise := range dbExercises {
+		existing.Exercises[strings.ToLower(dbExercise.Name)] = dbExercise.ID
+	}
+
+	dbParameterTypes, err := repository.NewParameterTypesRepository(queries).List(ctx, repository.ListParameterTypesParams{
+		UserId: userId,
+		Limit:  libraryFetchLimit,
+	})
+	if err != nil {
+		return existing, err
+	}
+	for _, dbParameterType := range dbParameterTypes {
+		existing.ParameterTypes[strings.ToLower(dbParameterType.Name)] = library.ExistingParameterType{
+			ID: dbParameterType.ID,
+			ParameterType: library.ParameterType{
+				Name:        dbParameterType.Name,
+				DataType:    dbParameterType.DataType,
+				DefaultUnit: dbParameterType.DefaultUnit,
+				MinValue:    utils.If(dbParameterType.MinValue.Valid, &dbParameterType.MinValue.Float64, nil),
+				MaxValue:    utils.If(dbParameterType.MaxValue.Valid, &dbParameterType.MaxValue.Float64, nil),
+			},
+		}
+	}
+
+	dbCategories, err := repository.NewCategoriesRepository(queries).List(ctx, repository.CategoryListParams{
+		UserId: userId,
+		Limit:  libraryFetchLimit,
+	})
+	if err != nil {
+		return existing, err
+	}
+	for _, dbCategory := range dbCategories {
+		existing.Categories[strings.ToLower(dbCategory.Name)] = dbCategory.ID
+	}
+
+	return existing, nil
+}
+
+// Helper function to create everything an import plan describes
+func applyImportPlan(ctx context.Context, queries *db.Queries, userId int64, plan *library.ImportPlan, existing library.Existing) error {
+	exerciseRepo := repository.NewExercisesRepository(queries)
+	variationRepo := repository.NewExerciseVariationsRepository(queries)
+	parameterTypeRepo := repository.NewParameterTypesRepository(queries)
+	categoryRepo := repository.NewCategoriesRepository(queries)
+
+	parameterTypeIds := make(map[string]int64)
+	for name, parameterType := range existing.ParameterTypes {
+		parameterTypeIds[name] = parameterType.ID
+	}
+	for _, parameterType := range plan.ParameterTypes {
+		dbParameterType, err := parameterTypeRepo.Create(ctx, repository.CreateParameterTypeParams{
+			Name:        &parameterType.Name,
+			DataType:    &parameterType.DataType,
+			DefaultUnit: &parameterType.DefaultUnit,
+			MinValue:    parameterType.MinValue,
+			MaxValue:    parameterType.MaxValue,
+		})
+		if err != nil {
+			return err
+		}
+		if err := parameterTypeRepo.LinkToUser(ctx, userId, dbParameterType.ID); err != nil {
+			return err
+		}
+		parameterTypeIds[strings.ToLower(parameterType.Name)] = dbParameterType.ID
+	}
+
+	categoryIds := make(map[string]int64)
+	for name, id := range existing.Categories {
+		categoryIds[name] = id
+	}
+	for _, name := range plan.Categories {
+		dbCategory, err := categoryRepo.Create(ctx, name, defaultImportedCategoryIcon, userId)
+		if err != nil {
+			return err
+		}
+		categoryIds[strings.ToLower(name)] = dbCategory.ID
+	}
+
+	for _, exercise := range plan.Exercises {
+		dbExercise, err := exerciseRepo.CreateExercise(ctx, exercise.Name, exercise.Description, userId, repository.ExerciseMetadata{
+			PrimaryMuscleGroups:   exercise.PrimaryMuscleGroups,
+			SecondaryMuscleGroups: exercise.SecondaryMuscleGroups,
+			Equipment:             exercise.Equipment,
+			Laterality:            exercise.Laterality,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, category := range exercise.Categories {
+			if err := categoryRepo.AddToExercise(ctx, dbExercise.ID, categoryIds[strings.ToLower(category)]); err != nil {
+				return err
+			}
+		}
+
+		for _, variation := range exercise.Variations {
+			dbVariation, err := variationRepo.CreateExerciseVariation(ctx, dbExercise.ID, variation.Name)
+			if err != nil {
+				return err
+			}
+			for _, parameter := range variation.Parameters {
+				parameterTypeId := parameterTypeIds[strings.ToLower(strings.TrimSpace(parameter.ParameterType))]
+				if _, err := variationRepo.AddParam(ctx, dbVariation.ID, parameterTypeId, parameter.Locked); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Export downloads a user's exercise library as JSON or CSV
+func (h *ExerciseLibraryHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	userId := filterParser.GetIntFilterOrZero("userId")
+	if userId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+
+	format, err := libraryFormat(r, filterParser)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		lib, _, err := buildExerciseLibrary(r.Context(), queries, repository.ExerciseListParams{UserID: userId})
+		if err != nil {
+			return err
+		}
+
+		var body bytes.Buffer
+		if err := library.Write(&body, lib, format); err != nil {
+			return err
+		}
+
+		log.Printf("Exported %d exercises for user %d as %s", len(lib.Exercises), userId, format)
+		w.Header().Set("Content-Type", utils.If(format == library.FormatCSV, "text/csv; charset=utf-8", "application/json"))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="exercise-library.%s"`, format))
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body.Bytes())
+		return err
+	})
+}
+
+// Import creates exercises, variations, parameter types and categories from a library
+// file in the request body. Everything is written in one transaction. With dryRun=true
+// the report is returned without writing anything. Exercises that already exist are
+// skipped, or block the import with onConflict=fail.
+func (h *ExerciseLibraryHandler) Import(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	userId := filterParser.GetIntFilterOrZero("userId")
+	if userId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+
+	format, err := libraryFormat(r, filterParser)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	onConflict := filterParser.GetStringFilterWithDefault("onConflict", library.ConflictSkip)
+	if onConflict != library.ConflictSkip && onConflict != library.ConflictFail {
+		api_utils.WriteError(w, http.StatusBadRequest, "onConflict must be skip or fail")
+		return
+	}
+	dryRun := filterParser.GetBoolFilterOrFalse("dryRun")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLibrarySize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			api_utils.WriteError(w, http.StatusRequestEntityTooLarge, "Library file is too large")
+			return
+		}
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	lib, err := library.Parse(bytes.NewReader(body), format)
+	if err != nil {
+		var parseErr *library.ParseError
+		if errors.As(err, &parseErr) {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid library: "+parseErr.Error())
+			return
+		}
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		existing, err := loadExistingLibrary(r.Context(), queries, userId)
+		if err != nil {
+			return err
+		}
+
+		plan := library.Plan(lib, existing, onConflict)
+		report := plan.Report(dryRun)
+
+		if dryRun {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			return json.NewEncoder(w).Encode(report)
+		}
+
+		if plan.Blocked() {
+			for _, issue := range report.Issues {
+				if issue.Blocking {
+					message := issue.Message
+					if issue.Line > 0 {
+						message = fmt.Sprintf("line %d: %s", issue.Line, message)
+					}
+					api_utils.WriteError(w, http.StatusUnprocessableEntity, "Import blocked: "+message+" (run with dryRun=true for the full report)")
+					return nil
+				}
+			}
+		}
+
+		if err := applyImportPlan(r.Context(), queries, userId, plan, existing); err != nil {
+			return err
+		}
+
+		log.Printf("Imported %d exercises for user %d", len(plan.Exercises), userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(report)
+	})
+}
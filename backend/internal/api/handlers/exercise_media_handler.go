@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/media"
+	"backend/internal/storage"
+	"backend/internal/types"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// signedURLExpiry is how long download links returned by the API stay valid
+const signedURLExpiry = 15 * time.Minute
+
+type ExerciseMediaHandler struct {
+	Db      *db.Database
+	Storage storage.Storage
+}
+
+// Helper function to convert DB ExerciseMedium to API ExerciseMedia with signed URLs
+func (h *ExerciseMediaHandler) dbMediaToApiMedia(ctx context.Context, dbMedia db.ExerciseMedium) (types.ExerciseMedia, error) {
+	url, err := h.Storage.SignedURL(ctx, dbMedia.StorageKey, signedURLExpiry)
+	if err != nil {
+		return types.ExerciseMedia{}, err
+	}
+
+	var thumbnailURL *string
+	if dbMedia.ThumbnailKey.Valid {
+		signed, err := h.Storage.SignedURL(ctx, dbMedia.ThumbnailKey.String, signedURLExpiry)
+		if err != nil {
+			return types.ExerciseMedia{}, err
+		}
+		thumbnailURL = &signed
+	}
+
+	return types.ExerciseMedia{
+		ID:           dbMedia.ID,
+		ExerciseID:   dbMedia.ExerciseID,
+		FileName:     dbMedia.FileName,
+		ContentType:  dbMedia.ContentType,
+		SizeBytes:    dbMedia.SizeBytes,
+		URL:          url,
+		ThumbnailURL: thumbnailURL,
+		ExpiresAt:    time.Now().Add(signedURLExpiry).UTC().Format(time.RFC3339),
+		CreatedAt:    dbMedia.CreatedAt.Time.String(),
+	}, nil
+}
+
+func newStorageKey(exerciseId int64, suffix string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("exercises/%d/%s%s", exerciseId, hex.EncodeToString(random), suffix), nil
+}
+
+func (h *ExerciseMediaHandler) List(w http.ResponseWriter, r *http.Request) {
+	exerciseId, err := api_utils.ParseBigInt(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		mediaRepo := repository.NewExerciseMediaRepository(queries)
+
+		dbMedia, err := mediaRepo.ListByExerciseId(r.Context(), exerciseId)
+		if err != nil {
+			return err
+		}
+
+		apiMedia := make([]types.ExerciseMedia, len(dbMedia))
+		for i, item := range dbMedia {
+			if apiMedia[i], err = h.dbMediaToApiMedia(r.Context(), item); err != nil {
+				return err
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiMedia)
+	})
+}
+
+// Upload accepts a multipart form with a single "file" field. The content type is
+// sniffed from the file itself and checked against the size limit for that type.
+func (h *ExerciseMediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	exerciseId, err := api_utils.ParseBigInt(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	// Leave headroom for the multipart envelope around the largest allowed file
+	r.Body = http.MaxBytesReader(w, r.Body, media.MaxVideoSize+(1<<20))
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			api_utils.WriteError(w, http.StatusRequestEntityTooLarge, "File too large")
+			return
+		}
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: file")
+		return
+	}
+	defer file.Close()
+
+	contentType, err := sniffUpload(file)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if err := media.ValidateSize(contentType, fileHeader.Size); err != nil {
+		api_utils.WriteError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	// The stored files are removed again unless the rows pointing to them are committed
+	var stored []string
+	committed := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		exerciseRepo := repository.NewExercisesRepository(queries)
+		mediaRepo := repository.NewExerciseMediaRepository(queries)
+
+		if _, err := exerciseRepo.GetExerciseById(r.Context(), exerciseId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Exercise not found")
+				return nil
+			}
+			return err
+		}
+
+		storageKey, thumbnailKey, err := h.store(r.Context(), exerciseId, file, fileHeader.Size, contentType)
+		if err != nil {
+			return err
+		}
+		stored = []string{storageKey, thumbnailKey}
+
+		dbMedia, err := mediaRepo.Create(r.Context(), repository.CreateExerciseMediaParams{
+			ExerciseId:   exerciseId,
+			FileName:     filepath.Base(fileHeader.Filename),
+			ContentType:  contentType,
+			SizeBytes:    fileHeader.Size,
+			StorageKey:   storageKey,
+			ThumbnailKey: thumbnailKey,
+		})
+		if err != nil {
+			return err
+		}
+
+		apiMedia, err := h.dbMediaToApiMedia(r.Context(), *dbMedia)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(apiMedia)
+	})
+	if !committed {
+		h.removeObjects(stored...)
+	}
+}
+
+func sniffUpload(file multipart.File) (string, error) {
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return media.DetectContentType(header[:n])
+}
+
+// store uploads the file, plus a thumbnail for images that support one, and returns their keys
+func (h *ExerciseMediaHandler) store(ctx context.Context, exerciseId int64, file multipart.File, size int64, contentType string) (string, string, error) {
+	storageKey, err := newStorageKey(exerciseId, media.Extension(contentType))
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := h.Storage.Put(ctx, storageKey, file, size, contentType); err != nil {
+		return "", "", err
+	}
+
+	if !media.CanThumbnail(contentType) {
+		return storageKey, "", nil
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		h.removeObjects(storageKey, "")
+		return "", "", err
+	}
+
+	thumbnail, err := media.GenerateThumbnail(file, media.ThumbnailMaxDimension)
+	if err != nil {
+		// The original is still useful without a thumbnail
+		log.Printf("Could not generate thumbnail for %s: %v", storageKey, err)
+		return storageKey, "", nil
+	}
+
+	thumbnailKey := storageKey + ".thumb.jpg"
+	if err := h.Storage.Put(ctx, thumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+		h.removeObjects(storageKey, "")
+		return "", "", err
+	}
+
+	return storageKey, thumbnailKey, nil
+}
+
+// removeObjects deletes stored objects on a best-effort basis
+func (h *ExerciseMediaHandler) removeObjects(keys ...string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := h.Storage.Delete(context.Background(), key); err != nil {
+			log.Printf("Failed to delete stored object %s: %v", key, err)
+		}
+	}
+}
+
+func (h *ExerciseMediaHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	exerciseId, err := api_utils.ParseBigInt(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	mediaId, err := api_utils.ParseBigInt(chi.URLParam(r, "mediaId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid media ID")
+		return
+	}
+
+	// The stored files are removed once the rows are gone, so a failed commit never
+	// leaves rows pointing to missing objects
+	var removed []string
+	committed := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		mediaRepo := repository.NewExerciseMediaRepository(queries)
+
+		existing, err := mediaRepo.GetById(r.Context(), mediaId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Media not found")
+				return nil
+			}
+			return err
+		}
+		if existing.ExerciseID != exerciseId {
+			api_utils.WriteError(w, http.StatusNotFound, "Media not found")
+			return nil
+		}
+
+		if _, err := mediaRepo.Delete(r.Context(), mediaId); err != nil {
+			return err
+		}
+
+		removed = []string{existing.StorageKey, existing.ThumbnailKey.String}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	if committed {
+		h.removeObjects(removed...)
+	}
+}
+
+// Download serves objects kept in local storage to holders of a valid signed URL.
+// Other backends hand out their own signed URLs, so this route 404s for them.
+func (h *ExerciseMediaHandler) Download(w http.ResponseWriter, r *http.Request) {
+	localStorage, ok := h.Storage.(*storage.LocalStorage)
+	if !ok {
+		api_utils.WriteError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	key := chi.URLParam(r, "*")
+	query := r.URL.Query()
+	if !localStorage.VerifySignature(key, query.Get("expires"), query.Get("signature")) {
+		api_utils.WriteError(w, http.StatusForbidden, "Invalid or expired signature")
+		return
+	}
+
+	object, err := localStorage.Get(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			api_utils.WriteError(w, http.StatusNotFound, "Not found")
+			return
+		}
+		api_utils.WriteError(w, http.StatusInternalServerError, "Failed to read file")
+		return
+	}
+	defer object.Close()
+
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(object, header)
+	w.Header().Set("Content-Type", http.DetectContentType(header[:n]))
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.WriteHeader(http.StatusOK)
+	w.Write(header[:n])
+	io.Copy(w, object)
+}
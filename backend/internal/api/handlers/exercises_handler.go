@@ -5,6 +5,8 @@ import (
 	"backend/db/repository"
 	api_utils "backend/internal/api/utils"
 	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
 	"encoding/json"
 	"errors"
 	"log"
@@ -19,26 +21,62 @@ type ExercisesHandler struct {
 }
 
 type CreateExerciseApiArgs struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	UserId      int64  `json:"userId"`
+	Name                  string   `json:"name"`
+	Description           string   `json:"description"`
+	UserId                int64    `json:"userId"`
+	CategoryIds           []int64  `json:"categoryIds,omitempty"`
+	PrimaryMuscleGroups   []string `json:"primaryMuscleGroups,omitempty"`
+	SecondaryMuscleGroups []string `json:"secondaryMuscleGroups,omitempty"`
+	Equipment             []string `json:"equipment,omitempty"`
+	Laterality            string   `json:"laterality,omitempty"`
 }
 
 type UpdateExerciseApiArgs struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// CategoryIds replaces the exercise's categories when present; omit it to leave them unchanged
+	CategoryIds []int64 `json:"categoryIds,omitempty"`
+	// Metadata fields behave the same way: omitted fields keep their current values
+	PrimaryMuscleGroups   []string `json:"primaryMuscleGroups,omitempty"`
+	SecondaryMuscleGroups []string `json:"secondaryMuscleGroups,omitempty"`
+	Equipment             []string `json:"equipment,omitempty"`
+	Laterality            string   `json:"laterality,omitempty"`
 }
 
 // Helper function to convert DB Exercise to API Exercise
 func dbExerciseToApiExercise(dbExercise db.Exercise) types.Exercise {
 	return types.Exercise{
-		ID:          dbExercise.ID,
-		Name:        dbExercise.Name,
-		Description: dbExercise.Description,
-		UserID:      dbExercise.UserID.Int64,
-		CreatedAt:   dbExercise.CreatedAt.Time.String(),
-		UpdatedAt:   dbExercise.UpdatedAt.Time.String(),
+		ID:                    dbExercise.ID,
+		Name:                  dbExercise.Name,
+		Description:           dbExercise.Description,
+		UserID:                dbExercise.UserID.Int64,
+		CreatedAt:             dbExercise.CreatedAt.Time.String(),
+		UpdatedAt:             dbExercise.UpdatedAt.Time.String(),
+		PrimaryMuscleGroups:   utils.If(dbExercise.PrimaryMuscleGroups != nil, dbExercise.PrimaryMuscleGroups, []string{}),
+		SecondaryMuscleGroups: utils.If(dbExercise.SecondaryMuscleGroups != nil, dbExercise.SecondaryMuscleGroups, []string{}),
+		Equipment:             utils.If(dbExercise.Equipment != nil, dbExercise.Equipment, []string{}),
+		Laterality:            dbExercise.Laterality,
+	}
+}
+
+// Helper function to validate and normalize exercise metadata from a request
+func parseExerciseMetadata(primaryMuscleGroups []string, secondaryMuscleGroups []string, equipment []string, laterality string) (repository.ExerciseMetadata, error) {
+	var metadata repository.ExerciseMetadata
+	var err error
+
+	if metadata.PrimaryMuscleGroups, err = utils.NormalizeMuscleGroups(primaryMuscleGroups); err != nil {
+		return metadata, err
 	}
+	if metadata.SecondaryMuscleGroups, err = utils.NormalizeMuscleGroups(secondaryMuscleGroups); err != nil {
+		return metadata, err
+	}
+	if metadata.Equipment, err = utils.NormalizeEquipment(equipment); err != nil {
+		return metadata, err
+	}
+	if metadata.Laterality, err = utils.NormalizeLaterality(laterality); err != nil {
+		return metadata, err
+	}
+	return metadata, nil
 }
 
 // Helper function to convert slice of DB Exercises to API Exercises
@@ -50,6 +88,27 @@ func dbExercisesToApiExercises(dbExercises []db.Exercise) []types.Exercise {
 	return result
 }
 
+// Helper function to load and attach categories to a slice of API Exercises
+func attachExerciseCategories(ctx context.Context, queries *db.Queries, exercises []types.Exercise) error {
+	exerciseIds := make([]int64, len(exercises))
+	for i, exercise := range exercises {
+		exerciseIds[i] = exercise.ID
+	}
+
+	categoryRepo := repository.NewCategoriesRepository(queries)
+	categoriesByExercise, err := categoryRepo.ListByExerciseIds(ctx, exerciseIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range exercises {
+		if dbCategories, ok := categoriesByExercise[exercises[i].ID]; ok {
+			exercises[i].Categories = dbCategoriesToApiCategories(dbCategories)
+		}
+	}
+	return nil
+}
+
 func (h *ExercisesHandler) ListByUserId(w http.ResponseWriter, r *http.Request) {
 	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
 	if err != nil {
@@ -86,16 +145,33 @@ func (h *ExercisesHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	metadata, err := parseExerciseMetadata(args.PrimaryMuscleGroups, args.SecondaryMuscleGroups, args.Equipment, args.Laterality)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
 		exercise_repo := repository.ExercisesRepository{Queries: queries}
 
-		dbExercise, err := exercise_repo.CreateExercise(r.Context(), args.Name, args.Description, args.UserId)
+		dbExercise, err := exercise_repo.CreateExercise(r.Context(), args.Name, args.Description, args.UserId, metadata)
 		if err != nil {
 			return err
 		}
 
+		if len(args.CategoryIds) > 0 {
+			category_repo := repository.NewCategoriesRepository(queries)
+			if err := category_repo.SetExerciseCategories(r.Context(), dbExercise.ID, args.CategoryIds); err != nil {
+				return err
+			}
+		}
+
 		// Convert DB exercise to API exercise
-		apiExercise := dbExerciseToApiExercise(*dbExercise)
+		apiExercises := []types.Exercise{dbExerciseToApiExercise(*dbExercise)}
+		if err := attachExerciseCategories(r.Context(), queries, apiExercises); err != nil {
+			return err
+		}
+		apiExercise := apiExercises[0]
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
@@ -124,13 +200,44 @@ func (h *ExercisesHandler) Update(w http.ResponseWriter, r *http.Request) {
 		// Create repository directly - no service layer needed
 		exercise_repo := repository.ExercisesRepository{Queries: queries}
 
-		dbExercise, err := exercise_repo.UpdateExercise(r.Context(), id, args.Name, args.Description)
+		existing, err := exercise_repo.GetExerciseById(r.Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			api_utils.WriteError(w, http.StatusNotFound, "Exercise not found")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		metadata, err := parseExerciseMetadata(
+			utils.If(args.PrimaryMuscleGroups != nil, args.PrimaryMuscleGroups, existing.PrimaryMuscleGroups),
+			utils.If(args.SecondaryMuscleGroups != nil, args.SecondaryMuscleGroups, existing.SecondaryMuscleGroups),
+			utils.If(args.Equipment != nil, args.Equipment, existing.Equipment),
+			utils.If(args.Laterality != "", args.Laterality, existing.Laterality),
+		)
+		if err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return nil
+		}
+
+		dbExercise, err := exercise_repo.UpdateExercise(r.Context(), id, args.Name, args.Description, metadata)
 		if err != nil {
 			return err
 		}
 
+		if args.CategoryIds != nil {
+			category_repo := repository.NewCategoriesRepository(queries)
+			if err := category_repo.SetExerciseCategories(r.Context(), id, args.CategoryIds); err != nil {
+				return err
+			}
+		}
+
 		// Convert DB exercise to API exercise
-		apiExercise := dbExerciseToApiExercise(*dbExercise)
+		apiExercises := []types.Exercise{dbExerciseToApiExercise(*dbExercise)}
+		if err := attachExerciseCategories(r.Context(), queries, apiExercises); err != nil {
+			return err
+		}
+		apiExercise := apiExercises[0]
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -176,28 +283,49 @@ func (h *ExercisesHandler) List(w http.ResponseWriter, r *http.Request) {
 	planId := filterParser.GetIntFilterOrZero("planId")
 	groupId := filterParser.GetIntFilterOrZero("groupId")
 	intervalId := filterParser.GetIntFilterOrZero("intervalId")
+	categoryId := filterParser.GetIntFilterOrZero("categoryId")
+	muscleGroup := utils.NormalizeMetadataValue(filterParser.GetStringFilter("muscleGroup"))
+	equipment := utils.NormalizeMetadataValue(filterParser.GetStringFilter("equipment"))
+	laterality := utils.NormalizeMetadataValue(filterParser.GetStringFilter("laterality"))
 
 	// Check if at least one filter is provided
-	if exerciseId == 0 && userId == 0 && planId == 0 && groupId == 0 && intervalId == 0 {
+	if exerciseId == 0 && userId == 0 && planId == 0 && groupId == 0 && intervalId == 0 && categoryId == 0 &&
+		muscleGroup == "" && equipment == "" && laterality == "" {
 		api_utils.WriteError(w, http.StatusBadRequest, "Missing at least one filter parameter")
 		return
 	}
 
+	// Filters by record are authorized against its owner; lists by category or metadata
+	// alone span users, so they only show the acting user's and the library's exercises
+	var visibleTo int64
+	if exerciseId == 0 && userId == 0 && planId == 0 && groupId == 0 && intervalId == 0 {
+		actor, ok := api_utils.RequireActingUser(w, r)
+		if !ok {
+			return
+		}
+		visibleTo = actor
+	}
+
 	limit := filterParser.GetLimit(100)
 	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
 		// Create repository directly - no service layer needed
 		exercise_repo := repository.ExercisesRepository{Queries: queries}
 
-		log.Printf("Calling ListExercises with exerciseId=%d, userId=%d, planId=%d, groupId=%d, intervalId=%d, limit=%d",
-			exerciseId, userId, planId, groupId, intervalId, limit)
+		log.Printf("Calling ListExercises with exerciseId=%d, userId=%d, planId=%d, groupId=%d, intervalId=%d, categoryId=%d, muscleGroup=%s, equipment=%s, laterality=%s, limit=%d",
+			exerciseId, userId, planId, groupId, intervalId, categoryId, muscleGroup, equipment, laterality, limit)
 
 		params := repository.ExerciseListParams{
-			ExerciseID: exerciseId,
-			UserID:     userId,
-			PlanID:     planId,
-			GroupID:    groupId,
-			IntervalID: intervalId,
-			Limit:      int32(limit),
+			ExerciseID:  exerciseId,
+			UserID:      userId,
+			PlanID:      planId,
+			GroupID:     groupId,
+			IntervalID:  intervalId,
+			CategoryID:  categoryId,
+			MuscleGroup: muscleGroup,
+			Equipment:   equipment,
+			Laterality:  laterality,
+			VisibleTo:   visibleTo,
+			Limit:       int32(limit),
 		}
 
 		dbExercises, err := exercise_repo.ListExercises(r.Context(), params)
@@ -208,6 +336,9 @@ func (h *ExercisesHandler) List(w http.ResponseWriter, r *http.Request) {
 
 		// Convert DB exercises to API exercises
 		apiExercises := dbExercisesToApiExercises(dbExercises)
+		if err := attachExerciseCategories(r.Context(), queries, apiExercises); err != nil {
+			return err
+		}
 
 		log.Printf("Successfully retrieved exercises")
 
@@ -216,3 +347,63 @@ func (h *ExercisesHandler) List(w http.ResponseWriter, r *http.Request) {
 		return json.NewEncoder(w).Encode(apiExercises)
 	})
 }
+
+func (h *ExercisesHandler) AddCategory(w http.ResponseWriter, r *http.Request) {
+	exerciseId, err := api_utils.ParseBigInt(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	categoryId, err := api_utils.ParseBigInt(chi.URLParam(r, "categoryId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		category_repo := repository.NewCategoriesRepository(queries)
+		if _, err := repository.NewExercisesRepository(queries).GetExerciseById(r.Context(), exerciseId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Exercise not found")
+				return nil
+			}
+			return err
+		}
+		if _, err := category_repo.GetById(r.Context(), categoryId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Category not found")
+				return nil
+			}
+			return err
+		}
+		if err := category_repo.AddToExercise(r.Context(), exerciseId, categoryId); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	})
+}
+
+func (h *ExercisesHandler) RemoveCategory(w http.ResponseWriter, r *http.Request) {
+	exerciseId, err := api_utils.ParseBigInt(chi.URLParam(r, "exerciseId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid exercise ID")
+		return
+	}
+
+	categoryId, err := api_utils.ParseBigInt(chi.URLParam(r, "categoryId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		category_repo := repository.NewCategoriesRepository(queries)
+		return category_repo.RemoveFromExercise(r.Context(), exerciseId, categoryId)
+	})
+
+	if success {
+		w.WriteHeader(http.StatusOK)
+	}
+}
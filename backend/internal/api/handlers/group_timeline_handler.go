@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/timeline"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Helper function to load a group's prescriptions in an interval, in order, with each
+// set resolved from the set scheme or the prescription's uniform values
+func loadTimelinePrescriptions(ctx context.Context, queries *db.Queries, groupId int64, intervalId int64) ([]timeline.Prescription, error) {
+	dbRows, err := repository.NewIntervalExercisePrescriptionsRepository(queries).ListAllWithDetails(ctx, repository.IntervalExercisePrescriptionListParams{
+		GroupId:    groupId,
+		IntervalId: intervalId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prescriptions := []timeline.Prescription{}
+	rows := make(map[int64]db.IntervalExercisePrescriptions_ListWithDetailsRow)
+	ids := []int64{}
+	for _, row := range dbRows {
+		if _, ok := rows[row.ID]; ok {
+			continue
+		}
+		rows[row.ID] = row
+		ids = append(ids, row.ID)
+	}
+
+	dbSets, _, err := repository.NewPrescriptionSetsRepository(queries).ListByPrescriptionIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	schemes := make(map[int64][]db.PrescriptionSet)
+	for _, dbSet := range dbSets {
+		schemes[dbSet.PrescriptionID] = append(schemes[dbSet.PrescriptionID], dbSet)
+	}
+
+	for _, id := range ids {
+		row := rows[id]
+		uniform := timeline.Set{
+			Reps:       row.Reps.Int32,
+			Work:       utils.IntervalToDuration(row.Duration),
+			SubReps:    row.SubReps.Int32,
+			SubRepWork: utils.IntervalToDuration(row.SubRepWorkDuration),
+			SubRepRest: utils.IntervalToDuration(row.SubRepRestDuration),
+			Rest:       utils.IntervalToDuration(row.Rest),
+		}
+
+		prescription := timeline.Prescription{
+			ID:        row.ID,
+			Exercise:  row.EName,
+			Variation: row.EvName,
+			BlockID:   row.BlockID.Int64,
+			Sets:      make([]timeline.Set, row.Sets),
+		}
+		for i := range prescription.Sets {
+			prescription.Sets[i] = uniform
+		}
+		for _, dbSet := range schemes[row.ID] {
+			if int(dbSet.SetNumber) > len(prescription.Sets) {
+				continue
+			}
+			set := &prescription.Sets[dbSet.SetNumber-1]
+			set.Reps = utils.If(dbSet.Reps.Valid, dbSet.Reps.Int32, set.Reps)
+			set.Work = utils.If(dbSet.Duration.Valid, utils.IntervalToDuration(dbSet.Duration), set.Work)
+			set.Rest = utils.If(dbSet.Rest.Valid, utils.IntervalToDuration(dbSet.Rest), set.Rest)
+		}
+		prescriptions = append(prescriptions, prescription)
+	}
+	return prescriptions, nil
+}
+
+// Timeline compiles a group's prescriptions in an interval into the work, rest and
+// transition segments of a workout timer. The time between exercises defaults to
+// 30 seconds and can be changed with transition, e.g. transition=1 minute.
+func (h *GroupsHandler) Timeline(w http.ResponseWriter, r *http.Request) {
+	groupId, err := api_utils.ParseBigInt(chi.URLParam(r, "groupId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid group ID")
+		return
+	}
+
+	filterParser := api_utils.NewFilterParser(r, true)
+	intervalId := filterParser.GetIntFilterOrZero("intervalId")
+	if intervalId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: intervalId")
+		return
+	}
+	transition := timeline.DefaultTransition
+	if value := filterParser.GetStringFilter("transition"); value != "" {
+		interval, err := utils.StringToInterval(value)
+		if err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid transition duration")
+			return
+		}
+		transition = utils.IntervalToDuration(interval)
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := repository.NewGroupsRepository(queries).GetGroupById(r.Context(), groupId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Group not found")
+				return nil
+			}
+			return err
+		}
+
+		prescriptions, err := loadTimelinePrescriptions(r.Context(), queries, groupId, intervalId)
+		if err != nil {
+			return err
+		}
+
+		dbBlocks, err := repository.NewPrescriptionBlocksRepository(queries).List(r.Context(), groupId, intervalId, 0, prescriptionGroupLimit)
+		if err != nil {
+			return err
+		}
+		blocks := make(map[int64]timeline.Block, len(dbBlocks))
+		for _, dbBlock := range dbBlocks {
+			blocks[dbBlock.ID] = timeline.Block{
+				ID:      dbBlock.ID,
+				Rest:    utils.IntervalToDuration(dbBlock.Rest),
+				HasRest: dbBlock.Rest.Valid,
+			}
+		}
+
+		compiled := timeline.Compile(prescriptions, blocks, transition)
+		result := types.WorkoutTimeline{
+			GroupId:         groupId,
+			PlanIntervalId:  intervalId,
+			Segments:        make([]types.TimelineSegment, len(compiled.Segments)),
+			TotalSeconds:    compiled.Total.Seconds(),
+			UntimedSegments: compiled.Untimed,
+		}
+		for i, segment := range compiled.Segments {
+			result.Segments[i] = types.TimelineSegment{
+				Kind:            segment.Kind,
+				PrescriptionId:  segment.PrescriptionID,
+				Exercise:        segment.Exercise,
+				Variation:       segment.Variation,
+				Set:             segment.Set,
+				Rep:             segment.Rep,
+				Round:           segment.Round,
+				Reps:            segment.Reps,
+				StartSeconds:    segment.Start.Seconds(),
+				DurationSeconds: segment.Duration.Seconds(),
+				EndSeconds:      segment.End().Seconds(),
+				Timed:           segment.Timed,
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -8,10 +8,13 @@ import (
 	"backend/internal/types"
 
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 type GroupsHandler struct {
@@ -93,6 +96,9 @@ func (h *GroupsHandler) List(w http.ResponseWriter, r *http.Request) {
 
 		// Convert DB groups to API groups
 		apiGroups := dbGroupsToApiGroups(dbGroups)
+		if err := attachGroupCommentCounts(r.Context(), queries, apiGroups, planId); err != nil {
+			return err
+		}
 
 		log.Printf("Successfully retrieved groups, count: %d", len(apiGroups))
 
@@ -216,29 +222,166 @@ func (h *GroupsHandler) GetById(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *GroupsHandler) AssignToInterval(w http.ResponseWriter, r *http.Request) {
+// IntervalAssignmentApiArgs configures a group within an interval. Omitted fields keep
+// their current value, or the default when the group is newly assigned.
+type IntervalAssignmentApiArgs struct {
+	Frequency  *int32   `json:"frequency"`
+	PinnedDays *[]int32 `json:"pinnedDays"`
+	Order      *int32   `json:"order"`
+}
+
+// Helper function to read the assignment path parameters
+func parseAssignmentPath(w http.ResponseWriter, r *http.Request) (int64, int64, bool) {
 	groupId, err := api_utils.ParseBigInt(chi.URLParam(r, "groupId"))
 	if err != nil {
 		api_utils.WriteError(w, http.StatusBadRequest, "Invalid group ID")
-		return
+		return 0, 0, false
 	}
 
 	planIntervalId, err := api_utils.ParseBigInt(chi.URLParam(r, "intervalId"))
 	if err != nil {
 		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan interval ID")
+		return 0, 0, false
+	}
+	return groupId, planIntervalId, true
+}
+
+// Helper function to decode an optional assignment body
+func decodeIntervalAssignmentArgs(r *http.Request) (IntervalAssignmentApiArgs, error) {
+	var args IntervalAssignmentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil && !errors.Is(err, io.EOF) {
+		return args, err
+	}
+	return args, nil
+}
+
+// Helper function to apply the request to an assignment and check the result
+func applyIntervalAssignmentArgs(assignment *types.IntervalGroupAssignment, args IntervalAssignmentApiArgs) error {
+	assignment.Frequency = service.DerefOrDefault(args.Frequency, assignment.Frequency)
+	assignment.Order = service.DerefOrDefault(args.Order, assignment.Order)
+	if args.PinnedDays != nil {
+		pinnedDays, err := parseWeekdays(*args.PinnedDays, "Pinned days")
+		if err != nil {
+			return err
+		}
+		assignment.PinnedDays = pinnedDays
+	}
+
+	if assignment.Frequency < 1 {
+		return errors.New("Frequency must be at least 1")
+	}
+	if len(assignment.PinnedDays) > 0 && int(assignment.Frequency) > len(assignment.PinnedDays) {
+		return errors.New("Frequency cannot exceed the number of pinned days")
+	}
+	if assignment.Order < 0 {
+		return errors.New("Order cannot be negative")
+	}
+	return nil
+}
+
+// AssignToInterval adds a group to an interval, or reconfigures it if it is already there
+func (h *GroupsHandler) AssignToInterval(w http.ResponseWriter, r *http.Request) {
+	groupId, planIntervalId, ok := parseAssignmentPath(w, r)
+	if !ok {
 		return
 	}
 
-	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
-		interval_group_assignment_repo := repository.IntervalGroupAssignmentRepository{Queries: queries}
-		interval_group_assignment_service := service.NewIntervalGroupAssignmentService(&interval_group_assignment_repo)
+	args, err := decodeIntervalAssignmentArgs(r)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment_repo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+		assignment_service := service.NewIntervalGroupAssignmentsService(&assignment_repo)
+
+		existing, err := assignment_service.GetAssignment(r.Context(), planIntervalId, groupId)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
 
-		err := interval_group_assignment_service.CreateIntervalGroupAssignment(r.Context(), planIntervalId, groupId)
+		status := http.StatusOK
+		var assignment *types.IntervalGroupAssignment
+		if existing != nil {
+			if err := applyIntervalAssignmentArgs(existing, args); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+			if assignment, err = assignment_service.UpdateAssignment(r.Context(), *existing); err != nil {
+				return err
+			}
+		} else {
+			created := types.IntervalGroupAssignment{Frequency: 1, PinnedDays: []int32{}}
+			if err := applyIntervalAssignmentArgs(&created, args); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+			if assignment, err = assignment_service.AssignGroupToInterval(r.Context(), planIntervalId, groupId, created.Frequency, created.PinnedDays, args.Order); err != nil {
+				return err
+			}
+			status = http.StatusCreated
+			log.Printf("Assigned group %d to interval %d", groupId, planIntervalId)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		return json.NewEncoder(w).Encode(assignment)
+	})
+}
+
+// UpdateIntervalAssignment changes the frequency, pinned days or order of a group within an interval
+func (h *GroupsHandler) UpdateIntervalAssignment(w http.ResponseWriter, r *http.Request) {
+	groupId, planIntervalId, ok := parseAssignmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	args, err := decodeIntervalAssignmentArgs(r)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment_repo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+		assignment_service := service.NewIntervalGroupAssignmentsService(&assignment_repo)
+
+		assignment, err := assignment_service.GetAssignment(r.Context(), planIntervalId, groupId)
 		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Group is not assigned to this interval")
+				return nil
+			}
 			return err
 		}
+		if err := applyIntervalAssignmentArgs(assignment, args); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return nil
+		}
 
-		return nil
+		assignment, err = assignment_service.UpdateAssignment(r.Context(), *assignment)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(assignment)
+	})
+}
+
+func (h *GroupsHandler) RemoveFromInterval(w http.ResponseWriter, r *http.Request) {
+	groupId, planIntervalId, ok := parseAssignmentPath(w, r)
+	if !ok {
+		return
+	}
+
+	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment_repo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+		assignment_service := service.NewIntervalGroupAssignmentsService(&assignment_repo)
+
+		return assignment_service.RemoveGroupFromInterval(r.Context(), planIntervalId, groupId)
 	})
 
 	if success {
@@ -246,32 +389,44 @@ func (h *GroupsHandler) AssignToInterval(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func (h *GroupsHandler) RemoveFromInterval(w http.ResponseWriter, r *http.Request) {
+// ListIntervals returns the intervals a group is assigned to, in plan order
+func (h *GroupsHandler) ListIntervals(w http.ResponseWriter, r *http.Request) {
 	groupId, err := api_utils.ParseBigInt(chi.URLParam(r, "groupId"))
 	if err != nil {
 		api_utils.WriteError(w, http.StatusBadRequest, "Invalid group ID")
 		return
 	}
 
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment_repo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+		assignments, err := service.NewIntervalGroupAssignmentsService(&assignment_repo).GetByGroupId(r.Context(), groupId)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(assignments)
+	})
+}
+
+// ListIntervalGroups returns the groups assigned to an interval, in their order
+func (h *GroupsHandler) ListIntervalGroups(w http.ResponseWriter, r *http.Request) {
 	planIntervalId, err := api_utils.ParseBigInt(chi.URLParam(r, "intervalId"))
 	if err != nil {
 		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan interval ID")
 		return
 	}
 
-	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
-		group_interval_assign_repo := repository.IntervalGroupAssignmentRepository{Queries: queries}
-		interval_group_assignment_service := service.NewIntervalGroupAssignmentService(&group_interval_assign_repo)
-
-		err := interval_group_assignment_service.DeleteIntervalGroupAssignment(r.Context(), planIntervalId, groupId)
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment_repo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+		assignments, err := service.NewIntervalGroupAssignmentsService(&assignment_repo).GetByIntervalId(r.Context(), planIntervalId)
 		if err != nil {
 			return err
 		}
 
-		return nil
-	})
-
-	if success {
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-	}
+		return json.NewEncoder(w).Encode(assignments)
+	})
 }
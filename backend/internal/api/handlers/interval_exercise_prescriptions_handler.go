@@ -4,12 +4,19 @@ import (
 	"backend/db"
 	"backend/db/repository"
 	api_utils "backend/internal/api/utils"
+	"backend/internal/service"
 	"backend/internal/types"
 	"backend/internal/utils"
+	"backend/internal/workout"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
 )
 
 type IntervalExercisePrescriptionsHandler struct {
@@ -28,11 +35,37 @@ type CreateIntervalExercisePrescriptionApiArgs struct {
 	SubRepWorkDuration  *string `json:"subRepWorkDuration"`
 	SubRepRestDuration  *string `json:"subRepRestDuration"`
 	Rest                *string `json:"rest"`
+	// SetScheme optionally breaks the prescription down set by set, sets then defaults to its length
+	SetScheme []PrescriptionSetApiArgs `json:"setScheme"`
+}
+
+// UpdateIntervalExercisePrescriptionApiArgs leaves omitted fields unchanged. An empty
+// setScheme makes the prescription uniform again.
+type UpdateIntervalExercisePrescriptionApiArgs struct {
+	ExerciseVariationId *int64                    `json:"exerciseVariationId"`
+	RPE                 *int32                    `json:"rpe"`
+	Sets                *int32                    `json:"sets"`
+	Reps                *int32                    `json:"reps"`
+	Duration            *string                   `json:"duration"`
+	SubReps             *int32                    `json:"subReps"`
+	SubRepWorkDuration  *string                   `json:"subRepWorkDuration"`
+	SubRepRestDuration  *string                   `json:"subRepRestDuration"`
+	Rest                *string                   `json:"rest"`
+	SetScheme           *[]PrescriptionSetApiArgs `json:"setScheme"`
+}
+
+type PrescriptionSetApiArgs struct {
+	Reps       *int32                           `json:"reps"`
+	Duration   *string                          `json:"duration"`
+	RPE        *int32                           `json:"rpe"`
+	Rest       *string                          `json:"rest"`
+	Parameters []types.PrescriptionSetParameter `json:"parameters"`
 }
 
 // Helper function to convert the new detailed prescription rows to API format
 func dbPrescriptionDetailRowsToApiPrescriptions(rows []db.IntervalExercisePrescriptions_ListWithDetailsRow) []types.IntervalExercisePrescription {
 	prescriptionsMap := make(map[int64]*types.IntervalExercisePrescription)
+	var order []int64
 	variationsMap := make(map[int64]*types.ExerciseVariation)
 
 	for _, row := range rows {
@@ -76,7 +109,10 @@ func dbPrescriptionDetailRowsToApiPrescriptions(rows []db.IntervalExercisePrescr
 				SubReps:             utils.If(row.SubReps.Valid, &row.SubReps.Int32, nil),
 				SubRepWorkDuration:  subRepWorkDuration,
 				SubRepRestDuration:  subRepRestDuration,
+				Position:            row.Position,
+				BlockId:             utils.If(row.BlockID.Valid, &row.BlockID.Int64, nil),
 			}
+			order = append(order, row.ID)
 		}
 
 		// Handle variation data (only if not already processed)
@@ -126,25 +162,59 @@ func dbPrescriptionDetailRowsToApiPrescriptions(rows []db.IntervalExercisePrescr
 		}
 	}
 
-	// Convert to slice
+	// Convert to slice, keeping the order prescriptions are performed in
 	prescriptions := make([]types.IntervalExercisePrescription, 0, len(prescriptionsMap))
-	for _, prescription := range prescriptionsMap {
-		prescriptions = append(prescriptions, *prescription)
+	for _, id := range order {
+		prescriptions = append(prescriptions, *prescriptionsMap[id])
 	}
 
 	return prescriptions
 }
 
+// List returns prescriptions with their set schemes. Given a userId, and a date that
+// defaults to today, or a sessionId, percentages of a benchmark are also resolved for
+// that athlete; a session also narrows the list to its group and interval. One of
+// groupId, intervalId or sessionId is required so that only records the acting user was
+// authorized for are listed.
 func (h *IntervalExercisePrescriptionsHandler) List(w http.ResponseWriter, r *http.Request) {
 	filterParser := api_utils.NewFilterParser(r, true)
 
 	groupId := filterParser.GetIntFilterOrZero("groupId")
 	intervalId := filterParser.GetIntFilterOrZero("intervalId")
+	userId := filterParser.GetIntFilterOrZero("userId")
+	sessionId := filterParser.GetIntFilterOrZero("sessionId")
+	date, err := dateOrToday(filterParser.GetStringFilter("date"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if groupId == 0 && intervalId == 0 && sessionId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing at least one filter parameter")
+		return
+	}
 
 	limit := filterParser.GetLimit(100)
 	offset := filterParser.GetIntFilterOrZero("offset")
 
-	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if sessionId != 0 {
+			session, err := repository.NewScheduledSessionsRepository(queries).GetById(r.Context(), sessionId)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					api_utils.WriteError(w, http.StatusNotFound, "Session not found")
+					return nil
+				}
+				return err
+			}
+			assignment, err := repository.NewPlanAssignmentsRepository(queries).GetById(r.Context(), session.PlanAssignmentID)
+			if err != nil {
+				return err
+			}
+			userId, date = assignment.UserID, session.ScheduledDate
+			groupId = utils.If(groupId == 0, session.GroupID, groupId)
+			intervalId = utils.If(intervalId == 0, session.PlanIntervalID, intervalId)
+		}
+
 		// Create repository using the new dedicated query approach
 		prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
 
@@ -163,14 +233,162 @@ func (h *IntervalExercisePrescriptionsHandler) List(w http.ResponseWriter, r *ht
 
 		// Convert to API format using the new helper function
 		apiPrescriptions := dbPrescriptionDetailRowsToApiPrescriptions(dbRows)
+		if err := attachSetSchemes(r.Context(), queries, apiPrescriptions); err != nil {
+			return err
+		}
+		if err := attachPrescriptionCommentCounts(r.Context(), queries, apiPrescriptions); err != nil {
+			return err
+		}
+		if userId != 0 {
+			if err := resolvePercentages(r.Context(), queries, apiPrescriptions, userId, date); err != nil {
+				return err
+			}
+		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
 		return json.NewEncoder(w).Encode(apiPrescriptions)
 	})
+}
 
-	if success {
-		w.WriteHeader(http.StatusOK)
+// attachSetSchemes adds the per-set breakdown to each prescription, empty for uniform ones
+func attachSetSchemes(ctx context.Context, queries *db.Queries, prescriptions []types.IntervalExercisePrescription) error {
+	ids := make([]int64, len(prescriptions))
+	for i, prescription := range prescriptions {
+		ids[i] = prescription.ID
+	}
+	dbSets, dbParameters, err := repository.NewPrescriptionSetsRepository(queries).ListByPrescriptionIds(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	parameters := make(map[int64][]types.PrescriptionSetParameter)
+	for _, dbParameter := range dbParameters {
+		parameters[dbParameter.PrescriptionSetID] = append(parameters[dbParameter.PrescriptionSetID], types.PrescriptionSetParameter{
+			ParameterTypeId: dbParameter.ParameterTypeID,
+			Value:           dbParameter.Value,
+			IsPercentage:    dbParameter.IsPercentage,
+		})
+	}
+
+	schemes := make(map[int64][]types.PrescriptionSet)
+	for _, dbSet := range dbSets {
+		set := types.PrescriptionSet{
+			SetNumber:  dbSet.SetNumber,
+			Reps:       utils.If(dbSet.Reps.Valid, &dbSet.Reps.Int32, nil),
+			RPE:        utils.If(dbSet.Rpe.Valid, &dbSet.Rpe.Int32, nil),
+			Parameters: parameters[dbSet.ID],
+		}
+		if dbSet.Duration.Valid {
+			duration := types.NewPostgreSQLInterval(dbSet.Duration)
+			set.Duration = &duration
+		}
+		if dbSet.Rest.Valid {
+			rest := types.NewPostgreSQLInterval(dbSet.Rest)
+			set.Rest = &rest
+		}
+		if set.Parameters == nil {
+			set.Parameters = []types.PrescriptionSetParameter{}
+		}
+		schemes[dbSet.PrescriptionID] = append(schemes[dbSet.PrescriptionID], set)
+	}
+
+	for i := range prescriptions {
+		prescriptions[i].SetScheme = schemes[prescriptions[i].ID]
+		if prescriptions[i].SetScheme == nil {
+			prescriptions[i].SetScheme = []types.PrescriptionSet{}
+		}
+	}
+	return nil
+}
+
+// checkSetScheme validates each set against the parameters of the prescribed variation
+func checkSetScheme(scheme []PrescriptionSetApiArgs, params []db.ExerciseVariations_ListParamsRow) error {
+	parameterTypes := make(map[int64]db.ExerciseVariations_ListParamsRow, len(params))
+	for _, param := range params {
+		parameterTypes[param.ParameterTypeID] = param
+	}
+
+	for i, set := range scheme {
+		number := i + 1
+		if set.Reps != nil && *set.Reps < 0 {
+			return fmt.Errorf("Set %d cannot have negative reps", number)
+		}
+		if set.RPE != nil && (*set.RPE < 0 || *set.RPE > 10) {
+			return fmt.Errorf("Set %d must have an RPE between 0 and 10", number)
+		}
+		for _, duration := range []*string{set.Duration, set.Rest} {
+			if duration == nil {
+				continue
+			}
+			if _, err := utils.StringToInterval(*duration); err != nil {
+				return fmt.Errorf("Set %d has an invalid duration %q", number, *duration)
+			}
+		}
+
+		seen := make(map[int64]bool, len(set.Parameters))
+		for _, parameter := range set.Parameters {
+			param, ok := parameterTypes[parameter.ParameterTypeId]
+			if !ok {
+				return fmt.Errorf("Set %d uses parameter type %d, which is not a parameter of the exercise variation", number, parameter.ParameterTypeId)
+			}
+			if seen[parameter.ParameterTypeId] {
+				return fmt.Errorf("Set %d gives %s more than once", number, param.Name)
+			}
+			seen[parameter.ParameterTypeId] = true
+			// Percentages of a benchmark are checked once resolved for an athlete
+			if parameter.IsPercentage {
+				if parameter.Value <= 0 {
+					return fmt.Errorf("Set %d gives %s a percentage that is not positive", number, param.Name)
+				}
+				continue
+			}
+			if (param.MinValue.Valid && parameter.Value < param.MinValue.Float64) || (param.MaxValue.Valid && parameter.Value > param.MaxValue.Float64) {
+				return fmt.Errorf("Set %d gives %s a value outside its allowed range", number, param.Name)
+			}
+		}
 	}
+	return nil
+}
+
+func toSetData(scheme []PrescriptionSetApiArgs) []repository.PrescriptionSetData {
+	sets := make([]repository.PrescriptionSetData, len(scheme))
+	for i, set := range scheme {
+		sets[i] = repository.PrescriptionSetData{
+			Reps:     set.Reps,
+			Duration: set.Duration,
+			RPE:      set.RPE,
+			Rest:     set.Rest,
+		}
+		for _, parameter := range set.Parameters {
+			sets[i].Parameters = append(sets[i].Parameters, repository.PrescriptionSetParameterData{
+				ParameterTypeId: parameter.ParameterTypeId,
+				Value:           parameter.Value,
+				IsPercentage:    parameter.IsPercentage,
+			})
+		}
+	}
+	return sets
+}
+
+// getApiPrescription loads a prescription with its variation and set scheme
+func getApiPrescription(ctx context.Context, queries *db.Queries, id int64) (*types.IntervalExercisePrescription, error) {
+	dbRows, err := repository.NewIntervalExercisePrescriptionsRepository(queries).ListWithDetails(ctx, repository.IntervalExercisePrescriptionListParams{
+		PrescriptionId: id,
+		Limit:          prescriptionGroupLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	apiPrescriptions := dbPrescriptionDetailRowsToApiPrescriptions(dbRows)
+	if len(apiPrescriptions) == 0 {
+		return nil, errors.New("prescription not found")
+	}
+	if err := attachSetSchemes(ctx, queries, apiPrescriptions); err != nil {
+		return nil, err
+	}
+	return &apiPrescriptions[0], nil
 }
 
 func (h *IntervalExercisePrescriptionsHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -180,54 +398,273 @@ func (h *IntervalExercisePrescriptionsHandler) Create(w http.ResponseWriter, r *
 		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if len(args.SetScheme) > 0 {
+		if args.Sets == 0 {
+			args.Sets = int32(len(args.SetScheme))
+		} else if args.Sets != int32(len(args.SetScheme)) {
+			api_utils.WriteError(w, http.StatusBadRequest, "Sets must match the number of sets in the set scheme")
+			return
+		}
+	}
 
-	success := api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
-		// Create repository using the dedicated approach
-		prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if len(args.SetScheme) > 0 {
+			params, err := repository.NewExerciseVariationsRepository(queries).ListParams(r.Context(), args.ExerciseVariationId)
+			if err != nil {
+				return err
+			}
+			if err := checkSetScheme(args.SetScheme, params); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
 
-		// Create the prescription
-		dbPrescription, err := prescriptionRepo.CreateOne(r.Context(), repository.PrescriptionCreateData{
-			GroupId:        args.GroupId,
-			VariationId:    args.ExerciseVariationId,
-			PlanIntervalId: args.PlanIntervalId,
-			RPE:            args.RPE,
-			Sets:           args.Sets,
-			Reps:           args.Reps,
-			Duration:       args.Duration,
-			Rest:           args.Rest,
+		dbPrescription, err := repository.NewIntervalExercisePrescriptionsRepository(queries).CreateOne(r.Context(), repository.PrescriptionCreateData{
+			GroupId:            args.GroupId,
+			VariationId:        args.ExerciseVariationId,
+			PlanIntervalId:     args.PlanIntervalId,
+			RPE:                args.RPE,
+			Sets:               args.Sets,
+			Reps:               args.Reps,
+			Duration:           args.Duration,
+			SubReps:            args.SubReps,
+			SubRepWorkDuration: args.SubRepWorkDuration,
+			SubRepRestDuration: args.SubRepRestDuration,
+			Rest:               args.Rest,
 		})
 		if err != nil {
 			return err
 		}
+		if err := repository.NewPrescriptionSetsRepository(queries).Replace(r.Context(), dbPrescription.ID, toSetData(args.SetScheme)); err != nil {
+			return err
+		}
+
+		prescription, err := getApiPrescription(r.Context(), queries, dbPrescription.ID)
+		if err != nil {
+			return err
+		}
 
-		// Get the complete prescription with details using the dedicated query
-		dbRows, err := prescriptionRepo.ListWithDetails(r.Context(), repository.IntervalExercisePrescriptionListParams{
-			PrescriptionId: dbPrescription.ID,
-			ExerciseId:     0,
-			IntervalId:     0,
-			GroupId:        0,
-			Limit:          1,
-			Offset:         0,
-		})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(prescription)
+	})
+}
+
+// Update changes a prescription's variation, uniform values or set scheme
+func (h *IntervalExercisePrescriptionsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid prescription ID")
+		return
+	}
+
+	var args UpdateIntervalExercisePrescriptionApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		log.Printf("Error decoding request body: %v", err)
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+		if _, err := prescriptionRepo.GetById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Prescription not found")
+				return nil
+			}
+			return err
+		}
+		existing, err := getApiPrescription(r.Context(), queries, id)
 		if err != nil {
 			return err
 		}
 
-		if len(dbRows) == 0 {
-			return errors.New("created prescription not found")
+		data := repository.PrescriptionCreateData{
+			VariationId:        service.DerefOrDefault(args.ExerciseVariationId, existing.ExerciseVariationId),
+			RPE:                utils.If(args.RPE != nil, args.RPE, existing.RPE),
+			Sets:               service.DerefOrDefault(args.Sets, existing.Sets),
+			Reps:               utils.If(args.Reps != nil, args.Reps, existing.Reps),
+			SubReps:            utils.If(args.SubReps != nil, args.SubReps, existing.SubReps),
+			Duration:           utils.If(args.Duration != nil, args.Duration, intervalArgument(existing.Duration)),
+			SubRepWorkDuration: utils.If(args.SubRepWorkDuration != nil, args.SubRepWorkDuration, intervalArgument(existing.SubRepWorkDuration)),
+			SubRepRestDuration: utils.If(args.SubRepRestDuration != nil, args.SubRepRestDuration, intervalArgument(existing.SubRepRestDuration)),
+			Rest:               utils.If(args.Rest != nil, args.Rest, intervalArgument(existing.Rest)),
 		}
 
-		// Convert to API format
-		apiPrescriptions := dbPrescriptionDetailRowsToApiPrescriptions(dbRows)
-		if len(apiPrescriptions) == 0 {
-			return errors.New("prescription conversion failed")
+		// The number of sets follows the set scheme
+		schemeLength := len(existing.SetScheme)
+		if args.SetScheme != nil {
+			schemeLength = len(*args.SetScheme)
+			if schemeLength > 0 && args.Sets == nil {
+				data.Sets = int32(schemeLength)
+			}
+		}
+		if schemeLength > 0 && data.Sets != int32(schemeLength) {
+			api_utils.WriteError(w, http.StatusBadRequest, "Sets must match the number of sets in the set scheme")
+			return nil
+		}
+		if data.Sets < 1 {
+			api_utils.WriteError(w, http.StatusBadRequest, "Sets must be at least 1")
+			return nil
+		}
+
+		if args.SetScheme != nil || (args.ExerciseVariationId != nil && schemeLength > 0) {
+			params, err := repository.NewExerciseVariationsRepository(queries).ListParams(r.Context(), data.VariationId)
+			if err != nil {
+				return err
+			}
+			scheme := setSchemeArguments(existing.SetScheme)
+			if args.SetScheme != nil {
+				scheme = *args.SetScheme
+			}
+			if err := checkSetScheme(scheme, params); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
+
+		if _, err := prescriptionRepo.UpdateOne(r.Context(), id, data); err != nil {
+			return err
+		}
+		if args.SetScheme != nil {
+			if err := repository.NewPrescriptionSetsRepository(queries).Replace(r.Context(), id, toSetData(*args.SetScheme)); err != nil {
+				return err
+			}
+		}
+
+		prescription, err := getApiPrescription(r.Context(), queries, id)
+		if err != nil {
+			return err
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		return json.NewEncoder(w).Encode(apiPrescriptions[0])
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(prescription)
 	})
+}
 
-	if success {
-		w.WriteHeader(http.StatusCreated)
+// intervalArgument turns a stored duration back into the form requests use
+func intervalArgument(interval *types.PostgreSQLInterval) *string {
+	if interval == nil {
+		return nil
+	}
+	value, err := utils.IntervalToString(interval.Interval)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+func setSchemeArguments(scheme []types.PrescriptionSet) []PrescriptionSetApiArgs {
+	args := make([]PrescriptionSetApiArgs, len(scheme))
+	for i, set := range scheme {
+		args[i] = PrescriptionSetApiArgs{
+			Reps:       set.Reps,
+			Duration:   intervalArgument(set.Duration),
+			RPE:        set.RPE,
+			Rest:       intervalArgument(set.Rest),
+			Parameters: set.Parameters,
+		}
 	}
+	return args
+}
+
+// prescriptionGroupLimit bounds how many prescriptions a group has in one interval
+const prescriptionGroupLimit = 1000
+
+type ReorderIntervalExercisePrescriptionsApiArgs struct {
+	GroupId         int64   `json:"groupId"`
+	PlanIntervalId  int64   `json:"planIntervalId"`
+	PrescriptionIds []int64 `json:"prescriptionIds"`
+}
+
+// groupPrescriptionEntries returns the prescriptions of a group in an interval in the
+// order they are performed
+func groupPrescriptionEntries(ctx context.Context, queries *db.Queries, groupId int64, intervalId int64) ([]workout.Entry, error) {
+	dbPrescriptions, err := repository.NewIntervalExercisePrescriptionsRepository(queries).List(ctx, repository.IntervalExercisePrescriptionListParams{
+		GroupId:    groupId,
+		IntervalId: intervalId,
+		Limit:      prescriptionGroupLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]workout.Entry, len(dbPrescriptions))
+	for i, dbPrescription := range dbPrescriptions {
+		entries[i] = workout.Entry{ID: dbPrescription.ID, BlockID: dbPrescription.BlockID.Int64}
+	}
+	return entries, nil
+}
+
+func entryIds(entries []workout.Entry) []int64 {
+	ids := make([]int64, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+// listGroupPrescriptions writes the prescriptions of a group in an interval in order
+func listGroupPrescriptions(ctx context.Context, queries *db.Queries, w http.ResponseWriter, groupId int64, intervalId int64) error {
+	dbRows, err := repository.NewIntervalExercisePrescriptionsRepository(queries).ListAllWithDetails(ctx, repository.IntervalExercisePrescriptionListParams{
+		GroupId:    groupId,
+		IntervalId: intervalId,
+	})
+	if err != nil {
+		return err
+	}
+
+	prescriptions := dbPrescriptionDetailRowsToApiPrescriptions(dbRows)
+	if err := attachSetSchemes(ctx, queries, prescriptions); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(prescriptions)
+}
+
+// Reorder sets the order of a group's prescriptions within an interval. Every prescription
+// must be listed once and supersets and circuits must stay together.
+func (h *IntervalExercisePrescriptionsHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	var args ReorderIntervalExercisePrescriptionsApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		log.Printf("Error decoding request body: %v", err)
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.GroupId == 0 || args.PlanIntervalId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Group ID and plan interval ID are required")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		entries, err := groupPrescriptionEntries(r.Context(), queries, args.GroupId, args.PlanIntervalId)
+		if err != nil {
+			return err
+		}
+		if !workout.SameMembers(entryIds(entries), args.PrescriptionIds) {
+			api_utils.WriteError(w, http.StatusBadRequest, "Prescription IDs must list every prescription of the group in this interval once")
+			return nil
+		}
+
+		blocks := make(map[int64]int64, len(entries))
+		for _, entry := range entries {
+			blocks[entry.ID] = entry.BlockID
+		}
+		reordered := make([]workout.Entry, len(args.PrescriptionIds))
+		for i, id := range args.PrescriptionIds {
+			reordered[i] = workout.Entry{ID: id, BlockID: blocks[id]}
+		}
+		if err := workout.CheckBlocks(reordered); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Prescriptions in a superset or circuit must stay next to each other")
+			return nil
+		}
+
+		if err := repository.NewIntervalExercisePrescriptionsRepository(queries).Reorder(r.Context(), args.PrescriptionIds); err != nil {
+			return err
+		}
+		return listGroupPrescriptions(r.Context(), queries, w, args.GroupId, args.PlanIntervalId)
+	})
 }
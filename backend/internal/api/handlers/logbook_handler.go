@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/grades"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// logbookEntryLimit caps how many entries a grade pyramid is counted from
+const logbookEntryLimit = 100000
+
+var logbookStyles = []string{repository.StyleOnsight, repository.StyleFlash, repository.StyleRedpoint, repository.StyleRepeat}
+
+type LogbookHandler struct {
+	Db *db.Database
+}
+
+// LogbookEntryApiArgs logs a climb. Attempts default to 1 and the date to today; a
+// scheduled session attaches the climb to that session.
+type LogbookEntryApiArgs struct {
+	ScheduledSessionId int64  `json:"scheduledSessionId"`
+	Name               string `json:"name"`
+	GradeSystem        string `json:"gradeSystem"`
+	Grade              string `json:"grade"`
+	Style              string `json:"style"`
+	Attempts           int32  `json:"attempts"`
+	Outdoor            bool   `json:"outdoor"`
+	ClimbedOn          string `json:"climbedOn"`
+}
+
+// Helper function to convert a DB logbook entry to an API logbook entry
+func dbLogbookEntryToApiLogbookEntry(entry db.LogbookEntry) types.LogbookEntry {
+	return types.LogbookEntry{
+		ID:                 entry.ID,
+		UserID:             entry.UserID,
+		ScheduledSessionId: utils.If(entry.ScheduledSessionID.Valid, &entry.ScheduledSessionID.Int64, nil),
+		Name:               entry.Name,
+		Discipline:         entry.Discipline,
+		GradeSystem:        entry.GradeSystem,
+		Grade:              entry.Grade,
+		Style:              entry.Style,
+		Attempts:           entry.Attempts,
+		Outdoor:            entry.Outdoor,
+		ClimbedOn:          utils.DateToString(entry.ClimbedOn),
+		CreatedAt:          entry.CreatedAt.Time.String(),
+		UpdatedAt:          entry.UpdatedAt.Time.String(),
+	}
+}
+
+// Helper function to read the grade of a stored entry
+func entryGrade(entry db.LogbookEntry) grades.Grade {
+	return grades.Grade{System: entry.GradeSystem, Name: entry.Grade, Index: int(entry.GradeIndex)}
+}
+
+// Helper function to check a logged climb and put it in the shape it is stored in
+func logbookEntryData(args LogbookEntryApiArgs) (repository.LogbookEntryData, error) {
+	grade, err := grades.Parse(args.GradeSystem, args.Grade)
+	if err != nil {
+		return repository.LogbookEntryData{}, fmt.Errorf("Invalid grade: %w", err)
+	}
+	discipline, _ := grades.Discipline(grade.System)
+	if !slices.Contains(logbookStyles, args.Style) {
+		return repository.LogbookEntryData{}, errors.New("Style must be onsight, flash, redpoint or repeat")
+	}
+	if len(args.Name) > 255 {
+		return repository.LogbookEntryData{}, errors.New("Name must be at most 255 characters")
+	}
+
+	attempts := utils.If(args.Attempts == 0, 1, args.Attempts)
+	if attempts < 1 {
+		return repository.LogbookEntryData{}, errors.New("Attempts must be at least 1")
+	}
+	if attempts > 1 && (args.Style == repository.StyleOnsight || args.Style == repository.StyleFlash) {
+		return repository.LogbookEntryData{}, fmt.Errorf("A %s takes a single attempt", args.Style)
+	}
+	climbedOn, err := dateOrToday(args.ClimbedOn)
+	if err != nil {
+		return repository.LogbookEntryData{}, err
+	}
+
+	return repository.LogbookEntryData{
+		ScheduledSessionID: args.ScheduledSessionId,
+		Name:               args.Name,
+		Discipline:         discipline,
+		GradeSystem:        grade.System,
+		Grade:              grade.Name,
+		GradeIndex:         int32(grade.Index),
+		Style:              args.Style,
+		Attempts:           attempts,
+		Outdoor:            args.Outdoor,
+		ClimbedOn:          climbedOn,
+	}, nil
+}
+
+// Helper function to read the filters of a logbook listing
+func logbookFilter(r *http.Request) (repository.LogbookEntryFilter, error) {
+	fp := api_utils.NewFilterParser(r, true)
+	filter := repository.LogbookEntryFilter{
+		Discipline:         fp.GetStringFilter("discipline"),
+		Style:              fp.GetStringFilter("style"),
+		ScheduledSessionID: fp.GetIntFilterOrZero("sessionId"),
+		Outdoor:            fp.GetBoolFilter("outdoor"),
+	}
+	if filter.Discipline != "" && filter.Discipline != grades.DisciplineBoulder && filter.Discipline != grades.DisciplineRoute {
+		return filter, errors.New("Discipline must be boulder or route")
+	}
+	if filter.Style != "" && !slices.Contains(logbookStyles, filter.Style) {
+		return filter, errors.New("Style must be onsight, flash, redpoint or repeat")
+	}
+
+	var err error
+	if from := fp.GetStringFilter("from"); from != "" {
+		if filter.From, err = utils.StringToDate(from); err != nil {
+			return filter, err
+		}
+	}
+	if to := fp.GetStringFilter("to"); to != "" {
+		if filter.To, err = utils.StringToDate(to); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// Helper function to check that a session a climb is attached to is one of the user's
+func checkLogbookSession(r *http.Request, queries *db.Queries, userId int64, sessionId int64) (bool, error) {
+	if sessionId == 0 {
+		return true, nil
+	}
+	session, err := repository.NewScheduledSessionsRepository(queries).GetById(r.Context(), sessionId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	apiSession, err := getApiSession(r.Context(), queries, session)
+	if err != nil {
+		return false, err
+	}
+	return apiSession.UserID == userId, nil
+}
+
+// List returns the user's logbook, most recent first. With a grade system, each grade is
+// also given in that system where it can be converted.
+func (h *LogbookHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	filter, err := logbookFilter(r)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fp := api_utils.NewFilterParser(r, true)
+	system := fp.GetStringFilter("system")
+	if system != "" && !slices.Contains(grades.Systems, system) {
+		api_utils.WriteError(w, http.StatusBadRequest, "System must be v, font, yds or french")
+		return
+	}
+	limit := fp.GetLimit(100)
+	offset := fp.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		entries, err := repository.NewLogbookEntriesRepository(queries).List(r.Context(), userId, filter, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		result := make([]types.LogbookEntry, len(entries))
+		for i, entry := range entries {
+			result[i] = dbLogbookEntryToApiLogbookEntry(entry)
+			if system == "" {
+				continue
+			}
+			if converted, err := grades.Convert(entryGrade(entry), system); err == nil {
+				result[i].ConvertedGrade = converted.Name
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Pyramid counts the user's sends per grade in a grade system. Sends graded in the other
+// system of the discipline are converted, so indoor Font and outdoor V grades, say, land
+// on one pyramid.
+func (h *LogbookHandler) Pyramid(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	filter, err := logbookFilter(r)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	system := api_utils.NewFilterParser(r, true).GetStringFilter("system")
+	if system == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: system")
+		return
+	}
+	discipline, ok := grades.Discipline(system)
+	if !ok {
+		api_utils.WriteError(w, http.StatusBadRequest, "System must be v, font, yds or french")
+		return
+	}
+	filter.Discipline = discipline
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		entries, err := repository.NewLogbookEntriesRepository(queries).List(r.Context(), userId, filter, 0, logbookEntryLimit)
+		if err != nil {
+			return err
+		}
+
+		sends := make([]grades.Grade, len(entries))
+		for i, entry := range entries {
+			sends[i] = entryGrade(entry)
+		}
+		levels, err := grades.Pyramid(sends, system)
+		if err != nil {
+			return err
+		}
+
+		pyramid := types.GradePyramid{Discipline: discipline, System: system, Total: len(entries), Levels: make([]types.GradePyramidLevel, len(levels))}
+		for i, level := range levels {
+			pyramid.Levels[i] = types.GradePyramidLevel{Grade: level.Grade.Name, Count: level.Count}
+		}
+		if len(levels) > 0 {
+			pyramid.MaxGrade = levels[0].Grade.Name
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(pyramid)
+	})
+}
+
+// Create logs a climb for the user
+func (h *LogbookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var args LogbookEntryApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	data, err := logbookEntryData(args)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		ok, err := checkLogbookSession(r, queries, userId, data.ScheduledSessionID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Session %d is not one of the user's sessions", data.ScheduledSessionID))
+			return nil
+		}
+
+		entry, err := repository.NewLogbookEntriesRepository(queries).Create(r.Context(), userId, data)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Logged %s %s for user %d", entry.Discipline, entry.Grade, userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbLogbookEntryToApiLogbookEntry(*entry))
+	})
+}
+
+// Update replaces a logbook entry
+func (h *LogbookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	entryId, err := api_utils.ParseBigInt(chi.URLParam(r, "entryId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid logbook entry ID")
+		return
+	}
+
+	var args LogbookEntryApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	data, err := logbookEntryData(args)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		ok, err := checkLogbookSession(r, queries, userId, data.ScheduledSessionID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Session %d is not one of the user's sessions", data.ScheduledSessionID))
+			return nil
+		}
+
+		entry, err := repository.NewLogbookEntriesRepository(queries).Update(r.Context(), entryId, userId, data)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Logbook entry not found")
+				return nil
+			}
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbLogbookEntryToApiLogbookEntry(*entry))
+	})
+}
+
+// Delete removes a logbook entry
+func (h *LogbookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	entryId, err := api_utils.ParseBigInt(chi.URLParam(r, "entryId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid logbook entry ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if err := repository.NewLogbookEntriesRepository(queries).Delete(r.Context(), entryId, userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Logbook entry not found")
+				return nil
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Grades lists the grades of a system, or of every system, from easiest to hardest with
+// their equivalents in the other system of the discipline
+func (h *LogbookHandler) Grades(w http.ResponseWriter, r *http.Request) {
+	systems := grades.Systems
+	if system := api_utils.NewFilterParser(r, true).GetStringFilter("system"); system != "" {
+		if !slices.Contains(grades.Systems, system) {
+			api_utils.WriteError(w, http.StatusBadRequest, "System must be v, font, yds or french")
+			return
+		}
+		systems = []string{system}
+	}
+
+	result := []types.ClimbingGrade{}
+	for _, system := range systems {
+		discipline, _ := grades.Discipline(system)
+		list, err := grades.List(system)
+		if err != nil {
+			api_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		for _, grade := range list {
+			apiGrade := types.ClimbingGrade{System: system, Discipline: discipline, Grade: grade.Name, Conversions: map[string]string{}}
+			for _, other := range grades.Systems {
+				if converted, err := grades.Convert(grade, other); err == nil && other != system {
+					apiGrade.Conversions[other] = converted.Name
+				}
+			}
+			result = append(result, apiGrade)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("Error encoding response: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/records"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// personalRecordLimit caps how many current records of a variation are read when
+// detecting new ones
+const personalRecordLimit = 10000
+
+type PersonalRecordsHandler struct {
+	Db *db.Database
+}
+
+// Helper function to convert a DB record to an API record
+func dbRecordToApiRecord(record db.PersonalRecord) types.PersonalRecord {
+	return types.PersonalRecord{
+		ID:                  record.ID,
+		UserID:              record.UserID,
+		ExerciseVariationId: record.ExerciseVariationID,
+		ParameterTypeId:     record.ParameterTypeID,
+		Kind:                record.Kind,
+		Value:               record.Value,
+		PreviousValue:       utils.If(record.PreviousValue.Valid, &record.PreviousValue.Float64, nil),
+		Load:                utils.If(record.Load.Valid, &record.Load.Float64, nil),
+		Reps:                utils.If(record.Reps.Valid, &record.Reps.Int32, nil),
+		SessionSetId:        utils.If(record.SessionSetID.Valid, &record.SessionSetID.Int64, nil),
+		AchievedOn:          utils.DateToString(record.AchievedOn),
+		CreatedAt:           record.CreatedAt.Time.String(),
+	}
+}
+
+// Helper function to convert a DB record to the shape records are detected against
+func dbRecordToRecord(record db.PersonalRecord) records.Record {
+	return records.Record{
+		Kind:            record.Kind,
+		VariationID:     record.ExerciseVariationID,
+		ParameterTypeID: record.ParameterTypeID,
+		Value:           record.Value,
+		Load:            utils.If(record.Load.Valid, &record.Load.Float64, nil),
+	}
+}
+
+// detectRecords stores the records the logged sets set for the user and returns them.
+// setIds are the IDs the sets were logged with.
+func detectRecords(ctx context.Context, queries *db.Queries, userId int64, sets []records.Set, setIds []int64, date pgtype.Date) ([]types.PersonalRecord, error) {
+	recordRepo := repository.NewPersonalRecordsRepository(queries)
+
+	var current []records.Record
+	var variationIds []int64
+	for _, set := range sets {
+		if slices.Contains(variationIds, set.VariationID) {
+			continue
+		}
+		variationIds = append(variationIds, set.VariationID)
+		dbRecords, err := recordRepo.List(ctx, repository.PersonalRecordFilter{UserID: userId, ExerciseVariationID: set.VariationID}, 0, personalRecordLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbRecord := range dbRecords {
+			current = append(current, dbRecordToRecord(dbRecord))
+		}
+	}
+
+	detected := records.Detect(current, sets)
+	result := make([]types.PersonalRecord, len(detected))
+	for i, record := range detected {
+		dbRecord, err := recordRepo.Create(ctx, repository.PersonalRecordData{
+			UserID:              userId,
+			ExerciseVariationID: record.VariationID,
+			ParameterTypeID:     record.ParameterTypeID,
+			Kind:                record.Kind,
+			Value:               record.Value,
+			PreviousValue:       record.Previous,
+			Load:                record.Load,
+			Reps:                record.Reps,
+			SessionSetID:        setIds[record.Set],
+			AchievedOn:          date,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result[i] = dbRecordToApiRecord(*dbRecord)
+	}
+	return result, nil
+}
+
+// List returns current personal records, most recent first. With history, the records
+// they beat are listed too.
+func (h *PersonalRecordsHandler) List(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	filter := repository.PersonalRecordFilter{
+		UserID:              filterParser.GetIntFilterOrZero("userId"),
+		ExerciseVariationID: filterParser.GetIntFilterOrZero("exerciseVariationId"),
+		ParameterTypeID:     filterParser.GetIntFilterOrZero("parameterTypeId"),
+		Kind:                filterParser.GetStringFilter("kind"),
+		History:             filterParser.GetBoolFilterOrFalse("history"),
+	}
+	if filter.Kind != "" && !slices.Contains(records.Kinds, filter.Kind) {
+		api_utils.WriteError(w, http.StatusBadRequest, "Kind must be heaviest_load, longest_duration, most_reps or estimated_max")
+		return
+	}
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbRecords, err := repository.NewPersonalRecordsRepository(queries).List(r.Context(), filter, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		result := make([]types.PersonalRecord, len(dbRecords))
+		for i, dbRecord := range dbRecords {
+			result[i] = dbRecordToApiRecord(dbRecord)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -0,0 +1,372 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/schedule"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+type PlanAssignmentsHandler struct {
+	Db *db.Database
+}
+
+type CreatePlanAssignmentApiArgs struct {
+	PlanID       int64   `json:"planId"`
+	UserID       int64   `json:"userId"`
+	StartDate    string  `json:"startDate"`
+	TrainingDays []int32 `json:"trainingDays"`
+	MinRestDays  int32   `json:"minRestDays"`
+}
+
+// UpdatePlanAssignmentApiArgs leaves omitted fields unchanged
+type UpdatePlanAssignmentApiArgs struct {
+	StartDate    *string  `json:"startDate"`
+	TrainingDays *[]int32 `json:"trainingDays"`
+	MinRestDays  *int32   `json:"minRestDays"`
+}
+
+// Helper function to convert a DB assignment to an API assignment
+func dbPlanAssignmentToApiPlanAssignment(assignment *db.PlanAssignment) types.PlanAssignment {
+	trainingDays := assignment.TrainingDays
+	if trainingDays == nil {
+		trainingDays = []int32{}
+	}
+	return types.PlanAssignment{
+		ID:           assignment.ID,
+		PlanID:       assignment.PlanID,
+		UserID:       assignment.UserID,
+		StartDate:    utils.DateToString(assignment.StartDate),
+		TrainingDays: trainingDays,
+		MinRestDays:  assignment.MinRestDays,
+		CreatedAt:    assignment.CreatedAt.Time.String(),
+		UpdatedAt:    assignment.UpdatedAt.Time.String(),
+	}
+}
+
+// Helper function to check and normalise weekdays into a sorted list without repeats
+func parseWeekdays(days []int32, what string) ([]int32, error) {
+	seen := make(map[int32]bool)
+	normalised := []int32{}
+	for _, day := range days {
+		if day < 0 || day > 6 {
+			return nil, errors.New(what + " must be weekdays from 0 (Sunday) to 6 (Saturday)")
+		}
+		if !seen[day] {
+			seen[day] = true
+			normalised = append(normalised, day)
+		}
+	}
+	sort.Slice(normalised, func(i, j int) bool { return normalised[i] < normalised[j] })
+	return normalised, nil
+}
+
+// Helper function to check the rest between sessions leaves room for a weekly session
+func validateMinRestDays(days int32) error {
+	if days < 0 || days > 6 {
+		return errors.New("Minimum rest days must be between 0 and 6")
+	}
+	return nil
+}
+
+// List returns plan assignments, optionally for one plan or user
+func (h *PlanAssignmentsHandler) List(w http.ResponseWriter, r *http.Request) {
+	fp := api_utils.NewFilterParser(r, true)
+	planId := fp.GetIntFilterOrZero("planId")
+	userId := fp.GetIntFilterOrZero("userId")
+	limit := fp.GetLimit(50)
+	offset := fp.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbAssignments, err := repository.NewPlanAssignmentsRepository(queries).List(r.Context(), planId, userId, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		assignments := make([]types.PlanAssignment, len(dbAssignments))
+		for i := range dbAssignments {
+			assignments[i] = dbPlanAssignmentToApiPlanAssignment(&dbAssignments[i])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(assignments)
+	})
+}
+
+// Get returns a single plan assignment
+func (h *PlanAssignmentsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan assignment ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignment, err := repository.NewPlanAssignmentsRepository(queries).GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan assignment not found")
+				return nil
+			}
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlanAssignmentToApiPlanAssignment(assignment))
+	})
+}
+
+// Create assigns a plan to a user from a start date and generates their sessions
+func (h *PlanAssignmentsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var args CreatePlanAssignmentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.PlanID == 0 || args.UserID == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Plan ID and user ID are required")
+		return
+	}
+	startDate, err := utils.StringToDate(args.StartDate)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	trainingDays, err := parseWeekdays(args.TrainingDays, "Training days")
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateMinRestDays(args.MinRestDays); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignmentRepo := repository.NewPlanAssignmentsRepository(queries)
+
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), args.PlanID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		if _, err := assignmentRepo.GetByPlanAndUser(r.Context(), args.PlanID, args.UserID); err == nil {
+			api_utils.WriteError(w, http.StatusConflict, "Plan is already assigned to this user")
+			return nil
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		assignment, err := assignmentRepo.Create(r.Context(), args.PlanID, args.UserID, startDate, trainingDays, args.MinRestDays)
+		if err != nil {
+			return err
+		}
+		if err := generateAssignmentSessions(r.Context(), queries, assignment); err != nil {
+			return err
+		}
+
+		log.Printf("Assigned plan %d to user %d from %s", assignment.PlanID, assignment.UserID, args.StartDate)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbPlanAssignmentToApiPlanAssignment(assignment))
+	})
+}
+
+// Update changes an assignment's start date or preferences and regenerates its pending sessions
+func (h *PlanAssignmentsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan assignment ID")
+		return
+	}
+
+	var args UpdatePlanAssignmentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignmentRepo := repository.NewPlanAssignmentsRepository(queries)
+
+		assignment, err := assignmentRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan assignment not found")
+				return nil
+			}
+			return err
+		}
+
+		startDate := assignment.StartDate
+		if args.StartDate != nil {
+			if startDate, err = utils.StringToDate(*args.StartDate); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
+		trainingDays := assignment.TrainingDays
+		if args.TrainingDays != nil {
+			if trainingDays, err = parseWeekdays(*args.TrainingDays, "Training days"); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
+		minRestDays := assignment.MinRestDays
+		if args.MinRestDays != nil {
+			if err := validateMinRestDays(*args.MinRestDays); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+			minRestDays = *args.MinRestDays
+		}
+
+		assignment, err = assignmentRepo.Update(r.Context(), id, startDate, trainingDays, minRestDays)
+		if err != nil {
+			return err
+		}
+		if err := generateAssignmentSessions(r.Context(), queries, assignment); err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlanAssignmentToApiPlanAssignment(assignment))
+	})
+}
+
+// Delete removes an assignment together with its sessions
+func (h *PlanAssignmentsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan assignment ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		assignmentRepo := repository.NewPlanAssignmentsRepository(queries)
+
+		if _, err := assignmentRepo.GetById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan assignment not found")
+				return nil
+			}
+			return err
+		}
+		if err := assignmentRepo.Delete(r.Context(), id); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Reflow moves missed sessions forward. When a pending session's date has passed, it and
+// every later pending session are laid out again from the given date (today by default),
+// keeping their order, the athlete's training days and rest. Completed and skipped
+// sessions stay where they are, and so do pinned sessions that were not missed.
+func (h *PlanAssignmentsHandler) Reflow(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan assignment ID")
+		return
+	}
+
+	from := utils.TimeToDate(time.Now())
+	if value := api_utils.NewFilterParser(r, true).GetStringFilter("from"); value != "" {
+		if from, err = utils.StringToDate(value); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sessionRepo := repository.NewScheduledSessionsRepository(queries)
+
+		assignment, err := repository.NewPlanAssignmentsRepository(queries).GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan assignment not found")
+				return nil
+			}
+			return err
+		}
+
+		filter := repository.ScheduledSessionFilter{PlanAssignmentID: assignment.ID}
+		rows, err := sessionRepo.List(r.Context(), filter, 0, scheduleSessionLimit)
+		if err != nil {
+			return err
+		}
+
+		// Groups pinned to weekdays keep to them when the schedule is reflowed
+		intervals, err := loadScheduleIntervals(r.Context(), queries, assignment.PlanID)
+		if err != nil {
+			return err
+		}
+		pinned := make(map[[2]int64]bool)
+		for _, interval := range intervals {
+			for _, group := range interval.Groups {
+				pinned[[2]int64{interval.ID, group.ID}] = len(group.PinnedDays) > 0
+			}
+		}
+
+		var pending []schedule.Session
+		var pendingIds []int64
+		var lastCompleted time.Time
+		missed := 0
+		for _, row := range rows {
+			switch {
+			case row.Status == repository.SessionStatusScheduled:
+				pending = append(pending, schedule.Session{
+					IntervalID: row.PlanIntervalID,
+					GroupID:    row.GroupID,
+					Date:       row.ScheduledDate.Time,
+					Pinned:     pinned[[2]int64{row.PlanIntervalID, row.GroupID}],
+				})
+				pendingIds = append(pendingIds, row.ID)
+				if row.ScheduledDate.Time.Before(from.Time) {
+					missed++
+				}
+			case row.Status == repository.SessionStatusCompleted && row.ScheduledDate.Time.Before(from.Time):
+				lastCompleted = row.ScheduledDate.Time
+			}
+		}
+
+		if missed > 0 {
+			for i, session := range schedule.Reflow(pending, from.Time, lastCompleted, assignmentScheduleOptions(assignment)) {
+				if session.Date.Equal(pending[i].Date) {
+					continue
+				}
+				if _, err := sessionRepo.Reschedule(r.Context(), pendingIds[i], utils.TimeToDate(session.Date)); err != nil {
+					return err
+				}
+			}
+			log.Printf("Reflowed %d sessions of plan assignment %d after %d missed", len(pending), id, missed)
+		}
+
+		sessions, err := listSessions(r.Context(), queries, filter, 0, scheduleSessionLimit)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(sessions)
+	})
+}
@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	"backend/internal/access"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/comments"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxCommentLength matches the length the database allows
+const maxCommentLength = 10000
+
+// PlanCommentsHandler is the discussion of a plan. Collaborators who may comment start
+// threads on the plan or one of its intervals, groups or prescriptions, reply to them,
+// mention each other and resolve the threads once settled.
+type PlanCommentsHandler struct {
+	Db *db.Database
+}
+
+type CreatePlanCommentApiArgs struct {
+	UserId         int64  `json:"userId"`
+	Body           string `json:"body"`
+	IntervalId     *int64 `json:"intervalId"`
+	GroupId        *int64 `json:"groupId"`
+	PrescriptionId *int64 `json:"prescriptionId"`
+	// ParentId replies to a comment; the reply joins the thread the comment is part of
+	ParentId *int64 `json:"parentId"`
+}
+
+type UpdatePlanCommentApiArgs struct {
+	Body string `json:"body"`
+}
+
+type ResolvePlanCommentApiArgs struct {
+	Resolved bool `json:"resolved"`
+}
+
+// Helper function to convert a DB comment with its mentions to an API comment
+func dbPlanCommentToApiPlanComment(row db.PlanComments_GetRow, mentions []types.CommentMention) types.PlanComment {
+	comment := types.PlanComment{
+		ID:             row.ID,
+		PlanID:         row.PlanID,
+		IntervalID:     utils.If(row.PlanIntervalID.Valid, &row.PlanIntervalID.Int64, nil),
+		GroupID:        utils.If(row.GroupID.Valid, &row.GroupID.Int64, nil),
+		PrescriptionID: utils.If(row.PrescriptionID.Valid, &row.PrescriptionID.Int64, nil),
+		ParentID:       utils.If(row.ParentID.Valid, &row.ParentID.Int64, nil),
+		UserID:         row.UserID,
+		AuthorName:     strings.TrimSpace(row.AuthorFirstName + " " + row.AuthorLastName),
+		Body:           row.Body,
+		Mentions:       utils.If(mentions != nil, mentions, []types.CommentMention{}),
+		Resolved:       row.ResolvedAt.Valid,
+		ResolvedBy:     utils.If(row.ResolvedBy.Valid, &row.ResolvedBy.Int64, nil),
+		CreatedAt:      row.CreatedAt.Time.String(),
+		UpdatedAt:      row.UpdatedAt.Time.String(),
+	}
+	if row.ResolvedAt.Valid {
+		resolvedAt := row.ResolvedAt.Time.String()
+		comment.ResolvedAt = &resolvedAt
+	}
+	if row.EditedAt.Valid {
+		editedAt := row.EditedAt.Time.String()
+		comment.EditedAt = &editedAt
+	}
+	return comment
+}
+
+// Helper function to load the mentions of comments by comment
+func loadCommentMentions(ctx context.Context, queries *db.Queries, commentIds []int64) (map[int64][]types.CommentMention, error) {
+	rows, err := repository.NewPlanCommentsRepository(queries).ListMentions(ctx, commentIds)
+	if err != nil {
+		return nil, err
+	}
+	mentions := make(map[int64][]types.CommentMention)
+	for _, row := range rows {
+		mentions[row.CommentID] = append(mentions[row.CommentID], types.CommentMention{
+			UserID: row.UserID,
+			Name:   strings.TrimSpace(row.FirstName + " " + row.LastName),
+		})
+	}
+	return mentions, nil
+}
+
+// Helper function to load a comment as returned by the API, without its replies
+func loadPlanComment(ctx context.Context, queries *db.Queries, id int64) (types.PlanComment, error) {
+	row, err := repository.NewPlanCommentsRepository(queries).Get(ctx, id)
+	if err != nil {
+		return types.PlanComment{}, err
+	}
+	mentions, err := loadCommentMentions(ctx, queries, []int64{id})
+	if err != nil {
+		return types.PlanComment{}, err
+	}
+	return dbPlanCommentToApiPlanComment(*row, mentions[id]), nil
+}
+
+// Helper function to record who a comment mentions among the plan's owner and collaborators
+func setCommentMentions(ctx context.Context, queries *db.Queries, planId int64, commentId int64, body string) error {
+	commentRepo := repository.NewPlanCommentsRepository(queries)
+	rows, err := commentRepo.ListMembers(ctx, planId)
+	if err != nil {
+		return err
+	}
+	members := make([]comments.Member, len(rows))
+	for i, row := range rows {
+		members[i] = comments.Member{ID: row.ID, Email: row.Email}
+	}
+	return commentRepo.SetMentions(ctx, commentId, comments.Mentions(body, members))
+}
+
+// Helper function to check a comment body, returning the message of the error to report
+func validateCommentBody(body string) string {
+	if body == "" {
+		return "Missing required field: body"
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "Comment is too long"
+	}
+	return ""
+}
+
+// Helper function to check that the record a comment is attached to is part of the plan,
+// returning the message of the error to report
+func validateCommentTarget(ctx context.Context, queries *db.Queries, planId int64, target repository.PlanCommentTarget) (string, error) {
+	accessRepo := repository.NewAccessRepository(queries)
+	var (
+		lookup  func(ctx context.Context, id int64) (access.Resource, error)
+		id      int64
+		missing string
+	)
+	switch {
+	case target.IntervalId != nil:
+		lookup, id, missing = accessRepo.Interval, *target.IntervalId, "Interval not found in this plan"
+	case target.GroupId != nil:
+		lookup, id, missing = accessRepo.Group, *target.GroupId, "Group not found in this plan"
+	case target.PrescriptionId != nil:
+		lookup, id, missing = accessRepo.Prescription, *target.PrescriptionId, "Prescription not found in this plan"
+	default:
+		return "", nil
+	}
+
+	resource, err := lookup(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return missing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(resource.PlanIDs, planId) {
+		return missing, nil
+	}
+	return "", nil
+}
+
+// Helper function to load a comment of the plan, writing a 404 when the plan has no such comment
+func planCommentOf(ctx context.Context, w http.ResponseWriter, queries *db.Queries, planId int64, id int64) (*db.PlanComments_GetRow, error) {
+	comment, err := repository.NewPlanCommentsRepository(queries).Get(ctx, id)
+	if err == nil && comment.PlanID != planId {
+		err = pgx.ErrNoRows
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		api_utils.WriteError(w, http.StatusNotFound, "Comment not found")
+	}
+	return comment, err
+}
+
+// List returns the plan's threads, oldest first, with their replies. intervalId, groupId
+// and prescriptionId limit them to the threads on a record, resolved to the resolved or
+// unresolved ones.
+func (h *PlanCommentsHandler) List(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	filterParser := api_utils.NewFilterParser(r, true)
+	params := repository.PlanCommentListParams{
+		PlanId: planId,
+		PlanCommentTarget: repository.PlanCommentTarget{
+			IntervalId:     filterParser.GetIntFilter("intervalId"),
+			GroupId:        filterParser.GetIntFilter("groupId"),
+			PrescriptionId: filterParser.GetIntFilter("prescriptionId"),
+		},
+		Resolved: filterParser.GetBoolFilter("resolved"),
+		Limit:    filterParser.GetLimit(50),
+		Offset:   int32(filterParser.GetOffset(0)),
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		commentRepo := repository.NewPlanCommentsRepository(queries)
+		threads, err := commentRepo.ListThreads(r.Context(), params)
+		if err != nil {
+			return err
+		}
+		threadIds := make([]int64, len(threads))
+		for i, thread := range threads {
+			threadIds[i] = thread.ID
+		}
+		replies, err := commentRepo.ListReplies(r.Context(), threadIds)
+		if err != nil {
+			return err
+		}
+
+		commentIds := append([]int64{}, threadIds...)
+		for _, reply := range replies {
+			commentIds = append(commentIds, reply.ID)
+		}
+		mentions, err := loadCommentMentions(r.Context(), queries, commentIds)
+		if err != nil {
+			return err
+		}
+
+		threadReplies := make(map[int64][]types.PlanComment)
+		for _, reply := range replies {
+			threadReplies[reply.ParentID.Int64] = append(threadReplies[reply.ParentID.Int64],
+				dbPlanCommentToApiPlanComment(db.PlanComments_GetRow(reply), mentions[reply.ID]))
+		}
+		result := make([]types.PlanComment, len(threads))
+		for i, thread := range threads {
+			result[i] = dbPlanCommentToApiPlanComment(db.PlanComments_GetRow(thread), mentions[thread.ID])
+			result[i].Replies = utils.If(threadReplies[thread.ID] != nil, threadReplies[thread.ID], []types.PlanComment{})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Create starts a thread on the plan or one of its records, or replies to a comment
+func (h *PlanCommentsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	var args CreatePlanCommentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.UserId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+	args.Body = strings.TrimSpace(args.Body)
+	if message := validateCommentBody(args.Body); message != "" {
+		api_utils.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+	target := repository.PlanCommentTarget{
+		IntervalId:     args.IntervalId,
+		GroupId:        args.GroupId,
+		PrescriptionId: args.PrescriptionId,
+	}
+	targets := 0
+	for _, id := range []*int64{target.IntervalId, target.GroupId, target.PrescriptionId} {
+		if id != nil {
+			targets++
+		}
+	}
+	if targets > 1 {
+		api_utils.WriteError(w, http.StatusBadRequest, "A comment is attached to at most one of intervalId, groupId and prescriptionId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		var parentId *int64
+		if args.ParentId != nil {
+			parent, err := planCommentOf(r.Context(), w, queries, planId, *args.ParentId)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// Replies share the target of their thread and are kept one level deep
+			threadId := utils.If(parent.ParentID.Valid, parent.ParentID.Int64, parent.ID)
+			parentId = &threadId
+			target = repository.PlanCommentTarget{
+				IntervalId:     utils.If(parent.PlanIntervalID.Valid, &parent.PlanIntervalID.Int64, nil),
+				GroupId:        utils.If(parent.GroupID.Valid, &parent.GroupID.Int64, nil),
+				PrescriptionId: utils.If(parent.PrescriptionID.Valid, &parent.PrescriptionID.Int64, nil),
+			}
+		} else {
+			message, err := validateCommentTarget(r.Context(), queries, planId, target)
+			if err != nil {
+				return err
+			}
+			if message != "" {
+				api_utils.WriteError(w, http.StatusNotFound, message)
+				return nil
+			}
+		}
+
+		dbComment, err := repository.NewPlanCommentsRepository(queries).Create(r.Context(), planId, target, parentId, args.UserId, args.Body)
+		if err != nil {
+			return err
+		}
+		if err := setCommentMentions(r.Context(), queries, planId, dbComment.ID, dbComment.Body); err != nil {
+			return err
+		}
+		comment, err := loadPlanComment(r.Context(), queries, dbComment.ID)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("User %d commented on plan %d as comment %d", args.UserId, planId, comment.ID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(comment)
+	})
+}
+
+// Update edits a comment. Only its author may edit it; its mentions are read again.
+func (h *PlanCommentsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "commentId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+	var args UpdatePlanCommentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	args.Body = strings.TrimSpace(args.Body)
+	if message := validateCommentBody(args.Body); message != "" {
+		api_utils.WriteError(w, http.StatusBadRequest, message)
+		return
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		existing, err := planCommentOf(r.Context(), w, queries, planId, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if actor != existing.UserID {
+			api_utils.WriteError(w, http.StatusForbidden, "Only the author can edit a comment")
+			return nil
+		}
+
+		if _, err := repository.NewPlanCommentsRepository(queries).Update(r.Context(), id, args.Body); err != nil {
+			return err
+		}
+		if err := setCommentMentions(r.Context(), queries, planId, id, args.Body); err != nil {
+			return err
+		}
+		comment, err := loadPlanComment(r.Context(), queries, id)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Edited comment %d on plan %d", id, planId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(comment)
+	})
+}
+
+// Delete removes a comment, and the replies when it starts a thread. Its author and the
+// plan's owner may delete it.
+func (h *PlanCommentsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "commentId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		existing, err := planCommentOf(r.Context(), w, queries, planId, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if actor != existing.UserID {
+			plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId)
+			if err != nil {
+				return err
+			}
+			if actor != plan.UserID {
+				api_utils.WriteError(w, http.StatusForbidden, "Only the author or the plan's owner can delete a comment")
+				return nil
+			}
+		}
+
+		if err := repository.NewPlanCommentsRepository(queries).Delete(r.Context(), id); err != nil {
+			return err
+		}
+
+		log.Printf("Deleted comment %d on plan %d", id, planId)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Resolve marks a thread resolved, or unresolved again, by the acting user
+func (h *PlanCommentsHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "commentId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+	var args ResolvePlanCommentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		existing, err := planCommentOf(r.Context(), w, queries, planId, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if existing.ParentID.Valid {
+			api_utils.WriteError(w, http.StatusBadRequest, "Replies can not be resolved, resolve the thread instead")
+			return nil
+		}
+
+		if _, err := repository.NewPlanCommentsRepository(queries).SetResolved(r.Context(), id, args.Resolved, &actor); err != nil {
+			return err
+		}
+		comment, err := loadPlanComment(r.Context(), queries, id)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Set thread %d on plan %d resolved=%v", id, planId, args.Resolved)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(comment)
+	})
+}
+
+// Helper function to add the comment counts of plans, including the comments on their
+// intervals, groups and prescriptions
+func attachPlanCommentCounts(ctx context.Context, queries *db.Queries, plans []types.Plan) error {
+	ids := make([]int64, len(plans))
+	for i, plan := range plans {
+		ids[i] = plan.ID
+	}
+	counts, err := repository.NewPlanCommentsRepository(queries).CountByPlan(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range plans {
+		plans[i].CommentCounts = apiCommentCounts(counts[plans[i].ID])
+	}
+	return nil
+}
+
+func attachIntervalCommentCounts(ctx context.Context, queries *db.Queries, intervals []types.PlanInterval) error {
+	ids := make([]int64, len(intervals))
+	for i, interval := range intervals {
+		ids[i] = interval.ID
+	}
+	counts, err := repository.NewPlanCommentsRepository(queries).CountByInterval(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range intervals {
+		intervals[i].CommentCounts = apiCommentCounts(counts[intervals[i].ID])
+	}
+	return nil
+}
+
+// Helper function to add the comment counts of groups in a plan, or in every plan they
+// are part of when planId is zero
+func attachGroupCommentCounts(ctx context.Context, queries *db.Queries, groups []types.Group, planId int64) error {
+	ids := make([]int64, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	counts, err := repository.NewPlanCommentsRepository(queries).CountByGroup(ctx, ids, planId)
+	if err != nil {
+		return err
+	}
+	for i := range groups {
+		groups[i].CommentCounts = apiCommentCounts(counts[groups[i].ID])
+	}
+	return nil
+}
+
+func attachPrescriptionCommentCounts(ctx context.Context, queries *db.Queries, prescriptions []types.IntervalExercisePrescription) error {
+	ids := make([]int64, len(prescriptions))
+	for i, prescription := range prescriptions {
+		ids[i] = prescription.ID
+	}
+	counts, err := repository.NewPlanCommentsRepository(queries).CountByPrescription(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range prescriptions {
+		prescriptions[i].CommentCounts = apiCommentCounts(counts[prescriptions[i].ID])
+	}
+	return nil
+}
+
+func apiCommentCounts(counts repository.CommentCounts) types.CommentCounts {
+	return types.CommentCounts{CommentCount: counts.Comments, UnresolvedThreadCount: counts.UnresolvedThreads}
+}
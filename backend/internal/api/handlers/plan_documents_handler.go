@@ -0,0 +1,659 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/library"
+	"backend/internal/plandoc"
+	"backend/internal/plantext"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// planDocumentFetchLimit caps how many intervals and prescriptions an export reads
+const planDocumentFetchLimit = 1000
+
+// Plans are exchanged as JSON documents or in the text format
+const (
+	planFormatJSON = "json"
+	planFormatText = "text"
+)
+
+// Helper function to pick the plan format from the format parameter or the content type
+func planFormat(r *http.Request, filterParser *api_utils.FilterParser) (string, error) {
+	if format := strings.ToLower(filterParser.GetStringFilter("format")); format != "" {
+		if format != planFormatJSON && format != planFormatText {
+			return "", fmt.Errorf("unsupported format: %q", format)
+		}
+		return format, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/plain" {
+		return planFormatText, nil
+	}
+	return planFormatJSON, nil
+}
+
+// Helper function to render an optional database interval in the document vocabulary
+func intervalToDocumentDuration(interval pgtype.Interval) *string {
+	if !interval.Valid {
+		return nil
+	}
+	duration, err := utils.IntervalToString(interval)
+	if err != nil {
+		return nil
+	}
+	return &duration
+}
+
+// Helper function to convert an optional database integer to a document value
+func int4ToDocumentValue(value pgtype.Int4) *int32 {
+	return utils.If(value.Valid, &value.Int32, nil)
+}
+
+// Helper function to build a self-contained document from a stored plan
+func buildPlanDocument(ctx context.Context, queries *db.Queries, dbPlan *db.Plan) (*plandoc.Document, error) {
+	intervalRepo := repository.PlanIntervalsRepository{Queries: queries}
+	assignmentRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+	prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+
+	lib, variationRefs, err := buildExerciseLibrary(ctx, queries, repository.ExerciseListParams{PlanID: dbPlan.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &plandoc.Document{
+		Format:     plandoc.Format,
+		Version:    plandoc.FormatVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Plan: plandoc.Plan{
+			Name:        dbPlan.Name,
+			Description: dbPlan.Description,
+			IsTemplate:  dbPlan.IsTemplate,
+			IsPublic:    dbPlan.IsPublic,
+		},
+		ParameterTypes: lib.ParameterTypes,
+		Exercises:      lib.Exercises,
+		Groups:         []plandoc.Group{},
+		Intervals:      []plandoc.Interval{},
+	}
+
+	dbIntervals, err := intervalRepo.ListPlanIntervals(ctx, dbPlan.ID, 0, planDocumentFetchLimit)
+	if err != nil {
+		return nil, err
+	}
+	parameterNames := make(map[int64]map[int64]string)
+
+	groupKeys := make(map[int64]string)
+	groupKey := func(group *types.Group) string {
+		if key, ok := groupKeys[group.ID]; ok {
+			return key
+		}
+		key := fmt.Sprintf("group-%d", len(groupKeys)+1)
+		groupKeys[group.ID] = key
+		doc.Groups = append(doc.Groups, plandoc.Group{Key: key, Name: group.Name, Description: group.Description})
+		return key
+	}
+
+	for _, dbInterval := range dbIntervals {
+		duration, err := utils.IntervalToString(dbInterval.Duration)
+		if err != nil {
+			return nil, err
+		}
+		interval := plandoc.Interval{
+			Name:        dbInterval.Name.String,
+			Description: dbInterval.Description.String,
+			Duration:    duration,
+			Order:       dbInterval.Order,
+			Deload:      dbInterval.IsDeload,
+			Groups:      []plandoc.IntervalGroup{},
+		}
+
+		assignments, err := assignmentRepo.GetByIntervalId(ctx, dbInterval.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make(map[int64]int)
+		for _, assignment := range assignments {
+			intervalGroup := plandoc.IntervalGroup{
+				Group:         groupKey(assignment.Group),
+				Frequency:     assignment.Frequency,
+				Prescriptions: []plandoc.Prescription{},
+			}
+			for _, day := range assignment.PinnedDays {
+				intervalGroup.PinnedDays = append(intervalGroup.PinnedDays, plandoc.Weekdays[day])
+			}
+			interval.Groups = append(interval.Groups, intervalGroup)
+			entries[assignment.GroupId] = len(interval.Groups) - 1
+		}
+
+		dbPrescriptions, err := prescriptionRepo.List(ctx, repository.IntervalExercisePrescriptionListParams{
+			IntervalId: dbInterval.ID,
+			Limit:      planDocumentFetchLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		dbBlocks, err := repository.NewPrescriptionBlocksRepository(queries).List(ctx, 0, dbInterval.ID, 0, planDocumentFetchLimit)
+		if err != nil {
+			return nil, err
+		}
+		blocks := make(map[int64]*db.PrescriptionBlock, len(dbBlocks))
+		for i := range dbBlocks {
+			blocks[dbBlocks[i].ID] = &dbBlocks[i]
+		}
+		blockEntries := make(map[int64]int)
+
+		setSchemes, err := buildDocumentSetSchemes(ctx, queries, dbPrescriptions, parameterNames)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dbPrescription := range dbPrescriptions {
+			entry, ok := entries[dbPrescription.GroupID]
+			if !ok {
+				// Prescriptions for a group the interval does not schedule are kept by
+				// scheduling the group once
+				dbGroup, err := repository.NewGroupsRepository(queries).GetGroupById(ctx, dbPrescription.GroupID)
+				if err != nil {
+					return nil, err
+				}
+				interval.Groups = append(interval.Groups, plandoc.IntervalGroup{
+					Group:         groupKey(&types.Group{ID: dbGroup.ID, Name: dbGroup.Name, Description: dbGroup.Description}),
+					Frequency:     1,
+					Prescriptions: []plandoc.Prescription{},
+				})
+				entry = len(interval.Groups) - 1
+				entries[dbPrescription.GroupID] = entry
+			}
+
+			ref := variationRefs[dbPrescription.ExerciseVariationID]
+			interval.Groups[entry].Prescriptions = append(interval.Groups[entry].Prescriptions, plandoc.Prescription{
+				Exercise:           ref.Exercise,
+				Variation:          ref.Variation,
+				Sets:               dbPrescription.Sets,
+				Reps:               int4ToDocumentValue(dbPrescription.Reps),
+				RPE:                int4ToDocumentValue(dbPrescription.Rpe),
+				Duration:           intervalToDocumentDuration(dbPrescription.Duration),
+				SubReps:            int4ToDocumentValue(dbPrescription.SubReps),
+				SubRepWorkDuration: intervalToDocumentDuration(dbPrescription.SubRepWorkDuration),
+				SubRepRestDuration: intervalToDocumentDuration(dbPrescription.SubRepRestDuration),
+				Rest:               intervalToDocumentDuration(dbPrescription.Rest),
+				SetScheme:          setSchemes[dbPrescription.ID],
+			})
+
+			block, ok := blocks[dbPrescription.BlockID.Int64]
+			if !dbPrescription.BlockID.Valid || !ok {
+				continue
+			}
+			intervalGroup := &interval.Groups[entry]
+			index, ok := blockEntries[block.ID]
+			if !ok {
+				intervalGroup.Blocks = append(intervalGroup.Blocks, plandoc.Block{
+					Kind:          block.Kind,
+					Rest:          intervalToDocumentDuration(block.Rest),
+					Prescriptions: []int{},
+				})
+				index = len(intervalGroup.Blocks) - 1
+				blockEntries[block.ID] = index
+			}
+			intervalGroup.Blocks[index].Prescriptions = append(intervalGroup.Blocks[index].Prescriptions, len(intervalGroup.Prescriptions)-1)
+		}
+
+		doc.Intervals = append(doc.Intervals, interval)
+	}
+
+	return doc, nil
+}
+
+// Helper function to load the set schemes of an interval's prescriptions, naming parameters
+// by type. parameterNames caches each variation's parameter type names across intervals.
+func buildDocumentSetSchemes(ctx context.Context, queries *db.Queries, dbPrescriptions []db.IntervalExercisePrescription, parameterNames map[int64]map[int64]string) (map[int64][]plandoc.Set, error) {
+	variations := make(map[int64]int64, len(dbPrescriptions))
+	ids := make([]int64, len(dbPrescriptions))
+	for i, dbPrescription := range dbPrescriptions {
+		ids[i] = dbPrescription.ID
+		variations[dbPrescription.ID] = dbPrescription.ExerciseVariationID
+	}
+
+	dbSets, dbParameters, err := repository.NewPrescriptionSetsRepository(queries).ListByPrescriptionIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	setParameters := make(map[int64][]db.PrescriptionSetParameter)
+	for _, dbParameter := range dbParameters {
+		setParameters[dbParameter.PrescriptionSetID] = append(setParameters[dbParameter.PrescriptionSetID], dbParameter)
+	}
+
+	schemes := make(map[int64][]plandoc.Set)
+	for _, dbSet := range dbSets {
+		variationId := variations[dbSet.PrescriptionID]
+		names, ok := parameterNames[variationId]
+		if !ok {
+			params, err := repository.NewExerciseVariationsRepository(queries).ListParams(ctx, variationId)
+			if err != nil {
+				return nil, err
+			}
+			names = make(map[int64]string, len(params))
+			for _, param := range params {
+				names[param.ParameterTypeID] = param.Name
+			}
+			parameterNames[variationId] = names
+		}
+
+		set := plandoc.Set{
+			Reps:     int4ToDocumentValue(dbSet.Reps),
+			Duration: intervalToDocumentDuration(dbSet.Duration),
+			RPE:      int4ToDocumentValue(dbSet.Rpe),
+			Rest:     intervalToDocumentDuration(dbSet.Rest),
+		}
+		for _, dbParameter := range setParameters[dbSet.ID] {
+			set.Parameters = append(set.Parameters, plandoc.SetParameter{
+				ParameterType: names[dbParameter.ParameterTypeID],
+				Value:         dbParameter.Value,
+				Percentage:    dbParameter.IsPercentage,
+			})
+		}
+		schemes[dbSet.PrescriptionID] = append(schemes[dbSet.PrescriptionID], set)
+	}
+	return schemes, nil
+}
+
+// documentLibrary resolves the exercises, variations and parameter types a plan document
+// names to the rows of a user's library
+type documentLibrary struct {
+	existing     library.Existing
+	variationIds map[library.VariationRef]int64
+}
+
+func (l *documentLibrary) variationId(exerciseName string, variationName string) (library.VariationRef, int64, bool) {
+	exerciseId := l.existing.Exercises[strings.ToLower(strings.TrimSpace(exerciseName))]
+	ref := library.VariationRef{Exercise: fmt.Sprint(exerciseId), Variation: strings.ToLower(strings.TrimSpace(variationName))}
+	id, ok := l.variationIds[ref]
+	return ref, id, ok
+}
+
+// Helper function to make the exercises and parameter types of a document available in
+// a user's library. Exercises and parameter types are reused by name; missing ones are
+// created, and missing variations are added to reused exercises.
+func importDocumentLibrary(ctx context.Context, queries *db.Queries, userId int64, doc *plandoc.Document, result *types.PlanImportResult) (*documentLibrary, error) {
+	existing, err := loadExistingLibrary(ctx, queries, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exercises, parameter types and categories the user does not have yet go through
+	// the library import; exercises that already exist come back as conflicts and are reused
+	libraryPlan := library.Plan(&library.Library{
+		Version:        library.FormatVersion,
+		ParameterTypes: doc.ParameterTypes,
+		Exercises:      doc.Exercises,
+	}, existing, library.ConflictSkip)
+	if libraryPlan.Blocked() {
+		for _, issue := range libraryPlan.Issues {
+			if issue.Blocking {
+				return nil, errors.New(issue.Message)
+			}
+		}
+	}
+	if err := applyImportPlan(ctx, queries, userId, libraryPlan, existing); err != nil {
+		return nil, err
+	}
+
+	report := libraryPlan.Report(false)
+	result.ExercisesCreated = report.Exercises
+	result.VariationsCreated = report.VariationsCreated
+	result.ParameterTypesCreated = report.ParameterTypesCreated
+	result.ParameterTypesReused = report.ParameterTypesReused
+
+	reusedExercises := make(map[string]bool)
+	for _, exercise := range doc.Exercises {
+		if _, ok := existing.Exercises[strings.ToLower(strings.TrimSpace(exercise.Name))]; ok {
+			reusedExercises[strings.ToLower(strings.TrimSpace(exercise.Name))] = true
+			result.ExercisesReused = append(result.ExercisesReused, exercise.Name)
+		}
+	}
+
+	// Reload so newly created exercises and parameter types resolve by name
+	existing, err = loadExistingLibrary(ctx, queries, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseIds := make([]int64, 0, len(doc.Exercises))
+	for _, exercise := range doc.Exercises {
+		exerciseIds = append(exerciseIds, existing.Exercises[strings.ToLower(strings.TrimSpace(exercise.Name))])
+	}
+
+	variationRepo := repository.NewExerciseVariationsRepository(queries)
+	variationRows, err := variationRepo.ListByExerciseIds(ctx, exerciseIds)
+	if err != nil {
+		return nil, err
+	}
+	lib := &documentLibrary{existing: existing, variationIds: make(map[library.VariationRef]int64)}
+	for _, row := range variationRows {
+		lib.variationIds[library.VariationRef{Exercise: fmt.Sprint(row.ExerciseID), Variation: strings.ToLower(strings.TrimSpace(row.Name))}] = row.ID
+	}
+
+	// Reused exercises get the variations the document needs but they lack
+	for _, exercise := range doc.Exercises {
+		if !reusedExercises[strings.ToLower(strings.TrimSpace(exercise.Name))] {
+			continue
+		}
+		for _, variation := range exercise.Variations {
+			ref, _, ok := lib.variationId(exercise.Name, variation.Name)
+			if ok {
+				continue
+			}
+			dbVariation, err := variationRepo.CreateExerciseVariation(ctx, existing.Exercises[strings.ToLower(strings.TrimSpace(exercise.Name))], variation.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, parameter := range variation.Parameters {
+				parameterType := existing.ParameterTypes[strings.ToLower(strings.TrimSpace(parameter.ParameterType))]
+				if _, err := variationRepo.AddParam(ctx, dbVariation.ID, parameterType.ID, parameter.Locked); err != nil {
+					return nil, err
+				}
+			}
+			lib.variationIds[ref] = dbVariation.ID
+			result.VariationsCreated++
+		}
+	}
+
+	return lib, nil
+}
+
+// Helper function to recreate a plan document for a user
+func importPlanDocument(ctx context.Context, queries *db.Queries, userId int64, doc *plandoc.Document) (*types.PlanImportResult, error) {
+	result := &types.PlanImportResult{
+		ExercisesCreated:      []string{},
+		ExercisesReused:       []string{},
+		ParameterTypesCreated: []string{},
+		ParameterTypesReused:  []string{},
+	}
+
+	lib, err := importDocumentLibrary(ctx, queries, userId, doc, result)
+	if err != nil {
+		return nil, err
+	}
+
+	planRepo := repository.PlansRepository{Queries: queries}
+	dbPlan, err := planRepo.CreatePlan(ctx, doc.Plan.Name, doc.Plan.Description, userId, doc.Plan.IsTemplate, doc.Plan.IsPublic, pgtype.Date{})
+	if err != nil {
+		return nil, err
+	}
+	result.Plan = dbPlanToApiPlan(*dbPlan)
+
+	groupRepo := repository.NewGroupsRepository(queries)
+	groupIds := make(map[string]int64)
+	for _, group := range doc.Groups {
+		dbGroup, err := groupRepo.Create(ctx, group.Name, group.Description, userId)
+		if err != nil {
+			return nil, err
+		}
+		groupIds[group.Key] = dbGroup.ID
+	}
+
+	intervals := append([]plandoc.Interval{}, doc.Intervals...)
+	sort.SliceStable(intervals, func(i, j int) bool { return intervals[i].Order < intervals[j].Order })
+
+	intervalRepo := repository.PlanIntervalsRepository{Queries: queries}
+	for _, interval := range intervals {
+		dbInterval, err := intervalRepo.CreatePlanInterval(ctx, dbPlan.ID, interval.Duration, interval.Name, interval.Order, interval.Description)
+		if err != nil {
+			return nil, err
+		}
+		if interval.Deload {
+			if dbInterval, err = intervalRepo.SetDeload(ctx, dbInterval.ID, true); err != nil {
+				return nil, err
+			}
+		}
+		if err := createIntervalGroups(ctx, queries, lib, dbInterval.ID, interval.Groups, groupIds); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Helper function to assign the groups of a document interval to an interval with their
+// prescriptions, set schemes and blocks. groupIds maps the document's group keys to groups.
+func createIntervalGroups(ctx context.Context, queries *db.Queries, lib *documentLibrary, intervalId int64, groups []plandoc.IntervalGroup, groupIds map[string]int64) error {
+	assignmentRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+	prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+	blockRepo := repository.NewPrescriptionBlocksRepository(queries)
+	setRepo := repository.NewPrescriptionSetsRepository(queries)
+
+	for order, intervalGroup := range groups {
+		groupId := groupIds[intervalGroup.Group]
+		pinnedDays := []int32{}
+		for _, day := range intervalGroup.PinnedDays {
+			number, _ := plandoc.WeekdayNumber(day)
+			pinnedDays = append(pinnedDays, number)
+		}
+		sort.Slice(pinnedDays, func(i, j int) bool { return pinnedDays[i] < pinnedDays[j] })
+		if _, err := assignmentRepo.CreateOne(ctx, types.IntervalGroupAssignment{
+			PlanIntervalId: intervalId,
+			GroupId:        groupId,
+			Frequency:      intervalGroup.Frequency,
+			PinnedDays:     pinnedDays,
+			Order:          int32(order),
+		}); err != nil {
+			return err
+		}
+
+		prescriptionIds := make([]int64, len(intervalGroup.Prescriptions))
+		for i, prescription := range intervalGroup.Prescriptions {
+			_, id, ok := lib.variationId(prescription.Exercise, prescription.Variation)
+			if !ok {
+				return fmt.Errorf("variation %q of %q could not be resolved", prescription.Variation, prescription.Exercise)
+			}
+			position := int32(i)
+			dbPrescription, err := prescriptionRepo.CreateOne(ctx, repository.PrescriptionCreateData{
+				GroupId:            groupId,
+				VariationId:        id,
+				PlanIntervalId:     intervalId,
+				RPE:                prescription.RPE,
+				Sets:               prescription.Sets,
+				Reps:               prescription.Reps,
+				Duration:           prescription.Duration,
+				SubReps:            prescription.SubReps,
+				SubRepWorkDuration: prescription.SubRepWorkDuration,
+				SubRepRestDuration: prescription.SubRepRestDuration,
+				Rest:               prescription.Rest,
+				Position:           &position,
+			})
+			if err != nil {
+				return err
+			}
+			prescriptionIds[i] = dbPrescription.ID
+
+			if len(prescription.SetScheme) == 0 {
+				continue
+			}
+			sets := make([]repository.PrescriptionSetData, len(prescription.SetScheme))
+			for j, set := range prescription.SetScheme {
+				sets[j] = repository.PrescriptionSetData{Reps: set.Reps, Duration: set.Duration, RPE: set.RPE, Rest: set.Rest}
+				for _, parameter := range set.Parameters {
+					sets[j].Parameters = append(sets[j].Parameters, repository.PrescriptionSetParameterData{
+						ParameterTypeId: lib.existing.ParameterTypes[strings.ToLower(strings.TrimSpace(parameter.ParameterType))].ID,
+						Value:           parameter.Value,
+						IsPercentage:    parameter.Percentage,
+					})
+				}
+			}
+			if err := setRepo.Replace(ctx, dbPrescription.ID, sets); err != nil {
+				return err
+			}
+		}
+
+		for _, block := range intervalGroup.Blocks {
+			rest := pgtype.Interval{}
+			if block.Rest != nil {
+				var err error
+				if rest, err = utils.StringToInterval(*block.Rest); err != nil {
+					return err
+				}
+			}
+			dbBlock, err := blockRepo.Create(ctx, groupId, intervalId, block.Kind, rest)
+			if err != nil {
+				return err
+			}
+			for _, index := range block.Prescriptions {
+				if err := prescriptionRepo.SetBlock(ctx, prescriptionIds[index], dbBlock.ID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// copyPlan copies a plan with its intervals, groups and prescriptions into the user's
+// account as a private plan linked to the source. Exercises the user lacks are added to
+// their library.
+func copyPlan(ctx context.Context, queries *db.Queries, source *db.Plan, userId int64) (*db.Plan, error) {
+	doc, err := buildPlanDocument(ctx, queries, source)
+	if err != nil {
+		return nil, err
+	}
+	doc.Plan.IsTemplate = false
+	doc.Plan.IsPublic = false
+
+	result, err := importPlanDocument(ctx, queries, userId, doc)
+	if err != nil {
+		return nil, err
+	}
+	return (&repository.PlansRepository{Queries: queries}).SetSourcePlan(ctx, result.Plan.ID, source.ID)
+}
+
+// Export downloads a plan as a self-contained, versioned JSON document, or in the text
+// format with format=text
+func (h *PlanHandler) Export(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+
+	format, err := planFormat(r, api_utils.NewFilterParser(r, true))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		planRepo := repository.PlansRepository{Queries: queries}
+
+		dbPlan, err := planRepo.GetPlanById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		doc, err := buildPlanDocument(r.Context(), queries, dbPlan)
+		if err != nil {
+			return err
+		}
+
+		var body bytes.Buffer
+		contentType, extension := "application/json", "json"
+		if format == planFormatText {
+			contentType, extension = "text/plain; charset=utf-8", "txt"
+			err = plantext.Write(&body, doc)
+		} else {
+			err = plandoc.Write(&body, doc)
+		}
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Exported plan %d with %d intervals", id, len(doc.Intervals))
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="plan-%d.%s"`, id, extension))
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(body.Bytes())
+		return err
+	})
+}
+
+// Import recreates a plan document or text plan for a user in one transaction
+func (h *PlanHandler) Import(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	userId := filterParser.GetIntFilterOrZero("userId")
+	if userId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+
+	format, err := planFormat(r, filterParser)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLibrarySize))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var doc *plandoc.Document
+	if format == planFormatText {
+		doc, err = plantext.Parse(bytes.NewReader(body))
+	} else {
+		doc, err = plandoc.Parse(bytes.NewReader(body))
+	}
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan document: "+err.Error())
+		return
+	}
+	if errs := plandoc.Validate(doc); len(errs) > 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan document: "+errors.Join(errs...).Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		result, err := importPlanDocument(r.Context(), queries, userId, doc)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Imported plan %d for user %d", result.Plan.ID, userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Schema serves the published JSON schema for plan documents
+func (h *PlanHandler) Schema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(plandoc.Schema); err != nil {
+		log.Printf("Error writing plan schema: %v", err)
+	}
+}
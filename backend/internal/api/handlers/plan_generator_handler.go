@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/library"
+	"backend/internal/periodization"
+	"backend/internal/plandoc"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type GeneratePlanApiArgs struct {
+	UserId          int64  `json:"userId"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Goal            string `json:"goal"`
+	Model           string `json:"model"`
+	Weeks           int    `json:"weeks"`
+	SessionsPerWeek int    `json:"sessionsPerWeek"`
+	// DeloadEvery makes every nth week a deload, 0 for none
+	DeloadEvery int                           `json:"deloadEvery"`
+	Exercises   []GeneratePlanExerciseApiArgs `json:"exercises"`
+}
+
+// GeneratePlanExerciseApiArgs selects a variation to program. A parameter type of the
+// variation together with a starting load prescribes and progresses the load.
+type GeneratePlanExerciseApiArgs struct {
+	ExerciseVariationId int64   `json:"exerciseVariationId"`
+	ParameterTypeId     int64   `json:"parameterTypeId"`
+	Load                float64 `json:"load"`
+}
+
+// Helper function to keep only the exercises, and their parameter types, a generated
+// plan uses
+func generatedPlanLibrary(lib *library.Library, used map[string]bool) ([]library.Exercise, []library.ParameterType) {
+	exercises := []library.Exercise{}
+	parameterNames := make(map[string]bool)
+	for _, exercise := range lib.Exercises {
+		if !used[exercise.Name] {
+			continue
+		}
+		exercises = append(exercises, exercise)
+		for _, variation := range exercise.Variations {
+			for _, parameter := range variation.Parameters {
+				parameterNames[strings.ToLower(parameter.ParameterType)] = true
+			}
+		}
+	}
+
+	parameterTypes := []library.ParameterType{}
+	for _, parameterType := range lib.ParameterTypes {
+		if parameterNames[strings.ToLower(parameterType.Name)] {
+			parameterTypes = append(parameterTypes, parameterType)
+		}
+	}
+	return exercises, parameterTypes
+}
+
+// Generate creates a periodized plan for a user from a goal, a number of weeks, sessions
+// per week and a model: linear, undulating or block. Every deloadEvery-th week is a
+// deload. The plan is created like an import and is edited like any other plan.
+func (h *PlanHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	var args GeneratePlanApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.UserId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		lib, refs, err := buildExerciseLibrary(r.Context(), queries, repository.ExerciseListParams{UserID: args.UserId})
+		if err != nil {
+			return err
+		}
+
+		config := periodization.Config{
+			Name:            args.Name,
+			Description:     args.Description,
+			Goal:            args.Goal,
+			Model:           args.Model,
+			Weeks:           args.Weeks,
+			SessionsPerWeek: args.SessionsPerWeek,
+			DeloadEvery:     args.DeloadEvery,
+			Exercises:       make([]periodization.Exercise, len(args.Exercises)),
+		}
+		used := make(map[string]bool)
+		for i, exercise := range args.Exercises {
+			ref, ok := refs[exercise.ExerciseVariationId]
+			if !ok {
+				api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Exercise variation %d is not in the user's library", exercise.ExerciseVariationId))
+				return nil
+			}
+			used[ref.Exercise] = true
+			config.Exercises[i] = periodization.Exercise{Exercise: ref.Exercise, Variation: ref.Variation, Load: exercise.Load}
+
+			if exercise.ParameterTypeId == 0 {
+				continue
+			}
+			params, err := repository.NewExerciseVariationsRepository(queries).ListParams(r.Context(), exercise.ExerciseVariationId)
+			if err != nil {
+				return err
+			}
+			for _, param := range params {
+				if param.ParameterTypeID == exercise.ParameterTypeId {
+					config.Exercises[i].LoadParameter = param.Name
+				}
+			}
+			if config.Exercises[i].LoadParameter == "" {
+				api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Parameter type %d is not a parameter of exercise variation %d", exercise.ParameterTypeId, exercise.ExerciseVariationId))
+				return nil
+			}
+		}
+
+		doc, err := periodization.Generate(config)
+		if err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan settings: "+err.Error())
+			return nil
+		}
+		doc.Exercises, doc.ParameterTypes = generatedPlanLibrary(lib, used)
+		if errs := plandoc.Validate(doc); len(errs) > 0 {
+			return errors.Join(errs...)
+		}
+
+		result, err := importPlanDocument(r.Context(), queries, args.UserId, doc)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Generated %s plan %d for user %d", config.Model, result.Plan.ID, args.UserId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/progression"
+	"backend/internal/service"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DeloadPlanIntervalApiArgs struct {
+	Name       *string                 `json:"name"`
+	Duration   *string                 `json:"duration"`
+	Reductions DeloadReductionsApiArgs `json:"reductions"`
+	// LoadParameterTypeIds are the set parameters the load reduction applies to, all of
+	// them when empty
+	LoadParameterTypeIds []int64 `json:"loadParameterTypeIds"`
+}
+
+// DeloadReductionsApiArgs are percentages cut from sets, reps and load, and points cut
+// from RPE. Missing values fall back to the default deload.
+type DeloadReductionsApiArgs struct {
+	Sets *float64 `json:"sets"`
+	Reps *float64 `json:"reps"`
+	Load *float64 `json:"load"`
+	RPE  *float64 `json:"rpe"`
+}
+
+func (args DeloadReductionsApiArgs) reduction() progression.Reduction {
+	return progression.Reduction{
+		Sets: service.DerefOrDefault(args.Sets, progression.DefaultDeload.Sets),
+		Reps: service.DerefOrDefault(args.Reps, progression.DefaultDeload.Reps),
+		Load: service.DerefOrDefault(args.Load, progression.DefaultDeload.Load),
+		RPE:  service.DerefOrDefault(args.RPE, progression.DefaultDeload.RPE),
+	}
+}
+
+// Deload inserts a deload interval right after an interval, shifting the following
+// intervals back. Its prescriptions are the source's, reduced; progression rules are
+// not copied.
+func (h *PlanIntervalHandler) Deload(w http.ResponseWriter, r *http.Request) {
+	intervalId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid interval ID")
+		return
+	}
+
+	var args DeloadPlanIntervalApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	reduction := args.Reductions.reduction()
+	if err := progression.ValidateReduction(reduction); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid reductions: "+err.Error())
+		return
+	}
+	if args.Duration != nil {
+		if _, err := utils.StringToInterval(*args.Duration); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid duration")
+			return
+		}
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		source, err := getIntervalRow(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			api_utils.WriteError(w, http.StatusNotFound, "Plan interval not found")
+			return nil
+		}
+		duration, err := utils.IntervalToString(source.Duration)
+		if err != nil {
+			return err
+		}
+
+		prescriptions, err := loadIntervalPrescriptions(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		isLoad := func(parameterTypeId int64) bool {
+			return len(args.LoadParameterTypeIds) == 0 || slices.Contains(args.LoadParameterTypeIds, parameterTypeId)
+		}
+		deloaded := make([]types.IntervalExercisePrescription, len(prescriptions))
+		for i, prescription := range prescriptions {
+			deloaded[i] = progression.Deload(prescription, reduction, isLoad)
+		}
+
+		plan_interval_repo := repository.PlanIntervalsRepository{Queries: queries}
+		name := service.DerefOrDefault(args.Name, source.Name.String+" (deload)")
+		dbInterval, err := plan_interval_repo.CreatePlanInterval(r.Context(), source.PlanID, service.DerefOrDefault(args.Duration, duration), name, source.Order+1, source.Description.String)
+		if err != nil {
+			return err
+		}
+		groupCount, err := copyIntervalContents(r.Context(), queries, intervalId, dbInterval.ID, deloaded, nil)
+		if err != nil {
+			return err
+		}
+		if dbInterval, err = plan_interval_repo.SetDeload(r.Context(), dbInterval.ID, true); err != nil {
+			return err
+		}
+
+		apiInterval, err := dbPlanIntervalSimpleToApiPlanInterval(*dbInterval, groupCount)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(apiInterval)
+	})
+}
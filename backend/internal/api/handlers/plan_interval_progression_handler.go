@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/progression"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxIntervalCopies bounds how many intervals one copy or preview produces
+const maxIntervalCopies = 52
+
+type CopyPlanIntervalApiArgs struct {
+	Count int32 `json:"count"`
+}
+
+// loadIntervalPrescriptions returns the prescriptions of every group in an interval with
+// their set schemes
+func loadIntervalPrescriptions(ctx context.Context, queries *db.Queries, intervalId int64) ([]types.IntervalExercisePrescription, error) {
+	dbRows, err := repository.NewIntervalExercisePrescriptionsRepository(queries).ListAllWithDetails(ctx, repository.IntervalExercisePrescriptionListParams{
+		IntervalId: intervalId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	prescriptions := dbPrescriptionDetailRowsToApiPrescriptions(dbRows)
+	if err := attachSetSchemes(ctx, queries, prescriptions); err != nil {
+		return nil, err
+	}
+	return prescriptions, nil
+}
+
+// progressPrescriptions moves each prescription forward by its rule, leaving those
+// without a rule unchanged
+func progressPrescriptions(prescriptions []types.IntervalExercisePrescription, rules map[int64]types.ProgressionRule, step int) []types.IntervalExercisePrescription {
+	result := make([]types.IntervalExercisePrescription, len(prescriptions))
+	for i, prescription := range prescriptions {
+		result[i] = prescription
+		if rule, ok := rules[prescription.ID]; ok {
+			result[i] = progression.Prescription(prescription, progressionSteps(rule.Steps), step)
+		}
+	}
+	return result
+}
+
+// getIntervalRow loads an interval, returning nil when it does not exist
+func getIntervalRow(ctx context.Context, queries *db.Queries, intervalId int64) (*db.PlanIntervals_ListRow, error) {
+	plan_interval_repo := repository.PlanIntervalsRepository{Queries: queries}
+	rows, err := plan_interval_repo.ListPlanIntervals(ctx, 0, intervalId, 1)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+// Progression previews the interval's prescriptions in each of the next count intervals
+// (1 by default) as a copy would create them, without changing anything
+func (h *PlanIntervalHandler) Progression(w http.ResponseWriter, r *http.Request) {
+	intervalId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid interval ID")
+		return
+	}
+	filterParser := api_utils.NewFilterParser(r, true)
+	count := filterParser.GetIntFilterOrZero("count")
+	if count == 0 {
+		count = 1
+	}
+	if count < 1 || count > maxIntervalCopies {
+		api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Count must be between 1 and %d", maxIntervalCopies))
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		interval, err := getIntervalRow(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		if interval == nil {
+			api_utils.WriteError(w, http.StatusNotFound, "Plan interval not found")
+			return nil
+		}
+
+		prescriptions, err := loadIntervalPrescriptions(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		rules, err := prescriptionProgressionRules(r.Context(), queries, prescriptions)
+		if err != nil {
+			return err
+		}
+
+		preview := types.ProgressionPreview{
+			PlanIntervalId: intervalId,
+			Intervals:      make([]types.ProgressionPreviewInterval, count),
+		}
+		for i := range preview.Intervals {
+			preview.Intervals[i] = types.ProgressionPreviewInterval{
+				Step:          int32(i + 1),
+				Prescriptions: progressPrescriptions(prescriptions, rules, i+1),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(preview)
+	})
+}
+
+// copyIntervalContents copies the group assignments and blocks of an interval into
+// another, along with the given prescriptions of the source, which may have been
+// progressed or reduced on the way. Rules of the prescriptions move along so the copies
+// progress further.
+func copyIntervalContents(ctx context.Context, queries *db.Queries, sourceId int64, targetId int64, prescriptions []types.IntervalExercisePrescription, rules map[int64]types.ProgressionRule) (int, error) {
+	assignmentsRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+	assignments, err := assignmentsRepo.GetByIntervalId(ctx, sourceId)
+	if err != nil {
+		return 0, err
+	}
+	for _, assignment := range assignments {
+		assignment.PlanIntervalId = targetId
+		if _, err := assignmentsRepo.CreateOne(ctx, assignment); err != nil {
+			return 0, err
+		}
+	}
+
+	blocksRepo := repository.NewPrescriptionBlocksRepository(queries)
+	dbBlocks, err := blocksRepo.List(ctx, 0, sourceId, 0, prescriptionGroupLimit)
+	if err != nil {
+		return 0, err
+	}
+	blocks := make(map[int64]int64, len(dbBlocks))
+	for _, dbBlock := range dbBlocks {
+		block, err := blocksRepo.Create(ctx, dbBlock.GroupID, targetId, dbBlock.Kind, dbBlock.Rest)
+		if err != nil {
+			return 0, err
+		}
+		blocks[dbBlock.ID] = block.ID
+	}
+
+	prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+	setsRepo := repository.NewPrescriptionSetsRepository(queries)
+	rulesRepo := repository.NewProgressionRulesRepository(queries)
+	for _, prescription := range prescriptions {
+		dbPrescription, err := prescriptionRepo.CreateOne(ctx, repository.PrescriptionCreateData{
+			GroupId:            prescription.GroupId,
+			VariationId:        prescription.ExerciseVariationId,
+			PlanIntervalId:     targetId,
+			RPE:                prescription.RPE,
+			Sets:               prescription.Sets,
+			Reps:               prescription.Reps,
+			Duration:           intervalArgument(prescription.Duration),
+			SubReps:            prescription.SubReps,
+			SubRepWorkDuration: intervalArgument(prescription.SubRepWorkDuration),
+			SubRepRestDuration: intervalArgument(prescription.SubRepRestDuration),
+			Rest:               intervalArgument(prescription.Rest),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := setsRepo.Replace(ctx, dbPrescription.ID, toSetData(setSchemeArguments(prescription.SetScheme))); err != nil {
+			return 0, err
+		}
+		if prescription.BlockId != nil {
+			if err := prescriptionRepo.SetBlock(ctx, dbPrescription.ID, blocks[*prescription.BlockId]); err != nil {
+				return 0, err
+			}
+		}
+		if rule, ok := rules[prescription.ID]; ok && rule.PrescriptionId != nil {
+			if _, err := rulesRepo.Create(ctx, dbPrescription.ID, 0, toProgressionStepData(progressionSteps(rule.Steps))); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(assignments), nil
+}
+
+// Copy creates count copies of an interval (1 by default) right after it, each one
+// progressed one step further than the one before by the prescriptions' rules. Copies
+// of a deload are deloads too.
+func (h *PlanIntervalHandler) Copy(w http.ResponseWriter, r *http.Request) {
+	intervalId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid interval ID")
+		return
+	}
+
+	var args CopyPlanIntervalApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.Count == 0 {
+		args.Count = 1
+	}
+	if args.Count < 1 || args.Count > maxIntervalCopies {
+		api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Count must be between 1 and %d", maxIntervalCopies))
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		source, err := getIntervalRow(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			api_utils.WriteError(w, http.StatusNotFound, "Plan interval not found")
+			return nil
+		}
+		duration, err := utils.IntervalToString(source.Duration)
+		if err != nil {
+			return err
+		}
+
+		prescriptions, err := loadIntervalPrescriptions(r.Context(), queries, intervalId)
+		if err != nil {
+			return err
+		}
+		rules, err := prescriptionProgressionRules(r.Context(), queries, prescriptions)
+		if err != nil {
+			return err
+		}
+
+		plan_interval_repo := repository.PlanIntervalsRepository{Queries: queries}
+		copies := make([]types.PlanInterval, args.Count)
+		for i := range copies {
+			step := i + 1
+			name := fmt.Sprintf("%s (%d)", source.Name.String, step+1)
+			dbInterval, err := plan_interval_repo.CreatePlanInterval(r.Context(), source.PlanID, duration, name, source.Order+int32(step), source.Description.String)
+			if err != nil {
+				return err
+			}
+			groupCount, err := copyIntervalContents(r.Context(), queries, intervalId, dbInterval.ID, progressPrescriptions(prescriptions, rules, step), rules)
+			if err != nil {
+				return err
+			}
+			if source.IsDeload {
+				if dbInterval, err = plan_interval_repo.SetDeload(r.Context(), dbInterval.ID, true); err != nil {
+					return err
+				}
+			}
+			if copies[i], err = dbPlanIntervalSimpleToApiPlanInterval(*dbInterval, groupCount); err != nil {
+				return err
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(copies)
+	})
+}
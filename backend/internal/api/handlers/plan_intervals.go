@@ -46,6 +46,7 @@ func dbPlanIntervalToApiPlanInterval(dbInterval db.PlanIntervals_ListRow) (types
 		Name:        dbInterval.Name.String,
 		Description: dbInterval.Description.String,
 		Order:       dbInterval.Order,
+		IsDeload:    dbInterval.IsDeload,
 		CreatedAt:   dbInterval.CreatedAt.Time.String(),
 		UpdatedAt:   dbInterval.UpdatedAt.Time.String(),
 		GroupCount:  int(dbInterval.GroupCount),
@@ -76,6 +77,7 @@ func dbPlanIntervalSimpleToApiPlanInterval(dbInterval db.PlanInterval, groupCoun
 		Name:        dbInterval.Name.String,
 		Description: dbInterval.Description.String,
 		Order:       dbInterval.Order,
+		IsDeload:    dbInterval.IsDeload,
 		CreatedAt:   dbInterval.CreatedAt.Time.String(),
 		UpdatedAt:   dbInterval.UpdatedAt.Time.String(),
 		GroupCount:  groupCount,
@@ -116,6 +118,9 @@ func (h *PlanIntervalHandler) List(w http.ResponseWriter, r *http.Request) {
 			log.Printf("Error converting plan intervals: %v", err)
 			return err
 		}
+		if err := attachIntervalCommentCounts(r.Context(), queries, apiPlanIntervals); err != nil {
+			return err
+		}
 
 		log.Printf("Successfully retrieved plan intervals, count: %d", len(apiPlanIntervals))
 
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	"backend/internal/access"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// PlanSharingHandler shares plans with collaborators who view, comment on or edit them,
+// directly or through links that expire or are revoked. Only the owner of a plan
+// manages who it is shared with; the router authorizes that.
+type PlanSharingHandler struct {
+	Db *db.Database
+}
+
+type PlanCollaboratorApiArgs struct {
+	Permission string `json:"permission"`
+}
+
+// PlanShareLinkApiArgs creates a share link. ExpiresAt is an RFC 3339 time; without it
+// the link lasts until it is revoked.
+type PlanShareLinkApiArgs struct {
+	Permission string  `json:"permission"`
+	ExpiresAt  *string `json:"expiresAt"`
+}
+
+// Helper function to convert DB collaborators to API collaborators
+func dbPlanCollaboratorsToApiPlanCollaborators(rows []db.PlanCollaborators_ListRow) []types.PlanCollaborator {
+	collaborators := make([]types.PlanCollaborator, len(rows))
+	for i, row := range rows {
+		collaborators[i] = types.PlanCollaborator{
+			PlanID:      row.PlanID,
+			UserID:      row.UserID,
+			Permission:  row.Permission,
+			Email:       row.Email,
+			FirstName:   row.FirstName,
+			LastName:    row.LastName,
+			ShareLinkID: utils.If(row.ShareLinkID.Valid, &row.ShareLinkID.Int64, nil),
+			CreatedAt:   row.CreatedAt.Time.String(),
+			UpdatedAt:   row.UpdatedAt.Time.String(),
+		}
+	}
+	return collaborators
+}
+
+// Helper function to convert a DB share link to an API share link
+func dbPlanShareLinkToApiPlanShareLink(link db.PlanShareLink, now time.Time) types.PlanShareLink {
+	var expiresAt, revokedAt *string
+	if link.ExpiresAt.Valid {
+		value := link.ExpiresAt.Time.UTC().Format(time.RFC3339)
+		expiresAt = &value
+	}
+	if link.RevokedAt.Valid {
+		value := link.RevokedAt.Time.UTC().Format(time.RFC3339)
+		revokedAt = &value
+	}
+	return types.PlanShareLink{
+		ID:         link.ID,
+		PlanID:     link.PlanID,
+		Token:      link.Token,
+		Permission: link.Permission,
+		CreatedBy:  utils.If(link.CreatedBy.Valid, &link.CreatedBy.Int64, nil),
+		ExpiresAt:  expiresAt,
+		RevokedAt:  revokedAt,
+		Active:     repository.ShareLinkActive(&link, now),
+		CreatedAt:  link.CreatedAt.Time.String(),
+	}
+}
+
+func validatePlanPermission(permission string) error {
+	if !access.ValidPermission(permission) {
+		return errors.New("Permission must be view, comment or edit")
+	}
+	return nil
+}
+
+// ListCollaborators lists the users the plan is shared with
+func (h *PlanSharingHandler) ListCollaborators(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		rows, err := repository.NewPlanSharingRepository(queries).ListCollaborators(r.Context(), planId)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(dbPlanCollaboratorsToApiPlanCollaborators(rows))
+	})
+}
+
+// SetCollaborator shares the plan with a user, or changes the permission they were given.
+// A user who joined through a link keeps their access after the link ends.
+func (h *PlanSharingHandler) SetCollaborator(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var args PlanCollaboratorApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validatePlanPermission(args.Permission); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		if plan.UserID == userId {
+			api_utils.WriteError(w, http.StatusBadRequest, "The owner of a plan can not be its collaborator")
+			return nil
+		}
+
+		sharingRepo := repository.NewPlanSharingRepository(queries)
+		if _, err := sharingRepo.SetCollaborator(r.Context(), planId, userId, args.Permission); err != nil {
+			return err
+		}
+		rows, err := sharingRepo.ListCollaborators(r.Context(), planId)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Shared plan %d with user %d to %s", planId, userId, args.Permission)
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(dbPlanCollaboratorsToApiPlanCollaborators(rows))
+	})
+}
+
+// RemoveCollaborator stops sharing the plan with a user. Collaborators may remove
+// themselves; only the owner removes others.
+func (h *PlanSharingHandler) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		if actor != userId && actor != plan.UserID {
+			api_utils.WriteError(w, http.StatusForbidden, "Only the owner of a plan can remove its collaborators")
+			return nil
+		}
+
+		if _, err := repository.NewPlanSharingRepository(queries).RemoveCollaborator(r.Context(), planId, userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Collaborator not found")
+				return nil
+			}
+			return err
+		}
+
+		log.Printf("Stopped sharing plan %d with user %d", planId, userId)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// ListShareLinks lists the plan's share links, revoked and expired ones included
+func (h *PlanSharingHandler) ListShareLinks(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		links, err := repository.NewPlanSharingRepository(queries).ListLinks(r.Context(), planId)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		result := make([]types.PlanShareLink, len(links))
+		for i, link := range links {
+			result[i] = dbPlanShareLinkToApiPlanShareLink(link, now)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+func (h *PlanSharingHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	var args PlanShareLinkApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validatePlanPermission(args.Permission); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now()
+	var expiresAt *time.Time
+	if args.ExpiresAt != nil && *args.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, *args.ExpiresAt)
+		if err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid expiresAt, expected an RFC 3339 time")
+			return
+		}
+		if !parsed.After(now) {
+			api_utils.WriteError(w, http.StatusBadRequest, "expiresAt must be in the future")
+			return
+		}
+		// Timestamps are stored without a time zone, in UTC
+		parsed = parsed.UTC()
+		expiresAt = &parsed
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		link, err := repository.NewPlanSharingRepository(queries).CreateLink(r.Context(), planId, args.Permission, &actor, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Created share link %d for plan %d", link.ID, planId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbPlanShareLinkToApiPlanShareLink(*link, now))
+	})
+}
+
+// RevokeShareLink revokes a link, ending the access of the users who joined through it.
+// The link is kept so that the owner sees when it was revoked.
+func (h *PlanSharingHandler) RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	linkId, err := api_utils.ParseBigInt(chi.URLParam(r, "linkId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid share link ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		link, err := repository.NewPlanSharingRepository(queries).RevokeLink(r.Context(), planId, linkId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Share link not found")
+				return nil
+			}
+			return err
+		}
+
+		log.Printf("Revoked share link %d of plan %d", linkId, planId)
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(dbPlanShareLinkToApiPlanShareLink(*link, time.Now()))
+	})
+}
+
+// AcceptShareLink makes the acting user a collaborator of the link's plan. A user keeps
+// a permission they already have when it allows more than the link's.
+func (h *PlanSharingHandler) AcceptShareLink(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	token := chi.URLParam(r, "token")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sharingRepo := repository.NewPlanSharingRepository(queries)
+		link, err := sharingRepo.GetLinkByToken(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Share link not found")
+				return nil
+			}
+			return err
+		}
+		if !repository.ShareLinkActive(link, time.Now()) {
+			api_utils.WriteError(w, http.StatusGone, "Share link has expired or was revoked")
+			return nil
+		}
+		plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), link.PlanID)
+		if err != nil {
+			return err
+		}
+		if plan.UserID == actor {
+			api_utils.WriteError(w, http.StatusConflict, "The owner of a plan can not be its collaborator")
+			return nil
+		}
+
+		permission, err := repository.NewAccessRepository(queries).Permission(r.Context(), actor, []int64{plan.ID})
+		if err != nil {
+			return err
+		}
+		if access.Strongest([]string{permission, link.Permission}) != permission {
+			if _, err := sharingRepo.JoinThroughLink(r.Context(), link, actor); err != nil {
+				return err
+			}
+			permission = link.Permission
+			log.Printf("User %d joined plan %d through share link %d", actor, plan.ID, link.ID)
+		}
+
+		result := dbPlanToApiPlan(*plan)
+		result.Permission = permission
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/plandoc"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// PlanVersionsHandler is the history of plans. Coaches compare versions to see what
+// changed and restore earlier ones to undo mistakes.
+type PlanVersionsHandler struct {
+	Db *db.Database
+}
+
+// PlanVersionDiff is what changed between two versions of a plan. To is left out when
+// the version is compared with the plan as it is now.
+type PlanVersionDiff struct {
+	From    int32            `json:"from"`
+	To      *int32           `json:"to,omitempty"`
+	Changes *plandoc.Changes `json:"changes"`
+}
+
+// Helper function to convert a DB version to an API version
+func dbPlanVersionToApiPlanVersion(version *db.PlanVersion) types.PlanVersion {
+	return types.PlanVersion{
+		ID:           version.ID,
+		PlanID:       version.PlanID,
+		Number:       version.Number,
+		CreatedBy:    utils.If(version.CreatedBy.Valid, &version.CreatedBy.Int64, nil),
+		RestoredFrom: utils.If(version.RestoredFrom.Valid, &version.RestoredFrom.Int32, nil),
+		CreatedAt:    version.CreatedAt.Time.String(),
+		Document:     version.Document,
+	}
+}
+
+// Helper function to snapshot a plan's tree. The export time is left out so that
+// snapshots of the same tree are equal, and intervals keep their IDs so that restoring
+// the snapshot changes the same intervals.
+func planSnapshot(ctx context.Context, queries *db.Queries, plan *db.Plan) (*plandoc.Document, error) {
+	doc, err := buildPlanDocument(ctx, queries, plan)
+	if err != nil {
+		return nil, err
+	}
+	doc.ExportedAt = ""
+
+	dbIntervals, err := (&repository.PlanIntervalsRepository{Queries: queries}).ListPlanIntervals(ctx, plan.ID, 0, planDocumentFetchLimit)
+	if err != nil {
+		return nil, err
+	}
+	// Orders are unique within a plan
+	intervalIds := make(map[int32]int64, len(dbIntervals))
+	for _, dbInterval := range dbIntervals {
+		intervalIds[dbInterval.Order] = dbInterval.ID
+	}
+	for i := range doc.Intervals {
+		doc.Intervals[i].ID = intervalIds[doc.Intervals[i].Order]
+	}
+
+	// Assignments are listed in the order the document lists each interval's groups
+	assignmentRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+	groupIds := make(map[string]int64, len(doc.Groups))
+	for _, interval := range doc.Intervals {
+		assignments, err := assignmentRepo.GetByIntervalId(ctx, interval.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i, assignment := range assignments {
+			if i < len(interval.Groups) {
+				groupIds[interval.Groups[i].Group] = assignment.GroupId
+			}
+		}
+	}
+	for i := range doc.Groups {
+		doc.Groups[i].ID = groupIds[doc.Groups[i].Key]
+	}
+	return doc, nil
+}
+
+// Helper function to record a version of a plan unless it matches the latest one.
+// It returns nil when nothing was recorded.
+func recordPlanVersion(ctx context.Context, queries *db.Queries, planId int64, createdBy *int64, restoredFrom *int32) (*db.PlanVersion, error) {
+	plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(ctx, planId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := planSnapshot(ctx, queries, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	versionRepo := repository.NewPlanVersionsRepository(queries)
+	latest, err := versionRepo.GetLatest(ctx, planId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if latest != nil {
+		var previous plandoc.Document
+		if err := json.Unmarshal(latest.Document, &previous); err != nil {
+			return nil, err
+		}
+		if plandoc.Diff(&previous, doc).Empty() {
+			return nil, nil
+		}
+	}
+
+	document, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return versionRepo.Create(ctx, planId, document, createdBy, restoredFrom)
+}
+
+// RecordPlanVersion records a version of a plan when it changed since its latest version
+func RecordPlanVersion(ctx context.Context, queries *db.Queries, planId int64, createdBy *int64) error {
+	_, err := recordPlanVersion(ctx, queries, planId, createdBy, nil)
+	return err
+}
+
+// Helper function to parse a version number
+func parseVersionNumber(value string) (int32, bool) {
+	number, err := api_utils.ParseBigInt(value)
+	if err != nil || number <= 0 || number > math.MaxInt32 {
+		return 0, false
+	}
+	return int32(number), true
+}
+
+// Helper function to load the document of a version, writing a 404 when it does not exist
+func loadVersionDocument(ctx context.Context, w http.ResponseWriter, queries *db.Queries, planId int64, number int32) (*plandoc.Document, error) {
+	version, err := repository.NewPlanVersionsRepository(queries).Get(ctx, planId, number)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			api_utils.WriteError(w, http.StatusNotFound, fmt.Sprintf("Version %d not found", number))
+		}
+		return nil, err
+	}
+	var doc plandoc.Document
+	if err := json.Unmarshal(version.Document, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Helper function to find the groups a restored document schedules, creating the ones
+// that are gone. Groups are matched by ID and brought back to their recorded name, or by
+// name among the plan's groups for documents recorded without group IDs.
+func restoreGroups(ctx context.Context, queries *db.Queries, plan *db.Plan, dbIntervals []db.PlanIntervals_ListRow, doc *plandoc.Document) (map[string]int64, error) {
+	byName := make(map[string]int64)
+	groupRepo := repository.NewGroupsRepository(queries)
+	assignmentRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+	for _, dbInterval := range dbIntervals {
+		assignments, err := assignmentRepo.GetByIntervalId(ctx, dbInterval.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, assignment := range assignments {
+			byName[strings.ToLower(strings.TrimSpace(assignment.Group.Name))] = assignment.GroupId
+		}
+	}
+
+	groupIds := make(map[string]int64, len(doc.Groups))
+	for _, group := range doc.Groups {
+		if group.ID != 0 {
+			dbGroup, err := groupRepo.GetGroupById(ctx, group.ID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return nil, err
+			}
+			if err == nil && dbGroup.UserID == plan.UserID {
+				if dbGroup.Name != group.Name || dbGroup.Description != group.Description {
+					if _, err := groupRepo.Update(ctx, dbGroup.ID, group.Name, group.Description); err != nil {
+						return nil, err
+					}
+				}
+				groupIds[group.Key] = dbGroup.ID
+				continue
+			}
+		} else if id, ok := byName[strings.ToLower(strings.TrimSpace(group.Name))]; ok {
+			groupIds[group.Key] = id
+			continue
+		}
+		dbGroup, err := groupRepo.Create(ctx, group.Name, group.Description, plan.UserID)
+		if err != nil {
+			return nil, err
+		}
+		byName[strings.ToLower(strings.TrimSpace(group.Name))] = dbGroup.ID
+		groupIds[group.Key] = dbGroup.ID
+	}
+	return groupIds, nil
+}
+
+func isOrderChange(field plandoc.FieldChange) bool {
+	return field.Field == "order"
+}
+
+// Helper function to tell whether an interval changed only its order
+func onlyMoved(changed *plandoc.IntervalChanges) bool {
+	return len(changed.Fields) == 1 && isOrderChange(changed.Fields[0]) &&
+		len(changed.GroupsAdded) == 0 && len(changed.GroupsRemoved) == 0 && len(changed.GroupsChanged) == 0 &&
+		len(changed.PrescriptionsAdded) == 0 && len(changed.PrescriptionsRemoved) == 0 && len(changed.PrescriptionsChanged) == 0
+}
+
+// Helper function to bring a plan's tree back to a document. Intervals the plan shares
+// with the document are changed in place rather than recreated, so the sessions scheduled
+// from them remain. They are matched by ID, or by order for documents recorded without
+// interval IDs.
+func restorePlanDocument(ctx context.Context, queries *db.Queries, plan *db.Plan, doc *plandoc.Document, changes *plandoc.Changes) error {
+	versionRepo := repository.NewPlanVersionsRepository(queries)
+	intervalRepo := repository.PlanIntervalsRepository{Queries: queries}
+
+	if _, err := versionRepo.RestorePlan(ctx, plan.ID, doc.Plan.Name, doc.Plan.Description, doc.Plan.IsTemplate, doc.Plan.IsPublic); err != nil {
+		return err
+	}
+
+	lib, err := importDocumentLibrary(ctx, queries, plan.UserID, doc, &types.PlanImportResult{})
+	if err != nil {
+		return err
+	}
+	dbIntervals, err := intervalRepo.ListPlanIntervals(ctx, plan.ID, 0, planDocumentFetchLimit)
+	if err != nil {
+		return err
+	}
+	groupIds, err := restoreGroups(ctx, queries, plan, dbIntervals, doc)
+	if err != nil {
+		return err
+	}
+
+	// The changes name the plan's intervals by ID and the document's by order
+	intervals := make(map[int32]*plandoc.Interval, len(doc.Intervals))
+	for i := range doc.Intervals {
+		intervals[doc.Intervals[i].Order] = &doc.Intervals[i]
+	}
+
+	for _, removed := range changes.IntervalsRemoved {
+		if _, err := intervalRepo.DeletePlanInterval(ctx, removed.ID); err != nil {
+			return err
+		}
+	}
+	var movedIds []int64
+	var movedOrders []int32
+	for _, changed := range changes.IntervalsChanged {
+		if slices.ContainsFunc(changed.Fields, isOrderChange) {
+			movedIds = append(movedIds, changed.ID)
+			movedOrders = append(movedOrders, changed.Order)
+		}
+	}
+	if err := versionRepo.MoveIntervals(ctx, movedIds, movedOrders); err != nil {
+		return err
+	}
+	for _, changed := range changes.IntervalsChanged {
+		// Intervals that only moved keep their prescriptions and the comments on them
+		if onlyMoved(&changed) {
+			continue
+		}
+		interval := intervals[changed.Order]
+		duration, err := utils.StringToInterval(interval.Duration)
+		if err != nil {
+			return err
+		}
+		if _, err := versionRepo.RestoreInterval(ctx, changed.ID, interval.Name, interval.Description, duration, interval.Deload); err != nil {
+			return err
+		}
+		if err := versionRepo.ClearInterval(ctx, changed.ID); err != nil {
+			return err
+		}
+		if err := createIntervalGroups(ctx, queries, lib, changed.ID, interval.Groups, groupIds); err != nil {
+			return err
+		}
+	}
+	for _, added := range changes.IntervalsAdded {
+		interval := intervals[added.Order]
+		duration, err := utils.StringToInterval(interval.Duration)
+		if err != nil {
+			return err
+		}
+		dbInterval, err := versionRepo.CreateInterval(ctx, plan.ID, interval.Name, interval.Description, duration, interval.Order, interval.Deload)
+		if err != nil {
+			return err
+		}
+		if err := createIntervalGroups(ctx, queries, lib, dbInterval.ID, interval.Groups, groupIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the versions of a plan, newest first
+func (h *PlanVersionsHandler) List(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	filterParser := api_utils.NewFilterParser(r, true)
+	limit := filterParser.GetLimit(20)
+	offset := int32(filterParser.GetOffset(0))
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		rows, err := repository.NewPlanVersionsRepository(queries).List(r.Context(), id, limit, offset)
+		if err != nil {
+			return err
+		}
+		versions := make([]types.PlanVersion, len(rows))
+		for i, row := range rows {
+			versions[i] = types.PlanVersion{
+				ID:            row.ID,
+				PlanID:        row.PlanID,
+				Number:        row.Number,
+				CreatedBy:     utils.If(row.CreatedBy.Valid, &row.CreatedBy.Int64, nil),
+				CreatedByName: strings.TrimSpace(row.CreatedByFirstName.String + " " + row.CreatedByLastName.String),
+				RestoredFrom:  utils.If(row.RestoredFrom.Valid, &row.RestoredFrom.Int32, nil),
+				CreatedAt:     row.CreatedAt.Time.String(),
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(versions)
+	})
+}
+
+// Get returns a version of a plan with its document
+func (h *PlanVersionsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	number, ok := parseVersionNumber(chi.URLParam(r, "number"))
+	if !ok {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid version number")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		version, err := repository.NewPlanVersionsRepository(queries).Get(r.Context(), id, number)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, fmt.Sprintf("Version %d not found", number))
+				return nil
+			}
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlanVersionToApiPlanVersion(version))
+	})
+}
+
+// Diff returns what changed from the version from to the version to, or to the plan as
+// it is now when to is left out
+func (h *PlanVersionsHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	query := r.URL.Query()
+	if query.Get("from") == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required parameter: from")
+		return
+	}
+	from, ok := parseVersionNumber(query.Get("from"))
+	if !ok {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid version number: from")
+		return
+	}
+	var to *int32
+	if query.Get("to") != "" {
+		number, ok := parseVersionNumber(query.Get("to"))
+		if !ok {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid version number: to")
+			return
+		}
+		to = &number
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		before, err := loadVersionDocument(r.Context(), w, queries, id, from)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var after *plandoc.Document
+		if to != nil {
+			after, err = loadVersionDocument(r.Context(), w, queries, id, *to)
+		} else {
+			after, err = planSnapshot(r.Context(), queries, plan)
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(PlanVersionDiff{From: from, To: to, Changes: plandoc.Diff(before, after)})
+	})
+}
+
+// Restore brings a plan back to an earlier version in one transaction and records the
+// result as a new version, so a restore can itself be undone
+func (h *PlanVersionsHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	number, ok := parseVersionNumber(chi.URLParam(r, "number"))
+	if !ok {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid version number")
+		return
+	}
+	var createdBy *int64
+	if actor, ok := api_utils.ActingUser(r); ok {
+		createdBy = &actor
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		plan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		target, err := loadVersionDocument(r.Context(), w, queries, id, number)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		current, err := planSnapshot(r.Context(), queries, plan)
+		if err != nil {
+			return err
+		}
+		changes := plandoc.Diff(current, target)
+		if changes.Empty() {
+			api_utils.WriteError(w, http.StatusConflict, fmt.Sprintf("The plan already matches version %d", number))
+			return nil
+		}
+
+		// Changes made outside of recorded requests are kept as a version of their own
+		if _, err := recordPlanVersion(r.Context(), queries, id, nil, nil); err != nil {
+			return err
+		}
+		if err := restorePlanDocument(r.Context(), queries, plan, target, changes); err != nil {
+			return err
+		}
+		version, err := recordPlanVersion(r.Context(), queries, id, createdBy, &number)
+		if err != nil {
+			return err
+		}
+		if version == nil {
+			return fmt.Errorf("plan %d did not change when restoring version %d", id, number)
+		}
+
+		log.Printf("Restored plan %d to version %d as version %d", id, number, version.Number)
+		version.Document = nil
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlanVersionToApiPlanVersion(version))
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"backend/db"
 	"backend/db/repository"
 	api_utils "backend/internal/api/utils"
+	"backend/internal/service"
 	"backend/internal/types"
+	"backend/internal/utils"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type PlanHandler struct {
@@ -32,6 +35,8 @@ type ListPlanApiArgs struct {
 	Cursor     *string `json:"cursor,omitempty"`
 	IsTemplate *bool   `json:"isTemplate,omitempty"`
 	IsPublic   *bool   `json:"isPublic,omitempty"`
+	// Shared lists only the plans shared with the user, or only their own plans when false
+	Shared *bool `json:"shared,omitempty"`
 }
 
 type CreatePlanApiArgs struct {
@@ -40,19 +45,32 @@ type CreatePlanApiArgs struct {
 	UserId      int64  `json:"userId"`
 	IsTemplate  bool   `json:"isTemplate"`
 	IsPublic    bool   `json:"isPublic"`
+	// StartDate is a YYYY-MM-DD date. On edit, leaving it out keeps the current date and
+	// an empty string clears it.
+	StartDate *string `json:"startDate,omitempty"`
+}
+
+// Helper function to parse an optional plan start date
+func parsePlanStartDate(startDate *string) (pgtype.Date, error) {
+	if startDate == nil || *startDate == "" {
+		return pgtype.Date{}, nil
+	}
+	return utils.StringToDate(*startDate)
 }
 
 // Helper function to convert DB Plan to API Plan
 func dbPlanToApiPlan(dbPlan db.Plan) types.Plan {
 	return types.Plan{
-		ID:          dbPlan.ID,
-		Name:        dbPlan.Name,
-		Description: dbPlan.Description,
-		UserID:      dbPlan.UserID,
-		CreatedAt:   dbPlan.CreatedAt.Time.String(),
-		UpdatedAt:   dbPlan.UpdatedAt.Time.String(),
-		IsTemplate:  dbPlan.IsTemplate,
-		IsPublic:    dbPlan.IsPublic,
+		ID:           dbPlan.ID,
+		Name:         dbPlan.Name,
+		Description:  dbPlan.Description,
+		UserID:       dbPlan.UserID,
+		CreatedAt:    dbPlan.CreatedAt.Time.String(),
+		UpdatedAt:    dbPlan.UpdatedAt.Time.String(),
+		IsTemplate:   dbPlan.IsTemplate,
+		IsPublic:     dbPlan.IsPublic,
+		StartDate:    utils.If(dbPlan.StartDate.Valid, utils.DateToString(dbPlan.StartDate), ""),
+		SourcePlanID: utils.If(dbPlan.SourcePlanID.Valid, &dbPlan.SourcePlanID.Int64, nil),
 	}
 }
 
@@ -65,6 +83,28 @@ func dbPlansToApiPlans(dbPlans []db.Plan) []types.Plan {
 	return result
 }
 
+// Helper function to convert the plans a user may access to API Plans, with the
+// permission they were given for the ones shared with them
+func dbAccessiblePlansToApiPlans(rows []db.Plans_ListAccessibleRow) []types.Plan {
+	result := make([]types.Plan, len(rows))
+	for i, row := range rows {
+		result[i] = dbPlanToApiPlan(db.Plan{
+			ID:           row.ID,
+			Name:         row.Name,
+			Description:  row.Description,
+			UserID:       row.UserID,
+			IsTemplate:   row.IsTemplate,
+			IsPublic:     row.IsPublic,
+			StartDate:    row.StartDate,
+			SourcePlanID: row.SourcePlanID,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+		})
+		result[i].Permission = row.SharedPermission.String
+	}
+	return result
+}
+
 func (h *PlanHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Log the incoming request for debugging
 	log.Printf("Handling plans List request: %s %s", r.Method, r.URL.String())
@@ -89,6 +129,7 @@ func (h *PlanHandler) List(w http.ResponseWriter, r *http.Request) {
 		offset := filterParser.GetOffset(0)
 		isTemplate := filterParser.GetBoolFilter("isTemplate")
 		isPublic := filterParser.GetBoolFilter("isPublic")
+		shared := filterParser.GetBoolFilter("shared")
 
 		if isTemplate != nil {
 			log.Printf("Filtering plans by isTemplate=%v", *isTemplate)
@@ -115,22 +156,28 @@ func (h *PlanHandler) List(w http.ResponseWriter, r *http.Request) {
 			// Convert DB plan to API plan and return as slice for consistent API response
 			apiPlan := dbPlanToApiPlan(*dbPlan)
 			result := []types.Plan{apiPlan}
+			if err := attachPlanCommentCounts(r.Context(), queries, result); err != nil {
+				return err
+			}
 
 			log.Printf("Successfully retrieved plan, ID: %d", apiPlan.ID)
 			w.Header().Set("Content-Type", "application/json")
 			return json.NewEncoder(w).Encode(&result)
 		}
 
-		// Handle userId filter - get plans by user
+		// Handle userId filter - get the user's plans and the plans shared with them
 		if userId != nil {
-			dbPlans, err := planRepo.GetPlansByUserId(r.Context(), *userId, int(limit), int(offset), isTemplate, isPublic)
+			dbPlans, err := planRepo.ListAccessible(r.Context(), *userId, int(limit), int(offset), isTemplate, isPublic, shared)
 			if err != nil {
-				log.Printf("Error from GetPlansByUserId: %v", err)
+				log.Printf("Error from ListAccessible: %v", err)
 				return err
 			}
 
 			// Convert DB plans to API plans
-			apiPlans := dbPlansToApiPlans(dbPlans)
+			apiPlans := dbAccessiblePlansToApiPlans(dbPlans)
+			if err := attachPlanCommentCounts(r.Context(), queries, apiPlans); err != nil {
+				return err
+			}
 
 			log.Printf("Successfully retrieved plans, count: %d", len(apiPlans))
 			w.Header().Set("Content-Type", "application/json")
@@ -169,6 +216,12 @@ func (h *PlanHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	startDate, err := parsePlanStartDate(args.StartDate)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	log.Printf("Creating plan with name=%s, userId=%d, isTemplate=%v, isPublic=%v",
 		args.Name, args.UserId, args.IsTemplate, args.IsPublic)
 
@@ -184,6 +237,7 @@ func (h *PlanHandler) Create(w http.ResponseWriter, r *http.Request) {
 			args.UserId,
 			args.IsTemplate,
 			args.IsPublic,
+			startDate,
 		)
 		if err != nil {
 			log.Printf("Error creating plan: %v", err)
@@ -225,12 +279,30 @@ func (h *PlanHandler) Edit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	startDate, err := parsePlanStartDate(args.StartDate)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	log.Printf("Updating plan with name=%s, isTemplate=%v, isPublic=%v",
 		args.Name, args.IsTemplate, args.IsPublic)
 
 	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
 		planRepo := repository.PlansRepository{Queries: queries}
 
+		if args.StartDate == nil {
+			current, err := planRepo.GetPlanById(r.Context(), id)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+					return nil
+				}
+				return err
+			}
+			startDate = current.StartDate
+		}
+
 		dbPlan, err := planRepo.UpdatePlan(
 			r.Context(),
 			id,
@@ -238,6 +310,7 @@ func (h *PlanHandler) Edit(w http.ResponseWriter, r *http.Request) {
 			args.Description,
 			args.IsTemplate,
 			args.IsPublic,
+			startDate,
 		)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
@@ -286,3 +359,51 @@ func (h *PlanHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 }
+
+// EquipmentCheck warns about equipment the plan's exercises require that the athlete
+// has not declared. The athlete defaults to the plan's owner.
+func (h *PlanHandler) EquipmentCheck(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+
+	filterParser := api_utils.NewFilterParser(r, true)
+	userId := filterParser.GetIntFilterOrZero("userId")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		planRepo := repository.PlansRepository{Queries: queries}
+		exerciseRepo := repository.NewExercisesRepository(queries)
+		equipmentRepo := repository.NewUserEquipmentRepository(queries)
+
+		dbPlan, err := planRepo.GetPlanById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+
+		if userId == 0 {
+			userId = dbPlan.UserID
+		}
+
+		exercises, err := exerciseRepo.ListEquipmentByPlanId(r.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		inventory, err := equipmentRepo.ListByUserId(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		check := service.CheckPlanEquipment(id, userId, exercises, inventory)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(check)
+	})
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/service"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"backend/internal/workout"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type PrescriptionBlocksHandler struct {
+	Db *db.Database
+}
+
+type CreatePrescriptionBlockApiArgs struct {
+	GroupId         int64   `json:"groupId"`
+	PlanIntervalId  int64   `json:"planIntervalId"`
+	Kind            string  `json:"kind"`
+	Rest            *string `json:"rest"`
+	PrescriptionIds []int64 `json:"prescriptionIds"`
+}
+
+// UpdatePrescriptionBlockApiArgs leaves omitted fields unchanged, an empty rest clears it
+type UpdatePrescriptionBlockApiArgs struct {
+	Kind            *string  `json:"kind"`
+	Rest            *string  `json:"rest"`
+	PrescriptionIds *[]int64 `json:"prescriptionIds"`
+}
+
+// Helper function to convert a DB block and its prescriptions to an API block
+func dbBlockToApiBlock(block *db.PrescriptionBlock, prescriptionIds []int64) types.PrescriptionBlock {
+	var rest *types.PostgreSQLInterval
+	if block.Rest.Valid {
+		pgInterval := types.NewPostgreSQLInterval(block.Rest)
+		rest = &pgInterval
+	}
+	if prescriptionIds == nil {
+		prescriptionIds = []int64{}
+	}
+
+	return types.PrescriptionBlock{
+		ID:              block.ID,
+		GroupId:         block.GroupID,
+		PlanIntervalId:  block.PlanIntervalID,
+		Kind:            block.Kind,
+		Rest:            rest,
+		PrescriptionIds: prescriptionIds,
+		CreatedAt:       block.CreatedAt.Time.String(),
+		UpdatedAt:       block.UpdatedAt.Time.String(),
+	}
+}
+
+func validateBlockKind(kind string) error {
+	if kind != repository.BlockKindSuperset && kind != repository.BlockKindCircuit {
+		return errors.New("Kind must be superset or circuit")
+	}
+	return nil
+}
+
+func parseBlockRest(rest *string) (pgtype.Interval, error) {
+	if rest == nil || *rest == "" {
+		return pgtype.Interval{Valid: false}, nil
+	}
+	interval, err := utils.StringToInterval(*rest)
+	if err != nil {
+		return pgtype.Interval{}, errors.New("Invalid rest duration")
+	}
+	return interval, nil
+}
+
+// checkBlockMembers makes sure the prescriptions can be linked into the block, returning
+// the status and message to reject the request with
+func checkBlockMembers(entries []workout.Entry, blockId int64, ids []int64) (int, string) {
+	if len(ids) < 2 {
+		return http.StatusBadRequest, "A superset or circuit needs at least two prescriptions"
+	}
+
+	blocks := make(map[int64]int64, len(entries))
+	for _, entry := range entries {
+		blocks[entry.ID] = entry.BlockID
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		current, ok := blocks[id]
+		if !ok {
+			return http.StatusBadRequest, fmt.Sprintf("Prescription %d does not belong to the group in this interval", id)
+		}
+		if seen[id] {
+			return http.StatusBadRequest, fmt.Sprintf("Prescription %d is listed more than once", id)
+		}
+		seen[id] = true
+		if current != 0 && current != blockId {
+			return http.StatusConflict, fmt.Sprintf("Prescription %d is already in a superset or circuit", id)
+		}
+	}
+	return 0, ""
+}
+
+// linkBlockMembers makes ids the members of the block, in order, and moves them next to
+// each other in the group's order
+func linkBlockMembers(ctx context.Context, queries *db.Queries, entries []workout.Entry, blockId int64, ids []int64) error {
+	prescriptionRepo := repository.NewIntervalExercisePrescriptionsRepository(queries)
+
+	members := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		members[id] = true
+	}
+	for _, entry := range entries {
+		if entry.BlockID == blockId && !members[entry.ID] {
+			if err := prescriptionRepo.SetBlock(ctx, entry.ID, 0); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range ids {
+		if err := prescriptionRepo.SetBlock(ctx, id, blockId); err != nil {
+			return err
+		}
+	}
+
+	return prescriptionRepo.Reorder(ctx, workout.Link(entryIds(entries), ids))
+}
+
+// blockMembers returns the prescriptions of each block in the order they are performed
+func blockMembers(ctx context.Context, queries *db.Queries, groupId int64, intervalId int64) (map[int64][]int64, error) {
+	dbPrescriptions, err := repository.NewIntervalExercisePrescriptionsRepository(queries).List(ctx, repository.IntervalExercisePrescriptionListParams{
+		GroupId:    groupId,
+		IntervalId: intervalId,
+		Limit:      scheduleSessionLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int64][]int64)
+	for _, dbPrescription := range dbPrescriptions {
+		if dbPrescription.BlockID.Valid {
+			members[dbPrescription.BlockID.Int64] = append(members[dbPrescription.BlockID.Int64], dbPrescription.ID)
+		}
+	}
+	return members, nil
+}
+
+func writeBlock(ctx context.Context, queries *db.Queries, w http.ResponseWriter, block *db.PrescriptionBlock, status int) error {
+	members, err := blockMembers(ctx, queries, block.GroupID, block.PlanIntervalID)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(dbBlockToApiBlock(block, members[block.ID]))
+}
+
+// List returns the supersets and circuits of a group and/or interval
+func (h *PrescriptionBlocksHandler) List(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	groupId := filterParser.GetIntFilterOrZero("groupId")
+	intervalId := filterParser.GetIntFilterOrZero("intervalId")
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbBlocks, err := repository.NewPrescriptionBlocksRepository(queries).List(r.Context(), groupId, intervalId, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+		members, err := blockMembers(r.Context(), queries, groupId, intervalId)
+		if err != nil {
+			return err
+		}
+
+		blocks := make([]types.PrescriptionBlock, len(dbBlocks))
+		for i := range dbBlocks {
+			blocks[i] = dbBlockToApiBlock(&dbBlocks[i], members[dbBlocks[i].ID])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(blocks)
+	})
+}
+
+// Get returns a single superset or circuit
+func (h *PrescriptionBlocksHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid prescription block ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		block, err := repository.NewPrescriptionBlocksRepository(queries).GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Prescription block not found")
+				return nil
+			}
+			return err
+		}
+		return writeBlock(r.Context(), queries, w, block, http.StatusOK)
+	})
+}
+
+// Create links prescriptions of a group into a superset or circuit. The prescriptions are
+// moved next to each other, in the order given, where the first of them was.
+func (h *PrescriptionBlocksHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var args CreatePrescriptionBlockApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		log.Printf("Error decoding request body: %v", err)
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.GroupId == 0 || args.PlanIntervalId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Group ID and plan interval ID are required")
+		return
+	}
+	if err := validateBlockKind(args.Kind); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rest, err := parseBlockRest(args.Rest)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		entries, err := groupPrescriptionEntries(r.Context(), queries, args.GroupId, args.PlanIntervalId)
+		if err != nil {
+			return err
+		}
+		if status, message := checkBlockMembers(entries, 0, args.PrescriptionIds); status != 0 {
+			api_utils.WriteError(w, status, message)
+			return nil
+		}
+
+		block, err := repository.NewPrescriptionBlocksRepository(queries).Create(r.Context(), args.GroupId, args.PlanIntervalId, args.Kind, rest)
+		if err != nil {
+			return err
+		}
+		if err := linkBlockMembers(r.Context(), queries, entries, block.ID, args.PrescriptionIds); err != nil {
+			return err
+		}
+		return writeBlock(r.Context(), queries, w, block, http.StatusCreated)
+	})
+}
+
+// Update changes the kind, rest or prescriptions of a superset or circuit
+func (h *PrescriptionBlocksHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid prescription block ID")
+		return
+	}
+
+	var args UpdatePrescriptionBlockApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		log.Printf("Error decoding request body: %v", err)
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.Kind != nil {
+		if err := validateBlockKind(*args.Kind); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		blocksRepo := repository.NewPrescriptionBlocksRepository(queries)
+		block, err := blocksRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Prescription block not found")
+				return nil
+			}
+			return err
+		}
+
+		kind := service.DerefOrDefault(args.Kind, block.Kind)
+		rest := block.Rest
+		if args.Rest != nil {
+			if rest, err = parseBlockRest(args.Rest); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
+
+		if args.PrescriptionIds != nil {
+			entries, err := groupPrescriptionEntries(r.Context(), queries, block.GroupID, block.PlanIntervalID)
+			if err != nil {
+				return err
+			}
+			if status, message := checkBlockMembers(entries, block.ID, *args.PrescriptionIds); status != 0 {
+				api_utils.WriteError(w, status, message)
+				return nil
+			}
+			if err := linkBlockMembers(r.Context(), queries, entries, block.ID, *args.PrescriptionIds); err != nil {
+				return err
+			}
+		}
+
+		block, err = blocksRepo.Update(r.Context(), block.ID, kind, rest)
+		if err != nil {
+			return err
+		}
+		return writeBlock(r.Context(), queries, w, block, http.StatusOK)
+	})
+}
+
+// Delete unlinks a superset or circuit, its prescriptions keep their order
+func (h *PrescriptionBlocksHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid prescription block ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		blocksRepo := repository.NewPrescriptionBlocksRepository(queries)
+		if _, err := blocksRepo.GetById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Prescription block not found")
+				return nil
+			}
+			return err
+		}
+		if err := blocksRepo.Delete(r.Context(), id); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
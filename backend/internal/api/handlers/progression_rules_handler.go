@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/progression"
+	"backend/internal/service"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+type ProgressionRulesHandler struct {
+	Db *db.Database
+}
+
+// CreateProgressionRuleApiArgs attaches a rule to either a prescription or a group
+type CreateProgressionRuleApiArgs struct {
+	PrescriptionId int64                   `json:"prescriptionId"`
+	GroupId        int64                   `json:"groupId"`
+	Steps          []types.ProgressionStep `json:"steps"`
+}
+
+type UpdateProgressionRuleApiArgs struct {
+	Steps []types.ProgressionStep `json:"steps"`
+}
+
+// Helper function to convert a DB rule and its steps to an API rule
+func dbProgressionRuleToApiRule(rule db.ProgressionRule, steps []db.ProgressionRuleStep) types.ProgressionRule {
+	apiRule := types.ProgressionRule{
+		ID:             rule.ID,
+		PrescriptionId: utils.If(rule.PrescriptionID.Valid, &rule.PrescriptionID.Int64, nil),
+		GroupId:        utils.If(rule.GroupID.Valid, &rule.GroupID.Int64, nil),
+		Steps:          make([]types.ProgressionStep, len(steps)),
+		CreatedAt:      rule.CreatedAt.Time.String(),
+		UpdatedAt:      rule.UpdatedAt.Time.String(),
+	}
+	for i, step := range steps {
+		apiRule.Steps[i] = types.ProgressionStep{
+			Target:          step.Target,
+			ParameterTypeId: utils.If(step.ParameterTypeID.Valid, &step.ParameterTypeID.Int64, nil),
+			Amount:          step.Amount,
+			Unit:            step.Unit,
+			Until:           utils.If(step.UntilValue.Valid, &step.UntilValue.Float64, nil),
+		}
+	}
+	return apiRule
+}
+
+// dbProgressionRulesToApiRules loads the steps of the rules, keeping the rules' order
+func dbProgressionRulesToApiRules(ctx context.Context, queries *db.Queries, rules []db.ProgressionRule) ([]types.ProgressionRule, error) {
+	ids := make([]int64, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ID
+	}
+	dbSteps, err := repository.NewProgressionRulesRepository(queries).ListSteps(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	steps := make(map[int64][]db.ProgressionRuleStep)
+	for _, step := range dbSteps {
+		steps[step.RuleID] = append(steps[step.RuleID], step)
+	}
+
+	apiRules := make([]types.ProgressionRule, len(rules))
+	for i, rule := range rules {
+		apiRules[i] = dbProgressionRuleToApiRule(rule, steps[rule.ID])
+	}
+	return apiRules, nil
+}
+
+// progressionSteps converts API steps for the progression package, defaulting the unit
+// to absolute amounts
+func progressionSteps(steps []types.ProgressionStep) []progression.Step {
+	result := make([]progression.Step, len(steps))
+	for i, step := range steps {
+		result[i] = progression.Step{
+			Target:          step.Target,
+			ParameterTypeID: service.DerefOrDefault(step.ParameterTypeId, 0),
+			Amount:          step.Amount,
+			Unit:            utils.If(step.Unit == "", progression.UnitAbsolute, step.Unit),
+			Until:           step.Until,
+		}
+	}
+	return result
+}
+
+func toProgressionStepData(steps []progression.Step) []repository.ProgressionStepData {
+	data := make([]repository.ProgressionStepData, len(steps))
+	for i, step := range steps {
+		data[i] = repository.ProgressionStepData{
+			Target:          step.Target,
+			ParameterTypeId: step.ParameterTypeID,
+			Amount:          step.Amount,
+			Unit:            step.Unit,
+			Until:           step.Until,
+		}
+	}
+	return data
+}
+
+// prescriptionProgressionRules returns the rule each prescription progresses by: its own
+// rule, or else the rule of its group. Prescriptions without either are left out.
+func prescriptionProgressionRules(ctx context.Context, queries *db.Queries, prescriptions []types.IntervalExercisePrescription) (map[int64]types.ProgressionRule, error) {
+	prescriptionIds := make([]int64, len(prescriptions))
+	groupIds := make([]int64, len(prescriptions))
+	for i, prescription := range prescriptions {
+		prescriptionIds[i] = prescription.ID
+		groupIds[i] = prescription.GroupId
+	}
+	dbRules, err := repository.NewProgressionRulesRepository(queries).ListForPrescriptions(ctx, prescriptionIds, groupIds)
+	if err != nil {
+		return nil, err
+	}
+	apiRules, err := dbProgressionRulesToApiRules(ctx, queries, dbRules)
+	if err != nil {
+		return nil, err
+	}
+
+	byPrescription := make(map[int64]types.ProgressionRule)
+	byGroup := make(map[int64]types.ProgressionRule)
+	for _, rule := range apiRules {
+		if rule.PrescriptionId != nil {
+			byPrescription[*rule.PrescriptionId] = rule
+		} else {
+			byGroup[*rule.GroupId] = rule
+		}
+	}
+
+	rules := make(map[int64]types.ProgressionRule)
+	for _, prescription := range prescriptions {
+		if rule, ok := byPrescription[prescription.ID]; ok {
+			rules[prescription.ID] = rule
+		} else if rule, ok := byGroup[prescription.GroupId]; ok {
+			rules[prescription.ID] = rule
+		}
+	}
+	return rules, nil
+}
+
+func writeProgressionRule(ctx context.Context, queries *db.Queries, w http.ResponseWriter, rule *db.ProgressionRule, status int) error {
+	apiRules, err := dbProgressionRulesToApiRules(ctx, queries, []db.ProgressionRule{*rule})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(apiRules[0])
+}
+
+// List returns progression rules, optionally filtered by prescriptionId or groupId
+func (h *ProgressionRulesHandler) List(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+	prescriptionId := filterParser.GetIntFilterOrZero("prescriptionId")
+	groupId := filterParser.GetIntFilterOrZero("groupId")
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetIntFilterOrZero("offset")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbRules, err := repository.NewProgressionRulesRepository(queries).List(r.Context(), prescriptionId, groupId, int32(offset), int32(limit))
+		if err != nil {
+			return err
+		}
+		apiRules, err := dbProgressionRulesToApiRules(r.Context(), queries, dbRules)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiRules)
+	})
+}
+
+func (h *ProgressionRulesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var args CreateProgressionRuleApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if (args.PrescriptionId == 0) == (args.GroupId == 0) {
+		api_utils.WriteError(w, http.StatusBadRequest, "Exactly one of prescriptionId and groupId is required")
+		return
+	}
+	steps := progressionSteps(args.Steps)
+	if err := progression.Validate(steps); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if args.PrescriptionId != 0 {
+			if _, err := repository.NewIntervalExercisePrescriptionsRepository(queries).GetById(r.Context(), args.PrescriptionId); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					api_utils.WriteError(w, http.StatusNotFound, "Prescription not found")
+					return nil
+				}
+				return err
+			}
+		} else if _, err := repository.NewGroupsRepository(queries).GetGroupById(r.Context(), args.GroupId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Group not found")
+				return nil
+			}
+			return err
+		}
+
+		rulesRepo := repository.NewProgressionRulesRepository(queries)
+		existing, err := rulesRepo.List(r.Context(), args.PrescriptionId, args.GroupId, 0, 1)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			api_utils.WriteError(w, http.StatusConflict, utils.If(args.PrescriptionId != 0,
+				"The prescription already has a progression rule",
+				"The group already has a progression rule"))
+			return nil
+		}
+
+		rule, err := rulesRepo.Create(r.Context(), args.PrescriptionId, args.GroupId, toProgressionStepData(steps))
+		if err != nil {
+			return err
+		}
+		return writeProgressionRule(r.Context(), queries, w, rule, http.StatusCreated)
+	})
+}
+
+// Update replaces the steps of a rule
+func (h *ProgressionRulesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid progression rule ID")
+		return
+	}
+
+	var args UpdateProgressionRuleApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	steps := progressionSteps(args.Steps)
+	if err := progression.Validate(steps); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		rulesRepo := repository.NewProgressionRulesRepository(queries)
+		if _, err := rulesRepo.GetById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Progression rule not found")
+				return nil
+			}
+			return err
+		}
+
+		rule, err := rulesRepo.ReplaceSteps(r.Context(), id, toProgressionStepData(steps))
+		if err != nil {
+			return err
+		}
+		return writeProgressionRule(r.Context(), queries, w, rule, http.StatusOK)
+	})
+}
+
+func (h *ProgressionRulesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid progression rule ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		rulesRepo := repository.NewProgressionRulesRepository(queries)
+		if _, err := rulesRepo.GetById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Progression rule not found")
+				return nil
+			}
+			return err
+		}
+		if err := rulesRepo.Delete(r.Context(), id); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
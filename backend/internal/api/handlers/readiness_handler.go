@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/readiness"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// readinessCheckinLimit caps how many check-ins scores and trends are computed from
+const readinessCheckinLimit = 1000
+
+// readinessTrendMaxDays caps how many days a readiness trend covers
+const readinessTrendMaxDays = 366
+
+type ReadinessHandler struct {
+	Db *db.Database
+}
+
+// ReadinessCheckinApiArgs is a day's check-in. Every measure is optional, but a check-in
+// needs at least one.
+type ReadinessCheckinApiArgs struct {
+	SleepHours *float64         `json:"sleepHours"`
+	Soreness   map[string]int32 `json:"soreness"`
+	FingerPain *int32           `json:"fingerPain"`
+	Mood       *int32           `json:"mood"`
+	RestingHR  *int32           `json:"restingHr"`
+}
+
+// Helper function to check a check-in and put it in the shape it is stored in
+func readinessCheckinData(args ReadinessCheckinApiArgs) (repository.ReadinessCheckinData, error) {
+	if args.SleepHours == nil && len(args.Soreness) == 0 && args.FingerPain == nil && args.Mood == nil && args.RestingHR == nil {
+		return repository.ReadinessCheckinData{}, errors.New("A check-in needs at least one measure")
+	}
+	if args.SleepHours != nil && (*args.SleepHours < 0 || *args.SleepHours > 24) {
+		return repository.ReadinessCheckinData{}, errors.New("Sleep hours must be between 0 and 24")
+	}
+	if args.FingerPain != nil && (*args.FingerPain < 0 || *args.FingerPain > 10) {
+		return repository.ReadinessCheckinData{}, errors.New("Finger pain must be between 0 and 10")
+	}
+	if args.Mood != nil && (*args.Mood < 1 || *args.Mood > 5) {
+		return repository.ReadinessCheckinData{}, errors.New("Mood must be between 1 and 5")
+	}
+	if args.RestingHR != nil && (*args.RestingHR < 20 || *args.RestingHR > 250) {
+		return repository.ReadinessCheckinData{}, errors.New("Resting heart rate must be between 20 and 250")
+	}
+
+	soreness := make(map[string]int32, len(args.Soreness))
+	for area, score := range args.Soreness {
+		normalized, err := utils.NormalizeBodyArea(area)
+		if err != nil {
+			return repository.ReadinessCheckinData{}, fmt.Errorf("Invalid soreness: %w", err)
+		}
+		if score < 0 || score > 10 {
+			return repository.ReadinessCheckinData{}, fmt.Errorf("Soreness of %s must be between 0 and 10", normalized)
+		}
+		soreness[normalized] = score
+	}
+
+	return repository.ReadinessCheckinData{
+		SleepHours: args.SleepHours,
+		Soreness:   soreness,
+		FingerPain: args.FingerPain,
+		Mood:       args.Mood,
+		RestingHR:  args.RestingHR,
+	}, nil
+}
+
+// Helper function to convert a DB check-in to the check-in readiness is scored from
+func dbCheckinToReadinessCheckin(checkin db.ReadinessCheckin, soreness map[string]int32) readiness.Checkin {
+	return readiness.Checkin{
+		Date:       checkin.CheckedOn.Time,
+		SleepHours: utils.If(checkin.SleepHours.Valid, &checkin.SleepHours.Float64, nil),
+		Soreness:   soreness,
+		FingerPain: utils.If(checkin.FingerPain.Valid, &checkin.FingerPain.Int32, nil),
+		Mood:       utils.If(checkin.Mood.Valid, &checkin.Mood.Int32, nil),
+		RestingHR:  utils.If(checkin.RestingHr.Valid, &checkin.RestingHr.Int32, nil),
+	}
+}
+
+// Helper function to convert a DB check-in to an API check-in
+func dbCheckinToApiCheckin(checkin db.ReadinessCheckin, soreness map[string]int32, score *float64) types.ReadinessCheckin {
+	scored := dbCheckinToReadinessCheckin(checkin, soreness)
+	return types.ReadinessCheckin{
+		ID:         checkin.ID,
+		UserID:     checkin.UserID,
+		CheckedOn:  utils.DateToString(checkin.CheckedOn),
+		SleepHours: scored.SleepHours,
+		Soreness:   utils.If(soreness == nil, map[string]int32{}, soreness),
+		FingerPain: scored.FingerPain,
+		Mood:       scored.Mood,
+		RestingHR:  scored.RestingHR,
+		Score:      score,
+		CreatedAt:  checkin.CreatedAt.Time.String(),
+		UpdatedAt:  checkin.UpdatedAt.Time.String(),
+	}
+}
+
+// scoredCheckins returns the user's check-ins between the dates, oldest first, with their
+// scores. The check-ins of the BaselineDays before from are read for the heart rate
+// baselines and returned with the others for scoring.
+func scoredCheckins(ctx context.Context, queries *db.Queries, userId int64, from pgtype.Date, to pgtype.Date) ([]types.ReadinessCheckin, []readiness.Checkin, error) {
+	readinessRepo := repository.NewReadinessRepository(queries)
+
+	since := utils.TimeToDate(from.Time.AddDate(0, 0, -readiness.BaselineDays))
+	dbCheckins, err := readinessRepo.List(ctx, userId, since, to, 0, readinessCheckinLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	slices.Reverse(dbCheckins)
+
+	ids := make([]int64, len(dbCheckins))
+	for i, checkin := range dbCheckins {
+		ids[i] = checkin.ID
+	}
+	soreness, err := readinessRepo.ListSoreness(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkins := make([]readiness.Checkin, len(dbCheckins))
+	for i, checkin := range dbCheckins {
+		checkins[i] = dbCheckinToReadinessCheckin(checkin, soreness[checkin.ID])
+	}
+	scores := readiness.ScoreAll(checkins)
+
+	result := []types.ReadinessCheckin{}
+	for i, checkin := range dbCheckins {
+		if !checkin.CheckedOn.Time.Before(from.Time) {
+			result = append(result, dbCheckinToApiCheckin(checkin, soreness[checkin.ID], scores[i]))
+		}
+	}
+	return result, checkins, nil
+}
+
+// Helper function to read a from and to date range, ending today and starting four
+// weeks before its end by default
+func readDateRange(filterParser *api_utils.FilterParser, maxDays int) (pgtype.Date, pgtype.Date, error) {
+	to, err := dateOrToday(filterParser.GetStringFilter("to"))
+	if err != nil {
+		return pgtype.Date{}, pgtype.Date{}, errors.New("Invalid to date")
+	}
+	from := utils.TimeToDate(to.Time.AddDate(0, 0, -27))
+	if value := filterParser.GetStringFilter("from"); value != "" {
+		if from, err = utils.StringToDate(value); err != nil {
+			return pgtype.Date{}, pgtype.Date{}, errors.New("Invalid from date")
+		}
+	}
+	if from.Time.After(to.Time) {
+		return pgtype.Date{}, pgtype.Date{}, errors.New("from must not be after to")
+	}
+	if days := int(to.Time.Sub(from.Time).Hours()/24) + 1; days > maxDays {
+		return pgtype.Date{}, pgtype.Date{}, fmt.Errorf("A range covers at most %d days", maxDays)
+	}
+	return from, to, nil
+}
+
+// List returns the user's check-ins with their scores, most recent first, optionally
+// between from and to
+func (h *ReadinessHandler) List(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	filterParser := api_utils.NewFilterParser(r, true)
+	var from, to pgtype.Date
+	if value := filterParser.GetStringFilter("from"); value != "" {
+		if from, err = utils.StringToDate(value); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid from date")
+			return
+		}
+	}
+	if value := filterParser.GetStringFilter("to"); value != "" {
+		if to, err = utils.StringToDate(value); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, "Invalid to date")
+			return
+		}
+	}
+	limit := filterParser.GetLimit(100)
+	offset := filterParser.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbCheckins, err := repository.NewReadinessRepository(queries).List(r.Context(), userId, from, to, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		result := []types.ReadinessCheckin{}
+		if len(dbCheckins) > 0 {
+			// Scores need the baselines of the listed days
+			scored, _, err := scoredCheckins(r.Context(), queries, userId, dbCheckins[len(dbCheckins)-1].CheckedOn, dbCheckins[0].CheckedOn)
+			if err != nil {
+				return err
+			}
+			for i := len(scored) - 1; i >= 0; i-- {
+				result = append(result, scored[i])
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Save records the user's check-in for a date, replacing one already given that day
+func (h *ReadinessHandler) Save(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	date, err := utils.StringToDate(chi.URLParam(r, "date"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid date")
+		return
+	}
+	if date.Time.After(time.Now()) {
+		api_utils.WriteError(w, http.StatusBadRequest, "Check-ins cannot be given for future dates")
+		return
+	}
+
+	var args ReadinessCheckinApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	data, err := readinessCheckinData(args)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		checkin, err := repository.NewReadinessRepository(queries).Save(r.Context(), userId, date, data)
+		if err != nil {
+			return err
+		}
+		scored, _, err := scoredCheckins(r.Context(), queries, userId, date, date)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Saved readiness check-in %d for user %d", checkin.ID, userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(scored[0])
+	})
+}
+
+// Delete removes the user's check-in for a date
+func (h *ReadinessHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	date, err := utils.StringToDate(chi.URLParam(r, "date"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid date")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := repository.NewReadinessRepository(queries).DeleteByDate(r.Context(), userId, date); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Check-in not found")
+				return nil
+			}
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Trend returns the user's daily readiness between from and to with weekly averages. to
+// defaults to today and from to four weeks before it.
+func (h *ReadinessHandler) Trend(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	from, to, err := readDateRange(api_utils.NewFilterParser(r, true), readinessTrendMaxDays)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		_, checkins, err := scoredCheckins(r.Context(), queries, userId, from, to)
+		if err != nil {
+			return err
+		}
+
+		result := []types.ReadinessTrendDay{}
+		for _, day := range readiness.Trend(checkins, from.Time, to.Time) {
+			result = append(result, types.ReadinessTrendDay{
+				Date:              utils.DateToString(utils.TimeToDate(day.Date)),
+				Score:             day.Score,
+				ScoreAverage:      day.ScoreAverage,
+				SleepAverage:      day.SleepAverage,
+				FingerPainAverage: day.FingerPainAverage,
+				RestingHRAverage:  day.RestingHRAverage,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// Recommendation recommends how to change a scheduled session given the athlete's
+// check-in on its date: resting or reducing intensity when readiness is low, and
+// swapping a finger training group for an antagonist group of the same interval when
+// the fingers hurt
+func (h *ReadinessHandler) Recommendation(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		session, err := repository.NewScheduledSessionsRepository(queries).GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Session not found")
+				return nil
+			}
+			return err
+		}
+		apiSession, err := getApiSession(r.Context(), queries, session)
+		if err != nil {
+			return err
+		}
+
+		result := types.ReadinessRecommendation{
+			SessionID: id,
+			Date:      apiSession.Date,
+			Actions:   []types.ReadinessAction{},
+		}
+		scored, checkins, err := scoredCheckins(r.Context(), queries, apiSession.UserID, session.ScheduledDate, session.ScheduledDate)
+		if err != nil {
+			return err
+		}
+		if len(scored) > 0 {
+			result.Checkin = &scored[0]
+
+			rows, err := repository.NewReadinessRepository(queries).ListIntervalGroupExercises(r.Context(), session.PlanIntervalID)
+			if err != nil {
+				return err
+			}
+			var groups []readiness.Group
+			for _, row := range rows {
+				if len(groups) == 0 || groups[len(groups)-1].ID != row.GroupID {
+					groups = append(groups, readiness.Group{ID: row.GroupID, Name: row.Name})
+				}
+				last := &groups[len(groups)-1]
+				last.Exercises = append(last.Exercises, readiness.Exercise{
+					PrimaryMuscleGroups: row.PrimaryMuscleGroups,
+					Equipment:           row.Equipment,
+				})
+			}
+			sessionGroup := readiness.Group{ID: session.GroupID}
+			if index := slices.IndexFunc(groups, func(group readiness.Group) bool { return group.ID == session.GroupID }); index >= 0 {
+				sessionGroup = groups[index]
+			}
+
+			checkin := checkins[len(checkins)-1]
+			for _, action := range readiness.Recommend(checkin, scored[0].Score, sessionGroup, groups, readiness.DefaultThresholds) {
+				apiAction := types.ReadinessAction{Kind: action.Kind, Reason: action.Reason}
+				if action.IntensityFactor > 0 {
+					apiAction.IntensityFactor = &action.IntensityFactor
+				}
+				if action.Group != nil {
+					apiAction.GroupId = &action.Group.ID
+					apiAction.GroupName = action.Group.Name
+				}
+				result.Actions = append(result.Actions, apiAction)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/schedule"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// scheduleIntervalLimit caps how many intervals a schedule is generated for
+const scheduleIntervalLimit = 1000
+
+// scheduleSessionLimit caps how many sessions are read back when rebuilding a schedule
+const scheduleSessionLimit = 10000
+
+type ScheduleHandler struct {
+	Db *db.Database
+}
+
+type RescheduleSessionApiArgs struct {
+	Date string `json:"date"`
+}
+
+// Helper function to convert a listed DB session to an API session
+func dbSessionToApiSession(row db.ScheduledSessions_ListRow) types.ScheduledSession {
+	return types.ScheduledSession{
+		ID:               row.ID,
+		PlanID:           row.PlanID,
+		PlanAssignmentID: row.PlanAssignmentID,
+		UserID:           row.UserID,
+		PlanIntervalID:   row.PlanIntervalID,
+		GroupID:          row.GroupID,
+		PlanName:         row.PlanName,
+		GroupName:        row.GroupName,
+		IntervalName:     row.IntervalName.String,
+		Date:             utils.DateToString(row.ScheduledDate),
+		OriginalDate:     utils.DateToString(row.OriginalDate),
+		Rescheduled:      row.ScheduledDate.Time != row.OriginalDate.Time,
+		Status:           row.Status,
+	}
+}
+
+// Helper function to list sessions in the API shape
+func listSessions(ctx context.Context, queries *db.Queries, filter repository.ScheduledSessionFilter, offset int32, limit int32) ([]types.ScheduledSession, error) {
+	rows, err := repository.NewScheduledSessionsRepository(queries).List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]types.ScheduledSession, len(rows))
+	for i, row := range rows {
+		sessions[i] = dbSessionToApiSession(row)
+	}
+	return sessions, nil
+}
+
+// Helper function to read back a single session in the API shape
+func getApiSession(ctx context.Context, queries *db.Queries, session *db.ScheduledSession) (types.ScheduledSession, error) {
+	filter := repository.ScheduledSessionFilter{
+		PlanAssignmentID: session.PlanAssignmentID,
+		From:             session.ScheduledDate,
+		To:               session.ScheduledDate,
+	}
+	sessions, err := listSessions(ctx, queries, filter, 0, scheduleSessionLimit)
+	if err != nil {
+		return types.ScheduledSession{}, err
+	}
+	for _, apiSession := range sessions {
+		if apiSession.ID == session.ID {
+			return apiSession, nil
+		}
+	}
+	return types.ScheduledSession{}, errors.New("session is missing from its schedule")
+}
+
+// Helper function to read a plan's intervals and assignments in the shape the scheduler expects
+func loadScheduleIntervals(ctx context.Context, queries *db.Queries, planId int64) ([]schedule.Interval, error) {
+	intervalRepo := repository.PlanIntervalsRepository{Queries: queries}
+	assignmentRepo := repository.IntervalGroupAssignmentsRepository{Queries: queries}
+
+	dbIntervals, err := intervalRepo.ListPlanIntervals(ctx, planId, 0, scheduleIntervalLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	intervals := make([]schedule.Interval, 0, len(dbIntervals))
+	for _, dbInterval := range dbIntervals {
+		assignments, err := assignmentRepo.GetByIntervalId(ctx, dbInterval.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		interval := schedule.Interval{ID: dbInterval.ID, Days: utils.IntervalToDays(dbInterval.Duration)}
+		for _, assignment := range assignments {
+			group := schedule.Group{ID: assignment.GroupId, Frequency: assignment.Frequency}
+			for _, day := range assignment.PinnedDays {
+				group.PinnedDays = append(group.PinnedDays, time.Weekday(day))
+			}
+			interval.Groups = append(interval.Groups, group)
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, nil
+}
+
+// Helper function to turn an assignment's preferences into scheduler options
+func assignmentScheduleOptions(assignment *db.PlanAssignment) schedule.Options {
+	options := schedule.Options{MinRestDays: int(assignment.MinRestDays)}
+	for _, day := range assignment.TrainingDays {
+		options.TrainingDays = append(options.TrainingDays, time.Weekday(day))
+	}
+	return options
+}
+
+// Helper function to lay out an assignment's sessions from its start date. Completed and
+// skipped sessions are kept and take the place of the generated session they stand for;
+// every pending session is replaced, including any that were moved.
+func generateAssignmentSessions(ctx context.Context, queries *db.Queries, assignment *db.PlanAssignment) error {
+	sessionRepo := repository.NewScheduledSessionsRepository(queries)
+
+	intervals, err := loadScheduleIntervals(ctx, queries, assignment.PlanID)
+	if err != nil {
+		return err
+	}
+
+	if err := sessionRepo.DeletePending(ctx, assignment.ID); err != nil {
+		return err
+	}
+	kept, err := sessionRepo.List(ctx, repository.ScheduledSessionFilter{PlanAssignmentID: assignment.ID}, 0, scheduleSessionLimit)
+	if err != nil {
+		return err
+	}
+	type slot struct{ intervalId, groupId int64 }
+	done := make(map[slot]int)
+	for _, session := range kept {
+		done[slot{session.PlanIntervalID, session.GroupID}]++
+	}
+
+	for _, session := range schedule.GenerateWithOptions(assignment.StartDate.Time, intervals, assignmentScheduleOptions(assignment)) {
+		key := slot{session.IntervalID, session.GroupID}
+		if done[key] > 0 {
+			done[key]--
+			continue
+		}
+		if _, err := sessionRepo.Create(ctx, assignment, session.IntervalID, session.GroupID, utils.TimeToDate(session.Date)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generate lays the plan's intervals out from its start date for the plan's owner and
+// replaces their pending sessions. Athletes following the plan have their own
+// assignments with their own start dates.
+func (h *ScheduleHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		planRepo := repository.PlansRepository{Queries: queries}
+		assignmentRepo := repository.NewPlanAssignmentsRepository(queries)
+
+		dbPlan, err := planRepo.GetPlanById(r.Context(), planId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		if !dbPlan.StartDate.Valid {
+			api_utils.WriteError(w, http.StatusBadRequest, "Plan has no start date")
+			return nil
+		}
+
+		assignment, err := assignmentRepo.GetByPlanAndUser(r.Context(), planId, dbPlan.UserID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			assignment, err = assignmentRepo.Create(r.Context(), planId, dbPlan.UserID, dbPlan.StartDate, []int32{}, 0)
+		case err == nil && assignment.StartDate.Time != dbPlan.StartDate.Time:
+			assignment, err = assignmentRepo.Update(r.Context(), assignment.ID, dbPlan.StartDate, assignment.TrainingDays, assignment.MinRestDays)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := generateAssignmentSessions(r.Context(), queries, assignment); err != nil {
+			return err
+		}
+
+		sessions, err := listSessions(r.Context(), queries, repository.ScheduledSessionFilter{PlanAssignmentID: assignment.ID}, 0, scheduleSessionLimit)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Generated %d sessions for plan %d", len(sessions), planId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(sessions)
+	})
+}
+
+// List returns a plan's sessions in date order for one athlete, the plan's owner by default
+func (h *ScheduleHandler) List(w http.ResponseWriter, r *http.Request) {
+	planId, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	fp := api_utils.NewFilterParser(r, true)
+	userId := fp.GetIntFilterOrZero("userId")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if userId == 0 {
+			dbPlan, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), planId)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+					return nil
+				}
+				return err
+			}
+			userId = dbPlan.UserID
+		}
+
+		sessions, err := listSessions(r.Context(), queries, repository.ScheduledSessionFilter{PlanID: planId, UserID: userId}, 0, scheduleSessionLimit)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(sessions)
+	})
+}
+
+// Upcoming lists sessions across plans, from today unless another date is given
+func (h *ScheduleHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	fp := api_utils.NewFilterParser(r, true)
+	filter := repository.ScheduledSessionFilter{
+		PlanAssignmentID: fp.GetIntFilterOrZero("assignmentId"),
+		PlanID:           fp.GetIntFilterOrZero("planId"),
+		UserID:           fp.GetIntFilterOrZero("userId"),
+		Status:           fp.GetStringFilter("status"),
+	}
+
+	var err error
+	filter.From = utils.TimeToDate(time.Now())
+	if from := fp.GetStringFilter("from"); from != "" {
+		if filter.From, err = utils.StringToDate(from); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if to := fp.GetStringFilter("to"); to != "" {
+		if filter.To, err = utils.StringToDate(to); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	switch filter.Status {
+	case "", repository.SessionStatusScheduled, repository.SessionStatusCompleted, repository.SessionStatusSkipped:
+	default:
+		api_utils.WriteError(w, http.StatusBadRequest, "Status must be scheduled, completed or skipped")
+		return
+	}
+
+	limit := fp.GetLimit(50)
+	offset := fp.GetOffset(0)
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sessions, err := listSessions(r.Context(), queries, filter, int32(offset), limit)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(sessions)
+	})
+}
+
+// Reschedule moves a session to another date
+func (h *ScheduleHandler) Reschedule(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var args RescheduleSessionApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	date, err := utils.StringToDate(args.Date)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sessionRepo := repository.NewScheduledSessionsRepository(queries)
+
+		session, err := sessionRepo.Reschedule(r.Context(), id, date)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Session not found")
+				return nil
+			}
+			return err
+		}
+
+		apiSession, err := getApiSession(r.Context(), queries, session)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Moved session %d to %s", id, apiSession.Date)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiSession)
+	})
+}
+
+// Skip marks a pending session as skipped. It stays on its date and is not reflowed.
+func (h *ScheduleHandler) Skip(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sessionRepo := repository.NewScheduledSessionsRepository(queries)
+
+		session, err := sessionRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Session not found")
+				return nil
+			}
+			return err
+		}
+		if session.Status != repository.SessionStatusScheduled {
+			api_utils.WriteError(w, http.StatusConflict, "Only scheduled sessions can be skipped")
+			return nil
+		}
+
+		session, err = sessionRepo.SetStatus(r.Context(), id, repository.SessionStatusSkipped)
+		if err != nil {
+			return err
+		}
+		apiSession, err := getApiSession(r.Context(), queries, session)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Skipped session %d", id)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiSession)
+	})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/records"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CompleteSessionApiArgs are the sets performed in a session. The date they were
+// performed on defaults to today. RPE rates the whole session and defaults to the
+// average RPE of its sets; with the duration it gives the session's training load.
+type CompleteSessionApiArgs struct {
+	Date     string              `json:"date"`
+	RPE      *int32              `json:"rpe"`
+	Duration *string             `json:"duration"`
+	Sets     []SessionSetApiArgs `json:"sets"`
+}
+
+type SessionSetApiArgs struct {
+	ExerciseVariationId int64                        `json:"exerciseVariationId"`
+	Reps                *int32                       `json:"reps"`
+	RPE                 *int32                       `json:"rpe"`
+	Parameters          []SessionSetParameterApiArgs `json:"parameters"`
+}
+
+type SessionSetParameterApiArgs struct {
+	ParameterTypeId int64   `json:"parameterTypeId"`
+	Value           float64 `json:"value"`
+}
+
+// checkSessionSet checks a logged set against the parameters of its variation
+func checkSessionSet(number int, set SessionSetApiArgs, params []db.ExerciseVariations_ListParamsRow) error {
+	if set.Reps != nil && *set.Reps < 0 {
+		return fmt.Errorf("Set %d cannot have negative reps", number)
+	}
+	if set.RPE != nil && (*set.RPE < 1 || *set.RPE > 10) {
+		return fmt.Errorf("Set %d must have an RPE between 1 and 10", number)
+	}
+
+	seen := make(map[int64]bool, len(set.Parameters))
+	for _, parameter := range set.Parameters {
+		index := slices.IndexFunc(params, func(param db.ExerciseVariations_ListParamsRow) bool {
+			return param.ParameterTypeID == parameter.ParameterTypeId
+		})
+		if index < 0 {
+			return fmt.Errorf("Set %d uses parameter type %d, which is not a parameter of exercise variation %d", number, parameter.ParameterTypeId, set.ExerciseVariationId)
+		}
+		if seen[parameter.ParameterTypeId] {
+			return fmt.Errorf("Set %d gives %s more than once", number, params[index].Name)
+		}
+		seen[parameter.ParameterTypeId] = true
+	}
+	return nil
+}
+
+// Helper function to rate a session by the average RPE of its sets, nil when none of them
+// were rated
+func averageSetRPE(sets []SessionSetApiArgs) *int32 {
+	var total, count int32
+	for _, set := range sets {
+		if set.RPE != nil {
+			total += *set.RPE
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	average := int32(math.Round(float64(total) / float64(count)))
+	return &average
+}
+
+// Complete logs the sets performed in a scheduled session and marks it completed. The
+// personal records the sets set are stored and returned.
+func (h *ScheduleHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	var args CompleteSessionApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	for i, set := range args.Sets {
+		if set.ExerciseVariationId == 0 {
+			api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Set %d is missing required field: exerciseVariationId", i+1))
+			return
+		}
+	}
+	date, err := dateOrToday(args.Date)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if args.RPE != nil && (*args.RPE < 1 || *args.RPE > 10) {
+		api_utils.WriteError(w, http.StatusBadRequest, "Session RPE must be between 1 and 10")
+		return
+	}
+	rpe := utils.If(args.RPE != nil, args.RPE, averageSetRPE(args.Sets))
+	var duration pgtype.Interval
+	if args.Duration != nil {
+		if duration, err = utils.StringToInterval(*args.Duration); err != nil || utils.IntervalToDuration(duration) <= 0 {
+			api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid duration %q", *args.Duration))
+			return
+		}
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		sessionRepo := repository.NewScheduledSessionsRepository(queries)
+		setRepo := repository.NewSessionSetsRepository(queries)
+
+		session, err := sessionRepo.GetById(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Session not found")
+				return nil
+			}
+			return err
+		}
+		if session.Status != repository.SessionStatusScheduled {
+			api_utils.WriteError(w, http.StatusConflict, "Only scheduled sessions can be completed")
+			return nil
+		}
+
+		// Parameters are looked up once per variation; their data types decide which
+		// records a set can set
+		variationParams := make(map[int64][]db.ExerciseVariations_ListParamsRow)
+		for i, set := range args.Sets {
+			params, ok := variationParams[set.ExerciseVariationId]
+			if !ok {
+				if params, err = repository.NewExerciseVariationsRepository(queries).ListParams(r.Context(), set.ExerciseVariationId); err != nil {
+					return err
+				}
+				variationParams[set.ExerciseVariationId] = params
+			}
+			if err := checkSessionSet(i+1, set, params); err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+				return nil
+			}
+		}
+
+		session, err = sessionRepo.SetStatus(r.Context(), id, repository.SessionStatusCompleted)
+		if err != nil {
+			return err
+		}
+		apiSession, err := getApiSession(r.Context(), queries, session)
+		if err != nil {
+			return err
+		}
+
+		setNumbers := make(map[int64]int32)
+		setIds := make([]int64, len(args.Sets))
+		logged := make([]records.Set, len(args.Sets))
+		for i, set := range args.Sets {
+			data := repository.SessionSetData{
+				ExerciseVariationId: set.ExerciseVariationId,
+				Reps:                set.Reps,
+				RPE:                 set.RPE,
+			}
+			logged[i] = records.Set{VariationID: set.ExerciseVariationId, Reps: set.Reps}
+			for _, parameter := range set.Parameters {
+				data.Parameters = append(data.Parameters, repository.SessionSetParameterData{
+					ParameterTypeId: parameter.ParameterTypeId,
+					Value:           parameter.Value,
+				})
+				for _, param := range variationParams[set.ExerciseVariationId] {
+					if param.ParameterTypeID == parameter.ParameterTypeId {
+						logged[i].Parameters = append(logged[i].Parameters, records.Parameter{
+							ParameterTypeID: param.ParameterTypeID,
+							DataType:        param.DataType,
+							Value:           parameter.Value,
+						})
+					}
+				}
+			}
+
+			setNumbers[set.ExerciseVariationId]++
+			dbSet, err := setRepo.Create(r.Context(), id, setNumbers[set.ExerciseVariationId], data)
+			if err != nil {
+				return err
+			}
+			setIds[i] = dbSet.ID
+		}
+
+		completion, err := repository.NewTrainingLoadRepository(queries).CreateCompletion(r.Context(), id, date, rpe, duration)
+		if err != nil {
+			return err
+		}
+
+		newRecords, err := detectRecords(r.Context(), queries, apiSession.UserID, logged, setIds, date)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Completed session %d with %d sets and %d personal records", id, len(args.Sets), len(newRecords))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(types.SessionCompletion{
+			Session: apiSession,
+			Sets:    len(args.Sets),
+			Load:    completionLoad(*completion),
+			Records: newRecords,
+		})
+	})
+}
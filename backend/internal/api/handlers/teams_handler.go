@@ -0,0 +1,760 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	"backend/internal/access"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Teams are managed on behalf of the acting user, whose role in the team decides what
+// they may do: owners manage the team and its roster, coaches invite athletes and assign
+// them plans, and athletes may only leave.
+type TeamsHandler struct {
+	Db *db.Database
+}
+
+type TeamApiArgs struct {
+	Name string `json:"name"`
+}
+
+type TeamMemberApiArgs struct {
+	Role string `json:"role"`
+}
+
+type TeamInvitationApiArgs struct {
+	UserID int64  `json:"userId"`
+	Role   string `json:"role"`
+}
+
+// AssignTeamPlanApiArgs assigns a plan to athletes of the team. With a start date each
+// copy is also scheduled for its athlete.
+type AssignTeamPlanApiArgs struct {
+	PlanID       int64   `json:"planId"`
+	AthleteIDs   []int64 `json:"athleteIds"`
+	StartDate    *string `json:"startDate"`
+	TrainingDays []int32 `json:"trainingDays"`
+	MinRestDays  int32   `json:"minRestDays"`
+}
+
+// Helper function to convert a DB team to an API team
+func dbTeamToApiTeam(team db.Team, role string) types.Team {
+	return types.Team{
+		ID:        team.ID,
+		Name:      team.Name,
+		Role:      role,
+		CreatedAt: team.CreatedAt.Time.String(),
+		UpdatedAt: team.UpdatedAt.Time.String(),
+	}
+}
+
+// Helper function to convert DB team members to API team members
+func dbTeamMembersToApiTeamMembers(rows []db.TeamMembers_ListRow) []types.TeamMember {
+	members := make([]types.TeamMember, len(rows))
+	for i, row := range rows {
+		members[i] = types.TeamMember{
+			UserID:    row.UserID,
+			Role:      row.Role,
+			Email:     row.Email,
+			FirstName: row.FirstName,
+			LastName:  row.LastName,
+			JoinedAt:  row.CreatedAt.Time.String(),
+		}
+	}
+	return members
+}
+
+// Helper function to convert a DB invitation to an API invitation
+func dbTeamInvitationToApiTeamInvitation(invitation db.TeamInvitation) types.TeamInvitation {
+	return types.TeamInvitation{
+		ID:        invitation.ID,
+		TeamID:    invitation.TeamID,
+		UserID:    invitation.UserID,
+		Role:      invitation.Role,
+		InvitedBy: utils.If(invitation.InvitedBy.Valid, &invitation.InvitedBy.Int64, nil),
+		Status:    invitation.Status,
+		CreatedAt: invitation.CreatedAt.Time.String(),
+		UpdatedAt: invitation.UpdatedAt.Time.String(),
+	}
+}
+
+func validateTeamRole(role string) error {
+	if !access.ValidRole(role) {
+		return errors.New("Role must be owner, coach or athlete")
+	}
+	return nil
+}
+
+// memberRole returns the user's role in the team. When the team does not exist or the
+// user is not a member it writes the error and returns an empty role.
+func memberRole(ctx context.Context, w http.ResponseWriter, teamRepo *repository.TeamsRepository, teamId int64, userId int64) (string, error) {
+	if _, err := teamRepo.GetById(ctx, teamId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			api_utils.WriteError(w, http.StatusNotFound, "Team not found")
+			return "", nil
+		}
+		return "", err
+	}
+	member, err := teamRepo.GetMember(ctx, teamId, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			api_utils.WriteError(w, http.StatusForbidden, "Not a member of this team")
+			return "", nil
+		}
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// writeTeam responds with the team, its roster and the user's role
+func writeTeam(ctx context.Context, w http.ResponseWriter, teamRepo *repository.TeamsRepository, team *db.Team, role string, status int) error {
+	rows, err := teamRepo.ListMembers(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+	result := dbTeamToApiTeam(*team, role)
+	result.Members = dbTeamMembersToApiTeamMembers(rows)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(result)
+}
+
+// ListUserTeams returns the teams a user belongs to with their role in each
+func (h *TeamsHandler) ListUserTeams(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		rows, err := repository.NewTeamsRepository(queries).ListByUserId(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		teams := make([]types.Team, len(rows))
+		for i, row := range rows {
+			teams[i] = dbTeamToApiTeam(db.Team{ID: row.ID, Name: row.Name, CreatedAt: row.CreatedAt, UpdatedAt: row.UpdatedAt}, row.Role)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(teams)
+	})
+}
+
+// Create creates a team owned by the acting user
+func (h *TeamsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	var args TeamApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	args.Name = strings.TrimSpace(args.Name)
+	if args.Name == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		team, err := teamRepo.Create(r.Context(), args.Name, actor)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Created team %d owned by user %d", team.ID, actor)
+		return writeTeam(r.Context(), w, teamRepo, team, access.RoleOwner, http.StatusCreated)
+	})
+}
+
+// Get returns a team with its roster to its members
+func (h *TeamsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		team, err := teamRepo.GetById(r.Context(), teamId)
+		if err != nil {
+			return err
+		}
+		return writeTeam(r.Context(), w, teamRepo, team, role, http.StatusOK)
+	})
+}
+
+// Update renames a team. Only owners may.
+func (h *TeamsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	var args TeamApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	args.Name = strings.TrimSpace(args.Name)
+	if args.Name == "" {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: name")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if role != access.RoleOwner {
+			api_utils.WriteError(w, http.StatusForbidden, "Only owners can change the team")
+			return nil
+		}
+
+		team, err := teamRepo.Update(r.Context(), teamId, args.Name)
+		if err != nil {
+			return err
+		}
+		log.Printf("Renamed team %d", teamId)
+		return writeTeam(r.Context(), w, teamRepo, team, role, http.StatusOK)
+	})
+}
+
+// Delete deletes a team with its roster and invitations. Plans assigned through the team
+// stay with their athletes.
+func (h *TeamsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if role != access.RoleOwner {
+			api_utils.WriteError(w, http.StatusForbidden, "Only owners can delete the team")
+			return nil
+		}
+
+		if _, err := teamRepo.Delete(r.Context(), teamId); err != nil {
+			return err
+		}
+		log.Printf("Deleted team %d", teamId)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// UpdateMember changes a member's role. Only owners may, and the team keeps at least one
+// owner.
+func (h *TeamsHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	var args TeamMemberApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := validateTeamRole(args.Role); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if role != access.RoleOwner {
+			api_utils.WriteError(w, http.StatusForbidden, "Only owners can change roles")
+			return nil
+		}
+
+		memberships, err := teamRepo.Memberships(r.Context(), teamId)
+		if err != nil {
+			return err
+		}
+		index := slices.IndexFunc(memberships, func(membership access.Membership) bool {
+			return membership.UserID == userId
+		})
+		if index < 0 {
+			api_utils.WriteError(w, http.StatusNotFound, "Team member not found")
+			return nil
+		}
+		if memberships[index].Role == access.RoleOwner && args.Role != access.RoleOwner && access.OwnerCount(memberships) == 1 {
+			api_utils.WriteError(w, http.StatusConflict, "A team needs at least one owner")
+			return nil
+		}
+
+		if _, err := teamRepo.UpdateMemberRole(r.Context(), teamId, userId, args.Role); err != nil {
+			return err
+		}
+		team, err := teamRepo.GetById(r.Context(), teamId)
+		if err != nil {
+			return err
+		}
+		log.Printf("Changed the role of user %d in team %d to %s", userId, teamId, args.Role)
+		return writeTeam(r.Context(), w, teamRepo, team, role, http.StatusOK)
+	})
+}
+
+// RemoveMember removes a member from the team. Members may leave, owners remove anyone
+// and coaches remove athletes; the last owner can not leave.
+func (h *TeamsHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+
+		memberships, err := teamRepo.Memberships(r.Context(), teamId)
+		if err != nil {
+			return err
+		}
+		index := slices.IndexFunc(memberships, func(membership access.Membership) bool {
+			return membership.UserID == userId
+		})
+		if index < 0 {
+			api_utils.WriteError(w, http.StatusNotFound, "Team member not found")
+			return nil
+		}
+		target := memberships[index].Role
+		if userId != actor && role != access.RoleOwner && !(role == access.RoleCoach && target == access.RoleAthlete) {
+			api_utils.WriteError(w, http.StatusForbidden, "Not allowed to remove this member")
+			return nil
+		}
+		if target == access.RoleOwner && access.OwnerCount(memberships) == 1 {
+			api_utils.WriteError(w, http.StatusConflict, "A team needs at least one owner")
+			return nil
+		}
+
+		if _, err := teamRepo.RemoveMember(r.Context(), teamId, userId); err != nil {
+			return err
+		}
+		log.Printf("Removed user %d from team %d", userId, teamId)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// ListInvitations returns the team's invitations to its owners and coaches, optionally
+// filtered by status
+func (h *TeamsHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	status := api_utils.NewFilterParser(r, true).GetStringFilter("status")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if !access.Manages(role) {
+			api_utils.WriteError(w, http.StatusForbidden, "Only owners and coaches can see invitations")
+			return nil
+		}
+
+		dbInvitations, err := teamRepo.ListInvitations(r.Context(), teamId, status)
+		if err != nil {
+			return err
+		}
+		invitations := make([]types.TeamInvitation, len(dbInvitations))
+		for i, invitation := range dbInvitations {
+			invitations[i] = dbTeamInvitationToApiTeamInvitation(invitation)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(invitations)
+	})
+}
+
+// Invite invites a user to the team. Owners invite to any role and coaches invite
+// athletes.
+func (h *TeamsHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	var args TeamInvitationApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.UserID == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+	if err := validateTeamRole(args.Role); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if !access.CanInvite(role, args.Role) {
+			api_utils.WriteError(w, http.StatusForbidden, fmt.Sprintf("Not allowed to invite a %s", args.Role))
+			return nil
+		}
+
+		if _, err := teamRepo.GetMember(r.Context(), teamId, args.UserID); err == nil {
+			api_utils.WriteError(w, http.StatusConflict, "User is already a member of this team")
+			return nil
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		pending, err := teamRepo.ListUserInvitations(r.Context(), args.UserID, repository.InvitationPending)
+		if err != nil {
+			return err
+		}
+		if slices.ContainsFunc(pending, func(invitation db.TeamInvitation) bool { return invitation.TeamID == teamId }) {
+			api_utils.WriteError(w, http.StatusConflict, "User already has a pending invitation to this team")
+			return nil
+		}
+
+		invitation, err := teamRepo.CreateInvitation(r.Context(), teamId, args.UserID, args.Role, actor)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("Invited user %d to team %d as %s", args.UserID, teamId, args.Role)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbTeamInvitationToApiTeamInvitation(*invitation))
+	})
+}
+
+// ListUserInvitations returns the invitations sent to a user, optionally filtered by
+// status
+func (h *TeamsHandler) ListUserInvitations(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+	status := api_utils.NewFilterParser(r, true).GetStringFilter("status")
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		dbInvitations, err := repository.NewTeamsRepository(queries).ListUserInvitations(r.Context(), userId, status)
+		if err != nil {
+			return err
+		}
+		invitations := make([]types.TeamInvitation, len(dbInvitations))
+		for i, invitation := range dbInvitations {
+			invitations[i] = dbTeamInvitationToApiTeamInvitation(invitation)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(invitations)
+	})
+}
+
+// Accept joins the invited user to the team in the invitation's role
+func (h *TeamsHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.answerInvitation(w, r, repository.InvitationAccepted)
+}
+
+// Decline turns an invitation down
+func (h *TeamsHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	h.answerInvitation(w, r, repository.InvitationDeclined)
+}
+
+func (h *TeamsHandler) answerInvitation(w http.ResponseWriter, r *http.Request, status string) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		invitation, err := teamRepo.GetInvitation(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Invitation not found")
+				return nil
+			}
+			return err
+		}
+		if invitation.UserID != actor {
+			api_utils.WriteError(w, http.StatusForbidden, "Only the invited user can answer an invitation")
+			return nil
+		}
+		if invitation.Status != repository.InvitationPending {
+			api_utils.WriteError(w, http.StatusConflict, "Invitation is no longer pending")
+			return nil
+		}
+
+		if status == repository.InvitationAccepted {
+			if _, err := teamRepo.AddMember(r.Context(), invitation.TeamID, actor, invitation.Role); err != nil {
+				return err
+			}
+		}
+		invitation, err = teamRepo.SetInvitationStatus(r.Context(), id, status)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("User %d %s the invitation to team %d", actor, status, invitation.TeamID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbTeamInvitationToApiTeamInvitation(*invitation))
+	})
+}
+
+// AssignPlan gives each athlete their own copy of a plan, linked to it, and schedules
+// the copies when a start date is given. The plan must be one the coach may read.
+func (h *TeamsHandler) AssignPlan(w http.ResponseWriter, r *http.Request) {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+	teamId, err := api_utils.ParseBigInt(chi.URLParam(r, "teamId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	var args AssignTeamPlanApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.PlanID == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: planId")
+		return
+	}
+	if len(args.AthleteIDs) == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: athleteIds")
+		return
+	}
+	slices.Sort(args.AthleteIDs)
+	args.AthleteIDs = slices.Compact(args.AthleteIDs)
+
+	var startDate pgtype.Date
+	if args.StartDate != nil {
+		if startDate, err = utils.StringToDate(*args.StartDate); err != nil {
+			api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	trainingDays, err := parseWeekdays(args.TrainingDays, "Training days")
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateMinRestDays(args.MinRestDays); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		teamRepo := repository.NewTeamsRepository(queries)
+		role, err := memberRole(r.Context(), w, teamRepo, teamId, actor)
+		if err != nil || role == "" {
+			return err
+		}
+		if !access.Manages(role) {
+			api_utils.WriteError(w, http.StatusForbidden, "Only owners and coaches can assign plans")
+			return nil
+		}
+
+		source, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), args.PlanID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		accessRepo := repository.NewAccessRepository(queries)
+		resource, err := accessRepo.Plan(r.Context(), source.ID)
+		if err != nil {
+			return err
+		}
+		if allowed, err := accessRepo.Allowed(r.Context(), actor, resource, access.Read); err != nil {
+			return err
+		} else if !allowed {
+			api_utils.WriteError(w, http.StatusForbidden, "Not allowed to view this resource")
+			return nil
+		}
+
+		memberships, err := teamRepo.Memberships(r.Context(), teamId)
+		if err != nil {
+			return err
+		}
+		for _, athleteId := range args.AthleteIDs {
+			if !slices.Contains(memberships, access.Membership{TeamID: teamId, UserID: athleteId, Role: access.RoleAthlete}) {
+				api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("User %d is not an athlete of this team", athleteId))
+				return nil
+			}
+		}
+
+		assignmentRepo := repository.NewPlanAssignmentsRepository(queries)
+		results := make([]types.TeamPlanAssignment, len(args.AthleteIDs))
+		for i, athleteId := range args.AthleteIDs {
+			planCopy, err := copyPlan(r.Context(), queries, source, athleteId)
+			if err != nil {
+				return err
+			}
+			results[i] = types.TeamPlanAssignment{AthleteID: athleteId, Plan: dbPlanToApiPlan(*planCopy)}
+
+			if !startDate.Valid {
+				continue
+			}
+			assignment, err := assignmentRepo.Create(r.Context(), planCopy.ID, athleteId, startDate, trainingDays, args.MinRestDays)
+			if err != nil {
+				return err
+			}
+			if err := generateAssignmentSessions(r.Context(), queries, assignment); err != nil {
+				return err
+			}
+			apiAssignment := dbPlanAssignmentToApiPlanAssignment(assignment)
+			results[i].Assignment = &apiAssignment
+		}
+
+		log.Printf("Assigned plan %d to %d athletes of team %d", source.ID, len(results), teamId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(results)
+	})
+}
+
+// Copies returns the plans copied from a plan, e.g. the copies a coach assigned to
+// athletes. An acting user only sees the copies they may read.
+func (h *PlanHandler) Copies(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid plan ID")
+		return
+	}
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		planRepo := &repository.PlansRepository{Queries: queries}
+		if _, err := planRepo.GetPlanById(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Plan not found")
+				return nil
+			}
+			return err
+		}
+		dbCopies, err := planRepo.ListCopies(r.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		accessRepo := repository.NewAccessRepository(queries)
+		visible := dbCopies[:0]
+		for _, dbCopy := range dbCopies {
+			allowed, err := accessRepo.Allowed(r.Context(), actor, access.Resource{OwnerID: &dbCopy.UserID, Public: dbCopy.IsPublic}, access.Read)
+			if err != nil {
+				return err
+			}
+			if allowed {
+				visible = append(visible, dbCopy)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlansToApiPlans(visible))
+	})
+}
@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// TemplateHandler is the gallery of the public templates of all users. Users rate the
+// templates and fork them into their own account.
+type TemplateHandler struct {
+	Db *db.Database
+}
+
+type TemplateRatingApiArgs struct {
+	UserId int64 `json:"userId"`
+	Stars  int32 `json:"stars"`
+}
+
+type TemplateForkApiArgs struct {
+	UserId int64 `json:"userId"`
+}
+
+// Helper function to convert gallery rows to API templates with a preview of their intervals
+func dbTemplatesToApiTemplates(rows []db.Templates_ListRow, intervals []db.Templates_ListIntervalsRow) []types.Template {
+	previews := make(map[int64][]types.TemplateInterval)
+	for _, interval := range intervals {
+		duration, err := utils.IntervalToString(interval.Duration)
+		if err != nil {
+			duration = ""
+		}
+		previews[interval.PlanID] = append(previews[interval.PlanID], types.TemplateInterval{
+			ID:                interval.ID,
+			Name:              interval.Name.String,
+			Duration:          duration,
+			Order:             interval.Order,
+			IsDeload:          interval.IsDeload,
+			Groups:            utils.If(interval.GroupNames != nil, interval.GroupNames, []string{}),
+			PrescriptionCount: interval.PrescriptionCount,
+		})
+	}
+
+	templates := make([]types.Template, len(rows))
+	for i, row := range rows {
+		templates[i] = types.Template{
+			ID:            row.ID,
+			Name:          row.Name,
+			Description:   row.Description,
+			UserID:        row.UserID,
+			AuthorName:    strings.TrimSpace(row.AuthorFirstName + " " + row.AuthorLastName),
+			ForkCount:     row.ForkCount,
+			RatingCount:   row.RatingCount,
+			AverageRating: utils.If(row.RatingCount > 0, &row.AverageRating, nil),
+			Intervals:     utils.If(previews[row.ID] != nil, previews[row.ID], []types.TemplateInterval{}),
+			CreatedAt:     row.CreatedAt.Time.String(),
+			UpdatedAt:     row.UpdatedAt.Time.String(),
+		}
+	}
+	return templates
+}
+
+// Helper function to convert a DB rating to an API rating
+func dbPlanRatingToApiPlanRating(rating *db.PlanRating) types.PlanRating {
+	return types.PlanRating{
+		PlanID:    rating.PlanID,
+		UserID:    rating.UserID,
+		Stars:     rating.Stars,
+		CreatedAt: rating.CreatedAt.Time.String(),
+		UpdatedAt: rating.UpdatedAt.Time.String(),
+	}
+}
+
+// List browses the public templates. search matches their name or description, category
+// the categories of the exercises they prescribe, and sort is recent (default) or popular.
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+
+	sort := strings.ToLower(filterParser.GetStringFilterWithDefault("sort", repository.TemplateSortRecent))
+	if sort != repository.TemplateSortRecent && sort != repository.TemplateSortPopular {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid sort, expected popular or recent")
+		return
+	}
+	params := repository.TemplateListParams{
+		Search:   strings.TrimSpace(filterParser.GetStringFilter("search")),
+		Category: strings.TrimSpace(filterParser.GetStringFilter("category")),
+		Sort:     sort,
+		Limit:    filterParser.GetLimit(20),
+		Offset:   int32(filterParser.GetOffset(0)),
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		templateRepo := repository.NewTemplatesRepository(queries)
+		rows, err := templateRepo.List(r.Context(), params)
+		if err != nil {
+			return err
+		}
+
+		planIds := make([]int64, len(rows))
+		for i, row := range rows {
+			planIds[i] = row.ID
+		}
+		intervals, err := templateRepo.ListIntervals(r.Context(), planIds)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbTemplatesToApiTemplates(rows, intervals))
+	})
+}
+
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		templateRepo := repository.NewTemplatesRepository(queries)
+		template, err := templateRepo.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Template not found")
+				return nil
+			}
+			return err
+		}
+		intervals, err := templateRepo.ListIntervals(r.Context(), []int64{id})
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbTemplatesToApiTemplates([]db.Templates_ListRow{*template}, intervals)[0])
+	})
+}
+
+// Rate sets the user's one to five star rating of a template. Authors do not rate their
+// own templates.
+func (h *TemplateHandler) Rate(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var args TemplateRatingApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.UserId <= 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid or missing userId")
+		return
+	}
+	if args.Stars < 1 || args.Stars > 5 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Stars must be between 1 and 5")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		templateRepo := repository.NewTemplatesRepository(queries)
+		template, err := templateRepo.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Template not found")
+				return nil
+			}
+			return err
+		}
+		if template.UserID == args.UserId {
+			api_utils.WriteError(w, http.StatusBadRequest, "The author of a template can not rate it")
+			return nil
+		}
+
+		rating, err := templateRepo.Rate(r.Context(), id, args.UserId, args.Stars)
+		if err != nil {
+			return err
+		}
+		log.Printf("User %d rated template %d with %d stars", args.UserId, id, args.Stars)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(dbPlanRatingToApiPlanRating(rating))
+	})
+}
+
+func (h *TemplateHandler) RemoveRating(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+	userId := api_utils.NewFilterParser(r, true).GetIntFilter("userId")
+	if userId == nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid or missing userId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := repository.NewTemplatesRepository(queries).RemoveRating(r.Context(), id, *userId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Rating not found")
+				return nil
+			}
+			return err
+		}
+		log.Printf("User %d removed their rating of template %d", *userId, id)
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+}
+
+// Fork copies a public template into the user's account as a private plan whose
+// sourcePlanId links back to the template
+func (h *TemplateHandler) Fork(w http.ResponseWriter, r *http.Request) {
+	id, err := api_utils.ParseBigInt(chi.URLParam(r, "id"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid template ID")
+		return
+	}
+
+	var args TemplateForkApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if args.UserId <= 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid or missing userId")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := repository.NewTemplatesRepository(queries).Get(r.Context(), id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				api_utils.WriteError(w, http.StatusNotFound, "Template not found")
+				return nil
+			}
+			return err
+		}
+		source, err := (&repository.PlansRepository{Queries: queries}).GetPlanById(r.Context(), id)
+		if err != nil {
+			return err
+		}
+
+		fork, err := copyPlan(r.Context(), queries, source, args.UserId)
+		if err != nil {
+			return err
+		}
+		log.Printf("User %d forked template %d into plan %d", args.UserId, id, fork.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		return json.NewEncoder(w).Encode(dbPlanToApiPlan(*fork))
+	})
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/readiness"
+	"backend/internal/trainingload"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// trainingLoadMaxDays caps how many days a training load series covers
+const trainingLoadMaxDays = 366
+
+type TrainingLoadHandler struct {
+	Db *db.Database
+}
+
+// TrainingLoadThresholdsApiArgs sets an athlete's training load thresholds
+type TrainingLoadThresholdsApiArgs struct {
+	ACWRHigh float64 `json:"acwrHigh"`
+	ACWRLow  float64 `json:"acwrLow"`
+	Monotony float64 `json:"monotony"`
+	Strain   float64 `json:"strain"`
+}
+
+// Helper function to look up the user's thresholds, the defaults when they have not set
+// their own
+func userThresholds(ctx context.Context, queries *db.Queries, userId int64) (trainingload.Thresholds, bool, error) {
+	dbThresholds, err := repository.NewTrainingLoadRepository(queries).GetThresholds(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return trainingload.DefaultThresholds, false, nil
+		}
+		return trainingload.Thresholds{}, false, err
+	}
+	return trainingload.Thresholds{
+		ACWRHigh: dbThresholds.AcwrHigh,
+		ACWRLow:  dbThresholds.AcwrLow,
+		Monotony: dbThresholds.Monotony,
+		Strain:   dbThresholds.Strain,
+	}, true, nil
+}
+
+func apiThresholds(thresholds trainingload.Thresholds, custom bool) types.TrainingLoadThresholds {
+	return types.TrainingLoadThresholds{
+		ACWRHigh: thresholds.ACWRHigh,
+		ACWRLow:  thresholds.ACWRLow,
+		Monotony: thresholds.Monotony,
+		Strain:   thresholds.Strain,
+		Custom:   custom,
+	}
+}
+
+// Helper function to give a completed session's load, nil without an RPE or duration
+func completionLoad(completion db.SessionCompletion) *float64 {
+	if !completion.Rpe.Valid || !completion.Duration.Valid {
+		return nil
+	}
+	load := trainingload.SessionLoad(completion.Rpe.Int32, utils.IntervalToDuration(completion.Duration))
+	return &load
+}
+
+// TrainingLoad returns an athlete's daily training load series between from and to,
+// flagging days that cross the athlete's thresholds or whose readiness is low. to
+// defaults to today and from to four weeks before it.
+func (h *TrainingLoadHandler) TrainingLoad(w http.ResponseWriter, r *http.Request) {
+	filterParser := api_utils.NewFilterParser(r, true)
+
+	userId := filterParser.GetIntFilterOrZero("userId")
+	if userId == 0 {
+		api_utils.WriteError(w, http.StatusBadRequest, "Missing required field: userId")
+		return
+	}
+	from, to, err := readDateRange(filterParser, trainingLoadMaxDays)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		thresholds, custom, err := userThresholds(r.Context(), queries, userId)
+		if err != nil {
+			return err
+		}
+
+		// The first days' chronic load reaches back four weeks
+		history := utils.TimeToDate(from.Time.AddDate(0, 0, -(trainingload.ChronicDays - 1)))
+		completions, err := repository.NewTrainingLoadRepository(queries).ListCompletions(r.Context(), userId, history, to)
+		if err != nil {
+			return err
+		}
+
+		result := types.TrainingLoad{
+			UserID:     userId,
+			From:       utils.DateToString(from),
+			To:         utils.DateToString(to),
+			Thresholds: apiThresholds(thresholds, custom),
+			Days:       []types.TrainingLoadDay{},
+		}
+		var sessions []trainingload.Session
+		for _, completion := range completions {
+			if !completion.Rpe.Valid || !completion.Duration.Valid {
+				if !completion.CompletedOn.Time.Before(from.Time) {
+					result.Unrated++
+				}
+				continue
+			}
+			sessions = append(sessions, trainingload.Session{
+				Date:     completion.CompletedOn.Time,
+				RPE:      completion.Rpe.Int32,
+				Duration: utils.IntervalToDuration(completion.Duration),
+			})
+		}
+
+		// Days are scored by their readiness check-ins and flagged when readiness is low
+		checkins, _, err := scoredCheckins(r.Context(), queries, userId, from, to)
+		if err != nil {
+			return err
+		}
+		scores := make(map[string]*float64, len(checkins))
+		for _, checkin := range checkins {
+			scores[checkin.CheckedOn] = checkin.Score
+		}
+
+		for _, day := range trainingload.Series(sessions, from.Time, to.Time, thresholds) {
+			date := utils.DateToString(utils.TimeToDate(day.Date))
+			flags := utils.If(day.Flags == nil, []string{}, day.Flags)
+			if score := scores[date]; score != nil && *score < readiness.DefaultThresholds.Score {
+				flags = append(flags, readiness.FlagLow)
+			}
+			result.Days = append(result.Days, types.TrainingLoadDay{
+				Date:      date,
+				Load:      day.Load,
+				Acute:     day.Acute,
+				Chronic:   day.Chronic,
+				ACWR:      day.ACWR,
+				Monotony:  day.Monotony,
+				Strain:    day.Strain,
+				Readiness: scores[date],
+				Flags:     flags,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	})
+}
+
+// GetThresholds returns the user's training load thresholds
+func (h *TrainingLoadHandler) GetThresholds(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		thresholds, custom, err := userThresholds(r.Context(), queries, userId)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiThresholds(thresholds, custom))
+	})
+}
+
+// SetThresholds replaces the user's training load thresholds
+func (h *TrainingLoadHandler) SetThresholds(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var args TrainingLoadThresholdsApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	thresholds := trainingload.Thresholds{
+		ACWRHigh: args.ACWRHigh,
+		ACWRLow:  args.ACWRLow,
+		Monotony: args.Monotony,
+		Strain:   args.Strain,
+	}
+	if err := thresholds.Validate(); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("Invalid thresholds: %s", err))
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		if _, err := repository.NewTrainingLoadRepository(queries).SetThresholds(r.Context(), db.TrainingLoadThresholds_UpsertParams{
+			UserID:   userId,
+			AcwrHigh: thresholds.ACWRHigh,
+			AcwrLow:  thresholds.ACWRLow,
+			Monotony: thresholds.Monotony,
+			Strain:   thresholds.Strain,
+		}); err != nil {
+			return err
+		}
+
+		log.Printf("Set training load thresholds for user %d", userId)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(apiThresholds(thresholds, true))
+	})
+}
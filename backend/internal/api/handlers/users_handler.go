@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type UsersHandler struct {
+	Db *db.Database
+}
+
+type SetUserEquipmentApiArgs struct {
+	Equipment []string `json:"equipment"`
+}
+
+// GetEquipment returns the athlete's declared equipment inventory
+func (h *UsersHandler) GetEquipment(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		equipmentRepo := repository.NewUserEquipmentRepository(queries)
+
+		equipment, err := equipmentRepo.ListByUserId(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(equipment)
+	})
+}
+
+// SetEquipment replaces the athlete's declared equipment inventory
+func (h *UsersHandler) SetEquipment(w http.ResponseWriter, r *http.Request) {
+	userId, err := api_utils.ParseBigInt(chi.URLParam(r, "userId"))
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var args SetUserEquipmentApiArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	equipment, err := utils.NormalizeEquipment(args.Equipment)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	api_utils.WithTransaction(r.Context(), h.Db, w, func(queries *db.Queries) error {
+		equipmentRepo := repository.NewUserEquipmentRepository(queries)
+
+		if err := equipmentRepo.SetEquipment(r.Context(), userId, equipment); err != nil {
+			return err
+		}
+
+		saved, err := equipmentRepo.ListByUserId(r.Context(), userId)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(saved)
+	})
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	"backend/internal/access"
+	api_utils "backend/internal/api/utils"
+	"backend/internal/auth"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Authenticate reads the user a request is made on behalf of from its bearer token and
+// rejects requests without a valid one
+func Authenticate(tokens *auth.Tokens) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				api_utils.WriteError(w, http.StatusUnauthorized, "Missing bearer token")
+				return
+			}
+			userId, err := tokens.Verify(token)
+			if err != nil {
+				api_utils.WriteError(w, http.StatusUnauthorized, "Invalid or expired bearer token")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(api_utils.WithActingUser(r.Context(), userId)))
+		})
+	}
+}
+
+// Owner looks up the resource an ID names
+type Owner func(repo *repository.AccessRepository, ctx context.Context, id int64) (access.Resource, error)
+
+var (
+	UserOwner              Owner = (*repository.AccessRepository).User
+	PlanOwner              Owner = (*repository.AccessRepository).Plan
+	IntervalOwner          Owner = (*repository.AccessRepository).Interval
+	PrescriptionOwner      Owner = (*repository.AccessRepository).Prescription
+	PrescriptionBlockOwner Owner = (*repository.AccessRepository).PrescriptionBlock
+	GroupOwner             Owner = (*repository.AccessRepository).Group
+	CategoryOwner          Owner = (*repository.AccessRepository).Category
+	ProgressionRuleOwner   Owner = (*repository.AccessRepository).ProgressionRule
+	ExerciseOwner          Owner = (*repository.AccessRepository).Exercise
+	ExerciseVariationOwner Owner = (*repository.AccessRepository).ExerciseVariation
+	PlanAssignmentOwner    Owner = (*repository.AccessRepository).PlanAssignment
+	SessionOwner           Owner = (*repository.AccessRepository).Session
+	ParameterTypeOwner     Owner = (*repository.AccessRepository).ParameterType
+)
+
+// filterOwners are the list filters that name another record
+var filterOwners = map[string]Owner{
+	"userId":              UserOwner,
+	"planId":              PlanOwner,
+	"intervalId":          IntervalOwner,
+	"groupId":             GroupOwner,
+	"exerciseId":          ExerciseOwner,
+	"exerciseVariationId": ExerciseVariationOwner,
+	"variationId":         ExerciseVariationOwner,
+	"prescriptionId":      PrescriptionOwner,
+	"assignmentId":        PlanAssignmentOwner,
+	"categoryId":          CategoryOwner,
+	"sessionId":           SessionOwner,
+	"parameterTypeId":     ParameterTypeOwner,
+}
+
+// Access authorizes requests made on behalf of an acting user against the owners of the
+// records they name. IDs of records that do not exist pass through so that handlers
+// report them.
+type Access struct {
+	Db *db.Database
+}
+
+// methodLevel reads with GET and HEAD and writes with every other method
+func methodLevel(r *http.Request) access.Level {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return access.Read
+	}
+	return access.Write
+}
+
+func (a *Access) authorize(w http.ResponseWriter, r *http.Request, id int64, owner Owner, level access.Level) bool {
+	actor, ok := api_utils.RequireActingUser(w, r)
+	if !ok {
+		return false
+	}
+
+	ctx := r.Context()
+	queries, tx, err := a.Db.TxQueries(ctx)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusInternalServerError, "Database error: "+err.Error())
+		return false
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("Rollback failed: %v", err)
+		}
+	}()
+
+	repo := repository.NewAccessRepository(queries)
+	resource, err := owner(repo, ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true
+	}
+	if err != nil {
+		api_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	allowed, err := repo.Allowed(ctx, actor, resource, level)
+	if err != nil {
+		api_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if !allowed {
+		switch level {
+		case access.Read:
+			api_utils.WriteError(w, http.StatusForbidden, "Not allowed to view this resource")
+		case access.Comment:
+			api_utils.WriteError(w, http.StatusForbidden, "Not allowed to comment on this resource")
+		case access.Manage:
+			api_utils.WriteError(w, http.StatusForbidden, "Only the owner can manage this resource")
+		default:
+			api_utils.WriteError(w, http.StatusForbidden, "Not allowed to change this resource")
+		}
+		return false
+	}
+	return true
+}
+
+// Param authorizes the record named by a URL parameter, reading it with GET requests
+// and changing it with the others
+func (a *Access) Param(name string, owner Owner) func(http.Handler) http.Handler {
+	return a.param(name, owner, methodLevel)
+}
+
+// ParamLevel authorizes the record named by a URL parameter at the level, whatever the method
+func (a *Access) ParamLevel(name string, owner Owner, level access.Level) func(http.Handler) http.Handler {
+	return a.param(name, owner, func(*http.Request) access.Level { return level })
+}
+
+func (a *Access) param(name string, owner Owner, level func(*http.Request) access.Level) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := api_utils.ParseBigInt(chi.URLParam(r, name))
+			if err == nil && !a.authorize(w, r, id, owner, level(r)) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Filters authorizes reading the records named by the list filters of GET requests
+func (a *Access) Filters(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			for name, owner := range filterOwners {
+				id, err := api_utils.ParseBigInt(query.Get(name))
+				if err == nil && !a.authorize(w, r, id, owner, access.Read) {
+					return
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Query authorizes the record named by a query parameter at the level
+func (a *Access) Query(name string, owner Owner, level access.Level) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := api_utils.ParseBigInt(r.URL.Query().Get(name))
+			if err == nil && !a.authorize(w, r, id, owner, level) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OwnData limits lists that span users to the acting user's own records unless a user
+// or assignment is asked for
+func OwnData(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor, ok := api_utils.RequireActingUser(w, r)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("userId") == "" && query.Get("assignmentId") == "" {
+			query.Set("userId", strconv.FormatInt(actor, 10))
+			r.URL.RawQuery = query.Encode()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Body authorizes the record named by a field of the JSON request body at the level
+func (a *Access) Body(field string, owner Owner, level access.Level) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := bodyId(r, field)
+			if err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			if id > 0 && !a.authorize(w, r, id, owner, level) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyId reads the ID in a field of the JSON request body, leaving the body to be read
+// again. It is zero when the body has no such field.
+func bodyId(r *http.Request, field string) (int64, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return 0, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]json.RawMessage
+	var id int64
+	if json.Unmarshal(body, &fields) != nil || json.Unmarshal(fields[field], &id) != nil || id < 0 {
+		return 0, nil
+	}
+	return id, nil
+}
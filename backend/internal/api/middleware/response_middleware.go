@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 )
 
 type ApiResponseWrapper struct {
@@ -39,7 +40,7 @@ func ResponseMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
-		if isApiResponseFormat(body) {
+		if isApiResponseFormat(body) || isPassthroughContentType(response.Header.Get("Content-Type")) || isAttachment(response.Header) {
 			w.WriteHeader(response.StatusCode)
 			_, err := w.Write(body)
 			if err != nil {
@@ -93,6 +94,24 @@ func ResponseMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// isPassthroughContentType reports whether a response carries a non-JSON payload,
+// such as a file download, that must be sent to the client as-is. Responses
+// without an explicit content type are sniffed as text/plain by the recorder,
+// so text/plain is still wrapped.
+func isPassthroughContentType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	return mediaType != "application/json" && mediaType != "text/plain"
+}
+
+// isAttachment reports whether a response is a file download. Downloads are sent as-is,
+// even JSON ones, so the file a client saves can be uploaded again unchanged.
+func isAttachment(header http.Header) bool {
+	return strings.HasPrefix(strings.TrimSpace(header.Get("Content-Disposition")), "attachment")
+}
+
 func isApiResponseFormat(body []byte) bool {
 	if len(body) == 0 {
 		return false
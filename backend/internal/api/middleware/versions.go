@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"backend/db"
+	"backend/db/repository"
+	api_utils "backend/internal/api/utils"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// Versions records a version of the plans a request changes, in the same transaction as
+// the change. The plans are looked up from the record the request names before it is
+// handled, so deletions are recorded too. A baseline version is recorded first when the
+// plan changed since its latest version, or has none yet, so that every change can be
+// diffed and undone.
+type Versions struct {
+	Db *db.Database
+	// Record stores a version of the plan when it differs from the latest one
+	Record func(ctx context.Context, queries *db.Queries, planId int64, createdBy *int64) error
+}
+
+// Param records versions of the plans the record named by a URL parameter is part of
+func (v *Versions) Param(name string, owner Owner) func(http.Handler) http.Handler {
+	return v.record(owner, func(r *http.Request) (int64, error) {
+		id, err := api_utils.ParseBigInt(chi.URLParam(r, name))
+		if err != nil {
+			return 0, nil
+		}
+		return id, nil
+	})
+}
+
+// Body records versions of the plans the record named by a field of the JSON request
+// body is part of
+func (v *Versions) Body(field string, owner Owner) func(http.Handler) http.Handler {
+	return v.record(owner, func(r *http.Request) (int64, error) {
+		if r.Body == nil {
+			return 0, nil
+		}
+		return bodyId(r, field)
+	})
+}
+
+func (v *Versions) record(owner Owner, recordId func(r *http.Request) (int64, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := recordId(r)
+			if err != nil {
+				api_utils.WriteError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+			if id <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			planIds, err := v.baseline(r.Context(), id, owner)
+			if err != nil {
+				api_utils.WriteError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if len(planIds) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var createdBy *int64
+			if actor, ok := api_utils.ActingUser(r); ok {
+				createdBy = &actor
+			}
+			// The versions are recorded in the handler's transaction before it commits. Its
+			// response is held back so that the request fails when they can not be.
+			var recordErr error
+			ctx := api_utils.WithCommitHook(r.Context(), func(ctx context.Context, queries *db.Queries) error {
+				for _, planId := range planIds {
+					if err := v.Record(ctx, queries, planId, createdBy); err != nil {
+						recordErr = err
+						return err
+					}
+				}
+				return nil
+			})
+			rec := httptest.NewRecorder()
+			next.ServeHTTP(rec, r.WithContext(ctx))
+			if recordErr != nil {
+				api_utils.WriteError(w, http.StatusInternalServerError, "Failed to record a version of the plan: "+recordErr.Error())
+				return
+			}
+
+			for key, values := range rec.Header() {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(rec.Code)
+			if _, err := w.Write(rec.Body.Bytes()); err != nil {
+				log.Printf("Error writing response: %v", err)
+			}
+		})
+	}
+}
+
+// baseline returns the plans the record is part of after recording their current state
+func (v *Versions) baseline(ctx context.Context, id int64, owner Owner) ([]int64, error) {
+	queries, tx, err := v.Db.TxQueries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("Rollback failed: %v", err)
+		}
+	}()
+
+	resource, err := owner(repository.NewAccessRepository(queries), ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, planId := range resource.PlanIDs {
+		if err := v.Record(ctx, queries, planId, nil); err != nil {
+			return nil, err
+		}
+	}
+	return resource.PlanIDs, tx.Commit(ctx)
+}
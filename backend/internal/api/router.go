@@ -2,8 +2,11 @@ package api
 
 import (
 	"backend/db"
+	"backend/internal/access"
 	"backend/internal/api/handlers"
 	"backend/internal/api/middleware"
+	"backend/internal/auth"
+	"backend/internal/storage"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -11,7 +14,7 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func NewRouter(db *db.Database) http.Handler {
+func NewRouter(db *db.Database, store storage.Storage, tokens *auth.Tokens) http.Handler {
 	r := chi.NewRouter()
 
 	// Basic middleware
@@ -33,65 +36,322 @@ func NewRouter(db *db.Database) http.Handler {
 	r.Use(middleware.ResponseMiddleware)
 
 	r.Route("/api/v1", func(r chi.Router) {
-		// Plans
-		plans_handler := &handlers.PlanHandler{Db: db}
-		r.Route("/plans", func(r chi.Router) {
-			r.Get("/", plans_handler.List)
-			r.Post("/", plans_handler.Create)
-			r.Put("/{id}", plans_handler.Edit)
-			r.Delete("/{id}", plans_handler.Delete)
-		})
+		exercise_media_handler := &handlers.ExerciseMediaHandler{Db: db, Storage: store}
+		calendar_feeds_handler := &handlers.CalendarFeedsHandler{Db: db}
 
-		// Plan Intervals
-		interval_handler := &handlers.PlanIntervalHandler{Db: db}
-		r.Route("/intervals", func(r chi.Router) {
-			r.Get("/", interval_handler.List)
-			r.Post("/", interval_handler.Create)
-			r.Put("/{id}", interval_handler.Update)
-			r.Delete("/{id}", interval_handler.Delete)
-		})
+		// Signed downloads for locally stored media
+		r.Get("/media/*", exercise_media_handler.Download)
+		// Subscribable iCalendar feeds, authorized by their token
+		r.Get("/calendar/{token}.ics", calendar_feeds_handler.Feed)
 
-		// Groups
-		groups_handler := &handlers.GroupsHandler{Db: db}
-		r.Route("/groups", func(r chi.Router) {
-			r.Get("/", groups_handler.List)
-			r.Post("/", groups_handler.Create)
-			r.Put("/{id}", groups_handler.Update)
-			r.Delete("/{id}", groups_handler.Delete)
-			// Group interval assignment
-			r.Post("/{groupId}/assign/{intervalId}", groups_handler.AssignToInterval)
-			r.Delete("/{groupId}/assign/{intervalId}", groups_handler.RemoveFromInterval)
-		})
+		// Every other request is made on behalf of the user its bearer token names, who
+		// may only reach their own data, their athletes' data, plans shared with them
+		// and public records
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Authenticate(tokens))
+			authorize := &middleware.Access{Db: db}
+			r.Use(authorize.Filters)
+			// Changes to a plan's tree are recorded as versions of the plan
+			versions := &middleware.Versions{Db: db, Record: handlers.RecordPlanVersion}
 
-		// Exercises
-		exercises_handler := &handlers.ExercisesHandler{Db: db}
-		exercise_variations_handler := &handlers.ExerciseVariationsHandler{Db: db}
-		r.Route("/exercises", func(r chi.Router) {
-			r.Get("/", exercises_handler.List)
-			r.Post("/", exercises_handler.Create)
-			r.Put("/{id}", exercises_handler.Update)
-			r.Delete("/{id}", exercises_handler.Delete)
-			r.Post("/{exerciseId}/create-variation", exercise_variations_handler.Create)
-		})
+			// Plans
+			plans_handler := &handlers.PlanHandler{Db: db}
+			schedule_handler := &handlers.ScheduleHandler{Db: db}
+			readiness_handler := &handlers.ReadinessHandler{Db: db}
+			plan_sharing_handler := &handlers.PlanSharingHandler{Db: db}
+			plan_versions_handler := &handlers.PlanVersionsHandler{Db: db}
+			plan_comments_handler := &handlers.PlanCommentsHandler{Db: db}
+			r.Route("/plans", func(r chi.Router) {
+				r.With(authorize.Query("id", middleware.PlanOwner, access.Read)).Get("/", plans_handler.List)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/", plans_handler.Create)
+				r.Get("/schema", plans_handler.Schema)
+				r.With(authorize.Query("userId", middleware.UserOwner, access.Write)).Post("/import", plans_handler.Import)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/generate", plans_handler.Generate)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("id", middleware.PlanOwner))
+					r.With(versions.Param("id", middleware.PlanOwner)).Put("/{id}", plans_handler.Edit)
+					r.Get("/{id}/equipment-check", plans_handler.EquipmentCheck)
+					r.Get("/{id}/export", plans_handler.Export)
+					// Dated schedule generated from the plan's start date
+					r.Post("/{id}/schedule", schedule_handler.Generate)
+					r.Get("/{id}/sessions", schedule_handler.List)
+					// Linked copies, e.g. the ones a coach assigned to athletes
+					r.Get("/{id}/copies", plans_handler.Copies)
+					// Collaborators see who else the plan is shared with and may leave it
+					r.Get("/{id}/collaborators", plan_sharing_handler.ListCollaborators)
+					// History of the plan's tree
+					r.Get("/{id}/versions", plan_versions_handler.List)
+					r.Get("/{id}/versions/diff", plan_versions_handler.Diff)
+					r.Get("/{id}/versions/{number}", plan_versions_handler.Get)
+					r.Post("/{id}/versions/{number}/restore", plan_versions_handler.Restore)
+					// Threaded discussion of the plan
+					r.Get("/{id}/comments", plan_comments_handler.List)
+				})
+				// Collaborators who may comment start threads, reply and resolve them; only
+				// authors edit their comments
+				r.With(
+					authorize.ParamLevel("id", middleware.PlanOwner, access.Comment),
+					authorize.Body("userId", middleware.UserOwner, access.Write),
+				).Post("/{id}/comments", plan_comments_handler.Create)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.ParamLevel("id", middleware.PlanOwner, access.Comment))
+					r.Put("/{id}/comments/{commentId}", plan_comments_handler.Update)
+					r.Delete("/{id}/comments/{commentId}", plan_comments_handler.Delete)
+					r.Put("/{id}/comments/{commentId}/resolved", plan_comments_handler.Resolve)
+				})
+				r.With(authorize.ParamLevel("id", middleware.PlanOwner, access.Read)).Delete("/{id}/collaborators/{userId}", plan_sharing_handler.RemoveCollaborator)
+				// Only the owner deletes the plan and decides who it is shared with
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.ParamLevel("id", middleware.PlanOwner, access.Manage))
+					r.Delete("/{id}", plans_handler.Delete)
+					r.Put("/{id}/collaborators/{userId}", plan_sharing_handler.SetCollaborator)
+					r.Get("/{id}/share-links", plan_sharing_handler.ListShareLinks)
+					r.Post("/{id}/share-links", plan_sharing_handler.CreateShareLink)
+					r.Delete("/{id}/share-links/{linkId}", plan_sharing_handler.RevokeShareLink)
+				})
+			})
+			r.Post("/share-links/{token}/accept", plan_sharing_handler.AcceptShareLink)
 
-		// Exercise Variations
-		r.Route("/exercise-variations", func(r chi.Router) {
-			r.Get("/", exercise_variations_handler.List)
-			r.Delete("/{id}", exercise_variations_handler.Delete)
-		})
+			// Gallery of the public templates of all users
+			templates_handler := &handlers.TemplateHandler{Db: db}
+			r.Route("/templates", func(r chi.Router) {
+				r.Get("/", templates_handler.List)
+				r.Get("/{id}", templates_handler.Get)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/{id}/fork", templates_handler.Fork)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Put("/{id}/rating", templates_handler.Rate)
+				r.With(authorize.Query("userId", middleware.UserOwner, access.Write)).Delete("/{id}/rating", templates_handler.RemoveRating)
+			})
 
-		//Parameter Types
-		parameter_types_handler := &handlers.ParameterTypesHandler{Db: db}
-		r.Route("/parameter-types", func(r chi.Router) {
-			r.Get("/", parameter_types_handler.List)
-		})
+			// Scheduled sessions
+			r.Route("/sessions", func(r chi.Router) {
+				r.With(middleware.OwnData).Get("/", schedule_handler.Upcoming)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("id", middleware.SessionOwner))
+					r.Put("/{id}", schedule_handler.Reschedule)
+					r.Post("/{id}/skip", schedule_handler.Skip)
+					r.Post("/{id}/complete", schedule_handler.Complete)
+					// Changes to the session recommended by the athlete's readiness check-in
+					r.Get("/{id}/recommendation", readiness_handler.Recommendation)
+				})
+			})
+
+			// Personal records detected from completed sessions
+			personal_records_handler := &handlers.PersonalRecordsHandler{Db: db}
+			r.With(middleware.OwnData).Get("/records", personal_records_handler.List)
+
+			// Plans assigned to athletes, each with its own dated schedule
+			plan_assignments_handler := &handlers.PlanAssignmentsHandler{Db: db}
+			r.Route("/plan-assignments", func(r chi.Router) {
+				r.With(middleware.OwnData).Get("/", plan_assignments_handler.List)
+				r.With(
+					authorize.Body("userId", middleware.UserOwner, access.Write),
+					authorize.Body("planId", middleware.PlanOwner, access.Read),
+				).Post("/", plan_assignments_handler.Create)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("id", middleware.PlanAssignmentOwner))
+					r.Get("/{id}", plan_assignments_handler.Get)
+					r.Put("/{id}", plan_assignments_handler.Update)
+					r.Delete("/{id}", plan_assignments_handler.Delete)
+					r.Post("/{id}/reflow", plan_assignments_handler.Reflow)
+				})
+			})
+
+			// Plan Intervals
+			interval_handler := &handlers.PlanIntervalHandler{Db: db}
+			groups_handler := &handlers.GroupsHandler{Db: db}
+			r.Route("/intervals", func(r chi.Router) {
+				r.With(authorize.Query("id", middleware.IntervalOwner, access.Read)).Get("/", interval_handler.List)
+				r.With(
+					authorize.Body("planId", middleware.PlanOwner, access.Write),
+					versions.Body("planId", middleware.PlanOwner),
+				).Post("/", interval_handler.Create)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("id", middleware.IntervalOwner))
+					r.Use(versions.Param("id", middleware.IntervalOwner))
+					r.Put("/{id}", interval_handler.Update)
+					r.Delete("/{id}", interval_handler.Delete)
+					r.Post("/{id}/copy", interval_handler.Copy)
+					r.Get("/{id}/progression", interval_handler.Progression)
+					r.Post("/{id}/deload", interval_handler.Deload)
+				})
+				r.With(authorize.Param("intervalId", middleware.IntervalOwner)).Get("/{intervalId}/groups", groups_handler.ListIntervalGroups)
+			})
+
+			// Groups
+			r.Route("/groups", func(r chi.Router) {
+				r.With(authorize.Query("id", middleware.GroupOwner, access.Read)).Get("/", groups_handler.List)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/", groups_handler.Create)
+				r.With(authorize.Param("id", middleware.GroupOwner), versions.Param("id", middleware.GroupOwner)).Put("/{id}", groups_handler.Update)
+				r.With(authorize.Param("id", middleware.GroupOwner), versions.Param("id", middleware.GroupOwner)).Delete("/{id}", groups_handler.Delete)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("groupId", middleware.GroupOwner))
+					// Group interval assignment
+					r.Get("/{groupId}/assignments", groups_handler.ListIntervals)
+					r.Get("/{groupId}/timeline", groups_handler.Timeline)
+					r.Group(func(r chi.Router) {
+						r.Use(authorize.Param("intervalId", middleware.IntervalOwner))
+						r.Use(versions.Param("intervalId", middleware.IntervalOwner))
+						r.Post("/{groupId}/assign/{intervalId}", groups_handler.AssignToInterval)
+						r.Put("/{groupId}/assign/{intervalId}", groups_handler.UpdateIntervalAssignment)
+						r.Delete("/{groupId}/assign/{intervalId}", groups_handler.RemoveFromInterval)
+					})
+				})
+			})
+
+			// Exercises
+			exercises_handler := &handlers.ExercisesHandler{Db: db}
+			exercise_variations_handler := &handlers.ExerciseVariationsHandler{Db: db}
+			exercise_library_handler := &handlers.ExerciseLibraryHandler{Db: db}
+			r.Route("/exercises", func(r chi.Router) {
+				r.With(authorize.Query("id", middleware.ExerciseOwner, access.Read)).Get("/", exercises_handler.List)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/", exercises_handler.Create)
+				// Bulk library import/export
+				r.Get("/export", exercise_library_handler.Export)
+				r.With(authorize.Query("userId", middleware.UserOwner, access.Write)).Post("/import", exercise_library_handler.Import)
+				r.With(authorize.Param("id", middleware.ExerciseOwner)).Put("/{id}", exercises_handler.Update)
+				r.With(authorize.Param("id", middleware.ExerciseOwner)).Delete("/{id}", exercises_handler.Delete)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("exerciseId", middleware.ExerciseOwner))
+					r.Post("/{exerciseId}/create-variation", exercise_variations_handler.Create)
+					// Exercise category tagging
+					r.With(authorize.Param("categoryId", middleware.CategoryOwner)).Post("/{exerciseId}/categories/{categoryId}", exercises_handler.AddCategory)
+					r.Delete("/{exerciseId}/categories/{categoryId}", exercises_handler.RemoveCategory)
+					// Exercise media attachments
+					r.Get("/{exerciseId}/media", exercise_media_handler.List)
+					r.Post("/{exerciseId}/media", exercise_media_handler.Upload)
+					r.Delete("/{exerciseId}/media/{mediaId}", exercise_media_handler.Delete)
+				})
+			})
+
+			// Categories
+			categories_handler := &handlers.CategoriesHandler{Db: db}
+			r.Route("/categories", func(r chi.Router) {
+				r.With(authorize.Query("id", middleware.CategoryOwner, access.Read)).Get("/", categories_handler.List)
+				r.With(authorize.Body("userId", middleware.UserOwner, access.Write)).Post("/", categories_handler.Create)
+				r.With(authorize.Param("id", middleware.CategoryOwner)).Put("/{id}", categories_handler.Update)
+				r.With(authorize.Param("id", middleware.CategoryOwner)).Delete("/{id}", categories_handler.Delete)
+			})
+
+			// Users
+			users_handler := &handlers.UsersHandler{Db: db}
+			benchmarks_handler := &handlers.BenchmarksHandler{Db: db}
+			logbook_handler := &handlers.LogbookHandler{Db: db}
+			training_load_handler := &handlers.TrainingLoadHandler{Db: db}
+			teams_handler := &handlers.TeamsHandler{Db: db}
+			r.Route("/users", func(r chi.Router) {
+				// Coaches may read their athletes' data; only users change their own
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("userId", middleware.UserOwner))
+					r.Get("/{userId}/equipment", users_handler.GetEquipment)
+					r.Put("/{userId}/equipment", users_handler.SetEquipment)
+					r.Get("/{userId}/calendar-feeds", calendar_feeds_handler.List)
+					r.Post("/{userId}/calendar-feeds", calendar_feeds_handler.Create)
+					r.Delete("/{userId}/calendar-feeds/{feedId}", calendar_feeds_handler.Delete)
+					r.Get("/{userId}/benchmarks", benchmarks_handler.List)
+					r.Post("/{userId}/benchmarks", benchmarks_handler.Create)
+					r.Put("/{userId}/benchmarks/{benchmarkId}", benchmarks_handler.Update)
+					r.Delete("/{userId}/benchmarks/{benchmarkId}", benchmarks_handler.Delete)
+					r.Get("/{userId}/logbook", logbook_handler.List)
+					r.Post("/{userId}/logbook", logbook_handler.Create)
+					r.Get("/{userId}/logbook/pyramid", logbook_handler.Pyramid)
+					r.Put("/{userId}/logbook/{entryId}", logbook_handler.Update)
+					r.Delete("/{userId}/logbook/{entryId}", logbook_handler.Delete)
+					r.Get("/{userId}/training-load-thresholds", training_load_handler.GetThresholds)
+					r.Put("/{userId}/training-load-thresholds", training_load_handler.SetThresholds)
+					r.Get("/{userId}/readiness", readiness_handler.List)
+					r.Get("/{userId}/readiness/trend", readiness_handler.Trend)
+					r.Put("/{userId}/readiness/{date}", readiness_handler.Save)
+					r.Delete("/{userId}/readiness/{date}", readiness_handler.Delete)
+					r.Get("/{userId}/teams", teams_handler.ListUserTeams)
+					r.Get("/{userId}/invitations", teams_handler.ListUserInvitations)
+				})
+			})
+
+			// Teams of athletes and their coaches, managed by the acting user
+			r.Route("/teams", func(r chi.Router) {
+				r.Post("/", teams_handler.Create)
+				r.Get("/{teamId}", teams_handler.Get)
+				r.Put("/{teamId}", teams_handler.Update)
+				r.Delete("/{teamId}", teams_handler.Delete)
+				r.Put("/{teamId}/members/{userId}", teams_handler.UpdateMember)
+				r.Delete("/{teamId}/members/{userId}", teams_handler.RemoveMember)
+				r.Get("/{teamId}/invitations", teams_handler.ListInvitations)
+				r.Post("/{teamId}/invitations", teams_handler.Invite)
+				// Linked copies of a plan for athletes of the team
+				r.Post("/{teamId}/assignments", teams_handler.AssignPlan)
+			})
+			r.Post("/invitations/{id}/accept", teams_handler.Accept)
+			r.Post("/invitations/{id}/decline", teams_handler.Decline)
+
+			// Climbing grades and their conversions between systems
+			r.Get("/grades", logbook_handler.Grades)
+
+			// Analytics
+			analytics_handler := &handlers.AnalyticsHandler{Db: db}
+			r.Route("/analytics", func(r chi.Router) {
+				r.Get("/muscle-group-volume", analytics_handler.MuscleGroupVolume)
+				r.Get("/training-load", training_load_handler.TrainingLoad)
+			})
+
+			// Exercise Variations
+			r.Route("/exercise-variations", func(r chi.Router) {
+				r.Get("/", exercise_variations_handler.List)
+				r.With(authorize.Param("id", middleware.ExerciseVariationOwner)).Delete("/{id}", exercise_variations_handler.Delete)
+			})
+
+			//Parameter Types
+			parameter_types_handler := &handlers.ParameterTypesHandler{Db: db}
+			r.Route("/parameter-types", func(r chi.Router) {
+				r.Get("/", parameter_types_handler.List)
+			})
+
+			//Interval Exercise Prescriptions
+			interval_exercise_prescriptions_handler := &handlers.IntervalExercisePrescriptionsHandler{Db: db}
+			r.Route("/interval-exercise-prescriptions", func(r chi.Router) {
+				r.Get("/", interval_exercise_prescriptions_handler.List)
+				r.With(
+					authorize.Body("planIntervalId", middleware.IntervalOwner, access.Write),
+					versions.Body("planIntervalId", middleware.IntervalOwner),
+				).Post("/", interval_exercise_prescriptions_handler.Create)
+				r.With(
+					authorize.Body("planIntervalId", middleware.IntervalOwner, access.Write),
+					versions.Body("planIntervalId", middleware.IntervalOwner),
+				).Put("/order", interval_exercise_prescriptions_handler.Reorder)
+				r.With(
+					authorize.Param("id", middleware.PrescriptionOwner),
+					versions.Param("id", middleware.PrescriptionOwner),
+				).Put("/{id}", interval_exercise_prescriptions_handler.Update)
+				// r.Delete("/{id}", interval_exercise_prescriptions_handler.Delete)
+			})
+
+			//Prescription Blocks (supersets and circuits)
+			prescription_blocks_handler := &handlers.PrescriptionBlocksHandler{Db: db}
+			r.Route("/prescription-blocks", func(r chi.Router) {
+				r.Get("/", prescription_blocks_handler.List)
+				r.With(
+					authorize.Body("planIntervalId", middleware.IntervalOwner, access.Write),
+					versions.Body("planIntervalId", middleware.IntervalOwner),
+				).Post("/", prescription_blocks_handler.Create)
+				r.Group(func(r chi.Router) {
+					r.Use(authorize.Param("id", middleware.PrescriptionBlockOwner))
+					r.Use(versions.Param("id", middleware.PrescriptionBlockOwner))
+					r.Get("/{id}", prescription_blocks_handler.Get)
+					r.Put("/{id}", prescription_blocks_handler.Update)
+					r.Delete("/{id}", prescription_blocks_handler.Delete)
+				})
+			})
 
-		//Interval Exercise Prescriptions
-		interval_exercise_prescriptions_handler := &handlers.IntervalExercisePrescriptionsHandler{Db: db}
-		r.Route("/interval-exercise-prescriptions", func(r chi.Router) {
-			r.Get("/", interval_exercise_prescriptions_handler.List)
-			r.Post("/", interval_exercise_prescriptions_handler.Create)
-			// r.Delete("/{id}", interval_exercise_prescriptions_handler.Delete)
+			//Progression rules
+			progression_rules_handler := &handlers.ProgressionRulesHandler{Db: db}
+			r.Route("/progression-rules", func(r chi.Router) {
+				r.Get("/", progression_rules_handler.List)
+				r.With(
+					authorize.Body("prescriptionId", middleware.PrescriptionOwner, access.Write),
+					authorize.Body("groupId", middleware.GroupOwner, access.Write),
+				).Post("/", progression_rules_handler.Create)
+				r.With(authorize.Param("id", middleware.ProgressionRuleOwner)).Put("/{id}", progression_rules_handler.Update)
+				r.With(authorize.Param("id", middleware.ProgressionRuleOwner)).Delete("/{id}", progression_rules_handler.Delete)
+			})
 		})
 	})
 
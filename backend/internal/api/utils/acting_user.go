@@ -0,0 +1,29 @@
+package api_utils
+
+import (
+	"context"
+	"net/http"
+)
+
+type actingUserKey struct{}
+
+// WithActingUser returns a context carrying the acting user's ID
+func WithActingUser(ctx context.Context, userId int64) context.Context {
+	return context.WithValue(ctx, actingUserKey{}, userId)
+}
+
+// ActingUser returns the ID of the user the request is made on behalf of, as named by
+// its authenticated bearer token
+func ActingUser(r *http.Request) (int64, bool) {
+	userId, ok := r.Context().Value(actingUserKey{}).(int64)
+	return userId, ok
+}
+
+// RequireActingUser returns the acting user, writing an error when there is none
+func RequireActingUser(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	userId, ok := ActingUser(r)
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, "Missing bearer token")
+	}
+	return userId, ok
+}
@@ -46,6 +46,17 @@ func WriteError(w http.ResponseWriter, status int, message string) {
 	}
 }
 
+type commitHookKey struct{}
+
+// CommitHook is work done in a request's transactions after their changes, so that it is
+// committed or rolled back together with them
+type CommitHook func(ctx context.Context, queries *db.Queries) error
+
+// WithCommitHook returns a context whose transactions run the hook before they commit
+func WithCommitHook(ctx context.Context, hook CommitHook) context.Context {
+	return context.WithValue(ctx, commitHookKey{}, hook)
+}
+
 func WithTransaction(ctx context.Context, db *db.Database, w http.ResponseWriter, fn func(*db.Queries) error) bool {
 	queries, tx, err := db.TxQueries(ctx)
 	if err != nil {
@@ -57,10 +68,24 @@ func WithTransaction(ctx context.Context, db *db.Database, w http.ResponseWriter
 	if err := fn(queries); err != nil {
 		logErrorWithLocation(err)
 		log.Printf("Transaction function error: %v", err)
+		if tx_err := tx.Rollback(ctx); tx_err != nil {
+			log.Printf("Rollback failed: %v", tx_err)
+		}
 		WriteError(w, http.StatusInternalServerError, err.Error())
 		return false
 	}
 
+	if hook, ok := ctx.Value(commitHookKey{}).(CommitHook); ok {
+		if err := hook(ctx, queries); err != nil {
+			logErrorWithLocation(err)
+			if tx_err := tx.Rollback(ctx); tx_err != nil {
+				log.Printf("Rollback failed: %v", tx_err)
+			}
+			WriteError(w, http.StatusInternalServerError, err.Error())
+			return false
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		logErrorWithLocation(err)
 		if tx_err := tx.Rollback(ctx); tx_err != nil {
@@ -0,0 +1,57 @@
+// Package benchmarks resolves prescribed values given as a percentage of an athlete's
+// benchmark, e.g. 80% of a 1RM deadlift, into absolute values for that athlete.
+package benchmarks
+
+import (
+	"math"
+	"time"
+)
+
+// Benchmark is an athlete's measured best for a parameter on a date. VariationID is 0
+// for benchmarks that apply to every variation, such as bodyweight.
+type Benchmark struct {
+	ID              int64
+	VariationID     int64
+	ParameterTypeID int64
+	Value           float64
+	Date            time.Time
+}
+
+// Latest returns the benchmark a percentage of the variation's parameter refers to on
+// the given date: the most recent one of the variation recorded by then, or else the
+// most recent general one
+func Latest(benchmarks []Benchmark, variationId int64, parameterTypeId int64, date time.Time) (Benchmark, bool) {
+	var own, general *Benchmark
+	for i := range benchmarks {
+		benchmark := &benchmarks[i]
+		if benchmark.ParameterTypeID != parameterTypeId || benchmark.Date.After(date) {
+			continue
+		}
+		switch benchmark.VariationID {
+		case variationId:
+			own = later(own, benchmark)
+		case 0:
+			general = later(general, benchmark)
+		}
+	}
+	if own != nil {
+		return *own, true
+	}
+	if general != nil {
+		return *general, true
+	}
+	return Benchmark{}, false
+}
+
+// later picks the more recent of two benchmarks, the later recorded one on the same date
+func later(current *Benchmark, candidate *Benchmark) *Benchmark {
+	if current == nil || candidate.Date.After(current.Date) || (candidate.Date.Equal(current.Date) && candidate.ID > current.ID) {
+		return candidate
+	}
+	return current
+}
+
+// Resolve is the absolute value of a percentage of the benchmark, rounded to 2 decimals
+func Resolve(percentage float64, benchmark Benchmark) float64 {
+	return math.Round(benchmark.Value*percentage) / 100
+}
@@ -0,0 +1,46 @@
+// Package comments reads the mentions in comments on plans.
+package comments
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Member is a user who may be mentioned in the comments on a plan, i.e. its owner or a
+// collaborator
+type Member struct {
+	ID    int64
+	Email string
+}
+
+// mentionPattern matches an @ that does not continue a word or an email address,
+// followed by a handle or a whole email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.+-])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// Handle is how a member is mentioned: the part of their email address before the @
+func Handle(email string) string {
+	handle, _, _ := strings.Cut(email, "@")
+	return strings.ToLower(handle)
+}
+
+// Mentions returns the members a comment mentions, in the order they are first mentioned.
+// Members are mentioned by @ and their handle or email address, ignoring case; a handle
+// that several members share mentions each of them.
+func Mentions(body string, members []Member) []int64 {
+	ids := []int64{}
+	seen := make(map[int64]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		// A sentence may end right after a mention
+		mention := strings.ToLower(strings.TrimRight(match[1], "."))
+		for _, member := range members {
+			if seen[member.ID] {
+				continue
+			}
+			if mention == strings.ToLower(member.Email) || mention == Handle(member.Email) {
+				seen[member.ID] = true
+				ids = append(ids, member.ID)
+			}
+		}
+	}
+	return ids
+}
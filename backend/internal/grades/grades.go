@@ -0,0 +1,198 @@
+// Package grades converts climbing grades between systems. Boulders are graded in the
+// V-scale or Font, routes in YDS or French. Each discipline has one ladder of grades,
+// so grades of either of its systems can be compared and converted.
+package grades
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Grade systems
+const (
+	SystemV      = "v"
+	SystemFont   = "font"
+	SystemYDS    = "yds"
+	SystemFrench = "french"
+)
+
+var Systems = []string{SystemV, SystemFont, SystemYDS, SystemFrench}
+
+// Disciplines
+const (
+	DisciplineBoulder = "boulder"
+	DisciplineRoute   = "route"
+)
+
+// ladder lists a discipline's grades from easiest to hardest, one column per system. A
+// grade that spans several rows of the other system repeats; it stands for its first
+// row.
+type ladder struct {
+	discipline string
+	systems    [2]string
+	rows       [][2]string
+}
+
+var boulderLadder = ladder{
+	discipline: DisciplineBoulder,
+	systems:    [2]string{SystemV, SystemFont},
+	rows: [][2]string{
+		{"VB", "3"}, {"V0", "4"}, {"V1", "5"}, {"V2", "5+"},
+		{"V3", "6A"}, {"V3", "6A+"}, {"V4", "6B"}, {"V4", "6B+"}, {"V5", "6C"}, {"V5", "6C+"},
+		{"V6", "7A"}, {"V7", "7A+"}, {"V8", "7B"}, {"V8", "7B+"}, {"V9", "7C"}, {"V10", "7C+"},
+		{"V11", "8A"}, {"V12", "8A+"}, {"V13", "8B"}, {"V14", "8B+"}, {"V15", "8C"}, {"V16", "8C+"},
+		{"V17", "9A"},
+	},
+}
+
+var routeLadder = ladder{
+	discipline: DisciplineRoute,
+	systems:    [2]string{SystemYDS, SystemFrench},
+	rows: [][2]string{
+		{"5.5", "4a"}, {"5.6", "4b"}, {"5.7", "4c"}, {"5.8", "5a"}, {"5.9", "5b"}, {"5.9", "5c"},
+		{"5.10a", "6a"}, {"5.10b", "6a+"}, {"5.10c", "6b"}, {"5.10d", "6b+"},
+		{"5.11a", "6c"}, {"5.11b", "6c"}, {"5.11c", "6c+"}, {"5.11d", "7a"},
+		{"5.12a", "7a+"}, {"5.12b", "7b"}, {"5.12c", "7b+"}, {"5.12d", "7c"},
+		{"5.13a", "7c+"}, {"5.13b", "8a"}, {"5.13c", "8a+"}, {"5.13d", "8b"},
+		{"5.14a", "8b+"}, {"5.14b", "8c"}, {"5.14c", "8c+"}, {"5.14d", "9a"},
+		{"5.15a", "9a+"}, {"5.15b", "9b"}, {"5.15c", "9b+"}, {"5.15d", "9c"},
+	},
+}
+
+func ladderFor(system string) (*ladder, int, bool) {
+	for _, l := range []*ladder{&boulderLadder, &routeLadder} {
+		for column, s := range l.systems {
+			if s == system {
+				return l, column, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// Discipline returns the discipline a grade system is used for
+func Discipline(system string) (string, bool) {
+	l, _, ok := ladderFor(system)
+	if !ok {
+		return "", false
+	}
+	return l.discipline, true
+}
+
+// grade returns the grade in a column at a place on the ladder. A repeated grade is known
+// by its first row.
+func (l *ladder) grade(column int, index int) Grade {
+	name := l.rows[index][column]
+	first := index
+	for first > 0 && l.rows[first-1][column] == name {
+		first--
+	}
+	return Grade{System: l.systems[column], Name: name, Index: first}
+}
+
+// Grade is a grade in a system. Index places it on its discipline's ladder, so grades of
+// the same discipline compare by index whatever their system.
+type Grade struct {
+	System string
+	Name   string
+	Index  int
+}
+
+// looseYDS matches YDS grades of 5.10 and above without a letter, which stand for "a"
+var looseYDS = regexp.MustCompile(`^5\.1[0-5]$`)
+
+// normalize writes a grade the way the ladder does
+func normalize(system string, name string) string {
+	name = strings.TrimSpace(name)
+	switch system {
+	case SystemV, SystemFont:
+		return strings.ToUpper(name)
+	case SystemYDS:
+		name = strings.ToLower(name)
+		if looseYDS.MatchString(name) {
+			name += "a"
+		}
+		return name
+	default:
+		return strings.ToLower(name)
+	}
+}
+
+// Parse reads a grade of a system, ignoring case
+func Parse(system string, name string) (Grade, error) {
+	l, column, ok := ladderFor(system)
+	if !ok {
+		return Grade{}, fmt.Errorf("unknown grade system %q", system)
+	}
+	normalized := normalize(system, name)
+	for index, row := range l.rows {
+		if row[column] == normalized {
+			return Grade{System: system, Name: normalized, Index: index}, nil
+		}
+	}
+	return Grade{}, fmt.Errorf("%q is not a %s grade", name, system)
+}
+
+// Convert returns the grade in another system of its discipline
+func Convert(g Grade, system string) (Grade, error) {
+	from, _, ok := ladderFor(g.System)
+	if !ok {
+		return Grade{}, fmt.Errorf("unknown grade system %q", g.System)
+	}
+	to, column, ok := ladderFor(system)
+	if !ok {
+		return Grade{}, fmt.Errorf("unknown grade system %q", system)
+	}
+	if from != to {
+		return Grade{}, fmt.Errorf("%s grades cannot be converted to %s", g.System, system)
+	}
+	return to.grade(column, g.Index), nil
+}
+
+// List returns a system's grades from easiest to hardest
+func List(system string) ([]Grade, error) {
+	l, column, ok := ladderFor(system)
+	if !ok {
+		return nil, fmt.Errorf("unknown grade system %q", system)
+	}
+	var result []Grade
+	for index := range l.rows {
+		if grade := l.grade(column, index); grade.Index == index {
+			result = append(result, grade)
+		}
+	}
+	return result, nil
+}
+
+// Level is a step of a grade pyramid
+type Level struct {
+	Grade Grade
+	Count int
+}
+
+// Pyramid counts grades in a system, hardest first. Grades are converted to the system,
+// so grades of its other system fall on the same levels; grades of another discipline
+// are left out.
+func Pyramid(list []Grade, system string) ([]Level, error) {
+	if _, _, ok := ladderFor(system); !ok {
+		return nil, fmt.Errorf("unknown grade system %q", system)
+	}
+	var levels []Level
+	for _, g := range list {
+		converted, err := Convert(g, system)
+		if err != nil {
+			continue
+		}
+		i := slices.IndexFunc(levels, func(level Level) bool { return level.Grade.Index == converted.Index })
+		if i < 0 {
+			levels = append(levels, Level{Grade: converted})
+			i = len(levels) - 1
+		}
+		levels[i].Count++
+	}
+	slices.SortFunc(levels, func(a, b Level) int { return cmp.Compare(b.Grade.Index, a.Grade.Index) })
+	return levels, nil
+}
@@ -0,0 +1,105 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// Event is an all-day calendar entry
+type Event struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	Updated     time.Time
+	// Sequence must grow every time the event changes, so subscribed calendars pick up moves
+	Sequence int
+}
+
+// Calendar is a named feed of events
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// escape escapes text values as RFC 5545 requires
+func escape(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// fold splits a content line into lines of at most 75 octets without breaking characters
+func fold(line string) string {
+	if len(line) <= maxLineOctets {
+		return line + "\r\n"
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// Write writes the calendar as an iCalendar (RFC 5545) document
+func Write(w io.Writer, calendar Calendar) error {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(fold(fmt.Sprintf(format, args...)))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Rocket//Training schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escape(calendar.Name))
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	line("X-PUBLISHED-TTL:PT1H")
+
+	for _, event := range calendar.Events {
+		line("BEGIN:VEVENT")
+		line("UID:%s", event.UID)
+		line("DTSTAMP:%s", formatTimestamp(event.Updated))
+		line("LAST-MODIFIED:%s", formatTimestamp(event.Updated))
+		line("DTSTART;VALUE=DATE:%s", formatDate(event.Date))
+		line("DTEND;VALUE=DATE:%s", formatDate(event.Date.AddDate(0, 0, 1)))
+		line("SEQUENCE:%d", event.Sequence)
+		line("SUMMARY:%s", escape(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:%s", escape(event.Description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,336 @@
+package library
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVHeader lists the columns of the flat CSV form. Each row describes one parameter of
+// one variation; exercise and parameter type columns are repeated on every row. Lists
+// such as muscle groups are separated by semicolons.
+var CSVHeader = []string{
+	"exercise",
+	"description",
+	"primary_muscle_groups",
+	"secondary_muscle_groups",
+	"equipment",
+	"laterality",
+	"categories",
+	"variation",
+	"parameter_type",
+	"data_type",
+	"default_unit",
+	"min_value",
+	"max_value",
+	"locked",
+}
+
+const (
+	colExercise = iota
+	colDescription
+	colPrimaryMuscleGroups
+	colSecondaryMuscleGroups
+	colEquipment
+	colLaterality
+	colCategories
+	colVariation
+	colParameterType
+	colDataType
+	colDefaultUnit
+	colMinValue
+	colMaxValue
+	colLocked
+)
+
+// exerciseColumns are the columns that describe the exercise rather than a variation
+var exerciseColumns = []int{
+	colDescription,
+	colPrimaryMuscleGroups,
+	colSecondaryMuscleGroups,
+	colEquipment,
+	colLaterality,
+	colCategories,
+}
+
+const listSeparator = ";"
+
+// ParseCSV reads a library from its flat CSV form. Columns are matched by header name,
+// so they may appear in any order and only "exercise" is required.
+func ParseCSV(r io.Reader) (*Library, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &ParseError{Line: 1, Message: "missing header row"}
+		}
+		return nil, csvParseError(err)
+	}
+
+	columns := make([]int, len(CSVHeader))
+	for i := range columns {
+		columns[i] = -1
+	}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index := indexOf(CSVHeader, name)
+		if index < 0 {
+			return nil, &ParseError{Line: 1, Message: fmt.Sprintf("unknown column %q", name)}
+		}
+		columns[index] = i
+	}
+	if columns[colExercise] < 0 {
+		return nil, &ParseError{Line: 1, Message: `missing required column "exercise"`}
+	}
+
+	lib := &Library{Version: FormatVersion}
+	exercises := make(map[string]int)
+	// Raw exercise cells from the row that introduced each exercise, used to catch rows
+	// that describe the same exercise differently
+	exerciseCells := make(map[string][]string)
+	parameterTypes := make(map[string]int)
+	parameterTypeLines := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, csvParseError(err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(column int) string {
+			index := columns[column]
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		name := cell(colExercise)
+		if name == "" {
+			return nil, &ParseError{Line: line, Message: "missing exercise name"}
+		}
+
+		exerciseIndex, ok := exercises[key(name)]
+		if !ok {
+			lib.Exercises = append(lib.Exercises, Exercise{
+				Name:                  name,
+				Description:           cell(colDescription),
+				PrimaryMuscleGroups:   splitList(cell(colPrimaryMuscleGroups)),
+				SecondaryMuscleGroups: splitList(cell(colSecondaryMuscleGroups)),
+				Equipment:             splitList(cell(colEquipment)),
+				Laterality:            cell(colLaterality),
+				Categories:            splitList(cell(colCategories)),
+				Variations:            []Variation{},
+				Line:                  line,
+			})
+			exerciseIndex = len(lib.Exercises) - 1
+			exercises[key(name)] = exerciseIndex
+
+			cells := make([]string, len(CSVHeader))
+			for _, column := range exerciseColumns {
+				cells[column] = cell(column)
+			}
+			exerciseCells[key(name)] = cells
+		} else {
+			first := exerciseCells[key(name)]
+			for _, column := range exerciseColumns {
+				if value := cell(column); value != "" && value != first[column] {
+					return nil, &ParseError{Line: line, Message: fmt.Sprintf("%s of exercise %q differs from line %d",
+						CSVHeader[column], name, lib.Exercises[exerciseIndex].Line)}
+				}
+			}
+		}
+		exercise := &lib.Exercises[exerciseIndex]
+
+		variationName := cell(colVariation)
+		parameterName := cell(colParameterType)
+		if variationName == "" && parameterName == "" {
+			continue
+		}
+
+		variationIndex := -1
+		for i, variation := range exercise.Variations {
+			if key(variation.Name) == key(variationName) {
+				variationIndex = i
+				break
+			}
+		}
+		if variationIndex < 0 {
+			exercise.Variations = append(exercise.Variations, Variation{Name: variationName, Parameters: []Parameter{}})
+			variationIndex = len(exercise.Variations) - 1
+		}
+		variation := &exercise.Variations[variationIndex]
+
+		if parameterName == "" {
+			continue
+		}
+
+		locked := false
+		if value := cell(colLocked); value != "" {
+			if locked, err = strconv.ParseBool(value); err != nil {
+				return nil, &ParseError{Line: line, Message: fmt.Sprintf("invalid locked value %q", value)}
+			}
+		}
+		variation.Parameters = append(variation.Parameters, Parameter{ParameterType: parameterName, Locked: locked})
+
+		// A parameter type is only defined when the row carries its settings; rows that
+		// just name it refer to a type defined elsewhere or already in the account
+		if cell(colDataType) == "" && cell(colDefaultUnit) == "" && cell(colMinValue) == "" && cell(colMaxValue) == "" {
+			continue
+		}
+		parameterType := ParameterType{
+			Name:        parameterName,
+			DataType:    cell(colDataType),
+			DefaultUnit: cell(colDefaultUnit),
+		}
+		if parameterType.MinValue, err = parseBound(cell(colMinValue)); err != nil {
+			return nil, &ParseError{Line: line, Message: fmt.Sprintf("invalid min_value %q", cell(colMinValue))}
+		}
+		if parameterType.MaxValue, err = parseBound(cell(colMaxValue)); err != nil {
+			return nil, &ParseError{Line: line, Message: fmt.Sprintf("invalid max_value %q", cell(colMaxValue))}
+		}
+
+		if index, ok := parameterTypes[key(parameterName)]; ok {
+			if !sameParameterType(lib.ParameterTypes[index], parameterType) {
+				return nil, &ParseError{Line: line, Message: fmt.Sprintf("parameter type %q differs from line %d",
+					parameterName, parameterTypeLines[key(parameterName)])}
+			}
+			continue
+		}
+		lib.ParameterTypes = append(lib.ParameterTypes, parameterType)
+		parameterTypes[key(parameterName)] = len(lib.ParameterTypes) - 1
+		parameterTypeLines[key(parameterName)] = line
+	}
+
+	return lib, nil
+}
+
+// WriteCSV writes a library in its flat CSV form. Exercises without variations and
+// variations without parameters still get a row of their own.
+func WriteCSV(w io.Writer, lib *Library) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return err
+	}
+
+	parameterTypes := make(map[string]ParameterType)
+	for _, parameterType := range lib.ParameterTypes {
+		parameterTypes[key(parameterType.Name)] = parameterType
+	}
+
+	for _, exercise := range lib.Exercises {
+		row := make([]string, len(CSVHeader))
+		row[colExercise] = exercise.Name
+		row[colDescription] = exercise.Description
+		row[colPrimaryMuscleGroups] = strings.Join(exercise.PrimaryMuscleGroups, listSeparator)
+		row[colSecondaryMuscleGroups] = strings.Join(exercise.SecondaryMuscleGroups, listSeparator)
+		row[colEquipment] = strings.Join(exercise.Equipment, listSeparator)
+		row[colLaterality] = exercise.Laterality
+		row[colCategories] = strings.Join(exercise.Categories, listSeparator)
+
+		if len(exercise.Variations) == 0 {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, variation := range exercise.Variations {
+			variationRow := append([]string{}, row...)
+			variationRow[colVariation] = variation.Name
+
+			if len(variation.Parameters) == 0 {
+				if err := writer.Write(variationRow); err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, parameter := range variation.Parameters {
+				parameterRow := append([]string{}, variationRow...)
+				parameterRow[colParameterType] = parameter.ParameterType
+				parameterRow[colLocked] = strconv.FormatBool(parameter.Locked)
+				if parameterType, ok := parameterTypes[key(parameter.ParameterType)]; ok {
+					parameterRow[colDataType] = parameterType.DataType
+					parameterRow[colDefaultUnit] = parameterType.DefaultUnit
+					parameterRow[colMinValue] = formatBound(parameterType.MinValue)
+					parameterRow[colMaxValue] = formatBound(parameterType.MaxValue)
+				}
+				if err := writer.Write(parameterRow); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvParseError(err error) error {
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		return &ParseError{Line: csvErr.Line, Message: csvErr.Err.Error()}
+	}
+	return err
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func parseBound(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &bound, nil
+}
+
+func formatBound(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func indexOf(values []string, target string) int {
+	for i, value := range values {
+		if value == target {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,415 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"backend/internal/types"
+	"backend/internal/utils"
+)
+
+// FormatVersion is the version written by exports and the newest version imports accept
+const FormatVersion = 1
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Conflict policies for exercises that already exist in the target account
+const (
+	ConflictSkip = "skip"
+	ConflictFail = "fail"
+)
+
+// Issue kinds reported by an import plan
+const (
+	IssueInvalid   = "invalid"   // the entry cannot be imported
+	IssueDuplicate = "duplicate" // the entry repeats an earlier one in the same file and is skipped
+	IssueConflict  = "conflict"  // the entry already exists in the account
+	IssueMismatch  = "mismatch"  // an existing parameter type is reused although the file defines it differently
+)
+
+const maxNameLength = 255
+
+// Library is a portable exercise library. Parameter types, categories and exercises
+// are referenced by name so a library can move between accounts and environments.
+type Library struct {
+	Version        int             `json:"version"`
+	ParameterTypes []ParameterType `json:"parameterTypes"`
+	Exercises      []Exercise      `json:"exercises"`
+}
+
+type ParameterType struct {
+	Name        string   `json:"name"`
+	DataType    string   `json:"dataType"`
+	DefaultUnit string   `json:"defaultUnit"`
+	MinValue    *float64 `json:"minValue,omitempty"`
+	MaxValue    *float64 `json:"maxValue,omitempty"`
+}
+
+type Exercise struct {
+	Name                  string      `json:"name"`
+	Description           string      `json:"description"`
+	PrimaryMuscleGroups   []string    `json:"primaryMuscleGroups"`
+	SecondaryMuscleGroups []string    `json:"secondaryMuscleGroups"`
+	Equipment             []string    `json:"equipment"`
+	Laterality            string      `json:"laterality"`
+	Categories            []string    `json:"categories"`
+	Variations            []Variation `json:"variations"`
+	// Line is the first line the exercise appears on in a CSV or plan text file
+	Line int `json:"-"`
+}
+
+type Variation struct {
+	Name       string      `json:"name"`
+	Parameters []Parameter `json:"parameters"`
+}
+
+// Parameter references a parameter type by name
+type Parameter struct {
+	ParameterType string `json:"parameterType"`
+	Locked        bool   `json:"locked"`
+}
+
+// VariationRef names a variation by its exercise, the way portable documents reference it
+type VariationRef struct {
+	Exercise  string
+	Variation string
+}
+
+// ParseError reports a problem with the file itself, located by line where the format allows it
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// Parse reads a library in the given format
+func Parse(r io.Reader, format string) (*Library, error) {
+	switch format {
+	case FormatJSON:
+		return ParseJSON(r)
+	case FormatCSV:
+		return ParseCSV(r)
+	}
+	return nil, fmt.Errorf("unsupported format: %q", format)
+}
+
+// Write serializes a library in the given format
+func Write(w io.Writer, lib *Library, format string) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, lib)
+	case FormatCSV:
+		return WriteCSV(w, lib)
+	}
+	return fmt.Errorf("unsupported format: %q", format)
+}
+
+// ParseJSON reads a library from its JSON form
+func ParseJSON(r io.Reader) (*Library, error) {
+	var lib Library
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&lib); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &ParseError{Message: fmt.Sprintf("invalid JSON at offset %d: %v", syntaxErr.Offset, err)}
+		}
+		return nil, &ParseError{Message: fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	if lib.Version == 0 {
+		return nil, &ParseError{Message: "missing library version"}
+	}
+	if lib.Version > FormatVersion {
+		return nil, &ParseError{Message: fmt.Sprintf("unsupported library version %d", lib.Version)}
+	}
+	return &lib, nil
+}
+
+// WriteJSON writes a library in its JSON form
+func WriteJSON(w io.Writer, lib *Library) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(lib)
+}
+
+// ExistingParameterType is a parameter type already visible to the importing user
+type ExistingParameterType struct {
+	ID int64
+	ParameterType
+}
+
+// Existing describes what the target account already has. Every map is keyed by
+// the lowercased name.
+type Existing struct {
+	Exercises      map[string]int64
+	ParameterTypes map[string]ExistingParameterType
+	Categories     map[string]int64
+}
+
+// ImportPlan is the outcome of checking a library against an account: what would be
+// created, what is reused and every issue found on the way.
+type ImportPlan struct {
+	Exercises            []Exercise
+	ParameterTypes       []ParameterType
+	Categories           []string
+	ReusedParameterTypes []string
+	Issues               []types.LibraryImportIssue
+}
+
+// Blocked reports whether any issue prevents the import from running
+func (p *ImportPlan) Blocked() bool {
+	for _, issue := range p.Issues {
+		if issue.Blocking {
+			return true
+		}
+	}
+	return false
+}
+
+// Report summarizes the plan for API responses
+func (p *ImportPlan) Report(dryRun bool) types.LibraryImportReport {
+	report := types.LibraryImportReport{
+		DryRun:                dryRun,
+		Valid:                 !p.Blocked(),
+		Exercises:             make([]string, len(p.Exercises)),
+		ParameterTypesCreated: make([]string, len(p.ParameterTypes)),
+		ParameterTypesReused:  append([]string{}, p.ReusedParameterTypes...),
+		CategoriesCreated:     append([]string{}, p.Categories...),
+		Issues:                append([]types.LibraryImportIssue{}, p.Issues...),
+	}
+	for i, exercise := range p.Exercises {
+		report.Exercises[i] = exercise.Name
+		report.VariationsCreated += len(exercise.Variations)
+	}
+	for i, parameterType := range p.ParameterTypes {
+		report.ParameterTypesCreated[i] = parameterType.Name
+	}
+	return report
+}
+
+func (p *ImportPlan) addIssue(kind string, blocking bool, exercise *Exercise, format string, args ...any) {
+	issue := types.LibraryImportIssue{
+		Kind:     kind,
+		Blocking: blocking,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if exercise != nil {
+		issue.Exercise = exercise.Name
+		issue.Line = exercise.Line
+	}
+	p.Issues = append(p.Issues, issue)
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Plan checks a library against an account without changing anything. Exercises that
+// repeat earlier entries in the file are skipped, and exercises that already exist are
+// skipped or block the import depending on onConflict.
+func Plan(lib *Library, existing Existing, onConflict string) *ImportPlan {
+	plan := &ImportPlan{}
+
+	// Parameter types defined in the file, keyed by lowercased name
+	defined := make(map[string]ParameterType)
+	reused := make(map[string]bool)
+	for _, parameterType := range lib.ParameterTypes {
+		parameterType.Name = strings.TrimSpace(parameterType.Name)
+		name := key(parameterType.Name)
+		if name == "" {
+			plan.addIssue(IssueInvalid, true, nil, "parameter type is missing a name")
+			continue
+		}
+		if previous, ok := defined[name]; ok {
+			if !sameParameterType(previous, parameterType) {
+				plan.addIssue(IssueInvalid, true, nil, "parameter type %q is defined more than once with different settings", parameterType.Name)
+			}
+			continue
+		}
+		defined[name] = parameterType
+
+		if current, ok := existing.ParameterTypes[name]; ok {
+			reused[name] = true
+			plan.ReusedParameterTypes = append(plan.ReusedParameterTypes, current.Name)
+			if !sameParameterType(current.ParameterType, parameterType) {
+				plan.addIssue(IssueMismatch, false, nil, "parameter type %q already exists as %s (%s); the existing definition is used",
+					current.Name, current.DataType, current.DefaultUnit)
+			}
+			continue
+		}
+		if parameterType.DataType == "" || parameterType.DefaultUnit == "" {
+			plan.addIssue(IssueInvalid, true, nil, "parameter type %q needs a data type and default unit", parameterType.Name)
+			continue
+		}
+		if len(parameterType.Name) > maxNameLength {
+			plan.addIssue(IssueInvalid, true, nil, "parameter type name %q is longer than %d characters", parameterType.Name, maxNameLength)
+			continue
+		}
+		plan.ParameterTypes = append(plan.ParameterTypes, parameterType)
+	}
+
+	seenExercises := make(map[string]bool)
+	newCategories := make(map[string]bool)
+	for i := range lib.Exercises {
+		exercise := lib.Exercises[i]
+		exercise.Name = strings.TrimSpace(exercise.Name)
+		name := key(exercise.Name)
+
+		if name == "" {
+			plan.addIssue(IssueInvalid, true, &exercise, "exercise is missing a name")
+			continue
+		}
+		if len(exercise.Name) > maxNameLength {
+			plan.addIssue(IssueInvalid, true, &exercise, "exercise name is longer than %d characters", maxNameLength)
+			continue
+		}
+		if seenExercises[name] {
+			plan.addIssue(IssueDuplicate, false, &exercise, "exercise %q appears more than once; only the first entry is imported", exercise.Name)
+			continue
+		}
+		seenExercises[name] = true
+
+		if _, ok := existing.Exercises[name]; ok {
+			if onConflict == ConflictFail {
+				plan.addIssue(IssueConflict, true, &exercise, "exercise %q already exists", exercise.Name)
+			} else {
+				plan.addIssue(IssueConflict, false, &exercise, "exercise %q already exists and is skipped", exercise.Name)
+			}
+			continue
+		}
+
+		if !normalizeExercise(plan, &exercise) {
+			continue
+		}
+
+		for _, category := range exercise.Categories {
+			categoryName := key(category)
+			if _, ok := existing.Categories[categoryName]; ok || newCategories[categoryName] {
+				continue
+			}
+			newCategories[categoryName] = true
+			plan.Categories = append(plan.Categories, category)
+		}
+
+		valid := true
+		variations := make([]Variation, 0, len(exercise.Variations))
+		seenVariations := make(map[string]bool)
+		for _, variation := range exercise.Variations {
+			variation.Name = strings.TrimSpace(variation.Name)
+			if seenVariations[key(variation.Name)] {
+				plan.addIssue(IssueDuplicate, false, &exercise, "variation %q appears more than once; only the first entry is imported", variation.Name)
+				continue
+			}
+			seenVariations[key(variation.Name)] = true
+			if len(variation.Name) > maxNameLength {
+				plan.addIssue(IssueInvalid, true, &exercise, "variation name %q is longer than %d characters", variation.Name, maxNameLength)
+				valid = false
+				continue
+			}
+
+			parameters := make([]Parameter, 0, len(variation.Parameters))
+			seenParameters := make(map[string]bool)
+			for _, parameter := range variation.Parameters {
+				parameterName := key(parameter.ParameterType)
+				if seenParameters[parameterName] {
+					plan.addIssue(IssueDuplicate, false, &exercise, "variation %q lists parameter %q more than once", variation.Name, parameter.ParameterType)
+					continue
+				}
+				seenParameters[parameterName] = true
+
+				if current, ok := existing.ParameterTypes[parameterName]; ok {
+					if !reused[parameterName] {
+						reused[parameterName] = true
+						plan.ReusedParameterTypes = append(plan.ReusedParameterTypes, current.Name)
+					}
+				} else if _, ok := defined[parameterName]; !ok {
+					plan.addIssue(IssueInvalid, true, &exercise, "variation %q uses unknown parameter type %q", variation.Name, parameter.ParameterType)
+					valid = false
+					continue
+				}
+				parameters = append(parameters, parameter)
+			}
+			variation.Parameters = parameters
+			variations = append(variations, variation)
+		}
+		exercise.Variations = variations
+
+		if valid {
+			plan.Exercises = append(plan.Exercises, exercise)
+		}
+	}
+
+	return plan
+}
+
+// normalizeExercise validates the exercise metadata against the shared vocabularies,
+// reporting every invalid field
+func normalizeExercise(plan *ImportPlan, exercise *Exercise) bool {
+	valid := true
+	var err error
+
+	if exercise.PrimaryMuscleGroups, err = utils.NormalizeMuscleGroups(exercise.PrimaryMuscleGroups); err != nil {
+		plan.addIssue(IssueInvalid, true, exercise, "%v", err)
+		valid = false
+	}
+	if exercise.SecondaryMuscleGroups, err = utils.NormalizeMuscleGroups(exercise.SecondaryMuscleGroups); err != nil {
+		plan.addIssue(IssueInvalid, true, exercise, "%v", err)
+		valid = false
+	}
+	if exercise.Equipment, err = utils.NormalizeEquipment(exercise.Equipment); err != nil {
+		plan.addIssue(IssueInvalid, true, exercise, "%v", err)
+		valid = false
+	}
+	if exercise.Laterality, err = utils.NormalizeLaterality(exercise.Laterality); err != nil {
+		plan.addIssue(IssueInvalid, true, exercise, "%v", err)
+		valid = false
+	}
+
+	categories := make([]string, 0, len(exercise.Categories))
+	seen := make(map[string]bool)
+	for _, category := range exercise.Categories {
+		category = strings.TrimSpace(category)
+		if category == "" || seen[key(category)] {
+			continue
+		}
+		if len(category) > maxNameLength {
+			plan.addIssue(IssueInvalid, true, exercise, "category name %q is longer than %d characters", category, maxNameLength)
+			valid = false
+			continue
+		}
+		seen[key(category)] = true
+		categories = append(categories, category)
+	}
+	exercise.Categories = categories
+
+	return valid
+}
+
+// sameParameterType compares the settings of two parameter types, ignoring the case of
+// names and units
+func sameParameterType(a ParameterType, b ParameterType) bool {
+	return strings.EqualFold(a.DataType, b.DataType) &&
+		strings.EqualFold(a.DefaultUnit, b.DefaultUnit) &&
+		sameBound(a.MinValue, b.MinValue) &&
+		sameBound(a.MaxValue, b.MaxValue)
+}
+
+func sameBound(a *float64, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,175 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register GIF decoding for thumbnails
+	"image/jpeg"
+	_ "image/png" // register PNG decoding for thumbnails
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	MaxImageSize = 10 << 20  // 10 MiB
+	MaxVideoSize = 200 << 20 // 200 MiB
+
+	ThumbnailMaxDimension = 320
+	// MaxImagePixels bounds the images decoded for thumbnails. A small compressed file
+	// may declare huge dimensions and would take that much memory to decode.
+	MaxImagePixels   = 50_000_000
+	thumbnailQuality = 80
+)
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrFileTooLarge           = errors.New("file too large")
+	ErrImageTooLarge          = errors.New("image dimensions too large")
+)
+
+// allowedContentTypes maps every accepted content type to the file extension used in storage keys
+var allowedContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+// DetectContentType sniffs the content type from the first bytes of a file rather
+// than trusting the client, and checks it against the accepted types
+func DetectContentType(header []byte) (string, error) {
+	contentType := http.DetectContentType(header)
+	if idx := strings.Index(contentType, ";"); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	if _, ok := allowedContentTypes[contentType]; !ok {
+		return contentType, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+	return contentType, nil
+}
+
+// Extension returns the storage file extension for an accepted content type
+func Extension(contentType string) string {
+	return allowedContentTypes[contentType]
+}
+
+func IsImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// MaxSize returns the upload size limit for a content type
+func MaxSize(contentType string) int64 {
+	if IsImage(contentType) {
+		return MaxImageSize
+	}
+	return MaxVideoSize
+}
+
+// ValidateSize checks a file's size against the limit for its content type
+func ValidateSize(contentType string, size int64) error {
+	if limit := MaxSize(contentType); size > limit {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte limit for %s", ErrFileTooLarge, size, limit, contentType)
+	}
+	return nil
+}
+
+// CanThumbnail reports whether a thumbnail can be generated for the content type.
+// The standard library cannot decode WebP, so those images are stored without one.
+func CanThumbnail(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// GenerateThumbnail decodes an image and returns a JPEG scaled down so that its
+// longest side is at most maxDimension. Smaller images keep their size. Images with
+// more than MaxImagePixels pixels are rejected before they are decoded.
+func GenerateThumbnail(r io.Reader, maxDimension int) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImageSize {
+		return nil, fmt.Errorf("%w: images are limited to %d bytes", ErrFileTooLarge, MaxImageSize)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, errors.New("image has no pixels")
+	}
+	if int64(config.Width)*int64(config.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrImageTooLarge, config.Width, config.Height, MaxImagePixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, errors.New("image has no pixels")
+	}
+
+	thumbWidth, thumbHeight := width, height
+	if width > maxDimension || height > maxDimension {
+		if width >= height {
+			thumbWidth = maxDimension
+			thumbHeight = max(1, height*maxDimension/width)
+		} else {
+			thumbHeight = maxDimension
+			thumbWidth = max(1, width*maxDimension/height)
+		}
+	}
+
+	thumb := scale(src, thumbWidth, thumbHeight)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scale resizes with a box filter: each destination pixel is the average of the
+// source pixels it covers
+func scale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count >> 8)
+			dst.Pix[offset+1] = uint8(g / count >> 8)
+			dst.Pix[offset+2] = uint8(b / count >> 8)
+			dst.Pix[offset+3] = uint8(a / count >> 8)
+		}
+	}
+	return dst
+}
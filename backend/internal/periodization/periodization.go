@@ -0,0 +1,329 @@
+// Package periodization generates plans week by week from a goal and a periodization
+// model. Plans come out as plan documents, so they are created like any import and stay
+// editable afterwards.
+package periodization
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"backend/internal/plandoc"
+	"backend/internal/progression"
+)
+
+// Periodization models
+const (
+	// ModelLinear progresses the same sessions week after week
+	ModelLinear = "linear"
+	// ModelUndulating rotates heavy, light and moderate sessions within each week
+	ModelUndulating = "undulating"
+	// ModelBlock moves through accumulation, transmutation and realization blocks
+	ModelBlock = "block"
+)
+
+var Models = []string{ModelLinear, ModelUndulating, ModelBlock}
+
+// Scheme is how an exercise is prescribed. Load scales the exercise's starting load.
+type Scheme struct {
+	Sets int32
+	Reps int32
+	RPE  int32
+	Rest string
+	Load float64
+}
+
+// Goals maps each goal to the scheme its training weeks start from
+var Goals = map[string]Scheme{
+	"strength":    {Sets: 5, Reps: 5, RPE: 8, Rest: "3 minutes", Load: 1},
+	"hypertrophy": {Sets: 4, Reps: 10, RPE: 7, Rest: "1 minute 30 seconds", Load: 1},
+	"endurance":   {Sets: 3, Reps: 15, RPE: 6, Rest: "1 minute", Load: 1},
+	"power":       {Sets: 5, Reps: 3, RPE: 7, Rest: "3 minutes", Load: 1},
+}
+
+// emphasis shifts a scheme towards heavier or lighter work
+type emphasis struct {
+	Name string
+	Sets int32
+	Reps float64
+	RPE  int32
+	Load float64
+}
+
+var (
+	heavy    = emphasis{Name: "Heavy", Reps: 0.6, RPE: 1, Load: 1.075}
+	moderate = emphasis{Name: "Moderate", Reps: 1, Load: 1}
+	light    = emphasis{Name: "Light", Reps: 1.4, RPE: -1, Load: 0.85}
+
+	// Undulating weeks cycle through these days
+	undulatingDays = []emphasis{heavy, light, moderate}
+
+	accumulation  = emphasis{Name: "Accumulation", Sets: 1, Reps: 1.5, RPE: -1, Load: 0.85}
+	transmutation = emphasis{Name: "Transmutation", Reps: 1, Load: 1}
+	realization   = emphasis{Name: "Realization", Sets: -1, Reps: 0.6, RPE: 1, Load: 1.075}
+)
+
+func (e emphasis) apply(scheme Scheme) Scheme {
+	return Scheme{
+		Sets: max(scheme.Sets+e.Sets, 1),
+		Reps: max(progression.Whole(float64(scheme.Reps)*e.Reps), 1),
+		RPE:  min(max(scheme.RPE+e.RPE, 1), 10),
+		Rest: scheme.Rest,
+		Load: scheme.Load * e.Load,
+	}
+}
+
+// Exercise is a variation to program. With a load parameter, e.g. "Weight", and a
+// starting load, each set prescribes the load and the load progresses; otherwise RPE
+// and reps do.
+type Exercise struct {
+	Exercise      string
+	Variation     string
+	LoadParameter string
+	Load          float64
+}
+
+type Config struct {
+	Name            string
+	Description     string
+	Goal            string
+	Model           string
+	Weeks           int
+	SessionsPerWeek int
+	// DeloadEvery makes every nth week a deload, 0 for none
+	DeloadEvery int
+	Exercises   []Exercise
+}
+
+// MaxWeeks bounds how long a generated plan can be
+const MaxWeeks = 52
+
+func (c Config) trainingWeeks() int {
+	if c.DeloadEvery == 0 {
+		return c.Weeks
+	}
+	return c.Weeks - c.Weeks/c.DeloadEvery
+}
+
+// Validate checks a configuration before anything is generated
+func Validate(c Config) error {
+	if _, ok := Goals[c.Goal]; !ok {
+		return fmt.Errorf("unknown goal %q", c.Goal)
+	}
+	if !slices.Contains(Models, c.Model) {
+		return fmt.Errorf("unknown periodization model %q", c.Model)
+	}
+	if c.Weeks < 1 || c.Weeks > MaxWeeks {
+		return fmt.Errorf("weeks must be between 1 and %d", MaxWeeks)
+	}
+	if c.SessionsPerWeek < 1 || c.SessionsPerWeek > 7 {
+		return errors.New("sessions per week must be between 1 and 7")
+	}
+	if c.DeloadEvery == 1 || c.DeloadEvery < 0 {
+		return errors.New("deloads can be every 2 weeks at most")
+	}
+	if c.Model == ModelUndulating && c.SessionsPerWeek < 2 {
+		return errors.New("undulating periodization needs at least 2 sessions per week")
+	}
+	if c.Model == ModelBlock && c.trainingWeeks() < 3 {
+		return errors.New("block periodization needs at least 3 training weeks")
+	}
+	if len(c.Exercises) == 0 {
+		return errors.New("at least one exercise is required")
+	}
+	for _, exercise := range c.Exercises {
+		if (exercise.LoadParameter == "") != (exercise.Load == 0) {
+			return fmt.Errorf("%q needs both a load parameter and a load, or neither", exercise.Variation)
+		}
+		if exercise.Load < 0 {
+			return fmt.Errorf("%q cannot start from a negative load", exercise.Variation)
+		}
+	}
+	return nil
+}
+
+// Steps are the weekly progression: loaded exercises add 2.5% load, others add RPE up
+// to 9 and then a rep per week
+func Steps(loaded bool) []progression.Step {
+	if loaded {
+		return []progression.Step{{Target: progression.TargetParameter, ParameterTypeID: loadKey, Amount: 2.5, Unit: progression.UnitPercent}}
+	}
+	until := 9.0
+	return []progression.Step{
+		{Target: progression.TargetRPE, Amount: 0.5, Unit: progression.UnitAbsolute, Until: &until},
+		{Target: progression.TargetReps, Amount: 1, Unit: progression.UnitAbsolute},
+	}
+}
+
+// loadKey stands in for the load parameter while progressing
+const loadKey = 1
+
+// block is a phase of a block plan, in training weeks
+type block struct {
+	emphasis emphasis
+	weeks    int
+}
+
+// blocks splits the training weeks into accumulation, transmutation and a shorter
+// realization block
+func blocks(trainingWeeks int) []block {
+	realizationWeeks := max(1, trainingWeeks/5)
+	accumulationWeeks := (trainingWeeks - realizationWeeks + 1) / 2
+	return []block{
+		{accumulation, accumulationWeeks},
+		{transmutation, trainingWeeks - realizationWeeks - accumulationWeeks},
+		{realization, realizationWeeks},
+	}
+}
+
+// session is a group of the plan with the emphasis of its scheme
+type session struct {
+	group     plandoc.Group
+	emphasis  emphasis
+	frequency int32
+}
+
+func sessions(c Config) []session {
+	if c.Model != ModelUndulating {
+		return []session{{
+			group:     plandoc.Group{Key: "session", Name: "Full body", Description: fmt.Sprintf("%d sessions per week", c.SessionsPerWeek)},
+			emphasis:  moderate,
+			frequency: int32(c.SessionsPerWeek),
+		}}
+	}
+	result := make([]session, c.SessionsPerWeek)
+	for i := range result {
+		day := undulatingDays[i%len(undulatingDays)]
+		result[i] = session{
+			group:     plandoc.Group{Key: fmt.Sprintf("day-%d", i+1), Name: fmt.Sprintf("Day %d: %s", i+1, day.Name), Description: day.Name + " session"},
+			emphasis:  day,
+			frequency: 1,
+		}
+	}
+	return result
+}
+
+// prescribe writes out a scheme for an exercise after the given number of progression
+// steps
+func prescribe(exercise Exercise, scheme Scheme, step int) plandoc.Prescription {
+	loaded := exercise.LoadParameter != ""
+	load := progression.Key(progression.TargetParameter, loadKey)
+	values := progression.Apply(Steps(loaded), progression.Values{
+		progression.TargetReps: float64(scheme.Reps),
+		progression.TargetRPE:  float64(scheme.RPE),
+		load:                   exercise.Load * scheme.Load,
+	}, step)
+
+	reps := progression.Whole(values[progression.TargetReps])
+	rpe := min(progression.Whole(values[progression.TargetRPE]), 10)
+	rest := scheme.Rest
+	prescription := plandoc.Prescription{
+		Exercise:  exercise.Exercise,
+		Variation: exercise.Variation,
+		Sets:      scheme.Sets,
+		Reps:      &reps,
+		RPE:       &rpe,
+		Rest:      &rest,
+	}
+	if loaded {
+		// Loads are rounded to the nearest half unit
+		value := math.Round(values[load]*2) / 2
+		prescription.SetScheme = make([]plandoc.Set, scheme.Sets)
+		for i := range prescription.SetScheme {
+			prescription.SetScheme[i] = plandoc.Set{Parameters: []plandoc.SetParameter{{ParameterType: exercise.LoadParameter, Value: value}}}
+		}
+	}
+	return prescription
+}
+
+// deload reduces a week's scheme by the default deload without progressing it
+func deload(scheme Scheme) Scheme {
+	reduction := progression.DefaultDeload
+	return Scheme{
+		Sets: reduction.SetCount(scheme.Sets),
+		Reps: scheme.Reps,
+		RPE:  max(scheme.RPE-int32(reduction.RPE), 1),
+		Rest: scheme.Rest,
+		Load: scheme.Load * (1 - reduction.Load/100),
+	}
+}
+
+// Generate builds the plan one week-long interval at a time. The document's exercises
+// and parameter types are left for the caller to fill in from its library.
+func Generate(c Config) (*plandoc.Document, error) {
+	if err := Validate(c); err != nil {
+		return nil, err
+	}
+
+	goal := Goals[c.Goal]
+	planSessions := sessions(c)
+	doc := &plandoc.Document{
+		Format:  plandoc.Format,
+		Version: plandoc.FormatVersion,
+		Plan: plandoc.Plan{
+			Name:        c.Name,
+			Description: c.Description,
+		},
+		Groups:    make([]plandoc.Group, len(planSessions)),
+		Intervals: make([]plandoc.Interval, 0, c.Weeks),
+	}
+	for i, s := range planSessions {
+		doc.Groups[i] = s.group
+	}
+	if doc.Plan.Name == "" {
+		doc.Plan.Name = fmt.Sprintf("%d-week %s %s plan", c.Weeks, c.Model, c.Goal)
+	}
+
+	var phases []block
+	if c.Model == ModelBlock {
+		phases = blocks(c.trainingWeeks())
+	}
+
+	// step counts training weeks since the start of the plan, or of the current block
+	step, phase := 0, 0
+	for week := 1; week <= c.Weeks; week++ {
+		isDeload := c.DeloadEvery > 0 && week%c.DeloadEvery == 0
+		interval := plandoc.Interval{
+			Name:     fmt.Sprintf("Week %d", week),
+			Duration: "1 week",
+			Order:    int32(week),
+			Groups:   make([]plandoc.IntervalGroup, len(planSessions)),
+		}
+
+		base := goal
+		if phases != nil {
+			if !isDeload && step == phases[phase].weeks {
+				phase, step = phase+1, 0
+			}
+			base = phases[phase].emphasis.apply(goal)
+			interval.Name += ": " + phases[phase].emphasis.Name
+		}
+		// A deload repeats the last training week at reduced volume and intensity
+		progressed := step
+		if isDeload {
+			progressed = max(step-1, 0)
+			interval.Name += " (deload)"
+			interval.Description = "Reduced volume and intensity to recover"
+			interval.Deload = true
+		}
+
+		for i, s := range planSessions {
+			scheme := s.emphasis.apply(base)
+			if isDeload {
+				scheme = deload(scheme)
+			}
+			group := plandoc.IntervalGroup{Group: s.group.Key, Frequency: s.frequency, Prescriptions: make([]plandoc.Prescription, len(c.Exercises))}
+			for j, exercise := range c.Exercises {
+				group.Prescriptions[j] = prescribe(exercise, scheme, progressed)
+			}
+			interval.Groups[i] = group
+		}
+		doc.Intervals = append(doc.Intervals, interval)
+
+		if !isDeload {
+			step++
+		}
+	}
+	return doc, nil
+}
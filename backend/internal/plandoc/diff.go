@@ -0,0 +1,304 @@
+package plandoc
+
+import "reflect"
+
+// FieldChange is a value that differs between two documents. Missing values are nil.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// Changes is what changed in a plan between two documents. Intervals are matched by the
+// ID they were recorded from when both documents carry them and by order otherwise,
+// groups within an interval by name and prescriptions within a group by exercise and
+// variation, in the order they are performed.
+type Changes struct {
+	Plan             []FieldChange     `json:"plan"`
+	IntervalsAdded   []IntervalRef     `json:"intervalsAdded"`
+	IntervalsRemoved []IntervalRef     `json:"intervalsRemoved"`
+	IntervalsChanged []IntervalChanges `json:"intervalsChanged"`
+}
+
+type IntervalRef struct {
+	ID    int64  `json:"id,omitempty"`
+	Order int32  `json:"order"`
+	Name  string `json:"name"`
+}
+
+// IntervalChanges are the changes to an interval, with the ID it has in the document
+// changed from and the order it has in the document changed to
+type IntervalChanges struct {
+	ID                   int64                 `json:"id,omitempty"`
+	Order                int32                 `json:"order"`
+	Name                 string                `json:"name"`
+	Fields               []FieldChange         `json:"fields"`
+	GroupsAdded          []string              `json:"groupsAdded"`
+	GroupsRemoved        []string              `json:"groupsRemoved"`
+	GroupsChanged        []GroupChanges        `json:"groupsChanged"`
+	PrescriptionsAdded   []PrescriptionRef     `json:"prescriptionsAdded"`
+	PrescriptionsRemoved []PrescriptionRef     `json:"prescriptionsRemoved"`
+	PrescriptionsChanged []PrescriptionChanges `json:"prescriptionsChanged"`
+}
+
+// GroupChanges are the changes to how an interval schedules a group
+type GroupChanges struct {
+	Group  string        `json:"group"`
+	Fields []FieldChange `json:"fields"`
+}
+
+type PrescriptionRef struct {
+	Group     string `json:"group"`
+	Exercise  string `json:"exercise"`
+	Variation string `json:"variation"`
+}
+
+type PrescriptionChanges struct {
+	Group     string        `json:"group"`
+	Exercise  string        `json:"exercise"`
+	Variation string        `json:"variation"`
+	Fields    []FieldChange `json:"fields"`
+}
+
+// Empty tells whether the documents describe the same plan
+func (c *Changes) Empty() bool {
+	return len(c.Plan) == 0 && len(c.IntervalsAdded) == 0 && len(c.IntervalsRemoved) == 0 && len(c.IntervalsChanged) == 0
+}
+
+// optional dereferences an optional value so that equal values compare equal
+func optional[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// fields collects the changes of values that are compared one by one
+type fields []FieldChange
+
+func (f *fields) compare(field string, from any, to any) {
+	if isEmpty(from) && isEmpty(to) {
+		return
+	}
+	if !reflect.DeepEqual(from, to) {
+		*f = append(*f, FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+// isEmpty treats missing values and empty lists alike
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Slice && v.Len() == 0
+}
+
+// Diff returns the changes that turn the document from into the document to
+func Diff(from *Document, to *Document) *Changes {
+	changes := &Changes{
+		Plan:             []FieldChange{},
+		IntervalsAdded:   []IntervalRef{},
+		IntervalsRemoved: []IntervalRef{},
+		IntervalsChanged: []IntervalChanges{},
+	}
+
+	plan := fields{}
+	plan.compare("name", from.Plan.Name, to.Plan.Name)
+	plan.compare("description", from.Plan.Description, to.Plan.Description)
+	plan.compare("isTemplate", from.Plan.IsTemplate, to.Plan.IsTemplate)
+	plan.compare("isPublic", from.Plan.IsPublic, to.Plan.IsPublic)
+	changes.Plan = plan
+
+	intervalKey := intervalKeys(from, to)
+	toIntervals := make(map[int64]*Interval, len(to.Intervals))
+	for i := range to.Intervals {
+		toIntervals[intervalKey(&to.Intervals[i])] = &to.Intervals[i]
+	}
+	fromIntervals := make(map[int64]bool, len(from.Intervals))
+	for i := range from.Intervals {
+		before := &from.Intervals[i]
+		fromIntervals[intervalKey(before)] = true
+		after, ok := toIntervals[intervalKey(before)]
+		if !ok {
+			changes.IntervalsRemoved = append(changes.IntervalsRemoved, IntervalRef{ID: before.ID, Order: before.Order, Name: before.Name})
+			continue
+		}
+		if intervalChanges := diffInterval(from, before, to, after); intervalChanges != nil {
+			changes.IntervalsChanged = append(changes.IntervalsChanged, *intervalChanges)
+		}
+	}
+	for i := range to.Intervals {
+		after := &to.Intervals[i]
+		if !fromIntervals[intervalKey(after)] {
+			changes.IntervalsAdded = append(changes.IntervalsAdded, IntervalRef{ID: after.ID, Order: after.Order, Name: after.Name})
+		}
+	}
+	return changes
+}
+
+// intervalKeys returns what identifies an interval in both documents: the ID it was
+// recorded from when every interval has one, otherwise its order
+func intervalKeys(from *Document, to *Document) func(interval *Interval) int64 {
+	for _, doc := range []*Document{from, to} {
+		for _, interval := range doc.Intervals {
+			if interval.ID == 0 {
+				return func(interval *Interval) int64 { return int64(interval.Order) }
+			}
+		}
+	}
+	return func(interval *Interval) int64 { return interval.ID }
+}
+
+// groupNames maps the group keys of a document to the groups' names
+func groupNames(doc *Document) map[string]string {
+	names := make(map[string]string, len(doc.Groups))
+	for _, group := range doc.Groups {
+		names[group.Key] = group.Name
+	}
+	return names
+}
+
+// prescriptionKey identifies a prescription within its group; n counts earlier
+// prescriptions of the same variation
+type prescriptionKey struct {
+	exercise  string
+	variation string
+	n         int
+}
+
+func prescriptionKeys(prescriptions []Prescription) []prescriptionKey {
+	keys := make([]prescriptionKey, len(prescriptions))
+	seen := make(map[prescriptionKey]int)
+	for i, prescription := range prescriptions {
+		k := prescriptionKey{exercise: key(prescription.Exercise), variation: key(prescription.Variation)}
+		keys[i] = prescriptionKey{exercise: k.exercise, variation: k.variation, n: seen[k]}
+		seen[k]++
+	}
+	return keys
+}
+
+// diffInterval returns the changes to an interval, or nil when it is unchanged
+func diffInterval(fromDoc *Document, from *Interval, toDoc *Document, to *Interval) *IntervalChanges {
+	changes := &IntervalChanges{
+		ID:                   from.ID,
+		Order:                to.Order,
+		Name:                 to.Name,
+		GroupsAdded:          []string{},
+		GroupsRemoved:        []string{},
+		GroupsChanged:        []GroupChanges{},
+		PrescriptionsAdded:   []PrescriptionRef{},
+		PrescriptionsRemoved: []PrescriptionRef{},
+		PrescriptionsChanged: []PrescriptionChanges{},
+	}
+
+	interval := fields{}
+	interval.compare("name", from.Name, to.Name)
+	interval.compare("order", from.Order, to.Order)
+	interval.compare("description", from.Description, to.Description)
+	interval.compare("duration", from.Duration, to.Duration)
+	interval.compare("deload", from.Deload, to.Deload)
+
+	fromNames, toNames := groupNames(fromDoc), groupNames(toDoc)
+	toGroups := make(map[string]*IntervalGroup, len(to.Groups))
+	for i := range to.Groups {
+		toGroups[key(toNames[to.Groups[i].Group])] = &to.Groups[i]
+	}
+	fromGroups := make(map[string]bool, len(from.Groups))
+	// fromOrder and toOrder are the groups in both documents in their order in each
+	var fromOrder, toOrder []string
+	for i := range from.Groups {
+		before := &from.Groups[i]
+		name := fromNames[before.Group]
+		fromGroups[key(name)] = true
+		after, ok := toGroups[key(name)]
+		if !ok {
+			changes.GroupsRemoved = append(changes.GroupsRemoved, name)
+			for _, prescription := range before.Prescriptions {
+				changes.PrescriptionsRemoved = append(changes.PrescriptionsRemoved, PrescriptionRef{Group: name, Exercise: prescription.Exercise, Variation: prescription.Variation})
+			}
+			continue
+		}
+
+		fromOrder = append(fromOrder, name)
+		group := fields{}
+		group.compare("frequency", before.Frequency, after.Frequency)
+		group.compare("pinnedDays", before.PinnedDays, after.PinnedDays)
+		group.compare("blocks", before.Blocks, after.Blocks)
+		prescriptionsBefore, prescriptionsAfter := diffPrescriptions(changes, name, before.Prescriptions, after.Prescriptions)
+		group.compare("prescriptionOrder", prescriptionsBefore, prescriptionsAfter)
+		if len(group) > 0 {
+			changes.GroupsChanged = append(changes.GroupsChanged, GroupChanges{Group: name, Fields: group})
+		}
+	}
+	for _, after := range to.Groups {
+		name := toNames[after.Group]
+		if fromGroups[key(name)] {
+			toOrder = append(toOrder, name)
+			continue
+		}
+		changes.GroupsAdded = append(changes.GroupsAdded, name)
+		for _, prescription := range after.Prescriptions {
+			changes.PrescriptionsAdded = append(changes.PrescriptionsAdded, PrescriptionRef{Group: name, Exercise: prescription.Exercise, Variation: prescription.Variation})
+		}
+	}
+
+	interval.compare("groupOrder", fromOrder, toOrder)
+	changes.Fields = interval
+
+	if len(changes.Fields) == 0 && len(changes.GroupsAdded) == 0 && len(changes.GroupsRemoved) == 0 && len(changes.GroupsChanged) == 0 &&
+		len(changes.PrescriptionsAdded) == 0 && len(changes.PrescriptionsRemoved) == 0 && len(changes.PrescriptionsChanged) == 0 {
+		return nil
+	}
+	return changes
+}
+
+// diffPrescriptions adds the changes to the prescriptions of a group and returns the
+// prescriptions in both documents in their order in each
+func diffPrescriptions(changes *IntervalChanges, group string, from []Prescription, to []Prescription) ([]string, []string) {
+	toKeys := prescriptionKeys(to)
+	toIndexes := make(map[prescriptionKey]int, len(to))
+	for i, k := range toKeys {
+		toIndexes[k] = i
+	}
+	matched := make(map[int]bool, len(to))
+	var fromOrder, toOrder []string
+	for i, k := range prescriptionKeys(from) {
+		before := from[i]
+		j, ok := toIndexes[k]
+		if !ok {
+			changes.PrescriptionsRemoved = append(changes.PrescriptionsRemoved, PrescriptionRef{Group: group, Exercise: before.Exercise, Variation: before.Variation})
+			continue
+		}
+		matched[j] = true
+		after := to[j]
+		fromOrder = append(fromOrder, before.Exercise+" ("+before.Variation+")")
+
+		prescription := fields{}
+		prescription.compare("sets", before.Sets, after.Sets)
+		prescription.compare("reps", optional(before.Reps), optional(after.Reps))
+		prescription.compare("rpe", optional(before.RPE), optional(after.RPE))
+		prescription.compare("duration", optional(before.Duration), optional(after.Duration))
+		prescription.compare("subReps", optional(before.SubReps), optional(after.SubReps))
+		prescription.compare("subRepWorkDuration", optional(before.SubRepWorkDuration), optional(after.SubRepWorkDuration))
+		prescription.compare("subRepRestDuration", optional(before.SubRepRestDuration), optional(after.SubRepRestDuration))
+		prescription.compare("rest", optional(before.Rest), optional(after.Rest))
+		prescription.compare("setScheme", before.SetScheme, after.SetScheme)
+		if len(prescription) > 0 {
+			changes.PrescriptionsChanged = append(changes.PrescriptionsChanged, PrescriptionChanges{
+				Group:     group,
+				Exercise:  after.Exercise,
+				Variation: after.Variation,
+				Fields:    prescription,
+			})
+		}
+	}
+	for j, after := range to {
+		if matched[j] {
+			toOrder = append(toOrder, after.Exercise+" ("+after.Variation+")")
+			continue
+		}
+		changes.PrescriptionsAdded = append(changes.PrescriptionsAdded, PrescriptionRef{Group: group, Exercise: after.Exercise, Variation: after.Variation})
+	}
+	return fromOrder, toOrder
+}
@@ -0,0 +1,359 @@
+package plandoc
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"backend/internal/library"
+	"backend/internal/utils"
+)
+
+// Format identifies plan documents, so other JSON files are rejected early
+const Format = "rocket-plan"
+
+// FormatVersion is the version written by exports and the newest version imports accept
+const FormatVersion = 1
+
+// Schema is the published JSON schema for plan documents
+//
+//go:embed plan.schema.json
+var Schema []byte
+
+// Document is a self-contained, portable plan. Groups are referenced by key and
+// exercises by name, so nothing in it depends on database IDs.
+type Document struct {
+	Format         string                  `json:"format"`
+	Version        int                     `json:"version"`
+	ExportedAt     string                  `json:"exportedAt,omitempty"`
+	Plan           Plan                    `json:"plan"`
+	ParameterTypes []library.ParameterType `json:"parameterTypes"`
+	Exercises      []library.Exercise      `json:"exercises"`
+	Groups         []Group                 `json:"groups"`
+	Intervals      []Interval              `json:"intervals"`
+}
+
+type Plan struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsTemplate  bool   `json:"isTemplate"`
+	IsPublic    bool   `json:"isPublic"`
+}
+
+// Group is a workout that intervals schedule. Key is unique within the document. ID is
+// only set in the versions of a plan, like the IDs of intervals.
+type Group struct {
+	ID          int64  `json:"id,omitempty"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Interval durations use the same vocabulary as the API, e.g. "2 weeks" or "1 week 3 days".
+// Deload marks an interval of reduced volume and intensity. ID is only set in the versions
+// of a plan, to the interval they were recorded from; exports leave it out.
+type Interval struct {
+	ID          int64           `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Duration    string          `json:"duration"`
+	Order       int32           `json:"order"`
+	Deload      bool            `json:"deload,omitempty"`
+	Groups      []IntervalGroup `json:"groups"`
+}
+
+// IntervalGroup assigns a group to an interval together with what it prescribes there.
+// Groups are listed in their order within the interval and prescriptions in the order
+// they are performed. PinnedDays are weekday names, e.g. "tuesday", the group is always
+// held on.
+type IntervalGroup struct {
+	Group         string         `json:"group"`
+	Frequency     int32          `json:"frequency"`
+	PinnedDays    []string       `json:"pinnedDays,omitempty"`
+	Prescriptions []Prescription `json:"prescriptions"`
+	Blocks        []Block        `json:"blocks,omitempty"`
+}
+
+// BlockKinds are the ways prescriptions can be performed together
+var BlockKinds = []string{"superset", "circuit"}
+
+// Block links consecutive prescriptions of an interval group into a superset or circuit.
+// Prescriptions are indexes into the group's prescriptions; Rest follows each round.
+type Block struct {
+	Kind          string  `json:"kind"`
+	Rest          *string `json:"rest,omitempty"`
+	Prescriptions []int   `json:"prescriptions"`
+}
+
+// Weekdays are the names pinned days are written with, indexed like time.Weekday
+var Weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// WeekdayNumber returns the time.Weekday number of a weekday name
+func WeekdayNumber(name string) (int32, bool) {
+	for i, weekday := range Weekdays {
+		if strings.EqualFold(name, weekday) {
+			return int32(i), true
+		}
+	}
+	return 0, false
+}
+
+// Prescription references a variation by exercise and variation name. Durations use
+// the interval vocabulary, e.g. "1 minute 30 seconds".
+type Prescription struct {
+	Exercise           string  `json:"exercise"`
+	Variation          string  `json:"variation"`
+	Sets               int32   `json:"sets"`
+	Reps               *int32  `json:"reps,omitempty"`
+	RPE                *int32  `json:"rpe,omitempty"`
+	Duration           *string `json:"duration,omitempty"`
+	SubReps            *int32  `json:"subReps,omitempty"`
+	SubRepWorkDuration *string `json:"subRepWorkDuration,omitempty"`
+	SubRepRestDuration *string `json:"subRepRestDuration,omitempty"`
+	Rest               *string `json:"rest,omitempty"`
+	// SetScheme breaks the prescription down set by set. Sets must equal its length.
+	SetScheme []Set `json:"setScheme,omitempty"`
+}
+
+// Set overrides the prescription's values for one set. Parameters reference the
+// variation's parameter types by name.
+type Set struct {
+	Reps       *int32         `json:"reps,omitempty"`
+	Duration   *string        `json:"duration,omitempty"`
+	RPE        *int32         `json:"rpe,omitempty"`
+	Rest       *string        `json:"rest,omitempty"`
+	Parameters []SetParameter `json:"parameters,omitempty"`
+}
+
+// SetParameter is a value for a parameter type, or with Percentage a percentage of the
+// athlete's benchmark for it
+type SetParameter struct {
+	ParameterType string  `json:"parameterType"`
+	Value         float64 `json:"value"`
+	Percentage    bool    `json:"percentage,omitempty"`
+}
+
+// Parse reads a plan document and checks its format and version
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	if doc.Format != Format {
+		return nil, fmt.Errorf("not a plan document: format must be %q", Format)
+	}
+	if doc.Version == 0 {
+		return nil, errors.New("missing document version")
+	}
+	if doc.Version > FormatVersion {
+		return nil, fmt.Errorf("unsupported document version %d", doc.Version)
+	}
+	return &doc, nil
+}
+
+// Write writes a plan document as indented JSON
+func Write(w io.Writer, doc *Document) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Validate checks that every reference in the document resolves within the document
+// and that all values can be stored. It returns every problem found.
+func Validate(doc *Document) []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if strings.TrimSpace(doc.Plan.Name) == "" {
+		fail("plan is missing a name")
+	}
+
+	parameterTypes := make(map[string]bool)
+	for _, parameterType := range doc.ParameterTypes {
+		if parameterType.Name == "" || parameterType.DataType == "" || parameterType.DefaultUnit == "" {
+			fail("parameter type %q needs a name, data type and default unit", parameterType.Name)
+		}
+		parameterTypes[key(parameterType.Name)] = true
+	}
+
+	// variations maps exercise and variation names to the variation's parameter types
+	variations := make(map[string]map[string]map[string]bool)
+	for _, exercise := range doc.Exercises {
+		if strings.TrimSpace(exercise.Name) == "" {
+			fail("exercise is missing a name")
+			continue
+		}
+		if _, ok := variations[key(exercise.Name)]; ok {
+			fail("exercise %q is defined more than once", exercise.Name)
+			continue
+		}
+		if _, err := utils.NormalizeMuscleGroups(exercise.PrimaryMuscleGroups); err != nil {
+			fail("exercise %q: %v", exercise.Name, err)
+		}
+		if _, err := utils.NormalizeMuscleGroups(exercise.SecondaryMuscleGroups); err != nil {
+			fail("exercise %q: %v", exercise.Name, err)
+		}
+		if _, err := utils.NormalizeEquipment(exercise.Equipment); err != nil {
+			fail("exercise %q: %v", exercise.Name, err)
+		}
+		if _, err := utils.NormalizeLaterality(exercise.Laterality); err != nil {
+			fail("exercise %q: %v", exercise.Name, err)
+		}
+
+		names := make(map[string]map[string]bool)
+		for _, variation := range exercise.Variations {
+			if _, ok := names[key(variation.Name)]; ok {
+				fail("exercise %q defines variation %q more than once", exercise.Name, variation.Name)
+			}
+			names[key(variation.Name)] = make(map[string]bool)
+			for _, parameter := range variation.Parameters {
+				if !parameterTypes[key(parameter.ParameterType)] {
+					fail("variation %q of exercise %q uses undefined parameter type %q", variation.Name, exercise.Name, parameter.ParameterType)
+				}
+				names[key(variation.Name)][key(parameter.ParameterType)] = true
+			}
+		}
+		variations[key(exercise.Name)] = names
+	}
+
+	groups := make(map[string]bool)
+	for _, group := range doc.Groups {
+		if group.Key == "" {
+			fail("group %q is missing a key", group.Name)
+			continue
+		}
+		if groups[group.Key] {
+			fail("group key %q is used more than once", group.Key)
+		}
+		groups[group.Key] = true
+		if strings.TrimSpace(group.Name) == "" {
+			fail("group %q is missing a name", group.Key)
+		}
+	}
+
+	orders := make(map[int32]bool)
+	for i, interval := range doc.Intervals {
+		label := fmt.Sprintf("interval %d", i+1)
+		if interval.Name != "" {
+			label = fmt.Sprintf("interval %q", interval.Name)
+		}
+
+		if orders[interval.Order] {
+			fail("%s reuses order %d", label, interval.Order)
+		}
+		orders[interval.Order] = true
+		if _, err := utils.StringToInterval(interval.Duration); err != nil {
+			fail("%s has an invalid duration %q", label, interval.Duration)
+		}
+
+		assigned := make(map[string]bool)
+		for _, intervalGroup := range interval.Groups {
+			if !groups[intervalGroup.Group] {
+				fail("%s references unknown group %q", label, intervalGroup.Group)
+				continue
+			}
+			if assigned[intervalGroup.Group] {
+				fail("%s assigns group %q more than once", label, intervalGroup.Group)
+			}
+			assigned[intervalGroup.Group] = true
+			if intervalGroup.Frequency < 1 {
+				fail("%s gives group %q a frequency below 1", label, intervalGroup.Group)
+			}
+			pinned := make(map[int32]bool)
+			for _, day := range intervalGroup.PinnedDays {
+				number, ok := WeekdayNumber(day)
+				if !ok {
+					fail("%s pins group %q to unknown weekday %q", label, intervalGroup.Group, day)
+				} else if pinned[number] {
+					fail("%s pins group %q to %s more than once", label, intervalGroup.Group, day)
+				}
+				pinned[number] = true
+			}
+			if len(intervalGroup.PinnedDays) > 0 && int(intervalGroup.Frequency) > len(intervalGroup.PinnedDays) {
+				fail("%s gives group %q a frequency above its %d pinned days", label, intervalGroup.Group, len(intervalGroup.PinnedDays))
+			}
+
+			linked := make(map[int]bool)
+			for _, block := range intervalGroup.Blocks {
+				if !slices.Contains(BlockKinds, block.Kind) {
+					fail("%s links prescriptions of group %q into unknown kind %q", label, intervalGroup.Group, block.Kind)
+				}
+				if block.Rest != nil {
+					if _, err := utils.StringToInterval(*block.Rest); err != nil {
+						fail("%s gives a %s of group %q an invalid rest %q", label, block.Kind, intervalGroup.Group, *block.Rest)
+					}
+				}
+				if len(block.Prescriptions) < 2 {
+					fail("%s links fewer than 2 prescriptions of group %q into a %s", label, intervalGroup.Group, block.Kind)
+				}
+				for i, index := range block.Prescriptions {
+					if index < 0 || index >= len(intervalGroup.Prescriptions) {
+						fail("%s links prescription %d of group %q, which does not exist", label, index, intervalGroup.Group)
+					} else if linked[index] {
+						fail("%s links prescription %d of group %q more than once", label, index, intervalGroup.Group)
+					} else if i > 0 && index != block.Prescriptions[i-1]+1 {
+						fail("%s links prescriptions of group %q that do not follow each other", label, intervalGroup.Group)
+					}
+					linked[index] = true
+				}
+			}
+
+			for _, prescription := range intervalGroup.Prescriptions {
+				names, ok := variations[key(prescription.Exercise)]
+				if !ok {
+					fail("%s prescribes undefined exercise %q", label, prescription.Exercise)
+					continue
+				}
+				variationParameters, ok := names[key(prescription.Variation)]
+				if !ok {
+					fail("%s prescribes undefined variation %q of %q", label, prescription.Variation, prescription.Exercise)
+				}
+				if prescription.Sets < 1 {
+					fail("%s prescribes %q with fewer than 1 set", label, prescription.Exercise)
+				}
+				for _, duration := range []*string{prescription.Duration, prescription.SubRepWorkDuration, prescription.SubRepRestDuration, prescription.Rest} {
+					if duration == nil {
+						continue
+					}
+					if _, err := utils.StringToInterval(*duration); err != nil {
+						fail("%s prescribes %q with an invalid duration %q", label, prescription.Exercise, *duration)
+					}
+				}
+
+				if len(prescription.SetScheme) > 0 && int(prescription.Sets) != len(prescription.SetScheme) {
+					fail("%s prescribes %q with %d sets but a set scheme of %d", label, prescription.Exercise, prescription.Sets, len(prescription.SetScheme))
+				}
+				for i, set := range prescription.SetScheme {
+					for _, duration := range []*string{set.Duration, set.Rest} {
+						if duration == nil {
+							continue
+						}
+						if _, err := utils.StringToInterval(*duration); err != nil {
+							fail("%s prescribes set %d of %q with an invalid duration %q", label, i+1, prescription.Exercise, *duration)
+						}
+					}
+					for _, parameter := range set.Parameters {
+						if ok && !variationParameters[key(parameter.ParameterType)] {
+							fail("%s prescribes set %d of %q with %q, which is not a parameter of the variation", label, i+1, prescription.Exercise, parameter.ParameterType)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,251 @@
+package plantext
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"backend/internal/plandoc"
+)
+
+// plainWord matches values that read back unquoted
+var plainWord = regexp.MustCompile(`^[A-Za-z0-9_%.+@-]+$`)
+
+func quote(value string) string {
+	return strconv.Quote(value)
+}
+
+// word leaves simple values such as units and muscle groups unquoted
+func word(value string) string {
+	if plainWord.MatchString(value) {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+func words(values []string, format func(string) string) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = format(value)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// Write prints a plan in the text format. Parsing the output yields the same document.
+func Write(w io.Writer, doc *plandoc.Document) error {
+	var b strings.Builder
+	line := func(indent int, format string, args ...any) {
+		b.WriteString(strings.Repeat("  ", indent))
+		fmt.Fprintf(&b, format, args...)
+		b.WriteString("\n")
+	}
+
+	line(0, "plan %s", quote(doc.Plan.Name))
+	if doc.Plan.Description != "" {
+		line(1, "description %s", quote(doc.Plan.Description))
+	}
+	if doc.Plan.IsTemplate {
+		line(1, "template")
+	}
+	if doc.Plan.IsPublic {
+		line(1, "public")
+	}
+
+	if len(doc.ParameterTypes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, parameterType := range doc.ParameterTypes {
+		text := fmt.Sprintf("parameter %s: %s, %s", quote(parameterType.Name), word(parameterType.DataType), word(parameterType.DefaultUnit))
+		if parameterType.MinValue != nil {
+			text += ", min " + strconv.FormatFloat(*parameterType.MinValue, 'g', -1, 64)
+		}
+		if parameterType.MaxValue != nil {
+			text += ", max " + strconv.FormatFloat(*parameterType.MaxValue, 'g', -1, 64)
+		}
+		line(0, "%s", text)
+	}
+
+	for _, exercise := range doc.Exercises {
+		b.WriteString("\n")
+		line(0, "exercise %s", quote(exercise.Name))
+		if exercise.Description != "" {
+			line(1, "description %s", quote(exercise.Description))
+		}
+		if len(exercise.PrimaryMuscleGroups) > 0 {
+			line(1, "primary %s", words(exercise.PrimaryMuscleGroups, word))
+		}
+		if len(exercise.SecondaryMuscleGroups) > 0 {
+			line(1, "secondary %s", words(exercise.SecondaryMuscleGroups, word))
+		}
+		if len(exercise.Equipment) > 0 {
+			line(1, "equipment %s", words(exercise.Equipment, word))
+		}
+		if exercise.Laterality != "" {
+			line(1, "laterality %s", word(exercise.Laterality))
+		}
+		if len(exercise.Categories) > 0 {
+			line(1, "categories %s", words(exercise.Categories, quote))
+		}
+		for _, variation := range exercise.Variations {
+			if len(variation.Parameters) == 0 {
+				line(1, "variation %s", quote(variation.Name))
+				continue
+			}
+			parameters := make([]string, len(variation.Parameters))
+			for i, parameter := range variation.Parameters {
+				parameters[i] = quote(parameter.ParameterType)
+				if parameter.Locked {
+					parameters[i] += " locked"
+				}
+			}
+			line(1, "variation %s: %s", quote(variation.Name), strings.Join(parameters, ", "))
+		}
+	}
+
+	for _, group := range doc.Groups {
+		b.WriteString("\n")
+		line(0, "group %s %s", word(group.Key), quote(group.Name))
+		if group.Description != "" {
+			line(1, "description %s", quote(group.Description))
+		}
+	}
+
+	for _, interval := range doc.Intervals {
+		b.WriteString("\n")
+		if interval.Name != "" {
+			line(0, "interval %d %s: %s", interval.Order, quote(interval.Name), interval.Duration)
+		} else {
+			line(0, "interval %d: %s", interval.Order, interval.Duration)
+		}
+		if interval.Description != "" {
+			line(1, "description %s", quote(interval.Description))
+		}
+		if interval.Deload {
+			line(1, "deload")
+		}
+		for _, intervalGroup := range interval.Groups {
+			text := fmt.Sprintf("group %s x%d", word(intervalGroup.Group), intervalGroup.Frequency)
+			if len(intervalGroup.PinnedDays) > 0 {
+				days := make([]string, len(intervalGroup.PinnedDays))
+				for i, day := range intervalGroup.PinnedDays {
+					days[i] = word(day)
+					if number, ok := plandoc.WeekdayNumber(day); ok {
+						days[i] = plandoc.Weekdays[number][:3]
+					}
+				}
+				text += " on " + strings.Join(days, ", ")
+			}
+			line(1, "%s", text)
+
+			blocks := make(map[int]plandoc.Block)
+			for _, block := range intervalGroup.Blocks {
+				if len(block.Prescriptions) > 0 {
+					blocks[block.Prescriptions[0]] = block
+				}
+			}
+			prescription := func(indent int, prescription plandoc.Prescription) {
+				line(indent, "%s", FormatPrescription(prescription))
+				for _, set := range prescription.SetScheme {
+					line(indent+1, "%s", FormatSet(set))
+				}
+			}
+			for i := 0; i < len(intervalGroup.Prescriptions); i++ {
+				block, ok := blocks[i]
+				if !ok {
+					prescription(2, intervalGroup.Prescriptions[i])
+					continue
+				}
+				if block.Rest != nil {
+					line(2, "%s, rest %s", block.Kind, *block.Rest)
+				} else {
+					line(2, "%s", block.Kind)
+				}
+				for _, index := range block.Prescriptions {
+					prescription(3, intervalGroup.Prescriptions[index])
+				}
+				i += len(block.Prescriptions) - 1
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FormatPrescription prints a single prescription line, e.g. `"Squat" / "Back": 3x6 @RPE8, rest 3 minutes`
+func FormatPrescription(prescription plandoc.Prescription) string {
+	reference := quote(prescription.Exercise)
+	if prescription.Variation != "" {
+		reference += " / " + quote(prescription.Variation)
+	}
+
+	var clauses []string
+	durationPrinted := false
+	switch {
+	case prescription.Reps != nil:
+		clauses = append(clauses, fmt.Sprintf("%dx%d", prescription.Sets, *prescription.Reps))
+	case prescription.Duration != nil:
+		clauses = append(clauses, fmt.Sprintf("%dx%s", prescription.Sets, *prescription.Duration))
+		durationPrinted = true
+	case prescription.Sets == 1:
+		clauses = append(clauses, "1 set")
+	default:
+		clauses = append(clauses, fmt.Sprintf("%d sets", prescription.Sets))
+	}
+	if prescription.RPE != nil {
+		clauses[0] += fmt.Sprintf(" @RPE%d", *prescription.RPE)
+	}
+
+	if prescription.Duration != nil && !durationPrinted {
+		clauses = append(clauses, "duration "+*prescription.Duration)
+	}
+	if prescription.SubReps != nil {
+		clauses = append(clauses, fmt.Sprintf("subreps %d", *prescription.SubReps))
+	}
+	if prescription.SubRepWorkDuration != nil {
+		clauses = append(clauses, "on "+*prescription.SubRepWorkDuration)
+	}
+	if prescription.SubRepRestDuration != nil {
+		clauses = append(clauses, "off "+*prescription.SubRepRestDuration)
+	}
+	if prescription.Rest != nil {
+		clauses = append(clauses, "rest "+*prescription.Rest)
+	}
+
+	return reference + ": " + strings.Join(clauses, ", ")
+}
+
+// FormatSet prints a line of a set scheme, e.g. `set 5 @RPE8, rest 3 minutes, "Weight" 100`
+func FormatSet(set plandoc.Set) string {
+	text := "set"
+	switch {
+	case set.Reps != nil:
+		text += fmt.Sprintf(" %d", *set.Reps)
+	case set.Duration != nil:
+		text += " " + *set.Duration
+	}
+	if set.RPE != nil {
+		text += fmt.Sprintf(" @RPE%d", *set.RPE)
+	}
+
+	var clauses []string
+	if set.Reps != nil && set.Duration != nil {
+		clauses = append(clauses, "duration "+*set.Duration)
+	}
+	if set.Rest != nil {
+		clauses = append(clauses, "rest "+*set.Rest)
+	}
+	for _, parameter := range set.Parameters {
+		value := strconv.FormatFloat(parameter.Value, 'g', -1, 64)
+		if parameter.Percentage {
+			value += "%"
+		}
+		clauses = append(clauses, quote(parameter.ParameterType)+" "+value)
+	}
+	if len(clauses) == 0 {
+		return text
+	}
+	return text + ", " + strings.Join(clauses, ", ")
+}
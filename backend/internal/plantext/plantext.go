@@ -0,0 +1,1106 @@
+package plantext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"backend/internal/library"
+	"backend/internal/plandoc"
+	"backend/internal/utils"
+)
+
+// The text format describes one plan per file. Statements start a line and indented
+// lines belong to the statement above them; "#" starts a comment. Names are quoted,
+// durations use the same vocabulary as the API ("2 weeks", "1 minute 30 seconds"):
+//
+//	plan "Strength block"
+//	  description "Four weeks of base strength"
+//
+//	parameter "Weight": number, kg, min 0
+//
+//	exercise "Squat"
+//	  primary quadriceps, glutes
+//	  variation "Back": "Weight"
+//
+//	group lower "Lower body"
+//
+//	interval 1 "Base": 2 weeks
+//	  group lower x2 on mon, thu
+//	    "Squat" / "Back": 3x6 @RPE8, rest 3 minutes
+//	    "Bench" / "Flat": 3 sets, rest 2 minutes
+//	      set 8 @RPE7, "Weight" 80
+//	      set 6 @RPE8, "Weight" 85
+//	      set 4 @RPE9, rest 3 minutes, "Weight" 90
+//	    "Deadlift": 1 set
+//	      set 3 @RPE8, "Weight" 85%   # of the athlete's benchmark
+//	    superset, rest 2 minutes
+//	      "Lunge": 3x10
+//	      "Calf raise": 3x15
+//
+// Exercises that are prescribed without being declared are reused by name on import,
+// or created without parameters.
+
+var (
+	rpePattern          = regexp.MustCompile(`(?i)@\s*rpe\s*(\d+)`)
+	setsRepsPattern     = regexp.MustCompile(`(?i)^(\d+)\s*x\s*(\d+)$`)
+	setsDurationPattern = regexp.MustCompile(`(?i)^(\d+)\s*x\s*(.+)$`)
+	setsPattern         = regexp.MustCompile(`(?i)^(\d+)\s*sets?$`)
+	frequencyPattern    = regexp.MustCompile(`(?i)^x(\d+)$`)
+	durationPattern     = regexp.MustCompile(`(?i)^(\d+\s*(weeks?|days?|hours?|minutes?|seconds?)\s*)+$`)
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// line is a statement together with the indented statements below it
+type line struct {
+	number   int
+	indent   int
+	tokens   []token
+	children []*line
+}
+
+func parseError(number int, format string, args ...any) error {
+	return &library.ParseError{Line: number, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokenize splits a line into words, quoted strings and the punctuation ":", "," and "/"
+func tokenize(number int, text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		switch c := text[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '#':
+			return tokens, nil
+		case c == ':' || c == ',' || c == '/':
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) {
+				return nil, parseError(number, "unterminated string")
+			}
+			value, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, parseError(number, "invalid string %s", text[i:end+1])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value})
+			i = end + 1
+		default:
+			end := i
+			for end < len(text) && !strings.ContainsRune(" \t#:,/\"", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: text[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// readLines tokenizes the input and nests every line under the closest less indented line
+func readLines(r io.Reader) ([]*line, error) {
+	root := &line{indent: -1}
+	stack := []*line{root}
+
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		tokens, err := tokenize(number, text)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		indent := 0
+		for _, c := range text {
+			if c == ' ' {
+				indent++
+			} else if c == '\t' {
+				indent += 4
+			} else {
+				break
+			}
+		}
+
+		for stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		current := &line{number: number, indent: indent, tokens: tokens}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, current)
+		stack = append(stack, current)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return root.children, nil
+}
+
+// cursor reads the tokens of one line
+type cursor struct {
+	line   *line
+	tokens []token
+}
+
+func newCursor(l *line) *cursor {
+	return &cursor{line: l, tokens: l.tokens}
+}
+
+func (c *cursor) fail(format string, args ...any) error {
+	return parseError(c.line.number, format, args...)
+}
+
+func (c *cursor) done() bool {
+	return len(c.tokens) == 0
+}
+
+func (c *cursor) next() token {
+	t := c.tokens[0]
+	c.tokens = c.tokens[1:]
+	return t
+}
+
+// name reads a quoted string or a single word
+func (c *cursor) name(what string) (string, error) {
+	if c.done() || c.tokens[0].kind == tokenPunct {
+		return "", c.fail("expected %s", what)
+	}
+	return c.next().text, nil
+}
+
+// punct consumes the punctuation if it comes next
+func (c *cursor) punct(p string) bool {
+	if !c.done() && c.tokens[0].kind == tokenPunct && c.tokens[0].text == p {
+		c.next()
+		return true
+	}
+	return false
+}
+
+func (c *cursor) end() error {
+	if !c.done() {
+		return c.fail("unexpected %q", c.tokens[0].text)
+	}
+	return nil
+}
+
+// clauses splits the remaining tokens on commas
+func (c *cursor) clauses() ([][]token, error) {
+	clauses := [][]token{{}}
+	for !c.done() {
+		t := c.next()
+		if t.kind == tokenPunct && t.text == "," {
+			clauses = append(clauses, []token{})
+			continue
+		}
+		if t.kind == tokenPunct {
+			return nil, c.fail("unexpected %q", t.text)
+		}
+		clauses[len(clauses)-1] = append(clauses[len(clauses)-1], t)
+	}
+	for _, clause := range clauses {
+		if len(clause) == 0 {
+			return nil, c.fail("empty item in list")
+		}
+	}
+	return clauses, nil
+}
+
+// names reads a comma separated list of names
+func (c *cursor) names(what string) ([]string, error) {
+	clauses, err := c.clauses()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		if len(clause) != 1 {
+			return nil, c.fail("expected one %s per item", what)
+		}
+		names = append(names, clause[0].text)
+	}
+	return names, nil
+}
+
+// words joins the remaining words of a clause, rejecting quoted strings
+func (c *cursor) words(clause []token) (string, error) {
+	parts := make([]string, 0, len(clause))
+	for _, t := range clause {
+		if t.kind == tokenString {
+			return "", c.fail("unexpected string %q", t.text)
+		}
+		parts = append(parts, t.text)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func (c *cursor) duration(text string) (string, error) {
+	if !durationPattern.MatchString(text) {
+		return "", c.fail("invalid duration %q, expected a combination of weeks, days, hours, minutes and seconds", text)
+	}
+	if _, err := utils.StringToInterval(text); err != nil {
+		return "", c.fail("invalid duration %q: %v", text, err)
+	}
+	return text, nil
+}
+
+func (c *cursor) integer(text string, what string) (int32, error) {
+	value, err := strconv.ParseInt(text, 10, 32)
+	if err != nil {
+		return 0, c.fail("invalid %s %q", what, text)
+	}
+	return int32(value), nil
+}
+
+func keyword(l *line) string {
+	if l.tokens[0].kind != tokenWord {
+		return ""
+	}
+	return strings.ToLower(l.tokens[0].text)
+}
+
+// groupRef records where an interval schedules a group
+type groupRef struct {
+	line int
+	key  string
+}
+
+// prescriptionRef records where a prescription references an exercise, so references can
+// be resolved once the whole file has been read
+type prescriptionRef struct {
+	line      int
+	exercise  string
+	variation string
+}
+
+type parser struct {
+	doc            *plandoc.Document
+	planLine       int
+	parameterTypes map[string]int
+	exercises      map[string]int
+	declared       map[string]bool
+	groups         map[string]int
+	orders         map[int32]int
+	groupRefs      []groupRef
+	prescriptions  []prescriptionRef
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Parse reads a plan written in the text format. Errors carry the line they occur on.
+func Parse(r io.Reader) (*plandoc.Document, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{
+		doc: &plandoc.Document{
+			Format:         plandoc.Format,
+			Version:        plandoc.FormatVersion,
+			ParameterTypes: []library.ParameterType{},
+			Exercises:      []library.Exercise{},
+			Groups:         []plandoc.Group{},
+			Intervals:      []plandoc.Interval{},
+		},
+		parameterTypes: make(map[string]int),
+		exercises:      make(map[string]int),
+		declared:       make(map[string]bool),
+		groups:         make(map[string]int),
+		orders:         make(map[int32]int),
+	}
+
+	for _, l := range lines {
+		var err error
+		switch keyword(l) {
+		case "plan":
+			err = p.parsePlan(l)
+		case "parameter":
+			err = p.parseParameterType(l)
+		case "exercise":
+			err = p.parseExercise(l)
+		case "group":
+			err = p.parseGroup(l)
+		case "interval":
+			err = p.parseInterval(l)
+		default:
+			err = parseError(l.number, "unknown statement %q, expected plan, parameter, exercise, group or interval", l.tokens[0].text)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.planLine == 0 {
+		return nil, parseError(0, "missing plan statement")
+	}
+	if err := p.resolve(); err != nil {
+		return nil, err
+	}
+
+	// Anything the line-level checks cannot see, such as metadata values, is still validated
+	if errs := plandoc.Validate(p.doc); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return p.doc, nil
+}
+
+func (p *parser) parsePlan(l *line) error {
+	c := newCursor(l)
+	c.next()
+	if p.planLine != 0 {
+		return c.fail("plan is already defined on line %d", p.planLine)
+	}
+	name, err := c.name("plan name")
+	if err != nil {
+		return err
+	}
+	if err := c.end(); err != nil {
+		return err
+	}
+	p.planLine = l.number
+	p.doc.Plan.Name = name
+
+	for _, child := range l.children {
+		c := newCursor(child)
+		switch keyword(child) {
+		case "description":
+			c.next()
+			if p.doc.Plan.Description, err = c.name("description"); err != nil {
+				return err
+			}
+		case "template":
+			c.next()
+			p.doc.Plan.IsTemplate = true
+		case "public":
+			c.next()
+			p.doc.Plan.IsPublic = true
+		default:
+			return c.fail("unknown plan property %q, expected description, template or public", child.tokens[0].text)
+		}
+		if err := c.end(); err != nil {
+			return err
+		}
+		if err := noChildren(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseParameterType(l *line) error {
+	c := newCursor(l)
+	c.next()
+	name, err := c.name("parameter type name")
+	if err != nil {
+		return err
+	}
+	if _, ok := p.parameterTypes[key(name)]; ok {
+		return c.fail("parameter type %q is already defined", name)
+	}
+	if !c.punct(":") {
+		return c.fail("expected \":\" followed by a data type and default unit")
+	}
+
+	clauses, err := c.clauses()
+	if err != nil {
+		return err
+	}
+	if len(clauses) < 2 || len(clauses[0]) != 1 || len(clauses[1]) != 1 {
+		return c.fail("expected a data type and default unit")
+	}
+	parameterType := library.ParameterType{Name: name, DataType: clauses[0][0].text, DefaultUnit: clauses[1][0].text}
+
+	for _, clause := range clauses[2:] {
+		if len(clause) != 2 {
+			return c.fail("expected min or max followed by a number")
+		}
+		value, err := strconv.ParseFloat(clause[1].text, 64)
+		if err != nil {
+			return c.fail("invalid number %q", clause[1].text)
+		}
+		switch strings.ToLower(clause[0].text) {
+		case "min":
+			parameterType.MinValue = &value
+		case "max":
+			parameterType.MaxValue = &value
+		default:
+			return c.fail("unknown parameter type property %q, expected min or max", clause[0].text)
+		}
+	}
+	if err := noChildren(l); err != nil {
+		return err
+	}
+
+	p.parameterTypes[key(name)] = l.number
+	p.doc.ParameterTypes = append(p.doc.ParameterTypes, parameterType)
+	return nil
+}
+
+func (p *parser) parseExercise(l *line) error {
+	c := newCursor(l)
+	c.next()
+	name, err := c.name("exercise name")
+	if err != nil {
+		return err
+	}
+	if err := c.end(); err != nil {
+		return err
+	}
+	if _, ok := p.exercises[key(name)]; ok {
+		return c.fail("exercise %q is already defined", name)
+	}
+
+	exercise := library.Exercise{
+		Name:                  name,
+		PrimaryMuscleGroups:   []string{},
+		SecondaryMuscleGroups: []string{},
+		Equipment:             []string{},
+		Categories:            []string{},
+		Variations:            []library.Variation{},
+		Line:                  l.number,
+	}
+	variations := make(map[string]bool)
+
+	for _, child := range l.children {
+		c := newCursor(child)
+		c.next()
+		var err error
+		switch keyword(child) {
+		case "description":
+			exercise.Description, err = c.name("description")
+		case "primary":
+			exercise.PrimaryMuscleGroups, err = c.names("muscle group")
+		case "secondary":
+			exercise.SecondaryMuscleGroups, err = c.names("muscle group")
+		case "equipment":
+			exercise.Equipment, err = c.names("equipment")
+		case "laterality":
+			exercise.Laterality, err = c.name("laterality")
+		case "categories":
+			exercise.Categories, err = c.names("category")
+		case "variation":
+			var variation library.Variation
+			if variation, err = p.parseVariation(c); err == nil {
+				if variations[key(variation.Name)] {
+					return c.fail("variation %q of exercise %q is already defined", variation.Name, name)
+				}
+				variations[key(variation.Name)] = true
+				exercise.Variations = append(exercise.Variations, variation)
+			}
+		default:
+			return c.fail("unknown exercise property %q", child.tokens[0].text)
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.end(); err != nil {
+			return err
+		}
+		if err := noChildren(child); err != nil {
+			return err
+		}
+	}
+
+	p.exercises[key(name)] = len(p.doc.Exercises)
+	p.declared[key(name)] = true
+	p.doc.Exercises = append(p.doc.Exercises, exercise)
+	return nil
+}
+
+// parseVariation reads `"Name": "Parameter type" [locked], ...` after the variation keyword
+func (p *parser) parseVariation(c *cursor) (library.Variation, error) {
+	variation := library.Variation{Parameters: []library.Parameter{}}
+	name, err := c.name("variation name")
+	if err != nil {
+		return variation, err
+	}
+	variation.Name = name
+	if !c.punct(":") {
+		return variation, nil
+	}
+
+	clauses, err := c.clauses()
+	if err != nil {
+		return variation, err
+	}
+	for _, clause := range clauses {
+		parameter := library.Parameter{ParameterType: clause[0].text}
+		switch {
+		case len(clause) == 2 && strings.EqualFold(clause[1].text, "locked"):
+			parameter.Locked = true
+		case len(clause) != 1:
+			return variation, c.fail("expected a parameter type optionally followed by locked")
+		}
+		if _, ok := p.parameterTypes[key(parameter.ParameterType)]; !ok {
+			return variation, c.fail("undefined parameter type %q, parameter types must be defined before they are used", parameter.ParameterType)
+		}
+		variation.Parameters = append(variation.Parameters, parameter)
+	}
+	return variation, nil
+}
+
+func (p *parser) parseGroup(l *line) error {
+	c := newCursor(l)
+	c.next()
+	groupKey, err := c.name("group key")
+	if err != nil {
+		return err
+	}
+	name, err := c.name("group name")
+	if err != nil {
+		return err
+	}
+	if err := c.end(); err != nil {
+		return err
+	}
+	if number, ok := p.groups[groupKey]; ok {
+		return c.fail("group %q is already defined on line %d", groupKey, number)
+	}
+
+	group := plandoc.Group{Key: groupKey, Name: name}
+	for _, child := range l.children {
+		c := newCursor(child)
+		if keyword(child) != "description" {
+			return c.fail("unknown group property %q, expected description", child.tokens[0].text)
+		}
+		c.next()
+		if group.Description, err = c.name("description"); err != nil {
+			return err
+		}
+		if err := c.end(); err != nil {
+			return err
+		}
+		if err := noChildren(child); err != nil {
+			return err
+		}
+	}
+
+	p.groups[groupKey] = l.number
+	p.doc.Groups = append(p.doc.Groups, group)
+	return nil
+}
+
+// parseInterval reads `interval <order> ["Name"]: <duration>` and the groups it schedules
+func (p *parser) parseInterval(l *line) error {
+	c := newCursor(l)
+	c.next()
+	orderText, err := c.name("interval order")
+	if err != nil {
+		return err
+	}
+	order, err := c.integer(orderText, "interval order")
+	if err != nil {
+		return err
+	}
+	if number, ok := p.orders[order]; ok {
+		return c.fail("interval order %d is already used on line %d", order, number)
+	}
+
+	interval := plandoc.Interval{Order: order, Groups: []plandoc.IntervalGroup{}}
+	if !c.done() && c.tokens[0].kind == tokenString {
+		interval.Name = c.next().text
+	}
+	if !c.punct(":") {
+		return c.fail("expected \":\" followed by the interval duration")
+	}
+	clauses, err := c.clauses()
+	if err != nil {
+		return err
+	}
+	if len(clauses) != 1 {
+		return c.fail("expected a single interval duration")
+	}
+	text, err := c.words(clauses[0])
+	if err != nil {
+		return err
+	}
+	if interval.Duration, err = c.duration(text); err != nil {
+		return err
+	}
+
+	groupLines := make(map[string]int)
+	for _, child := range l.children {
+		c := newCursor(child)
+		switch keyword(child) {
+		case "description":
+			c.next()
+			if interval.Description, err = c.name("description"); err != nil {
+				return err
+			}
+			if err := c.end(); err != nil {
+				return err
+			}
+			if err := noChildren(child); err != nil {
+				return err
+			}
+		case "deload":
+			c.next()
+			interval.Deload = true
+			if err := c.end(); err != nil {
+				return err
+			}
+			if err := noChildren(child); err != nil {
+				return err
+			}
+		case "group":
+			intervalGroup, err := p.parseIntervalGroup(child)
+			if err != nil {
+				return err
+			}
+			if number, ok := groupLines[intervalGroup.Group]; ok {
+				return c.fail("group %q is already scheduled in this interval on line %d", intervalGroup.Group, number)
+			}
+			groupLines[intervalGroup.Group] = child.number
+			interval.Groups = append(interval.Groups, intervalGroup)
+		default:
+			return c.fail("unknown interval property %q, expected description, deload or group", child.tokens[0].text)
+		}
+	}
+
+	p.orders[order] = l.number
+	p.doc.Intervals = append(p.doc.Intervals, interval)
+	return nil
+}
+
+// weekday reads a weekday written in full or by its first three letters
+func weekday(text string) (string, bool) {
+	for _, name := range plandoc.Weekdays {
+		if strings.EqualFold(text, name) || strings.EqualFold(text, name[:3]) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// parseIntervalGroup reads `group <key> [x<frequency>] [on <weekday>, ...]` and its prescriptions
+func (p *parser) parseIntervalGroup(l *line) (plandoc.IntervalGroup, error) {
+	intervalGroup := plandoc.IntervalGroup{Frequency: 1, Prescriptions: []plandoc.Prescription{}}
+
+	c := newCursor(l)
+	c.next()
+	groupKey, err := c.name("group key")
+	if err != nil {
+		return intervalGroup, err
+	}
+	intervalGroup.Group = groupKey
+	if !c.done() && !strings.EqualFold(c.tokens[0].text, "on") {
+		text := c.next().text
+		match := frequencyPattern.FindStringSubmatch(text)
+		if match == nil {
+			return intervalGroup, c.fail("invalid frequency %q, expected e.g. x2", text)
+		}
+		if intervalGroup.Frequency, err = c.integer(match[1], "frequency"); err != nil {
+			return intervalGroup, err
+		}
+		if intervalGroup.Frequency < 1 {
+			return intervalGroup, c.fail("frequency must be at least 1")
+		}
+	}
+	if !c.done() && strings.EqualFold(c.tokens[0].text, "on") {
+		c.next()
+		days, err := c.names("weekday")
+		if err != nil {
+			return intervalGroup, err
+		}
+		for _, day := range days {
+			name, ok := weekday(day)
+			if !ok {
+				return intervalGroup, c.fail("unknown weekday %q, expected e.g. mon or monday", day)
+			}
+			intervalGroup.PinnedDays = append(intervalGroup.PinnedDays, name)
+		}
+	}
+	if err := c.end(); err != nil {
+		return intervalGroup, err
+	}
+
+	for _, child := range l.children {
+		if isBlock(child) {
+			if err := p.parseBlock(child, &intervalGroup); err != nil {
+				return intervalGroup, err
+			}
+			continue
+		}
+		prescription, err := p.parsePrescription(child)
+		if err != nil {
+			return intervalGroup, err
+		}
+		intervalGroup.Prescriptions = append(intervalGroup.Prescriptions, prescription)
+	}
+
+	// Group references are resolved once every group has been read
+	p.groupRefs = append(p.groupRefs, groupRef{line: l.number, key: groupKey})
+	return intervalGroup, nil
+}
+
+// isBlock tells a `superset` or `circuit` line apart from a prescription of an exercise
+// with the same name, which is always followed by ":" or "/"
+func isBlock(l *line) bool {
+	kind := keyword(l)
+	if kind != "superset" && kind != "circuit" {
+		return false
+	}
+	return len(l.tokens) == 1 || (l.tokens[1].kind == tokenPunct && l.tokens[1].text == ",")
+}
+
+// parseBlock reads `superset [, rest <duration>]` or `circuit [, rest <duration>]` and the
+// prescriptions that are performed together
+func (p *parser) parseBlock(l *line, intervalGroup *plandoc.IntervalGroup) error {
+	c := newCursor(l)
+	block := plandoc.Block{Kind: keyword(l), Prescriptions: []int{}}
+	c.next()
+	if c.punct(",") {
+		clauses, err := c.clauses()
+		if err != nil {
+			return err
+		}
+		for _, clause := range clauses {
+			text, err := c.words(clause)
+			if err != nil {
+				return err
+			}
+			property, value, _ := strings.Cut(text, " ")
+			if !strings.EqualFold(property, "rest") || block.Rest != nil {
+				return c.fail("unexpected %q, expected rest followed by a duration", text)
+			}
+			if block.Rest, err = durationValue(c, strings.TrimSpace(value)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.end(); err != nil {
+		return err
+	}
+	if len(l.children) < 2 {
+		return c.fail("a %s needs at least two prescriptions", block.Kind)
+	}
+
+	for _, child := range l.children {
+		prescription, err := p.parsePrescription(child)
+		if err != nil {
+			return err
+		}
+		block.Prescriptions = append(block.Prescriptions, len(intervalGroup.Prescriptions))
+		intervalGroup.Prescriptions = append(intervalGroup.Prescriptions, prescription)
+	}
+	intervalGroup.Blocks = append(intervalGroup.Blocks, block)
+	return nil
+}
+
+// parsePrescription reads `"Exercise" [/ "Variation"]: 3x6 @RPE8, rest 3 minutes` and the
+// set lines below it
+func (p *parser) parsePrescription(l *line) (plandoc.Prescription, error) {
+	var prescription plandoc.Prescription
+
+	c := newCursor(l)
+	exercise, err := c.name("exercise name")
+	if err != nil {
+		return prescription, err
+	}
+	prescription.Exercise = exercise
+	if c.punct("/") {
+		if prescription.Variation, err = c.name("variation name"); err != nil {
+			return prescription, err
+		}
+	}
+	if !c.punct(":") {
+		return prescription, c.fail("expected \":\" followed by sets, e.g. 3x6 @RPE8")
+	}
+
+	clauses, err := c.clauses()
+	if err != nil {
+		return prescription, err
+	}
+
+	seen := make(map[string]bool)
+	for i, clause := range clauses {
+		text, err := c.words(clause)
+		if err != nil {
+			return prescription, err
+		}
+
+		if match := rpePattern.FindStringSubmatch(text); match != nil {
+			if prescription.RPE != nil {
+				return prescription, c.fail("RPE is given more than once")
+			}
+			rpe, err := c.integer(match[1], "RPE")
+			if err != nil {
+				return prescription, err
+			}
+			prescription.RPE = &rpe
+			text = strings.TrimSpace(strings.Replace(text, match[0], "", 1))
+			if text == "" && i > 0 {
+				continue
+			}
+		}
+
+		if i == 0 {
+			if err := p.parseSets(c, text, &prescription); err != nil {
+				return prescription, err
+			}
+			continue
+		}
+
+		property, value, _ := strings.Cut(text, " ")
+		property = strings.ToLower(property)
+		value = strings.TrimSpace(value)
+		if seen[property] {
+			return prescription, c.fail("%s is given more than once", property)
+		}
+		seen[property] = true
+
+		switch property {
+		case "rest":
+			prescription.Rest, err = durationValue(c, value)
+		case "duration":
+			if prescription.Duration != nil {
+				return prescription, c.fail("duration is given more than once")
+			}
+			prescription.Duration, err = durationValue(c, value)
+		case "reps":
+			if prescription.Reps != nil {
+				return prescription, c.fail("reps are given more than once")
+			}
+			prescription.Reps, err = integerValue(c, value, "reps")
+		case "subreps":
+			prescription.SubReps, err = integerValue(c, value, "subreps")
+		case "on":
+			prescription.SubRepWorkDuration, err = durationValue(c, value)
+		case "off":
+			prescription.SubRepRestDuration, err = durationValue(c, value)
+		default:
+			return prescription, c.fail("unknown prescription property %q, expected rest, duration, reps, subreps, on or off", property)
+		}
+		if err != nil {
+			return prescription, err
+		}
+	}
+
+	for _, child := range l.children {
+		set, err := p.parseSet(child)
+		if err != nil {
+			return prescription, err
+		}
+		prescription.SetScheme = append(prescription.SetScheme, set)
+	}
+	if len(prescription.SetScheme) > 0 && int(prescription.Sets) != len(prescription.SetScheme) {
+		return prescription, c.fail("%d sets are prescribed but %d set lines follow", prescription.Sets, len(prescription.SetScheme))
+	}
+
+	p.prescriptions = append(p.prescriptions, prescriptionRef{line: l.number, exercise: prescription.Exercise, variation: prescription.Variation})
+	return prescription, nil
+}
+
+// parseSet reads `set [<reps>|<duration>] [@RPE8] [, duration <duration>] [, rest <duration>]
+// [, "Parameter" <value>]`
+func (p *parser) parseSet(l *line) (plandoc.Set, error) {
+	var set plandoc.Set
+
+	c := newCursor(l)
+	if keyword(l) != "set" {
+		return set, c.fail("expected a set line, e.g. set 5 @RPE8, \"Weight\" 100")
+	}
+	c.next()
+	if err := noChildren(l); err != nil {
+		return set, err
+	}
+	if c.done() {
+		return set, nil
+	}
+	// A set without reps can go straight to its properties
+	c.punct(",")
+
+	clauses, err := c.clauses()
+	if err != nil {
+		return set, err
+	}
+
+	seen := make(map[string]bool)
+	for i, clause := range clauses {
+		if clause[0].kind == tokenString {
+			if len(clause) != 2 {
+				return set, c.fail("expected a value after %q", clause[0].text)
+			}
+			// A trailing "%" makes the value a percentage of the athlete's benchmark
+			text, percentage := strings.CutSuffix(clause[1].text, "%")
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return set, c.fail("invalid value %q for %q", clause[1].text, clause[0].text)
+			}
+			if seen[key(clause[0].text)] {
+				return set, c.fail("%q is given more than once", clause[0].text)
+			}
+			seen[key(clause[0].text)] = true
+			set.Parameters = append(set.Parameters, plandoc.SetParameter{ParameterType: clause[0].text, Value: value, Percentage: percentage})
+			continue
+		}
+
+		text, err := c.words(clause)
+		if err != nil {
+			return set, err
+		}
+		if match := rpePattern.FindStringSubmatch(text); match != nil {
+			if set.RPE != nil {
+				return set, c.fail("RPE is given more than once")
+			}
+			if set.RPE, err = integerValue(c, match[1], "RPE"); err != nil {
+				return set, err
+			}
+			text = strings.TrimSpace(strings.Replace(text, match[0], "", 1))
+			if text == "" {
+				continue
+			}
+		}
+
+		property, value, _ := strings.Cut(text, " ")
+		if i == 0 && !strings.EqualFold(property, "rest") && !strings.EqualFold(property, "duration") {
+			if _, err := strconv.Atoi(text); err == nil {
+				set.Reps, err = integerValue(c, text, "reps")
+			} else {
+				set.Duration, err = durationValue(c, text)
+			}
+			if err != nil {
+				return set, err
+			}
+			continue
+		}
+		property = strings.ToLower(property)
+		if (property != "rest" && property != "duration") || seen[property] {
+			return set, c.fail("unexpected %q, expected rest or duration followed by a duration, or a quoted parameter and its value", text)
+		}
+		seen[property] = true
+		if property == "rest" {
+			set.Rest, err = durationValue(c, strings.TrimSpace(value))
+		} else {
+			set.Duration, err = durationValue(c, strings.TrimSpace(value))
+		}
+		if err != nil {
+			return set, err
+		}
+	}
+	return set, nil
+}
+
+// parseSets reads the leading `3x6`, `3x30 seconds` or `3 sets` of a prescription
+func (p *parser) parseSets(c *cursor, text string, prescription *plandoc.Prescription) error {
+	var err error
+	if match := setsRepsPattern.FindStringSubmatch(text); match != nil {
+		if prescription.Sets, err = c.integer(match[1], "sets"); err != nil {
+			return err
+		}
+		prescription.Reps, err = integerValue(c, match[2], "reps")
+		return err
+	}
+	if match := setsDurationPattern.FindStringSubmatch(text); match != nil {
+		if prescription.Sets, err = c.integer(match[1], "sets"); err != nil {
+			return err
+		}
+		prescription.Duration, err = durationValue(c, strings.TrimSpace(match[2]))
+		return err
+	}
+	if match := setsPattern.FindStringSubmatch(text); match != nil {
+		prescription.Sets, err = c.integer(match[1], "sets")
+		return err
+	}
+	return c.fail("invalid sets %q, expected e.g. 3x6, 3x30 seconds or 3 sets", text)
+}
+
+func durationValue(c *cursor, text string) (*string, error) {
+	duration, err := c.duration(text)
+	if err != nil {
+		return nil, err
+	}
+	return &duration, nil
+}
+
+func integerValue(c *cursor, text string, what string) (*int32, error) {
+	value, err := c.integer(text, what)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func noChildren(l *line) error {
+	if len(l.children) > 0 {
+		return parseError(l.children[0].number, "unexpected indented line")
+	}
+	return nil
+}
+
+// resolve checks group and exercise references once the whole file has been read.
+// Exercises that are prescribed but not declared are added without parameters.
+func (p *parser) resolve() error {
+	for _, ref := range p.groupRefs {
+		if _, ok := p.groups[ref.key]; !ok {
+			return parseError(ref.line, "unknown group %q", ref.key)
+		}
+	}
+
+	for _, ref := range p.prescriptions {
+		index, ok := p.exercises[key(ref.exercise)]
+		if !ok {
+			index = len(p.doc.Exercises)
+			p.exercises[key(ref.exercise)] = index
+			p.doc.Exercises = append(p.doc.Exercises, library.Exercise{
+				Name:                  ref.exercise,
+				PrimaryMuscleGroups:   []string{},
+				SecondaryMuscleGroups: []string{},
+				Equipment:             []string{},
+				Categories:            []string{},
+				Variations:            []library.Variation{},
+				Line:                  ref.line,
+			})
+		}
+
+		exercise := &p.doc.Exercises[index]
+		found := false
+		for _, variation := range exercise.Variations {
+			if key(variation.Name) == key(ref.variation) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		if p.declared[key(ref.exercise)] {
+			return parseError(ref.line, "exercise %q has no variation %q", ref.exercise, ref.variation)
+		}
+		exercise.Variations = append(exercise.Variations, library.Variation{Name: ref.variation, Parameters: []library.Parameter{}})
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package progression
+
+import (
+	"errors"
+	"math"
+
+	"backend/internal/types"
+)
+
+// Reduction is how much a deload cuts from the interval it follows. Sets, reps and load
+// are cut by a percentage, RPE by points.
+type Reduction struct {
+	Sets float64
+	Reps float64
+	Load float64
+	RPE  float64
+}
+
+// DefaultDeload keeps the reps but cuts sets, load and effort
+var DefaultDeload = Reduction{Sets: 40, Load: 10, RPE: 2}
+
+// ValidateReduction checks that a reduction leaves something to train
+func ValidateReduction(r Reduction) error {
+	for _, percent := range []float64{r.Sets, r.Reps, r.Load} {
+		if percent < 0 || percent >= 100 {
+			return errors.New("sets, reps and load reductions must be at least 0% and below 100%")
+		}
+	}
+	if r.RPE < 0 || r.RPE >= 10 {
+		return errors.New("the RPE reduction must be at least 0 and below 10")
+	}
+	return nil
+}
+
+func cut(value float64, percent float64) float64 {
+	return value * (1 - percent/100)
+}
+
+// SetCount is the number of sets left after the reduction, at least one
+func (r Reduction) SetCount(sets int32) int32 {
+	return max(Whole(cut(float64(sets), r.Sets)), 1)
+}
+
+func (r Reduction) reps(reps *int32) *int32 {
+	if reps == nil {
+		return nil
+	}
+	result := max(Whole(cut(float64(*reps), r.Reps)), 1)
+	return &result
+}
+
+func (r Reduction) rpe(rpe *int32) *int32 {
+	if rpe == nil {
+		return nil
+	}
+	result := max(Whole(float64(*rpe)-r.RPE), 1)
+	return &result
+}
+
+// LoadValue is a load after the reduction
+func (r Reduction) LoadValue(value float64) float64 {
+	return math.Round(cut(value, r.Load)*100) / 100
+}
+
+// Deload returns the prescription reduced for a deload. A set scheme keeps its first
+// sets, and the set parameters isLoad accepts, such as weight, are cut by the load
+// reduction.
+func Deload(prescription types.IntervalExercisePrescription, r Reduction, isLoad func(parameterTypeId int64) bool) types.IntervalExercisePrescription {
+	result := prescription
+	result.Reps = r.reps(prescription.Reps)
+	result.RPE = r.rpe(prescription.RPE)
+	if len(prescription.SetScheme) == 0 {
+		result.Sets = r.SetCount(prescription.Sets)
+		return result
+	}
+
+	sets := r.SetCount(int32(len(prescription.SetScheme)))
+	result.Sets = sets
+	result.SetScheme = make([]types.PrescriptionSet, sets)
+	for i, set := range prescription.SetScheme[:sets] {
+		result.SetScheme[i] = set
+		result.SetScheme[i].Reps = r.reps(set.Reps)
+		result.SetScheme[i].RPE = r.rpe(set.RPE)
+		result.SetScheme[i].Parameters = make([]types.PrescriptionSetParameter, len(set.Parameters))
+		for j, parameter := range set.Parameters {
+			result.SetScheme[i].Parameters[j] = parameter
+			if isLoad(parameter.ParameterTypeId) {
+				result.SetScheme[i].Parameters[j].Value = r.LoadValue(parameter.Value)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,222 @@
+// Package progression moves prescriptions forward from one interval to the next, e.g.
+// "+2.5 kg per interval", "+1 rep until 8, then +5% weight" or "RPE +0.5".
+package progression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"backend/internal/types"
+	"backend/internal/utils"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Values a step can progress. Durations are progressed in seconds and parameters are
+// the per-set values of a set scheme, such as the weight of each set.
+const (
+	TargetSets      = "sets"
+	TargetReps      = "reps"
+	TargetRPE       = "rpe"
+	TargetDuration  = "duration"
+	TargetParameter = "parameter"
+)
+
+var Targets = []string{TargetSets, TargetReps, TargetRPE, TargetDuration, TargetParameter}
+
+// A step adds its amount as is, or as a percentage of the current value
+const (
+	UnitAbsolute = "absolute"
+	UnitPercent  = "percent"
+)
+
+var Units = []string{UnitAbsolute, UnitPercent}
+
+// Step changes one value per interval until the value reaches Until, when given
+type Step struct {
+	Target          string
+	ParameterTypeID int64
+	Amount          float64
+	Unit            string
+	Until           *float64
+}
+
+// Key identifies the value the step progresses
+func (s Step) Key() string {
+	return Key(s.Target, s.ParameterTypeID)
+}
+
+func Key(target string, parameterTypeId int64) string {
+	if target == TargetParameter {
+		return fmt.Sprintf("%s:%d", target, parameterTypeId)
+	}
+	return target
+}
+
+// Values holds the values of a prescription, or of one of its sets, by key
+type Values map[string]float64
+
+// Validate checks the steps of a rule
+func Validate(steps []Step) error {
+	if len(steps) == 0 {
+		return errors.New("a progression rule needs at least one step")
+	}
+	for i, step := range steps {
+		number := i + 1
+		if !slices.Contains(Targets, step.Target) {
+			return fmt.Errorf("step %d has an unknown target %q", number, step.Target)
+		}
+		if !slices.Contains(Units, step.Unit) {
+			return fmt.Errorf("step %d has an unknown unit %q", number, step.Unit)
+		}
+		if (step.Target == TargetParameter) != (step.ParameterTypeID != 0) {
+			return fmt.Errorf("step %d needs a parameter type exactly when it progresses a parameter", number)
+		}
+		if step.Amount == 0 {
+			return fmt.Errorf("step %d does not change anything", number)
+		}
+	}
+	return nil
+}
+
+// reached reports whether a value is at or past the limit in the direction of the step
+func reached(step Step, value float64) bool {
+	if step.Until == nil {
+		return false
+	}
+	if step.Amount > 0 {
+		return value >= *step.Until
+	}
+	return value <= *step.Until
+}
+
+// Apply progresses values over the given number of intervals. Each interval applies
+// the first step whose value is present and has not reached its limit. Values are
+// clamped to the limit and never drop below zero.
+func Apply(steps []Step, values Values, intervals int) Values {
+	result := make(Values, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+
+	for range intervals {
+		for _, step := range steps {
+			value, ok := result[step.Key()]
+			if !ok || reached(step, value) {
+				continue
+			}
+			next := value + step.Amount
+			if step.Unit == UnitPercent {
+				next = value * (1 + step.Amount/100)
+			}
+			if step.Until != nil && reached(step, next) {
+				next = *step.Until
+			}
+			result[step.Key()] = math.Max(next, 0)
+			break
+		}
+	}
+	return result
+}
+
+// Whole rounds a progressed count to the nearest whole number, halves down, so RPE
+// +0.5 from 7 reads 7, 8, 8, 9 over successive intervals
+func Whole(value float64) int32 {
+	return int32(math.Ceil(value - 0.5))
+}
+
+func seconds(interval *types.PostgreSQLInterval) (float64, bool) {
+	if interval == nil || !interval.Valid {
+		return 0, false
+	}
+	return utils.IntervalToDuration(interval.Interval).Seconds(), true
+}
+
+func interval(seconds float64) *types.PostgreSQLInterval {
+	value := types.NewPostgreSQLInterval(pgtype.Interval{Microseconds: int64(math.Round(seconds * 1e6)), Valid: true})
+	return &value
+}
+
+func whole(value float64) *int32 {
+	result := Whole(value)
+	return &result
+}
+
+// Prescription returns the prescription as it reads after the given number of
+// intervals. The uniform values and each set of the set scheme progress on their own;
+// sets follow the prescription's reps, RPE and duration unless they set their own.
+// The number of sets is fixed by a set scheme and does not progress then.
+func Prescription(prescription types.IntervalExercisePrescription, steps []Step, intervals int) types.IntervalExercisePrescription {
+	uniform := Values{}
+	if len(prescription.SetScheme) == 0 {
+		uniform[TargetSets] = float64(prescription.Sets)
+	}
+	if prescription.Reps != nil {
+		uniform[TargetReps] = float64(*prescription.Reps)
+	}
+	if prescription.RPE != nil {
+		uniform[TargetRPE] = float64(*prescription.RPE)
+	}
+	if duration, ok := seconds(prescription.Duration); ok {
+		uniform[TargetDuration] = duration
+	}
+
+	progressed := Apply(steps, uniform, intervals)
+	result := prescription
+	if value, ok := progressed[TargetSets]; ok {
+		result.Sets = max(Whole(value), 1)
+	}
+	if value, ok := progressed[TargetReps]; ok {
+		result.Reps = whole(value)
+	}
+	if value, ok := progressed[TargetRPE]; ok {
+		result.RPE = whole(min(value, 10))
+	}
+	if value, ok := progressed[TargetDuration]; ok {
+		result.Duration = interval(value)
+	}
+
+	result.SetScheme = make([]types.PrescriptionSet, len(prescription.SetScheme))
+	for i, set := range prescription.SetScheme {
+		values := Values{}
+		for key, value := range uniform {
+			if key != TargetSets {
+				values[key] = value
+			}
+		}
+		if set.Reps != nil {
+			values[TargetReps] = float64(*set.Reps)
+		}
+		if set.RPE != nil {
+			values[TargetRPE] = float64(*set.RPE)
+		}
+		if duration, ok := seconds(set.Duration); ok {
+			values[TargetDuration] = duration
+		}
+		for _, parameter := range set.Parameters {
+			values[Key(TargetParameter, parameter.ParameterTypeId)] = parameter.Value
+		}
+
+		progressed := Apply(steps, values, intervals)
+		result.SetScheme[i] = set
+		if set.Reps != nil {
+			result.SetScheme[i].Reps = whole(progressed[TargetReps])
+		}
+		if set.RPE != nil {
+			result.SetScheme[i].RPE = whole(min(progressed[TargetRPE], 10))
+		}
+		if set.Duration != nil {
+			result.SetScheme[i].Duration = interval(progressed[TargetDuration])
+		}
+		result.SetScheme[i].Parameters = make([]types.PrescriptionSetParameter, len(set.Parameters))
+		for j, parameter := range set.Parameters {
+			result.SetScheme[i].Parameters[j] = types.PrescriptionSetParameter{
+				ParameterTypeId: parameter.ParameterTypeId,
+				Value:           math.Round(progressed[Key(TargetParameter, parameter.ParameterTypeId)]*100) / 100,
+			}
+		}
+	}
+	return result
+}
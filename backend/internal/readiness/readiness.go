@@ -0,0 +1,299 @@
+// Package readiness scores athletes' daily check-ins and recommends how to change a
+// session when readiness is low. A check-in's score runs from 0 to 100 and is the
+// average of whichever of its measures were given: sleep, soreness, finger pain, mood
+// and resting heart rate against the athlete's baseline.
+package readiness
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// SleepTarget is the hours of sleep that score full marks
+	SleepTarget = 8.0
+	// BaselineDays is how far back resting heart rates make up the baseline
+	BaselineDays = 28
+	// MinBaselineCheckins is how many heart rates a baseline needs
+	MinBaselineCheckins = 3
+	// HeartRateTolerance is how far above the baseline a resting heart rate scores 0
+	HeartRateTolerance = 0.1
+	// TrendDays is the window trend averages are taken over
+	TrendDays = 7
+	// ReducedIntensity is the share of the prescribed intensity recommended on low days
+	ReducedIntensity = 0.8
+)
+
+// FlagLow marks training load days with a readiness score below the threshold
+const FlagLow = "readiness_low"
+
+// FingerAreas are the body areas whose soreness counts against finger training
+var FingerAreas = []string{"fingers", "forearms", "wrists", "elbows"}
+
+// Thresholds decide the recommendations. Below Score intensity is reduced and below
+// RestScore the session is rested; finger pain or finger area soreness at or above
+// their thresholds swaps finger training for antagonists.
+type Thresholds struct {
+	Score          float64
+	RestScore      float64
+	FingerPain     int32
+	FingerSoreness int32
+}
+
+var DefaultThresholds = Thresholds{Score: 60, RestScore: 35, FingerPain: 4, FingerSoreness: 6}
+
+// Checkin is a day's check-in. Soreness scores and finger pain run from 0 to 10 and
+// mood from 1 to 5.
+type Checkin struct {
+	Date       time.Time
+	SleepHours *float64
+	Soreness   map[string]int32
+	FingerPain *int32
+	Mood       *int32
+	RestingHR  *int32
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Baseline returns the average resting heart rate of the check-ins in the BaselineDays
+// before the date, nil with fewer than MinBaselineCheckins of them
+func Baseline(history []Checkin, date time.Time) *float64 {
+	date = dayOf(date)
+	since := date.AddDate(0, 0, -BaselineDays)
+	var total float64
+	var count int
+	for _, checkin := range history {
+		day := dayOf(checkin.Date)
+		if checkin.RestingHR != nil && day.Before(date) && !day.Before(since) {
+			total += float64(*checkin.RestingHR)
+			count++
+		}
+	}
+	if count < MinBaselineCheckins {
+		return nil
+	}
+	baseline := total / float64(count)
+	return &baseline
+}
+
+// Score returns a check-in's readiness from 0 to 100, nil when it measures nothing. The
+// heart rate only counts against a baseline.
+func Score(checkin Checkin, baseline *float64) *float64 {
+	var components []float64
+	if checkin.SleepHours != nil {
+		components = append(components, math.Min(*checkin.SleepHours/SleepTarget, 1))
+	}
+	if len(checkin.Soreness) > 0 {
+		var worst int32
+		for _, score := range checkin.Soreness {
+			worst = max(worst, score)
+		}
+		components = append(components, 1-float64(worst)/10)
+	}
+	if checkin.FingerPain != nil {
+		components = append(components, 1-float64(*checkin.FingerPain)/10)
+	}
+	if checkin.Mood != nil {
+		components = append(components, float64(*checkin.Mood-1)/4)
+	}
+	if checkin.RestingHR != nil && baseline != nil && *baseline > 0 {
+		elevation := (float64(*checkin.RestingHR) - *baseline) / *baseline
+		components = append(components, 1-math.Max(0, math.Min(elevation/HeartRateTolerance, 1)))
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	var total float64
+	for _, component := range components {
+		total += component
+	}
+	score := round(total / float64(len(components)) * 100)
+	return &score
+}
+
+// ScoreAll scores each check-in against the baseline of the check-ins before it
+func ScoreAll(checkins []Checkin) []*float64 {
+	scores := make([]*float64, len(checkins))
+	for i, checkin := range checkins {
+		scores[i] = Score(checkin, Baseline(checkins, checkin.Date))
+	}
+	return scores
+}
+
+// TrendDay is a day's score with the averages of the TrendDays ending on it. Averages
+// are nil when nothing was measured in the window.
+type TrendDay struct {
+	Date              time.Time
+	Score             *float64
+	ScoreAverage      *float64
+	SleepAverage      *float64
+	FingerPainAverage *float64
+	RestingHRAverage  *float64
+}
+
+type average struct {
+	total float64
+	count int
+}
+
+func (a *average) add(value float64) {
+	a.total += value
+	a.count++
+}
+
+func (a average) value() *float64 {
+	if a.count == 0 {
+		return nil
+	}
+	value := round(a.total / float64(a.count))
+	return &value
+}
+
+// Trend returns every day from from to to. Check-ins up to BaselineDays before from are
+// needed for the first days' heart rate baselines.
+func Trend(checkins []Checkin, from time.Time, to time.Time) []TrendDay {
+	from, to = dayOf(from), dayOf(to)
+	scores := ScoreAll(checkins)
+
+	var trend []TrendDay
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day := TrendDay{Date: date}
+		since := date.AddDate(0, 0, -(TrendDays - 1))
+		var score, sleep, fingerPain, restingHR average
+		for i, checkin := range checkins {
+			checkedOn := dayOf(checkin.Date)
+			if checkedOn.Equal(date) {
+				day.Score = scores[i]
+			}
+			if checkedOn.Before(since) || checkedOn.After(date) {
+				continue
+			}
+			if scores[i] != nil {
+				score.add(*scores[i])
+			}
+			if checkin.SleepHours != nil {
+				sleep.add(*checkin.SleepHours)
+			}
+			if checkin.FingerPain != nil {
+				fingerPain.add(float64(*checkin.FingerPain))
+			}
+			if checkin.RestingHR != nil {
+				restingHR.add(float64(*checkin.RestingHR))
+			}
+		}
+		day.ScoreAverage = score.value()
+		day.SleepAverage = sleep.value()
+		day.FingerPainAverage = fingerPain.value()
+		day.RestingHRAverage = restingHR.value()
+		trend = append(trend, day)
+	}
+	return trend
+}
+
+// Exercise is an exercise of a session's group
+type Exercise struct {
+	PrimaryMuscleGroups []string
+	Equipment           []string
+}
+
+// Group is a group of exercises a session can be held with
+type Group struct {
+	ID        int64
+	Name      string
+	Exercises []Exercise
+}
+
+// LoadsFingers tells whether any of the group's exercises trains the fingers or uses a
+// hangboard or campus board
+func (g Group) LoadsFingers() bool {
+	for _, exercise := range g.Exercises {
+		if slices.Contains(exercise.PrimaryMuscleGroups, "fingers") ||
+			slices.Contains(exercise.Equipment, "hangboard") ||
+			slices.Contains(exercise.Equipment, "campus_board") {
+			return true
+		}
+	}
+	return false
+}
+
+// antagonistMuscleGroups are the pushing muscles that balance climbing's pulling
+var antagonistMuscleGroups = []string{"chest", "triceps", "shoulders"}
+
+// TrainsAntagonists tells whether the group trains pushing muscles without loading the
+// fingers
+func (g Group) TrainsAntagonists() bool {
+	if g.LoadsFingers() {
+		return false
+	}
+	for _, exercise := range g.Exercises {
+		for _, muscleGroup := range exercise.PrimaryMuscleGroups {
+			if slices.Contains(antagonistMuscleGroups, muscleGroup) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Kinds of recommended action
+const (
+	ActionRest            = "rest"
+	ActionReduceIntensity = "reduce_intensity"
+	ActionSwapGroup       = "swap_group"
+)
+
+// Action is a recommended change to a session. A swap names the group to hold the
+// session with instead.
+type Action struct {
+	Kind            string
+	Reason          string
+	IntensityFactor float64
+	Group           *Group
+}
+
+// fingersStrained tells whether finger pain or soreness crosses its threshold
+func fingersStrained(checkin Checkin, thresholds Thresholds) bool {
+	if checkin.FingerPain != nil && *checkin.FingerPain >= thresholds.FingerPain {
+		return true
+	}
+	for _, area := range FingerAreas {
+		if score, ok := checkin.Soreness[area]; ok && score >= thresholds.FingerSoreness {
+			return true
+		}
+	}
+	return false
+}
+
+// Recommend returns the changes to make to a session held with a group, given the day's
+// check-in and its score. Alternatives are the other groups the session could be held
+// with, in order of preference. A rest replaces every other change.
+func Recommend(checkin Checkin, score *float64, session Group, alternatives []Group, thresholds Thresholds) []Action {
+	if score != nil && *score < thresholds.RestScore {
+		return []Action{{Kind: ActionRest, Reason: "Readiness is too low to train"}}
+	}
+
+	var actions []Action
+	reduced := false
+	if score != nil && *score < thresholds.Score {
+		actions = append(actions, Action{Kind: ActionReduceIntensity, Reason: "Readiness is low", IntensityFactor: ReducedIntensity})
+		reduced = true
+	}
+	if session.LoadsFingers() && fingersStrained(checkin, thresholds) {
+		index := slices.IndexFunc(alternatives, func(group Group) bool {
+			return group.ID != session.ID && group.TrainsAntagonists()
+		})
+		if index >= 0 {
+			actions = append(actions, Action{Kind: ActionSwapGroup, Reason: "Fingers need rest; train antagonists instead", Group: &alternatives[index]})
+		} else if !reduced {
+			actions = append(actions, Action{Kind: ActionReduceIntensity, Reason: "Fingers need rest and there is no antagonist group to swap to", IntensityFactor: ReducedIntensity})
+		}
+	}
+	return actions
+}
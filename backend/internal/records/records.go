@@ -0,0 +1,194 @@
+// Package records detects personal records in logged sets. Records are kept per exercise
+// variation and parameter type: the heaviest load and best estimated max for weight
+// parameters, the most reps done at each load, and the longest duration for time
+// parameters such as hangs.
+package records
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Kinds of personal record
+const (
+	KindHeaviestLoad    = "heaviest_load"
+	KindLongestDuration = "longest_duration"
+	// KindMostReps is kept per load. More reps at a load only count when no heavier load
+	// was done for as many reps.
+	KindMostReps     = "most_reps"
+	KindEstimatedMax = "estimated_max"
+)
+
+var Kinds = []string{KindHeaviestLoad, KindLongestDuration, KindMostReps, KindEstimatedMax}
+
+// Data types of the parameter types records are detected for
+const (
+	DataTypeWeight = "weight"
+	DataTypeTime   = "time"
+)
+
+// MaxEstimateReps is the most reps a max is estimated from; longer sets say little
+// about a single rep
+const MaxEstimateReps = 12
+
+type Parameter struct {
+	ParameterTypeID int64
+	DataType        string
+	Value           float64
+}
+
+// Set is a logged set. A set logged with 0 reps was failed and sets no record.
+type Set struct {
+	VariationID int64
+	Reps        *int32
+	Parameters  []Parameter
+}
+
+type Record struct {
+	Kind            string
+	VariationID     int64
+	ParameterTypeID int64
+	// Value is the load, duration, reps or estimated max
+	Value float64
+	// Load is the load most reps were done at
+	Load *float64
+	// Reps are the reps of the set the record was set with
+	Reps *int32
+	// Previous is the record this one beat, nil for the first of its kind
+	Previous *float64
+	// Set is the index of the set the record was set with
+	Set int
+}
+
+type key struct {
+	kind            string
+	variationID     int64
+	parameterTypeID int64
+	load            float64
+}
+
+func (r Record) key() key {
+	k := key{kind: r.Kind, variationID: r.VariationID, parameterTypeID: r.ParameterTypeID}
+	if r.Load != nil {
+		k.load = *r.Load
+	}
+	return k
+}
+
+// EstimatedMax estimates a one rep max from a set with the Epley formula
+func EstimatedMax(load float64, reps int32) (float64, bool) {
+	if load <= 0 || reps < 1 || reps > MaxEstimateReps {
+		return 0, false
+	}
+	if reps == 1 {
+		return load, true
+	}
+	return math.Round(load*(1+float64(reps)/30)*10) / 10, true
+}
+
+// candidates are the records a set would set for one of its parameters
+func candidates(index int, set Set, parameter Parameter) []Record {
+	if parameter.Value <= 0 || (set.Reps != nil && *set.Reps < 1) {
+		return nil
+	}
+	record := func(kind string, value float64) Record {
+		return Record{Kind: kind, VariationID: set.VariationID, ParameterTypeID: parameter.ParameterTypeID, Value: value, Reps: set.Reps, Set: index}
+	}
+
+	switch parameter.DataType {
+	case DataTypeTime:
+		return []Record{record(KindLongestDuration, parameter.Value)}
+	case DataTypeWeight:
+		result := []Record{record(KindHeaviestLoad, parameter.Value)}
+		if set.Reps == nil {
+			return result
+		}
+		load := parameter.Value
+		mostReps := record(KindMostReps, float64(*set.Reps))
+		mostReps.Load = &load
+		result = append(result, mostReps)
+		if estimate, ok := EstimatedMax(load, *set.Reps); ok {
+			result = append(result, record(KindEstimatedMax, estimate))
+		}
+		return result
+	}
+	return nil
+}
+
+// beaten returns the record a candidate has to beat, nil when there is none
+func beaten(records map[key]Record, candidate Record) *float64 {
+	if candidate.Kind != KindMostReps {
+		if current, ok := records[candidate.key()]; ok {
+			return &current.Value
+		}
+		return nil
+	}
+	var best *float64
+	for _, record := range records {
+		if record.Kind == KindMostReps && record.VariationID == candidate.VariationID && record.ParameterTypeID == candidate.ParameterTypeID && *record.Load >= *candidate.Load {
+			if best == nil || record.Value > *best {
+				value := record.Value
+				best = &value
+			}
+		}
+	}
+	return best
+}
+
+// dominates reports whether a most reps record makes another redundant: as many reps at
+// a load at least as heavy
+func dominates(a Record, b Record) bool {
+	return a.Kind == KindMostReps && b.Kind == KindMostReps && a.key() != b.key() &&
+		a.VariationID == b.VariationID && a.ParameterTypeID == b.ParameterTypeID &&
+		*a.Load >= *b.Load && a.Value >= b.Value
+}
+
+// Detect returns the records the sets set, in the order of the sets, given the current
+// records. A record beaten again later in the same sets is returned once, with its best
+// value and the record it beat before the sets.
+func Detect(current []Record, sets []Set) []Record {
+	records := make(map[key]Record, len(current))
+	for _, record := range current {
+		records[record.key()] = record
+	}
+
+	found := make(map[key]Record)
+	for i, set := range sets {
+		for _, parameter := range set.Parameters {
+			for _, candidate := range candidates(i, set, parameter) {
+				previous := beaten(records, candidate)
+				if previous != nil && candidate.Value <= *previous {
+					continue
+				}
+				candidate.Previous = previous
+				if earlier, ok := found[candidate.key()]; ok {
+					candidate.Previous = earlier.Previous
+				}
+				records[candidate.key()] = candidate
+				found[candidate.key()] = candidate
+			}
+		}
+	}
+
+	result := make([]Record, 0, len(found))
+	for _, record := range found {
+		redundant := false
+		for _, other := range found {
+			redundant = redundant || dominates(other, record)
+		}
+		if !redundant {
+			result = append(result, record)
+		}
+	}
+	slices.SortFunc(result, func(a, b Record) int {
+		if a.Set != b.Set {
+			return cmp.Compare(a.Set, b.Set)
+		}
+		if a.Kind != b.Kind {
+			return cmp.Compare(slices.Index(Kinds, a.Kind), slices.Index(Kinds, b.Kind))
+		}
+		return cmp.Compare(a.ParameterTypeID, b.ParameterTypeID)
+	})
+	return result
+}
@@ -0,0 +1,260 @@
+package schedule
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Interval is a plan interval to lay out. Intervals follow each other in the order given.
+type Interval struct {
+	ID     int64
+	Days   int
+	Groups []Group
+}
+
+// Group is a group assigned to an interval. Frequency is the number of sessions per week.
+// A group with pinned days is only held on those weekdays.
+type Group struct {
+	ID         int64
+	Frequency  int32
+	PinnedDays []time.Weekday
+}
+
+// Session is one group session placed on a date. Pinned sessions belong to a group
+// pinned to weekdays and are only held on those days.
+type Session struct {
+	IntervalID int64
+	GroupID    int64
+	Date       time.Time
+	Pinned     bool
+}
+
+// Options are an athlete's scheduling preferences
+type Options struct {
+	// TrainingDays are the weekdays sessions may be placed on. Empty allows every day.
+	TrainingDays []time.Weekday
+	// MinRestDays is the number of days to keep free between two sessions
+	MinRestDays int
+}
+
+func (o Options) allows(day time.Time) bool {
+	if len(o.TrainingDays) == 0 {
+		return true
+	}
+	for _, weekday := range o.TrainingDays {
+		if day.Weekday() == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionCount is how many sessions a group gets in an interval: its weekly frequency
+// scaled to the interval's length, and at least one
+func SessionCount(frequency int32, days int) int {
+	count := int(math.Round(float64(frequency) * float64(days) / 7))
+	if count < 1 {
+		return 1
+	}
+	return count
+}
+
+// Truncate drops the time of day, so dates compare by calendar day
+func Truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Generate lays the intervals out back to back from the start date and spreads each
+// group's sessions evenly across its interval. Groups are staggered so that groups with
+// the same frequency do not land on the same days.
+func Generate(start time.Time, intervals []Interval) []Session {
+	return GenerateWithOptions(start, intervals, Options{})
+}
+
+// GenerateWithOptions is Generate for an athlete with preferred training days and rest.
+// Groups pinned to weekdays stay on them. Other sessions keep to one per day and to the
+// rest days where the interval has room for it; an interval with more sessions than
+// allowed days doubles up on its least used training days rather than spilling into the
+// next interval.
+func GenerateWithOptions(start time.Time, intervals []Interval, options Options) []Session {
+	start = Truncate(start)
+	constrained := len(options.TrainingDays) > 0 || options.MinRestDays > 0
+
+	type slot struct {
+		position float64
+		pinned   bool
+		session  Session
+	}
+
+	var sessions []Session
+	var previous time.Time
+	used := make(map[time.Time]int)
+	intervalStart := start
+	for _, interval := range intervals {
+		days := interval.Days
+		if days < 1 {
+			days = 1
+		}
+		intervalEnd := intervalStart.AddDate(0, 0, days)
+
+		var slots []slot
+		for i, group := range interval.Groups {
+			count := SessionCount(group.Frequency, days)
+			if pinned := pinnedDays(intervalStart, days, group.PinnedDays); len(pinned) > 0 {
+				for k := 0; k < count; k++ {
+					day := pinned[k*len(pinned)/count%len(pinned)]
+					slots = append(slots, slot{
+						position: float64(day),
+						pinned:   true,
+						session: Session{
+							IntervalID: interval.ID,
+							GroupID:    group.ID,
+							Date:       intervalStart.AddDate(0, 0, day),
+							Pinned:     true,
+						},
+					})
+				}
+				continue
+			}
+
+			offset := float64(i) / float64(len(interval.Groups))
+			for k := 0; k < count; k++ {
+				position := (float64(k) + offset) * float64(days) / float64(count)
+				slots = append(slots, slot{
+					position: position,
+					session: Session{
+						IntervalID: interval.ID,
+						GroupID:    group.ID,
+						Date:       intervalStart.AddDate(0, 0, int(position)),
+					},
+				})
+			}
+		}
+		sort.SliceStable(slots, func(a, b int) bool { return slots[a].position < slots[b].position })
+
+		// Pinned sessions claim their days before the others are placed around them
+		for _, s := range slots {
+			if s.pinned {
+				used[s.session.Date]++
+			}
+		}
+		for _, s := range slots {
+			if constrained && !s.pinned {
+				s.session.Date = place(s.session.Date, previous, intervalStart, intervalEnd, used, options)
+				used[s.session.Date]++
+			}
+			previous = s.session.Date
+			sessions = append(sessions, s.session)
+		}
+
+		intervalStart = intervalEnd
+	}
+	return sessions
+}
+
+// pinnedDays lists the day offsets within an interval that fall on the pinned weekdays
+func pinnedDays(start time.Time, days int, weekdays []time.Weekday) []int {
+	var offsets []int
+	for offset := 0; offset < days && len(weekdays) > 0; offset++ {
+		for _, weekday := range weekdays {
+			if start.AddDate(0, 0, offset).Weekday() == weekday {
+				offsets = append(offsets, offset)
+				break
+			}
+		}
+	}
+	return offsets
+}
+
+// place finds the day for a session that ideally falls on target, within [from, to)
+func place(target time.Time, previous time.Time, from time.Time, to time.Time, used map[time.Time]int, options Options) time.Time {
+	earliest := target
+	if !previous.IsZero() {
+		if rested := previous.AddDate(0, 0, options.MinRestDays+1); rested.After(earliest) {
+			earliest = rested
+		}
+	}
+
+	// Keep the rest days, then give up rest, then give up the preferred position
+	for _, candidateFrom := range []time.Time{earliest, maxTime(target, previous.AddDate(0, 0, 1)), from} {
+		for day := candidateFrom; day.Before(to); day = day.AddDate(0, 0, 1) {
+			if options.allows(day) && used[day] == 0 {
+				return day
+			}
+		}
+	}
+
+	// The interval is full, so share the least used training day, preferring later ones
+	best := time.Time{}
+	for day := to.AddDate(0, 0, -1); !day.Before(from); day = day.AddDate(0, 0, -1) {
+		if options.allows(day) && (best.IsZero() || used[day] < used[best]) {
+			best = day
+		}
+	}
+	if best.IsZero() {
+		return target
+	}
+	return best
+}
+
+func maxTime(a time.Time, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Reflow moves pending sessions onto the next allowed days from the given date, in their
+// current order and keeping the rest days, so a missed session pushes the rest of the
+// schedule back instead of being lost. last is the most recent session that stays put.
+// Pinned sessions keep their dates, or move to the same weekday from the given date when
+// they were missed, and the other sessions are reflowed around them.
+func Reflow(pending []Session, from time.Time, last time.Time, options Options) []Session {
+	from = Truncate(from)
+	reflowed := make([]Session, len(pending))
+	taken := make(map[time.Time]bool)
+	latest := from
+	for i, session := range pending {
+		if !session.Pinned {
+			continue
+		}
+		day := Truncate(session.Date)
+		for day.Before(from) || taken[day] {
+			day = day.AddDate(0, 0, 7)
+		}
+		session.Date = day
+		reflowed[i] = session
+		taken[day] = true
+		latest = maxTime(latest, day)
+	}
+
+	cursor := from
+	if !last.IsZero() {
+		if rested := Truncate(last).AddDate(0, 0, options.MinRestDays+1); rested.After(cursor) {
+			cursor = rested
+		}
+	}
+	for i, session := range pending {
+		if session.Pinned {
+			continue
+		}
+		cursor = options.next(cursor, taken, maxTime(latest, cursor).AddDate(0, 0, 7))
+		session.Date = cursor
+		reflowed[i] = session
+		cursor = cursor.AddDate(0, 0, options.MinRestDays+1)
+	}
+	return reflowed
+}
+
+// next returns the first allowed day from day on that is not taken. Past until every
+// weekday has been tried without a taken day in the way, so when none is found the
+// options allow no day at all and day is returned unchanged.
+func (o Options) next(day time.Time, taken map[time.Time]bool, until time.Time) time.Time {
+	for candidate := day; !candidate.After(until); candidate = candidate.AddDate(0, 0, 1) {
+		if o.allows(candidate) && !taken[candidate] {
+			return candidate
+		}
+	}
+	return day
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"backend/db"
+	"backend/internal/types"
+	"fmt"
+	"sort"
+)
+
+// CheckPlanEquipment compares the equipment a plan's exercises require against an athlete's
+// declared inventory and reports every piece that is missing along with the exercises needing it.
+func CheckPlanEquipment(planId int64, userId int64, exercises []db.Exercises_ListEquipmentByPlanIdRow, inventory []string) types.EquipmentCheck {
+	owned := make(map[string]bool, len(inventory))
+	for _, item := range inventory {
+		owned[item] = true
+	}
+
+	required := make(map[string]bool)
+	missingByEquipment := make(map[string][]types.ExerciseSummary)
+	for _, exercise := range exercises {
+		for _, item := range exercise.Equipment {
+			required[item] = true
+			if !owned[item] {
+				missingByEquipment[item] = append(missingByEquipment[item], types.ExerciseSummary{
+					ID:   exercise.ID,
+					Name: exercise.Name,
+				})
+			}
+		}
+	}
+
+	requiredEquipment := make([]string, 0, len(required))
+	for item := range required {
+		requiredEquipment = append(requiredEquipment, item)
+	}
+	sort.Strings(requiredEquipment)
+
+	missing := make([]types.MissingEquipment, 0, len(missingByEquipment))
+	for _, item := range requiredEquipment {
+		exercisesNeeding, ok := missingByEquipment[item]
+		if !ok {
+			continue
+		}
+		missing = append(missing, types.MissingEquipment{
+			Equipment: item,
+			Exercises: exercisesNeeding,
+			Message:   fmt.Sprintf("%d exercise(s) require %s, which is not in the athlete's equipment", len(exercisesNeeding), item),
+		})
+	}
+
+	if inventory == nil {
+		inventory = []string{}
+	}
+
+	return types.EquipmentCheck{
+		PlanID:            planId,
+		UserID:            userId,
+		RequiredEquipment: requiredEquipment,
+		UserEquipment:     inventory,
+		Missing:           missing,
+		HasAllEquipment:   len(missing) == 0,
+	}
+}
@@ -24,15 +24,36 @@ func (s *IntervalGroupAssignmentsService) GetByGroupId(ctx context.Context, grou
 	return s.repo.GetByGroupId(ctx, groupId)
 }
 
-func (s *IntervalGroupAssignmentsService) AssignGroupToInterval(ctx context.Context, planIntervalId int64, groupId int64, frequency int32) (*types.IntervalGroupAssignment, error) {
+func (s *IntervalGroupAssignmentsService) GetAssignment(ctx context.Context, planIntervalId int64, groupId int64) (*types.IntervalGroupAssignment, error) {
+	return s.repo.GetOne(ctx, planIntervalId, groupId)
+}
+
+// AssignGroupToInterval adds a group to an interval. Without an order the group goes after
+// the groups already in the interval.
+func (s *IntervalGroupAssignmentsService) AssignGroupToInterval(ctx context.Context, planIntervalId int64, groupId int64, frequency int32, pinnedDays []int32, order *int32) (*types.IntervalGroupAssignment, error) {
 	assignment := types.IntervalGroupAssignment{
 		PlanIntervalId: planIntervalId,
 		GroupId:        groupId,
 		Frequency:      frequency,
+		PinnedDays:     pinnedDays,
+	}
+	if order != nil {
+		assignment.Order = *order
+	} else {
+		next, err := s.repo.NextOrder(ctx, planIntervalId)
+		if err != nil {
+			return nil, err
+		}
+		assignment.Order = next
 	}
 	return s.repo.CreateOne(ctx, assignment)
 }
 
+// UpdateAssignment changes the frequency, pinned days and order of an existing assignment
+func (s *IntervalGroupAssignmentsService) UpdateAssignment(ctx context.Context, assignment types.IntervalGroupAssignment) (*types.IntervalGroupAssignment, error) {
+	return s.repo.UpdateOne(ctx, assignment)
+}
+
 func (s *IntervalGroupAssignmentsService) RemoveGroupFromInterval(ctx context.Context, planIntervalId int64, groupId int64) error {
 	return s.repo.DeleteOne(ctx, planIntervalId, groupId)
 }
@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+)
+
+// S3Storage talks to any S3-compatible object store (AWS S3, MinIO, R2, ...)
+// using plain HTTP requests signed with AWS Signature Version 4.
+type S3Storage struct {
+	// Endpoint is the base URL of the service, e.g. "https://s3.us-east-1.amazonaws.com"
+	// or "http://localhost:9000" for a local MinIO
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// UsePathStyle addresses objects as Endpoint/Bucket/Key instead of Bucket.Endpoint/Key.
+	// Most self-hosted stand-ins need path-style addressing.
+	UsePathStyle bool
+	Client       *http.Client
+	Now          func() time.Time
+}
+
+func NewS3Storage(endpoint, region, bucket, accessKey, secretKey string, usePathStyle bool) *S3Storage {
+	return &S3Storage{
+		Endpoint:     strings.TrimSuffix(endpoint, "/"),
+		Region:       region,
+		Bucket:       bucket,
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+		UsePathStyle: usePathStyle,
+		Client:       http.DefaultClient,
+		Now:          time.Now,
+	}
+}
+
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid S3 endpoint: %w", err)
+	}
+
+	objectURL := *endpoint
+	if s.UsePathStyle {
+		objectURL.Path = "/" + s.Bucket + "/" + key
+	} else {
+		objectURL.Host = s.Bucket + "." + endpoint.Host
+		objectURL.Path = "/" + key
+	}
+	return &objectURL, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	return s.do(req, http.StatusOK)
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.signRequest(req)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, objectURL.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(req, http.StatusNoContent, http.StatusOK)
+}
+
+// SignedURL returns a presigned GET URL (SigV4 query string authentication)
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.Now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	scope := s.scope(now)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		uriEncode(objectURL.Path, false),
+		canonicalQuery(query),
+		"host:" + objectURL.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", s.signature(now, amzDate, scope, canonicalRequest))
+	objectURL.RawQuery = canonicalQuery(query)
+	return objectURL.String(), nil
+}
+
+func (s *S3Storage) do(req *http.Request, expectedStatus ...int) error {
+	s.signRequest(req)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	for _, status := range expectedStatus {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	return s3Error(resp)
+}
+
+// signRequest adds SigV4 authorization headers. Payloads are sent unsigned so
+// uploads can be streamed without buffering them to compute a hash.
+func (s *S3Storage) signRequest(req *http.Request) {
+	now := s.Now().UTC()
+	amzDate := now.Format(s3TimeFormat)
+	scope := s.scope(now)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": s3UnsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		headers["content-type"] = contentType
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	signature := s.signature(now, amzDate, scope, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.AccessKey, scope, signedHeaders, signature))
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return strings.Join([]string{now.Format(s3DateFormat), s.Region, s3Service, "aws4_request"}, "/")
+}
+
+func (s *S3Storage) signature(now time.Time, amzDate string, scope string, canonicalRequest string) string {
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and strictly URI-encodes query parameters as SigV4 requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes everything except unreserved characters. Slashes
+// are kept when encoding a path.
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("storage: S3 request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
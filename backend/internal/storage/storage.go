@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"backend/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// Storage is a blob store for uploaded files. Keys are slash separated paths
+// such as "exercises/12/abc123.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that allows downloading the object until it expires
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// ValidateKey rejects empty, absolute and parent-relative keys
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	if path.Clean(key) != key {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || segment == "." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+// LocalDownloadPath is where the API serves objects from LocalStorage
+const LocalDownloadPath = "/api/v1/media"
+
+// New builds the storage backend selected by the config
+func New(cfg config.StorageConfig, publicURL string) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalRoot, strings.TrimSuffix(publicURL, "/")+LocalDownloadPath, cfg.SigningSecret), nil
+	case "s3":
+		if cfg.S3Bucket == "" {
+			return nil, errors.New("storage: S3_BUCKET is required for the s3 backend")
+		}
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3PathStyle), nil
+	}
+	return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+}
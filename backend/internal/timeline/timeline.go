@@ -0,0 +1,192 @@
+// Package timeline compiles the prescriptions of a group into the segments a workout
+// timer runs through: work, rest between reps and sets, and transitions between
+// exercises. Every client times a session from the same compiled timeline.
+package timeline
+
+import "time"
+
+// Segment kinds
+const (
+	SegmentWork       = "work"
+	SegmentRest       = "rest"
+	SegmentTransition = "transition"
+)
+
+// DefaultTransition is the time allowed to move on to the next exercise
+const DefaultTransition = 30 * time.Second
+
+// Set is one resolved set of a prescription. A set with sub-reps and a sub-rep work
+// duration is a repeater, e.g. 6 hangs of 7 seconds on and 3 seconds off. Otherwise it
+// is a single work segment of Work, which is untimed when Work is 0.
+type Set struct {
+	Reps       int32
+	Work       time.Duration
+	SubReps    int32
+	SubRepWork time.Duration
+	SubRepRest time.Duration
+	// Rest follows the set unless it is the last one of the prescription
+	Rest time.Duration
+}
+
+// Prescription is a prescription in the group's order. BlockID links prescriptions
+// into a superset or circuit and is 0 otherwise.
+type Prescription struct {
+	ID        int64
+	Exercise  string
+	Variation string
+	BlockID   int64
+	Sets      []Set
+}
+
+// Block is a superset or circuit. Its members are performed one set at a time, round
+// after round, with Rest between rounds. Without a rest of its own the rest of the
+// round's last set is used.
+type Block struct {
+	ID      int64
+	Rest    time.Duration
+	HasRest bool
+}
+
+// Segment is one step of the timer. Transitions name the prescription that comes next.
+type Segment struct {
+	Kind           string
+	PrescriptionID int64
+	Exercise       string
+	Variation      string
+	// Set and Rep count from 1. Rep is 0 outside of repeaters.
+	Set   int32
+	Rep   int32
+	Round int32
+	// Reps to perform during an untimed work segment
+	Reps     int32
+	Start    time.Duration
+	Duration time.Duration
+	Timed    bool
+}
+
+func (s Segment) End() time.Duration {
+	return s.Start + s.Duration
+}
+
+// Timeline is a compiled session with its total time
+type Timeline struct {
+	Segments []Segment
+	Total    time.Duration
+	// Untimed counts the work segments that last as long as the athlete takes, so the
+	// total is a lower bound when it is not 0
+	Untimed int
+}
+
+// compiler appends segments with cumulative start times
+type compiler struct {
+	timeline Timeline
+}
+
+func (c *compiler) add(segment Segment) {
+	if segment.Kind != SegmentWork && segment.Duration <= 0 {
+		return
+	}
+	segment.Start = c.timeline.Total
+	if segment.Kind == SegmentWork && segment.Duration <= 0 {
+		segment.Duration = 0
+		c.timeline.Untimed++
+	} else {
+		segment.Timed = true
+	}
+	c.timeline.Segments = append(c.timeline.Segments, segment)
+	c.timeline.Total += segment.Duration
+}
+
+// set adds the work of one set, with rest between the reps of a repeater
+func (c *compiler) set(prescription Prescription, number int32, round int32) {
+	set := prescription.Sets[number-1]
+	segment := Segment{PrescriptionID: prescription.ID, Exercise: prescription.Exercise, Variation: prescription.Variation, Set: number, Round: round}
+	if set.SubReps < 1 || set.SubRepWork <= 0 {
+		segment.Kind = SegmentWork
+		segment.Reps = set.Reps
+		segment.Duration = set.Work
+		c.add(segment)
+		return
+	}
+	for rep := int32(1); rep <= set.SubReps; rep++ {
+		segment.Rep = rep
+		segment.Kind = SegmentWork
+		segment.Duration = set.SubRepWork
+		c.add(segment)
+		if rep < set.SubReps {
+			segment.Kind = SegmentRest
+			segment.Duration = set.SubRepRest
+			c.add(segment)
+		}
+	}
+}
+
+func (c *compiler) rest(prescription Prescription, number int32, round int32, duration time.Duration) {
+	c.add(Segment{Kind: SegmentRest, PrescriptionID: prescription.ID, Exercise: prescription.Exercise, Variation: prescription.Variation, Set: number, Round: round, Duration: duration})
+}
+
+func (c *compiler) transition(next Prescription, duration time.Duration) {
+	c.add(Segment{Kind: SegmentTransition, PrescriptionID: next.ID, Exercise: next.Exercise, Variation: next.Variation, Duration: duration})
+}
+
+// Compile lays the prescriptions out in order. Prescriptions of the same block must
+// follow each other. transition is the time between exercises, both between
+// prescriptions and between the members of a superset or circuit.
+func Compile(prescriptions []Prescription, blocks map[int64]Block, transition time.Duration) Timeline {
+	c := &compiler{timeline: Timeline{Segments: []Segment{}}}
+
+	for i := 0; i < len(prescriptions); {
+		// A unit is a single prescription or the members of a block
+		end := i + 1
+		if prescriptions[i].BlockID != 0 {
+			for end < len(prescriptions) && prescriptions[end].BlockID == prescriptions[i].BlockID {
+				end++
+			}
+		}
+		unit := prescriptions[i:end]
+		if i > 0 {
+			c.transition(unit[0], transition)
+		}
+
+		if len(unit) == 1 {
+			prescription := unit[0]
+			for number := int32(1); number <= int32(len(prescription.Sets)); number++ {
+				c.set(prescription, number, 0)
+				if number < int32(len(prescription.Sets)) {
+					c.rest(prescription, number, 0, prescription.Sets[number-1].Rest)
+				}
+			}
+			i = end
+			continue
+		}
+
+		rounds := 0
+		for _, member := range unit {
+			rounds = max(rounds, len(member.Sets))
+		}
+		block := blocks[unit[0].BlockID]
+		for round := int32(1); round <= int32(rounds); round++ {
+			var last *Prescription
+			for j := range unit {
+				if int(round) > len(unit[j].Sets) {
+					continue
+				}
+				if last != nil {
+					c.transition(unit[j], transition)
+				}
+				c.set(unit[j], round, round)
+				last = &unit[j]
+			}
+			if round < int32(rounds) {
+				rest := last.Sets[round-1].Rest
+				if block.HasRest {
+					rest = block.Rest
+				}
+				c.rest(*last, round, round, rest)
+			}
+		}
+		i = end
+	}
+
+	return c.timeline
+}
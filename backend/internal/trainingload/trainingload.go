@@ -0,0 +1,151 @@
+// Package trainingload derives training load metrics from completed sessions. A session's
+// load is its session RPE times its duration in minutes. Daily loads are rolled up into
+// the acute (last week) and chronic (last four weeks) load, the acute:chronic workload
+// ratio, and the monotony and strain of the last week.
+package trainingload
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Window lengths in days, each ending on the day a metric is given for
+const (
+	AcuteDays   = 7
+	ChronicDays = 28
+)
+
+// Flags set on days that cross a threshold
+const (
+	FlagACWRHigh     = "acwr_high"
+	FlagACWRLow      = "acwr_low"
+	FlagMonotonyHigh = "monotony_high"
+	FlagStrainHigh   = "strain_high"
+)
+
+// Thresholds are the limits beyond which a day is flagged. A ratio above ACWRHigh is a
+// spike in load; one below ACWRLow is detraining.
+type Thresholds struct {
+	ACWRHigh float64
+	ACWRLow  float64
+	Monotony float64
+	Strain   float64
+}
+
+// DefaultThresholds are used for athletes who have not set their own
+var DefaultThresholds = Thresholds{ACWRHigh: 1.5, ACWRLow: 0.8, Monotony: 2, Strain: 6000}
+
+// Validate checks that the thresholds are positive and the ratio range is not empty
+func (t Thresholds) Validate() error {
+	if t.ACWRHigh <= 0 || t.ACWRLow <= 0 || t.Monotony <= 0 || t.Strain <= 0 {
+		return fmt.Errorf("thresholds must be positive")
+	}
+	if t.ACWRLow >= t.ACWRHigh {
+		return fmt.Errorf("the low ACWR threshold must be below the high one")
+	}
+	return nil
+}
+
+// Session is a completed session rated with a session RPE
+type Session struct {
+	Date     time.Time
+	RPE      int32
+	Duration time.Duration
+}
+
+// SessionLoad returns the load of a session in arbitrary units
+func SessionLoad(rpe int32, duration time.Duration) float64 {
+	return round(float64(rpe) * duration.Minutes())
+}
+
+// Day holds the metrics of a day. The ratio is nil without chronic load, and monotony and
+// strain are nil when the last week's loads do not vary.
+type Day struct {
+	Date     time.Time
+	Load     float64
+	Acute    float64
+	Chronic  float64
+	ACWR     *float64
+	Monotony *float64
+	Strain   *float64
+	Flags    []string
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Series returns the metrics of every day from from to to. Sessions up to ChronicDays - 1
+// days before from are needed for the first days' rolling loads; earlier and later
+// sessions are ignored.
+func Series(sessions []Session, from time.Time, to time.Time, thresholds Thresholds) []Day {
+	from, to = dayOf(from), dayOf(to)
+	start := from.AddDate(0, 0, -(ChronicDays - 1))
+	days := int(to.Sub(start).Hours()/24) + 1
+	if days < ChronicDays {
+		return nil
+	}
+
+	loads := make([]float64, days)
+	for _, session := range sessions {
+		index := int(dayOf(session.Date).Sub(start).Hours() / 24)
+		if index >= 0 && index < days {
+			loads[index] += float64(session.RPE) * session.Duration.Minutes()
+		}
+	}
+
+	series := make([]Day, 0, days-ChronicDays+1)
+	for index := ChronicDays - 1; index < days; index++ {
+		week := loads[index-AcuteDays+1 : index+1]
+		var acute, chronic float64
+		for _, load := range week {
+			acute += load
+		}
+		for _, load := range loads[index-ChronicDays+1 : index+1] {
+			chronic += load
+		}
+		// Chronic load is the average week of the window, so it compares with the acute
+		chronic = chronic * AcuteDays / ChronicDays
+
+		day := Day{
+			Date:    start.AddDate(0, 0, index),
+			Load:    round(loads[index]),
+			Acute:   round(acute),
+			Chronic: round(chronic),
+		}
+		if chronic > 0 {
+			ratio := round(acute / chronic)
+			day.ACWR = &ratio
+			if ratio > thresholds.ACWRHigh {
+				day.Flags = append(day.Flags, FlagACWRHigh)
+			} else if ratio < thresholds.ACWRLow {
+				day.Flags = append(day.Flags, FlagACWRLow)
+			}
+		}
+
+		mean := acute / AcuteDays
+		var variance float64
+		for _, load := range week {
+			variance += (load - mean) * (load - mean)
+		}
+		if sd := math.Sqrt(variance / AcuteDays); sd > 0 {
+			monotony := mean / sd
+			strain := round(acute * monotony)
+			monotony = round(monotony)
+			day.Monotony, day.Strain = &monotony, &strain
+			if monotony > thresholds.Monotony {
+				day.Flags = append(day.Flags, FlagMonotonyHigh)
+			}
+			if strain > thresholds.Strain {
+				day.Flags = append(day.Flags, FlagStrainHigh)
+			}
+		}
+		series = append(series, day)
+	}
+	return series
+}
@@ -1,15 +1,21 @@
 package types
 
+import "encoding/json"
+
 type Plan struct {
-	ID          int64          `json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description,omitempty"`
-	UserID      int64          `json:"userId"`
-	CreatedAt   string         `json:"createdAt"`
-	UpdatedAt   string         `json:"updatedAt"`
-	IsTemplate  bool           `json:"isTemplate"`
-	IsPublic    bool           `json:"isPublic"`
-	Intervals   []PlanInterval `json:"intervals,omitempty"`
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	Description  string         `json:"description,omitempty"`
+	UserID       int64          `json:"userId"`
+	CreatedAt    string         `json:"createdAt"`
+	UpdatedAt    string         `json:"updatedAt"`
+	IsTemplate   bool           `json:"isTemplate"`
+	IsPublic     bool           `json:"isPublic"`
+	StartDate    string         `json:"startDate,omitempty"`
+	SourcePlanID *int64         `json:"sourcePlanId,omitempty"`
+	Permission   string         `json:"permission,omitempty"` // what the user may do with a plan shared with them
+	Intervals    []PlanInterval `json:"intervals,omitempty"`
+	CommentCounts
 }
 
 // Standard API response structure for success
@@ -36,9 +42,11 @@ type PlanInterval struct {
 	Name        string              `json:"name"`
 	Description string              `json:"description"`
 	Order       int32               `json:"order"`
+	IsDeload    bool                `json:"isDeload"`
 	CreatedAt   string              `json:"createdAt"`
 	UpdatedAt   string              `json:"updatedAt"`
 	GroupCount  int                 `json:"groupCount"`
+	CommentCounts
 }
 
 type Group struct {
@@ -48,15 +56,31 @@ type Group struct {
 	UserID      int64  `json:"userId"`
 	CreatedAt   string `json:"createdAt"`
 	UpdatedAt   string `json:"updatedAt"`
+	CommentCounts
 }
 
 type Exercise struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	UserID      int64  `json:"userId"`
-	CreatedAt   string `json:"createdAt"`
-	UpdatedAt   string `json:"updatedAt"`
+	ID                    int64      `json:"id"`
+	Name                  string     `json:"name"`
+	Description           string     `json:"description"`
+	UserID                int64      `json:"userId"`
+	CreatedAt             string     `json:"createdAt"`
+	UpdatedAt             string     `json:"updatedAt"`
+	Categories            []Category `json:"categories,omitempty"`
+	PrimaryMuscleGroups   []string   `json:"primaryMuscleGroups"`
+	SecondaryMuscleGroups []string   `json:"secondaryMuscleGroups"`
+	Equipment             []string   `json:"equipment"`
+	Laterality            string     `json:"laterality"`
+}
+
+// Category tags an exercise with a training focus. Global categories have no UserID.
+type Category struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Icon      string `json:"icon"`
+	UserID    *int64 `json:"userId"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
 }
 
 type ParameterType struct {
@@ -96,7 +120,105 @@ type IntervalExercisePrescription struct {
 	SubReps             *int32                 `json:"subReps"`
 	SubRepWorkDuration  *PostgreSQLInterval    `json:"subRepWorkDuration"`
 	SubRepRestDuration  *PostgreSQLInterval    `json:"subRepRestDuration"`
+	Position            int32                  `json:"position"`
+	BlockId             *int64                 `json:"blockId"`
+	SetScheme           []PrescriptionSet      `json:"setScheme"`
 	ExerciseVariation   ExerciseVariation      `json:"exerciseVariation,omitempty"`
+	CommentCounts
+}
+
+// WorkoutTimeline is a group's session in an interval compiled into timer segments.
+// Times are in seconds from the start of the session.
+type WorkoutTimeline struct {
+	GroupId         int64             `json:"groupId"`
+	PlanIntervalId  int64             `json:"planIntervalId"`
+	Segments        []TimelineSegment `json:"segments"`
+	TotalSeconds    float64           `json:"totalSeconds"`
+	UntimedSegments int               `json:"untimedSegments"`
+}
+
+// TimelineSegment is one step of a workout timer: work, rest or a transition to the
+// next exercise. Untimed work lasts as long as the reps take.
+type TimelineSegment struct {
+	Kind            string  `json:"kind"`
+	PrescriptionId  int64   `json:"prescriptionId"`
+	Exercise        string  `json:"exercise"`
+	Variation       string  `json:"variation"`
+	Set             int32   `json:"set,omitempty"`
+	Rep             int32   `json:"rep,omitempty"`
+	Round           int32   `json:"round,omitempty"`
+	Reps            int32   `json:"reps,omitempty"`
+	StartSeconds    float64 `json:"startSeconds"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	EndSeconds      float64 `json:"endSeconds"`
+	Timed           bool    `json:"timed"`
+}
+
+// PrescriptionSet is one set of a prescription's per-set breakdown. Null fields fall back
+// to the prescription's uniform values.
+type PrescriptionSet struct {
+	SetNumber  int32                      `json:"setNumber"`
+	Reps       *int32                     `json:"reps"`
+	Duration   *PostgreSQLInterval        `json:"duration"`
+	RPE        *int32                     `json:"rpe"`
+	Rest       *PostgreSQLInterval        `json:"rest"`
+	Parameters []PrescriptionSetParameter `json:"parameters"`
+}
+
+// PrescriptionSetParameter is a set's value for a parameter. A percentage is of the
+// athlete's benchmark for the parameter; ResolvedValue is the absolute value for an
+// athlete when prescriptions are listed for one, missing without a benchmark.
+type PrescriptionSetParameter struct {
+	ParameterTypeId int64    `json:"parameterTypeId"`
+	Value           float64  `json:"value"`
+	IsPercentage    bool     `json:"isPercentage"`
+	ResolvedValue   *float64 `json:"resolvedValue,omitempty"`
+}
+
+// ProgressionRule moves a prescription, or every prescription of a group, forward each
+// time an interval is copied or generated. A prescription's own rule takes precedence.
+type ProgressionRule struct {
+	ID             int64             `json:"id"`
+	PrescriptionId *int64            `json:"prescriptionId"`
+	GroupId        *int64            `json:"groupId"`
+	Steps          []ProgressionStep `json:"steps"`
+	CreatedAt      string            `json:"createdAt"`
+	UpdatedAt      string            `json:"updatedAt"`
+}
+
+// ProgressionStep changes one value per interval, e.g. reps by 1 until 8. Each interval
+// applies the first step that has not reached its limit, so later steps take over.
+type ProgressionStep struct {
+	Target          string   `json:"target"`
+	ParameterTypeId *int64   `json:"parameterTypeId"`
+	Amount          float64  `json:"amount"`
+	Unit            string   `json:"unit"`
+	Until           *float64 `json:"until"`
+}
+
+// ProgressionPreview shows an interval's prescriptions as they would read in each of the
+// following intervals
+type ProgressionPreview struct {
+	PlanIntervalId int64                        `json:"planIntervalId"`
+	Intervals      []ProgressionPreviewInterval `json:"intervals"`
+}
+
+type ProgressionPreviewInterval struct {
+	Step          int32                          `json:"step"`
+	Prescriptions []IntervalExercisePrescription `json:"prescriptions"`
+}
+
+// PrescriptionBlock links prescriptions of a group into a superset or circuit.
+// PrescriptionIds are in the order the exercises are performed; Rest follows each round.
+type PrescriptionBlock struct {
+	ID              int64               `json:"id"`
+	GroupId         int64               `json:"groupId"`
+	PlanIntervalId  int64               `json:"planIntervalId"`
+	Kind            string              `json:"kind"`
+	Rest            *PostgreSQLInterval `json:"rest"`
+	PrescriptionIds []int64             `json:"prescriptionIds"`
+	CreatedAt       string              `json:"createdAt"`
+	UpdatedAt       string              `json:"updatedAt"`
 }
 
 type IntervalGroupAssignment struct {
@@ -104,6 +226,435 @@ type IntervalGroupAssignment struct {
 	PlanIntervalId int64         `json:"planIntervalId"`
 	GroupId        int64         `json:"groupId"`
 	Frequency      int32         `json:"frequency"`
+	PinnedDays     []int32       `json:"pinnedDays"` // weekdays from Sunday = 0; empty leaves the days to the scheduler
+	Order          int32         `json:"order"`
 	Group          *Group        `json:"group,omitempty"`
 	PlanInterval   *PlanInterval `json:"planInterval,omitempty"`
 }
+
+// MuscleGroupVolume is the prescribed training volume that lands on one muscle group.
+// Secondary sets count at half weight in WeightedSets.
+type MuscleGroupVolume struct {
+	MuscleGroup   string  `json:"muscleGroup"`
+	PrimarySets   int32   `json:"primarySets"`
+	SecondarySets int32   `json:"secondarySets"`
+	WeightedSets  float64 `json:"weightedSets"`
+	TotalReps     int32   `json:"totalReps"`
+}
+
+type ExerciseSummary struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// MissingEquipment is a piece of equipment a plan needs that the athlete has not declared
+type MissingEquipment struct {
+	Equipment string            `json:"equipment"`
+	Exercises []ExerciseSummary `json:"exercises"`
+	Message   string            `json:"message"`
+}
+
+type EquipmentCheck struct {
+	PlanID            int64              `json:"planId"`
+	UserID            int64              `json:"userId"`
+	RequiredEquipment []string           `json:"requiredEquipment"`
+	UserEquipment     []string           `json:"userEquipment"`
+	Missing           []MissingEquipment `json:"missing"`
+	HasAllEquipment   bool               `json:"hasAllEquipment"`
+}
+
+// ExerciseMedia is an image or video attached to an exercise. URLs are signed and expire.
+type ExerciseMedia struct {
+	ID           int64   `json:"id"`
+	ExerciseID   int64   `json:"exerciseId"`
+	FileName     string  `json:"fileName"`
+	ContentType  string  `json:"contentType"`
+	SizeBytes    int64   `json:"sizeBytes"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnailUrl"`
+	ExpiresAt    string  `json:"expiresAt"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
+// LibraryImportIssue is a problem found while checking an exercise library import.
+// Blocking issues stop the import; the others only skip or annotate entries.
+type LibraryImportIssue struct {
+	Kind     string `json:"kind"` // "invalid", "duplicate", "conflict" or "mismatch"
+	Blocking bool   `json:"blocking"`
+	Line     int    `json:"line,omitempty"`
+	Exercise string `json:"exercise,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LibraryImportReport describes what an exercise library import created, or would create on a dry run
+type LibraryImportReport struct {
+	DryRun                bool                 `json:"dryRun"`
+	Valid                 bool                 `json:"valid"`
+	Exercises             []string             `json:"exercises"`
+	VariationsCreated     int                  `json:"variationsCreated"`
+	ParameterTypesCreated []string             `json:"parameterTypesCreated"`
+	ParameterTypesReused  []string             `json:"parameterTypesReused"`
+	CategoriesCreated     []string             `json:"categoriesCreated"`
+	Issues                []LibraryImportIssue `json:"issues"`
+}
+
+// PlanImportResult is the plan a plan document import created, with what was reused by name
+type PlanImportResult struct {
+	Plan                  Plan     `json:"plan"`
+	ExercisesCreated      []string `json:"exercisesCreated"`
+	ExercisesReused       []string `json:"exercisesReused"`
+	VariationsCreated     int      `json:"variationsCreated"`
+	ParameterTypesCreated []string `json:"parameterTypesCreated"`
+	ParameterTypesReused  []string `json:"parameterTypesReused"`
+}
+
+// ScheduledSession is a group session placed on a date of an athlete's schedule
+type ScheduledSession struct {
+	ID               int64  `json:"id"`
+	PlanID           int64  `json:"planId"`
+	PlanAssignmentID int64  `json:"planAssignmentId"`
+	UserID           int64  `json:"userId"`
+	PlanIntervalID   int64  `json:"planIntervalId"`
+	GroupID          int64  `json:"groupId"`
+	PlanName         string `json:"planName"`
+	GroupName        string `json:"groupName"`
+	IntervalName     string `json:"intervalName,omitempty"`
+	Date             string `json:"date"`
+	OriginalDate     string `json:"originalDate"`
+	Rescheduled      bool   `json:"rescheduled"`
+	Status           string `json:"status"`
+}
+
+// Benchmark is an athlete's measured best for a parameter on a date, e.g. a 1RM or a max
+// hang. Without an exercise variation it applies to every variation, like bodyweight.
+type Benchmark struct {
+	ID                  int64   `json:"id"`
+	UserID              int64   `json:"userId"`
+	ExerciseVariationId *int64  `json:"exerciseVariationId"`
+	ParameterTypeId     int64   `json:"parameterTypeId"`
+	Value               float64 `json:"value"`
+	RecordedOn          string  `json:"recordedOn"`
+	CreatedAt           string  `json:"createdAt"`
+	UpdatedAt           string  `json:"updatedAt"`
+}
+
+// PersonalRecord is a best detected in a completed session. Beaten records are kept as
+// history; Load is the load a most reps record was done at.
+type PersonalRecord struct {
+	ID                  int64    `json:"id"`
+	UserID              int64    `json:"userId"`
+	ExerciseVariationId int64    `json:"exerciseVariationId"`
+	ParameterTypeId     int64    `json:"parameterTypeId"`
+	Kind                string   `json:"kind"`
+	Value               float64  `json:"value"`
+	PreviousValue       *float64 `json:"previousValue"`
+	Load                *float64 `json:"load,omitempty"`
+	Reps                *int32   `json:"reps,omitempty"`
+	SessionSetId        *int64   `json:"sessionSetId,omitempty"`
+	AchievedOn          string   `json:"achievedOn"`
+	CreatedAt           string   `json:"createdAt"`
+}
+
+// SessionCompletion is a completed session with the personal records it set. Load is
+// the session RPE times the minutes trained, nil when either is unknown.
+type SessionCompletion struct {
+	Session ScheduledSession `json:"session"`
+	Sets    int              `json:"sets"`
+	Load    *float64         `json:"load"`
+	Records []PersonalRecord `json:"records"`
+}
+
+// TrainingLoad is an athlete's daily training load between two dates. Unrated counts the
+// completed sessions without an RPE or duration, which add no load.
+type TrainingLoad struct {
+	UserID     int64                  `json:"userId"`
+	From       string                 `json:"from"`
+	To         string                 `json:"to"`
+	Thresholds TrainingLoadThresholds `json:"thresholds"`
+	Unrated    int                    `json:"unrated"`
+	Days       []TrainingLoadDay      `json:"days"`
+}
+
+// TrainingLoadDay holds a day's load and the rolling metrics ending on it. Acute is the
+// load of the last 7 days and Chronic the average weekly load of the last 28.
+type TrainingLoadDay struct {
+	Date     string   `json:"date"`
+	Load     float64  `json:"load"`
+	Acute    float64  `json:"acute"`
+	Chronic  float64  `json:"chronic"`
+	ACWR     *float64 `json:"acwr"`
+	Monotony *float64 `json:"monotony"`
+	Strain   *float64 `json:"strain"`
+	// Readiness is the score of the day's check-in
+	Readiness *float64 `json:"readiness"`
+	Flags     []string `json:"flags"`
+}
+
+// TrainingLoadThresholds are the limits beyond which days are flagged. Custom is false
+// while the athlete uses the defaults.
+type TrainingLoadThresholds struct {
+	ACWRHigh float64 `json:"acwrHigh"`
+	ACWRLow  float64 `json:"acwrLow"`
+	Monotony float64 `json:"monotony"`
+	Strain   float64 `json:"strain"`
+	Custom   bool    `json:"custom"`
+}
+
+// ReadinessCheckin is an athlete's daily check-in with its readiness score from 0 to 100.
+// Soreness maps body areas to scores from 0 to 10.
+type ReadinessCheckin struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"userId"`
+	CheckedOn  string           `json:"checkedOn"`
+	SleepHours *float64         `json:"sleepHours"`
+	Soreness   map[string]int32 `json:"soreness"`
+	FingerPain *int32           `json:"fingerPain"`
+	Mood       *int32           `json:"mood"`
+	RestingHR  *int32           `json:"restingHr"`
+	Score      *float64         `json:"score"`
+	CreatedAt  string           `json:"createdAt"`
+	UpdatedAt  string           `json:"updatedAt"`
+}
+
+// ReadinessTrendDay is a day's readiness with the averages of the week ending on it
+type ReadinessTrendDay struct {
+	Date              string   `json:"date"`
+	Score             *float64 `json:"score"`
+	ScoreAverage      *float64 `json:"scoreAverage"`
+	SleepAverage      *float64 `json:"sleepAverage"`
+	FingerPainAverage *float64 `json:"fingerPainAverage"`
+	RestingHRAverage  *float64 `json:"restingHrAverage"`
+}
+
+// ReadinessRecommendation is how a session should change given the check-in of its
+// day. Without a check-in there are no actions.
+type ReadinessRecommendation struct {
+	SessionID int64             `json:"sessionId"`
+	Date      string            `json:"date"`
+	Checkin   *ReadinessCheckin `json:"checkin"`
+	Actions   []ReadinessAction `json:"actions"`
+}
+
+// ReadinessAction is a recommended change: rest, reduce_intensity by IntensityFactor or
+// swap_group to GroupId
+type ReadinessAction struct {
+	Kind            string   `json:"kind"`
+	Reason          string   `json:"reason"`
+	IntensityFactor *float64 `json:"intensityFactor,omitempty"`
+	GroupId         *int64   `json:"groupId,omitempty"`
+	GroupName       string   `json:"groupName,omitempty"`
+}
+
+// LogbookEntry is a route or boulder a user sent. The grade is given in its own system;
+// ConvertedGrade is the grade in the system a listing asked for.
+type LogbookEntry struct {
+	ID                 int64  `json:"id"`
+	UserID             int64  `json:"userId"`
+	ScheduledSessionId *int64 `json:"scheduledSessionId"`
+	Name               string `json:"name"`
+	Discipline         string `json:"discipline"`
+	GradeSystem        string `json:"gradeSystem"`
+	Grade              string `json:"grade"`
+	ConvertedGrade     string `json:"convertedGrade,omitempty"`
+	Style              string `json:"style"`
+	Attempts           int32  `json:"attempts"`
+	Outdoor            bool   `json:"outdoor"`
+	ClimbedOn          string `json:"climbedOn"`
+	CreatedAt          string `json:"createdAt"`
+	UpdatedAt          string `json:"updatedAt"`
+}
+
+// GradePyramid counts a user's sends per grade in one system, hardest first
+type GradePyramid struct {
+	Discipline string              `json:"discipline"`
+	System     string              `json:"system"`
+	MaxGrade   string              `json:"maxGrade,omitempty"`
+	Total      int                 `json:"total"`
+	Levels     []GradePyramidLevel `json:"levels"`
+}
+
+type GradePyramidLevel struct {
+	Grade string `json:"grade"`
+	Count int    `json:"count"`
+}
+
+// ClimbingGrade is a grade with its equivalent in the other system of its discipline
+type ClimbingGrade struct {
+	System      string            `json:"system"`
+	Discipline  string            `json:"discipline"`
+	Grade       string            `json:"grade"`
+	Conversions map[string]string `json:"conversions"`
+}
+
+// CalendarFeed is a subscribable iCalendar feed of a user's sessions. Path includes the
+// token, which is the only credential needed to read the feed.
+type CalendarFeed struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"userId"`
+	Token     string `json:"token"`
+	Path      string `json:"path"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// PlanAssignment is a plan being followed by a user from a start date. TrainingDays are
+// weekdays numbered from Sunday = 0; an empty list allows every day.
+type PlanAssignment struct {
+	ID           int64   `json:"id"`
+	PlanID       int64   `json:"planId"`
+	UserID       int64   `json:"userId"`
+	StartDate    string  `json:"startDate"`
+	TrainingDays []int32 `json:"trainingDays"`
+	MinRestDays  int32   `json:"minRestDays"`
+	CreatedAt    string  `json:"createdAt"`
+	UpdatedAt    string  `json:"updatedAt"`
+}
+
+// Team is a group of athletes and their coaches. Role is the requesting user's role.
+type Team struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Role      string       `json:"role,omitempty"`
+	Members   []TeamMember `json:"members,omitempty"`
+	CreatedAt string       `json:"createdAt"`
+	UpdatedAt string       `json:"updatedAt"`
+}
+
+// TeamMember is a user on a team's roster with their role: owner, coach or athlete
+type TeamMember struct {
+	UserID    int64  `json:"userId"`
+	Role      string `json:"role"`
+	Email     string `json:"email"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	JoinedAt  string `json:"joinedAt"`
+}
+
+// TeamInvitation invites a user to join a team in a role
+type TeamInvitation struct {
+	ID        int64  `json:"id"`
+	TeamID    int64  `json:"teamId"`
+	UserID    int64  `json:"userId"`
+	Role      string `json:"role"`
+	InvitedBy *int64 `json:"invitedBy,omitempty"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// TeamPlanAssignment is the linked copy of a plan a coach assigned to an athlete, with
+// its schedule when a start date was given
+type TeamPlanAssignment struct {
+	AthleteID  int64           `json:"athleteId"`
+	Plan       Plan            `json:"plan"`
+	Assignment *PlanAssignment `json:"assignment,omitempty"`
+}
+
+// PlanCollaborator is a user a plan is shared with. ShareLinkID is the link they joined
+// through, their access ends with it.
+type PlanCollaborator struct {
+	PlanID      int64  `json:"planId"`
+	UserID      int64  `json:"userId"`
+	Permission  string `json:"permission"`
+	Email       string `json:"email"`
+	FirstName   string `json:"firstName"`
+	LastName    string `json:"lastName"`
+	ShareLinkID *int64 `json:"shareLinkId,omitempty"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// PlanShareLink shares a plan with whoever accepts it until it expires or is revoked
+type PlanShareLink struct {
+	ID         int64   `json:"id"`
+	PlanID     int64   `json:"planId"`
+	Token      string  `json:"token"`
+	Permission string  `json:"permission"`
+	CreatedBy  *int64  `json:"createdBy,omitempty"`
+	ExpiresAt  *string `json:"expiresAt,omitempty"`
+	RevokedAt  *string `json:"revokedAt,omitempty"`
+	Active     bool    `json:"active"`
+	CreatedAt  string  `json:"createdAt"`
+}
+
+// Template is a public template in the gallery. SourcePlanID of a fork links back to it.
+type Template struct {
+	ID            int64              `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	UserID        int64              `json:"userId"`
+	AuthorName    string             `json:"authorName"`
+	ForkCount     int32              `json:"forkCount"`
+	RatingCount   int32              `json:"ratingCount"`
+	AverageRating *float64           `json:"averageRating,omitempty"` // left out until the template is rated
+	Intervals     []TemplateInterval `json:"intervals"`
+	CreatedAt     string             `json:"createdAt"`
+	UpdatedAt     string             `json:"updatedAt"`
+}
+
+// TemplateInterval previews the structure of a template's interval
+type TemplateInterval struct {
+	ID                int64    `json:"id"`
+	Name              string   `json:"name"`
+	Duration          string   `json:"duration"`
+	Order             int32    `json:"order"`
+	IsDeload          bool     `json:"isDeload"`
+	Groups            []string `json:"groups"`
+	PrescriptionCount int32    `json:"prescriptionCount"`
+}
+
+// PlanRating is a user's one to five star rating of a template
+type PlanRating struct {
+	PlanID    int64  `json:"planId"`
+	UserID    int64  `json:"userId"`
+	Stars     int32  `json:"stars"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// PlanVersion is a snapshot of a plan's tree recorded when it changed. RestoredFrom is the
+// number of the version a restore brought back. The document is left out of lists.
+type PlanVersion struct {
+	ID            int64           `json:"id"`
+	PlanID        int64           `json:"planId"`
+	Number        int32           `json:"number"`
+	CreatedBy     *int64          `json:"createdBy,omitempty"`
+	CreatedByName string          `json:"createdByName,omitempty"`
+	RestoredFrom  *int32          `json:"restoredFrom,omitempty"`
+	CreatedAt     string          `json:"createdAt"`
+	Document      json.RawMessage `json:"document,omitempty"`
+}
+
+// CommentCounts are the comments on a record and its unresolved threads, which list
+// endpoints include for badges in the plan editor
+type CommentCounts struct {
+	CommentCount          int32 `json:"commentCount,omitempty"`
+	UnresolvedThreadCount int32 `json:"unresolvedThreadCount,omitempty"`
+}
+
+// PlanComment is a comment on a plan, or on one of its intervals, groups or prescriptions.
+// A comment that starts a thread carries its replies and whether it is resolved.
+type PlanComment struct {
+	ID             int64            `json:"id"`
+	PlanID         int64            `json:"planId"`
+	IntervalID     *int64           `json:"intervalId,omitempty"`
+	GroupID        *int64           `json:"groupId,omitempty"`
+	PrescriptionID *int64           `json:"prescriptionId,omitempty"`
+	ParentID       *int64           `json:"parentId,omitempty"`
+	UserID         int64            `json:"userId"`
+	AuthorName     string           `json:"authorName"`
+	Body           string           `json:"body"`
+	Mentions       []CommentMention `json:"mentions"`
+	Resolved       bool             `json:"resolved"`
+	ResolvedAt     *string          `json:"resolvedAt,omitempty"`
+	ResolvedBy     *int64           `json:"resolvedBy,omitempty"`
+	EditedAt       *string          `json:"editedAt,omitempty"`
+	CreatedAt      string           `json:"createdAt"`
+	UpdatedAt      string           `json:"updatedAt"`
+	Replies        []PlanComment    `json:"replies,omitempty"`
+}
+
+// CommentMention is a user a comment mentions
+type CommentMention struct {
+	UserID int64  `json:"userId"`
+	Name   string `json:"name"`
+}
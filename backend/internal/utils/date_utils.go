@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DateLayout is how calendar dates are written in the API, e.g. "2025-03-17"
+const DateLayout = "2006-01-02"
+
+// StringToDate parses a calendar date in the API layout
+func StringToDate(value string) (pgtype.Date, error) {
+	parsed, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return pgtype.Date{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+	}
+	return pgtype.Date{Time: parsed, Valid: true}, nil
+}
+
+// DateToString formats a calendar date in the API layout
+func DateToString(date pgtype.Date) string {
+	if !date.Valid {
+		return ""
+	}
+	return date.Time.Format(DateLayout)
+}
+
+// TimeToDate keeps only the calendar date of a time
+func TimeToDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+}
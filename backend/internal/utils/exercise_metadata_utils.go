@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	LateralityBilateral  = "bilateral"
+	LateralityUnilateral = "unilateral"
+)
+
+// MuscleGroups is the vocabulary accepted for primary and secondary muscle groups
+var MuscleGroups = []string{
+	"fingers",
+	"forearms",
+	"biceps",
+	"triceps",
+	"shoulders",
+	"chest",
+	"upper_back",
+	"lats",
+	"core",
+	"hips",
+	"glutes",
+	"quadriceps",
+	"hamstrings",
+	"calves",
+}
+
+// BodyAreas is the vocabulary accepted for soreness in readiness check-ins: the muscle
+// groups and the joints climbing strains
+var BodyAreas = []string{
+	"fingers",
+	"forearms",
+	"wrists",
+	"elbows",
+	"biceps",
+	"triceps",
+	"shoulders",
+	"neck",
+	"chest",
+	"upper_back",
+	"lats",
+	"lower_back",
+	"core",
+	"hips",
+	"glutes",
+	"quadriceps",
+	"hamstrings",
+	"knees",
+	"calves",
+}
+
+// Equipment is the vocabulary accepted for exercise requirements and athlete inventories
+var Equipment = []string{
+	"hangboard",
+	"campus_board",
+	"system_board",
+	"spray_wall",
+	"barbell",
+	"dumbbell",
+	"kettlebell",
+	"rings",
+	"pull_up_bar",
+	"resistance_band",
+	"weight_belt",
+}
+
+// NormalizeMetadataValue lowercases a value and joins words with underscores, so
+// "Campus Board" and "campus-board" both become "campus_board"
+func NormalizeMetadataValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+func normalizeAgainst(values []string, vocabulary []string, kind string) ([]string, error) {
+	result := make([]string, 0, len(values))
+	seen := make(map[string]bool)
+	for _, value := range values {
+		normalized := NormalizeMetadataValue(value)
+		if !contains(vocabulary, normalized) {
+			return nil, fmt.Errorf("unknown %s: %q", kind, value)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+// NormalizeMuscleGroups validates and de-duplicates a list of muscle groups
+func NormalizeMuscleGroups(values []string) ([]string, error) {
+	return normalizeAgainst(values, MuscleGroups, "muscle group")
+}
+
+// NormalizeEquipment validates and de-duplicates a list of equipment
+func NormalizeEquipment(values []string) ([]string, error) {
+	return normalizeAgainst(values, Equipment, "equipment")
+}
+
+// NormalizeBodyArea validates a body area
+func NormalizeBodyArea(value string) (string, error) {
+	normalized, err := normalizeAgainst([]string{value}, BodyAreas, "body area")
+	if err != nil {
+		return "", err
+	}
+	return normalized[0], nil
+}
+
+// NormalizeLaterality validates a laterality value, defaulting to bilateral when empty
+func NormalizeLaterality(value string) (string, error) {
+	normalized := NormalizeMetadataValue(value)
+	switch normalized {
+	case "":
+		return LateralityBilateral, nil
+	case LateralityBilateral, LateralityUnilateral:
+		return normalized, nil
+	}
+	return "", fmt.Errorf("unknown laterality: %q", value)
+}
+
+func contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -189,3 +190,21 @@ func IntervalToString(interval pgtype.Interval) (string, error) {
 
 	return result.String(), nil
 }
+
+// IntervalToDays converts a pgtype.Interval to whole calendar days, counting months as 30 days
+func IntervalToDays(interval pgtype.Interval) int {
+	if !interval.Valid {
+		return 0
+	}
+	const microsecondsPerDay = 24 * 60 * 60 * 1000000
+	return int(interval.Months)*30 + int(interval.Days) + int(interval.Microseconds/microsecondsPerDay)
+}
+
+// IntervalToDuration converts a pgtype.Interval to a time.Duration, counting months as 30 days
+func IntervalToDuration(interval pgtype.Interval) time.Duration {
+	if !interval.Valid {
+		return 0
+	}
+	days := int64(interval.Months)*30 + int64(interval.Days)
+	return time.Duration(days)*24*time.Hour + time.Duration(interval.Microseconds)*time.Microsecond
+}
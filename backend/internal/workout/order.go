@@ -0,0 +1,72 @@
+// Package workout describes how the prescriptions of a group are performed in a session:
+// the order they come in and which of them are linked into supersets or circuits.
+package workout
+
+import "fmt"
+
+// Entry is a prescription at its place in the group's order. BlockID is 0 when the
+// prescription is not part of a superset or circuit.
+type Entry struct {
+	ID      int64
+	BlockID int64
+}
+
+// CheckBlocks makes sure the prescriptions of every block follow each other, so a
+// superset or circuit is never interrupted by another exercise
+func CheckBlocks(entries []Entry) error {
+	closed := make(map[int64]bool)
+	var current int64
+	for _, entry := range entries {
+		if entry.BlockID != current {
+			if current != 0 {
+				closed[current] = true
+			}
+			current = entry.BlockID
+		}
+		if current != 0 && closed[current] {
+			return fmt.Errorf("prescription %d is separated from the rest of its superset or circuit", entry.ID)
+		}
+	}
+	return nil
+}
+
+// Link moves the members next to each other, in the order given, to where the first of
+// them currently is. Prescriptions that are not members keep their relative order.
+func Link(order []int64, members []int64) []int64 {
+	linked := make(map[int64]bool, len(members))
+	for _, id := range members {
+		linked[id] = true
+	}
+
+	result := make([]int64, 0, len(order))
+	inserted := false
+	for _, id := range order {
+		if !linked[id] {
+			result = append(result, id)
+			continue
+		}
+		if !inserted {
+			result = append(result, members...)
+			inserted = true
+		}
+	}
+	return result
+}
+
+// SameMembers reports whether ids lists every id of order exactly once
+func SameMembers(order []int64, ids []int64) bool {
+	if len(order) != len(ids) {
+		return false
+	}
+	remaining := make(map[int64]bool, len(order))
+	for _, id := range order {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
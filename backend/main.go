@@ -5,14 +5,19 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 )
 
 func main() {
 	serverCmd := flag.NewFlagSet("server", flag.ExitOnError)
 	migrateCmd := flag.NewFlagSet("migrate", flag.ExitOnError)
+	planCmd := flag.NewFlagSet("plan", flag.ExitOnError)
+	tokenCmd := flag.NewFlagSet("token", flag.ExitOnError)
+	tokenUser := tokenCmd.Int64("user", 0, "ID of the user the token acts on behalf of")
+	tokenExpiry := tokenCmd.Duration("expiry", 30*24*time.Hour, "how long the token stays valid")
 
 	if len(os.Args) < 2 {
-		fmt.Println("expected 'server' or 'migrate' subcommands")
+		fmt.Println("expected 'server', 'migrate', 'plan' or 'token' subcommands")
 		os.Exit(1)
 	}
 
@@ -31,8 +36,22 @@ func main() {
 			os.Exit(1)
 		}
 		cmd.Migrate()
+	case "plan":
+		err := planCmd.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("error parsing plan command:", err)
+			os.Exit(1)
+		}
+		cmd.Plan(planCmd.Args())
+	case "token":
+		err := tokenCmd.Parse(os.Args[2:])
+		if err != nil {
+			fmt.Println("error parsing token command:", err)
+			os.Exit(1)
+		}
+		cmd.Token(*tokenUser, *tokenExpiry)
 	default:
-		fmt.Println("expected 'server' or 'migrate' subcommands")
+		fmt.Println("expected 'server', 'migrate', 'plan' or 'token' subcommands")
 		os.Exit(1)
 	}
 }
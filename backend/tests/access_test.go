@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"backend/internal/access"
+	"testing"
+)
+
+func int64Pointer(value int64) *int64 {
+	return &value
+}
+
+// team is a team with an owner 1, a coach 2 and athletes 3 and 4
+var team = []access.Membership{
+	{TeamID: 1, UserID: 1, Role: access.RoleOwner},
+	{TeamID: 1, UserID: 2, Role: access.RoleCoach},
+	{TeamID: 1, UserID: 3, Role: access.RoleAthlete},
+	{TeamID: 1, UserID: 4, Role: access.RoleAthlete},
+}
+
+// TestAccessAllowed tests who may read and change a resource
+func TestAccessAllowed(t *testing.T) {
+	testCases := []struct {
+		name        string
+		actor       int64
+		resource    access.Resource
+		level       access.Level
+		memberships []access.Membership
+		expected    bool
+	}{
+		{"own data", 3, access.Resource{OwnerID: int64Pointer(3)}, access.Write, nil, true},
+		{"library read", 3, access.Resource{Public: true}, access.Read, nil, true},
+		{"library write", 3, access.Resource{Public: true}, access.Write, nil, false},
+		{"public plan read", 3, access.Resource{OwnerID: int64Pointer(5), Public: true}, access.Read, nil, true},
+		{"public plan write", 3, access.Resource{OwnerID: int64Pointer(5), Public: true}, access.Write, nil, false},
+		{"stranger", 3, access.Resource{OwnerID: int64Pointer(5)}, access.Read, nil, false},
+		{"owner reads athlete", 1, access.Resource{OwnerID: int64Pointer(3)}, access.Read, team, true},
+		{"coach reads athlete", 2, access.Resource{OwnerID: int64Pointer(3)}, access.Read, team, true},
+		{"coach comments on athlete", 2, access.Resource{OwnerID: int64Pointer(3)}, access.Comment, team, true},
+		{"coach changes athlete", 2, access.Resource{OwnerID: int64Pointer(3)}, access.Write, team, false},
+		{"public plan comment", 3, access.Resource{OwnerID: int64Pointer(5), Public: true}, access.Comment, nil, false},
+		{"athlete reads athlete", 4, access.Resource{OwnerID: int64Pointer(3)}, access.Read, team, false},
+		{"athlete reads coach", 3, access.Resource{OwnerID: int64Pointer(2)}, access.Read, team, false},
+		{"coach reads coach", 2, access.Resource{OwnerID: int64Pointer(1)}, access.Read, team, false},
+		{"viewer reads", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionView}, access.Read, nil, true},
+		{"viewer comments", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionView}, access.Comment, nil, false},
+		{"commenter comments", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionComment}, access.Comment, nil, true},
+		{"commenter edits", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionComment}, access.Write, nil, false},
+		{"editor edits", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionEdit}, access.Write, nil, true},
+		{"editor manages", 5, access.Resource{OwnerID: int64Pointer(3), Permission: access.PermissionEdit}, access.Manage, nil, false},
+		{"owner manages", 3, access.Resource{OwnerID: int64Pointer(3)}, access.Manage, nil, true},
+		{"coach manages athlete", 2, access.Resource{OwnerID: int64Pointer(3)}, access.Manage, team, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := access.Allowed(tc.actor, tc.resource, tc.level, tc.memberships); actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+// TestAccessCoachesTeam tests that coaching is limited to the teams an athlete trains with
+func TestAccessCoachesTeam(t *testing.T) {
+	memberships := []access.Membership{
+		{TeamID: 1, UserID: 1, Role: access.RoleCoach},
+		{TeamID: 1, UserID: 2, Role: access.RoleCoach},
+		{TeamID: 2, UserID: 1, Role: access.RoleAthlete},
+		{TeamID: 2, UserID: 2, Role: access.RoleOwner},
+	}
+	if access.Coaches(1, 2, memberships) {
+		t.Error("expected a fellow coach not to be coached")
+	}
+	if !access.Coaches(2, 1, memberships) {
+		t.Error("expected the owner of the second team to coach its athlete")
+	}
+}
+
+// TestAccessCanInvite tests which roles members may invite
+func TestAccessCanInvite(t *testing.T) {
+	testCases := []struct {
+		role     string
+		invited  string
+		expected bool
+	}{
+		{access.RoleOwner, access.RoleOwner, true},
+		{access.RoleOwner, access.RoleCoach, true},
+		{access.RoleCoach, access.RoleAthlete, true},
+		{access.RoleCoach, access.RoleCoach, false},
+		{access.RoleAthlete, access.RoleAthlete, false},
+	}
+
+	for _, tc := range testCases {
+		if actual := access.CanInvite(tc.role, tc.invited); actual != tc.expected {
+			t.Errorf("%s inviting %s: expected %v, got %v", tc.role, tc.invited, tc.expected, actual)
+		}
+	}
+	if count := access.OwnerCount(team); count != 1 {
+		t.Errorf("expected 1 owner, got %d", count)
+	}
+}
+
+// TestAccessStrongestPermission tests that a collaborator's strongest permission wins
+func TestAccessStrongestPermission(t *testing.T) {
+	testCases := []struct {
+		permissions []string
+		expected    string
+	}{
+		{nil, ""},
+		{[]string{access.PermissionView}, access.PermissionView},
+		{[]string{access.PermissionEdit, access.PermissionView}, access.PermissionEdit},
+		{[]string{access.PermissionView, access.PermissionComment}, access.PermissionComment},
+		{[]string{"owner", access.PermissionView}, access.PermissionView},
+	}
+
+	for _, tc := range testCases {
+		if actual := access.Strongest(tc.permissions); actual != tc.expected {
+			t.Errorf("%v: expected %q, got %q", tc.permissions, tc.expected, actual)
+		}
+	}
+}
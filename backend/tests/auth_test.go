@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"backend/internal/auth"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTokensVerify tests that only unexpired tokens signed with the secret name a user
+func TestTokensVerify(t *testing.T) {
+	now := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	tokens := &auth.Tokens{Secret: []byte("secret"), Now: func() time.Time { return now }}
+
+	valid, err := tokens.Issue(7, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	expired, _ := tokens.Issue(7, -time.Minute)
+	other, _ := (&auth.Tokens{Secret: []byte("other"), Now: tokens.Now}).Issue(7, time.Hour)
+	zero, _ := tokens.Issue(0, time.Hour)
+
+	testCases := []struct {
+		name     string
+		token    string
+		expected int64
+		err      error
+	}{
+		{"valid", valid, 7, nil},
+		{"expired", expired, 0, auth.ErrExpiredToken},
+		{"other secret", other, 0, auth.ErrInvalidToken},
+		{"tampered signature", valid[:len(valid)-2] + "xx", 0, auth.ErrInvalidToken},
+		{"no user", zero, 0, auth.ErrInvalidToken},
+		{"unsigned", "eyJhbGciOiJub25lIn0.eyJzdWIiOiI3In0.", 0, auth.ErrInvalidToken},
+		{"malformed", "not-a-token", 0, auth.ErrInvalidToken},
+		{"empty", "", 0, auth.ErrInvalidToken},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userId, err := tokens.Verify(tc.token)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if userId != tc.expected {
+				t.Errorf("Expected user %d, got %d", tc.expected, userId)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"backend/internal/benchmarks"
+	"testing"
+	"time"
+)
+
+func day(value string) time.Time {
+	date, _ := time.Parse("2006-01-02", value)
+	return date
+}
+
+// TestBenchmarksLatest tests that percentages refer to the latest benchmark as of the
+// date, preferring the variation's own over general ones
+func TestBenchmarksLatest(t *testing.T) {
+	recorded := []benchmarks.Benchmark{
+		{ID: 1, VariationID: 4, ParameterTypeID: 1, Value: 100, Date: day("2026-01-01")},
+		{ID: 2, VariationID: 4, ParameterTypeID: 1, Value: 110, Date: day("2026-03-01")},
+		{ID: 3, VariationID: 0, ParameterTypeID: 1, Value: 70, Date: day("2026-02-01")},
+		{ID: 4, VariationID: 5, ParameterTypeID: 3, Value: 60, Date: day("2026-01-01")},
+	}
+
+	testCases := []struct {
+		name      string
+		variation int64
+		parameter int64
+		date      string
+		expected  int64
+	}{
+		{name: "Latest", variation: 4, parameter: 1, date: "2026-04-01", expected: 2},
+		{name: "AsOfDate", variation: 4, parameter: 1, date: "2026-02-15", expected: 1},
+		{name: "SameDay", variation: 4, parameter: 1, date: "2026-03-01", expected: 2},
+		{name: "General", variation: 2, parameter: 1, date: "2026-04-01", expected: 3},
+		{name: "NotYetRecorded", variation: 2, parameter: 1, date: "2026-01-15"},
+		{name: "OtherParameter", variation: 4, parameter: 2, date: "2026-04-01"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			benchmark, ok := benchmarks.Latest(recorded, tc.variation, tc.parameter, day(tc.date))
+			if ok != (tc.expected != 0) || benchmark.ID != tc.expected {
+				t.Errorf("Expected benchmark %d, got %+v (found %v)", tc.expected, benchmark, ok)
+			}
+		})
+	}
+}
+
+// TestBenchmarksResolve tests that percentages resolve to absolute values
+func TestBenchmarksResolve(t *testing.T) {
+	benchmark := benchmarks.Benchmark{Value: 142.5}
+	if got := benchmarks.Resolve(80, benchmark); got != 114 {
+		t.Errorf("Expected 80%% of 142.5 to be 114, got %v", got)
+	}
+	if got := benchmarks.Resolve(33, benchmark); got != 47.03 {
+		t.Errorf("Expected values to be rounded to 2 decimals, got %v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"backend/internal/comments"
+	"slices"
+	"testing"
+)
+
+// TestCommentMentions tests which members of a plan a comment mentions
+func TestCommentMentions(t *testing.T) {
+	members := []comments.Member{
+		{ID: 1, Email: "coach@example.com"},
+		{ID: 2, Email: "Jane.Doe@example.com"},
+		{ID: 3, Email: "jane.doe@club.org"},
+		{ID: 4, Email: "sam@example.com"},
+	}
+
+	testCases := []struct {
+		name     string
+		body     string
+		expected []int64
+	}{
+		{"no mentions", "Looks good to me", []int64{}},
+		{"handle", "@coach can we go heavier here?", []int64{1}},
+		{"end of sentence", "Thanks @sam.", []int64{4}},
+		{"ignores case", "@COACH", []int64{1}},
+		{"email address", "cc @jane.doe@club.org", []int64{3}},
+		{"shared handle", "@jane.doe what do you think?", []int64{2, 3}},
+		{"order of first mention", "@sam and @coach, then @sam again", []int64{4, 1}},
+		{"not a member", "@someone", []int64{}},
+		{"email address in text", "mail coach@example.com", []int64{}},
+		{"inside a word", "x@sam", []int64{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := comments.Mentions(tc.body, members); !slices.Equal(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
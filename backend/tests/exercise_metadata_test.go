@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"backend/db"
+	"backend/internal/analytics"
+	"backend/internal/service"
+	"backend/internal/utils"
+	"reflect"
+	"testing"
+)
+
+// TestExerciseMetadataNormalization tests validation of muscle groups, equipment and laterality
+func TestExerciseMetadataNormalization(t *testing.T) {
+	t.Run("NormalizeEquipment", func(t *testing.T) {
+		equipment, err := utils.NormalizeEquipment([]string{"Campus Board", "campus-board", "rings"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(equipment, []string{"campus_board", "rings"}) {
+			t.Errorf("Expected de-duplicated normalized equipment, got %v", equipment)
+		}
+
+		if _, err := utils.NormalizeEquipment([]string{"trampoline"}); err == nil {
+			t.Errorf("Expected unknown equipment to be rejected")
+		}
+	})
+
+	t.Run("NormalizeMuscleGroups", func(t *testing.T) {
+		groups, err := utils.NormalizeMuscleGroups([]string{"Upper Back", "LATS"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(groups, []string{"upper_back", "lats"}) {
+			t.Errorf("Expected normalized muscle groups, got %v", groups)
+		}
+
+		empty, err := utils.NormalizeMuscleGroups(nil)
+		if err != nil || len(empty) != 0 {
+			t.Errorf("Expected no muscle groups and no error, got %v, %v", empty, err)
+		}
+	})
+
+	t.Run("NormalizeLaterality", func(t *testing.T) {
+		if laterality, _ := utils.NormalizeLaterality(""); laterality != utils.LateralityBilateral {
+			t.Errorf("Expected empty laterality to default to bilateral, got %q", laterality)
+		}
+		if laterality, _ := utils.NormalizeLaterality("Unilateral"); laterality != utils.LateralityUnilateral {
+			t.Errorf("Expected unilateral, got %q", laterality)
+		}
+		if _, err := utils.NormalizeLaterality("both"); err == nil {
+			t.Errorf("Expected unknown laterality to be rejected")
+		}
+	})
+}
+
+// TestMuscleGroupVolume tests attributing prescription volume to muscle groups
+func TestMuscleGroupVolume(t *testing.T) {
+	volumes := analytics.MuscleGroupVolume([]analytics.PrescriptionVolume{
+		{Sets: 3, Reps: 5, Frequency: 2, PrimaryMuscleGroups: []string{"lats"}, SecondaryMuscleGroups: []string{"biceps"}},
+		{Sets: 4, Reps: 0, Frequency: 0, PrimaryMuscleGroups: []string{"biceps"}},
+	})
+
+	if len(volumes) != 2 {
+		t.Fatalf("Expected 2 muscle groups, got %d", len(volumes))
+	}
+
+	// biceps: 4 primary sets (frequency defaults to 1) + 6 secondary sets at half weight = 7
+	// lats: 6 primary sets
+	biceps, lats := volumes[0], volumes[1]
+	if biceps.MuscleGroup != "biceps" || biceps.PrimarySets != 4 || biceps.SecondarySets != 6 || biceps.WeightedSets != 7 {
+		t.Errorf("Unexpected biceps volume: %+v", biceps)
+	}
+	if lats.MuscleGroup != "lats" || lats.PrimarySets != 6 || lats.WeightedSets != 6 || lats.TotalReps != 30 {
+		t.Errorf("Unexpected lats volume: %+v", lats)
+	}
+}
+
+// TestMuscleGroupVolumeSetScheme tests that a set scheme's own reps are counted set by set
+func TestMuscleGroupVolumeSetScheme(t *testing.T) {
+	volumes := analytics.MuscleGroupVolume([]analytics.PrescriptionVolume{
+		{Sets: 3, Reps: 5, Frequency: 2, SetReps: []int32{8, 6, 4}, PrimaryMuscleGroups: []string{"quads"}},
+	})
+
+	if len(volumes) != 1 || volumes[0].PrimarySets != 6 || volumes[0].TotalReps != 36 {
+		t.Errorf("Unexpected quads volume: %+v", volumes)
+	}
+}
+
+// TestCheckPlanEquipment tests the plan-level equipment inventory warning
+func TestCheckPlanEquipment(t *testing.T) {
+	exercises := []db.Exercises_ListEquipmentByPlanIdRow{
+		{ID: 1, Name: "Max Hangs", Equipment: []string{"hangboard"}},
+		{ID: 2, Name: "Campus Ladders", Equipment: []string{"campus_board"}},
+		{ID: 3, Name: "Weighted Pull-ups", Equipment: []string{"pull_up_bar", "weight_belt"}},
+	}
+
+	check := service.CheckPlanEquipment(7, 1, exercises, []string{"hangboard", "pull_up_bar"})
+
+	if check.HasAllEquipment {
+		t.Errorf("Expected missing equipment to be reported")
+	}
+	if !reflect.DeepEqual(check.RequiredEquipment, []string{"campus_board", "hangboard", "pull_up_bar", "weight_belt"}) {
+		t.Errorf("Unexpected required equipment: %v", check.RequiredEquipment)
+	}
+	if len(check.Missing) != 2 || check.Missing[0].Equipment != "campus_board" || check.Missing[1].Equipment != "weight_belt" {
+		t.Fatalf("Unexpected missing equipment: %+v", check.Missing)
+	}
+	if check.Missing[1].Exercises[0].Name != "Weighted Pull-ups" {
+		t.Errorf("Expected missing weight belt to reference Weighted Pull-ups, got %+v", check.Missing[1].Exercises)
+	}
+
+	complete := service.CheckPlanEquipment(7, 1, exercises, []string{"hangboard", "campus_board", "pull_up_bar", "weight_belt"})
+	if !complete.HasAllEquipment || len(complete.Missing) != 0 {
+		t.Errorf("Expected no missing equipment, got %+v", complete.Missing)
+	}
+}
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"backend/internal/grades"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestGradesConvert tests conversions within each discipline, both ways
+func TestGradesConvert(t *testing.T) {
+	testCases := []struct {
+		system   string
+		grade    string
+		to       string
+		expected string
+	}{
+		{system: grades.SystemV, grade: "V5", to: grades.SystemFont, expected: "6C"},
+		{system: grades.SystemFont, grade: "6c+", to: grades.SystemV, expected: "V5"},
+		{system: grades.SystemFont, grade: "7A", to: grades.SystemV, expected: "V6"},
+		{system: grades.SystemV, grade: "v10", to: grades.SystemFont, expected: "7C+"},
+		{system: grades.SystemYDS, grade: "5.12a", to: grades.SystemFrench, expected: "7a+"},
+		{system: grades.SystemYDS, grade: "5.11", to: grades.SystemFrench, expected: "6c"},
+		{system: grades.SystemFrench, grade: "6C", to: grades.SystemYDS, expected: "5.11a"},
+		{system: grades.SystemFrench, grade: "8a", to: grades.SystemYDS, expected: "5.13b"},
+		{system: grades.SystemFrench, grade: "5c", to: grades.SystemYDS, expected: "5.9"},
+	}
+	for _, tc := range testCases {
+		grade, err := grades.Parse(tc.system, tc.grade)
+		if err != nil {
+			t.Fatalf("Unexpected error parsing %s: %v", tc.grade, err)
+		}
+		converted, err := grades.Convert(grade, tc.to)
+		if err != nil || converted.Name != tc.expected {
+			t.Errorf("Expected %s %s to be %s %s, got %+v, %v", tc.system, tc.grade, tc.to, tc.expected, converted, err)
+		}
+	}
+}
+
+// TestGradesCompare tests that grades compare across systems and that invalid grades and
+// conversions between disciplines are rejected
+func TestGradesCompare(t *testing.T) {
+	v4, _ := grades.Parse(grades.SystemV, "V4")
+	font, _ := grades.Parse(grades.SystemFont, "6B+")
+	if v4.Index != font.Index-1 {
+		t.Errorf("Expected 6B+ to sit just above V4, got %d and %d", v4.Index, font.Index)
+	}
+
+	if _, err := grades.Parse(grades.SystemV, "V25"); err == nil || !strings.Contains(err.Error(), "is not a v grade") {
+		t.Errorf("Expected V25 to be rejected, got %v", err)
+	}
+	if _, err := grades.Parse("hueco", "V1"); err == nil {
+		t.Error("Expected an unknown system to be rejected")
+	}
+	if _, err := grades.Convert(v4, grades.SystemFrench); err == nil || !strings.Contains(err.Error(), "cannot be converted") {
+		t.Errorf("Expected boulder grades not to convert to French, got %v", err)
+	}
+
+	list, _ := grades.List(grades.SystemV)
+	if list[0].Name != "VB" || list[len(list)-1].Name != "V17" || len(list) != 19 {
+		t.Errorf("Expected VB to V17 once each, got %+v", list)
+	}
+}
+
+// TestGradesPyramid tests that a pyramid merges both systems of a discipline, hardest
+// first
+func TestGradesPyramid(t *testing.T) {
+	var sends []grades.Grade
+	for _, send := range [][2]string{{"v", "V5"}, {"font", "6C"}, {"font", "6C+"}, {"v", "V3"}, {"v", "V6"}, {"yds", "5.10a"}} {
+		grade, err := grades.Parse(send[0], send[1])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		sends = append(sends, grade)
+	}
+
+	levels, err := grades.Pyramid(sends, grades.SystemV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := []string{}
+	for _, level := range levels {
+		got = append(got, fmt.Sprintf("%sx%d", level.Grade.Name, level.Count))
+	}
+	if strings.Join(got, " ") != "V6x1 V5x3 V3x1" {
+		t.Errorf("Unexpected pyramid %v", got)
+	}
+}
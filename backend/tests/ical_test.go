@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"backend/internal/ical"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestICalWrite tests the calendar layout, escaping and line folding
+func TestICalWrite(t *testing.T) {
+	var buffer bytes.Buffer
+	err := ical.Write(&buffer, ical.Calendar{
+		Name: "Training",
+		Events: []ical.Event{{
+			UID:         "session-1@rocket",
+			Date:        date("2025-03-03"),
+			Summary:     "Upper body; heavy, short",
+			Description: strings.Repeat("ö", 60) + "\nsecond line",
+			Updated:     time.Date(2025, 3, 1, 8, 30, 0, 0, time.UTC),
+			Sequence:    2,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := buffer.String()
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"DTSTART;VALUE=DATE:20250303\r\nDTEND;VALUE=DATE:20250304\r\n",
+		"DTSTAMP:20250301T083000Z\r\n",
+		"SEQUENCE:2\r\n",
+		`SUMMARY:Upper body\; heavy\, short` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q:\n%s", expected, output)
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line is longer than 75 octets: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(output, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("ö", 60)+`\nsecond line`) {
+		t.Errorf("Folding changed the description:\n%s", output)
+	}
+}
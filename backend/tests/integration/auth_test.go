@@ -0,0 +1,48 @@
+package integration
+
+import (
+	"backend/internal/auth"
+	"net/http/httptest"
+	"time"
+)
+
+// TestAuthentication tests that requests need a valid bearer token except for public links
+func (suite *IntegrationTestSuite) TestAuthentication() {
+	send := func(path string, header string, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		recorder := httptest.NewRecorder()
+		suite.router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	recorder := send("/api/v1/plans?userId=1", "", "")
+	suite.AssertErrorResponse(recorder, 401, "Missing bearer token")
+
+	// The user a request is made for is not taken on trust
+	recorder = send("/api/v1/plans?userId=1", "X-User-Id", "1")
+	suite.AssertErrorResponse(recorder, 401, "Missing bearer token")
+
+	forged, err := auth.NewTokens("another-secret").Issue(1, time.Hour)
+	suite.Require().NoError(err)
+	recorder = send("/api/v1/plans?userId=1", "Authorization", "Bearer "+forged)
+	suite.AssertErrorResponse(recorder, 401, "Invalid or expired bearer token")
+
+	expired, err := suite.tokens.Issue(1, -time.Minute)
+	suite.Require().NoError(err)
+	recorder = send("/api/v1/plans?userId=1", "Authorization", "Bearer "+expired)
+	suite.AssertErrorResponse(recorder, 401, "Invalid or expired bearer token")
+
+	valid, err := suite.tokens.Issue(1, time.Hour)
+	suite.Require().NoError(err)
+	recorder = send("/api/v1/plans?userId=1", "Authorization", "Bearer "+valid)
+	suite.AssertStatusCode(recorder, 200)
+
+	// Media files and calendar feeds are reached through their unguessable links
+	recorder = send("/api/v1/media/missing.png", "", "")
+	suite.NotEqual(401, recorder.Code)
+	recorder = send("/api/v1/calendar/missing.ics", "", "")
+	suite.AssertErrorResponse(recorder, 404, "Calendar feed not found")
+}
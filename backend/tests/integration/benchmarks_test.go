@@ -0,0 +1,136 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestBenchmarks tests recording, listing, correcting and deleting benchmarks
+func (suite *IntegrationTestSuite) TestBenchmarks() {
+	recorder := suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{
+		"exerciseVariationId": 4,
+		"parameterTypeId":     1,
+		"value":               100,
+		"recordedOn":          "2026-01-05",
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var created types.Benchmark
+	suite.GetResponseData(recorder, &created)
+	suite.Equal(int64(4), *created.ExerciseVariationId)
+	suite.Equal("2026-01-05", created.RecordedOn)
+
+	recorder = suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{
+		"parameterTypeId": 1,
+		"value":           72.5,
+		"recordedOn":      "2026-01-01",
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var bodyweight types.Benchmark
+	suite.GetResponseData(recorder, &bodyweight)
+	suite.Nil(bodyweight.ExerciseVariationId, "Benchmarks without a variation apply to every variation")
+
+	recorder = suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{
+		"exerciseVariationId": 4,
+		"parameterTypeId":     3,
+		"value":               60,
+	})
+	suite.AssertErrorResponse(recorder, 400, "Parameter type 3 is not a parameter of exercise variation 4")
+	recorder = suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{"parameterTypeId": 9999, "value": 60})
+	suite.AssertErrorResponse(recorder, 404, "Parameter type not found")
+	recorder = suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{"parameterTypeId": 1, "value": 0})
+	suite.AssertErrorResponse(recorder, 400, "Value must be positive")
+	recorder = suite.POST("/api/v1/users/1/benchmarks", map[string]interface{}{"parameterTypeId": 1, "value": 60, "recordedOn": "January"})
+	suite.AssertErrorResponse(recorder, 400, `invalid date "January", expected YYYY-MM-DD`)
+
+	recorder = suite.GET("/api/v1/users/1/benchmarks?exerciseVariationId=4")
+	suite.AssertStatusCode(recorder, 200)
+	var listed []types.Benchmark
+	suite.GetResponseData(recorder, &listed)
+	suite.Require().Len(listed, 1)
+	suite.Equal(created.ID, listed[0].ID)
+
+	path := fmt.Sprintf("/api/v1/users/1/benchmarks/%d", created.ID)
+	recorder = suite.PUT(path, map[string]interface{}{"value": 105, "recordedOn": "2026-01-06"})
+	suite.AssertStatusCode(recorder, 200)
+	var updated types.Benchmark
+	suite.GetResponseData(recorder, &updated)
+	suite.Equal(float64(105), updated.Value)
+
+	recorder = suite.AS(2, "PUT", fmt.Sprintf("/api/v1/users/2/benchmarks/%d", created.ID), map[string]interface{}{"value": 1})
+	suite.AssertErrorResponse(recorder, 404, "Benchmark not found")
+
+	recorder = suite.DELETE(path)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.DELETE(path)
+	suite.AssertErrorResponse(recorder, 404, "Benchmark not found")
+}
+
+// TestBenchmarksPercentagePrescriptions tests that percentages resolve against the
+// athlete's latest benchmark as of the date, alongside the prescribed value
+func (suite *IntegrationTestSuite) TestBenchmarksPercentagePrescriptions() {
+	for _, benchmark := range []map[string]interface{}{
+		{"exerciseVariationId": 4, "parameterTypeId": 1, "value": 100, "recordedOn": "2026-01-01"},
+		{"exerciseVariationId": 4, "parameterTypeId": 1, "value": 120, "recordedOn": "2026-03-01"},
+	} {
+		recorder := suite.POST("/api/v1/users/1/benchmarks", benchmark)
+		suite.AssertStatusCode(recorder, 201)
+	}
+
+	recorder := suite.PUT("/api/v1/interval-exercise-prescriptions/4", map[string]interface{}{
+		"setScheme": []map[string]interface{}{
+			{"reps": 5, "parameters": []map[string]interface{}{{"parameterTypeId": 1, "value": 80, "isPercentage": true}}},
+			{"reps": 5, "parameters": []map[string]interface{}{{"parameterTypeId": 1, "value": 20}}},
+		},
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	listSet := func(query string) types.PrescriptionSetParameter {
+		recorder := suite.GET("/api/v1/interval-exercise-prescriptions?groupId=2&intervalId=1" + query)
+		suite.AssertStatusCode(recorder, 200)
+		var prescriptions []types.IntervalExercisePrescription
+		suite.GetResponseData(recorder, &prescriptions)
+		for _, prescription := range prescriptions {
+			if prescription.ID == 4 {
+				suite.Require().Len(prescription.SetScheme, 2)
+				suite.Equal(float64(20), *prescription.SetScheme[1].Parameters[0].ResolvedValue, "Absolute values resolve to themselves")
+				return prescription.SetScheme[0].Parameters[0]
+			}
+		}
+		suite.FailNow("Prescription 4 is missing")
+		return types.PrescriptionSetParameter{}
+	}
+
+	parameter := listSet("")
+	suite.True(parameter.IsPercentage)
+	suite.Equal(float64(80), parameter.Value)
+	suite.Nil(parameter.ResolvedValue, "Nothing resolves without an athlete")
+
+	parameter = listSet("&userId=1&date=2026-02-01")
+	suite.Equal(float64(80), parameter.Value)
+	suite.Equal(float64(80), *parameter.ResolvedValue)
+
+	parameter = listSet("&userId=1&date=2026-03-15")
+	suite.Equal(float64(96), *parameter.ResolvedValue, "The latest benchmark as of the date applies")
+
+	parameter = listSet("&userId=1&date=2025-12-01")
+	suite.Nil(parameter.ResolvedValue, "Percentages without a benchmark stay unresolved")
+
+	recorder = suite.GET("/api/v1/interval-exercise-prescriptions?userId=1&date=someday")
+	suite.AssertErrorResponse(recorder, 400, `invalid date "someday", expected YYYY-MM-DD`)
+	recorder = suite.GET("/api/v1/interval-exercise-prescriptions?sessionId=9999")
+	suite.AssertErrorResponse(recorder, 404, "Session not found")
+
+	// A session names its athlete, whose benchmarks other users may not read
+	recorder = suite.PUT("/api/v1/plans/1", map[string]interface{}{"name": "User1 Regular Plan", "startDate": "2026-03-02"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Require().NotEmpty(sessions)
+
+	path := fmt.Sprintf("/api/v1/interval-exercise-prescriptions?sessionId=%d", sessions[0].ID)
+	recorder = suite.GET(path)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "GET", path, nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+}
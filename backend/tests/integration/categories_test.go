@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestCategoriesListGlobal tests that seeded global categories are visible to every user
+func (suite *IntegrationTestSuite) TestCategoriesListGlobal() {
+	recorder := suite.GET("/api/v1/categories?userId=1")
+	suite.AssertStatusCode(recorder, 200)
+
+	var categories []types.Category
+	suite.GetResponseData(recorder, &categories)
+
+	categoryNames := make(map[string]bool)
+	for _, category := range categories {
+		categoryNames[category.Name] = true
+		suite.Nil(category.UserID, "Seeded categories should be global")
+	}
+	suite.True(categoryNames["Finger strength"], "Should contain Finger strength category")
+	suite.True(categoryNames["Power endurance"], "Should contain Power endurance category")
+	suite.True(categoryNames["Antagonist"], "Should contain Antagonist category")
+}
+
+// TestCategoriesListErrorCases tests error scenarios for the categories list endpoint
+func (suite *IntegrationTestSuite) TestCategoriesListErrorCases() {
+	recorder := suite.GET("/api/v1/categories")
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: userId, id, or exerciseId")
+}
+
+// TestCategoriesCreateAndUserIsolation tests the POST /api/v1/categories endpoint
+func (suite *IntegrationTestSuite) TestCategoriesCreateAndUserIsolation() {
+	createRequest := map[string]interface{}{
+		"name":   "Hangboard Repeaters",
+		"icon":   "hand",
+		"userId": 1,
+	}
+
+	recorder := suite.POST("/api/v1/categories", createRequest)
+	suite.AssertStatusCode(recorder, 201)
+
+	var category types.Category
+	suite.GetResponseData(recorder, &category)
+	suite.Equal("Hangboard Repeaters", category.Name)
+	suite.NotNil(category.UserID)
+	suite.Equal(int64(1), *category.UserID)
+
+	// User 2 should not see user 1's category
+	recorder = suite.AS(2, "GET", "/api/v1/categories?userId=2", nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	var categories []types.Category
+	suite.GetResponseData(recorder, &categories)
+	for _, c := range categories {
+		suite.NotEqual(category.ID, c.ID, "User 2 should not see user 1's categories")
+	}
+
+	// Missing fields
+	recorder = suite.POST("/api/v1/categories", map[string]interface{}{"icon": "hand", "userId": 1})
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: name")
+}
+
+// TestCategoriesGlobalAreReadOnly tests that global categories cannot be changed
+func (suite *IntegrationTestSuite) TestCategoriesGlobalAreReadOnly() {
+	recorder := suite.GET("/api/v1/categories?userId=1")
+	var categories []types.Category
+	suite.GetResponseData(recorder, &categories)
+	suite.NotEmpty(categories)
+
+	path := fmt.Sprintf("/api/v1/categories/%d", categories[0].ID)
+	recorder = suite.PUT(path, map[string]interface{}{"name": "Renamed", "icon": "x"})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+
+	recorder = suite.DELETE(path)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+
+	recorder = suite.DELETE("/api/v1/categories/999999")
+	suite.AssertErrorResponse(recorder, 404, "Category not found")
+}
+
+// TestExercisesCategoryTagging tests tagging exercises and filtering by category
+func (suite *IntegrationTestSuite) TestExercisesCategoryTagging() {
+	recorder := suite.POST("/api/v1/categories", map[string]interface{}{
+		"name":   "Test Focus",
+		"icon":   "target",
+		"userId": 1,
+	})
+	suite.AssertStatusCode(recorder, 201)
+
+	var category types.Category
+	suite.GetResponseData(recorder, &category)
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/exercises/2/categories/%d", category.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/exercises/999999/categories/%d", category.ID), nil)
+	suite.AssertErrorResponse(recorder, 404, "Exercise not found")
+	recorder = suite.POST("/api/v1/exercises/2/categories/999999", nil)
+	suite.AssertErrorResponse(recorder, 404, "Category not found")
+
+	// Filter exercises by category
+	recorder = suite.GET(fmt.Sprintf("/api/v1/exercises?userId=1&categoryId=%d", category.ID))
+	suite.AssertStatusCode(recorder, 200)
+
+	var exercises []types.Exercise
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1, "Only the tagged exercise should match")
+	suite.Equal(int64(2), exercises[0].ID)
+	suite.Len(exercises[0].Categories, 1)
+	suite.Equal("Test Focus", exercises[0].Categories[0].Name)
+
+	// Categories can be listed by exercise
+	recorder = suite.GET("/api/v1/categories?exerciseId=2")
+	var categories []types.Category
+	suite.GetResponseData(recorder, &categories)
+	suite.Len(categories, 1)
+
+	// Removing the tag removes the exercise from the filter
+	recorder = suite.DELETE(fmt.Sprintf("/api/v1/exercises/2/categories/%d", category.ID))
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/exercises?userId=1&categoryId=%d", category.ID))
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 0)
+}
@@ -0,0 +1,122 @@
+package integration
+
+import (
+	"backend/internal/library"
+	"backend/internal/types"
+	"bytes"
+	"strings"
+)
+
+// TestExerciseLibraryExportImport tests sharing a library between two accounts
+func (suite *IntegrationTestSuite) TestExerciseLibraryExportImport() {
+	recorder := suite.GET("/api/v1/exercises/export?userId=1&format=json")
+	suite.AssertStatusCode(recorder, 200)
+	suite.Contains(recorder.Header().Get("Content-Disposition"), "exercise-library.json")
+
+	// Downloads are not wrapped, so the file can be imported as-is
+	exported, err := library.ParseJSON(bytes.NewReader(recorder.Body.Bytes()))
+	suite.Require().NoError(err)
+	suite.Len(exported.Exercises, 3)
+
+	exportedNames := make(map[string]library.Exercise)
+	for _, exercise := range exported.Exercises {
+		exportedNames[exercise.Name] = exercise
+	}
+	suite.Len(exportedNames["Squats"].Variations, 2)
+
+	// Dry run reports without writing anything
+	recorder = suite.ASRaw(2, "POST", "/api/v1/exercises/import?userId=2&dryRun=true", "application/json", recorder.Body.Bytes())
+	suite.AssertStatusCode(recorder, 200)
+
+	var report types.LibraryImportReport
+	suite.GetResponseData(recorder, &report)
+	suite.True(report.DryRun)
+	suite.True(report.Valid)
+	suite.Len(report.Exercises, 3)
+	suite.Empty(report.ParameterTypesCreated, "Seeded parameter types are global and reused")
+
+	recorder = suite.AS(2, "GET", "/api/v1/exercises?userId=2", nil)
+	var exercises []types.Exercise
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1, "Dry run must not create exercises")
+
+	// The real import creates the exercises with their variations
+	var body bytes.Buffer
+	suite.Require().NoError(library.WriteJSON(&body, exported))
+	recorder = suite.ASRaw(2, "POST", "/api/v1/exercises/import?userId=2", "application/json", body.Bytes())
+	suite.AssertStatusCode(recorder, 201)
+	suite.GetResponseData(recorder, &report)
+	suite.False(report.DryRun)
+	suite.Equal(5, report.VariationsCreated)
+
+	recorder = suite.AS(2, "GET", "/api/v1/exercises?userId=2", nil)
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 4)
+
+	// Importing again conflicts with the exercises that now exist
+	recorder = suite.ASRaw(2, "POST", "/api/v1/exercises/import?userId=2&dryRun=true", "application/json", body.Bytes())
+	suite.GetResponseData(recorder, &report)
+	suite.Empty(report.Exercises)
+	suite.Len(report.Issues, 3)
+	for _, issue := range report.Issues {
+		suite.Equal(library.IssueConflict, issue.Kind)
+		suite.False(issue.Blocking)
+	}
+
+	recorder = suite.ASRaw(2, "POST", "/api/v1/exercises/import?userId=2&onConflict=fail", "application/json", body.Bytes())
+	suite.AssertErrorResponse(recorder, 422, "already exists")
+}
+
+// TestExerciseLibraryCSVImport tests importing a CSV library with new parameter types and categories
+func (suite *IntegrationTestSuite) TestExerciseLibraryCSVImport() {
+	csv := strings.Join([]string{
+		"exercise,primary_muscle_groups,equipment,categories,variation,parameter_type,data_type,default_unit,min_value,max_value,locked",
+		"Max Hangs,fingers,hangboard,Finger strength;Hangboard,Half crimp,Edge Depth,length,mm,6,40,true",
+		"Max Hangs,,,,Half crimp,Weight,,,,,false",
+		"Pull-ups,lats;biceps,pull_up_bar,,Weighted,Weight,,,,,",
+	}, "\n")
+
+	recorder := suite.POSTRaw("/api/v1/exercises/import?userId=1", "text/csv", []byte(csv))
+	suite.AssertStatusCode(recorder, 201)
+
+	var report types.LibraryImportReport
+	suite.GetResponseData(recorder, &report)
+	suite.Equal([]string{"Max Hangs", "Pull-ups"}, report.Exercises)
+	suite.Equal([]string{"Edge Depth"}, report.ParameterTypesCreated)
+	suite.Equal([]string{"Hangboard"}, report.CategoriesCreated)
+
+	// The new parameter type is only visible to the importing user
+	recorder = suite.GET("/api/v1/parameter-types?userId=1")
+	suite.Contains(recorder.Body.String(), "Edge Depth")
+	recorder = suite.AS(2, "GET", "/api/v1/parameter-types?userId=2", nil)
+	suite.NotContains(recorder.Body.String(), "Edge Depth")
+
+	// CSV export round-trips the imported exercise
+	recorder = suite.GET("/api/v1/exercises/export?userId=1&format=csv")
+	suite.AssertStatusCode(recorder, 200)
+	suite.Contains(recorder.Header().Get("Content-Type"), "text/csv")
+	exported, err := library.ParseCSV(bytes.NewReader(recorder.Body.Bytes()))
+	suite.Require().NoError(err)
+	suite.Len(exported.Exercises, 5)
+}
+
+// TestExerciseLibraryImportErrorCases tests rejected imports
+func (suite *IntegrationTestSuite) TestExerciseLibraryImportErrorCases() {
+	recorder := suite.POSTRaw("/api/v1/exercises/import", "text/csv", []byte("exercise\nA\n"))
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: userId")
+
+	recorder = suite.POSTRaw("/api/v1/exercises/import?userId=1", "text/csv", []byte("exercise,variation\nA,x\n,y\n"))
+	suite.AssertErrorResponse(recorder, 400, "line 3: missing exercise name")
+
+	recorder = suite.POSTRaw("/api/v1/exercises/import?userId=1", "text/csv", []byte("exercise,equipment\nA,trampoline\n"))
+	suite.AssertErrorResponse(recorder, 422, "line 2: unknown equipment")
+
+	// A blocked import writes nothing
+	recorder = suite.GET("/api/v1/exercises?userId=1")
+	var exercises []types.Exercise
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 3)
+
+	recorder = suite.GET("/api/v1/exercises/export?userId=1&format=xml")
+	suite.AssertErrorResponse(recorder, 400, "unsupported format")
+}
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/url"
+)
+
+func testPNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// localPath strips the host from a signed URL so it can be requested against the test router
+func localPath(signedURL string) string {
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		panic(err)
+	}
+	return parsed.RequestURI()
+}
+
+// TestExerciseMediaUpload tests uploading, listing, downloading and deleting exercise media
+func (suite *IntegrationTestSuite) TestExerciseMediaUpload() {
+	recorder := suite.UPLOAD("/api/v1/exercises/1/media", "file", "half-crimp.png", testPNG(800, 400))
+	suite.AssertStatusCode(recorder, 201)
+
+	var uploaded types.ExerciseMedia
+	suite.GetResponseData(recorder, &uploaded)
+	suite.Equal(int64(1), uploaded.ExerciseID)
+	suite.Equal("half-crimp.png", uploaded.FileName)
+	suite.Equal("image/png", uploaded.ContentType)
+	suite.NotEmpty(uploaded.URL)
+	suite.Require().NotNil(uploaded.ThumbnailURL, "Images should get a thumbnail")
+
+	// List returns the upload
+	recorder = suite.GET("/api/v1/exercises/1/media")
+	suite.AssertStatusCode(recorder, 200)
+
+	var media []types.ExerciseMedia
+	suite.GetResponseData(recorder, &media)
+	suite.Len(media, 1)
+	suite.Equal(uploaded.ID, media[0].ID)
+
+	// The signed URL serves the original file untouched
+	recorder = suite.GET(localPath(uploaded.URL))
+	suite.AssertStatusCode(recorder, 200)
+	suite.Equal("image/png", recorder.Header().Get("Content-Type"))
+
+	// The thumbnail is a downscaled JPEG
+	recorder = suite.GET(localPath(*uploaded.ThumbnailURL))
+	suite.AssertStatusCode(recorder, 200)
+	suite.Equal("image/jpeg", recorder.Header().Get("Content-Type"))
+	thumbnail, _, err := image.DecodeConfig(recorder.Body)
+	suite.Require().NoError(err)
+	suite.Equal(320, thumbnail.Width)
+	suite.Equal(160, thumbnail.Height)
+
+	// Tampered signatures are rejected
+	recorder = suite.GET(localPath(uploaded.URL) + "0")
+	suite.AssertErrorResponse(recorder, 403, "Invalid or expired signature")
+
+	// Delete removes the record and the stored file
+	recorder = suite.DELETE(fmt.Sprintf("/api/v1/exercises/1/media/%d", uploaded.ID))
+	suite.AssertStatusCode(recorder, 204)
+
+	recorder = suite.GET("/api/v1/exercises/1/media")
+	suite.GetResponseData(recorder, &media)
+	suite.Len(media, 0)
+
+	recorder = suite.GET(localPath(uploaded.URL))
+	suite.AssertErrorResponse(recorder, 404)
+}
+
+// TestExerciseMediaUploadErrorCases tests content type validation and missing resources
+func (suite *IntegrationTestSuite) TestExerciseMediaUploadErrorCases() {
+	recorder := suite.UPLOAD("/api/v1/exercises/1/media", "file", "notes.txt", []byte("just some text"))
+	suite.AssertErrorResponse(recorder, 415, "unsupported content type")
+
+	// The declared file name does not matter, only the content
+	recorder = suite.UPLOAD("/api/v1/exercises/1/media", "file", "fake.png", []byte("<html></html>"))
+	suite.AssertErrorResponse(recorder, 415, "unsupported content type")
+
+	recorder = suite.UPLOAD("/api/v1/exercises/1/media", "wrong", "grip.png", testPNG(10, 10))
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: file")
+
+	recorder = suite.UPLOAD("/api/v1/exercises/999/media", "file", "grip.png", testPNG(10, 10))
+	suite.AssertErrorResponse(recorder, 404, "Exercise not found")
+
+	recorder = suite.DELETE("/api/v1/exercises/1/media/999")
+	suite.AssertErrorResponse(recorder, 404, "Media not found")
+}
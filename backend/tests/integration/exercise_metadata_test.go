@@ -0,0 +1,175 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+func (suite *IntegrationTestSuite) tagPushUpsWithMetadata() {
+	recorder := suite.PUT("/api/v1/exercises/1", map[string]interface{}{
+		"name":                  "Push-ups",
+		"description":           "Basic push-up exercise for upper body strength",
+		"primaryMuscleGroups":   []string{"chest"},
+		"secondaryMuscleGroups": []string{"triceps", "shoulders"},
+		"equipment":             []string{"Rings"},
+	})
+	suite.AssertStatusCode(recorder, 200)
+}
+
+// TestExercisesMetadata tests creating and updating exercise metadata
+func (suite *IntegrationTestSuite) TestExercisesMetadata() {
+	recorder := suite.POST("/api/v1/exercises", map[string]interface{}{
+		"name":                "One Arm Hang",
+		"description":         "Single arm dead hang",
+		"userId":              1,
+		"primaryMuscleGroups": []string{"fingers", "forearms"},
+		"equipment":           []string{"hangboard"},
+		"laterality":          "unilateral",
+	})
+	suite.AssertStatusCode(recorder, 201)
+
+	var exercise types.Exercise
+	suite.GetResponseData(recorder, &exercise)
+	suite.Equal([]string{"fingers", "forearms"}, exercise.PrimaryMuscleGroups)
+	suite.Equal([]string{}, exercise.SecondaryMuscleGroups)
+	suite.Equal([]string{"hangboard"}, exercise.Equipment)
+	suite.Equal("unilateral", exercise.Laterality)
+
+	// Omitted metadata keeps its current value on update
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/exercises/%d", exercise.ID), map[string]interface{}{
+		"name":        "One Arm Hang",
+		"description": "Updated description",
+	})
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &exercise)
+	suite.Equal([]string{"hangboard"}, exercise.Equipment)
+	suite.Equal("unilateral", exercise.Laterality)
+
+	// Existing exercises default to bilateral with no metadata
+	recorder = suite.GET("/api/v1/exercises?id=2")
+	var exercises []types.Exercise
+	suite.GetResponseData(recorder, &exercises)
+	suite.Equal("bilateral", exercises[0].Laterality)
+	suite.Empty(exercises[0].PrimaryMuscleGroups)
+}
+
+// TestExercisesMetadataValidation tests that unknown metadata values are rejected
+func (suite *IntegrationTestSuite) TestExercisesMetadataValidation() {
+	recorder := suite.POST("/api/v1/exercises", map[string]interface{}{
+		"name":                "Bad Exercise",
+		"userId":              1,
+		"primaryMuscleGroups": []string{"wings"},
+	})
+	suite.AssertErrorResponse(recorder, 400, `unknown muscle group: "wings"`)
+
+	recorder = suite.POST("/api/v1/exercises", map[string]interface{}{
+		"name":       "Bad Exercise",
+		"userId":     1,
+		"laterality": "sideways",
+	})
+	suite.AssertErrorResponse(recorder, 400, `unknown laterality: "sideways"`)
+}
+
+// TestExercisesMetadataFiltering tests the muscleGroup, equipment and laterality filters
+func (suite *IntegrationTestSuite) TestExercisesMetadataFiltering() {
+	suite.tagPushUpsWithMetadata()
+
+	var exercises []types.Exercise
+
+	recorder := suite.GET("/api/v1/exercises?userId=1&muscleGroup=chest")
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1)
+	suite.Equal("Push-ups", exercises[0].Name)
+
+	// Secondary muscle groups match as well
+	recorder = suite.GET("/api/v1/exercises?userId=1&muscleGroup=triceps")
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1)
+
+	recorder = suite.GET("/api/v1/exercises?userId=1&equipment=rings")
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1)
+
+	recorder = suite.GET("/api/v1/exercises?userId=1&equipment=barbell")
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 0)
+
+	recorder = suite.GET("/api/v1/exercises?userId=1&laterality=bilateral")
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 3)
+
+	// A metadata filter is enough on its own
+	recorder = suite.GET("/api/v1/exercises?equipment=rings")
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 1)
+
+	// Without a user filter other users' exercises stay hidden
+	recorder = suite.AS(2, "GET", "/api/v1/exercises?equipment=rings", nil)
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &exercises)
+	suite.Len(exercises, 0)
+}
+
+// TestPlanEquipmentCheck tests the plan-level equipment warning against the athlete's inventory
+func (suite *IntegrationTestSuite) TestPlanEquipmentCheck() {
+	suite.tagPushUpsWithMetadata()
+
+	recorder := suite.GET("/api/v1/plans/1/equipment-check")
+	suite.AssertStatusCode(recorder, 200)
+
+	var check types.EquipmentCheck
+	suite.GetResponseData(recorder, &check)
+	suite.Equal(int64(1), check.UserID, "Should default to the plan owner")
+	suite.Equal([]string{"rings"}, check.RequiredEquipment)
+	suite.False(check.HasAllEquipment)
+	suite.Len(check.Missing, 1)
+	suite.Equal("rings", check.Missing[0].Equipment)
+	suite.Equal("Push-ups", check.Missing[0].Exercises[0].Name)
+
+	// Declare the equipment and check again
+	recorder = suite.PUT("/api/v1/users/1/equipment", map[string]interface{}{
+		"equipment": []string{"Rings", "hangboard"},
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	var equipment []string
+	suite.GetResponseData(recorder, &equipment)
+	suite.Equal([]string{"hangboard", "rings"}, equipment)
+
+	recorder = suite.GET("/api/v1/plans/1/equipment-check")
+	suite.GetResponseData(recorder, &check)
+	suite.True(check.HasAllEquipment)
+	suite.Empty(check.Missing)
+
+	recorder = suite.GET("/api/v1/plans/999/equipment-check")
+	suite.AssertErrorResponse(recorder, 404, "Plan not found")
+
+	recorder = suite.PUT("/api/v1/users/1/equipment", map[string]interface{}{
+		"equipment": []string{"jetpack"},
+	})
+	suite.AssertErrorResponse(recorder, 400, `unknown equipment: "jetpack"`)
+}
+
+// TestAnalyticsMuscleGroupVolume tests the muscle group volume breakdown
+func (suite *IntegrationTestSuite) TestAnalyticsMuscleGroupVolume() {
+	suite.tagPushUpsWithMetadata()
+
+	// Interval 1 has two push-up prescriptions (3x12 and 3x15) in a group trained 3x per week
+	recorder := suite.GET("/api/v1/analytics/muscle-group-volume?planId=1&intervalId=1")
+	suite.AssertStatusCode(recorder, 200)
+
+	var volumes []types.MuscleGroupVolume
+	suite.GetResponseData(recorder, &volumes)
+	suite.Len(volumes, 3)
+	suite.Equal("chest", volumes[0].MuscleGroup)
+	suite.Equal(int32(18), volumes[0].PrimarySets)
+	suite.Equal(float64(18), volumes[0].WeightedSets)
+	suite.Equal(int32(243), volumes[0].TotalReps)
+	suite.Equal(int32(18), volumes[1].SecondarySets)
+	suite.Equal(float64(9), volumes[1].WeightedSets)
+
+	recorder = suite.GET("/api/v1/analytics/muscle-group-volume")
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: planId")
+}
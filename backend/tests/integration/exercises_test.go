@@ -41,7 +41,7 @@ func (suite *IntegrationTestSuite) TestExercisesList() {
 // TestExercisesListUserIsolation tests user isolation in exercises list
 func (suite *IntegrationTestSuite) TestExercisesListUserIsolation() {
 	// User isolation - userId=2 should only return user 2's exercises
-	recorder := suite.GET("/api/v1/exercises?userId=2")
+	recorder := suite.AS(2, "GET", "/api/v1/exercises?userId=2", nil)
 	suite.AssertStatusCode(recorder, 200)
 
 	var exercises []types.Exercise
@@ -146,7 +146,7 @@ func (suite *IntegrationTestSuite) TestExercisesUpdateErrorCases() {
 
 	// Test Case 1: Update non-existent exercise should return error
 	recorder := suite.PUT("/api/v1/exercises/999", updateRequest)
-	suite.AssertErrorResponse(recorder, 500) // Should ideally be 404, but service returns 500 for not found
+	suite.AssertErrorResponse(recorder, 404, "Exercise not found")
 
 	// Test Case 2: Invalid ID format returns 400
 	recorder = suite.PUT("/api/v1/exercises/invalid", updateRequest)
@@ -41,7 +41,7 @@ func (suite *IntegrationTestSuite) TestGroupsList() {
 // TestGroupsListUserIsolation tests user isolation in groups list
 func (suite *IntegrationTestSuite) TestGroupsListUserIsolation() {
 	// User isolation - userId=2 should only return user 2's groups
-	recorder := suite.GET("/api/v1/groups?userId=2")
+	recorder := suite.AS(2, "GET", "/api/v1/groups?userId=2", nil)
 	suite.AssertStatusCode(recorder, 200)
 	
 	var groups []types.Group
@@ -276,4 +276,95 @@ func (suite *IntegrationTestSuite) TestGroupsIntervalAssignmentErrorCases() {
 	// Test Case 4: Invalid interval ID for removal
 	recorder = suite.DELETE("/api/v1/groups/1/assign/invalid")
 	suite.AssertErrorResponse(recorder, 400, "Invalid plan interval ID")
-}
\ No newline at end of file
+}
+// TestGroupsIntervalAssignmentConfiguration tests assigning with a frequency, pinned days
+// and order, updating the assignment and listing assignments by interval and group
+func (suite *IntegrationTestSuite) TestGroupsIntervalAssignmentConfiguration() {
+	recorder := suite.POST("/api/v1/groups/3/assign/1", map[string]interface{}{
+		"frequency":  2,
+		"pinnedDays": []int{4, 2, 4},
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var assignment types.IntervalGroupAssignment
+	suite.GetResponseData(recorder, &assignment)
+	suite.Equal(int32(2), assignment.Frequency)
+	suite.Equal([]int32{2, 4}, assignment.PinnedDays)
+	suite.Equal(int32(1), assignment.Order, "New groups go after the groups already in the interval")
+
+	// Assigning again updates the existing assignment
+	recorder = suite.POST("/api/v1/groups/3/assign/1", map[string]interface{}{"frequency": 3})
+	suite.AssertErrorResponse(recorder, 400, "Frequency cannot exceed the number of pinned days")
+
+	recorder = suite.PUT("/api/v1/groups/3/assign/1", map[string]interface{}{"order": 0, "pinnedDays": []int{}})
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &assignment)
+	suite.Equal(int32(2), assignment.Frequency, "Omitted fields keep their value")
+	suite.Equal([]int32{}, assignment.PinnedDays)
+	suite.Equal(int32(0), assignment.Order)
+
+	recorder = suite.PUT("/api/v1/groups/3/assign/1", map[string]interface{}{"pinnedDays": []int{8}})
+	suite.AssertErrorResponse(recorder, 400, "Pinned days must be weekdays")
+
+	recorder = suite.PUT("/api/v1/groups/2/assign/2", map[string]interface{}{"frequency": 1})
+	suite.AssertErrorResponse(recorder, 404, "Group is not assigned to this interval")
+
+	recorder = suite.POST("/api/v1/groups/2/assign/2", map[string]interface{}{"frequency": 0})
+	suite.AssertErrorResponse(recorder, 400, "Frequency must be at least 1")
+
+	recorder = suite.GET("/api/v1/intervals/1/groups")
+	suite.AssertStatusCode(recorder, 200)
+	var byInterval []types.IntervalGroupAssignment
+	suite.GetResponseData(recorder, &byInterval)
+	suite.Len(byInterval, 3)
+	for _, a := range byInterval {
+		suite.NotNil(a.Group)
+	}
+
+	recorder = suite.GET("/api/v1/groups/1/assignments")
+	suite.AssertStatusCode(recorder, 200)
+	var byGroup []types.IntervalGroupAssignment
+	suite.GetResponseData(recorder, &byGroup)
+	suite.Len(byGroup, 3)
+	suite.NotNil(byGroup[0].PlanInterval)
+	suite.Equal(int64(1), byGroup[0].PlanIntervalId)
+
+	recorder = suite.GET("/api/v1/groups/invalid/assignments")
+	suite.AssertErrorResponse(recorder, 400, "Invalid group ID")
+}
+
+// TestGroupsTimeline tests compiling a repeater prescription into timer segments
+func (suite *IntegrationTestSuite) TestGroupsTimeline() {
+	recorder := suite.POST("/api/v1/interval-exercise-prescriptions", map[string]interface{}{
+		"groupId":             3,
+		"exerciseVariationId": 5,
+		"planIntervalId":      1,
+		"sets":                2,
+		"subReps":             3,
+		"subRepWorkDuration":  "7 seconds",
+		"subRepRestDuration":  "3 seconds",
+		"rest":                "1 minute",
+	})
+	suite.AssertStatusCode(recorder, 201)
+
+	recorder = suite.GET("/api/v1/groups/3/timeline?intervalId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var timeline types.WorkoutTimeline
+	suite.GetResponseData(recorder, &timeline)
+	suite.Len(timeline.Segments, 11, "Two sets of three hangs with rest between hangs and between sets")
+	suite.Equal(float64(114), timeline.TotalSeconds)
+	suite.Equal(0, timeline.UntimedSegments)
+	suite.Equal("work", timeline.Segments[0].Kind)
+	suite.Equal("Plank", timeline.Segments[0].Exercise)
+	suite.Equal("rest", timeline.Segments[5].Kind)
+	suite.Equal(float64(27), timeline.Segments[5].StartSeconds)
+	suite.Equal(float64(87), timeline.Segments[5].EndSeconds)
+
+	recorder = suite.GET("/api/v1/groups/3/timeline")
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: intervalId")
+
+	recorder = suite.GET("/api/v1/groups/3/timeline?intervalId=1&transition=soon")
+	suite.AssertErrorResponse(recorder, 400, "Invalid transition duration")
+
+	recorder = suite.GET("/api/v1/groups/9999/timeline?intervalId=1")
+	suite.AssertErrorResponse(recorder, 404, "Group not found")
+}
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestLogbook tests logging climbs, attaching them to a session and comparing grades
+// across systems
+func (suite *IntegrationTestSuite) TestLogbook() {
+	climbs := []map[string]interface{}{
+		{"name": "Warm-up", "gradeSystem": "font", "grade": "6c", "style": "flash", "climbedOn": "2025-03-01"},
+		{"name": "The Pinch", "gradeSystem": "v", "grade": "V5", "style": "redpoint", "attempts": 4, "outdoor": true, "climbedOn": "2025-03-02"},
+		{"name": "Arete", "gradeSystem": "v", "grade": "V6", "style": "onsight", "outdoor": true, "climbedOn": "2025-03-03"},
+		{"name": "Slab", "gradeSystem": "yds", "grade": "5.11a", "style": "repeat", "climbedOn": "2025-03-04"},
+	}
+	var entries []types.LogbookEntry
+	for _, climb := range climbs {
+		recorder := suite.POST("/api/v1/users/1/logbook", climb)
+		suite.AssertStatusCode(recorder, 201)
+		var entry types.LogbookEntry
+		suite.GetResponseData(recorder, &entry)
+		entries = append(entries, entry)
+	}
+	suite.Equal("6C", entries[0].Grade)
+	suite.Equal("boulder", entries[0].Discipline)
+	suite.Equal(int32(1), entries[0].Attempts)
+	suite.Equal("route", entries[3].Discipline)
+	suite.Nil(entries[0].ScheduledSessionId)
+
+	// Invalid climbs are rejected
+	recorder := suite.POST("/api/v1/users/1/logbook", map[string]interface{}{"gradeSystem": "v", "grade": "6A", "style": "flash"})
+	suite.AssertErrorResponse(recorder, 400, "Invalid grade")
+	recorder = suite.POST("/api/v1/users/1/logbook", map[string]interface{}{"gradeSystem": "v", "grade": "V2", "style": "flash", "attempts": 3})
+	suite.AssertErrorResponse(recorder, 400, "A flash takes a single attempt")
+	recorder = suite.POST("/api/v1/users/1/logbook", map[string]interface{}{"gradeSystem": "v", "grade": "V2", "style": "dogged"})
+	suite.AssertErrorResponse(recorder, 400, "Style must be")
+
+	// Boulders listed in the V-scale; the route has no V grade
+	recorder = suite.GET("/api/v1/users/1/logbook?system=v")
+	suite.AssertStatusCode(recorder, 200)
+	var listed []types.LogbookEntry
+	suite.GetResponseData(recorder, &listed)
+	suite.Require().Len(listed, 4)
+	suite.Equal("Slab", listed[0].Name)
+	suite.Empty(listed[0].ConvertedGrade)
+	suite.Equal("V5", listed[3].ConvertedGrade)
+
+	recorder = suite.GET("/api/v1/users/1/logbook?discipline=boulder&outdoor=true")
+	suite.GetResponseData(recorder, &listed)
+	suite.Len(listed, 2)
+
+	// The Font flash and the V5 redpoint share a level of the pyramid
+	recorder = suite.GET("/api/v1/users/1/logbook/pyramid?system=v")
+	suite.AssertStatusCode(recorder, 200)
+	var pyramid types.GradePyramid
+	suite.GetResponseData(recorder, &pyramid)
+	suite.Equal("boulder", pyramid.Discipline)
+	suite.Equal("V6", pyramid.MaxGrade)
+	suite.Equal(3, pyramid.Total)
+	suite.Equal([]types.GradePyramidLevel{{Grade: "V6", Count: 1}, {Grade: "V5", Count: 2}}, pyramid.Levels)
+
+	recorder = suite.GET("/api/v1/users/1/logbook/pyramid?system=french")
+	suite.GetResponseData(recorder, &pyramid)
+	suite.Equal("6c", pyramid.MaxGrade)
+
+	recorder = suite.GET("/api/v1/users/1/logbook/pyramid")
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: system")
+
+	// Climbs attach to the user's own sessions only
+	recorder = suite.PUT("/api/v1/plans/1", map[string]interface{}{"name": "User1 Regular Plan", "startDate": "2025-03-03"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Require().NotEmpty(sessions)
+
+	update := climbs[2]
+	update["scheduledSessionId"] = sessions[0].ID
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/users/1/logbook/%d", entries[2].ID), update)
+	suite.AssertStatusCode(recorder, 200)
+	var updated types.LogbookEntry
+	suite.GetResponseData(recorder, &updated)
+	suite.Require().NotNil(updated.ScheduledSessionId)
+	suite.Equal(sessions[0].ID, *updated.ScheduledSessionId)
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/users/1/logbook?sessionId=%d", sessions[0].ID))
+	suite.GetResponseData(recorder, &listed)
+	suite.Len(listed, 1)
+
+	recorder = suite.AS(2, "POST", "/api/v1/users/2/logbook", update)
+	suite.AssertErrorResponse(recorder, 400, "is not one of the user's sessions")
+
+	recorder = suite.AS(2, "DELETE", fmt.Sprintf("/api/v1/users/2/logbook/%d", entries[2].ID), nil)
+	suite.AssertErrorResponse(recorder, 404, "Logbook entry not found")
+	recorder = suite.DELETE(fmt.Sprintf("/api/v1/users/1/logbook/%d", entries[2].ID))
+	suite.AssertStatusCode(recorder, 204)
+
+	recorder = suite.GET("/api/v1/grades?system=font")
+	suite.AssertStatusCode(recorder, 200)
+	var grades []types.ClimbingGrade
+	suite.GetResponseData(recorder, &grades)
+	suite.Equal("3", grades[0].Grade)
+	suite.Equal("VB", grades[0].Conversions["v"])
+}
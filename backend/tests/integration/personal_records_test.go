@@ -0,0 +1,99 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// goblet is a logged set of goblet squats, which are weighed and counted in reps
+func goblet(weight float64, reps int32) map[string]interface{} {
+	return map[string]interface{}{
+		"exerciseVariationId": 4,
+		"reps":                reps,
+		"parameters":          []map[string]interface{}{{"parameterTypeId": 1, "value": weight}},
+	}
+}
+
+// TestSessionCompletionPersonalRecords tests that completing sessions logs their sets and
+// returns the personal records they set, and that beaten records are kept as history
+func (suite *IntegrationTestSuite) TestSessionCompletionPersonalRecords() {
+	recorder := suite.PUT("/api/v1/plans/1", map[string]interface{}{
+		"name":      "User1 Regular Plan",
+		"startDate": "2025-03-03",
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	suite.AssertStatusCode(recorder, 201)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Require().GreaterOrEqual(len(sessions), 3)
+
+	// Parameters the variation does not have are rejected and leave the session pending
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{
+		"sets": []map[string]interface{}{{
+			"exerciseVariationId": 4,
+			"parameters":          []map[string]interface{}{{"parameterTypeId": 3, "value": 30}},
+		}},
+	})
+	suite.AssertErrorResponse(recorder, 400, "not a parameter of exercise variation 4")
+
+	// The first session sets the first records
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{
+		"date": "2025-03-03",
+		"sets": []map[string]interface{}{goblet(20, 12), goblet(24, 10)},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	var completion types.SessionCompletion
+	suite.GetResponseData(recorder, &completion)
+	suite.Equal("completed", completion.Session.Status)
+	suite.Equal(2, completion.Sets)
+	kinds := map[string]int{}
+	for _, record := range completion.Records {
+		kinds[record.Kind]++
+		suite.Equal(int64(1), record.UserID)
+		suite.Equal("2025-03-03", record.AchievedOn)
+		suite.NotNil(record.SessionSetId)
+	}
+	// 20x12 is outdone by 24x10 within the session for load and estimated max
+	suite.Equal(map[string]int{"heaviest_load": 1, "most_reps": 2, "estimated_max": 1}, kinds)
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{"sets": []interface{}{}})
+	suite.AssertErrorResponse(recorder, 409, "Only scheduled sessions can be completed")
+
+	// A heavier set beats the heaviest load and keeps the old record in the history
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[1].ID), map[string]interface{}{
+		"date": "2025-03-05",
+		"sets": []map[string]interface{}{goblet(28, 4)},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &completion)
+	suite.Require().Len(completion.Records, 2)
+	suite.Equal("heaviest_load", completion.Records[0].Kind)
+	suite.Equal(28.0, completion.Records[0].Value)
+	suite.Equal(24.0, *completion.Records[0].PreviousValue)
+	suite.Equal("most_reps", completion.Records[1].Kind)
+
+	recorder = suite.GET("/api/v1/records?userId=1&kind=heaviest_load")
+	suite.AssertStatusCode(recorder, 200)
+	var current []types.PersonalRecord
+	suite.GetResponseData(recorder, &current)
+	suite.Require().Len(current, 1)
+	suite.Equal(28.0, current[0].Value)
+
+	recorder = suite.GET("/api/v1/records?userId=1&kind=heaviest_load&history=true")
+	var history []types.PersonalRecord
+	suite.GetResponseData(recorder, &history)
+	suite.Require().Len(history, 2)
+	suite.Equal(24.0, history[1].Value)
+	suite.Nil(history[1].PreviousValue)
+
+	recorder = suite.AS(2, "GET", "/api/v1/records?userId=2", nil)
+	suite.GetResponseData(recorder, &current)
+	suite.Empty(current)
+
+	recorder = suite.GET("/api/v1/records?kind=fastest")
+	suite.AssertErrorResponse(recorder, 400, "Kind must be")
+
+	recorder = suite.POST("/api/v1/sessions/999999/complete", map[string]interface{}{"sets": []interface{}{}})
+	suite.AssertErrorResponse(recorder, 404, "Session not found")
+}
@@ -0,0 +1,198 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestPlanComments tests threaded comments on a plan and its intervals and prescriptions,
+// with mentions, resolving threads and the comment counts on list endpoints
+func (suite *IntegrationTestSuite) TestPlanComments() {
+	recorder := suite.AS(2, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 2, "body": "Hello"})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed")
+
+	// Viewers read the comments but do not write them
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "view"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/comments", nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 2, "body": "Hello"})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to comment on this resource")
+
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "comment"})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 1, "body": ""})
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: body")
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 1, "body": "Hello", "intervalId": 1, "groupId": 1})
+	suite.AssertErrorResponse(recorder, 400, "at most one of intervalId, groupId and prescriptionId")
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 1, "body": "Hello", "intervalId": 4})
+	suite.AssertErrorResponse(recorder, 404, "Interval not found in this plan")
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/comments", map[string]interface{}{"userId": 1, "body": "Hello", "prescriptionId": 8})
+	suite.AssertErrorResponse(recorder, 404, "Prescription not found in this plan")
+
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/comments", map[string]interface{}{
+		"userId": 1, "body": "@test2 can you check the volume here?", "intervalId": 1,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var thread types.PlanComment
+	suite.GetResponseData(recorder, &thread)
+	suite.Equal(int64(1), thread.PlanID)
+	suite.Require().NotNil(thread.IntervalID)
+	suite.Equal(int64(1), *thread.IntervalID)
+	suite.Equal("Test User1", thread.AuthorName)
+	suite.Require().Len(thread.Mentions, 1)
+	suite.Equal(int64(2), thread.Mentions[0].UserID)
+	suite.False(thread.Resolved)
+
+	// A reply joins the thread and its target
+	recorder = suite.AS(2, "POST", "/api/v1/plans/1/comments", map[string]interface{}{
+		"userId": 2, "body": "Looks fine @test1", "parentId": thread.ID,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var reply types.PlanComment
+	suite.GetResponseData(recorder, &reply)
+	suite.Require().NotNil(reply.ParentID)
+	suite.Equal(thread.ID, *reply.ParentID)
+	suite.Require().NotNil(reply.IntervalID)
+	suite.Equal(int64(1), *reply.IntervalID)
+	suite.Require().Len(reply.Mentions, 1)
+	suite.Equal(int64(1), reply.Mentions[0].UserID)
+
+	recorder = suite.AS(2, "POST", "/api/v1/plans/1/comments", map[string]interface{}{
+		"userId": 2, "body": "Three sets instead?", "prescriptionId": 3,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var prescriptionThread types.PlanComment
+	suite.GetResponseData(recorder, &prescriptionThread)
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/comments", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var threads []types.PlanComment
+	suite.GetResponseData(recorder, &threads)
+	suite.Require().Len(threads, 2)
+	suite.Equal(thread.ID, threads[0].ID)
+	suite.Require().Len(threads[0].Replies, 1)
+	suite.Equal(reply.ID, threads[0].Replies[0].ID)
+	suite.Empty(threads[1].Replies)
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/comments?prescriptionId=3", nil)
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &threads)
+	suite.Require().Len(threads, 1)
+	suite.Equal(prescriptionThread.ID, threads[0].ID)
+
+	// Only the author edits a comment
+	path := fmt.Sprintf("/api/v1/plans/1/comments/%d", thread.ID)
+	recorder = suite.AS(2, "PUT", path, map[string]interface{}{"body": "Changed"})
+	suite.AssertErrorResponse(recorder, 403, "Only the author can edit a comment")
+	recorder = suite.AS(1, "PUT", path, map[string]interface{}{"body": "Can you check the volume here?"})
+	suite.AssertStatusCode(recorder, 200)
+	var edited types.PlanComment
+	suite.GetResponseData(recorder, &edited)
+	suite.NotNil(edited.EditedAt)
+	suite.Empty(edited.Mentions)
+
+	recorder = suite.AS(1, "PUT", fmt.Sprintf("/api/v1/plans/1/comments/%d/resolved", reply.ID), map[string]interface{}{"resolved": true})
+	suite.AssertErrorResponse(recorder, 400, "Replies can not be resolved")
+	recorder = suite.AS(2, "PUT", path+"/resolved", map[string]interface{}{"resolved": true})
+	suite.AssertStatusCode(recorder, 200)
+	var resolved types.PlanComment
+	suite.GetResponseData(recorder, &resolved)
+	suite.True(resolved.Resolved)
+	suite.Require().NotNil(resolved.ResolvedBy)
+	suite.Equal(int64(2), *resolved.ResolvedBy)
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/comments?resolved=false", nil)
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &threads)
+	suite.Require().Len(threads, 1)
+	suite.Equal(prescriptionThread.ID, threads[0].ID)
+
+	// List endpoints carry the counts for badges
+	recorder = suite.AS(1, "GET", "/api/v1/plans?id=1", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var plans []types.Plan
+	suite.GetResponseData(recorder, &plans)
+	suite.Require().Len(plans, 1)
+	suite.Equal(int32(3), plans[0].CommentCount)
+	suite.Equal(int32(1), plans[0].UnresolvedThreadCount)
+
+	recorder = suite.AS(1, "GET", "/api/v1/intervals?planId=1", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	for _, interval := range intervals {
+		if interval.ID == 1 {
+			suite.Equal(int32(2), interval.CommentCount)
+			suite.Equal(int32(0), interval.UnresolvedThreadCount)
+		} else {
+			suite.Equal(int32(0), interval.CommentCount)
+		}
+	}
+
+	recorder = suite.AS(1, "GET", "/api/v1/interval-exercise-prescriptions?intervalId=1", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var prescriptions []types.IntervalExercisePrescription
+	suite.GetResponseData(recorder, &prescriptions)
+	for _, prescription := range prescriptions {
+		if prescription.ID == 3 {
+			suite.Equal(int32(1), prescription.CommentCount)
+			suite.Equal(int32(1), prescription.UnresolvedThreadCount)
+		} else {
+			suite.Equal(int32(0), prescription.CommentCount)
+		}
+	}
+
+	// The author or the plan's owner deletes a comment, with its replies
+	recorder = suite.AS(2, "DELETE", path, nil)
+	suite.AssertErrorResponse(recorder, 403, "Only the author or the plan's owner can delete a comment")
+	recorder = suite.AS(1, "DELETE", fmt.Sprintf("/api/v1/plans/1/comments/%d", prescriptionThread.ID), nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(1, "DELETE", path, nil)
+	suite.AssertStatusCode(recorder, 204)
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/comments", nil)
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &threads)
+	suite.Empty(threads)
+	recorder = suite.AS(1, "PUT", fmt.Sprintf("/api/v1/plans/1/comments/%d", reply.ID), map[string]interface{}{"body": "Gone"})
+	suite.AssertErrorResponse(recorder, 404, "Comment not found")
+}
+
+// TestPlanCommentsByCoach tests that coaches comment on their athletes' plans but do not
+// change them, and that athletes do not comment on each other's plans
+func (suite *IntegrationTestSuite) TestPlanCommentsByCoach() {
+	coach := suite.createUser("coach@example.com")
+	athlete := suite.createUser("athlete@example.com")
+
+	recorder := suite.AS(1, "POST", "/api/v1/teams", map[string]interface{}{"name": "Crag Rats"})
+	suite.AssertStatusCode(recorder, 201)
+	var team types.Team
+	suite.GetResponseData(recorder, &team)
+	suite.joinTeam(team.ID, 1, coach, "coach")
+	suite.joinTeam(team.ID, 1, 2, "athlete")
+	suite.joinTeam(team.ID, 1, athlete, "athlete")
+
+	recorder = suite.AS(coach, "POST", "/api/v1/plans/5/comments", map[string]interface{}{
+		"userId": coach, "body": "Add a deload week?", "intervalId": 4,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var thread types.PlanComment
+	suite.GetResponseData(recorder, &thread)
+	suite.Equal(int64(5), thread.PlanID)
+	suite.Equal(coach, thread.UserID)
+
+	recorder = suite.AS(2, "GET", "/api/v1/plans/5/comments", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var threads []types.PlanComment
+	suite.GetResponseData(recorder, &threads)
+	suite.Require().Len(threads, 1)
+	suite.Equal(thread.ID, threads[0].ID)
+
+	recorder = suite.AS(coach, "PUT", "/api/v1/plans/5", map[string]interface{}{"name": "Coach's Plan"})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+
+	recorder = suite.AS(athlete, "POST", "/api/v1/plans/5/comments", map[string]interface{}{"userId": athlete, "body": "Nice"})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to comment on this resource")
+}
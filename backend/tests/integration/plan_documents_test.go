@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"backend/internal/plandoc"
+	"backend/internal/plantext"
+	"backend/internal/types"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// TestPlanExportImport tests copying a plan to another account through its document
+func (suite *IntegrationTestSuite) TestPlanExportImport() {
+	recorder := suite.GET("/api/v1/plans/1/export")
+	suite.AssertStatusCode(recorder, 200)
+	suite.Contains(recorder.Header().Get("Content-Disposition"), "plan-1.json")
+
+	exported, err := plandoc.Parse(bytes.NewReader(recorder.Body.Bytes()))
+	suite.Require().NoError(err)
+	suite.Empty(plandoc.Validate(exported))
+	suite.NotEmpty(exported.Intervals)
+
+	prescriptions := 0
+	for _, interval := range exported.Intervals {
+		for _, group := range interval.Groups {
+			prescriptions += len(group.Prescriptions)
+		}
+	}
+
+	recorder = suite.ASRaw(2, "POST", "/api/v1/plans/import?userId=2", "application/json", recorder.Body.Bytes())
+	suite.AssertStatusCode(recorder, 201)
+
+	var result types.PlanImportResult
+	suite.GetResponseData(recorder, &result)
+	suite.Equal(exported.Plan.Name, result.Plan.Name)
+	suite.Equal(int64(2), result.Plan.UserID)
+	suite.Len(result.ExercisesCreated, len(exported.Exercises))
+
+	// Exporting the copy yields the same structure
+	recorder = suite.AS(2, "GET", fmt.Sprintf("/api/v1/plans/%d/export", result.Plan.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	copied, err := plandoc.Parse(bytes.NewReader(recorder.Body.Bytes()))
+	suite.Require().NoError(err)
+	suite.Equal(len(exported.Intervals), len(copied.Intervals))
+	suite.Equal(len(exported.Groups), len(copied.Groups))
+
+	copiedPrescriptions := 0
+	for _, interval := range copied.Intervals {
+		for _, group := range interval.Groups {
+			copiedPrescriptions += len(group.Prescriptions)
+		}
+	}
+	suite.Equal(prescriptions, copiedPrescriptions)
+
+	// Importing into the original account reuses its exercises
+	recorder = suite.POSTRaw("/api/v1/plans/import?userId=1", "application/json", recorder.Body.Bytes())
+	suite.AssertStatusCode(recorder, 201)
+	suite.GetResponseData(recorder, &result)
+	suite.Empty(result.ExercisesCreated)
+	suite.Len(result.ExercisesReused, len(exported.Exercises))
+}
+
+// TestPlanImportValidation tests that broken documents are rejected with their problems
+func (suite *IntegrationTestSuite) TestPlanImportValidation() {
+	body := []byte(`{"format": "rocket-plan", "version": 1, "plan": {"name": "Broken"}, "parameterTypes": [], "exercises": [], "groups": [],
+		"intervals": [{"duration": "1 week", "order": 1, "groups": [{"group": "missing", "frequency": 1}]}]}`)
+
+	recorder := suite.POSTRaw("/api/v1/plans/import?userId=1", "application/json", body)
+	suite.AssertErrorResponse(recorder, 400, `unknown group "missing"`)
+
+	recorder = suite.POSTRaw("/api/v1/plans/import", "application/json", body)
+	suite.AssertErrorResponse(recorder, 400, "userId")
+
+	recorder = suite.GET("/api/v1/plans/999/export")
+	suite.AssertErrorResponse(recorder, 404, "Plan not found")
+}
+
+// TestPlanSchema tests that the document schema is published
+func (suite *IntegrationTestSuite) TestPlanSchema() {
+	recorder := suite.GET("/api/v1/plans/schema")
+	suite.AssertStatusCode(recorder, 200)
+	suite.Equal(plandoc.Schema, recorder.Body.Bytes())
+}
+
+// TestPlanTextExportImport tests exchanging a plan in the text format
+func (suite *IntegrationTestSuite) TestPlanTextExportImport() {
+	recorder := suite.GET("/api/v1/plans/1/export?format=text")
+	suite.AssertStatusCode(recorder, 200)
+	suite.Contains(recorder.Header().Get("Content-Disposition"), "plan-1.txt")
+
+	text := recorder.Body.String()
+	suite.True(strings.HasPrefix(text, "plan "), "Downloads are not wrapped")
+	exported, err := plantext.Parse(strings.NewReader(text))
+	suite.Require().NoError(err)
+
+	recorder = suite.ASRaw(2, "POST", "/api/v1/plans/import?userId=2", "text/plain", []byte(text))
+	suite.AssertStatusCode(recorder, 201)
+
+	var result types.PlanImportResult
+	suite.GetResponseData(recorder, &result)
+	suite.Equal(exported.Plan.Name, result.Plan.Name)
+
+	// A hand-written plan reports problems with their line
+	recorder = suite.ASRaw(2, "POST", "/api/v1/plans/import?userId=2&format=text", "text/plain", []byte("plan \"Draft\"\ninterval 1: soon\n"))
+	suite.AssertErrorResponse(recorder, 400, "line 2: invalid duration")
+}
+
+// TestPlanGenerate tests generating a periodized plan from the user's variations
+func (suite *IntegrationTestSuite) TestPlanGenerate() {
+	args := map[string]interface{}{
+		"userId":          1,
+		"goal":            "strength",
+		"model":           "linear",
+		"weeks":           6,
+		"sessionsPerWeek": 3,
+		"deloadEvery":     3,
+		"exercises": []map[string]interface{}{
+			{"exerciseVariationId": 4, "parameterTypeId": 1, "load": 20},
+			{"exerciseVariationId": 1},
+		},
+	}
+	recorder := suite.POST("/api/v1/plans/generate", args)
+	suite.AssertStatusCode(recorder, 201)
+	var result types.PlanImportResult
+	suite.GetResponseData(recorder, &result)
+	suite.Equal("6-week linear strength plan", result.Plan.Name)
+	suite.Empty(result.ExercisesCreated, "Generated plans use the user's own exercises")
+	suite.ElementsMatch([]string{"Squats", "Push-ups"}, result.ExercisesReused)
+
+	recorder = suite.AS(2, "GET", fmt.Sprintf("/api/v1/plans/%d/export", result.Plan.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	generated, err := plandoc.Parse(bytes.NewReader(recorder.Body.Bytes()))
+	suite.Require().NoError(err)
+	suite.Require().Len(generated.Intervals, 6)
+	suite.Contains(generated.Intervals[2].Name, "deload")
+	squat := generated.Intervals[1].Groups[0].Prescriptions[0]
+	suite.Equal("Goblet Squat", squat.Variation)
+	suite.Require().Len(squat.SetScheme, 5)
+	suite.Equal(20.5, squat.SetScheme[0].Parameters[0].Value)
+
+	args["exercises"] = []map[string]interface{}{{"exerciseVariationId": 6}}
+	recorder = suite.POST("/api/v1/plans/generate", args)
+	suite.AssertErrorResponse(recorder, 400, "Exercise variation 6 is not in the user's library")
+
+	args["exercises"] = []map[string]interface{}{{"exerciseVariationId": 5, "parameterTypeId": 1, "load": 10}}
+	recorder = suite.POST("/api/v1/plans/generate", args)
+	suite.AssertErrorResponse(recorder, 400, "Parameter type 1 is not a parameter of exercise variation 5")
+
+	args["exercises"] = []map[string]interface{}{{"exerciseVariationId": 1}}
+	args["model"] = "wave"
+	recorder = suite.POST("/api/v1/plans/generate", args)
+	suite.AssertErrorResponse(recorder, 400, `Invalid plan settings: unknown periodization model "wave"`)
+}
@@ -51,7 +51,7 @@ func (suite *IntegrationTestSuite) TestPlanIntervalsList() {
 // TestPlanIntervalsListUserIsolation tests user isolation through plan ownership
 func (suite *IntegrationTestSuite) TestPlanIntervalsListUserIsolation() {
 	// Test intervals for user 2's plan (plan 5)
-	recorder := suite.GET("/api/v1/intervals?planId=5")
+	recorder := suite.AS(2, "GET", "/api/v1/intervals?planId=5", nil)
 	suite.AssertStatusCode(recorder, 200)
 	
 	var intervals []types.PlanInterval
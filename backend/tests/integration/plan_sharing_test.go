@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestPlanCollaborators tests that plans shared directly show up for their collaborators
+// and that the permission decides what they may do
+func (suite *IntegrationTestSuite) TestPlanCollaborators() {
+	recorder := suite.AS(2, "GET", "/api/v1/plans/1/export", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "admin"})
+	suite.AssertErrorResponse(recorder, 400, "Permission must be view, comment or edit")
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/1", map[string]interface{}{"permission": "view"})
+	suite.AssertErrorResponse(recorder, 400, "owner of a plan can not be its collaborator")
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "view"})
+	suite.AssertStatusCode(recorder, 200)
+	var collaborators []types.PlanCollaborator
+	suite.GetResponseData(recorder, &collaborators)
+	suite.Require().Len(collaborators, 1)
+	suite.Equal(int64(2), collaborators[0].UserID)
+	suite.Equal("view", collaborators[0].Permission)
+
+	// Viewers read the plan but change nothing in it
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/export", nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "GET", "/api/v1/plans?id=1", nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 5})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+	recorder = suite.AS(2, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "edit"})
+	suite.AssertErrorResponse(recorder, 403, "Only the owner can manage this resource")
+
+	// The shared plan is listed with the user's own
+	recorder = suite.AS(2, "GET", "/api/v1/plans?userId=2", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var plans []types.Plan
+	suite.GetResponseData(recorder, &plans)
+	suite.Len(plans, 2)
+
+	recorder = suite.AS(2, "GET", "/api/v1/plans?userId=2&shared=true", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var shared []types.Plan
+	suite.GetResponseData(recorder, &shared)
+	suite.Require().Len(shared, 1)
+	suite.Equal(int64(1), shared[0].ID)
+	suite.Equal("view", shared[0].Permission)
+
+	recorder = suite.AS(2, "GET", "/api/v1/plans?userId=2&shared=false", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var own []types.Plan
+	suite.GetResponseData(recorder, &own)
+	suite.Require().Len(own, 1)
+	suite.Equal(int64(5), own[0].ID)
+	suite.Empty(own[0].Permission)
+
+	// Editors change the plan tree, including the groups assigned in it, but do not delete the plan
+	recorder = suite.AS(1, "PUT", "/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "edit"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 5})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "POST", "/api/v1/intervals", map[string]interface{}{
+		"planId":   1,
+		"name":     "Shared week",
+		"duration": "1 week",
+		"order":    3,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	recorder = suite.AS(2, "PUT", "/api/v1/groups/1", map[string]interface{}{"name": "Upper", "description": "Shared"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "DELETE", "/api/v1/plans/1", nil)
+	suite.AssertErrorResponse(recorder, 403, "Only the owner can manage this resource")
+
+	// Collaborators leave on their own
+	recorder = suite.AS(2, "DELETE", "/api/v1/plans/1/collaborators/2", nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/export", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+}
+
+// TestPlanShareLinks tests that share links give access until they are revoked or expire
+func (suite *IntegrationTestSuite) TestPlanShareLinks() {
+	recorder := suite.AS(1, "POST", "/api/v1/plans/1/share-links", map[string]interface{}{
+		"permission": "comment",
+		"expiresAt":  "2020-01-01T00:00:00Z",
+	})
+	suite.AssertErrorResponse(recorder, 400, "expiresAt must be in the future")
+
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/share-links", map[string]interface{}{"permission": "edit"})
+	suite.AssertStatusCode(recorder, 201)
+	var link types.PlanShareLink
+	suite.GetResponseData(recorder, &link)
+	suite.True(link.Active)
+	suite.NotEmpty(link.Token)
+	suite.Nil(link.ExpiresAt)
+
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/share-links", nil)
+	suite.AssertErrorResponse(recorder, 403, "Only the owner can manage this resource")
+
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/share-links/%s/accept", link.Token), nil)
+	suite.AssertErrorResponse(recorder, 409, "owner of a plan can not be its collaborator")
+	recorder = suite.AS(2, "POST", "/api/v1/share-links/unknown/accept", nil)
+	suite.AssertErrorResponse(recorder, 404, "Share link not found")
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/share-links/%s/accept", link.Token), nil)
+	suite.AssertStatusCode(recorder, 200)
+	var plan types.Plan
+	suite.GetResponseData(recorder, &plan)
+	suite.Equal(int64(1), plan.ID)
+	suite.Equal("edit", plan.Permission)
+
+	recorder = suite.AS(2, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 5})
+	suite.AssertStatusCode(recorder, 200)
+
+	// Revoking the link ends the access it gave
+	recorder = suite.AS(1, "DELETE", fmt.Sprintf("/api/v1/plans/1/share-links/%d", link.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	var revoked types.PlanShareLink
+	suite.GetResponseData(recorder, &revoked)
+	suite.False(revoked.Active)
+	suite.NotNil(revoked.RevokedAt)
+
+	recorder = suite.AS(2, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 4})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+	recorder = suite.AS(2, "GET", "/api/v1/plans?userId=2&shared=true", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var shared []types.Plan
+	suite.GetResponseData(recorder, &shared)
+	suite.Empty(shared)
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/share-links/%s/accept", link.Token), nil)
+	suite.AssertErrorResponse(recorder, 410, "Share link has expired or was revoked")
+
+	// So does its expiry
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/share-links", map[string]interface{}{
+		"permission": "view",
+		"expiresAt":  "2999-01-01T00:00:00Z",
+	})
+	suite.AssertStatusCode(recorder, 201)
+	suite.GetResponseData(recorder, &link)
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/share-links/%s/accept", link.Token), nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/export", nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	tx, err := suite.testDB.DB.Begin(suite.ctx)
+	suite.Require().NoError(err)
+	_, err = tx.Exec(suite.ctx, "UPDATE plan_share_links SET expires_at = CURRENT_TIMESTAMP - INTERVAL '1 hour' WHERE id = $1", link.ID)
+	suite.Require().NoError(err)
+	suite.Require().NoError(tx.Commit(suite.ctx))
+
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/export", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/share-links", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var links []types.PlanShareLink
+	suite.GetResponseData(recorder, &links)
+	suite.Require().Len(links, 2)
+	suite.False(links[0].Active)
+	suite.False(links[1].Active)
+}
@@ -0,0 +1,214 @@
+package integration
+
+import (
+	"backend/internal/plandoc"
+	"backend/internal/types"
+	"fmt"
+)
+
+type planVersionDiff struct {
+	From    int32           `json:"from"`
+	To      *int32          `json:"to"`
+	Changes plandoc.Changes `json:"changes"`
+}
+
+// TestPlanVersionsRecordAndDiff tests that changes to a plan's tree are recorded as
+// versions that can be compared
+func (suite *IntegrationTestSuite) TestPlanVersionsRecordAndDiff() {
+	recorder := suite.GET("/api/v1/plans/1/versions")
+	suite.AssertStatusCode(recorder, 200)
+	var versions []types.PlanVersion
+	suite.GetResponseData(recorder, &versions)
+	suite.Empty(versions)
+
+	// The first change records the plan as it was before it, then the change itself
+	recorder = suite.AS(1, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 9})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/intervals", map[string]interface{}{
+		"planId":      1,
+		"name":        "Week 3",
+		"description": "Third week of the plan",
+		"duration":    "1 week",
+		"order":       3,
+	})
+	suite.AssertStatusCode(recorder, 200)
+	// Failed requests and reads record nothing
+	recorder = suite.PUT("/api/v1/interval-exercise-prescriptions/999", map[string]interface{}{"sets": 2})
+	suite.AssertStatusCode(recorder, 404)
+
+	recorder = suite.GET("/api/v1/plans/1/versions")
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &versions)
+	suite.Require().Len(versions, 3)
+	suite.Equal(int32(3), versions[0].Number)
+	suite.Nil(versions[0].CreatedBy)
+	suite.Equal(int32(2), versions[1].Number)
+	suite.Require().NotNil(versions[1].CreatedBy)
+	suite.Equal(int64(1), *versions[1].CreatedBy)
+	suite.NotEmpty(versions[1].CreatedByName)
+	suite.Nil(versions[2].CreatedBy)
+	suite.Empty(versions[0].Document)
+
+	recorder = suite.GET("/api/v1/plans/1/versions/1")
+	suite.AssertStatusCode(recorder, 200)
+	var version types.PlanVersion
+	suite.GetResponseData(recorder, &version)
+	suite.NotEmpty(version.Document)
+	recorder = suite.GET("/api/v1/plans/1/versions/99")
+	suite.AssertErrorResponse(recorder, 404, "Version 99 not found")
+
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=1&to=3")
+	suite.AssertStatusCode(recorder, 200)
+	var diff planVersionDiff
+	suite.GetResponseData(recorder, &diff)
+	suite.Empty(diff.Changes.Plan)
+	suite.Require().Len(diff.Changes.IntervalsAdded, 1)
+	suite.Equal("Week 3", diff.Changes.IntervalsAdded[0].Name)
+	suite.Require().Len(diff.Changes.IntervalsChanged, 1)
+	suite.Equal(int32(1), diff.Changes.IntervalsChanged[0].Order)
+	suite.Require().Len(diff.Changes.IntervalsChanged[0].PrescriptionsChanged, 1)
+	fields := diff.Changes.IntervalsChanged[0].PrescriptionsChanged[0].Fields
+	suite.Require().Len(fields, 1)
+	suite.Equal("sets", fields[0].Field)
+	suite.Equal(float64(9), fields[0].To)
+
+	// Without to the version is compared with the plan as it is now
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=3")
+	suite.AssertStatusCode(recorder, 200)
+	var current planVersionDiff
+	suite.GetResponseData(recorder, &current)
+	suite.Nil(current.To)
+	suite.True(current.Changes.Empty())
+
+	recorder = suite.GET("/api/v1/plans/1/versions/diff")
+	suite.AssertErrorResponse(recorder, 400, "Missing required parameter: from")
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=1&to=99")
+	suite.AssertErrorResponse(recorder, 404, "Version 99 not found")
+	recorder = suite.AS(2, "GET", "/api/v1/plans/1/versions", nil)
+	suite.AssertStatusCode(recorder, 403)
+}
+
+// TestPlanVersionsRestore tests that restoring a version brings the tree back in place and
+// can itself be undone
+func (suite *IntegrationTestSuite) TestPlanVersionsRestore() {
+	recorder := suite.AS(1, "PUT", "/api/v1/interval-exercise-prescriptions/1", map[string]interface{}{"sets": 9})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(1, "DELETE", "/api/v1/intervals/2", nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.AS(2, "POST", "/api/v1/plans/1/versions/1/restore", nil)
+	suite.AssertStatusCode(recorder, 403)
+	recorder = suite.POST("/api/v1/plans/1/versions/99/restore", nil)
+	suite.AssertErrorResponse(recorder, 404, "Version 99 not found")
+
+	recorder = suite.AS(1, "POST", "/api/v1/plans/1/versions/1/restore", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var restored types.PlanVersion
+	suite.GetResponseData(recorder, &restored)
+	suite.Equal(int32(4), restored.Number)
+	suite.Require().NotNil(restored.RestoredFrom)
+	suite.Equal(int32(1), *restored.RestoredFrom)
+
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=1")
+	suite.AssertStatusCode(recorder, 200)
+	var diff planVersionDiff
+	suite.GetResponseData(recorder, &diff)
+	suite.True(diff.Changes.Empty())
+
+	// The interval that stayed is changed in place, the deleted one comes back
+	recorder = suite.GET("/api/v1/intervals?planId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	suite.Require().Len(intervals, 2)
+	suite.Equal(int64(1), intervals[0].ID)
+	suite.Equal("Week 2", intervals[1].Name)
+
+	recorder = suite.POST("/api/v1/plans/1/versions/1/restore", nil)
+	suite.AssertErrorResponse(recorder, 409, "The plan already matches version 1")
+
+	// Restoring the version before the restore undoes it
+	recorder = suite.POST("/api/v1/plans/1/versions/3/restore", nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=3")
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &diff)
+	suite.True(diff.Changes.Empty())
+}
+
+// TestPlanVersionsRestoreMovedIntervals tests that restoring matches intervals by ID, so
+// intervals that moved go back to their order instead of taking on each other's content
+func (suite *IntegrationTestSuite) TestPlanVersionsRestoreMovedIntervals() {
+	// Inserting an interval first moves the plan's intervals one order down
+	recorder := suite.POST("/api/v1/intervals", map[string]interface{}{
+		"planId":      1,
+		"name":        "Week 0",
+		"description": "Ramp-up week",
+		"duration":    "1 week",
+		"order":       1,
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.POST("/api/v1/plans/1/versions/1/restore", nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.GET("/api/v1/intervals?planId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	suite.Require().Len(intervals, 2)
+	suite.Equal(int64(1), intervals[0].ID)
+	suite.Equal("Week 1", intervals[0].Name)
+	suite.Equal(int32(1), intervals[0].Order)
+	suite.Equal(int64(2), intervals[1].ID)
+	suite.Equal("Week 2", intervals[1].Name)
+	suite.Equal(int32(2), intervals[1].Order)
+
+	recorder = suite.GET("/api/v1/plans/1/versions/diff?from=1")
+	suite.AssertStatusCode(recorder, 200)
+	var diff planVersionDiff
+	suite.GetResponseData(recorder, &diff)
+	suite.True(diff.Changes.Empty())
+}
+
+// TestPlanVersionsRestoreRenamedGroups tests that restoring matches groups by ID, so a
+// group renamed since the version was recorded is reused instead of duplicated
+func (suite *IntegrationTestSuite) TestPlanVersionsRestoreRenamedGroups() {
+	recorder := suite.DELETE("/api/v1/intervals/2")
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.PUT("/api/v1/groups/3", map[string]interface{}{
+		"name":        "Core Renamed",
+		"description": "Core exercise group",
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.POST("/api/v1/plans/1/versions/1/restore", nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.GET("/api/v1/groups?userId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var groups []types.Group
+	suite.GetResponseData(recorder, &groups)
+	suite.Len(groups, 3, "No group should be created")
+	for _, group := range groups {
+		if group.ID == 3 {
+			suite.Equal("Core", group.Name, "The group should be back to its recorded name")
+		}
+	}
+
+	// The deleted interval comes back scheduling the same groups
+	recorder = suite.GET("/api/v1/intervals?planId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	suite.Require().Len(intervals, 2)
+	recorder = suite.GET(fmt.Sprintf("/api/v1/intervals/%d/groups", intervals[1].ID))
+	suite.AssertStatusCode(recorder, 200)
+	var assignments []types.IntervalGroupAssignment
+	suite.GetResponseData(recorder, &assignments)
+	groupIds := []int64{}
+	for _, assignment := range assignments {
+		groupIds = append(groupIds, assignment.GroupId)
+	}
+	suite.ElementsMatch([]int64{1, 3}, groupIds)
+}
@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+	"strconv"
+)
+
+// TestProgressionRules tests creating, updating and deleting progression rules
+func (suite *IntegrationTestSuite) TestProgressionRules() {
+	recorder := suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"prescriptionId": 1,
+		"steps": []map[string]interface{}{
+			{"target": "reps", "amount": 1, "until": 13},
+			{"target": "sets", "amount": 1},
+		},
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var created types.ProgressionRule
+	suite.GetResponseData(recorder, &created)
+	suite.Equal(int64(1), *created.PrescriptionId)
+	suite.Nil(created.GroupId)
+	suite.Require().Len(created.Steps, 2)
+	suite.Equal("absolute", created.Steps[0].Unit, "The unit defaults to absolute amounts")
+	suite.Equal(float64(13), *created.Steps[0].Until)
+
+	recorder = suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"prescriptionId": 1,
+		"steps":          []map[string]interface{}{{"target": "rpe", "amount": 1}},
+	})
+	suite.AssertErrorResponse(recorder, 409, "The prescription already has a progression rule")
+
+	recorder = suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"prescriptionId": 1,
+		"groupId":        1,
+		"steps":          []map[string]interface{}{{"target": "rpe", "amount": 1}},
+	})
+	suite.AssertErrorResponse(recorder, 400, "Exactly one of prescriptionId and groupId is required")
+
+	recorder = suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"groupId": 2,
+		"steps":   []map[string]interface{}{{"target": "parameter", "amount": 2.5}},
+	})
+	suite.AssertErrorResponse(recorder, 400, "step 1 needs a parameter type exactly when it progresses a parameter")
+
+	recorder = suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"groupId": 9999,
+		"steps":   []map[string]interface{}{{"target": "rpe", "amount": 1}},
+	})
+	suite.AssertErrorResponse(recorder, 404, "Group not found")
+
+	path := "/api/v1/progression-rules/" + strconv.FormatInt(created.ID, 10)
+	recorder = suite.PUT(path, map[string]interface{}{
+		"steps": []map[string]interface{}{{"target": "reps", "amount": 10, "unit": "percent"}},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	var updated types.ProgressionRule
+	suite.GetResponseData(recorder, &updated)
+	suite.Require().Len(updated.Steps, 1)
+	suite.Equal("percent", updated.Steps[0].Unit)
+
+	recorder = suite.GET("/api/v1/progression-rules?prescriptionId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var rules []types.ProgressionRule
+	suite.GetResponseData(recorder, &rules)
+	suite.Require().Len(rules, 1)
+	suite.Equal(created.ID, rules[0].ID)
+
+	recorder = suite.DELETE(path)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.DELETE(path)
+	suite.AssertErrorResponse(recorder, 404, "Progression rule not found")
+}
+
+// TestPlanIntervalsProgression tests that previews and copies progress prescriptions by
+// their own rule, or else their group's
+func (suite *IntegrationTestSuite) TestPlanIntervalsProgression() {
+	recorder := suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"prescriptionId": 1,
+		"steps": []map[string]interface{}{
+			{"target": "reps", "amount": 1, "until": 13},
+			{"target": "sets", "amount": 1},
+		},
+	})
+	suite.AssertStatusCode(recorder, 201)
+	recorder = suite.POST("/api/v1/progression-rules", map[string]interface{}{
+		"groupId": 2,
+		"steps":   []map[string]interface{}{{"target": "rpe", "amount": 0.5}},
+	})
+	suite.AssertStatusCode(recorder, 201)
+
+	recorder = suite.GET("/api/v1/intervals/1/progression?count=3")
+	suite.AssertStatusCode(recorder, 200)
+	var preview types.ProgressionPreview
+	suite.GetResponseData(recorder, &preview)
+	suite.Require().Len(preview.Intervals, 3)
+
+	expected := []struct{ sets, reps, squatRPE int32 }{{3, 13, 7}, {4, 13, 8}, {5, 13, 8}}
+	for i, want := range expected {
+		interval := preview.Intervals[i]
+		suite.Equal(int32(i+1), interval.Step)
+		prescriptions := make(map[int64]types.IntervalExercisePrescription)
+		for _, prescription := range interval.Prescriptions {
+			prescriptions[prescription.ID] = prescription
+		}
+		suite.Equal(want.sets, prescriptions[1].Sets, "step %d", i+1)
+		suite.Equal(want.reps, *prescriptions[1].Reps, "step %d", i+1)
+		suite.Equal(want.squatRPE, *prescriptions[3].RPE, "step %d", i+1)
+		suite.Equal(int32(6), *prescriptions[2].RPE, "Prescriptions without a rule are unchanged")
+	}
+
+	recorder = suite.GET("/api/v1/intervals/1/progression?count=100")
+	suite.AssertErrorResponse(recorder, 400, "Count must be between 1 and 52")
+	recorder = suite.GET("/api/v1/intervals/9999/progression")
+	suite.AssertErrorResponse(recorder, 404, "Plan interval not found")
+
+	recorder = suite.POST("/api/v1/intervals/1/copy", map[string]interface{}{"count": 2})
+	suite.AssertStatusCode(recorder, 201)
+	var copies []types.PlanInterval
+	suite.GetResponseData(recorder, &copies)
+	suite.Require().Len(copies, 2)
+	suite.Equal("Week 1 (3)", copies[1].Name)
+	suite.Equal(int32(3), copies[1].Order, "Copies follow the source interval")
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/interval-exercise-prescriptions?groupId=1&intervalId=%d", copies[1].ID))
+	suite.AssertStatusCode(recorder, 200)
+	var prescriptions []types.IntervalExercisePrescription
+	suite.GetResponseData(recorder, &prescriptions)
+	suite.Require().Len(prescriptions, 2)
+	suite.Equal(int32(4), prescriptions[0].Sets)
+	suite.Equal(int32(13), *prescriptions[0].Reps)
+
+	// The copied prescription keeps its rule, the original Week 2 moves back
+	recorder = suite.GET(fmt.Sprintf("/api/v1/progression-rules?prescriptionId=%d", prescriptions[0].ID))
+	suite.AssertStatusCode(recorder, 200)
+	var rules []types.ProgressionRule
+	suite.GetResponseData(recorder, &rules)
+	suite.Len(rules, 1)
+
+	recorder = suite.GET("/api/v1/intervals?id=2")
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	suite.Require().Len(intervals, 1)
+	suite.Equal(int32(4), intervals[0].Order)
+}
+
+// TestPlanIntervalsDeload tests that a deload is inserted after its source with reduced
+// prescriptions and is told apart by analytics
+func (suite *IntegrationTestSuite) TestPlanIntervalsDeload() {
+	suite.tagPushUpsWithMetadata()
+	recorder := suite.GET("/api/v1/analytics/muscle-group-volume?planId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var before []types.MuscleGroupVolume
+	suite.GetResponseData(recorder, &before)
+	suite.Require().NotEmpty(before)
+
+	recorder = suite.POST("/api/v1/intervals/1/deload", map[string]interface{}{
+		"reductions": map[string]interface{}{"reps": 25},
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var deload types.PlanInterval
+	suite.GetResponseData(recorder, &deload)
+	suite.True(deload.IsDeload)
+	suite.Equal("Week 1 (deload)", deload.Name)
+	suite.Equal(int32(2), deload.Order, "The deload follows its source")
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/interval-exercise-prescriptions?groupId=1&intervalId=%d", deload.ID))
+	suite.AssertStatusCode(recorder, 200)
+	var prescriptions []types.IntervalExercisePrescription
+	suite.GetResponseData(recorder, &prescriptions)
+	suite.Require().Len(prescriptions, 2)
+	suite.Equal(int32(2), prescriptions[0].Sets, "40% fewer sets by default")
+	suite.Equal(int32(9), *prescriptions[0].Reps)
+	suite.Equal(int32(6), *prescriptions[0].RPE, "2 points less RPE by default")
+
+	recorder = suite.GET("/api/v1/intervals?planId=1")
+	suite.AssertStatusCode(recorder, 200)
+	var intervals []types.PlanInterval
+	suite.GetResponseData(recorder, &intervals)
+	suite.Require().Len(intervals, 3)
+	suite.Equal("Week 2", intervals[2].Name)
+	suite.Equal(int32(3), intervals[2].Order, "Following intervals move back")
+	suite.False(intervals[2].IsDeload)
+
+	// Copies of a deload are deloads too
+	recorder = suite.POST(fmt.Sprintf("/api/v1/intervals/%d/copy", deload.ID), map[string]interface{}{})
+	suite.AssertStatusCode(recorder, 201)
+	var copies []types.PlanInterval
+	suite.GetResponseData(recorder, &copies)
+	suite.Require().Len(copies, 1)
+	suite.True(copies[0].IsDeload)
+
+	recorder = suite.GET("/api/v1/analytics/muscle-group-volume?planId=1&excludeDeloads=true")
+	suite.AssertStatusCode(recorder, 200)
+	var excluded []types.MuscleGroupVolume
+	suite.GetResponseData(recorder, &excluded)
+	suite.Equal(before, excluded, "Excluding deloads leaves the training volume")
+
+	recorder = suite.POST("/api/v1/intervals/1/deload", map[string]interface{}{
+		"reductions": map[string]interface{}{"sets": 100},
+	})
+	suite.AssertErrorResponse(recorder, 400, "Invalid reductions: sets, reps and load reductions must be at least 0% and below 100%")
+	recorder = suite.POST("/api/v1/intervals/9999/deload", map[string]interface{}{})
+	suite.AssertErrorResponse(recorder, 404, "Plan interval not found")
+}
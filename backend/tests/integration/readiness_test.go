@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestReadinessCheckins tests saving, listing, trending and deleting check-ins
+func (suite *IntegrationTestSuite) TestReadinessCheckins() {
+	recorder := suite.PUT("/api/v1/users/1/readiness/2025-03-03", map[string]interface{}{
+		"sleepHours": 8,
+		"soreness":   map[string]int{"Upper Back": 4},
+		"fingerPain": 1,
+		"mood":       4,
+		"restingHr":  52,
+	})
+	suite.AssertStatusCode(recorder, 200)
+	var checkin types.ReadinessCheckin
+	suite.GetResponseData(recorder, &checkin)
+	suite.Equal("2025-03-03", checkin.CheckedOn)
+	suite.Equal(map[string]int32{"upper_back": 4}, checkin.Soreness)
+	// Sleep 100, soreness 60, finger pain 90 and mood 75; the heart rate has no baseline
+	suite.Require().NotNil(checkin.Score)
+	suite.Equal(81.3, *checkin.Score)
+
+	// A second check-in on the same day replaces the first
+	recorder = suite.PUT("/api/v1/users/1/readiness/2025-03-03", map[string]interface{}{"sleepHours": 6})
+	suite.AssertStatusCode(recorder, 200)
+	var replaced types.ReadinessCheckin
+	suite.GetResponseData(recorder, &replaced)
+	suite.Equal(checkin.ID, replaced.ID)
+	suite.Empty(replaced.Soreness)
+	suite.Nil(replaced.Mood)
+	suite.Equal(75.0, *replaced.Score)
+
+	recorder = suite.PUT("/api/v1/users/1/readiness/2025-03-05", map[string]interface{}{"mood": 1})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.GET("/api/v1/users/1/readiness")
+	suite.AssertStatusCode(recorder, 200)
+	var checkins []types.ReadinessCheckin
+	suite.GetResponseData(recorder, &checkins)
+	suite.Require().Len(checkins, 2)
+	suite.Equal("2025-03-05", checkins[0].CheckedOn)
+	suite.Equal(0.0, *checkins[0].Score)
+
+	recorder = suite.GET("/api/v1/users/1/readiness/trend?from=2025-03-03&to=2025-03-05")
+	suite.AssertStatusCode(recorder, 200)
+	var trend []types.ReadinessTrendDay
+	suite.GetResponseData(recorder, &trend)
+	suite.Require().Len(trend, 3)
+	suite.Nil(trend[1].Score)
+	suite.Equal(75.0, *trend[1].ScoreAverage)
+	suite.Equal(37.5, *trend[2].ScoreAverage)
+
+	recorder = suite.PUT("/api/v1/users/1/readiness/2025-03-06", map[string]interface{}{})
+	suite.AssertErrorResponse(recorder, 400, "A check-in needs at least one measure")
+	recorder = suite.PUT("/api/v1/users/1/readiness/2025-03-06", map[string]interface{}{"soreness": map[string]int{"toes": 3}})
+	suite.AssertErrorResponse(recorder, 400, "unknown body area")
+	recorder = suite.PUT("/api/v1/users/1/readiness/2025-03-06", map[string]interface{}{"mood": 6})
+	suite.AssertErrorResponse(recorder, 400, "Mood must be between 1 and 5")
+	recorder = suite.PUT("/api/v1/users/1/readiness/2999-01-01", map[string]interface{}{"mood": 3})
+	suite.AssertErrorResponse(recorder, 400, "future dates")
+
+	recorder = suite.DELETE("/api/v1/users/1/readiness/2025-03-05")
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.DELETE("/api/v1/users/1/readiness/2025-03-05")
+	suite.AssertErrorResponse(recorder, 404, "Check-in not found")
+}
+
+// TestReadinessRecommendation tests that sore fingers swap a finger training session for
+// an antagonist group of its interval, and that low readiness shows in the training load
+func (suite *IntegrationTestSuite) TestReadinessRecommendation() {
+	// Push-ups train the chest; squats stand in for a hangboard session
+	suite.tagPushUpsWithMetadata()
+	recorder := suite.PUT("/api/v1/exercises/2", map[string]interface{}{
+		"name":                "Squats",
+		"description":         "Basic squat exercise for lower body strength",
+		"primaryMuscleGroups": []string{"fingers"},
+		"equipment":           []string{"hangboard"},
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.PUT("/api/v1/plans/1", map[string]interface{}{
+		"name":      "User1 Regular Plan",
+		"startDate": "2025-03-03",
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	suite.AssertStatusCode(recorder, 201)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	var hangboard *types.ScheduledSession
+	for i := range sessions {
+		if sessions[i].GroupID == 2 && sessions[i].PlanIntervalID == 1 {
+			hangboard = &sessions[i]
+			break
+		}
+	}
+	suite.Require().NotNil(hangboard)
+
+	// Without a check-in nothing changes
+	recorder = suite.GET(fmt.Sprintf("/api/v1/sessions/%d/recommendation", hangboard.ID))
+	suite.AssertStatusCode(recorder, 200)
+	var recommendation types.ReadinessRecommendation
+	suite.GetResponseData(recorder, &recommendation)
+	suite.Nil(recommendation.Checkin)
+	suite.Empty(recommendation.Actions)
+
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/users/1/readiness/%s", hangboard.Date), map[string]interface{}{
+		"sleepHours": 8,
+		"fingerPain": 6,
+		"mood":       4,
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/sessions/%d/recommendation", hangboard.ID))
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &recommendation)
+	suite.Require().NotNil(recommendation.Checkin)
+	suite.Require().Len(recommendation.Actions, 1)
+	suite.Equal("swap_group", recommendation.Actions[0].Kind)
+	suite.Equal(int64(1), *recommendation.Actions[0].GroupId)
+	suite.Equal("Upper Body", recommendation.Actions[0].GroupName)
+
+	// A poor night on top of it lowers readiness below the threshold
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/users/1/readiness/%s", hangboard.Date), map[string]interface{}{
+		"sleepHours": 4,
+		"fingerPain": 6,
+		"mood":       2,
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.GET(fmt.Sprintf("/api/v1/sessions/%d/recommendation", hangboard.ID))
+	suite.GetResponseData(recorder, &recommendation)
+	suite.Require().Len(recommendation.Actions, 2)
+	suite.Equal("reduce_intensity", recommendation.Actions[0].Kind)
+	suite.Equal(0.8, *recommendation.Actions[0].IntensityFactor)
+
+	recorder = suite.GET(fmt.Sprintf("/api/v1/analytics/training-load?userId=1&from=%s&to=%s", hangboard.Date, hangboard.Date))
+	suite.AssertStatusCode(recorder, 200)
+	var load types.TrainingLoad
+	suite.GetResponseData(recorder, &load)
+	suite.Require().Len(load.Days, 1)
+	suite.Require().NotNil(load.Days[0].Readiness)
+	suite.Contains(load.Days[0].Flags, "readiness_low")
+
+	recorder = suite.GET("/api/v1/sessions/999999/recommendation")
+	suite.AssertErrorResponse(recorder, 404, "Session not found")
+}
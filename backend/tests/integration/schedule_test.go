@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TestPlanScheduleAndCalendarFeed tests generating a schedule, moving a session and
+// seeing the move in the user's calendar feed
+func (suite *IntegrationTestSuite) TestPlanScheduleAndCalendarFeed() {
+	// Without a start date there is nothing to schedule from
+	recorder := suite.POST("/api/v1/plans/1/schedule", nil)
+	suite.AssertErrorResponse(recorder, 400, "no start date")
+
+	recorder = suite.PUT("/api/v1/plans/1", map[string]interface{}{
+		"name":        "User1 Regular Plan",
+		"description": "A regular workout plan for user 1",
+		"startDate":   "2025-03-03",
+	})
+	suite.AssertStatusCode(recorder, 200)
+	var plan types.Plan
+	suite.GetResponseData(recorder, &plan)
+	suite.Equal("2025-03-03", plan.StartDate)
+
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	suite.AssertStatusCode(recorder, 201)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+
+	// Week 1 schedules groups 3x and 2x, week 2 schedules them 2x and 1x
+	suite.Len(sessions, 8)
+	suite.Equal("2025-03-03", sessions[0].Date)
+	suite.Equal("2025-03-10", sessions[5].Date)
+	for _, session := range sessions {
+		suite.False(session.Rescheduled)
+	}
+
+	// Editing without a start date keeps the current one
+	recorder = suite.PUT("/api/v1/plans/1", map[string]interface{}{"name": "Renamed"})
+	suite.GetResponseData(recorder, &plan)
+	suite.Equal("2025-03-03", plan.StartDate)
+
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/sessions/%d", sessions[0].ID), map[string]string{"date": "2025-03-04"})
+	suite.AssertStatusCode(recorder, 200)
+	var moved types.ScheduledSession
+	suite.GetResponseData(recorder, &moved)
+	suite.Equal("2025-03-04", moved.Date)
+	suite.Equal("2025-03-03", moved.OriginalDate)
+	suite.True(moved.Rescheduled)
+
+	recorder = suite.PUT(fmt.Sprintf("/api/v1/sessions/%d", sessions[0].ID), map[string]string{"date": "tomorrow"})
+	suite.AssertErrorResponse(recorder, 400, "invalid date")
+
+	// The feed is served as-is and reflects the move
+	recorder = suite.POST("/api/v1/users/1/calendar-feeds", nil)
+	suite.AssertStatusCode(recorder, 201)
+	var feed types.CalendarFeed
+	suite.GetResponseData(recorder, &feed)
+	suite.Len(feed.Token, 64)
+
+	recorder = suite.GET(feed.Path)
+	suite.AssertStatusCode(recorder, 200)
+	suite.Contains(recorder.Header().Get("Content-Type"), "text/calendar")
+	calendar := recorder.Body.String()
+	suite.True(strings.HasPrefix(calendar, "BEGIN:VCALENDAR"))
+	suite.Equal(8, strings.Count(calendar, "BEGIN:VEVENT"))
+	suite.Contains(calendar, fmt.Sprintf("UID:session-%d@rocket\r\n", sessions[0].ID))
+	suite.Contains(calendar, "DTSTART;VALUE=DATE:20250304")
+	suite.Contains(calendar, "Moved from 2025-03-03")
+
+	// Other users' feeds do not include the plan, and revoked feeds stop working
+	recorder = suite.AS(2, "POST", "/api/v1/users/2/calendar-feeds", nil)
+	var otherFeed types.CalendarFeed
+	suite.GetResponseData(recorder, &otherFeed)
+	recorder = suite.GET(otherFeed.Path)
+	suite.Equal(0, strings.Count(recorder.Body.String(), "BEGIN:VEVENT"))
+
+	recorder = suite.DELETE(fmt.Sprintf("/api/v1/users/1/calendar-feeds/%d", feed.ID))
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.GET(feed.Path)
+	suite.AssertErrorResponse(recorder, 404, "Calendar feed not found")
+}
+
+// TestPlanAssignmentScheduling tests assigning a plan with training day preferences,
+// skipping a session and reflowing the schedule after missed sessions
+func (suite *IntegrationTestSuite) TestPlanAssignmentScheduling() {
+	// The athlete assigns themselves a plan shared with them
+	recorder := suite.PUT("/api/v1/plans/1/collaborators/2", map[string]interface{}{"permission": "view"})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.AS(2, "POST", "/api/v1/plan-assignments", map[string]interface{}{
+		"planId":       1,
+		"userId":       2,
+		"startDate":    "2025-03-03",
+		"trainingDays": []int{6, 2, 4, 2},
+		"minRestDays":  1,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var assignment types.PlanAssignment
+	suite.GetResponseData(recorder, &assignment)
+	suite.Equal([]int32{2, 4, 6}, assignment.TrainingDays)
+	suite.Equal(int32(1), assignment.MinRestDays)
+
+	recorder = suite.AS(2, "POST", "/api/v1/plan-assignments", map[string]interface{}{
+		"planId":    1,
+		"userId":    2,
+		"startDate": "2025-03-10",
+	})
+	suite.AssertErrorResponse(recorder, 409, "already assigned")
+
+	recorder = suite.POST("/api/v1/plan-assignments", map[string]interface{}{
+		"planId":       1,
+		"userId":       1,
+		"startDate":    "2025-03-10",
+		"trainingDays": []int{7},
+	})
+	suite.AssertErrorResponse(recorder, 400, "Training days")
+
+	// Every session lands on a Tuesday, Thursday or Saturday
+	recorder = suite.AS(2, "GET", fmt.Sprintf("/api/v1/sessions?assignmentId=%d&from=2025-03-01", assignment.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Len(sessions, 8)
+	for _, session := range sessions {
+		suite.Equal(int64(2), session.UserID)
+		suite.Equal("scheduled", session.Status)
+		weekday := date(session.Date).Weekday()
+		suite.Contains([]time.Weekday{time.Tuesday, time.Thursday, time.Saturday}, weekday, session.Date)
+	}
+	suite.Equal("2025-03-04", sessions[0].Date)
+
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/sessions/%d/skip", sessions[0].ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	var skipped types.ScheduledSession
+	suite.GetResponseData(recorder, &skipped)
+	suite.Equal("skipped", skipped.Status)
+
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/sessions/%d/skip", sessions[0].ID), nil)
+	suite.AssertErrorResponse(recorder, 409, "Only scheduled sessions")
+
+	// The rest of week 1 was missed, so it moves to the next training days after the 9th
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/plan-assignments/%d/reflow?from=2025-03-09", assignment.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	var reflowed []types.ScheduledSession
+	suite.GetResponseData(recorder, &reflowed)
+	suite.Len(reflowed, 8)
+	suite.Equal("skipped", reflowed[0].Status)
+	suite.Equal("2025-03-04", reflowed[0].Date)
+	suite.Equal("2025-03-11", reflowed[1].Date)
+	suite.True(reflowed[1].Rescheduled)
+	for _, session := range reflowed[1:] {
+		suite.GreaterOrEqual(session.Date, "2025-03-11")
+	}
+
+	// Upcoming sessions can be narrowed down by status and date
+	recorder = suite.AS(2, "GET", "/api/v1/sessions?userId=2&from=2025-03-01&status=skipped", nil)
+	suite.GetResponseData(recorder, &sessions)
+	suite.Len(sessions, 1)
+
+	recorder = suite.AS(2, "GET", "/api/v1/sessions?userId=2&status=done", nil)
+	suite.AssertErrorResponse(recorder, 400, "Status")
+
+	recorder = suite.AS(2, "PUT", fmt.Sprintf("/api/v1/plan-assignments/%d", assignment.ID), map[string]interface{}{"minRestDays": 9})
+	suite.AssertErrorResponse(recorder, 400, "Minimum rest days")
+
+	recorder = suite.AS(2, "DELETE", fmt.Sprintf("/api/v1/plan-assignments/%d", assignment.ID), nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(2, "GET", fmt.Sprintf("/api/v1/plan-assignments/%d", assignment.ID), nil)
+	suite.AssertErrorResponse(recorder, 404, "Plan assignment not found")
+}
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
@@ -2,14 +2,18 @@ package integration
 
 import (
 	"backend/internal/api"
+	"backend/internal/auth"
+	"backend/internal/storage"
 	"backend/tests/testdb"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -18,6 +22,8 @@ import (
 type IntegrationTestSuite struct {
 	suite.Suite
 	testDB *testdb.TestDatabase
+	store  *storage.LocalStorage
+	tokens *auth.Tokens
 	router http.Handler
 	ctx    context.Context
 }
@@ -35,8 +41,12 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	err = suite.testDB.SeedTestData(suite.ctx)
 	suite.Require().NoError(err, "Failed to seed test data")
 
-	// Initialize router with test database
-	suite.router = api.NewRouter(suite.testDB.DB)
+	// Media uploads go to a temporary directory that is removed with the suite
+	suite.store = storage.NewLocalStorage(suite.T().TempDir(), "http://localhost"+storage.LocalDownloadPath, "test-signing-secret")
+
+	// Initialize router with test database; requests carry tokens signed with the test secret
+	suite.tokens = auth.NewTokens("test-jwt-secret")
+	suite.router = api.NewRouter(suite.testDB.DB, suite.store, suite.tokens)
 }
 
 // TearDownSuite runs once after all tests in the suite
@@ -62,14 +72,26 @@ func (suite *IntegrationTestSuite) TearDownTest() {
 
 // HTTP Request Helpers
 
-// GET performs a GET request and returns the response recorder
-func (suite *IntegrationTestSuite) GET(path string) *httptest.ResponseRecorder {
-	req := httptest.NewRequest("GET", path, nil)
+// defaultUser is the user requests are made on behalf of unless a test says otherwise;
+// the fixtures give them most of the data
+const defaultUser = 1
+
+// serve performs a request with a bearer token for the user and returns the response recorder
+func (suite *IntegrationTestSuite) serve(userId int64, req *http.Request) *httptest.ResponseRecorder {
+	token, err := suite.tokens.Issue(userId, time.Hour)
+	suite.Require().NoError(err, "Failed to issue token")
+	req.Header.Set("Authorization", "Bearer "+token)
 	recorder := httptest.NewRecorder()
 	suite.router.ServeHTTP(recorder, req)
 	return recorder
 }
 
+// GET performs a GET request and returns the response recorder
+func (suite *IntegrationTestSuite) GET(path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	return suite.serve(defaultUser, req)
+}
+
 // POST performs a POST request with JSON body and returns the response recorder
 func (suite *IntegrationTestSuite) POST(path string, body interface{}) *httptest.ResponseRecorder {
 	jsonBody, err := json.Marshal(body)
@@ -77,9 +99,7 @@ func (suite *IntegrationTestSuite) POST(path string, body interface{}) *httptest
 
 	req := httptest.NewRequest("POST", path, bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
-	recorder := httptest.NewRecorder()
-	suite.router.ServeHTTP(recorder, req)
-	return recorder
+	return suite.serve(defaultUser, req)
 }
 
 // PUT performs a PUT request with JSON body and returns the response recorder
@@ -89,17 +109,58 @@ func (suite *IntegrationTestSuite) PUT(path string, body interface{}) *httptest.
 
 	req := httptest.NewRequest("PUT", path, bytes.NewBuffer(jsonBody))
 	req.Header.Set("Content-Type", "application/json")
-	recorder := httptest.NewRecorder()
-	suite.router.ServeHTTP(recorder, req)
-	return recorder
+	return suite.serve(defaultUser, req)
+}
+
+// POSTRaw performs a POST request with a raw body of the given content type and returns the response recorder
+func (suite *IntegrationTestSuite) POSTRaw(path string, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return suite.serve(defaultUser, req)
+}
+
+// ASRaw performs a request with a raw body of the given content type on behalf of a user
+// and returns the response recorder
+func (suite *IntegrationTestSuite) ASRaw(userId int64, method string, path string, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return suite.serve(userId, req)
 }
 
 // DELETE performs a DELETE request and returns the response recorder
 func (suite *IntegrationTestSuite) DELETE(path string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest("DELETE", path, nil)
-	recorder := httptest.NewRecorder()
-	suite.router.ServeHTTP(recorder, req)
-	return recorder
+	return suite.serve(defaultUser, req)
+}
+
+// AS performs a request on behalf of a user, with a JSON body unless body is nil, and
+// returns the response recorder
+func (suite *IntegrationTestSuite) AS(userId int64, method string, path string, body interface{}) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		suite.Require().NoError(err, "Failed to marshal request body")
+		req = httptest.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+	return suite.serve(userId, req)
+}
+
+// UPLOAD performs a multipart POST with a single file field and returns the response recorder
+func (suite *IntegrationTestSuite) UPLOAD(path string, fieldName string, fileName string, content []byte) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(fieldName, fileName)
+	suite.Require().NoError(err, "Failed to create multipart file")
+	_, err = part.Write(content)
+	suite.Require().NoError(err, "Failed to write multipart file")
+	suite.Require().NoError(writer.Close(), "Failed to close multipart writer")
+
+	req := httptest.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return suite.serve(defaultUser, req)
 }
 
 // Response Assertion Helpers
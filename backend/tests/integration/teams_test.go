@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// createUser adds a user beyond the fixtures and returns their ID
+func (suite *IntegrationTestSuite) createUser(email string) int64 {
+	tx, err := suite.testDB.DB.Begin(suite.ctx)
+	suite.Require().NoError(err)
+	var id int64
+	err = tx.QueryRow(suite.ctx, "INSERT INTO users (email, first_name, last_name) VALUES ($1, 'Test', 'Athlete') RETURNING id", email).Scan(&id)
+	suite.Require().NoError(err)
+	suite.Require().NoError(tx.Commit(suite.ctx))
+	return id
+}
+
+// joinTeam invites a user to the team on behalf of the inviter and accepts the invitation
+func (suite *IntegrationTestSuite) joinTeam(teamId int64, inviter int64, userId int64, role string) {
+	recorder := suite.AS(inviter, "POST", fmt.Sprintf("/api/v1/teams/%d/invitations", teamId), map[string]interface{}{
+		"userId": userId,
+		"role":   role,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var invitation types.TeamInvitation
+	suite.GetResponseData(recorder, &invitation)
+	recorder = suite.AS(userId, "POST", fmt.Sprintf("/api/v1/invitations/%d/accept", invitation.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+}
+
+// TestTeamRoster tests creating a team, invitations and role changes
+func (suite *IntegrationTestSuite) TestTeamRoster() {
+	recorder := suite.AS(1, "POST", "/api/v1/teams", map[string]interface{}{"name": "Crag Rats"})
+	suite.AssertStatusCode(recorder, 201)
+	var team types.Team
+	suite.GetResponseData(recorder, &team)
+	suite.Equal("owner", team.Role)
+	suite.Require().Len(team.Members, 1)
+	suite.Equal(int64(1), team.Members[0].UserID)
+
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/teams/%d/invitations", team.ID), map[string]interface{}{
+		"userId": 2,
+		"role":   "athlete",
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var invitation types.TeamInvitation
+	suite.GetResponseData(recorder, &invitation)
+	suite.Equal("pending", invitation.Status)
+
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/teams/%d/invitations", team.ID), map[string]interface{}{
+		"userId": 2,
+		"role":   "athlete",
+	})
+	suite.AssertErrorResponse(recorder, 409, "pending invitation")
+
+	recorder = suite.AS(2, "GET", "/api/v1/users/2/invitations?status=pending", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var invitations []types.TeamInvitation
+	suite.GetResponseData(recorder, &invitations)
+	suite.Require().Len(invitations, 1)
+
+	// Only the invited user answers
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/invitations/%d/accept", invitation.ID), nil)
+	suite.AssertErrorResponse(recorder, 403, "Only the invited user")
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/invitations/%d/accept", invitation.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/invitations/%d/decline", invitation.ID), nil)
+	suite.AssertErrorResponse(recorder, 409, "no longer pending")
+
+	recorder = suite.AS(2, "GET", "/api/v1/users/2/teams", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var teams []types.Team
+	suite.GetResponseData(recorder, &teams)
+	suite.Require().Len(teams, 1)
+	suite.Equal("athlete", teams[0].Role)
+
+	// Athletes can neither invite coaches nor rename the team
+	recorder = suite.AS(2, "POST", fmt.Sprintf("/api/v1/teams/%d/invitations", team.ID), map[string]interface{}{
+		"userId": 1,
+		"role":   "coach",
+	})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to invite a coach")
+	recorder = suite.AS(2, "PUT", fmt.Sprintf("/api/v1/teams/%d", team.ID), map[string]interface{}{"name": "Mine"})
+	suite.AssertErrorResponse(recorder, 403, "Only owners")
+
+	// The last owner stays
+	recorder = suite.AS(1, "PUT", fmt.Sprintf("/api/v1/teams/%d/members/1", team.ID), map[string]interface{}{"role": "coach"})
+	suite.AssertErrorResponse(recorder, 409, "at least one owner")
+	recorder = suite.AS(1, "DELETE", fmt.Sprintf("/api/v1/teams/%d/members/1", team.ID), nil)
+	suite.AssertErrorResponse(recorder, 409, "at least one owner")
+
+	// Athletes may leave
+	recorder = suite.AS(2, "DELETE", fmt.Sprintf("/api/v1/teams/%d/members/2", team.ID), nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(2, "GET", fmt.Sprintf("/api/v1/teams/%d", team.ID), nil)
+	suite.AssertErrorResponse(recorder, 403, "Not a member of this team")
+
+	recorder = suite.AS(1, "DELETE", fmt.Sprintf("/api/v1/teams/%d", team.ID), nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(1, "GET", fmt.Sprintf("/api/v1/teams/%d", team.ID), nil)
+	suite.AssertErrorResponse(recorder, 404, "Team not found")
+}
+
+// TestTeamPlanAssignment tests that a coach assigns linked copies of a plan, reads their
+// athletes' data, and that athletes can not read each other's data
+func (suite *IntegrationTestSuite) TestTeamPlanAssignment() {
+	athlete := suite.createUser("athlete@example.com")
+
+	recorder := suite.AS(1, "POST", "/api/v1/teams", map[string]interface{}{"name": "Crag Rats"})
+	suite.AssertStatusCode(recorder, 201)
+	var team types.Team
+	suite.GetResponseData(recorder, &team)
+	suite.joinTeam(team.ID, 1, 2, "athlete")
+	suite.joinTeam(team.ID, 1, athlete, "athlete")
+
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/teams/%d/assignments", team.ID), map[string]interface{}{
+		"planId":     1,
+		"athleteIds": []int64{2, athlete},
+		"startDate":  "2025-03-03",
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var assigned []types.TeamPlanAssignment
+	suite.GetResponseData(recorder, &assigned)
+	suite.Require().Len(assigned, 2)
+	planCopy := assigned[0].Plan
+	suite.Equal(int64(2), planCopy.UserID)
+	suite.Require().NotNil(planCopy.SourcePlanID)
+	suite.Equal(int64(1), *planCopy.SourcePlanID)
+	suite.Require().NotNil(assigned[0].Assignment)
+	suite.Equal(planCopy.ID, assigned[0].Assignment.PlanID)
+
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/teams/%d/assignments", team.ID), map[string]interface{}{
+		"planId":     1,
+		"athleteIds": []int64{1},
+	})
+	suite.AssertErrorResponse(recorder, 400, "User 1 is not an athlete of this team")
+
+	recorder = suite.AS(1, "GET", "/api/v1/plans/1/copies", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var copies []types.Plan
+	suite.GetResponseData(recorder, &copies)
+	suite.Len(copies, 2)
+
+	// The coach reads the athlete's sessions and plan but can not change them
+	recorder = suite.AS(1, "GET", "/api/v1/sessions?userId=2&from=2025-03-01", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Require().NotEmpty(sessions)
+	recorder = suite.AS(1, "GET", fmt.Sprintf("/api/v1/plans/%d/export", planCopy.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(1, "POST", fmt.Sprintf("/api/v1/sessions/%d/skip", sessions[0].ID), map[string]interface{}{})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+	recorder = suite.AS(1, "PUT", "/api/v1/users/2/readiness/2025-03-03", map[string]interface{}{"mood": 3})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+
+	// Athletes see their own sessions by default and not each other's
+	recorder = suite.AS(2, "GET", "/api/v1/sessions?from=2025-03-01", nil)
+	suite.AssertStatusCode(recorder, 200)
+	var own []types.ScheduledSession
+	suite.GetResponseData(recorder, &own)
+	suite.Len(own, len(sessions))
+	recorder = suite.AS(athlete, "GET", "/api/v1/sessions?userId=2", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+	recorder = suite.AS(athlete, "GET", fmt.Sprintf("/api/v1/sessions/%d/recommendation", sessions[0].ID), nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+	recorder = suite.AS(athlete, "GET", "/api/v1/analytics/training-load?userId=2", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+	recorder = suite.AS(2, "GET", "/api/v1/users/1/benchmarks", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to view this resource")
+
+	// Athletes change their own copy, but not the coach's plan
+	recorder = suite.AS(2, "PUT", fmt.Sprintf("/api/v1/plans/%d", planCopy.ID), map[string]interface{}{"name": "My copy"})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "DELETE", "/api/v1/plans/1", nil)
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+	recorder = suite.AS(2, "POST", "/api/v1/intervals", map[string]interface{}{
+		"planId":   1,
+		"name":     "Extra",
+		"duration": "1 week",
+		"order":    9,
+	})
+	suite.AssertErrorResponse(recorder, 403, "Not allowed to change this resource")
+}
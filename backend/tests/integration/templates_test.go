@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestTemplateGallery tests browsing, searching and filtering the public templates
+func (suite *IntegrationTestSuite) TestTemplateGallery() {
+	recorder := suite.PUT("/api/v1/plans/2", map[string]interface{}{
+		"name":        "User1 Template Plan",
+		"description": "A template plan for user 1",
+		"isTemplate":  true,
+		"isPublic":    true,
+	})
+	suite.AssertStatusCode(recorder, 200)
+
+	recorder = suite.POST("/api/v1/categories", map[string]interface{}{
+		"name":   "Pushing",
+		"icon":   "arrow-up",
+		"userId": 1,
+	})
+	suite.AssertStatusCode(recorder, 201)
+	var category types.Category
+	suite.GetResponseData(recorder, &category)
+	recorder = suite.POST(fmt.Sprintf("/api/v1/exercises/1/categories/%d", category.ID), nil)
+	suite.AssertStatusCode(recorder, 200)
+
+	// Only public templates are listed, the most recently updated first
+	recorder = suite.GET("/api/v1/templates")
+	suite.AssertStatusCode(recorder, 200)
+	var templates []types.Template
+	suite.GetResponseData(recorder, &templates)
+	suite.Require().Len(templates, 2)
+	suite.Equal(int64(2), templates[0].ID)
+	suite.Equal(int64(4), templates[1].ID)
+	suite.Empty(templates[1].Intervals)
+
+	recorder = suite.GET("/api/v1/templates?search=public")
+	suite.AssertStatusCode(recorder, 200)
+	var found []types.Template
+	suite.GetResponseData(recorder, &found)
+	suite.Require().Len(found, 1)
+	suite.Equal(int64(4), found[0].ID)
+
+	// The category filter matches the exercises a template prescribes
+	recorder = suite.GET("/api/v1/templates?category=pushing")
+	suite.AssertStatusCode(recorder, 200)
+	var filtered []types.Template
+	suite.GetResponseData(recorder, &filtered)
+	suite.Require().Len(filtered, 1)
+	suite.Equal(int64(2), filtered[0].ID)
+	suite.Require().Len(filtered[0].Intervals, 1)
+	suite.Equal("Template Week 1", filtered[0].Intervals[0].Name)
+	suite.Equal(int32(1), filtered[0].Intervals[0].PrescriptionCount)
+	suite.Len(filtered[0].Intervals[0].Groups, 1)
+
+	recorder = suite.GET("/api/v1/templates?sort=oldest")
+	suite.AssertErrorResponse(recorder, 400, "Invalid sort")
+
+	recorder = suite.GET("/api/v1/templates/1")
+	suite.AssertErrorResponse(recorder, 404, "Template not found")
+	recorder = suite.GET("/api/v1/templates/4")
+	suite.AssertStatusCode(recorder, 200)
+	var template types.Template
+	suite.GetResponseData(recorder, &template)
+	suite.Equal("User1 Public Template", template.Name)
+	suite.Nil(template.AverageRating)
+}
+
+// TestTemplateRatingsAndForks tests that ratings and forks rank the gallery and that forks
+// link back to their template
+func (suite *IntegrationTestSuite) TestTemplateRatingsAndForks() {
+	recorder := suite.PUT("/api/v1/templates/4/rating", map[string]interface{}{"userId": 1, "stars": 5})
+	suite.AssertErrorResponse(recorder, 400, "author of a template can not rate it")
+	recorder = suite.AS(2, "PUT", "/api/v1/templates/4/rating", map[string]interface{}{"userId": 2, "stars": 6})
+	suite.AssertErrorResponse(recorder, 400, "Stars must be between 1 and 5")
+	recorder = suite.AS(2, "PUT", "/api/v1/templates/1/rating", map[string]interface{}{"userId": 2, "stars": 3})
+	suite.AssertErrorResponse(recorder, 404, "Template not found")
+	recorder = suite.AS(2, "PUT", "/api/v1/templates/4/rating", map[string]interface{}{"userId": 1, "stars": 3})
+	suite.AssertStatusCode(recorder, 403)
+
+	recorder = suite.AS(2, "PUT", "/api/v1/templates/4/rating", map[string]interface{}{"userId": 2, "stars": 2})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "PUT", "/api/v1/templates/4/rating", map[string]interface{}{"userId": 2, "stars": 4})
+	suite.AssertStatusCode(recorder, 200)
+	var rating types.PlanRating
+	suite.GetResponseData(recorder, &rating)
+	suite.Equal(int32(4), rating.Stars)
+
+	recorder = suite.GET("/api/v1/templates/4")
+	suite.AssertStatusCode(recorder, 200)
+	var template types.Template
+	suite.GetResponseData(recorder, &template)
+	suite.Equal(int32(1), template.RatingCount)
+	suite.Require().NotNil(template.AverageRating)
+	suite.InDelta(4.0, *template.AverageRating, 0.001)
+
+	// Forks are private copies in the user's account that link back to the template
+	recorder = suite.PUT("/api/v1/plans/2", map[string]interface{}{
+		"name":        "User1 Template Plan",
+		"description": "A template plan for user 1",
+		"isTemplate":  true,
+		"isPublic":    true,
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.AS(2, "POST", "/api/v1/templates/1/fork", map[string]interface{}{"userId": 2})
+	suite.AssertErrorResponse(recorder, 404, "Template not found")
+	recorder = suite.AS(2, "POST", "/api/v1/templates/2/fork", map[string]interface{}{"userId": 1})
+	suite.AssertStatusCode(recorder, 403)
+
+	recorder = suite.AS(2, "POST", "/api/v1/templates/4/fork", map[string]interface{}{"userId": 2})
+	suite.AssertStatusCode(recorder, 201)
+	var fork types.Plan
+	suite.GetResponseData(recorder, &fork)
+	suite.Equal(int64(2), fork.UserID)
+	suite.False(fork.IsTemplate)
+	suite.False(fork.IsPublic)
+	suite.Require().NotNil(fork.SourcePlanID)
+	suite.Equal(int64(4), *fork.SourcePlanID)
+
+	// The forked template ranks first by popularity, the newer one by recency
+	recorder = suite.GET("/api/v1/templates?sort=popular")
+	suite.AssertStatusCode(recorder, 200)
+	var popular []types.Template
+	suite.GetResponseData(recorder, &popular)
+	suite.Require().Len(popular, 2)
+	suite.Equal(int64(4), popular[0].ID)
+	suite.Equal(int32(1), popular[0].ForkCount)
+	suite.Equal(int32(0), popular[1].ForkCount)
+
+	recorder = suite.GET("/api/v1/templates?sort=recent")
+	suite.AssertStatusCode(recorder, 200)
+	var recent []types.Template
+	suite.GetResponseData(recorder, &recent)
+	suite.Require().Len(recent, 2)
+	suite.Equal(int64(2), recent[0].ID)
+
+	recorder = suite.AS(2, "DELETE", "/api/v1/templates/4/rating?userId=2", nil)
+	suite.AssertStatusCode(recorder, 204)
+	recorder = suite.AS(2, "DELETE", "/api/v1/templates/4/rating?userId=2", nil)
+	suite.AssertErrorResponse(recorder, 404, "Rating not found")
+}
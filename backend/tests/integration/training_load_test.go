@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"backend/internal/types"
+	"fmt"
+)
+
+// TestTrainingLoad tests that completed sessions rated with an RPE and duration make up
+// the training load series, and that the user's thresholds decide the flags
+func (suite *IntegrationTestSuite) TestTrainingLoad() {
+	recorder := suite.PUT("/api/v1/plans/1", map[string]interface{}{
+		"name":      "User1 Regular Plan",
+		"startDate": "2025-03-03",
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.POST("/api/v1/plans/1/schedule", nil)
+	suite.AssertStatusCode(recorder, 201)
+	var sessions []types.ScheduledSession
+	suite.GetResponseData(recorder, &sessions)
+	suite.Require().GreaterOrEqual(len(sessions), 3)
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{
+		"date": "2025-03-03",
+		"rpe":  11,
+		"sets": []interface{}{},
+	})
+	suite.AssertErrorResponse(recorder, 400, "Session RPE must be between 1 and 10")
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{
+		"date":     "2025-03-03",
+		"duration": "soon",
+		"sets":     []interface{}{},
+	})
+	suite.AssertErrorResponse(recorder, 400, "Invalid duration")
+
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[0].ID), map[string]interface{}{
+		"date":     "2025-03-03",
+		"rpe":      6,
+		"duration": "60 minutes",
+		"sets":     []interface{}{},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	var completion types.SessionCompletion
+	suite.GetResponseData(recorder, &completion)
+	suite.Require().NotNil(completion.Load)
+	suite.Equal(360.0, *completion.Load)
+
+	// Without a session RPE the sets' average rates the session
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[1].ID), map[string]interface{}{
+		"date":     "2025-03-05",
+		"duration": "30 minutes",
+		"sets": []map[string]interface{}{
+			{"exerciseVariationId": 4, "reps": 10, "rpe": 7},
+			{"exerciseVariationId": 4, "reps": 8, "rpe": 9},
+		},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &completion)
+	suite.Require().NotNil(completion.Load)
+	suite.Equal(240.0, *completion.Load)
+
+	// A session without a duration has no load
+	recorder = suite.POST(fmt.Sprintf("/api/v1/sessions/%d/complete", sessions[2].ID), map[string]interface{}{
+		"date": "2025-03-06",
+		"rpe":  5,
+		"sets": []interface{}{},
+	})
+	suite.AssertStatusCode(recorder, 200)
+	suite.GetResponseData(recorder, &completion)
+	suite.Nil(completion.Load)
+
+	recorder = suite.GET("/api/v1/analytics/training-load?userId=1&from=2025-03-03&to=2025-03-09")
+	suite.AssertStatusCode(recorder, 200)
+	var load types.TrainingLoad
+	suite.GetResponseData(recorder, &load)
+	suite.Require().Len(load.Days, 7)
+	suite.Equal(1, load.Unrated)
+	suite.False(load.Thresholds.Custom)
+	suite.Equal(360.0, load.Days[0].Load)
+	suite.Equal(600.0, load.Days[6].Acute)
+	suite.Equal(150.0, load.Days[6].Chronic)
+	suite.Require().NotNil(load.Days[6].ACWR)
+	suite.Equal(4.0, *load.Days[6].ACWR)
+	suite.Contains(load.Days[6].Flags, "acwr_high")
+
+	// Raising the ratio threshold clears the flag
+	recorder = suite.PUT("/api/v1/users/1/training-load-thresholds", map[string]interface{}{
+		"acwrHigh": 5, "acwrLow": 0.5, "monotony": 2, "strain": 6000,
+	})
+	suite.AssertStatusCode(recorder, 200)
+	recorder = suite.GET("/api/v1/users/1/training-load-thresholds")
+	var thresholds types.TrainingLoadThresholds
+	suite.GetResponseData(recorder, &thresholds)
+	suite.True(thresholds.Custom)
+	suite.Equal(5.0, thresholds.ACWRHigh)
+
+	recorder = suite.GET("/api/v1/analytics/training-load?userId=1&from=2025-03-03&to=2025-03-09")
+	suite.GetResponseData(recorder, &load)
+	suite.NotContains(load.Days[6].Flags, "acwr_high")
+
+	recorder = suite.PUT("/api/v1/users/1/training-load-thresholds", map[string]interface{}{
+		"acwrHigh": 1, "acwrLow": 1.2, "monotony": 2, "strain": 6000,
+	})
+	suite.AssertErrorResponse(recorder, 400, "Invalid thresholds")
+
+	recorder = suite.GET("/api/v1/analytics/training-load?from=2025-03-03")
+	suite.AssertErrorResponse(recorder, 400, "Missing required field: userId")
+	recorder = suite.GET("/api/v1/analytics/training-load?userId=1&from=2025-03-09&to=2025-03-03")
+	suite.AssertErrorResponse(recorder, 400, "from must not be after to")
+	recorder = suite.GET("/api/v1/analytics/training-load?userId=1&from=2024-01-01&to=2025-03-03")
+	suite.AssertErrorResponse(recorder, 400, "at most 366 days")
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"backend/internal/library"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleLibrary() *library.Library {
+	maxPercentage := 100.0
+	return &library.Library{
+		Version: library.FormatVersion,
+		ParameterTypes: []library.ParameterType{
+			{Name: "Edge Depth", DataType: "length", DefaultUnit: "mm"},
+			{Name: "Intensity", DataType: "percentage", DefaultUnit: "%", MaxValue: &maxPercentage},
+		},
+		Exercises: []library.Exercise{
+			{
+				Name:                  "Max Hangs",
+				Description:           "Short, heavy hangs, with a comma",
+				PrimaryMuscleGroups:   []string{"fingers", "forearms"},
+				SecondaryMuscleGroups: []string{},
+				Equipment:             []string{"hangboard"},
+				Laterality:            "bilateral",
+				Categories:            []string{"Finger strength"},
+				Variations: []library.Variation{
+					{Name: "Half crimp", Parameters: []library.Parameter{
+						{ParameterType: "Edge Depth", Locked: true},
+						{ParameterType: "Intensity"},
+					}},
+					{Name: "Open hand", Parameters: []library.Parameter{}},
+				},
+			},
+			{
+				Name:                  "Pistol Squat",
+				Description:           "",
+				PrimaryMuscleGroups:   []string{"quadriceps"},
+				SecondaryMuscleGroups: []string{"glutes"},
+				Equipment:             []string{},
+				Laterality:            "unilateral",
+				Categories:            []string{},
+				Variations:            []library.Variation{},
+			},
+		},
+	}
+}
+
+// stripLines clears CSV line numbers so parsed libraries compare equal to built ones
+func stripLines(lib *library.Library) {
+	for i := range lib.Exercises {
+		lib.Exercises[i].Line = 0
+	}
+}
+
+// TestLibraryRoundTrip tests that both formats read back what they wrote
+func TestLibraryRoundTrip(t *testing.T) {
+	for _, format := range []string{library.FormatJSON, library.FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := library.Write(&buffer, sampleLibrary(), format); err != nil {
+				t.Fatalf("Unexpected write error: %v", err)
+			}
+
+			parsed, err := library.Parse(&buffer, format)
+			if err != nil {
+				t.Fatalf("Unexpected parse error: %v", err)
+			}
+			stripLines(parsed)
+
+			if !reflect.DeepEqual(parsed, sampleLibrary()) {
+				t.Errorf("Round trip changed the library:\n got %+v\nwant %+v", parsed, sampleLibrary())
+			}
+		})
+	}
+}
+
+// TestLibraryParseCSVErrors tests that CSV problems are reported with their line number
+func TestLibraryParseCSVErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		csv      string
+		expected string
+	}{
+		{
+			name:     "UnknownColumn",
+			csv:      "exercise,colour\nPull-up,red\n",
+			expected: `line 1: unknown column "colour"`,
+		},
+		{
+			name:     "MissingExerciseName",
+			csv:      "exercise,variation\nPull-up,Wide\n,Narrow\n",
+			expected: "line 3: missing exercise name",
+		},
+		{
+			name:     "ConflictingExerciseRows",
+			csv:      "exercise,laterality\nPull-up,bilateral\nPull-up,unilateral\n",
+			expected: `line 3: laterality of exercise "Pull-up" differs from line 2`,
+		},
+		{
+			name:     "ConflictingParameterTypes",
+			csv:      "exercise,variation,parameter_type,data_type,default_unit\nA,x,Load,weight,kg\nB,y,Load,weight,lb\n",
+			expected: `line 3: parameter type "Load" differs from line 2`,
+		},
+		{
+			name:     "InvalidLocked",
+			csv:      "exercise,variation,parameter_type,locked\nA,x,Load,maybe\n",
+			expected: `line 2: invalid locked value "maybe"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := library.ParseCSV(strings.NewReader(tc.csv))
+			var parseErr *library.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a parse error, got %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+// TestLibraryParseJSONVersion tests that unversioned and newer libraries are rejected
+func TestLibraryParseJSONVersion(t *testing.T) {
+	if _, err := library.ParseJSON(strings.NewReader(`{"exercises": []}`)); err == nil {
+		t.Errorf("Expected a library without a version to be rejected")
+	}
+	if _, err := library.ParseJSON(strings.NewReader(`{"version": 99, "exercises": []}`)); err == nil {
+		t.Errorf("Expected a newer library version to be rejected")
+	}
+}
+
+// TestLibraryPlan tests conflict, duplicate and reference checks of an import plan
+func TestLibraryPlan(t *testing.T) {
+	lib := sampleLibrary()
+	lib.Exercises = append(lib.Exercises,
+		library.Exercise{Name: "max hangs", Line: 9},
+		library.Exercise{Name: "Campus Ladders", Variations: []library.Variation{
+			{Name: "1-3-5", Parameters: []library.Parameter{{ParameterType: "Rung Spacing"}}},
+		}},
+	)
+
+	existing := library.Existing{
+		Exercises: map[string]int64{"pistol squat": 2},
+		ParameterTypes: map[string]library.ExistingParameterType{
+			"edge depth": {ID: 7, ParameterType: library.ParameterType{Name: "Edge Depth", DataType: "length", DefaultUnit: "mm"}},
+		},
+		Categories: map[string]int64{},
+	}
+
+	plan := library.Plan(lib, existing, library.ConflictSkip)
+
+	if len(plan.Exercises) != 1 || plan.Exercises[0].Name != "Max Hangs" {
+		t.Fatalf("Expected only Max Hangs to be planned, got %+v", plan.Exercises)
+	}
+	if len(plan.ParameterTypes) != 1 || plan.ParameterTypes[0].Name != "Intensity" {
+		t.Errorf("Expected only Intensity to be created, got %+v", plan.ParameterTypes)
+	}
+	if !reflect.DeepEqual(plan.ReusedParameterTypes, []string{"Edge Depth"}) {
+		t.Errorf("Expected Edge Depth to be reused, got %v", plan.ReusedParameterTypes)
+	}
+	if !reflect.DeepEqual(plan.Categories, []string{"Finger strength"}) {
+		t.Errorf("Expected Finger strength to be created, got %v", plan.Categories)
+	}
+
+	kinds := make(map[string]int)
+	for _, issue := range plan.Issues {
+		kinds[issue.Kind]++
+	}
+	if kinds[library.IssueConflict] != 1 || kinds[library.IssueDuplicate] != 1 || kinds[library.IssueInvalid] != 1 {
+		t.Errorf("Expected one conflict, duplicate and invalid issue, got %+v", plan.Issues)
+	}
+	if !plan.Blocked() {
+		t.Errorf("Expected the unknown parameter type to block the import")
+	}
+
+	report := plan.Report(true)
+	if report.Valid || !report.DryRun || report.VariationsCreated != 2 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+
+	// Conflicts only block when asked to
+	lib.Exercises = lib.Exercises[:2]
+	if library.Plan(lib, existing, library.ConflictSkip).Blocked() {
+		t.Errorf("Expected skipped conflicts not to block the import")
+	}
+	if !library.Plan(lib, existing, library.ConflictFail).Blocked() {
+		t.Errorf("Expected conflicts to block the import with onConflict=fail")
+	}
+}
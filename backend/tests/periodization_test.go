@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"backend/internal/library"
+	"backend/internal/periodization"
+	"backend/internal/plandoc"
+	"strings"
+	"testing"
+)
+
+func periodizationConfig(model string) periodization.Config {
+	return periodization.Config{
+		Goal:            "strength",
+		Model:           model,
+		Weeks:           8,
+		SessionsPerWeek: 3,
+		DeloadEvery:     4,
+		Exercises: []periodization.Exercise{
+			{Exercise: "Squat", Variation: "Back", LoadParameter: "Weight", Load: 100},
+			{Exercise: "Pull-up", Variation: "Strict"},
+		},
+	}
+}
+
+// generatedLibrary defines the exercises periodizationConfig uses
+func generatedLibrary(doc *plandoc.Document) {
+	doc.ParameterTypes = []library.ParameterType{{Name: "Weight", DataType: "number", DefaultUnit: "kg"}}
+	doc.Exercises = []library.Exercise{
+		{Name: "Squat", Variations: []library.Variation{{Name: "Back", Parameters: []library.Parameter{{ParameterType: "Weight"}}}}},
+		{Name: "Pull-up", Variations: []library.Variation{{Name: "Strict", Parameters: []library.Parameter{}}}},
+	}
+}
+
+func setLoad(prescription plandoc.Prescription) float64 {
+	return prescription.SetScheme[0].Parameters[0].Value
+}
+
+// TestPeriodizationLinear tests that loads progress week by week, deload weeks cut volume
+// without progressing, and the plan is a valid document
+func TestPeriodizationLinear(t *testing.T) {
+	doc, err := periodization.Generate(periodizationConfig(periodization.ModelLinear))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	generatedLibrary(doc)
+	if errs := plandoc.Validate(doc); len(errs) > 0 {
+		t.Fatalf("Expected a valid document, got %v", errs)
+	}
+	if doc.Plan.Name != "8-week linear strength plan" || len(doc.Intervals) != 8 || len(doc.Groups) != 1 {
+		t.Fatalf("Unexpected plan %+v", doc)
+	}
+	if doc.Intervals[0].Groups[0].Frequency != 3 {
+		t.Errorf("Expected one group held 3 times a week, got %+v", doc.Intervals[0].Groups[0])
+	}
+
+	// Week 4 and 8 are deloads; week 5 continues from week 3
+	expected := []float64{100, 102.5, 105, 94.5, 107.5, 110.5, 113, 102}
+	for i, want := range expected {
+		squat := doc.Intervals[i].Groups[0].Prescriptions[0]
+		if got := setLoad(squat); got != want {
+			t.Errorf("Week %d: expected a load of %v, got %v", i+1, want, got)
+		}
+	}
+	deload := doc.Intervals[3]
+	if !deload.Deload || !strings.Contains(deload.Name, "deload") || deload.Groups[0].Prescriptions[0].Sets != 3 || *deload.Groups[0].Prescriptions[0].RPE != 6 {
+		t.Errorf("Expected week 4 to be a deload of 3 sets at RPE 6, got %+v", deload)
+	}
+
+	// Without a load, RPE climbs to 9 and then reps take over
+	pullUps := []int32{}
+	for _, interval := range doc.Intervals {
+		prescription := interval.Groups[0].Prescriptions[1]
+		pullUps = append(pullUps, *prescription.RPE*100+*prescription.Reps)
+	}
+	if got := pullUps[:3]; got[0] != 805 || got[1] != 805 || got[2] != 905 {
+		t.Errorf("Expected RPE to progress before reps, got %v", pullUps)
+	}
+	if pullUps[6] != 908 {
+		t.Errorf("Expected reps to progress once RPE reaches 9, got %v", pullUps)
+	}
+}
+
+// TestPeriodizationModels tests the sessions of undulating plans and the phases of block
+// plans
+func TestPeriodizationModels(t *testing.T) {
+	doc, err := periodization.Generate(periodizationConfig(periodization.ModelUndulating))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(doc.Groups) != 3 || doc.Groups[0].Name != "Day 1: Heavy" || doc.Groups[1].Name != "Day 2: Light" {
+		t.Fatalf("Expected heavy, light and moderate days, got %+v", doc.Groups)
+	}
+	week := doc.Intervals[0].Groups
+	if *week[0].Prescriptions[0].Reps != 3 || *week[1].Prescriptions[0].Reps != 7 || *week[2].Prescriptions[0].Reps != 5 {
+		t.Errorf("Expected the days to undulate in reps, got %+v", week)
+	}
+
+	config := periodizationConfig(periodization.ModelBlock)
+	config.Weeks, config.DeloadEvery = 10, 0
+	doc, err = periodization.Generate(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	phases := []string{}
+	for _, interval := range doc.Intervals {
+		phases = append(phases, interval.Name[strings.Index(interval.Name, ": ")+2:])
+	}
+	expected := "Accumulation Accumulation Accumulation Accumulation Transmutation Transmutation Transmutation Transmutation Realization Realization"
+	if strings.Join(phases, " ") != expected {
+		t.Errorf("Unexpected blocks %v", phases)
+	}
+	if setLoad(doc.Intervals[4].Groups[0].Prescriptions[0]) != 100 {
+		t.Errorf("Expected progression to restart with each block, got %+v", doc.Intervals[4])
+	}
+}
+
+// TestPeriodizationValidate tests that settings the models cannot program are rejected
+func TestPeriodizationValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		change   func(*periodization.Config)
+		expected string
+	}{
+		{name: "Goal", change: func(c *periodization.Config) { c.Goal = "speed" }, expected: `unknown goal "speed"`},
+		{name: "Model", change: func(c *periodization.Config) { c.Model = "wave" }, expected: `unknown periodization model "wave"`},
+		{name: "Weeks", change: func(c *periodization.Config) { c.Weeks = 0 }, expected: "weeks must be between 1 and 52"},
+		{name: "Deload", change: func(c *periodization.Config) { c.DeloadEvery = 1 }, expected: "every 2 weeks at most"},
+		{name: "Undulating", change: func(c *periodization.Config) { c.Model, c.SessionsPerWeek = periodization.ModelUndulating, 1 }, expected: "at least 2 sessions"},
+		{name: "Block", change: func(c *periodization.Config) { c.Model, c.Weeks = periodization.ModelBlock, 2 }, expected: "at least 3 training weeks"},
+		{name: "Load", change: func(c *periodization.Config) { c.Exercises[1].Load = 20 }, expected: `"Strict" needs both a load parameter and a load`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := periodizationConfig(periodization.ModelLinear)
+			tc.change(&config)
+			err := periodization.Validate(config)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package tests
+
+import (
+	"backend/internal/library"
+	"backend/internal/plandoc"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func samplePlanDocument() *plandoc.Document {
+	reps := int32(5)
+	rest := "3 minutes"
+	return &plandoc.Document{
+		Format:  plandoc.Format,
+		Version: plandoc.FormatVersion,
+		Plan:    plandoc.Plan{Name: "Strength block", Description: "Four weeks"},
+		ParameterTypes: []library.ParameterType{
+			{Name: "Weight", DataType: "number", DefaultUnit: "kg"},
+		},
+		Exercises: []library.Exercise{
+			{Name: "Squat", Variations: []library.Variation{
+				{Name: "Back", Parameters: []library.Parameter{{ParameterType: "Weight"}}},
+			}},
+		},
+		Groups: []plandoc.Group{{Key: "group-1", Name: "Lower"}},
+		Intervals: []plandoc.Interval{
+			{Name: "Base", Duration: "1 week", Order: 1, Groups: []plandoc.IntervalGroup{
+				{Group: "group-1", Frequency: 2, Prescriptions: []plandoc.Prescription{
+					{Exercise: "Squat", Variation: "Back", Sets: 3, Reps: &reps, Rest: &rest},
+				}},
+			}},
+		},
+	}
+}
+
+// TestPlanDocumentRoundTrip tests that a written document parses back unchanged
+func TestPlanDocumentRoundTrip(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := plandoc.Write(&buffer, samplePlanDocument()); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+
+	parsed, err := plandoc.Parse(&buffer)
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+	if !reflect.DeepEqual(parsed, samplePlanDocument()) {
+		t.Errorf("Round trip changed the document:\n got %+v\nwant %+v", parsed, samplePlanDocument())
+	}
+	if errs := plandoc.Validate(parsed); len(errs) > 0 {
+		t.Errorf("Expected a valid document, got %v", errs)
+	}
+}
+
+// TestPlanDocumentParse tests that foreign and newer documents are rejected
+func TestPlanDocumentParse(t *testing.T) {
+	for _, body := range []string{
+		`{"format": "other", "version": 1}`,
+		`{"format": "rocket-plan"}`,
+		`{"format": "rocket-plan", "version": 99}`,
+		`{"format": "rocket-plan", "version": 1, "unknown": true}`,
+	} {
+		if _, err := plandoc.Parse(strings.NewReader(body)); err == nil {
+			t.Errorf("Expected %s to be rejected", body)
+		}
+	}
+}
+
+// TestPlanDocumentValidate tests that broken references and values are all reported
+func TestPlanDocumentValidate(t *testing.T) {
+	doc := samplePlanDocument()
+	doc.Exercises[0].Variations[0].Parameters[0].ParameterType = "Load"
+	doc.Intervals[0].Duration = "a fortnight"
+	doc.Intervals[0].Groups = append(doc.Intervals[0].Groups,
+		plandoc.IntervalGroup{Group: "group-9", Frequency: 1},
+		plandoc.IntervalGroup{Group: "group-1", Frequency: 0, Prescriptions: []plandoc.Prescription{
+			{Exercise: "Squat", Variation: "Front", Sets: 0},
+			{Exercise: "Deadlift", Variation: "", Sets: 1},
+		}},
+	)
+
+	expected := []string{
+		`undefined parameter type "Load"`,
+		`invalid duration "a fortnight"`,
+		`unknown group "group-9"`,
+		`assigns group "group-1" more than once`,
+		`frequency below 1`,
+		`undefined variation "Front"`,
+		`fewer than 1 set`,
+		`undefined exercise "Deadlift"`,
+	}
+
+	errs := plandoc.Validate(doc)
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for i, message := range expected {
+		if !strings.Contains(errs[i].Error(), message) {
+			t.Errorf("Expected error %d to contain %q, got %q", i, message, errs[i].Error())
+		}
+	}
+}
+
+// TestPlanDocumentValidatePinnedDays tests that pinned days are known weekdays with room
+// for the group's frequency
+func TestPlanDocumentValidatePinnedDays(t *testing.T) {
+	doc := samplePlanDocument()
+	doc.Intervals[0].Groups[0].PinnedDays = []string{"Tuesday"}
+	errs := plandoc.Validate(doc)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "frequency above its 1 pinned days") {
+		t.Errorf("Expected the frequency to be checked against the pinned days, got %v", errs)
+	}
+
+	doc.Intervals[0].Groups[0].PinnedDays = []string{"tuesday", "funday"}
+	errs = plandoc.Validate(doc)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `unknown weekday "funday"`) {
+		t.Errorf("Expected the unknown weekday to be reported, got %v", errs)
+	}
+
+	doc.Intervals[0].Groups[0].PinnedDays = []string{"tuesday", "friday"}
+	if errs := plandoc.Validate(doc); len(errs) != 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+// TestPlanDocumentValidateBlocks tests that supersets and circuits link existing
+// prescriptions that follow each other
+func TestPlanDocumentValidateBlocks(t *testing.T) {
+	doc := samplePlanDocument()
+	group := &doc.Intervals[0].Groups[0]
+	group.Prescriptions = append(group.Prescriptions, group.Prescriptions[0], group.Prescriptions[0])
+
+	testCases := []struct {
+		name     string
+		block    plandoc.Block
+		expected string
+	}{
+		{name: "UnknownKind", block: plandoc.Block{Kind: "giant set", Prescriptions: []int{0, 1}}, expected: `unknown kind "giant set"`},
+		{name: "TooFew", block: plandoc.Block{Kind: "superset", Prescriptions: []int{0}}, expected: "fewer than 2 prescriptions"},
+		{name: "Missing", block: plandoc.Block{Kind: "superset", Prescriptions: []int{2, 3}}, expected: "prescription 3 of group \"group-1\", which does not exist"},
+		{name: "Apart", block: plandoc.Block{Kind: "circuit", Prescriptions: []int{0, 2}}, expected: "do not follow each other"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			group.Blocks = []plandoc.Block{tc.block}
+			errs := plandoc.Validate(doc)
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tc.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tc.expected, errs)
+			}
+		})
+	}
+
+	rest := "2 minutes"
+	group.Blocks = []plandoc.Block{{Kind: "circuit", Rest: &rest, Prescriptions: []int{1, 2}}}
+	if errs := plandoc.Validate(doc); len(errs) != 0 {
+		t.Errorf("Unexpected errors: %v", errs)
+	}
+}
+
+// TestPlanDocumentValidateSetScheme tests that a set scheme matches the sets and only uses
+// the variation's parameters
+func TestPlanDocumentValidateSetScheme(t *testing.T) {
+	doc := samplePlanDocument()
+	prescription := &doc.Intervals[0].Groups[0].Prescriptions[0]
+	prescription.SetScheme = []plandoc.Set{
+		{Parameters: []plandoc.SetParameter{{ParameterType: "weight", Value: 100}}},
+		{Rest: stringPointer("forever")},
+	}
+
+	expected := []string{
+		"with 3 sets but a set scheme of 2",
+		`set 2 of "Squat" with an invalid duration "forever"`,
+	}
+	errs := plandoc.Validate(doc)
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %v", len(expected), errs)
+	}
+	for i, message := range expected {
+		if !strings.Contains(errs[i].Error(), message) {
+			t.Errorf("Expected error %d to contain %q, got %q", i, message, errs[i].Error())
+		}
+	}
+
+	prescription.Sets = 2
+	prescription.SetScheme[1] = plandoc.Set{Parameters: []plandoc.SetParameter{{ParameterType: "Tempo", Value: 3}}}
+	errs = plandoc.Validate(doc)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `"Tempo", which is not a parameter of the variation`) {
+		t.Errorf("Expected the unknown parameter to be reported, got %v", errs)
+	}
+}
+
+// TestPlanDocumentSchema tests that the published schema is valid JSON describing the document
+func TestPlanDocumentSchema(t *testing.T) {
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(plandoc.Schema, &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	var document map[string]json.RawMessage
+	encoded, _ := json.Marshal(samplePlanDocument())
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name := range document {
+		if _, ok := schema.Properties[name]; !ok {
+			t.Errorf("Schema does not describe %q", name)
+		}
+	}
+	for _, name := range schema.Required {
+		if _, ok := document[name]; !ok {
+			t.Errorf("Document is missing required %q", name)
+		}
+	}
+}
+
+// TestPlanDocumentDiff tests that the diff names the intervals, groups and prescriptions
+// that changed between two documents
+func TestPlanDocumentDiff(t *testing.T) {
+	from := samplePlanDocument()
+	if changes := plandoc.Diff(from, samplePlanDocument()); !changes.Empty() {
+		t.Errorf("Expected no changes between equal documents, got %+v", changes)
+	}
+
+	to := samplePlanDocument()
+	to.Plan.Name = "Strength block 2"
+	reps := int32(8)
+	to.Intervals[0].Groups[0].Prescriptions[0].Reps = &reps
+	to.Intervals[0].Groups[0].Prescriptions = append(to.Intervals[0].Groups[0].Prescriptions,
+		plandoc.Prescription{Exercise: "Squat", Variation: "Back", Sets: 1})
+	to.Intervals = append(to.Intervals, plandoc.Interval{Name: "Peak", Duration: "1 week", Order: 2, Groups: []plandoc.IntervalGroup{}})
+
+	changes := plandoc.Diff(from, to)
+	if len(changes.Plan) != 1 || changes.Plan[0].Field != "name" || changes.Plan[0].To != "Strength block 2" {
+		t.Errorf("Expected the plan name to change, got %+v", changes.Plan)
+	}
+	if len(changes.IntervalsAdded) != 1 || changes.IntervalsAdded[0].Name != "Peak" {
+		t.Errorf("Expected the Peak interval to be added, got %+v", changes.IntervalsAdded)
+	}
+	if len(changes.IntervalsChanged) != 1 {
+		t.Fatalf("Expected one changed interval, got %+v", changes.IntervalsChanged)
+	}
+	interval := changes.IntervalsChanged[0]
+	if len(interval.PrescriptionsAdded) != 1 || interval.PrescriptionsAdded[0].Group != "Lower" {
+		t.Errorf("Expected the second squat to be added to Lower, got %+v", interval.PrescriptionsAdded)
+	}
+	if len(interval.PrescriptionsChanged) != 1 {
+		t.Fatalf("Expected the first squat to change, got %+v", interval.PrescriptionsChanged)
+	}
+	expected := []plandoc.FieldChange{{Field: "reps", From: int32(5), To: int32(8)}}
+	if !reflect.DeepEqual(interval.PrescriptionsChanged[0].Fields, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, interval.PrescriptionsChanged[0].Fields)
+	}
+
+	// Removing the interval and the group is reported the other way round
+	back := plandoc.Diff(to, from)
+	if len(back.IntervalsRemoved) != 1 || len(back.IntervalsChanged[0].PrescriptionsRemoved) != 1 {
+		t.Errorf("Expected the interval and prescription to be removed, got %+v", back)
+	}
+}
+
+// TestPlanDocumentDiffOrder tests that reordering groups and prescriptions is a change
+func TestPlanDocumentDiffOrder(t *testing.T) {
+	from := samplePlanDocument()
+	from.Groups = append(from.Groups, plandoc.Group{Key: "group-2", Name: "Upper"})
+	from.Intervals[0].Groups = append(from.Intervals[0].Groups, plandoc.IntervalGroup{Group: "group-2", Frequency: 1})
+	from.Intervals[0].Groups[0].Prescriptions = append(from.Intervals[0].Groups[0].Prescriptions,
+		plandoc.Prescription{Exercise: "Squat", Variation: "Front", Sets: 2})
+
+	to := samplePlanDocument()
+	to.Groups = append([]plandoc.Group{{Key: "group-2", Name: "Upper"}}, to.Groups...)
+	lower := to.Intervals[0].Groups[0]
+	lower.Prescriptions = []plandoc.Prescription{{Exercise: "Squat", Variation: "Front", Sets: 2}, lower.Prescriptions[0]}
+	to.Intervals[0].Groups = []plandoc.IntervalGroup{{Group: "group-2", Frequency: 1}, lower}
+
+	changes := plandoc.Diff(from, to)
+	if len(changes.IntervalsChanged) != 1 {
+		t.Fatalf("Expected one changed interval, got %+v", changes)
+	}
+	interval := changes.IntervalsChanged[0]
+	if len(interval.Fields) != 1 || interval.Fields[0].Field != "groupOrder" {
+		t.Errorf("Expected the group order to change, got %+v", interval.Fields)
+	}
+	if len(interval.GroupsChanged) != 1 || interval.GroupsChanged[0].Fields[0].Field != "prescriptionOrder" {
+		t.Errorf("Expected the prescription order of Lower to change, got %+v", interval.GroupsChanged)
+	}
+	if len(interval.PrescriptionsAdded) != 0 || len(interval.PrescriptionsRemoved) != 0 || len(interval.PrescriptionsChanged) != 0 {
+		t.Errorf("Expected reordered prescriptions to match, got %+v", interval)
+	}
+}
+
+// TestPlanDocumentDiffIntervalIds tests that intervals recorded with their IDs are
+// matched by ID, so removing an interval does not shift the ones after it
+func TestPlanDocumentDiffIntervalIds(t *testing.T) {
+	from := samplePlanDocument()
+	from.Intervals[0].ID = 10
+	from.Intervals = append(from.Intervals, plandoc.Interval{ID: 11, Name: "Peak", Duration: "1 week", Order: 2, Groups: []plandoc.IntervalGroup{}})
+
+	to := samplePlanDocument()
+	to.Intervals = []plandoc.Interval{{ID: 11, Name: "Peak", Duration: "1 week", Order: 1, Groups: []plandoc.IntervalGroup{}}}
+
+	changes := plandoc.Diff(from, to)
+	if len(changes.IntervalsRemoved) != 1 || changes.IntervalsRemoved[0].ID != 10 {
+		t.Errorf("Expected interval 10 to be removed, got %+v", changes.IntervalsRemoved)
+	}
+	if len(changes.IntervalsAdded) != 0 {
+		t.Errorf("Expected no interval to be added, got %+v", changes.IntervalsAdded)
+	}
+	if len(changes.IntervalsChanged) != 1 {
+		t.Fatalf("Expected one changed interval, got %+v", changes.IntervalsChanged)
+	}
+	expected := []plandoc.FieldChange{{Field: "order", From: int32(2), To: int32(1)}}
+	if interval := changes.IntervalsChanged[0]; interval.ID != 11 || !reflect.DeepEqual(interval.Fields, expected) {
+		t.Errorf("Expected interval 11 to move to order 1, got %+v", interval)
+	}
+
+	// Versions recorded without IDs are matched by order
+	to.Intervals[0].ID = 0
+	changes = plandoc.Diff(from, to)
+	if len(changes.IntervalsRemoved) != 1 || changes.IntervalsRemoved[0].Order != 2 {
+		t.Errorf("Expected the second interval to be removed, got %+v", changes.IntervalsRemoved)
+	}
+}
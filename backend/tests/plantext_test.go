@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"backend/internal/library"
+	"backend/internal/plandoc"
+	"backend/internal/plantext"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+// TestPlanTextRoundTrip tests that printed plans parse back into the same document
+func TestPlanTextRoundTrip(t *testing.T) {
+	doc := samplePlanDocument()
+	doc.Plan.IsTemplate = true
+	doc.Exercises[0].PrimaryMuscleGroups = []string{"quadriceps", "glutes"}
+	doc.Exercises[0].SecondaryMuscleGroups = []string{}
+	doc.Exercises[0].Equipment = []string{}
+	doc.Exercises[0].Categories = []string{"Lower body"}
+	doc.Exercises[0].Laterality = "bilateral"
+	doc.Exercises[0].Variations = append(doc.Exercises[0].Variations, library.Variation{Name: "", Parameters: []library.Parameter{{ParameterType: "Weight", Locked: true}}})
+	doc.Groups[0].Description = "Legs, \"heavy\""
+	doc.Intervals = append(doc.Intervals, plandoc.Interval{
+		Duration: "1 week 3 days",
+		Order:    2,
+		Deload:   true,
+		Groups: []plandoc.IntervalGroup{{Group: "group-1", Frequency: 1, PinnedDays: []string{"monday", "thursday"}, Prescriptions: []plandoc.Prescription{
+			{Exercise: "Squat", Sets: 1, RPE: int32Pointer(9), Duration: stringPointer("30 seconds"), Rest: stringPointer("1 minute 30 seconds")},
+			{Exercise: "Squat", Variation: "Back", Sets: 6, Reps: int32Pointer(1), Duration: stringPointer("10 seconds"),
+				SubReps: int32Pointer(6), SubRepWorkDuration: stringPointer("7 seconds"), SubRepRestDuration: stringPointer("3 seconds")},
+			{Exercise: "Squat", Variation: "Back", Sets: 2},
+			{Exercise: "Squat", Variation: "Back", Sets: 4, Rest: stringPointer("3 minutes"), SetScheme: []plandoc.Set{
+				{Reps: int32Pointer(8), RPE: int32Pointer(7), Parameters: []plandoc.SetParameter{{ParameterType: "Weight", Value: 80}}},
+				{Reps: int32Pointer(5), Duration: stringPointer("20 seconds"), Parameters: []plandoc.SetParameter{{ParameterType: "Weight", Value: 92.5, Percentage: true}}},
+				{Duration: stringPointer("30 seconds"), Rest: stringPointer("4 minutes")},
+				{RPE: int32Pointer(10)},
+			}},
+		}, Blocks: []plandoc.Block{{Kind: "superset", Rest: stringPointer("2 minutes"), Prescriptions: []int{1, 2}}}}},
+	})
+
+	var buffer bytes.Buffer
+	if err := plantext.Write(&buffer, doc); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+	text := buffer.String()
+
+	parsed, err := plantext.Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v\n%s", err, text)
+	}
+	for i := range parsed.Exercises {
+		parsed.Exercises[i].Line = 0
+	}
+	if !reflect.DeepEqual(parsed, doc) {
+		t.Errorf("Round trip changed the plan:\n got %+v\nwant %+v\n%s", parsed, doc, text)
+	}
+
+	// Printing is stable, so text plans diff cleanly
+	var again bytes.Buffer
+	if err := plantext.Write(&again, parsed); err != nil {
+		t.Fatalf("Unexpected write error: %v", err)
+	}
+	if again.String() != text {
+		t.Errorf("Printing the parsed plan changed the text:\n%s\n%s", text, again.String())
+	}
+}
+
+// TestPlanTextParse tests the prescription shorthand and undeclared exercises
+func TestPlanTextParse(t *testing.T) {
+	text := `# Hangboard block
+plan "Fingers"
+
+group hangs "Hangboard"
+
+interval 1 "Base": 2 weeks
+  group hangs x3 on mon, wed, Friday
+    "Max Hangs" / "Half crimp": 5x10 seconds @RPE 8, rest 3 minutes
+    Pull-ups: 3x6, rest 2 minutes   # bodyweight
+    circuit
+      "Max Hangs": 1x7 seconds
+      Circuit: 3 sets
+`
+	doc, err := plantext.Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Unexpected parse error: %v", err)
+	}
+
+	if len(doc.Exercises) != 3 || doc.Exercises[0].Name != "Max Hangs" || doc.Exercises[0].Line != 8 {
+		t.Fatalf("Expected undeclared exercises to be added, got %+v", doc.Exercises)
+	}
+
+	intervalGroup := doc.Intervals[0].Groups[0]
+	if intervalGroup.Frequency != 3 {
+		t.Errorf("Expected frequency 3, got %d", intervalGroup.Frequency)
+	}
+	if !reflect.DeepEqual(intervalGroup.PinnedDays, []string{"monday", "wednesday", "friday"}) {
+		t.Errorf("Expected pinned days to be read, got %v", intervalGroup.PinnedDays)
+	}
+
+	expected := plandoc.Prescription{Exercise: "Max Hangs", Variation: "Half crimp", Sets: 5, RPE: int32Pointer(8),
+		Duration: stringPointer("10 seconds"), Rest: stringPointer("3 minutes")}
+	if !reflect.DeepEqual(intervalGroup.Prescriptions[0], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, intervalGroup.Prescriptions[0])
+	}
+	if got := plantext.FormatPrescription(intervalGroup.Prescriptions[1]); got != `"Pull-ups": 3x6, rest 2 minutes` {
+		t.Errorf("Unexpected prescription %s", got)
+	}
+
+	// An exercise may share its name with a block kind
+	if len(intervalGroup.Prescriptions) != 4 || intervalGroup.Prescriptions[3].Exercise != "Circuit" {
+		t.Fatalf("Expected the circuit's prescriptions to be read, got %+v", intervalGroup.Prescriptions)
+	}
+	expectedBlocks := []plandoc.Block{{Kind: "circuit", Prescriptions: []int{2, 3}}}
+	if !reflect.DeepEqual(intervalGroup.Blocks, expectedBlocks) {
+		t.Errorf("Expected %+v, got %+v", expectedBlocks, intervalGroup.Blocks)
+	}
+}
+
+// TestPlanTextErrors tests that problems are reported with their line number
+func TestPlanTextErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "MissingPlan",
+			text:     "group a \"A\"\n",
+			expected: "missing plan statement",
+		},
+		{
+			name:     "UnknownStatement",
+			text:     "plan \"P\"\nweek 1\n",
+			expected: `line 2: unknown statement "week", expected plan, parameter, exercise, group or interval`,
+		},
+		{
+			name:     "InvalidDuration",
+			text:     "plan \"P\"\ninterval 1: a fortnight\n",
+			expected: `line 2: invalid duration "a fortnight", expected a combination of weeks, days, hours, minutes and seconds`,
+		},
+		{
+			name:     "UnknownGroup",
+			text:     "plan \"P\"\ninterval 1: 1 week\n  group legs x2\n",
+			expected: `line 3: unknown group "legs"`,
+		},
+		{
+			name:     "UnknownWeekday",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a x1 on someday\n",
+			expected: `line 4: unknown weekday "someday", expected e.g. mon or monday`,
+		},
+		{
+			name:     "ShortSuperset",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    superset, rest 1 minute\n      Squat: 3x5\n",
+			expected: "line 5: a superset needs at least two prescriptions",
+		},
+		{
+			name:     "InvalidBlockProperty",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    circuit, rounds 3\n      Squat: 3x5\n      Lunge: 3x5\n",
+			expected: `line 5: unexpected "rounds 3", expected rest followed by a duration`,
+		},
+		{
+			name:     "SetLinesMismatch",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    Squat: 3 sets\n      set 5\n      set 3\n",
+			expected: "line 5: 3 sets are prescribed but 2 set lines follow",
+		},
+		{
+			name:     "InvalidSetProperty",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    Squat: 1 set\n      set 5, tempo 3\n",
+			expected: `line 6: unexpected "tempo 3", expected rest or duration followed by a duration, or a quoted parameter and its value`,
+		},
+		{
+			name:     "InvalidSets",
+			text:     "plan \"P\"\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    Squat: lots\n",
+			expected: `line 5: invalid sets "lots", expected e.g. 3x6, 3x30 seconds or 3 sets`,
+		},
+		{
+			name:     "UnknownVariation",
+			text:     "plan \"P\"\nexercise Squat\n  variation Back\ngroup a \"A\"\ninterval 1: 1 week\n  group a\n    Squat / Front: 3x5\n",
+			expected: `line 7: exercise "Squat" has no variation "Front"`,
+		},
+		{
+			name:     "UndefinedParameterType",
+			text:     "plan \"P\"\nexercise Squat\n  variation Back: Weight\n",
+			expected: `line 3: undefined parameter type "Weight", parameter types must be defined before they are used`,
+		},
+		{
+			name:     "DuplicateOrder",
+			text:     "plan \"P\"\ninterval 1: 1 week\ninterval 1: 2 weeks\n",
+			expected: "line 3: interval order 1 is already used on line 2",
+		},
+		{
+			name:     "UnterminatedString",
+			text:     "plan \"P\n",
+			expected: "line 1: unterminated string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := plantext.Parse(strings.NewReader(tc.text))
+			var parseErr *library.ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a parse error, got %v", err)
+			}
+			if err.Error() != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
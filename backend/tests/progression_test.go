@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"backend/internal/progression"
+	"backend/internal/types"
+	"backend/internal/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+func float64Pointer(v float64) *float64 {
+	return &v
+}
+
+// TestProgressionApply tests that later steps take over once earlier ones reach their limit
+func TestProgressionApply(t *testing.T) {
+	// +1 rep until 8, then +5% weight
+	steps := []progression.Step{
+		{Target: progression.TargetReps, Amount: 1, Unit: progression.UnitAbsolute, Until: float64Pointer(8)},
+		{Target: progression.TargetParameter, ParameterTypeID: 1, Amount: 5, Unit: progression.UnitPercent},
+	}
+	values := progression.Values{progression.TargetReps: 6, progression.Key(progression.TargetParameter, 1): 100}
+
+	expected := []struct{ reps, weight float64 }{{6, 100}, {7, 100}, {8, 100}, {8, 105}, {8, 110.25}}
+	for intervals, want := range expected {
+		got := progression.Apply(steps, values, intervals)
+		if got[progression.TargetReps] != want.reps || got["parameter:1"] != want.weight {
+			t.Errorf("After %d intervals expected %v reps at %v, got %v", intervals, want.reps, want.weight, got)
+		}
+	}
+	if values[progression.TargetReps] != 6 {
+		t.Errorf("Expected the input values to be left alone, got %v", values)
+	}
+
+	// Limits clamp, and missing values are skipped
+	clamped := progression.Apply([]progression.Step{
+		{Target: progression.TargetRPE, Amount: 1, Unit: progression.UnitAbsolute},
+		{Target: progression.TargetSets, Amount: -2, Unit: progression.UnitAbsolute, Until: float64Pointer(3)},
+	}, progression.Values{progression.TargetSets: 6}, 3)
+	if clamped[progression.TargetSets] != 3 {
+		t.Errorf("Expected sets to stop at 3, got %v", clamped)
+	}
+}
+
+// TestProgressionPrescription tests that RPE rounds to whole numbers and that each set of a
+// scheme progresses its own values
+func TestProgressionPrescription(t *testing.T) {
+	rest, _ := utils.StringToInterval("2 minutes")
+	restInterval := types.NewPostgreSQLInterval(rest)
+	prescription := types.IntervalExercisePrescription{
+		Sets: 2,
+		Reps: int32Pointer(5),
+		RPE:  int32Pointer(7),
+		Rest: &restInterval,
+		SetScheme: []types.PrescriptionSet{
+			{SetNumber: 1, Parameters: []types.PrescriptionSetParameter{{ParameterTypeId: 1, Value: 100}}},
+			{SetNumber: 2, Reps: int32Pointer(3), Parameters: []types.PrescriptionSetParameter{{ParameterTypeId: 1, Value: 102.5}}},
+		},
+	}
+
+	rpe := []progression.Step{{Target: progression.TargetRPE, Amount: 0.5, Unit: progression.UnitAbsolute}}
+	for intervals, want := range []int32{7, 7, 8, 8, 9} {
+		if got := progression.Prescription(prescription, rpe, intervals); *got.RPE != want {
+			t.Errorf("After %d intervals expected RPE %d, got %d", intervals, want, *got.RPE)
+		}
+	}
+
+	load := []progression.Step{
+		{Target: progression.TargetParameter, ParameterTypeID: 1, Amount: 2.5, Unit: progression.UnitAbsolute},
+		{Target: progression.TargetSets, Amount: 1, Unit: progression.UnitAbsolute},
+	}
+	got := progression.Prescription(prescription, load, 2)
+	if got.Sets != 2 || *got.Reps != 5 {
+		t.Errorf("Expected a set scheme to keep the sets and reps, got %+v", got)
+	}
+	if got.SetScheme[0].Parameters[0].Value != 105 || got.SetScheme[1].Parameters[0].Value != 107.5 {
+		t.Errorf("Expected each set to gain 5 kg, got %+v", got.SetScheme)
+	}
+	if got.SetScheme[0].Reps != nil || *got.SetScheme[1].Reps != 3 {
+		t.Errorf("Expected only explicit set values to be written, got %+v", got.SetScheme)
+	}
+	if prescription.SetScheme[0].Parameters[0].Value != 100 {
+		t.Errorf("Expected the source prescription to be left alone")
+	}
+
+	duration := []progression.Step{{Target: progression.TargetDuration, Amount: 10, Unit: progression.UnitPercent}}
+	prescription.Duration = &restInterval
+	got = progression.Prescription(prescription, duration, 1)
+	if utils.IntervalToDuration(got.Duration.Interval) != 132*time.Second {
+		t.Errorf("Expected the duration to grow by 10%%, got %v", got.Duration)
+	}
+}
+
+// TestProgressionDeload tests that deloads cut sets, reps, RPE and the chosen loads,
+// keeping at least one set and rep
+func TestProgressionDeload(t *testing.T) {
+	prescription := types.IntervalExercisePrescription{
+		Sets: 5,
+		Reps: int32Pointer(10),
+		RPE:  int32Pointer(8),
+	}
+	reduction := progression.Reduction{Sets: 40, Reps: 20, Load: 10, RPE: 2}
+	got := progression.Deload(prescription, reduction, func(int64) bool { return true })
+	if got.Sets != 3 || *got.Reps != 8 || *got.RPE != 6 {
+		t.Errorf("Expected 3x8 at RPE 6, got %d x %d at RPE %d", got.Sets, *got.Reps, *got.RPE)
+	}
+	if *prescription.Reps != 10 {
+		t.Errorf("Expected the source prescription to be left alone")
+	}
+
+	got = progression.Deload(types.IntervalExercisePrescription{Sets: 1, Reps: int32Pointer(1), RPE: int32Pointer(2)}, progression.Reduction{Sets: 90, Reps: 90, RPE: 5}, nil)
+	if got.Sets != 1 || *got.Reps != 1 || *got.RPE != 1 {
+		t.Errorf("Expected at least one set and rep at RPE 1, got %+v", got)
+	}
+
+	// A set scheme keeps its first sets and only the load parameter is cut
+	prescription.SetScheme = []types.PrescriptionSet{
+		{SetNumber: 1, Reps: int32Pointer(5), Parameters: []types.PrescriptionSetParameter{{ParameterTypeId: 1, Value: 100}, {ParameterTypeId: 2, Value: 20}}},
+		{SetNumber: 2, Parameters: []types.PrescriptionSetParameter{{ParameterTypeId: 1, Value: 105}}},
+		{SetNumber: 3, Parameters: []types.PrescriptionSetParameter{{ParameterTypeId: 1, Value: 110}}},
+	}
+	got = progression.Deload(prescription, reduction, func(id int64) bool { return id == 1 })
+	if got.Sets != 2 || len(got.SetScheme) != 2 {
+		t.Fatalf("Expected the first 2 sets, got %+v", got.SetScheme)
+	}
+	first := got.SetScheme[0]
+	if *first.Reps != 4 || first.Parameters[0].Value != 90 || first.Parameters[1].Value != 20 {
+		t.Errorf("Expected 4 reps at 90 with the other parameter unchanged, got %+v", first)
+	}
+	if got.SetScheme[1].Reps != nil || got.SetScheme[1].Parameters[0].Value != 94.5 {
+		t.Errorf("Expected only explicit set values to be written, got %+v", got.SetScheme[1])
+	}
+	if prescription.SetScheme[0].Parameters[0].Value != 100 {
+		t.Errorf("Expected the source set scheme to be left alone")
+	}
+
+	if err := progression.ValidateReduction(progression.Reduction{Sets: 100}); err == nil {
+		t.Errorf("Expected removing every set to be rejected")
+	}
+	if err := progression.ValidateReduction(progression.DefaultDeload); err != nil {
+		t.Errorf("Expected the default deload to be valid, got %v", err)
+	}
+}
+
+// TestProgressionValidate tests that broken steps are rejected
+func TestProgressionValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		steps    []progression.Step
+		expected string
+	}{
+		{name: "Empty", expected: "at least one step"},
+		{name: "UnknownTarget", steps: []progression.Step{{Target: "tempo", Amount: 1, Unit: progression.UnitAbsolute}}, expected: `unknown target "tempo"`},
+		{name: "UnknownUnit", steps: []progression.Step{{Target: progression.TargetReps, Amount: 1, Unit: "kg"}}, expected: `unknown unit "kg"`},
+		{name: "MissingParameter", steps: []progression.Step{{Target: progression.TargetParameter, Amount: 1, Unit: progression.UnitAbsolute}}, expected: "needs a parameter type"},
+		{name: "Zero", steps: []progression.Step{{Target: progression.TargetReps, Unit: progression.UnitAbsolute}}, expected: "does not change anything"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := progression.Validate(tc.steps)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
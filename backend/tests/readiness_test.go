@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"backend/internal/readiness"
+	"testing"
+)
+
+func heartRate(date string, bpm int32) readiness.Checkin {
+	return readiness.Checkin{Date: day(date), RestingHR: int32Pointer(bpm)}
+}
+
+// TestReadinessScore tests that a check-in scores the average of the measures it gives
+func TestReadinessScore(t *testing.T) {
+	testCases := []struct {
+		name     string
+		checkin  readiness.Checkin
+		baseline *float64
+		expected *float64
+	}{
+		{
+			name:     "full marks",
+			checkin:  readiness.Checkin{SleepHours: float64Pointer(9), FingerPain: int32Pointer(0), Mood: int32Pointer(5)},
+			expected: float64Pointer(100),
+		},
+		{
+			// 6 hours score 75, soreness of 4 at worst 60 and a mood of 3 50
+			name: "partial",
+			checkin: readiness.Checkin{
+				SleepHours: float64Pointer(6),
+				Soreness:   map[string]int32{"fingers": 4, "lats": 2},
+				Mood:       int32Pointer(3),
+			},
+			expected: float64Pointer(61.7),
+		},
+		{
+			// 55 is 10% above a baseline of 50 and scores 0
+			name:     "elevated heart rate",
+			checkin:  readiness.Checkin{RestingHR: int32Pointer(55), FingerPain: int32Pointer(2)},
+			baseline: float64Pointer(50),
+			expected: float64Pointer(40),
+		},
+		{
+			name:    "heart rate without a baseline",
+			checkin: readiness.Checkin{RestingHR: int32Pointer(55)},
+		},
+	}
+	for _, tc := range testCases {
+		got := readiness.Score(tc.checkin, tc.baseline)
+		if (got == nil) != (tc.expected == nil) || (got != nil && *got != *tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestReadinessBaseline tests that a baseline needs enough heart rates from the weeks
+// before the day
+func TestReadinessBaseline(t *testing.T) {
+	history := []readiness.Checkin{
+		heartRate("2025-02-01", 80),
+		heartRate("2025-03-01", 50),
+		heartRate("2025-03-02", 52),
+		heartRate("2025-03-03", 54),
+		heartRate("2025-03-04", 70),
+	}
+	if baseline := readiness.Baseline(history, day("2025-03-03")); baseline != nil {
+		t.Errorf("Expected no baseline from two heart rates, got %v", *baseline)
+	}
+	if baseline := readiness.Baseline(history, day("2025-03-04")); baseline == nil || *baseline != 52 {
+		t.Errorf("Expected a baseline of 52, got %v", baseline)
+	}
+
+	scores := readiness.ScoreAll(history)
+	if scores[3] != nil || scores[4] == nil || *scores[4] != 0 {
+		t.Errorf("Expected only the last heart rate to be scored, at 0, got %v and %v", scores[3], scores[4])
+	}
+}
+
+// TestReadinessTrend tests the daily scores and weekly averages of a trend
+func TestReadinessTrend(t *testing.T) {
+	checkins := []readiness.Checkin{
+		{Date: day("2025-03-01"), SleepHours: float64Pointer(8), FingerPain: int32Pointer(2)},
+		{Date: day("2025-03-03"), SleepHours: float64Pointer(6)},
+		{Date: day("2025-03-09"), Mood: int32Pointer(1)},
+	}
+	trend := readiness.Trend(checkins, day("2025-03-02"), day("2025-03-09"))
+	if len(trend) != 8 {
+		t.Fatalf("Expected 8 days, got %d", len(trend))
+	}
+	if trend[0].Score != nil || *trend[0].SleepAverage != 8 || *trend[0].FingerPainAverage != 2 {
+		t.Errorf("Expected the week's averages without a score of the day, got %+v", trend[0])
+	}
+	if *trend[1].Score != 75 || *trend[1].ScoreAverage != 82.5 || *trend[1].SleepAverage != 7 {
+		t.Errorf("Expected 75 against an average of 82.5, got %+v", trend[1])
+	}
+	// The first check-in has left the window of the last day
+	last := trend[7]
+	if *last.Score != 0 || *last.ScoreAverage != 37.5 || last.FingerPainAverage != nil || last.RestingHRAverage != nil {
+		t.Errorf("Expected 0 against an average of 37.5, got %+v", last)
+	}
+}
+
+// TestReadinessRecommend tests the recommendations for low readiness and sore fingers
+func TestReadinessRecommend(t *testing.T) {
+	hangboard := readiness.Group{ID: 1, Name: "Hangboard", Exercises: []readiness.Exercise{
+		{PrimaryMuscleGroups: []string{"forearms"}, Equipment: []string{"hangboard"}},
+	}}
+	pulling := readiness.Group{ID: 2, Name: "Pulling", Exercises: []readiness.Exercise{
+		{PrimaryMuscleGroups: []string{"lats", "fingers"}},
+	}}
+	pushing := readiness.Group{ID: 3, Name: "Pushing", Exercises: []readiness.Exercise{
+		{PrimaryMuscleGroups: []string{"chest", "triceps"}, Equipment: []string{"dumbbell"}},
+	}}
+	groups := []readiness.Group{hangboard, pulling, pushing}
+	thresholds := readiness.DefaultThresholds
+
+	if !hangboard.LoadsFingers() || !pulling.LoadsFingers() || pushing.LoadsFingers() || !pushing.TrainsAntagonists() {
+		t.Fatalf("Expected the hangboard and pulling groups to load the fingers and pushing to be antagonists")
+	}
+
+	sore := readiness.Checkin{FingerPain: int32Pointer(5)}
+	actions := readiness.Recommend(sore, float64Pointer(70), hangboard, groups, thresholds)
+	if len(actions) != 1 || actions[0].Kind != readiness.ActionSwapGroup || actions[0].Group.ID != 3 {
+		t.Fatalf("Expected a swap to the pushing group, got %+v", actions)
+	}
+
+	// Sore fingers do not change a session that spares them
+	if actions := readiness.Recommend(sore, float64Pointer(70), pushing, groups, thresholds); len(actions) != 0 {
+		t.Errorf("Expected no changes to the pushing group, got %+v", actions)
+	}
+
+	// Without antagonists the session is reduced instead, once
+	actions = readiness.Recommend(sore, float64Pointer(50), hangboard, []readiness.Group{hangboard, pulling}, thresholds)
+	if len(actions) != 1 || actions[0].Kind != readiness.ActionReduceIntensity || actions[0].IntensityFactor != readiness.ReducedIntensity {
+		t.Errorf("Expected a single reduction, got %+v", actions)
+	}
+
+	elbows := readiness.Checkin{Soreness: map[string]int32{"elbows": 6}}
+	if actions := readiness.Recommend(elbows, float64Pointer(40), pulling, groups, thresholds); len(actions) != 2 {
+		t.Errorf("Expected a reduction and a swap for sore elbows, got %+v", actions)
+	}
+
+	if actions := readiness.Recommend(sore, float64Pointer(20), hangboard, groups, thresholds); len(actions) != 1 || actions[0].Kind != readiness.ActionRest {
+		t.Errorf("Expected a rest, got %+v", actions)
+	}
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"backend/internal/records"
+	"testing"
+)
+
+func loggedSet(variationId int64, reps *int32, parameters ...records.Parameter) records.Set {
+	return records.Set{VariationID: variationId, Reps: reps, Parameters: parameters}
+}
+
+func weight(value float64) records.Parameter {
+	return records.Parameter{ParameterTypeID: 1, DataType: records.DataTypeWeight, Value: value}
+}
+
+// TestRecordsEstimatedMax tests the Epley estimate and the sets it is not made from
+func TestRecordsEstimatedMax(t *testing.T) {
+	testCases := []struct {
+		load     float64
+		reps     int32
+		expected float64
+		ok       bool
+	}{
+		{load: 100, reps: 1, expected: 100, ok: true},
+		{load: 100, reps: 5, expected: 116.7, ok: true},
+		{load: 80, reps: 10, expected: 106.7, ok: true},
+		{load: 60, reps: 13},
+		{load: 100, reps: 0},
+		{load: 0, reps: 5},
+	}
+	for _, tc := range testCases {
+		got, ok := records.EstimatedMax(tc.load, tc.reps)
+		if ok != tc.ok || got != tc.expected {
+			t.Errorf("EstimatedMax(%v, %d): expected %v, %v, got %v, %v", tc.load, tc.reps, tc.expected, tc.ok, got, ok)
+		}
+	}
+}
+
+// TestRecordsDetect tests the records a session sets against the current ones
+func TestRecordsDetect(t *testing.T) {
+	hundred := 100.0
+	current := []records.Record{
+		{Kind: records.KindHeaviestLoad, VariationID: 4, ParameterTypeID: 1, Value: 100},
+		{Kind: records.KindEstimatedMax, VariationID: 4, ParameterTypeID: 1, Value: 120},
+		{Kind: records.KindMostReps, VariationID: 4, ParameterTypeID: 1, Value: 6, Load: &hundred},
+	}
+
+	// 90x8 beats the 6 reps done at 100; 105x3 is the heaviest load and the first set at
+	// 105 or above
+	detected := records.Detect(current, []records.Set{
+		loggedSet(4, int32Pointer(8), weight(90)),
+		loggedSet(4, int32Pointer(3), weight(105)),
+	})
+	if len(detected) != 3 {
+		t.Fatalf("Expected 3 records, got %+v", detected)
+	}
+	if detected[0].Kind != records.KindMostReps || detected[0].Set != 0 || *detected[0].Load != 90 || detected[0].Value != 8 || *detected[0].Previous != 6 {
+		t.Errorf("Expected 8 reps at 90 to beat 6 reps at 100, got %+v", detected[0])
+	}
+	if detected[1].Kind != records.KindHeaviestLoad || detected[1].Set != 1 || detected[1].Value != 105 || *detected[1].Previous != 100 {
+		t.Errorf("Expected 105 to be the heaviest load, got %+v", detected[1])
+	}
+	if detected[2].Kind != records.KindMostReps || *detected[2].Load != 105 || detected[2].Previous != nil {
+		t.Errorf("Expected the first reps at 105, got %+v", detected[2])
+	}
+
+	// An estimate of 116.7 does not beat 120, and failed sets set nothing
+	for _, record := range detected {
+		if record.Kind == records.KindEstimatedMax {
+			t.Errorf("Unexpected estimated max %+v", record)
+		}
+	}
+	if failed := records.Detect(nil, []records.Set{loggedSet(4, int32Pointer(0), weight(200))}); len(failed) != 0 {
+		t.Errorf("Expected a failed set to set no records, got %+v", failed)
+	}
+}
+
+// TestRecordsDetectWithinSession tests that a record beaten again in the same session is
+// returned once, and that durations are recorded for time parameters
+func TestRecordsDetectWithinSession(t *testing.T) {
+	hang := func(seconds float64) records.Parameter {
+		return records.Parameter{ParameterTypeID: 3, DataType: records.DataTypeTime, Value: seconds}
+	}
+	detected := records.Detect(nil, []records.Set{
+		loggedSet(5, nil, hang(10)),
+		loggedSet(5, nil, hang(12)),
+		loggedSet(5, nil, hang(11)),
+	})
+	if len(detected) != 1 || detected[0].Kind != records.KindLongestDuration || detected[0].Value != 12 || detected[0].Set != 1 || detected[0].Previous != nil {
+		t.Fatalf("Expected a single 12 second record, got %+v", detected)
+	}
+
+	// 5 reps at 100 make 5 reps at 90 redundant
+	detected = records.Detect(nil, []records.Set{
+		loggedSet(4, int32Pointer(5), weight(90)),
+		loggedSet(4, int32Pointer(5), weight(100)),
+	})
+	kinds := map[string]int{}
+	for _, record := range detected {
+		kinds[record.Kind]++
+	}
+	if kinds[records.KindMostReps] != 1 || kinds[records.KindHeaviestLoad] != 1 || kinds[records.KindEstimatedMax] != 1 {
+		t.Errorf("Expected one record of each weight kind, got %+v", detected)
+	}
+
+	// Parameters without a weight or time data type set no records
+	count := records.Parameter{ParameterTypeID: 2, DataType: "count", Value: 20}
+	if detected := records.Detect(nil, []records.Set{loggedSet(1, int32Pointer(20), count)}); len(detected) != 0 {
+		t.Errorf("Expected no records for a count, got %+v", detected)
+	}
+}
@@ -0,0 +1,203 @@
+package tests
+
+import (
+	"backend/internal/schedule"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+// TestScheduleSessionCount tests that weekly frequencies scale with the interval length
+func TestScheduleSessionCount(t *testing.T) {
+	testCases := []struct {
+		frequency int32
+		days      int
+		expected  int
+	}{
+		{frequency: 3, days: 7, expected: 3},
+		{frequency: 3, days: 14, expected: 6},
+		{frequency: 2, days: 10, expected: 3},
+		{frequency: 1, days: 3, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		if got := schedule.SessionCount(tc.frequency, tc.days); got != tc.expected {
+			t.Errorf("SessionCount(%d, %d) = %d, expected %d", tc.frequency, tc.days, got, tc.expected)
+		}
+	}
+}
+
+// TestScheduleGenerate tests that sessions are spread across back to back intervals
+func TestScheduleGenerate(t *testing.T) {
+	sessions := schedule.Generate(date("2025-03-03"), []schedule.Interval{
+		{ID: 1, Days: 7, Groups: []schedule.Group{{ID: 10, Frequency: 3}, {ID: 20, Frequency: 2}}},
+		{ID: 2, Days: 14, Groups: []schedule.Group{{ID: 10, Frequency: 1}}},
+	})
+
+	expected := []struct {
+		interval int64
+		group    int64
+		date     string
+	}{
+		{1, 10, "2025-03-03"},
+		{1, 20, "2025-03-04"},
+		{1, 10, "2025-03-05"},
+		{1, 10, "2025-03-07"},
+		{1, 20, "2025-03-08"},
+		{2, 10, "2025-03-10"},
+		{2, 10, "2025-03-17"},
+	}
+
+	if len(sessions) != len(expected) {
+		t.Fatalf("Expected %d sessions, got %+v", len(expected), sessions)
+	}
+	for i, e := range expected {
+		session := sessions[i]
+		if session.IntervalID != e.interval || session.GroupID != e.group || session.Date.Format("2006-01-02") != e.date {
+			t.Errorf("Session %d: expected interval %d group %d on %s, got %+v", i, e.interval, e.group, e.date, session)
+		}
+	}
+}
+
+// TestScheduleGenerateWithOptions tests that sessions move onto training days and keep
+// the rest days between them
+func TestScheduleGenerateWithOptions(t *testing.T) {
+	options := schedule.Options{
+		TrainingDays: []time.Weekday{time.Tuesday, time.Thursday, time.Saturday},
+		MinRestDays:  1,
+	}
+	sessions := schedule.GenerateWithOptions(date("2025-03-03"), []schedule.Interval{
+		{ID: 1, Days: 7, Groups: []schedule.Group{{ID: 10, Frequency: 2}, {ID: 20, Frequency: 1}}},
+	}, options)
+
+	expected := []string{"2025-03-04", "2025-03-06", "2025-03-08"}
+	if len(sessions) != len(expected) {
+		t.Fatalf("Expected %d sessions, got %+v", len(expected), sessions)
+	}
+	for i, e := range expected {
+		if got := sessions[i].Date.Format("2006-01-02"); got != e {
+			t.Errorf("Session %d: expected %s, got %s", i, e, got)
+		}
+	}
+}
+
+// TestScheduleGenerateWithOptionsFullInterval tests that an interval with more sessions
+// than training days doubles up instead of spilling into the next interval
+func TestScheduleGenerateWithOptionsFullInterval(t *testing.T) {
+	options := schedule.Options{TrainingDays: []time.Weekday{time.Monday, time.Thursday}}
+	sessions := schedule.GenerateWithOptions(date("2025-03-03"), []schedule.Interval{
+		{ID: 1, Days: 7, Groups: []schedule.Group{{ID: 10, Frequency: 3}}},
+		{ID: 2, Days: 7, Groups: []schedule.Group{{ID: 10, Frequency: 1}}},
+	}, options)
+
+	expected := []string{"2025-03-03", "2025-03-06", "2025-03-06", "2025-03-10"}
+	if len(sessions) != len(expected) {
+		t.Fatalf("Expected %d sessions, got %+v", len(expected), sessions)
+	}
+	for i, e := range expected {
+		if got := sessions[i].Date.Format("2006-01-02"); got != e {
+			t.Errorf("Session %d: expected %s, got %s", i, e, got)
+		}
+	}
+}
+
+// TestScheduleReflow tests that missed sessions are pushed forward in order
+func TestScheduleReflow(t *testing.T) {
+	pending := []schedule.Session{
+		{IntervalID: 1, GroupID: 10, Date: date("2025-03-07")},
+		{IntervalID: 1, GroupID: 20, Date: date("2025-03-10")},
+		{IntervalID: 2, GroupID: 10, Date: date("2025-03-14")},
+	}
+	options := schedule.Options{
+		TrainingDays: []time.Weekday{time.Monday, time.Wednesday, time.Friday},
+		MinRestDays:  1,
+	}
+
+	reflowed := schedule.Reflow(pending, date("2025-03-12"), date("2025-03-11"), options)
+
+	expected := []string{"2025-03-14", "2025-03-17", "2025-03-19"}
+	for i, e := range expected {
+		if reflowed[i].GroupID != pending[i].GroupID || reflowed[i].IntervalID != pending[i].IntervalID {
+			t.Errorf("Session %d changed identity: %+v", i, reflowed[i])
+		}
+		if got := reflowed[i].Date.Format("2006-01-02"); got != e {
+			t.Errorf("Session %d: expected %s, got %s", i, e, got)
+		}
+	}
+	if got := pending[0].Date.Format("2006-01-02"); got != "2025-03-07" {
+		t.Errorf("Reflow modified its input: %s", got)
+	}
+}
+
+// TestScheduleReflowPinned tests that pinned sessions stay on their weekdays and the
+// other sessions are reflowed around them
+func TestScheduleReflowPinned(t *testing.T) {
+	pending := []schedule.Session{
+		{IntervalID: 1, GroupID: 20, Date: date("2025-03-10")},
+		{IntervalID: 1, GroupID: 10, Date: date("2025-03-11"), Pinned: true},
+		{IntervalID: 1, GroupID: 10, Date: date("2025-03-14"), Pinned: true},
+		{IntervalID: 1, GroupID: 20, Date: date("2025-03-15")},
+	}
+
+	reflowed := schedule.Reflow(pending, date("2025-03-13"), time.Time{}, schedule.Options{})
+
+	// The missed Tuesday moves to the next Tuesday, the Friday stays put
+	expected := []string{"2025-03-13", "2025-03-18", "2025-03-14", "2025-03-15"}
+	for i, e := range expected {
+		if got := reflowed[i].Date.Format("2006-01-02"); got != e {
+			t.Errorf("Session %d: expected %s, got %s", i, e, got)
+		}
+	}
+}
+
+// TestScheduleReflowNoTrainingDays tests that reflowing terminates when the options
+// allow no weekday
+func TestScheduleReflowNoTrainingDays(t *testing.T) {
+	pending := []schedule.Session{{IntervalID: 1, GroupID: 10, Date: date("2025-03-10")}}
+	options := schedule.Options{TrainingDays: []time.Weekday{time.Weekday(7)}}
+
+	reflowed := schedule.Reflow(pending, date("2025-03-12"), time.Time{}, options)
+	if got := reflowed[0].Date.Format("2006-01-02"); got != "2025-03-12" {
+		t.Errorf("Expected the session on 2025-03-12, got %s", got)
+	}
+}
+
+// TestScheduleGeneratePinnedDays tests that pinned groups stay on their weekdays and the
+// other groups are placed around them
+func TestScheduleGeneratePinnedDays(t *testing.T) {
+	options := schedule.Options{MinRestDays: 1}
+	sessions := schedule.GenerateWithOptions(date("2025-03-03"), []schedule.Interval{
+		{ID: 1, Days: 14, Groups: []schedule.Group{
+			{ID: 10, Frequency: 2, PinnedDays: []time.Weekday{time.Tuesday, time.Friday}},
+			{ID: 20, Frequency: 1},
+		}},
+	}, options)
+
+	pinned := map[string]bool{}
+	var other []string
+	for _, session := range sessions {
+		if session.GroupID == 10 {
+			pinned[session.Date.Format("2006-01-02")] = true
+		} else {
+			other = append(other, session.Date.Format("2006-01-02"))
+		}
+	}
+
+	for _, day := range []string{"2025-03-04", "2025-03-07", "2025-03-11", "2025-03-14"} {
+		if !pinned[day] {
+			t.Errorf("Expected a pinned session on %s, got %v", day, pinned)
+		}
+	}
+	if len(other) != 2 {
+		t.Fatalf("Expected 2 sessions of the unpinned group, got %v", other)
+	}
+	for _, day := range other {
+		if pinned[day] {
+			t.Errorf("Unpinned session placed on pinned day %s", day)
+		}
+	}
+}
@@ -132,15 +132,45 @@ func (td *TestDatabase) CleanupTestDB(ctx context.Context) error {
 
 func (td *TestDatabase) Reset(ctx context.Context) error {
 	truncateQueries := []string{
+		"TRUNCATE TABLE plan_comment_mentions CASCADE",
+		"TRUNCATE TABLE plan_comments CASCADE",
+		"TRUNCATE TABLE plan_versions CASCADE",
+		"TRUNCATE TABLE plan_ratings CASCADE",
+		"TRUNCATE TABLE plan_collaborators CASCADE",
+		"TRUNCATE TABLE plan_share_links CASCADE",
+		"TRUNCATE TABLE team_invitations CASCADE",
+		"TRUNCATE TABLE team_members CASCADE",
+		"TRUNCATE TABLE teams CASCADE",
+		"TRUNCATE TABLE readiness_soreness CASCADE",
+		"TRUNCATE TABLE readiness_checkins CASCADE",
+		"TRUNCATE TABLE training_load_thresholds CASCADE",
+		"TRUNCATE TABLE session_completions CASCADE",
+		"TRUNCATE TABLE logbook_entries CASCADE",
+		"TRUNCATE TABLE personal_records CASCADE",
+		"TRUNCATE TABLE session_set_parameters CASCADE",
+		"TRUNCATE TABLE session_sets CASCADE",
+		"TRUNCATE TABLE scheduled_sessions CASCADE",
+		"TRUNCATE TABLE plan_assignments CASCADE",
+		"TRUNCATE TABLE benchmarks CASCADE",
+		"TRUNCATE TABLE calendar_feeds CASCADE",
+		"TRUNCATE TABLE progression_rule_steps CASCADE",
+		"TRUNCATE TABLE progression_rules CASCADE",
+		"TRUNCATE TABLE prescription_set_parameters CASCADE",
+		"TRUNCATE TABLE prescription_sets CASCADE",
 		"TRUNCATE TABLE interval_exercise_prescriptions CASCADE",
+		"TRUNCATE TABLE prescription_blocks CASCADE",
 		"TRUNCATE TABLE exercise_variation_params CASCADE",
 		"TRUNCATE TABLE exercise_variations CASCADE",
 		"TRUNCATE TABLE interval_group_assignments CASCADE",
+		"TRUNCATE TABLE exercise_categories CASCADE",
+		"TRUNCATE TABLE exercise_media CASCADE",
 		"TRUNCATE TABLE plan_intervals CASCADE",
 		"TRUNCATE TABLE exercises CASCADE",
 		"TRUNCATE TABLE groups CASCADE",
 		"TRUNCATE TABLE plans CASCADE",
+		"TRUNCATE TABLE user_parameter_types CASCADE",
 		"TRUNCATE TABLE parameter_types CASCADE",
+		"TRUNCATE TABLE user_equipment CASCADE",
 		"TRUNCATE TABLE users CASCADE",
 	}
 
@@ -204,15 +234,46 @@ func (td *TestDatabase) Health(ctx context.Context) error {
 
 func (td *TestDatabase) QuickReset(ctx context.Context) error {
 	deleteQueries := []string{
+		"DELETE FROM plan_comment_mentions",
+		"DELETE FROM plan_comments",
+		"DELETE FROM plan_versions",
+		"DELETE FROM plan_ratings",
+		"DELETE FROM plan_collaborators",
+		"DELETE FROM plan_share_links",
+		"DELETE FROM team_invitations",
+		"DELETE FROM team_members",
+		"DELETE FROM teams",
+		"DELETE FROM readiness_soreness",
+		"DELETE FROM readiness_checkins",
+		"DELETE FROM training_load_thresholds",
+		"DELETE FROM session_completions",
+		"DELETE FROM logbook_entries",
+		"DELETE FROM personal_records",
+		"DELETE FROM session_set_parameters",
+		"DELETE FROM session_sets",
+		"DELETE FROM scheduled_sessions",
+		"DELETE FROM plan_assignments",
+		"DELETE FROM benchmarks",
+		"DELETE FROM calendar_feeds",
+		"DELETE FROM progression_rule_steps",
+		"DELETE FROM progression_rules",
+		"DELETE FROM prescription_set_parameters",
+		"DELETE FROM prescription_sets",
 		"DELETE FROM interval_exercise_prescriptions",
+		"DELETE FROM prescription_blocks",
 		"DELETE FROM exercise_variation_params",
 		"DELETE FROM exercise_variations",
 		"DELETE FROM interval_group_assignments",
+		"DELETE FROM exercise_categories",
+		"DELETE FROM exercise_media",
 		"DELETE FROM plan_intervals",
 		"DELETE FROM exercises",
 		"DELETE FROM groups",
 		"DELETE FROM plans",
+		"DELETE FROM user_parameter_types",
 		"DELETE FROM parameter_types",
+		"DELETE FROM categories WHERE user_id IS NOT NULL",
+		"DELETE FROM user_equipment",
 		"DELETE FROM users",
 	}
 
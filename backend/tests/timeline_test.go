@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"backend/internal/timeline"
+	"testing"
+	"time"
+)
+
+type expectedSegment struct {
+	kind     string
+	id       int64
+	start    time.Duration
+	duration time.Duration
+}
+
+func checkSegments(t *testing.T, compiled timeline.Timeline, expected []expectedSegment) {
+	t.Helper()
+	if len(compiled.Segments) != len(expected) {
+		t.Fatalf("Expected %d segments, got %+v", len(expected), compiled.Segments)
+	}
+	for i, want := range expected {
+		got := compiled.Segments[i]
+		if got.Kind != want.kind || got.PrescriptionID != want.id || got.Start != want.start || got.Duration != want.duration {
+			t.Errorf("Segment %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func repeatSet(set timeline.Set, count int) []timeline.Set {
+	sets := make([]timeline.Set, count)
+	for i := range sets {
+		sets[i] = set
+	}
+	return sets
+}
+
+// TestTimelineRepeaters tests that a repeater protocol is laid out hang by hang with rest
+// between reps and sets, but none after the last set
+func TestTimelineRepeaters(t *testing.T) {
+	hangs := timeline.Prescription{ID: 1, Exercise: "Max Hangs", Sets: repeatSet(timeline.Set{
+		SubReps: 3, SubRepWork: 7 * time.Second, SubRepRest: 3 * time.Second, Rest: time.Minute,
+	}, 2)}
+	pullUps := timeline.Prescription{ID: 2, Exercise: "Pull-ups", Sets: []timeline.Set{{Reps: 6, Rest: 2 * time.Minute}}}
+
+	compiled := timeline.Compile([]timeline.Prescription{hangs, pullUps}, nil, 30*time.Second)
+
+	s := time.Second
+	checkSegments(t, compiled, []expectedSegment{
+		{timeline.SegmentWork, 1, 0, 7 * s},
+		{timeline.SegmentRest, 1, 7 * s, 3 * s},
+		{timeline.SegmentWork, 1, 10 * s, 7 * s},
+		{timeline.SegmentRest, 1, 17 * s, 3 * s},
+		{timeline.SegmentWork, 1, 20 * s, 7 * s},
+		{timeline.SegmentRest, 1, 27 * s, 60 * s},
+		{timeline.SegmentWork, 1, 87 * s, 7 * s},
+		{timeline.SegmentRest, 1, 94 * s, 3 * s},
+		{timeline.SegmentWork, 1, 97 * s, 7 * s},
+		{timeline.SegmentRest, 1, 104 * s, 3 * s},
+		{timeline.SegmentWork, 1, 107 * s, 7 * s},
+		{timeline.SegmentTransition, 2, 114 * s, 30 * s},
+		{timeline.SegmentWork, 2, 144 * s, 0},
+	})
+	if compiled.Total != 144*s || compiled.Untimed != 1 {
+		t.Errorf("Expected 144 seconds with 1 untimed segment, got %v and %d", compiled.Total, compiled.Untimed)
+	}
+	if last := compiled.Segments[len(compiled.Segments)-1]; last.Timed || last.Reps != 6 {
+		t.Errorf("Expected the pull-ups to be untimed with 6 reps, got %+v", last)
+	}
+	if rep := compiled.Segments[4]; rep.Set != 1 || rep.Rep != 3 {
+		t.Errorf("Expected the fifth segment to be rep 3 of set 1, got %+v", rep)
+	}
+}
+
+// TestTimelineBlocks tests that supersets alternate their members round by round and
+// rest after each round
+func TestTimelineBlocks(t *testing.T) {
+	s := time.Second
+	prescriptions := []timeline.Prescription{
+		{ID: 1, BlockID: 9, Sets: repeatSet(timeline.Set{Work: 20 * s, Rest: 90 * s}, 2)},
+		{ID: 2, BlockID: 9, Sets: repeatSet(timeline.Set{Work: 30 * s, Rest: 45 * s}, 1)},
+	}
+
+	compiled := timeline.Compile(prescriptions, map[int64]timeline.Block{9: {ID: 9}}, 10*s)
+	checkSegments(t, compiled, []expectedSegment{
+		{timeline.SegmentWork, 1, 0, 20 * s},
+		{timeline.SegmentTransition, 2, 20 * s, 10 * s},
+		{timeline.SegmentWork, 2, 30 * s, 30 * s},
+		{timeline.SegmentRest, 2, 60 * s, 45 * s},
+		{timeline.SegmentWork, 1, 105 * s, 20 * s},
+	})
+
+	// The block's own rest takes precedence, and no transition is zero seconds long
+	compiled = timeline.Compile(prescriptions, map[int64]timeline.Block{9: {ID: 9, Rest: 2 * time.Minute, HasRest: true}}, 0)
+	checkSegments(t, compiled, []expectedSegment{
+		{timeline.SegmentWork, 1, 0, 20 * s},
+		{timeline.SegmentWork, 2, 20 * s, 30 * s},
+		{timeline.SegmentRest, 2, 50 * s, 120 * s},
+		{timeline.SegmentWork, 1, 170 * s, 20 * s},
+	})
+	if compiled.Total != 190*s || compiled.Segments[3].Round != 2 {
+		t.Errorf("Unexpected timeline %+v", compiled)
+	}
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"backend/internal/trainingload"
+	"slices"
+	"testing"
+	"time"
+)
+
+func loadSession(date string, rpe int32, minutes int) trainingload.Session {
+	return trainingload.Session{Date: day(date), RPE: rpe, Duration: time.Duration(minutes) * time.Minute}
+}
+
+// TestTrainingLoadSessionLoad tests that a session's load is its RPE times its minutes
+func TestTrainingLoadSessionLoad(t *testing.T) {
+	if load := trainingload.SessionLoad(7, 90*time.Minute); load != 630 {
+		t.Errorf("Expected 630, got %v", load)
+	}
+	if load := trainingload.SessionLoad(5, 45*time.Second); load != 3.75 {
+		t.Errorf("Expected 3.75, got %v", load)
+	}
+}
+
+// TestTrainingLoadSeries tests the rolling loads, ratio and flags of a steady month
+// followed by a spike
+func TestTrainingLoadSeries(t *testing.T) {
+	// Four weeks of 300 on Mondays, Wednesdays and Fridays
+	var sessions []trainingload.Session
+	for week := 0; week < 4; week++ {
+		monday := day("2025-03-03").AddDate(0, 0, 7*week)
+		for _, offset := range []int{0, 2, 4} {
+			sessions = append(sessions, trainingload.Session{Date: monday.AddDate(0, 0, offset), RPE: 5, Duration: time.Hour})
+		}
+	}
+	// Then a hard week of 600 every day
+	for offset := 0; offset < 7; offset++ {
+		sessions = append(sessions, trainingload.Session{Date: day("2025-03-31").AddDate(0, 0, offset), RPE: 10, Duration: time.Hour})
+	}
+
+	series := trainingload.Series(sessions, day("2025-03-30"), day("2025-04-06"), trainingload.DefaultThresholds)
+	if len(series) != 8 {
+		t.Fatalf("Expected 8 days, got %d", len(series))
+	}
+
+	steady := series[0]
+	if !steady.Date.Equal(day("2025-03-30")) || steady.Load != 0 || steady.Acute != 900 || steady.Chronic != 900 {
+		t.Errorf("Expected a steady week of 900, got %+v", steady)
+	}
+	if steady.ACWR == nil || *steady.ACWR != 1 || len(steady.Flags) != 0 {
+		t.Errorf("Expected a ratio of 1 without flags, got %+v", steady)
+	}
+	// 300, 0, 300, 0, 300, 0, 0 average 128.57 with a standard deviation of 148.46
+	if steady.Monotony == nil || *steady.Monotony != 0.87 || steady.Strain == nil || *steady.Strain != 779.42 {
+		t.Errorf("Expected a monotony of 0.87 and a strain of 779.42, got %+v", steady)
+	}
+
+	// The hard week loads every day the same, so monotony has no spread to divide by
+	spike := series[7]
+	if spike.Load != 600 || spike.Acute != 4200 || spike.Chronic != 1725 {
+		t.Errorf("Expected a 4200 week against a chronic 1725, got %+v", spike)
+	}
+	if spike.ACWR == nil || *spike.ACWR != 2.43 || !slices.Equal(spike.Flags, []string{trainingload.FlagACWRHigh}) {
+		t.Errorf("Expected a flagged ratio of 2.43, got %+v", spike)
+	}
+	if spike.Monotony != nil || spike.Strain != nil {
+		t.Errorf("Expected no monotony for a week without variation, got %+v", spike)
+	}
+
+	// Six hard days and a rest day are monotonous and straining
+	sixth := series[6]
+	if !slices.Equal(sixth.Flags, []string{trainingload.FlagACWRHigh, trainingload.FlagMonotonyHigh, trainingload.FlagStrainHigh}) {
+		t.Errorf("Expected every flag on the sixth hard day, got %+v", sixth)
+	}
+}
+
+// TestTrainingLoadSeriesWithoutHistory tests that a ratio needs chronic load and that
+// days after a layoff are flagged low
+func TestTrainingLoadSeriesWithoutHistory(t *testing.T) {
+	sessions := []trainingload.Session{loadSession("2025-03-01", 6, 60), loadSession("2025-03-20", 6, 30)}
+
+	series := trainingload.Series(sessions, day("2025-02-27"), day("2025-03-20"), trainingload.DefaultThresholds)
+	if len(series) != 22 {
+		t.Fatalf("Expected 22 days, got %d", len(series))
+	}
+	if series[0].ACWR != nil || series[0].Chronic != 0 {
+		t.Errorf("Expected no ratio before any load, got %+v", series[0])
+	}
+	if series[2].ACWR == nil || *series[2].ACWR != 4 {
+		t.Errorf("Expected the first session to be four times its chronic week, got %+v", series[2])
+	}
+	last := series[len(series)-1]
+	if last.Acute != 180 || last.Chronic != 135 || *last.ACWR != 1.33 {
+		t.Errorf("Expected 180 against 135, got %+v", last)
+	}
+	if !slices.Contains(series[10].Flags, trainingload.FlagACWRLow) {
+		t.Errorf("Expected a week off to be flagged low, got %+v", series[10])
+	}
+
+	if series := trainingload.Series(sessions, day("2025-03-20"), day("2025-03-19"), trainingload.DefaultThresholds); series != nil {
+		t.Errorf("Expected no days for an empty range, got %+v", series)
+	}
+}
+
+// TestTrainingLoadThresholdsValidate tests the thresholds that are rejected
+func TestTrainingLoadThresholdsValidate(t *testing.T) {
+	if err := trainingload.DefaultThresholds.Validate(); err != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", err)
+	}
+	invalid := []trainingload.Thresholds{
+		{ACWRHigh: 1.5, ACWRLow: 1.5, Monotony: 2, Strain: 6000},
+		{ACWRHigh: 1.5, ACWRLow: 0.8, Monotony: 0, Strain: 6000},
+		{ACWRHigh: 1.5, ACWRLow: -1, Monotony: 2, Strain: 6000},
+	}
+	for _, thresholds := range invalid {
+		if err := thresholds.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", thresholds)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"backend/internal/workout"
+	"reflect"
+	"testing"
+)
+
+// TestWorkoutCheckBlocks tests that supersets and circuits must not be split up
+func TestWorkoutCheckBlocks(t *testing.T) {
+	together := []workout.Entry{{ID: 1}, {ID: 2, BlockID: 7}, {ID: 3, BlockID: 7}, {ID: 4}, {ID: 5, BlockID: 8}, {ID: 6, BlockID: 8}}
+	if err := workout.CheckBlocks(together); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	apart := []workout.Entry{{ID: 2, BlockID: 7}, {ID: 1}, {ID: 3, BlockID: 7}}
+	if err := workout.CheckBlocks(apart); err == nil {
+		t.Error("Expected a split superset to be rejected")
+	}
+}
+
+// TestWorkoutLink tests that linked prescriptions move to where the first of them was
+func TestWorkoutLink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		order    []int64
+		members  []int64
+		expected []int64
+	}{
+		{name: "Adjacent", order: []int64{1, 2, 3}, members: []int64{2, 3}, expected: []int64{1, 2, 3}},
+		{name: "Apart", order: []int64{1, 2, 3, 4}, members: []int64{1, 4}, expected: []int64{1, 4, 2, 3}},
+		{name: "GivenOrder", order: []int64{1, 2, 3, 4}, members: []int64{4, 2}, expected: []int64{1, 4, 2, 3}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := workout.Link(tc.order, tc.members); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+// TestWorkoutSameMembers tests that a new order must list every prescription once
+func TestWorkoutSameMembers(t *testing.T) {
+	order := []int64{1, 2, 3}
+	if !workout.SameMembers(order, []int64{3, 1, 2}) {
+		t.Error("Expected a permutation to be accepted")
+	}
+	for _, ids := range [][]int64{{1, 2}, {1, 2, 2}, {1, 2, 4}} {
+		if workout.SameMembers(order, ids) {
+			t.Errorf("Expected %v to be rejected", ids)
+		}
+	}
+}
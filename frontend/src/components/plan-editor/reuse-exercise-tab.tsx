@@ -1,8 +1,9 @@
 import { Exercise, ExerciseVariation } from '@/services/types';
 import { useState, useRef } from 'react';
 import { Input } from '@heroui/input';
+import { Chip } from '@heroui/chip';
 import ExpandableExerciseCard from './expandable-exercise-card';
-import { useExercises } from '@/services/hooks';
+import { useCategories, useExercises } from '@/services/hooks';
 import { X } from 'lucide-react';
 
 interface ReuseExerciseTabProps {
@@ -11,9 +12,11 @@ interface ReuseExerciseTabProps {
 
 const ReuseExerciseTab = ({ onSelect }: ReuseExerciseTabProps) => {
   const [searchTerm, setSearchTerm] = useState('');
+  const [categoryId, setCategoryId] = useState<number | undefined>(undefined);
   const searchInputRef = useRef<HTMLInputElement>(null);
 
-  const { data: exercises } = useExercises({ userId: 1 });
+  const { data: categories } = useCategories({ userId: 1 });
+  const { data: exercises } = useExercises({ userId: 1, categoryId });
 
   const filteredExercises = exercises
     ? exercises.filter(
@@ -55,6 +58,24 @@ const ReuseExerciseTab = ({ onSelect }: ReuseExerciseTabProps) => {
             </button>
           )}
         </div>
+        {categories && categories.length > 0 && (
+          <div className="flex flex-wrap gap-1.5 mt-2">
+            {categories.map((category) => (
+              <Chip
+                key={category.id}
+                size="sm"
+                color="primary"
+                variant={categoryId === category.id ? 'solid' : 'bordered'}
+                className="cursor-pointer"
+                onClick={() =>
+                  setCategoryId(categoryId === category.id ? undefined : category.id)
+                }
+              >
+                {category.name}
+              </Chip>
+            ))}
+          </div>
+        )}
         <div className="text-xs text-gray-500 mt-1.5 ml-1">
           Select an exercise variant to use in this group
         </div>
@@ -66,7 +87,9 @@ const ReuseExerciseTab = ({ onSelect }: ReuseExerciseTabProps) => {
           <div className="text-center py-8 text-gray-400">
             {searchTerm
               ? `No exercises found matching "${searchTerm}"`
-              : 'No exercises available to reuse.'}
+              : categoryId
+                ? 'No exercises in this category.'
+                : 'No exercises available to reuse.'}
           </div>
         ) : (
           <div className="space-y-3 pb-4">
@@ -1,8 +1,9 @@
 import React, { useState } from 'react';
 import { Card, CardContent, CardHeader, CardTitle, CardDescription } from 'shad/components/ui/card';
 import { Button } from '@heroui/button';
-import { CheckCircle, Star, TrendingUp, Clock, Save, Home } from 'lucide-react';
+import { CheckCircle, Star, TrendingUp, Clock, Save, Home, Trophy } from 'lucide-react';
 import { Textarea } from 'shad/components/ui/textarea';
+import { PersonalRecord } from '@/services/types';
 
 interface ExerciseResult {
   exerciseId: number;
@@ -30,6 +31,8 @@ interface WorkoutCompletionScreenProps {
   exercises: ExerciseSummary[];
   results: ExerciseResult[];
   previousResults?: ExerciseResult[];
+  // Records set in this workout, from the session completion response
+  personalRecords?: PersonalRecord[];
   onSave: (notes: string) => void;
   onReturn: () => void;
 }
@@ -40,6 +43,7 @@ const WorkoutCompletionScreen: React.FC<WorkoutCompletionScreenProps> = ({
   exercises,
   results,
   previousResults = [],
+  personalRecords = [],
   onSave,
   onReturn,
 }) => {
@@ -123,6 +127,20 @@ const WorkoutCompletionScreen: React.FC<WorkoutCompletionScreenProps> = ({
   };
   
   const highlights = getPerformanceHighlights();
+
+  const describeRecord = (record: PersonalRecord): string => {
+    const previous = record.previousValue !== null ? ` (was ${record.previousValue})` : '';
+    switch (record.kind) {
+      case 'heaviest_load':
+        return `Heaviest load: ${record.value}${previous}`;
+      case 'longest_duration':
+        return `Longest hold: ${record.value}s${previous}`;
+      case 'most_reps':
+        return `Most reps at ${record.load}: ${record.value}${previous}`;
+      case 'estimated_max':
+        return `Best estimated max: ${record.value}${previous}`;
+    }
+  };
   
   return (
     <div className="container mx-auto p-4 max-w-3xl">
@@ -148,6 +166,29 @@ const WorkoutCompletionScreen: React.FC<WorkoutCompletionScreenProps> = ({
             </div>
           </div>
           
+          {/* Personal Records */}
+          {personalRecords.length > 0 && (
+            <div className="mb-6">
+              <h3 className="text-lg font-medium mb-3">
+                {personalRecords.length} new personal record{personalRecords.length > 1 ? 's' : ''}!
+              </h3>
+              <div className="space-y-3">
+                {personalRecords.map(record => {
+                  const exercise = exercises.find(e => e.id === record.exerciseVariationId);
+                  return (
+                    <div key={record.id} className="flex items-center gap-3 p-3 bg-yellow-100/60 dark:bg-yellow-900/30 rounded-lg">
+                      <Trophy className="h-5 w-5 text-yellow-500" />
+                      <span>
+                        {exercise ? `${exercise.name}: ` : ''}
+                        {describeRecord(record)}
+                      </span>
+                    </div>
+                  );
+                })}
+              </div>
+            </div>
+          )}
+
           {/* Exercise Summary */}
           <div className="mb-6">
             <h3 className="text-lg font-medium mb-3">Exercise Summary</h3>
@@ -4,6 +4,10 @@ import { createRoot } from 'react-dom/client';
 import './index.css';
 import App from './App.tsx';
 import { HeroUIProvider } from '@heroui/react';
+import { AuthService } from './services/api/auth';
+
+// Sign in with a token passed in the URL before the first request is made
+AuthService.storeTokenFromUrl();
 
 createRoot(document.getElementById('root')!).render(
   <StrictMode>
@@ -0,0 +1,34 @@
+// Key of the bearer token the API client sends with every request
+export const AUTH_TOKEN_KEY = 'authToken';
+
+// Name of the query parameter the app is opened with to sign in, e.g. ?token=<token>
+const TOKEN_PARAM = 'token';
+
+export const AuthService = {
+  getToken(): string | null {
+    return localStorage.getItem(AUTH_TOKEN_KEY);
+  },
+
+  setToken(token: string): void {
+    localStorage.setItem(AUTH_TOKEN_KEY, token);
+  },
+
+  clearToken(): void {
+    localStorage.removeItem(AUTH_TOKEN_KEY);
+  },
+
+  /**
+   * Store a token issued with the backend's `token` command that the app was opened
+   * with, then drop it from the address bar so it is not kept in the history
+   */
+  storeTokenFromUrl(): void {
+    const url = new URL(window.location.href);
+    const token = url.searchParams.get(TOKEN_PARAM);
+    if (!token) {
+      return;
+    }
+    this.setToken(token);
+    url.searchParams.delete(TOKEN_PARAM);
+    window.history.replaceState(window.history.state, '', url.toString());
+  },
+};
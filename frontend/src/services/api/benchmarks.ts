@@ -0,0 +1,33 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { Benchmark, BenchmarkDto } from '../types';
+
+export interface BenchmarkFilters {
+  exerciseVariationId?: number;
+  parameterTypeId?: number;
+}
+
+export const BenchmarkService = {
+  /**
+   * List a user's benchmarks, most recent first
+   */
+  async getBenchmarks(userId: number, filters: BenchmarkFilters = {}): Promise<ApiResponse<Benchmark[]>> {
+    return apiClient.get(`/users/${userId}/benchmarks`, { params: filters });
+  },
+
+  async createBenchmark(userId: number, benchmark: BenchmarkDto): Promise<ApiResponse<Benchmark>> {
+    return apiClient.post(`/users/${userId}/benchmarks`, benchmark);
+  },
+
+  async updateBenchmark(
+    userId: number,
+    id: number,
+    data: Pick<BenchmarkDto, 'value' | 'recordedOn'>,
+  ): Promise<ApiResponse<Benchmark>> {
+    return apiClient.put(`/users/${userId}/benchmarks/${id}`, data);
+  },
+
+  async deleteBenchmark(userId: number, id: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/users/${userId}/benchmarks/${id}`);
+  },
+};
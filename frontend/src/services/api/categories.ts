@@ -0,0 +1,60 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { Category } from '../types';
+
+/**
+ * Filters for listing categories.
+ * Global categories are always included alongside the user's own.
+ */
+export interface CategoryFilters {
+  id?: number;
+  userId?: number;
+  exerciseId?: number;
+}
+
+interface PaginationParams {
+  limit?: number;
+  offset?: number;
+}
+
+export interface CreateCategoryDto {
+  name: string;
+  icon: string;
+  userId: number;
+}
+
+export interface UpdateCategoryDto {
+  name: string;
+  icon: string;
+}
+
+export const CategoryService = {
+  async getCategories(
+    filters: CategoryFilters,
+    pagination: PaginationParams = { limit: 100, offset: 0 },
+  ): Promise<ApiResponse<Category[]>> {
+    return apiClient.get('/categories', {
+      params: { ...filters, ...pagination },
+    });
+  },
+
+  async createCategory(categoryData: CreateCategoryDto): Promise<ApiResponse<Category>> {
+    return apiClient.post('/categories', categoryData);
+  },
+
+  async updateCategory(id: number, updateData: UpdateCategoryDto): Promise<ApiResponse<Category>> {
+    return apiClient.put(`/categories/${id}`, updateData);
+  },
+
+  async deleteCategory(id: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/categories/${id}`);
+  },
+
+  async addExerciseCategory(exerciseId: number, categoryId: number): Promise<ApiResponse<void>> {
+    return apiClient.post(`/exercises/${exerciseId}/categories/${categoryId}`);
+  },
+
+  async removeExerciseCategory(exerciseId: number, categoryId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/exercises/${exerciseId}/categories/${categoryId}`);
+  },
+};
@@ -1,9 +1,10 @@
 import axios, { InternalAxiosRequestConfig, AxiosError, AxiosRequestConfig, AxiosResponse } from 'axios';
 import { ApiResponse, ApiError } from './errorHandler';
+import { AuthService } from './auth';
 
 // Import the ApiErrorCode from errorHandler instead of redefining it
 
-const API_BASE_URL = 'http://dev.rocket:8080/api/v1';
+export const API_BASE_URL = 'http://dev.rocket:8080/api/v1';
 
 // Create the axios instance
 const axiosInstance = axios.create({
@@ -17,7 +18,7 @@ const axiosInstance = axios.create({
 // Configure request interceptor with proper types
 axiosInstance.interceptors.request.use(
   (config: InternalAxiosRequestConfig) => {
-    const token = localStorage.getItem('authToken');
+    const token = AuthService.getToken();
     if (token && config.headers) {
       config.headers.Authorization = `Bearer ${token}`;
     }
@@ -1,4 +1,4 @@
-import apiClient from './client';
+import apiClient, { API_BASE_URL } from './client';
 import { ApiResponse } from './errorHandler';
 import {
   Exercise,
@@ -6,6 +6,9 @@ import {
   UpdateExerciseDto,
   ExerciseVariation,
   CreateExerciseVariationDto,
+  ExerciseMedia,
+  ExerciseLibraryFormat,
+  ExerciseLibraryImportReport,
 } from '../types';
 
 export interface ExerciseFilters {
@@ -14,6 +17,10 @@ export interface ExerciseFilters {
   planId?: number;
   groupId?: number;
   intervalId?: number;
+  categoryId?: number;
+  muscleGroup?: string;
+  equipment?: string;
+  laterality?: 'bilateral' | 'unilateral';
 }
 
 export interface ExerciseVariationFilters {
@@ -25,6 +32,11 @@ export interface ExerciseVariationFilters {
   intervalId?: number;
 }
 
+export interface ExerciseLibraryImportOptions {
+  dryRun?: boolean;
+  onConflict?: 'skip' | 'fail';
+}
+
 interface PaginationParams {
   limit?: number;
   offset?: number;
@@ -80,4 +92,38 @@ export const ExerciseService = {
   async deleteExerciseVariation(id: number): Promise<ApiResponse<void>> {
     return apiClient.delete(`/exercise-variations/${id}`);
   },
+
+  async getExerciseMedia(exerciseId: number): Promise<ApiResponse<ExerciseMedia[]>> {
+    return apiClient.get(`/exercises/${exerciseId}/media`);
+  },
+
+  async uploadExerciseMedia(exerciseId: number, file: File): Promise<ApiResponse<ExerciseMedia>> {
+    const formData = new FormData();
+    formData.append('file', file);
+    return apiClient.post(`/exercises/${exerciseId}/media`, formData, {
+      headers: { 'Content-Type': 'multipart/form-data' },
+    });
+  },
+
+  async deleteExerciseMedia(exerciseId: number, mediaId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/exercises/${exerciseId}/media/${mediaId}`);
+  },
+
+  // Library downloads are plain files rather than wrapped API responses, so they are
+  // fetched through a link instead of the API client
+  getExerciseLibraryExportUrl(userId: number, format: ExerciseLibraryFormat = 'json'): string {
+    return `${API_BASE_URL}/exercises/export?userId=${userId}&format=${format}`;
+  },
+
+  async importExerciseLibrary(
+    userId: number,
+    file: File,
+    options: ExerciseLibraryImportOptions = {},
+  ): Promise<ApiResponse<ExerciseLibraryImportReport>> {
+    const format: ExerciseLibraryFormat = file.name.toLowerCase().endsWith('.csv') ? 'csv' : 'json';
+    return apiClient.post('/exercises/import', await file.text(), {
+      params: { userId, format, ...options },
+      headers: { 'Content-Type': format === 'csv' ? 'text/csv' : 'application/json' },
+    });
+  },
 };
@@ -1,6 +1,6 @@
 import apiClient from './client';
 import { ApiResponse } from './errorHandler';
-import { Group, CreateGroupDto, UpdateGroupDto } from '../types';
+import { Group, CreateGroupDto, UpdateGroupDto, IntervalAssignmentDto, IntervalGroupAssignment, WorkoutTimeline } from '../types';
 
 export interface GroupFilters {
   planId?: number;
@@ -47,8 +47,37 @@ export const GroupService = {
     return apiClient.delete(`/groups/${id}`);
   },
 
-  async assignGroupToInterval(groupId: number, intervalId: number): Promise<ApiResponse<void>> {
-    return apiClient.post(`/groups/${groupId}/assign/${intervalId}`, {});
+  // Assigning a group that is already in the interval updates its assignment
+  async assignGroupToInterval(
+    groupId: number,
+    intervalId: number,
+    assignment: IntervalAssignmentDto = {},
+  ): Promise<ApiResponse<IntervalGroupAssignment>> {
+    return apiClient.post(`/groups/${groupId}/assign/${intervalId}`, assignment);
+  },
+
+  async updateIntervalAssignment(
+    groupId: number,
+    intervalId: number,
+    assignment: IntervalAssignmentDto,
+  ): Promise<ApiResponse<IntervalGroupAssignment>> {
+    return apiClient.put(`/groups/${groupId}/assign/${intervalId}`, assignment);
+  },
+
+  async getGroupAssignments(groupId: number): Promise<ApiResponse<IntervalGroupAssignment[]>> {
+    return apiClient.get(`/groups/${groupId}/assignments`);
+  },
+
+  async getIntervalAssignments(intervalId: number): Promise<ApiResponse<IntervalGroupAssignment[]>> {
+    return apiClient.get(`/intervals/${intervalId}/groups`);
+  },
+
+  /**
+   * Compile the group's prescriptions in an interval into timer segments.
+   * transition is the time between exercises, e.g. "45 seconds".
+   */
+  async getGroupTimeline(groupId: number, intervalId: number, transition?: string): Promise<ApiResponse<WorkoutTimeline>> {
+    return apiClient.get(`/groups/${groupId}/timeline`, { params: { intervalId, transition } });
   },
 
   async removeGroupFromInterval(groupId: number, intervalId: number): Promise<ApiResponse<void>> {
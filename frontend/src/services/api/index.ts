@@ -1,4 +1,5 @@
 export { default as apiClient } from './client';
+export * from './auth';
 export * from './plans';
 export * from './intervals';
 export * from './groups';
@@ -7,3 +8,12 @@ export * from './exerciseVariations';
 export * from './prescriptions';
 export * from './parameterTypes';
 export * from './errorHandler';
+export * from './categories';
+export * from './schedule';
+export * from './progressionRules';
+export * from './benchmarks';
+export * from './logbook';
+export * from './trainingLoad';
+export * from './readiness';
+export * from './teams';
+export * from './templates';
@@ -1,6 +1,6 @@
 import apiClient from './client';
 import { ApiResponse } from './errorHandler';
-import { PlanInterval, CreatePlanIntervalDto } from '../types';
+import { PlanInterval, CreatePlanIntervalDto, InsertDeloadDto, ProgressionPreview } from '../types';
 
 export interface IntervalFilters {
   planId?: number;
@@ -49,6 +49,28 @@ export const IntervalService = {
     return apiClient.delete(`/intervals/${id}`);
   },
 
+  /**
+   * Copy an interval count times, right after it, progressing each copy by the
+   * prescriptions' progression rules
+   */
+  async copyInterval(id: number, count = 1): Promise<ApiResponse<PlanInterval[]>> {
+    return apiClient.post(`/intervals/${id}/copy`, { count });
+  },
+
+  /**
+   * Preview the interval's prescriptions in each of the next count intervals
+   */
+  async previewProgression(id: number, count = 1): Promise<ApiResponse<ProgressionPreview>> {
+    return apiClient.get(`/intervals/${id}/progression`, { params: { count } });
+  },
+
+  /**
+   * Insert a deload right after an interval with its prescriptions reduced
+   */
+  async insertDeload(id: number, data: InsertDeloadDto = {}): Promise<ApiResponse<PlanInterval>> {
+    return apiClient.post(`/intervals/${id}/deload`, data);
+  },
+
   /**
    * Reorder intervals within a plan
    */
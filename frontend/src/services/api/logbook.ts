@@ -0,0 +1,54 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { ClimbDiscipline, ClimbingGrade, GradePyramid, GradeSystem, LogbookEntry, LogbookEntryDto, SendStyle } from '../types';
+
+export interface LogbookFilters {
+  discipline?: ClimbDiscipline;
+  style?: SendStyle;
+  sessionId?: number;
+  outdoor?: boolean;
+  from?: string;
+  to?: string;
+}
+
+export const LogbookService = {
+  /**
+   * List a user's climbs, most recent first. With a system, grades are also given in it.
+   */
+  async getEntries(
+    userId: number,
+    filters: LogbookFilters = {},
+    system?: GradeSystem,
+    limit = 100,
+    offset = 0,
+  ): Promise<ApiResponse<LogbookEntry[]>> {
+    return apiClient.get(`/users/${userId}/logbook`, { params: { ...filters, system, limit, offset } });
+  },
+
+  async createEntry(userId: number, entry: LogbookEntryDto): Promise<ApiResponse<LogbookEntry>> {
+    return apiClient.post(`/users/${userId}/logbook`, entry);
+  },
+
+  async updateEntry(userId: number, id: number, entry: LogbookEntryDto): Promise<ApiResponse<LogbookEntry>> {
+    return apiClient.put(`/users/${userId}/logbook/${id}`, entry);
+  },
+
+  async deleteEntry(userId: number, id: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/users/${userId}/logbook/${id}`);
+  },
+
+  /**
+   * Sends per grade in one system, hardest first; the other system of the discipline is converted
+   */
+  async getPyramid(
+    userId: number,
+    system: GradeSystem,
+    filters: Omit<LogbookFilters, 'discipline'> = {},
+  ): Promise<ApiResponse<GradePyramid>> {
+    return apiClient.get(`/users/${userId}/logbook/pyramid`, { params: { ...filters, system } });
+  },
+
+  async getGrades(system?: GradeSystem): Promise<ApiResponse<ClimbingGrade[]>> {
+    return apiClient.get('/grades', { params: { system } });
+  },
+};
@@ -1,10 +1,28 @@
-import apiClient from './client';
+import apiClient, { API_BASE_URL } from './client';
 import { ApiResponse } from './errorHandler';
-import { Plan, CreatePlanDto, UpdatePlanDto } from '../types';
+import {
+  Plan,
+  CreatePlanDto,
+  UpdatePlanDto,
+  PlanFormat,
+  PlanImportResult,
+  GeneratePlanDto,
+  PlanCollaborator,
+  PlanPermission,
+  PlanShareLink,
+  CreatePlanShareLinkDto,
+  PlanVersion,
+  PlanVersionDiff,
+  PlanComment,
+  CreatePlanCommentDto,
+  PlanCommentFilters,
+} from '../types';
 
 interface PlanFilters {
   id?: number;
   userId?: number;
+  // true lists only the plans shared with the user, false only their own
+  shared?: boolean;
 }
 
 interface PaginationParams {
@@ -36,4 +54,115 @@ export const PlanService = {
   async deletePlan(id: number): Promise<ApiResponse<void>> {
     return apiClient.delete(`/plans/${id}`);
   },
+
+  getPlanExportUrl(id: number, format: PlanFormat = 'json'): string {
+    return `${API_BASE_URL}/plans/${id}/export?format=${format}`;
+  },
+
+  async importPlan(userId: number, file: File): Promise<ApiResponse<PlanImportResult>> {
+    const format: PlanFormat = file.name.toLowerCase().endsWith('.txt') ? 'text' : 'json';
+    return apiClient.post('/plans/import', await file.text(), {
+      params: { userId, format },
+      headers: { 'Content-Type': format === 'text' ? 'text/plain' : 'application/json' },
+    });
+  },
+
+  /**
+   * Generate a periodized plan from a goal, length, sessions per week and model
+   */
+  async generatePlan(settings: GeneratePlanDto): Promise<ApiResponse<PlanImportResult>> {
+    return apiClient.post('/plans/generate', settings);
+  },
+
+  async getCollaborators(planId: number): Promise<ApiResponse<PlanCollaborator[]>> {
+    return apiClient.get(`/plans/${planId}/collaborators`);
+  },
+
+  /**
+   * Share the plan with a user, or change their permission; returns every collaborator
+   */
+  async setCollaborator(planId: number, userId: number, permission: PlanPermission): Promise<ApiResponse<PlanCollaborator[]>> {
+    return apiClient.put(`/plans/${planId}/collaborators/${userId}`, { permission });
+  },
+
+  async removeCollaborator(planId: number, userId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/plans/${planId}/collaborators/${userId}`);
+  },
+
+  async getShareLinks(planId: number): Promise<ApiResponse<PlanShareLink[]>> {
+    return apiClient.get(`/plans/${planId}/share-links`);
+  },
+
+  async createShareLink(planId: number, link: CreatePlanShareLinkDto): Promise<ApiResponse<PlanShareLink>> {
+    return apiClient.post(`/plans/${planId}/share-links`, link);
+  },
+
+  /**
+   * Revoke a link; everyone who joined through it loses their access
+   */
+  async revokeShareLink(planId: number, linkId: number): Promise<ApiResponse<PlanShareLink>> {
+    return apiClient.delete(`/plans/${planId}/share-links/${linkId}`);
+  },
+
+  /**
+   * Join a plan through a share link as the acting user
+   */
+  async acceptShareLink(token: string): Promise<ApiResponse<Plan>> {
+    return apiClient.post(`/share-links/${token}/accept`);
+  },
+
+  async getVersions(
+    planId: number,
+    pagination: PaginationParams = { limit: 20, offset: 0 },
+  ): Promise<ApiResponse<PlanVersion[]>> {
+    return apiClient.get(`/plans/${planId}/versions`, { params: pagination });
+  },
+
+  async getVersion(planId: number, number: number): Promise<ApiResponse<PlanVersion>> {
+    return apiClient.get(`/plans/${planId}/versions/${number}`);
+  },
+
+  /**
+   * Compare two versions, or a version with the plan as it is now when to is left out
+   */
+  async diffVersions(planId: number, from: number, to?: number): Promise<ApiResponse<PlanVersionDiff>> {
+    return apiClient.get(`/plans/${planId}/versions/diff`, { params: { from, to } });
+  },
+
+  /**
+   * Bring the plan back to a version; the result is recorded as a new version
+   */
+  async restoreVersion(planId: number, number: number): Promise<ApiResponse<PlanVersion>> {
+    return apiClient.post(`/plans/${planId}/versions/${number}/restore`);
+  },
+
+  /**
+   * List the threads on a plan, each with its replies
+   */
+  async getComments(
+    planId: number,
+    filters: PlanCommentFilters = {},
+    pagination: PaginationParams = { limit: 50, offset: 0 },
+  ): Promise<ApiResponse<PlanComment[]>> {
+    return apiClient.get(`/plans/${planId}/comments`, { params: { ...filters, ...pagination } });
+  },
+
+  async createComment(planId: number, data: CreatePlanCommentDto): Promise<ApiResponse<PlanComment>> {
+    return apiClient.post(`/plans/${planId}/comments`, data);
+  },
+
+  async updateComment(planId: number, commentId: number, body: string): Promise<ApiResponse<PlanComment>> {
+    return apiClient.put(`/plans/${planId}/comments/${commentId}`, { body });
+  },
+
+  /**
+   * Delete a comment; deleting the comment that started a thread deletes its replies
+   */
+  async deleteComment(planId: number, commentId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/plans/${planId}/comments/${commentId}`);
+  },
+
+  async resolveComment(planId: number, commentId: number, resolved: boolean): Promise<ApiResponse<PlanComment>> {
+    return apiClient.put(`/plans/${planId}/comments/${commentId}/resolved`, { resolved });
+  },
 };
@@ -1,11 +1,22 @@
 import apiClient from './client';
 import { ApiResponse } from './errorHandler';
-import { IntervalExercisePrescription, CreatePrescriptionDto } from '../types';
+import {
+  IntervalExercisePrescription,
+  CreatePrescriptionDto,
+  UpdatePrescriptionDto,
+  PrescriptionBlock,
+  CreatePrescriptionBlockDto,
+  UpdatePrescriptionBlockDto,
+} from '../types';
 
 export interface PrescriptionFilters {
   groupId?: number;
   intervalId?: number;
   id?: number;
+  // Resolve percentages for an athlete as of a date (YYYY-MM-DD), or for a session
+  userId?: number;
+  date?: string;
+  sessionId?: number;
 }
 
 interface PaginationParams {
@@ -41,10 +52,50 @@ export const PrescriptionService = {
     return apiClient.post('/interval-exercise-prescriptions', prescriptionData);
   },
 
+  /**
+   * Update a prescription, omitted fields keep their value
+   */
+  async updatePrescription(id: number, prescriptionData: UpdatePrescriptionDto): Promise<ApiResponse<IntervalExercisePrescription>> {
+    return apiClient.put(`/interval-exercise-prescriptions/${id}`, prescriptionData);
+  },
+
   /**
    * Delete a prescription
    */
   async deletePrescription(id: number): Promise<ApiResponse<void>> {
     return apiClient.delete(`/interval-exercise-prescriptions/${id}`);
+  },
+
+  /**
+   * Set the order of a group's prescriptions within an interval
+   */
+  async reorderPrescriptions(
+    groupId: number,
+    planIntervalId: number,
+    prescriptionIds: number[],
+  ): Promise<ApiResponse<IntervalExercisePrescription[]>> {
+    return apiClient.put('/interval-exercise-prescriptions/order', { groupId, planIntervalId, prescriptionIds });
+  },
+
+  async getPrescriptionBlocks(filters: { groupId?: number; intervalId?: number }): Promise<ApiResponse<PrescriptionBlock[]>> {
+    return apiClient.get('/prescription-blocks', { params: filters });
+  },
+
+  /**
+   * Link prescriptions into a superset or circuit
+   */
+  async createPrescriptionBlock(blockData: CreatePrescriptionBlockDto): Promise<ApiResponse<PrescriptionBlock>> {
+    return apiClient.post('/prescription-blocks', blockData);
+  },
+
+  async updatePrescriptionBlock(id: number, blockData: UpdatePrescriptionBlockDto): Promise<ApiResponse<PrescriptionBlock>> {
+    return apiClient.put(`/prescription-blocks/${id}`, blockData);
+  },
+
+  /**
+   * Unlink a superset or circuit, its prescriptions keep their order
+   */
+  async deletePrescriptionBlock(id: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/prescription-blocks/${id}`);
   }
 };
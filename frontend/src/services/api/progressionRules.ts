@@ -0,0 +1,29 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { ProgressionRule, CreateProgressionRuleDto, ProgressionStepDto } from '../types';
+
+export interface ProgressionRuleFilters {
+  prescriptionId?: number;
+  groupId?: number;
+}
+
+export const ProgressionRuleService = {
+  async getProgressionRules(filters: ProgressionRuleFilters): Promise<ApiResponse<ProgressionRule[]>> {
+    return apiClient.get('/progression-rules', { params: filters });
+  },
+
+  async createProgressionRule(rule: CreateProgressionRuleDto): Promise<ApiResponse<ProgressionRule>> {
+    return apiClient.post('/progression-rules', rule);
+  },
+
+  /**
+   * Replace the steps of a rule
+   */
+  async updateProgressionRule(id: number, steps: ProgressionStepDto[]): Promise<ApiResponse<ProgressionRule>> {
+    return apiClient.put(`/progression-rules/${id}`, { steps });
+  },
+
+  async deleteProgressionRule(id: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/progression-rules/${id}`);
+  },
+};
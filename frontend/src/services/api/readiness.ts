@@ -0,0 +1,42 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { ReadinessCheckin, ReadinessCheckinDto, ReadinessRecommendation, ReadinessTrendDay } from '../types';
+
+export interface ReadinessFilters {
+  from?: string;
+  to?: string;
+}
+
+export const ReadinessService = {
+  /**
+   * List a user's check-ins with their scores, most recent first
+   */
+  async getCheckins(userId: number, filters: ReadinessFilters = {}, limit = 100, offset = 0): Promise<ApiResponse<ReadinessCheckin[]>> {
+    return apiClient.get(`/users/${userId}/readiness`, { params: { ...filters, limit, offset } });
+  },
+
+  /**
+   * Save the check-in for a date (YYYY-MM-DD), replacing one already given that day
+   */
+  async saveCheckin(userId: number, date: string, checkin: ReadinessCheckinDto): Promise<ApiResponse<ReadinessCheckin>> {
+    return apiClient.put(`/users/${userId}/readiness/${date}`, checkin);
+  },
+
+  async deleteCheckin(userId: number, date: string): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/users/${userId}/readiness/${date}`);
+  },
+
+  /**
+   * Daily readiness with weekly averages; defaults to the four weeks ending today
+   */
+  async getTrend(userId: number, filters: ReadinessFilters = {}): Promise<ApiResponse<ReadinessTrendDay[]>> {
+    return apiClient.get(`/users/${userId}/readiness/trend`, { params: filters });
+  },
+
+  /**
+   * Changes to a session recommended by the check-in of its day
+   */
+  async getRecommendation(sessionId: number): Promise<ApiResponse<ReadinessRecommendation>> {
+    return apiClient.get(`/sessions/${sessionId}/recommendation`);
+  },
+};
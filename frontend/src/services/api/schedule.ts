@@ -0,0 +1,117 @@
+import apiClient, { API_BASE_URL } from './client';
+import { ApiResponse } from './errorHandler';
+import {
+  CalendarFeed,
+  PersonalRecord,
+  PersonalRecordKind,
+  PlanAssignment,
+  ScheduledSession,
+  ScheduledSessionStatus,
+  SessionCompletion,
+  SessionRating,
+  SessionSetDto,
+} from '../types';
+
+export interface SessionFilters {
+  userId?: number;
+  planId?: number;
+  assignmentId?: number;
+  from?: string;
+  to?: string;
+  status?: ScheduledSessionStatus;
+}
+
+export interface PersonalRecordFilters {
+  userId?: number;
+  exerciseVariationId?: number;
+  parameterTypeId?: number;
+  kind?: PersonalRecordKind;
+  // Include the records that have since been beaten
+  history?: boolean;
+}
+
+export interface PlanAssignmentInput {
+  planId: number;
+  userId: number;
+  startDate: string;
+  trainingDays?: number[];
+  minRestDays?: number;
+}
+
+export const ScheduleService = {
+  async generateSchedule(planId: number): Promise<ApiResponse<ScheduledSession[]>> {
+    return apiClient.post(`/plans/${planId}/schedule`);
+  },
+
+  async getSessions(planId: number, userId?: number): Promise<ApiResponse<ScheduledSession[]>> {
+    return apiClient.get(`/plans/${planId}/sessions`, { params: { userId } });
+  },
+
+  // Sessions from today onwards unless `from` says otherwise
+  async getUpcomingSessions(filters: SessionFilters = {}, limit = 50, offset = 0): Promise<ApiResponse<ScheduledSession[]>> {
+    return apiClient.get('/sessions', { params: { ...filters, limit, offset } });
+  },
+
+  async rescheduleSession(sessionId: number, date: string): Promise<ApiResponse<ScheduledSession>> {
+    return apiClient.put(`/sessions/${sessionId}`, { date });
+  },
+
+  async skipSession(sessionId: number): Promise<ApiResponse<ScheduledSession>> {
+    return apiClient.post(`/sessions/${sessionId}/skip`);
+  },
+
+  // Logs the performed sets; the response carries any personal records they set
+  async completeSession(
+    sessionId: number,
+    sets: SessionSetDto[],
+    date?: string,
+    rating: SessionRating = {},
+  ): Promise<ApiResponse<SessionCompletion>> {
+    return apiClient.post(`/sessions/${sessionId}/complete`, { sets, date, ...rating });
+  },
+
+  async getPersonalRecords(filters: PersonalRecordFilters = {}, limit = 100, offset = 0): Promise<ApiResponse<PersonalRecord[]>> {
+    return apiClient.get('/records', { params: { ...filters, limit, offset } });
+  },
+
+  async getPlanAssignments(filters: { planId?: number; userId?: number } = {}): Promise<ApiResponse<PlanAssignment[]>> {
+    return apiClient.get('/plan-assignments', { params: filters });
+  },
+
+  async createPlanAssignment(input: PlanAssignmentInput): Promise<ApiResponse<PlanAssignment>> {
+    return apiClient.post('/plan-assignments', input);
+  },
+
+  async updatePlanAssignment(
+    assignmentId: number,
+    input: Partial<Omit<PlanAssignmentInput, 'planId' | 'userId'>>
+  ): Promise<ApiResponse<PlanAssignment>> {
+    return apiClient.put(`/plan-assignments/${assignmentId}`, input);
+  },
+
+  async deletePlanAssignment(assignmentId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/plan-assignments/${assignmentId}`);
+  },
+
+  // Moves missed sessions and everything after them forward from `from` (today by default)
+  async reflowPlanAssignment(assignmentId: number, from?: string): Promise<ApiResponse<ScheduledSession[]>> {
+    return apiClient.post(`/plan-assignments/${assignmentId}/reflow`, undefined, { params: { from } });
+  },
+
+  async getCalendarFeeds(userId: number): Promise<ApiResponse<CalendarFeed[]>> {
+    return apiClient.get(`/users/${userId}/calendar-feeds`);
+  },
+
+  async createCalendarFeed(userId: number): Promise<ApiResponse<CalendarFeed>> {
+    return apiClient.post(`/users/${userId}/calendar-feeds`);
+  },
+
+  async revokeCalendarFeed(userId: number, feedId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/users/${userId}/calendar-feeds/${feedId}`);
+  },
+
+  // The address calendar apps subscribe to
+  getCalendarFeedUrl(feed: CalendarFeed): string {
+    return new URL(feed.path, API_BASE_URL).toString();
+  },
+};
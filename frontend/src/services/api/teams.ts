@@ -0,0 +1,87 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import {
+  AssignTeamPlanDto,
+  Plan,
+  Team,
+  TeamInvitation,
+  TeamInvitationStatus,
+  TeamPlanAssignment,
+  TeamRole,
+} from '../types';
+
+export const TeamsService = {
+  /**
+   * The teams a user belongs to with their role in each
+   */
+  async getUserTeams(userId: number): Promise<ApiResponse<Team[]>> {
+    return apiClient.get(`/users/${userId}/teams`);
+  },
+
+  async getUserInvitations(userId: number, status?: TeamInvitationStatus): Promise<ApiResponse<TeamInvitation[]>> {
+    return apiClient.get(`/users/${userId}/invitations`, { params: { status } });
+  },
+
+  /**
+   * Create a team owned by the acting user
+   */
+  async createTeam(name: string): Promise<ApiResponse<Team>> {
+    return apiClient.post('/teams', { name });
+  },
+
+  async getTeam(teamId: number): Promise<ApiResponse<Team>> {
+    return apiClient.get(`/teams/${teamId}`);
+  },
+
+  async updateTeam(teamId: number, name: string): Promise<ApiResponse<Team>> {
+    return apiClient.put(`/teams/${teamId}`, { name });
+  },
+
+  async deleteTeam(teamId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/teams/${teamId}`);
+  },
+
+  async updateMember(teamId: number, userId: number, role: TeamRole): Promise<ApiResponse<Team>> {
+    return apiClient.put(`/teams/${teamId}/members/${userId}`, { role });
+  },
+
+  /**
+   * Remove a member; members may also remove themselves to leave the team
+   */
+  async removeMember(teamId: number, userId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/teams/${teamId}/members/${userId}`);
+  },
+
+  async getInvitations(teamId: number, status?: TeamInvitationStatus): Promise<ApiResponse<TeamInvitation[]>> {
+    return apiClient.get(`/teams/${teamId}/invitations`, { params: { status } });
+  },
+
+  /**
+   * Invite a user; coaches may only invite athletes
+   */
+  async invite(teamId: number, userId: number, role: TeamRole): Promise<ApiResponse<TeamInvitation>> {
+    return apiClient.post(`/teams/${teamId}/invitations`, { userId, role });
+  },
+
+  async acceptInvitation(invitationId: number): Promise<ApiResponse<TeamInvitation>> {
+    return apiClient.post(`/invitations/${invitationId}/accept`);
+  },
+
+  async declineInvitation(invitationId: number): Promise<ApiResponse<TeamInvitation>> {
+    return apiClient.post(`/invitations/${invitationId}/decline`);
+  },
+
+  /**
+   * Give each athlete a linked copy of the plan, scheduled from the start date when given
+   */
+  async assignPlan(teamId: number, assignment: AssignTeamPlanDto): Promise<ApiResponse<TeamPlanAssignment[]>> {
+    return apiClient.post(`/teams/${teamId}/assignments`, assignment);
+  },
+
+  /**
+   * The copies made of a plan, e.g. the ones assigned to a team's athletes
+   */
+  async getPlanCopies(planId: number): Promise<ApiResponse<Plan[]>> {
+    return apiClient.get(`/plans/${planId}/copies`);
+  },
+};
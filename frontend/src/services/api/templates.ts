@@ -0,0 +1,43 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { Plan, PlanRating, Template, TemplateSort } from '../types';
+
+export interface TemplateFilters {
+  search?: string;
+  // Name of a category of the exercises the template prescribes
+  category?: string;
+  sort?: TemplateSort;
+  limit?: number;
+  offset?: number;
+}
+
+export const TemplatesService = {
+  /**
+   * Browse the public templates of all users
+   */
+  async getTemplates(filters: TemplateFilters = {}): Promise<ApiResponse<Template[]>> {
+    return apiClient.get('/templates', { params: filters });
+  },
+
+  async getTemplate(templateId: number): Promise<ApiResponse<Template>> {
+    return apiClient.get(`/templates/${templateId}`);
+  },
+
+  /**
+   * Rate a template with one to five stars, replacing the user's earlier rating
+   */
+  async rateTemplate(templateId: number, userId: number, stars: number): Promise<ApiResponse<PlanRating>> {
+    return apiClient.put(`/templates/${templateId}/rating`, { userId, stars });
+  },
+
+  async removeRating(templateId: number, userId: number): Promise<ApiResponse<void>> {
+    return apiClient.delete(`/templates/${templateId}/rating`, { params: { userId } });
+  },
+
+  /**
+   * Copy a template into the user's account as a private plan linked to the template
+   */
+  async forkTemplate(templateId: number, userId: number): Promise<ApiResponse<Plan>> {
+    return apiClient.post(`/templates/${templateId}/fork`, { userId });
+  },
+};
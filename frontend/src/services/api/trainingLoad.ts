@@ -0,0 +1,24 @@
+import apiClient from './client';
+import { ApiResponse } from './errorHandler';
+import { TrainingLoad, TrainingLoadThresholds } from '../types';
+
+export const TrainingLoadService = {
+  /**
+   * Daily training load between two dates, flagged against the user's thresholds. `to`
+   * defaults to today and `from` to four weeks before it.
+   */
+  async getTrainingLoad(userId: number, from?: string, to?: string): Promise<ApiResponse<TrainingLoad>> {
+    return apiClient.get('/analytics/training-load', { params: { userId, from, to } });
+  },
+
+  async getThresholds(userId: number): Promise<ApiResponse<TrainingLoadThresholds>> {
+    return apiClient.get(`/users/${userId}/training-load-thresholds`);
+  },
+
+  async setThresholds(
+    userId: number,
+    thresholds: Omit<TrainingLoadThresholds, 'custom'>,
+  ): Promise<ApiResponse<TrainingLoadThresholds>> {
+    return apiClient.put(`/users/${userId}/training-load-thresholds`, thresholds);
+  },
+};
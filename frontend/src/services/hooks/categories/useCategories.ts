@@ -0,0 +1,25 @@
+import { useQuery } from '@tanstack/react-query';
+import { CategoryFilters, CategoryService } from '../../api/categories';
+import { isApiError } from '../../api/errorHandler';
+import { Category } from '../../types';
+import { createCategoryCacheKey } from './utils';
+
+/**
+ * Hook to fetch exercise categories
+ * @param filters Filter criteria (id, userId, exerciseId)
+ * @param options Additional react-query options
+ */
+export const useCategories = (filters: CategoryFilters = {}, options = {}) => {
+  return useQuery({
+    queryKey: createCategoryCacheKey({ filters }),
+    queryFn: async () => {
+      const response = await CategoryService.getCategories(filters);
+      if (isApiError(response)) {
+        throw response.error;
+      }
+      const cacheValue = response.data || ([] as Category[]);
+      return cacheValue;
+    },
+    ...options,
+  });
+};
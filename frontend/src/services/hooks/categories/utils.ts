@@ -0,0 +1,8 @@
+import { CategoryFilters } from '@/services/api/categories';
+
+export const QUERY_KEY = 'categories';
+
+export const createCategoryCacheKey = (args: { filters: CategoryFilters }) => [
+  QUERY_KEY,
+  args.filters,
+];
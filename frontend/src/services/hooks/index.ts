@@ -5,3 +5,4 @@ export * from './exercises';
 export * from './exerciseVariations';
 export * from './prescriptions';
 export * from './parameterTypes';
+export * from './categories';
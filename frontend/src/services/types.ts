@@ -5,6 +5,12 @@ export interface BaseEntity {
   updatedAt: string;
 }
 
+// Comments on a record and its unresolved threads, for badges; left out when there are none
+export interface CommentCounts {
+  commentCount?: number;
+  unresolvedThreadCount?: number;
+}
+
 // Pagination Params
 export interface PaginationParams {
   page?: number;
@@ -12,12 +18,183 @@ export interface PaginationParams {
 }
 
 // Plan Types
-export interface Plan extends BaseEntity {
+export interface Plan extends BaseEntity, CommentCounts {
   name: string;
   description: string;
   duration?: number;
   schedule?: string;
   userId: number;
+  startDate?: string;
+  // The plan this one was copied from, e.g. when a coach assigned it
+  sourcePlanId?: number;
+  // What the user may do with a plan shared with them
+  permission?: PlanPermission;
+}
+
+// Plan sharing. A share link gives whoever accepts it its permission until it expires
+// or is revoked; expiresAt is an RFC 3339 time.
+export type PlanPermission = 'view' | 'comment' | 'edit';
+
+export interface PlanCollaborator {
+  planId: number;
+  userId: number;
+  permission: PlanPermission;
+  email: string;
+  firstName: string;
+  lastName: string;
+  shareLinkId?: number;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface PlanShareLink {
+  id: number;
+  planId: number;
+  token: string;
+  permission: PlanPermission;
+  createdBy?: number;
+  expiresAt?: string;
+  revokedAt?: string;
+  active: boolean;
+  createdAt: string;
+}
+
+export interface CreatePlanShareLinkDto {
+  permission: PlanPermission;
+  expiresAt?: string;
+}
+
+// Template gallery. Forking a template copies it into the user's account as a private
+// plan whose sourcePlanId links back to it.
+export type TemplateSort = 'recent' | 'popular';
+
+export interface TemplateInterval {
+  id: number;
+  name: string;
+  duration: string;
+  order: number;
+  isDeload: boolean;
+  groups: string[];
+  prescriptionCount: number;
+}
+
+export interface Template extends BaseEntity {
+  name: string;
+  description?: string;
+  userId: number;
+  authorName: string;
+  forkCount: number;
+  ratingCount: number;
+  // Left out until the template is rated
+  averageRating?: number;
+  intervals: TemplateInterval[];
+}
+
+export interface PlanRating {
+  planId: number;
+  userId: number;
+  stars: number;
+  createdAt: string;
+  updatedAt: string;
+}
+
+// Snapshot of a plan's tree recorded when it changed; restoredFrom is set on versions
+// a restore created. The document is only included when a single version is fetched.
+export interface PlanVersion {
+  id: number;
+  planId: number;
+  number: number;
+  createdBy?: number;
+  createdByName?: string;
+  restoredFrom?: number;
+  createdAt: string;
+  document?: unknown;
+}
+
+export interface PlanFieldChange {
+  field: string;
+  from: unknown;
+  to: unknown;
+}
+
+export interface PlanIntervalRef {
+  id?: number;
+  order: number;
+  name: string;
+}
+
+export interface PlanPrescriptionRef {
+  group: string;
+  exercise: string;
+  variation: string;
+}
+
+export interface PlanIntervalChanges extends PlanIntervalRef {
+  fields: PlanFieldChange[];
+  groupsAdded: string[];
+  groupsRemoved: string[];
+  groupsChanged: { group: string; fields: PlanFieldChange[] }[];
+  prescriptionsAdded: PlanPrescriptionRef[];
+  prescriptionsRemoved: PlanPrescriptionRef[];
+  prescriptionsChanged: (PlanPrescriptionRef & { fields: PlanFieldChange[] })[];
+}
+
+// Intervals are matched by ID, or by order in older versions, groups by name and
+// prescriptions by exercise and variation
+export interface PlanChanges {
+  plan: PlanFieldChange[];
+  intervalsAdded: PlanIntervalRef[];
+  intervalsRemoved: PlanIntervalRef[];
+  intervalsChanged: PlanIntervalChanges[];
+}
+
+// to is left out when the version is compared with the plan as it is now
+export interface PlanVersionDiff {
+  from: number;
+  to?: number;
+  changes: PlanChanges;
+}
+
+// Comment on a plan, or on one of its intervals, groups or prescriptions. Threads are one
+// level deep: replies carry the parentId of the comment that started the thread, and only
+// that comment is resolved.
+export interface PlanComment extends BaseEntity {
+  planId: number;
+  intervalId?: number;
+  groupId?: number;
+  prescriptionId?: number;
+  parentId?: number;
+  userId: number;
+  authorName: string;
+  body: string;
+  mentions: CommentMention[];
+  resolved: boolean;
+  resolvedAt?: string;
+  resolvedBy?: number;
+  editedAt?: string;
+  replies?: PlanComment[];
+}
+
+export interface CommentMention {
+  userId: number;
+  name: string;
+}
+
+// Collaborators are mentioned in the body by @ and the part of their email before the @
+export interface CreatePlanCommentDto {
+  userId: number;
+  body: string;
+  intervalId?: number;
+  groupId?: number;
+  prescriptionId?: number;
+  parentId?: number;
+}
+
+export interface PlanCommentFilters {
+  intervalId?: number;
+  groupId?: number;
+  prescriptionId?: number;
+  resolved?: boolean;
 }
 
 export interface CreatePlanDto {
@@ -26,20 +203,351 @@ export interface CreatePlanDto {
   userId: number;
   duration?: number;
   schedule?: string;
+  startDate?: string;
 }
 
 export interface UpdatePlanDto {
   name: string;
   description: string;
+  // Omit to keep the current start date, or send an empty string to clear it
+  startDate?: string;
+}
+
+// Schedule Types
+export type ScheduledSessionStatus = 'scheduled' | 'completed' | 'skipped';
+
+export interface ScheduledSession {
+  id: number;
+  planId: number;
+  planAssignmentId: number;
+  userId: number;
+  planIntervalId: number;
+  groupId: number;
+  planName: string;
+  groupName: string;
+  intervalName?: string;
+  date: string;
+  originalDate: string;
+  rescheduled: boolean;
+  status: ScheduledSessionStatus;
+}
+
+// trainingDays are weekdays from 0 (Sunday) to 6; an empty list allows every day
+export interface PlanAssignment {
+  id: number;
+  planId: number;
+  userId: number;
+  startDate: string;
+  trainingDays: number[];
+  minRestDays: number;
+  createdAt: string;
+  updatedAt: string;
+}
+
+// An athlete's measured best, e.g. a 1RM or a max hang. Without an exercise variation it
+// applies to every variation, like bodyweight.
+export interface Benchmark {
+  id: number;
+  userId: number;
+  exerciseVariationId: number | null;
+  parameterTypeId: number;
+  value: number;
+  recordedOn: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface BenchmarkDto {
+  exerciseVariationId?: number;
+  parameterTypeId: number;
+  value: number;
+  // YYYY-MM-DD, today when left out
+  recordedOn?: string;
+}
+
+// Personal records, detected when a session is completed. Beaten records are kept as
+// history; `load` is the load a most reps record was done at.
+export type PersonalRecordKind = 'heaviest_load' | 'longest_duration' | 'most_reps' | 'estimated_max';
+
+export interface PersonalRecord {
+  id: number;
+  userId: number;
+  exerciseVariationId: number;
+  parameterTypeId: number;
+  kind: PersonalRecordKind;
+  value: number;
+  previousValue: number | null;
+  load?: number;
+  reps?: number;
+  sessionSetId?: number;
+  achievedOn: string;
+  createdAt: string;
+}
+
+// A set as it was performed
+export interface SessionSetDto {
+  exerciseVariationId: number;
+  reps?: number;
+  rpe?: number;
+  parameters: Array<{ parameterTypeId: number; value: number }>;
+}
+
+// How hard and long a whole session was. The RPE defaults to the average of the sets';
+// the duration is a string like "75 minutes".
+export interface SessionRating {
+  rpe?: number;
+  duration?: string;
+}
+
+export interface SessionCompletion {
+  session: ScheduledSession;
+  sets: number;
+  // Session RPE times minutes, null without an RPE or duration
+  load: number | null;
+  records: PersonalRecord[];
+}
+
+// Training load. Acute is the last 7 days' load, chronic the average week of the last 28.
+export type TrainingLoadFlag = 'acwr_high' | 'acwr_low' | 'monotony_high' | 'strain_high' | 'readiness_low';
+
+export interface TrainingLoadDay {
+  date: string;
+  load: number;
+  acute: number;
+  chronic: number;
+  acwr: number | null;
+  monotony: number | null;
+  strain: number | null;
+  // Score of the day's readiness check-in
+  readiness: number | null;
+  flags: TrainingLoadFlag[];
+}
+
+export interface TrainingLoadThresholds {
+  acwrHigh: number;
+  acwrLow: number;
+  monotony: number;
+  strain: number;
+  // False while the athlete uses the defaults
+  custom?: boolean;
+}
+
+export interface TrainingLoad {
+  userId: number;
+  from: string;
+  to: string;
+  thresholds: TrainingLoadThresholds;
+  // Completed sessions without an RPE or duration, which add no load
+  unrated: number;
+  days: TrainingLoadDay[];
+}
+
+// Daily readiness check-ins. Every measure is optional; soreness maps body areas such as
+// "fingers" or "elbows" to 0-10, finger pain is 0-10 and mood 1-5. The score is 0-100.
+export interface ReadinessCheckinDto {
+  sleepHours?: number;
+  soreness?: Record<string, number>;
+  fingerPain?: number;
+  mood?: number;
+  restingHr?: number;
+}
+
+export interface ReadinessCheckin {
+  id: number;
+  userId: number;
+  checkedOn: string;
+  sleepHours: number | null;
+  soreness: Record<string, number>;
+  fingerPain: number | null;
+  mood: number | null;
+  restingHr: number | null;
+  score: number | null;
+  createdAt: string;
+  updatedAt: string;
+}
+
+// A day's readiness with the averages of the week ending on it
+export interface ReadinessTrendDay {
+  date: string;
+  score: number | null;
+  scoreAverage: number | null;
+  sleepAverage: number | null;
+  fingerPainAverage: number | null;
+  restingHrAverage: number | null;
+}
+
+export type ReadinessActionKind = 'rest' | 'reduce_intensity' | 'swap_group';
+
+export interface ReadinessAction {
+  kind: ReadinessActionKind;
+  reason: string;
+  intensityFactor?: number;
+  groupId?: number;
+  groupName?: string;
+}
+
+export interface ReadinessRecommendation {
+  sessionId: number;
+  date: string;
+  checkin: ReadinessCheckin | null;
+  actions: ReadinessAction[];
+}
+
+// Climbing logbook. Grades are kept in the system they were given in; `convertedGrade`
+// is the grade in the system a listing asked for.
+export type GradeSystem = 'v' | 'font' | 'yds' | 'french';
+export type ClimbDiscipline = 'boulder' | 'route';
+export type SendStyle = 'onsight' | 'flash' | 'redpoint' | 'repeat';
+
+export interface LogbookEntry {
+  id: number;
+  userId: number;
+  scheduledSessionId: number | null;
+  name: string;
+  discipline: ClimbDiscipline;
+  gradeSystem: GradeSystem;
+  grade: string;
+  convertedGrade?: string;
+  style: SendStyle;
+  attempts: number;
+  outdoor: boolean;
+  climbedOn: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface LogbookEntryDto {
+  scheduledSessionId?: number;
+  name?: string;
+  gradeSystem: GradeSystem;
+  grade: string;
+  style: SendStyle;
+  // 1 when left out; onsights and flashes take a single attempt
+  attempts?: number;
+  outdoor?: boolean;
+  // YYYY-MM-DD, today when left out
+  climbedOn?: string;
+}
+
+export interface GradePyramid {
+  discipline: ClimbDiscipline;
+  system: GradeSystem;
+  maxGrade?: string;
+  total: number;
+  levels: Array<{ grade: string; count: number }>;
+}
+
+export interface ClimbingGrade {
+  system: GradeSystem;
+  discipline: ClimbDiscipline;
+  grade: string;
+  conversions: Partial<Record<GradeSystem, string>>;
+}
+
+export interface CalendarFeed {
+  id: number;
+  userId: number;
+  token: string;
+  path: string;
+  createdAt: string;
+}
+
+// Teams. Owners and coaches read the data of the team's athletes and assign them plans;
+// athletes never see each other's data. Requests act as the user named by the bearer token.
+export type TeamRole = 'owner' | 'coach' | 'athlete';
+export type TeamInvitationStatus = 'pending' | 'accepted' | 'declined';
+
+export interface TeamMember {
+  userId: number;
+  role: TeamRole;
+  email: string;
+  firstName: string;
+  lastName: string;
+  joinedAt: string;
+}
+
+// role is the acting user's role in the team
+export interface Team extends BaseEntity {
+  name: string;
+  role?: TeamRole;
+  members?: TeamMember[];
+}
+
+export interface TeamInvitation extends BaseEntity {
+  teamId: number;
+  userId: number;
+  role: TeamRole;
+  invitedBy?: number;
+  status: TeamInvitationStatus;
+}
+
+export interface AssignTeamPlanDto {
+  planId: number;
+  athleteIds: number[];
+  startDate?: string;
+  trainingDays?: number[];
+  minRestDays?: number;
+}
+
+// The athlete's linked copy of the plan, scheduled when a start date was given
+export interface TeamPlanAssignment {
+  athleteId: number;
+  plan: Plan;
+  assignment?: PlanAssignment;
 }
 
 // Group Types
-export interface Group extends BaseEntity {
+export interface Group extends BaseEntity, CommentCounts {
   name: string;
   description: string;
   userId: number;
 }
 
+// pinnedDays are weekdays from 0 (Sunday) to 6; an empty list leaves the days to the scheduler
+export interface IntervalGroupAssignment {
+  id: number;
+  planIntervalId: number;
+  groupId: number;
+  frequency: number;
+  pinnedDays: number[];
+  order: number;
+  group?: Group;
+  planInterval?: PlanInterval;
+}
+
+export type TimelineSegmentKind = 'work' | 'rest' | 'transition';
+
+// One step of a workout timer; times are seconds from the start of the session
+export interface TimelineSegment {
+  kind: TimelineSegmentKind;
+  prescriptionId: number;
+  exercise: string;
+  variation: string;
+  set?: number;
+  rep?: number;
+  round?: number;
+  reps?: number;
+  startSeconds: number;
+  durationSeconds: number;
+  endSeconds: number;
+  // Untimed work lasts as long as the reps take
+  timed: boolean;
+}
+
+export interface WorkoutTimeline {
+  groupId: number;
+  planIntervalId: number;
+  segments: TimelineSegment[];
+  totalSeconds: number;
+  untimedSegments: number;
+}
+
+export interface IntervalAssignmentDto {
+  frequency?: number;
+  pinnedDays?: number[];
+  order?: number;
+}
+
 export interface CreateGroupDto {
   name: string;
   description: string;
@@ -51,12 +559,13 @@ export interface UpdateGroupDto {
 }
 
 // Plan Interval Types
-export interface PlanInterval extends BaseEntity {
+export interface PlanInterval extends BaseEntity, CommentCounts {
   planId: number;
   name: string;
   description: string;
   duration: string;
   order: number;
+  isDeload: boolean;
   createdAt: string;
   updatedAt: string;
   groupCount: number;
@@ -70,22 +579,118 @@ export interface CreatePlanIntervalDto {
   order: number;
 }
 
+// Percentages cut from sets, reps and load, and points cut from RPE
+export interface DeloadReductions {
+  sets?: number;
+  reps?: number;
+  load?: number;
+  rpe?: number;
+}
+
+export interface InsertDeloadDto {
+  name?: string;
+  duration?: string;
+  reductions?: DeloadReductions;
+  // Set parameters the load reduction applies to, all of them when empty
+  loadParameterTypeIds?: number[];
+}
+
 // Exercise Types
-export interface Exercise extends BaseEntity {
+export interface Category extends BaseEntity {
+  name: string;
+  icon: string;
+  userId: number | null;
+}
+
+export type Laterality = 'bilateral' | 'unilateral';
+
+export interface ExerciseMetadata {
+  primaryMuscleGroups: string[];
+  secondaryMuscleGroups: string[];
+  equipment: string[];
+  laterality: Laterality;
+}
+
+export interface Exercise extends BaseEntity, ExerciseMetadata {
   name: string;
   description: string;
   userId: number;
+  categories?: Category[];
+}
+
+export interface ExerciseMedia {
+  id: number;
+  exerciseId: number;
+  fileName: string;
+  contentType: string;
+  sizeBytes: number;
+  url: string;
+  thumbnailUrl: string | null;
+  expiresAt: string;
+  createdAt: string;
+}
+
+export type ExerciseLibraryFormat = 'json' | 'csv';
+
+export interface ExerciseLibraryImportIssue {
+  kind: 'invalid' | 'duplicate' | 'conflict' | 'mismatch';
+  blocking: boolean;
+  line?: number;
+  exercise?: string;
+  message: string;
+}
+
+export interface ExerciseLibraryImportReport {
+  dryRun: boolean;
+  valid: boolean;
+  exercises: string[];
+  variationsCreated: number;
+  parameterTypesCreated: string[];
+  parameterTypesReused: string[];
+  categoriesCreated: string[];
+  issues: ExerciseLibraryImportIssue[];
+}
+
+export type PlanFormat = 'json' | 'text';
+
+export interface PlanImportResult {
+  plan: Plan;
+  exercisesCreated: string[];
+  exercisesReused: string[];
+  variationsCreated: number;
+  parameterTypesCreated: string[];
+  parameterTypesReused: string[];
+}
+
+export type PeriodizationGoal = 'strength' | 'hypertrophy' | 'endurance' | 'power';
+
+export type PeriodizationModel = 'linear' | 'undulating' | 'block';
+
+export interface GeneratePlanDto {
+  userId: number;
+  name?: string;
+  description?: string;
+  goal: PeriodizationGoal;
+  model: PeriodizationModel;
+  weeks: number;
+  sessionsPerWeek: number;
+  // Every nth week is a deload; 0 or omitted for none
+  deloadEvery?: number;
+  // A parameter type with a starting load prescribes and progresses the load
+  exercises: { exerciseVariationId: number; parameterTypeId?: number; load?: number }[];
 }
 
-export interface CreateExerciseDto {
+export interface CreateExerciseDto extends Partial<ExerciseMetadata> {
   name: string;
   description: string;
   userId: number;
+  categoryIds?: number[];
 }
 
-export interface UpdateExerciseDto {
+export interface UpdateExerciseDto extends Partial<ExerciseMetadata> {
   name: string;
   description: string;
+  categoryIds?: number[];
 }
 
 // Exercise Variation Types
@@ -129,7 +734,7 @@ export interface CreateExerciseVariationDto {
 }
 
 // Interval Exercise Prescription Types
-export interface IntervalExercisePrescription extends BaseEntity {
+export interface IntervalExercisePrescription extends BaseEntity, CommentCounts {
   groupId: number;
   exerciseVariationId: number;
   planIntervalId: number;
@@ -141,9 +746,101 @@ export interface IntervalExercisePrescription extends BaseEntity {
   subReps: number | null;
   subWorkDuration: string | null;
   subRestDuration: string | null;
+  position: number;
+  blockId: number | null;
+  setScheme: PrescriptionSet[];
   exerciseVariation?: ExerciseVariation;
 }
 
+// One set of a prescription; missing values fall back to the prescription
+export interface PrescriptionSet {
+  setNumber: number;
+  reps: number | null;
+  duration: string | null;
+  rpe: number | null;
+  rest: string | null;
+  parameters: PrescriptionSetParameter[];
+}
+
+// A percentage is of the athlete's benchmark for the parameter. resolvedValue is only
+// present when prescriptions are listed for an athlete that has a benchmark.
+export interface PrescriptionSetParameter {
+  parameterTypeId: number;
+  value: number;
+  isPercentage?: boolean;
+  resolvedValue?: number;
+}
+
+export type PrescriptionSetDto = Partial<Omit<PrescriptionSet, 'setNumber'>>;
+
+export type ProgressionTarget = 'sets' | 'reps' | 'rpe' | 'duration' | 'parameter';
+
+export type ProgressionUnit = 'absolute' | 'percent';
+
+// One step of a progression rule; durations progress in seconds
+export interface ProgressionStep {
+  target: ProgressionTarget;
+  parameterTypeId: number | null;
+  amount: number;
+  unit: ProgressionUnit;
+  until: number | null;
+}
+
+// Applied to a prescription, or every prescription of a group, per copied interval
+export interface ProgressionRule {
+  id: number;
+  prescriptionId: number | null;
+  groupId: number | null;
+  steps: ProgressionStep[];
+  createdAt: string;
+  updatedAt: string;
+}
+
+export type ProgressionStepDto = Omit<ProgressionStep, 'parameterTypeId' | 'unit' | 'until'> &
+  Partial<Pick<ProgressionStep, 'parameterTypeId' | 'unit' | 'until'>>;
+
+export interface CreateProgressionRuleDto {
+  prescriptionId?: number;
+  groupId?: number;
+  steps: ProgressionStepDto[];
+}
+
+export interface ProgressionPreview {
+  planIntervalId: number;
+  intervals: {
+    step: number;
+    prescriptions: IntervalExercisePrescription[];
+  }[];
+}
+
+export type PrescriptionBlockKind = 'superset' | 'circuit';
+
+// Prescriptions performed together; rest is taken after each round
+export interface PrescriptionBlock {
+  id: number;
+  groupId: number;
+  planIntervalId: number;
+  kind: PrescriptionBlockKind;
+  rest: string | null;
+  prescriptionIds: number[];
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface CreatePrescriptionBlockDto {
+  groupId: number;
+  planIntervalId: number;
+  kind: PrescriptionBlockKind;
+  rest?: string | null;
+  prescriptionIds: number[];
+}
+
+export interface UpdatePrescriptionBlockDto {
+  kind?: PrescriptionBlockKind;
+  rest?: string;
+  prescriptionIds?: number[];
+}
+
 export interface CreatePrescriptionDto {
   groupId: number;
   exerciseVariationId: number;
@@ -156,4 +853,8 @@ export interface CreatePrescriptionDto {
   subReps: number | null;
   subWorkDuration: string | null;
   subRestDuration: string | null;
+  setScheme?: PrescriptionSetDto[];
 }
+
+// An empty setScheme makes the prescription uniform again
+export type UpdatePrescriptionDto = Partial<Omit<CreatePrescriptionDto, 'groupId' | 'planIntervalId'>>;